@@ -0,0 +1,286 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/dadrus/heimdall/internal/handler/management"
+	"github.com/dadrus/heimdall/internal/x/httpx"
+	"github.com/dadrus/heimdall/internal/x/stringx"
+)
+
+var errMalformedFlagValue = errors.New("malformed flag value")
+
+type explainRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Cookies map[string]string `json:"cookies,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	Stubs   []httpx.Stub      `json:"stubs,omitempty"`
+	Offline bool              `json:"offline"`
+}
+
+type explainResponse struct {
+	Rule *struct {
+		ID     string `json:"id"`
+		Source string `json:"source"`
+	} `json:"rule"`
+	Steps []struct {
+		ID           string `json:"id"`
+		Type         string `json:"type"`
+		ConditionMet bool   `json:"condition_met"`
+		Outcome      string `json:"outcome"`
+		Error        string `json:"error"`
+	} `json:"steps"`
+	Upstream struct {
+		URL     string              `json:"url"`
+		Headers map[string][]string `json:"headers"`
+		Cookies map[string]string   `json:"cookies"`
+	} `json:"upstream"`
+	Status   int    `json:"status"`
+	Location string `json:"location"`
+	Error    string `json:"error"`
+}
+
+// nolint: gochecknoglobals
+var explainCmd = &cobra.Command{
+	Use:   "explain [url]",
+	Short: "Explains how a Heimdall deployment would handle the given request",
+	Example: `heimdall explain -e https://heimdall.local:4457 -X POST -H "Authorization: Bearer foo" \
+  --stubs stubs.yaml https://my-service.local/api/foo`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		endpointURL, _ := cmd.Flags().GetString("endpoint")
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		exReq, err := createExplainRequest(cmd, args[0])
+		if err != nil {
+			cmd.PrintErrf("Failed to create request: %v", err)
+			os.Exit(-1)
+		}
+
+		rawReq, err := json.Marshal(exReq)
+		if err != nil {
+			cmd.PrintErrf("Failed to marshal request: %v", err)
+			os.Exit(-1)
+		}
+
+		resp, err := http.DefaultClient.Post(fmt.Sprintf("%s%s", endpointURL, management.EndpointExplain),
+			"application/json", bytes.NewReader(rawReq))
+		if err != nil {
+			cmd.PrintErrf("Failed to send request: %v", err)
+			os.Exit(-1)
+		}
+
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			cmd.PrintErrf("Unexpected HTTP status code : %s", resp.Status)
+			os.Exit(-1)
+		}
+
+		rawResp, err := io.ReadAll(resp.Body)
+		if err != nil {
+			cmd.PrintErrf("Failed to read response: %v", err)
+			os.Exit(-1)
+		}
+
+		switch outputFormat {
+		case "json":
+			cmd.Println(stringx.ToString(rawResp))
+		case "yaml":
+			var structuredResponse map[string]any
+			if err := json.Unmarshal(rawResp, &structuredResponse); err != nil {
+				cmd.PrintErrf("Failed to unmarshal response: %v", err)
+				os.Exit(-1)
+			}
+
+			rawYaml, err := yaml.Marshal(structuredResponse)
+			if err != nil {
+				cmd.PrintErrf("Failed to convert response to yaml: %v", err)
+				os.Exit(-1)
+			}
+			cmd.Println(stringx.ToString(rawYaml))
+		default:
+			var exResp explainResponse
+			if err := json.Unmarshal(rawResp, &exResp); err != nil {
+				cmd.PrintErrf("Failed to unmarshal response: %v", err)
+				os.Exit(-1)
+			}
+
+			cmd.Print(exResp.String())
+		}
+	},
+}
+
+func createExplainRequest(cmd *cobra.Command, targetURL string) (*explainRequest, error) {
+	method, _ := cmd.Flags().GetString("method")
+	headers, _ := cmd.Flags().GetStringArray("header")
+	cookies, _ := cmd.Flags().GetStringArray("cookie")
+	body, _ := cmd.Flags().GetString("data")
+	stubsFile, _ := cmd.Flags().GetString("stubs")
+	online, _ := cmd.Flags().GetBool("online")
+
+	exReq := &explainRequest{
+		Method:  method,
+		URL:     targetURL,
+		Headers: make(map[string]string, len(headers)),
+		Cookies: make(map[string]string, len(cookies)),
+		Body:    body,
+		Offline: !online,
+	}
+
+	for _, header := range headers {
+		name, value, found := strings.Cut(header, ":")
+		if !found {
+			return nil, fmt.Errorf("%w: header '%s' is not in 'Name: value' format", errMalformedFlagValue, header)
+		}
+
+		exReq.Headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	for _, cookie := range cookies {
+		name, value, found := strings.Cut(cookie, "=")
+		if !found {
+			return nil, fmt.Errorf("%w: cookie '%s' is not in 'name=value' format", errMalformedFlagValue, cookie)
+		}
+
+		exReq.Cookies[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	if len(stubsFile) != 0 {
+		raw, err := os.ReadFile(stubsFile)
+		if err != nil {
+			return nil, err
+		}
+
+		// yaml is a superset of json, so both formats are supported
+		if err = yaml.Unmarshal(raw, &exReq.Stubs); err != nil {
+			return nil, err
+		}
+	}
+
+	return exReq, nil
+}
+
+func (r explainResponse) String() string {
+	var buf strings.Builder
+
+	if r.Rule != nil {
+		fmt.Fprintf(&buf, "Rule:     %s (%s)\n", r.Rule.ID, r.Rule.Source)
+	} else {
+		buf.WriteString("Rule:     -\n")
+	}
+
+	fmt.Fprintf(&buf, "Status:   %d\n", r.Status)
+
+	if len(r.Location) != 0 {
+		fmt.Fprintf(&buf, "Location: %s\n", r.Location)
+	}
+
+	if len(r.Error) != 0 {
+		fmt.Fprintf(&buf, "Error:    %s\n", r.Error)
+	}
+
+	if len(r.Steps) != 0 {
+		buf.WriteString("Steps:\n")
+
+		for _, step := range r.Steps {
+			fmt.Fprintf(&buf, "  - %s %s: %s", step.Type, step.ID, step.Outcome)
+
+			if !step.ConditionMet {
+				buf.WriteString(" (execution condition not met)")
+			}
+
+			if len(step.Error) != 0 {
+				fmt.Fprintf(&buf, " (%s)", step.Error)
+			}
+
+			buf.WriteString("\n")
+		}
+	}
+
+	if len(r.Upstream.URL) != 0 {
+		fmt.Fprintf(&buf, "Upstream: %s\n", r.Upstream.URL)
+	}
+
+	if len(r.Upstream.Headers) != 0 {
+		buf.WriteString("Upstream Headers:\n")
+
+		for _, name := range sortedKeys(r.Upstream.Headers) {
+			fmt.Fprintf(&buf, "  %s: %s\n", name, strings.Join(r.Upstream.Headers[name], ","))
+		}
+	}
+
+	if len(r.Upstream.Cookies) != 0 {
+		buf.WriteString("Upstream Cookies:\n")
+
+		for _, name := range sortedKeys(r.Upstream.Cookies) {
+			fmt.Fprintf(&buf, "  %s=%s\n", name, r.Upstream.Cookies[name])
+		}
+	}
+
+	return buf.String()
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// nolint: gochecknoinits
+func init() {
+	RootCmd.AddCommand(explainCmd)
+
+	explainCmd.PersistentFlags().StringP("endpoint", "e", "",
+		"The base URL of Heimdall's management service.")
+	explainCmd.PersistentFlags().StringP("method", "X", http.MethodGet,
+		"The HTTP method of the request to explain.")
+	explainCmd.PersistentFlags().StringArrayP("header", "H", nil,
+		`A header of the request to explain in "Name: value" format. Can be specified multiple times.`)
+	explainCmd.PersistentFlags().StringArray("cookie", nil,
+		`A cookie of the request to explain in "name=value" format. Can be specified multiple times.`)
+	explainCmd.PersistentFlags().StringP("data", "d", "",
+		"The body of the request to explain.")
+	explainCmd.PersistentFlags().String("stubs", "",
+		`Path to a yaml or json file with stubbed responses for the remote calls done
+by the pipeline mechanisms.`)
+	explainCmd.PersistentFlags().Bool("online", false,
+		`If set, remote calls not matching any of the stubs are executed instead of failing.
+Requires remote calls to be allowed by heimdall's configuration.`)
+	explainCmd.PersistentFlags().StringP("output", "o", "text", `The format for the result output.
+Can be "json", "text", or "yaml".`)
+}
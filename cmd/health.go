@@ -32,14 +32,28 @@ import (
 
 // nolint: gochecknoglobals
 var healthCmd = &cobra.Command{
-	Use:     "health",
-	Short:   "Checks the health status of a Heimdall deployment",
-	Example: "heimdall health -e https://heimdall.local",
+	Use:   "health",
+	Short: "Checks the health status of a Heimdall deployment",
+	Example: `heimdall health -e https://heimdall.local
+heimdall health -e https://heimdall.local --probe ready`,
 	Run: func(cmd *cobra.Command, args []string) {
 		endpointURL, _ := cmd.Flags().GetString("endpoint")
 		outputFormat, _ := cmd.Flags().GetString("output")
+		probe, _ := cmd.Flags().GetString("probe")
 
-		resp, err := http.DefaultClient.Get(fmt.Sprintf("%s%s", endpointURL, management.EndpointHealth))
+		var endpoint string
+
+		switch probe {
+		case "live":
+			endpoint = management.EndpointHealth
+		case "ready":
+			endpoint = management.EndpointReadiness
+		default:
+			cmd.PrintErrf("Unsupported probe: %s", probe)
+			os.Exit(-1)
+		}
+
+		resp, err := http.DefaultClient.Get(fmt.Sprintf("%s%s", endpointURL, endpoint))
 		if err != nil {
 			cmd.PrintErrf("Failed to send request: %v", err)
 			os.Exit(-1)
@@ -47,7 +61,9 @@ var healthCmd = &cobra.Command{
 
 		defer resp.Body.Close()
 
-		if resp.StatusCode != http.StatusOK {
+		// a not ready heimdall instance responds with 503 and reports the not ready components
+		notReady := probe == "ready" && resp.StatusCode == http.StatusServiceUnavailable
+		if resp.StatusCode != http.StatusOK && !notReady {
 			cmd.PrintErrf("Unexpected HTTP status code : %s", resp.Status)
 			os.Exit(-1)
 		}
@@ -76,6 +92,17 @@ var healthCmd = &cobra.Command{
 			cmd.Println(stringx.ToString(rawYaml))
 		default:
 			cmd.Println(structuredResponse["status"])
+
+			components, _ := structuredResponse["components"].([]any)
+			for _, component := range components {
+				if entry, ok := component.(map[string]any); ok && entry["error"] != nil {
+					cmd.Printf("  %s: %s\n", entry["name"], entry["error"])
+				}
+			}
+		}
+
+		if notReady {
+			os.Exit(1)
 		}
 	},
 }
@@ -89,4 +116,7 @@ Note: The endpoint URL should point to a single Heimdall deployment.
 If the endpoint URL points to a Load Balancer, these commands will effective test the Load Balancer.`)
 	healthCmd.PersistentFlags().StringP("output", "o", "text", `The format for the result output.
 Can be "json", "text", or "yaml".`)
+	healthCmd.PersistentFlags().String("probe", "live", `The health status to query.
+Can be "live" to check whether heimdall is up and running, or "ready" to check whether it is ready to serve requests.
+A not ready deployment results in an exit code of 1.`)
 }
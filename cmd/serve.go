@@ -37,6 +37,9 @@ func init() {
 			"If not provided, the lookup sequence is:\n  1. $PWD\n  2. $HOME/.config\n  3. /etc/heimdall/")
 	serveCmd.PersistentFlags().String("env-config-prefix", "HEIMDALLCFG_",
 		"Prefix for the environment variables to consider for\nloading configuration from")
+	serveCmd.PersistentFlags().Bool("watch-config", false,
+		"If specified, the configuration is reloaded if the configuration file changes,\n"+
+			"or heimdall receives a SIGHUP signal")
 	serveCmd.AddCommand(serve.NewProxyCommand())
 	serveCmd.AddCommand(serve.NewDecisionCommand())
 }
@@ -55,6 +55,7 @@ func NewDecisionCommand() *cobra.Command {
 func createDecisionApp(cmd *cobra.Command) (*fx.App, error) {
 	configPath, _ := cmd.Flags().GetString("config")
 	envPrefix, _ := cmd.Flags().GetString("env-config-prefix")
+	watchConfig, _ := cmd.Flags().GetBool("watch-config")
 	useEnvoyExtAuth, _ := cmd.Flags().GetBool("envoy-grpc")
 
 	opts := []fx.Option{
@@ -62,6 +63,7 @@ func createDecisionApp(cmd *cobra.Command) (*fx.App, error) {
 		fx.Supply(
 			config.ConfigurationPath(configPath),
 			config.EnvVarPrefix(envPrefix),
+			config.WatchConfiguration(watchConfig),
 			config.DecisionMode),
 		internal.Module,
 	}
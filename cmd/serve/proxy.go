@@ -49,12 +49,14 @@ func NewProxyCommand() *cobra.Command {
 func createProxyApp(cmd *cobra.Command) (*fx.App, error) {
 	configPath, _ := cmd.Flags().GetString("config")
 	envPrefix, _ := cmd.Flags().GetString("env-config-prefix")
+	watchConfig, _ := cmd.Flags().GetBool("watch-config")
 
 	app := fx.New(
 		fx.NopLogger,
 		fx.Supply(
 			config.ConfigurationPath(configPath),
 			config.EnvVarPrefix(envPrefix),
+			config.WatchConfiguration(watchConfig),
 			config.ProxyMode),
 		internal.Module,
 		proxy.Module,
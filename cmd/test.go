@@ -0,0 +1,44 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/dadrus/heimdall/cmd/test"
+)
+
+// nolint: gochecknoglobals
+var testCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Commands for testing heimdall's rules",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Println(cmd.UsageString())
+	},
+}
+
+// nolint: gochecknoinits
+func init() {
+	RootCmd.AddCommand(testCmd)
+
+	testCmd.PersistentFlags().StringP("config", "c", "",
+		"Path to heimdall's configuration file.")
+	testCmd.PersistentFlags().String("env-config-prefix", "HEIMDALLCFG_",
+		"Prefix for the environment variables to consider for\nloading configuration from")
+
+	testCmd.AddCommand(test.NewTestRulesCommand())
+}
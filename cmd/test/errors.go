@@ -0,0 +1,10 @@
+package test
+
+import "errors"
+
+var (
+	ErrNoConfigFile    = errors.New("no config file provided")
+	ErrNoTestCasesFile = errors.New("no test cases file provided")
+	ErrInvalidTestCase = errors.New("invalid test case")
+	ErrTestsFailed     = errors.New("tests failed")
+)
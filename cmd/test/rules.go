@@ -0,0 +1,192 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/handler/middleware/http/errorhandler"
+	"github.com/dadrus/heimdall/internal/rules"
+	rulesconfig "github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms"
+	"github.com/dadrus/heimdall/internal/rules/upstream"
+	"github.com/dadrus/heimdall/internal/signer"
+	"github.com/dadrus/heimdall/internal/x"
+)
+
+// NewTestRulesCommand represents the "test rules" command.
+func NewTestRulesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rules [path to ruleset]",
+		Short:   "Runs declarative test cases against heimdall's ruleset",
+		Args:    cobra.ExactArgs(1),
+		Example: "heimdall test rules -c myconfig.yaml -t mytests.yaml myruleset.yaml",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runTests(cmd, args); err != nil {
+				cmd.PrintErrf("%v\n", err)
+
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.PersistentFlags().StringP("tests", "t", "",
+		"Path to the file with the test cases")
+	cmd.PersistentFlags().Bool("proxy-mode", false,
+		"If specified, the rule set is tested for the usage in proxy operation mode")
+
+	return cmd
+}
+
+func runTests(cmd *cobra.Command, args []string) error {
+	envPrefix, _ := cmd.Flags().GetString("env-config-prefix")
+	logger := zerolog.Nop()
+
+	configPath, _ := cmd.Flags().GetString("config")
+	if len(configPath) == 0 {
+		return ErrNoConfigFile
+	}
+
+	testsPath, _ := cmd.Flags().GetString("tests")
+	if len(testsPath) == 0 {
+		return ErrNoTestCasesFile
+	}
+
+	opMode := config.DecisionMode
+	if proxyMode, _ := cmd.Flags().GetBool("proxy-mode"); proxyMode {
+		opMode = config.ProxyMode
+	}
+
+	conf, err := config.NewConfiguration(
+		config.EnvVarPrefix(envPrefix),
+		config.ConfigurationPath(configPath),
+	)
+	if err != nil {
+		return err
+	}
+
+	suite, err := loadSuite(testsPath)
+	if err != nil {
+		return err
+	}
+
+	ruleSet, err := loadRuleSet(args[0])
+	if err != nil {
+		return err
+	}
+
+	mFactory, err := mechanisms.NewFactory(conf, logger)
+	if err != nil {
+		return err
+	}
+
+	defer mFactory.Close() //nolint:errcheck
+
+	upstreams, err := upstream.NewRegistry(logger, noop.NewMeterProvider(), upstream.WithTLSStores(conf.UpstreamTLS))
+	if err != nil {
+		return err
+	}
+
+	rFactory, err := rules.NewRuleFactory(mFactory, conf, opMode, upstreams, noop.NewMeterProvider(), logger)
+	if err != nil {
+		return err
+	}
+
+	exec, err := rules.NewStaticRuleExecutor(rFactory, logger, ruleSet)
+	if err != nil {
+		return err
+	}
+
+	// the issued JWTs are not verified by anyone. So there is no need to require
+	// the configured key material to be available when running the tests.
+	jwtSigner, err := signer.NewJWTSigner(
+		&config.Configuration{Signer: config.SignerConfig{Name: conf.Signer.Name}}, logger)
+	if err != nil {
+		return err
+	}
+
+	srvConf := x.IfThenElse(opMode == config.ProxyMode, conf.Serve.Proxy, conf.Serve.Decision)
+	runner := &runner{
+		exec:   exec,
+		signer: jwtSigner,
+		eh: errorhandler.New(
+			errorhandler.WithPreconditionErrorCode(srvConf.Respond.With.ArgumentError.Code),
+			errorhandler.WithAuthenticationErrorCode(srvConf.Respond.With.AuthenticationError.Code),
+			errorhandler.WithAuthorizationErrorCode(srvConf.Respond.With.AuthorizationError.Code),
+			errorhandler.WithCommunicationErrorCode(srvConf.Respond.With.CommunicationError.Code),
+			errorhandler.WithMethodErrorCode(srvConf.Respond.With.BadMethodError.Code),
+			errorhandler.WithNoRuleErrorCode(srvConf.Respond.With.NoRuleError.Code),
+			errorhandler.WithInternalServerErrorCode(srvConf.Respond.With.InternalError.Code),
+		),
+		acceptedCode: x.IfThenElse(srvConf.Respond.With.Accepted.Code != 0,
+			srvConf.Respond.With.Accepted.Code, http.StatusOK),
+		stubs: suite.Stubs,
+	}
+
+	failed := 0
+
+	for _, tc := range suite.Cases {
+		failures := runner.run(context.Background(), tc)
+		if len(failures) == 0 {
+			cmd.Printf("PASS: %s\n", tc.Name)
+
+			continue
+		}
+
+		failed++
+
+		cmd.Printf("FAIL: %s\n", tc.Name)
+
+		for _, failure := range failures {
+			cmd.Printf("    %s\n", failure)
+		}
+	}
+
+	cmd.Printf("\n%d passed, %d failed\n", len(suite.Cases)-failed, failed)
+
+	if failed != 0 {
+		return fmt.Errorf("%w: %d of %d test cases failed", ErrTestsFailed, failed, len(suite.Cases))
+	}
+
+	return nil
+}
+
+func loadRuleSet(fileName string) (*rulesconfig.RuleSet, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	ruleSet, err := rulesconfig.ParseRules("application/yaml", file, false)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleSet.Source = fmt.Sprintf("file_system:%s", fileName)
+
+	return ruleSet, nil
+}
@@ -0,0 +1,181 @@
+package test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func newTestRulesCommand(t *testing.T, confFile, testsFile string, out *bytes.Buffer) *cobra.Command {
+	t.Helper()
+
+	cmd := NewTestRulesCommand()
+	cmd.Flags().StringP("config", "c", "", "Path to heimdall's configuration file.")
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+
+	var flags []string
+
+	if len(confFile) != 0 {
+		flags = append(flags, "--config", confFile)
+	}
+
+	if len(testsFile) != 0 {
+		flags = append(flags, "--tests", testsFile)
+	}
+
+	require.NoError(t, cmd.ParseFlags(flags))
+
+	return cmd
+}
+
+func TestRunTests(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc        string
+		confFile  string
+		testsFile string
+		rulesFile string
+		expError  error
+		assert    func(t *testing.T, output string)
+	}{
+		{
+			uc:       "no config provided",
+			expError: ErrNoConfigFile,
+		},
+		{
+			uc:       "no test cases provided",
+			confFile: "test_data/config.yaml",
+			expError: ErrNoTestCasesFile,
+		},
+		{
+			uc:        "invalid config file",
+			confFile:  "doesnotexist.yaml",
+			testsFile: "test_data/passing-tests.yaml",
+			expError:  os.ErrNotExist,
+		},
+		{
+			uc:        "invalid test cases file",
+			confFile:  "test_data/config.yaml",
+			testsFile: "doesnotexist.yaml",
+			rulesFile: "test_data/ruleset.yaml",
+			expError:  os.ErrNotExist,
+		},
+		{
+			uc:        "invalid test case",
+			confFile:  "test_data/config.yaml",
+			testsFile: "test_data/invalid-tests.yaml",
+			rulesFile: "test_data/ruleset.yaml",
+			expError:  ErrInvalidTestCase,
+		},
+		{
+			uc:        "invalid rule set file",
+			confFile:  "test_data/config.yaml",
+			testsFile: "test_data/passing-tests.yaml",
+			rulesFile: "doesnotexist.yaml",
+			expError:  os.ErrNotExist,
+		},
+		{
+			uc:        "failing test cases",
+			confFile:  "test_data/config.yaml",
+			testsFile: "test_data/failing-tests.yaml",
+			rulesFile: "test_data/ruleset.yaml",
+			expError:  ErrTestsFailed,
+			assert: func(t *testing.T, output string) {
+				t.Helper()
+
+				assert.Contains(t, output, "FAIL: introspection endpoint is not stubbed")
+				assert.Contains(t, output, "expected decision 'allow', got 'deny'")
+				assert.Contains(t, output, "expected upstream header X-User-ID to be set")
+				assert.Contains(t, output, "no stub found for POST https://idp.local/introspect")
+				assert.Contains(t, output, "0 passed, 1 failed")
+			},
+		},
+		{
+			uc:        "passing test cases",
+			confFile:  "test_data/config.yaml",
+			testsFile: "test_data/passing-tests.yaml",
+			rulesFile: "test_data/ruleset.yaml",
+			assert: func(t *testing.T, output string) {
+				t.Helper()
+
+				assert.Contains(t, output, "PASS: public endpoints are accessible anonymously")
+				assert.Contains(t, output, "PASS: session based access")
+				assert.Contains(t, output, "PASS: access is denied if the authorization service says so")
+				assert.Contains(t, output, "PASS: active access tokens are accepted")
+				assert.Contains(t, output, "PASS: requests to unknown endpoints are handled by the default rule")
+				assert.Contains(t, output, "PASS: methods not allowed by the default rule are rejected")
+				assert.Contains(t, output, "6 passed, 0 failed")
+			},
+		},
+	} {
+		t.Run(tc.uc, func(t *testing.T) {
+			// GIVEN
+			buf := bytes.NewBuffer([]byte{})
+			cmd := newTestRulesCommand(t, tc.confFile, tc.testsFile, buf)
+
+			// WHEN
+			err := runTests(cmd, []string{tc.rulesFile})
+
+			// THEN
+			if tc.expError != nil {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tc.expError)
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tc.assert != nil {
+				tc.assert(t, buf.String())
+			}
+		})
+	}
+}
+
+func TestRunTestRulesCommand(t *testing.T) {
+	for _, tc := range []struct {
+		uc        string
+		testsFile string
+		expOutput string
+		expExit   bool
+	}{
+		{
+			uc:        "tests fail",
+			testsFile: "test_data/failing-tests.yaml",
+			expOutput: "1 of 1 test cases failed",
+			expExit:   true,
+		},
+		{
+			uc:        "tests pass",
+			testsFile: "test_data/passing-tests.yaml",
+			expOutput: "6 passed, 0 failed",
+		},
+	} {
+		t.Run(tc.uc, func(t *testing.T) {
+			// GIVEN
+			exit, err := testsupport.PatchOSExit(t, func(int) {})
+			require.NoError(t, err)
+
+			buf := bytes.NewBuffer([]byte{})
+			cmd := newTestRulesCommand(t, "test_data/config.yaml", tc.testsFile, buf)
+
+			// WHEN
+			cmd.Run(cmd, []string{"test_data/ruleset.yaml"})
+
+			// THEN
+			assert.Contains(t, buf.String(), tc.expOutput)
+			assert.Equal(t, tc.expExit, exit.Called)
+
+			if tc.expExit {
+				assert.Equal(t, 1, exit.Code)
+			}
+		})
+	}
+}
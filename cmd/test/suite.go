@@ -0,0 +1,255 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"gopkg.in/yaml.v3"
+
+	"github.com/dadrus/heimdall/internal/accesscontext"
+	"github.com/dadrus/heimdall/internal/handler/middleware/http/errorhandler"
+	"github.com/dadrus/heimdall/internal/handler/requestcontext"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/rule"
+	"github.com/dadrus/heimdall/internal/x/httpx"
+)
+
+const (
+	decisionAllow = "allow"
+	decisionDeny  = "deny"
+)
+
+type testSuite struct {
+	Stubs []httpx.Stub `yaml:"stubs"`
+	Cases []testCase   `yaml:"cases"`
+}
+
+type testCase struct {
+	Name    string       `yaml:"name"`
+	Request testRequest  `yaml:"request"`
+	Stubs   []httpx.Stub `yaml:"stubs"`
+	Expect  expectation  `yaml:"expect"`
+}
+
+type testRequest struct {
+	Method  string            `yaml:"method"`
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+	Cookies map[string]string `yaml:"cookies"`
+	Body    string            `yaml:"body"`
+}
+
+type expectation struct {
+	Rule            string            `yaml:"rule"`
+	Decision        string            `yaml:"decision"`
+	Status          int               `yaml:"status"`
+	UpstreamHeaders map[string]string `yaml:"upstream_headers"`
+	UpstreamCookies map[string]string `yaml:"upstream_cookies"`
+}
+
+func loadSuite(fileName string) (*testSuite, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	var suite testSuite
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+
+	if err = decoder.Decode(&suite); err != nil {
+		return nil, fmt.Errorf("failed to parse test cases file %s: %w", fileName, err)
+	}
+
+	for idx, tc := range suite.Cases {
+		if err = tc.validate(); err != nil {
+			return nil, fmt.Errorf("case #%d (%s): %w", idx+1, tc.Name, err)
+		}
+	}
+
+	return &suite, nil
+}
+
+func (tc testCase) validate() error {
+	if len(tc.Name) == 0 {
+		return fmt.Errorf("%w: name is required", ErrInvalidTestCase)
+	}
+
+	if reqURL, err := url.Parse(tc.Request.URL); err != nil || !reqURL.IsAbs() {
+		return fmt.Errorf("%w: request url '%s' is not absolute", ErrInvalidTestCase, tc.Request.URL)
+	}
+
+	switch tc.Expect.Decision {
+	case "", decisionAllow, decisionDeny:
+	default:
+		return fmt.Errorf("%w: decision must be either '%s' or '%s'",
+			ErrInvalidTestCase, decisionAllow, decisionDeny)
+	}
+
+	for name, pattern := range tc.Expect.UpstreamHeaders {
+		if _, err := glob.Compile(pattern); err != nil {
+			return fmt.Errorf("%w: bad pattern for upstream header %s: %w", ErrInvalidTestCase, name, err)
+		}
+	}
+
+	for name, pattern := range tc.Expect.UpstreamCookies {
+		if _, err := glob.Compile(pattern); err != nil {
+			return fmt.Errorf("%w: bad pattern for upstream cookie %s: %w", ErrInvalidTestCase, name, err)
+		}
+	}
+
+	return nil
+}
+
+type runner struct {
+	exec         rule.Executor
+	signer       heimdall.JWTSigner
+	eh           errorhandler.ErrorHandler
+	acceptedCode int
+	stubs        []httpx.Stub
+}
+
+// run executes the given test case and returns the deviations from the expected results. Remote
+// calls done by the mechanisms are answered by the stubs of the test case, followed by the stubs
+// of the test suite. Calls not matching any stub fail.
+func (r *runner) run(ctx context.Context, tc testCase) []string {
+	ctx = httpx.WithStubs(accesscontext.New(ctx), append(tc.Stubs, r.stubs...), true)
+
+	req, err := tc.Request.toHTTPRequest(ctx)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	rc := requestcontext.New(r.signer, req)
+
+	backend, err := r.exec.Execute(rc)
+	if backend != nil {
+		backend.Done(nil)
+	}
+
+	decision := decisionAllow
+	status := r.acceptedCode
+
+	if err != nil {
+		recorder := httptest.NewRecorder()
+		r.eh.HandleError(recorder, req, err)
+
+		decision = decisionDeny
+		status = recorder.Code
+	}
+
+	var failures []string
+
+	ruleID, _ := accesscontext.Rule(ctx)
+	if len(tc.Expect.Rule) != 0 && tc.Expect.Rule != ruleID {
+		failures = append(failures, fmt.Sprintf("expected rule '%s' to match, got '%s'", tc.Expect.Rule, ruleID))
+	}
+
+	if len(tc.Expect.Decision) != 0 && tc.Expect.Decision != decision {
+		failures = append(failures, fmt.Sprintf("expected decision '%s', got '%s'", tc.Expect.Decision, decision))
+	}
+
+	if tc.Expect.Status != 0 && tc.Expect.Status != status {
+		failures = append(failures, fmt.Sprintf("expected status %d, got %d", tc.Expect.Status, status))
+	}
+
+	for _, name := range sortedKeys(tc.Expect.UpstreamHeaders) {
+		values, present := rc.UpstreamHeaders()[http.CanonicalHeaderKey(name)]
+		if !present {
+			failures = append(failures, fmt.Sprintf("expected upstream header %s to be set", name))
+		} else if value := strings.Join(values, ","); !matches(tc.Expect.UpstreamHeaders[name], value) {
+			failures = append(failures, fmt.Sprintf("expected upstream header %s to match '%s', got '%s'",
+				name, tc.Expect.UpstreamHeaders[name], value))
+		}
+	}
+
+	for _, name := range sortedKeys(tc.Expect.UpstreamCookies) {
+		value, present := rc.UpstreamCookies()[name]
+		if !present {
+			failures = append(failures, fmt.Sprintf("expected upstream cookie %s to be set", name))
+		} else if !matches(tc.Expect.UpstreamCookies[name], value) {
+			failures = append(failures, fmt.Sprintf("expected upstream cookie %s to match '%s', got '%s'",
+				name, tc.Expect.UpstreamCookies[name], value))
+		}
+	}
+
+	if len(failures) != 0 && err != nil {
+		failures = append(failures, fmt.Sprintf("pipeline error: %s", err))
+	}
+
+	return failures
+}
+
+func (r testRequest) toHTTPRequest(ctx context.Context) (*http.Request, error) {
+	method := strings.ToUpper(r.Method)
+	if len(method) == 0 {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, r.URL, strings.NewReader(r.Body))
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL.Scheme == "https" {
+		// the scheme of the inbound request is derived from the used connection
+		req.TLS = &tls.ConnectionState{}
+	}
+
+	for name, value := range r.Headers {
+		if strings.EqualFold(name, "Host") {
+			req.Host = value
+		} else {
+			req.Header.Set(name, value)
+		}
+	}
+
+	for name, value := range r.Cookies {
+		req.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+
+	return req, nil
+}
+
+func matches(pattern, value string) bool {
+	// the patterns have been validated while loading the test cases
+	return glob.MustCompile(pattern).Match(value)
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -6,12 +6,15 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/metric/noop"
 
 	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/health"
 	"github.com/dadrus/heimdall/internal/rules"
 	"github.com/dadrus/heimdall/internal/rules/event"
 	"github.com/dadrus/heimdall/internal/rules/mechanisms"
 	"github.com/dadrus/heimdall/internal/rules/provider/filesystem"
+	"github.com/dadrus/heimdall/internal/rules/upstream"
 )
 
 // NewValidateRulesCommand represents the "validate rules" command.
@@ -69,7 +72,14 @@ func validateRuleSet(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	rFactory, err := rules.NewRuleFactory(mFactory, conf, opMode, logger)
+	defer mFactory.Close() //nolint:errcheck
+
+	upstreams, err := upstream.NewRegistry(logger, noop.NewMeterProvider(), upstream.WithTLSStores(conf.UpstreamTLS))
+	if err != nil {
+		return err
+	}
+
+	rFactory, err := rules.NewRuleFactory(mFactory, conf, opMode, upstreams, noop.NewMeterProvider(), logger)
 	if err != nil {
 		return err
 	}
@@ -78,7 +88,9 @@ func validateRuleSet(cmd *cobra.Command, args []string) error {
 
 	defer close(queue)
 
-	provider, err := filesystem.NewProvider(conf, rules.NewRuleSetProcessor(queue, rFactory, logger), logger)
+	provider, err := filesystem.NewProvider(
+		conf, rules.NewRuleSetProcessor(queue, rFactory, logger), health.NewRegistry(), logger,
+	)
 	if err != nil {
 		return err
 	}
@@ -23,8 +23,30 @@ import (
 type ctxKey struct{}
 
 type accessContext struct {
-	err     error
-	subject string
+	err        error
+	subject    string
+	ruleID     string
+	ruleSource string
+	request    *Request
+	mechanisms []MechanismResult
+}
+
+// Request holds the details of the request a decision has been made for. These
+// differ from the request received by heimdall, if e.g. the X-Forwarded-* headers
+// have been used to communicate them.
+type Request struct {
+	Method string
+	Scheme string
+	Host   string
+	Path   string
+}
+
+// MechanismResult holds the result of a pipeline mechanism execution.
+type MechanismResult struct {
+	ID      string
+	Type    string
+	Outcome string
+	Err     error
 }
 
 func New(ctx context.Context) context.Context {
@@ -58,3 +80,46 @@ func SetSubject(ctx context.Context, subject string) {
 		c.subject = subject
 	}
 }
+
+func Rule(ctx context.Context) (string, string) {
+	if c, ok := ctx.Value(ctxKey{}).(*accessContext); ok {
+		return c.ruleID, c.ruleSource
+	}
+
+	return "", ""
+}
+
+func SetRule(ctx context.Context, id, source string) {
+	if c, ok := ctx.Value(ctxKey{}).(*accessContext); ok {
+		c.ruleID = id
+		c.ruleSource = source
+	}
+}
+
+func ResolvedRequest(ctx context.Context) *Request {
+	if c, ok := ctx.Value(ctxKey{}).(*accessContext); ok {
+		return c.request
+	}
+
+	return nil
+}
+
+func SetResolvedRequest(ctx context.Context, req Request) {
+	if c, ok := ctx.Value(ctxKey{}).(*accessContext); ok {
+		c.request = &req
+	}
+}
+
+func Mechanisms(ctx context.Context) []MechanismResult {
+	if c, ok := ctx.Value(ctxKey{}).(*accessContext); ok {
+		return c.mechanisms
+	}
+
+	return nil
+}
+
+func AddMechanism(ctx context.Context, result MechanismResult) {
+	if c, ok := ctx.Value(ctxKey{}).(*accessContext); ok {
+		c.mechanisms = append(c.mechanisms, result)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+const defaultBufferSize = 1000
+
+// Auditor delivers audit events to the configured sinks.
+type Auditor interface {
+	// Audit queues the event for delivery. It never blocks. If the queue is full,
+	// the event is dropped.
+	Audit(event *Event)
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+type auditor struct {
+	logger   zerolog.Logger
+	redactor redactor
+	sinks    []Sink
+	events   chan *Event
+	dropped  atomic.Int64
+	done     chan struct{}
+	ctx      context.Context //nolint:containedctx
+	cancel   context.CancelFunc
+
+	mut     sync.RWMutex
+	stopped bool
+}
+
+func newAuditor(conf config.AuditConfig, logger zerolog.Logger) (*auditor, error) {
+	red, err := newRedactor(conf.Redact)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(conf.Sinks) == 0 {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration, "no audit sinks configured")
+	}
+
+	sinks := make([]Sink, len(conf.Sinks))
+
+	for idx, sinkConf := range conf.Sinks {
+		if sinks[idx], err = newSink(sinkConf); err != nil {
+			for _, sink := range sinks[:idx] {
+				sink.Close()
+			}
+
+			return nil, err
+		}
+	}
+
+	return &auditor{
+		logger:   logger,
+		redactor: red,
+		sinks:    sinks,
+		events:   make(chan *Event, x.IfThenElse(conf.BufferSize > 0, conf.BufferSize, defaultBufferSize)),
+		done:     make(chan struct{}),
+	}, nil
+}
+
+func (a *auditor) Audit(event *Event) {
+	a.mut.RLock()
+	defer a.mut.RUnlock()
+
+	if a.stopped {
+		return
+	}
+
+	select {
+	case a.events <- event:
+	default:
+		a.dropped.Add(1)
+	}
+}
+
+func (a *auditor) Start(_ context.Context) error {
+	a.logger.Info().Int("_sinks", len(a.sinks)).Msg("Starting audit event delivery")
+
+	a.ctx, a.cancel = context.WithCancel(a.logger.WithContext(context.Background()))
+
+	go a.deliver()
+
+	return nil
+}
+
+func (a *auditor) Stop(ctx context.Context) error {
+	a.logger.Info().Msg("Stopping audit event delivery")
+
+	a.mut.Lock()
+	a.stopped = true
+	close(a.events)
+	a.mut.Unlock()
+
+	select {
+	case <-a.done:
+	case <-ctx.Done():
+		a.logger.Warn().Msg("Audit events queue could not be drained in time. Remaining events dropped")
+
+		// abort in-flight writes and wait for the delivery to finish to not close
+		// the sinks while they are still written to
+		a.cancel()
+		<-a.done
+	}
+
+	a.cancel()
+
+	for _, sink := range a.sinks {
+		if err := sink.Close(); err != nil {
+			a.logger.Warn().Err(err).Msg("Failed to close audit sink")
+		}
+	}
+
+	return nil
+}
+
+func (a *auditor) deliver() {
+	defer close(a.done)
+
+	for event := range a.events {
+		if a.ctx.Err() != nil {
+			continue
+		}
+
+		if dropped := a.dropped.Swap(0); dropped != 0 {
+			a.logger.Warn().Int64("_count", dropped).Msg("Audit events queue was full. Events dropped")
+		}
+
+		data, err := a.serialize(event)
+		if err != nil {
+			a.logger.Error().Err(err).Msg("Failed to serialize audit event")
+
+			continue
+		}
+
+		for _, sink := range a.sinks {
+			if err = sink.Write(a.ctx, data); err != nil {
+				a.logger.Error().Err(err).Msg("Failed to write audit event")
+			}
+		}
+	}
+}
+
+func (a *auditor) serialize(event *Event) ([]byte, error) {
+	if len(a.redactor) == 0 {
+		return json.Marshal(event)
+	}
+
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	var value map[string]any
+	if err = json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+
+	a.redactor.redact(value)
+
+	return json.Marshal(value)
+}
+
+type noopAuditor struct{}
+
+func (noopAuditor) Audit(*Event)                  {}
+func (noopAuditor) Start(_ context.Context) error { return nil }
+func (noopAuditor) Stop(_ context.Context) error  { return nil }
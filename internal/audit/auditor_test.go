@@ -0,0 +1,296 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+)
+
+type recordingSink struct {
+	mut    sync.Mutex
+	events [][]byte
+	err    error
+	closed bool
+}
+
+func (s *recordingSink) Write(_ context.Context, event []byte) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	s.events = append(s.events, event)
+
+	return s.err
+}
+
+func (s *recordingSink) Close() error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	s.closed = true
+
+	return nil
+}
+
+type blockingSink struct {
+	writes             atomic.Int32
+	writing            atomic.Bool
+	closedWhileWriting atomic.Bool
+	closed             atomic.Bool
+}
+
+func (s *blockingSink) Write(ctx context.Context, _ []byte) error {
+	s.writes.Add(1)
+	s.writing.Store(true)
+	defer s.writing.Store(false)
+
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+func (s *blockingSink) Close() error {
+	s.closedWhileWriting.Store(s.writing.Load())
+	s.closed.Store(true)
+
+	return nil
+}
+
+func TestNewAuditor(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc     string
+		conf   config.AuditConfig
+		assert func(t *testing.T, err error, aud *auditor)
+	}{
+		{
+			uc:   "without sinks",
+			conf: config.AuditConfig{Enabled: true},
+			assert: func(t *testing.T, err error, _ *auditor) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "no audit sinks")
+			},
+		},
+		{
+			uc: "with invalid redaction rule",
+			conf: config.AuditConfig{
+				Enabled: true,
+				Redact:  []config.RedactionRule{{Field: "foo", Pattern: "(foo"}},
+				Sinks:   []config.AuditSinkConfig{{Type: "stdout"}},
+			},
+			assert: func(t *testing.T, err error, _ *auditor) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "redaction rule")
+			},
+		},
+		{
+			uc: "with unsupported sink type",
+			conf: config.AuditConfig{
+				Enabled: true,
+				Sinks:   []config.AuditSinkConfig{{Type: "stdout"}, {Type: "foo"}},
+			},
+			assert: func(t *testing.T, err error, _ *auditor) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "unsupported audit sink type 'foo'")
+			},
+		},
+		{
+			uc: "with default buffer size",
+			conf: config.AuditConfig{
+				Enabled: true,
+				Sinks:   []config.AuditSinkConfig{{Type: "stdout"}},
+			},
+			assert: func(t *testing.T, err error, aud *auditor) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Len(t, aud.sinks, 1)
+				assert.Equal(t, defaultBufferSize, cap(aud.events))
+			},
+		},
+		{
+			uc: "with all properties",
+			conf: config.AuditConfig{
+				Enabled:    true,
+				BufferSize: 10,
+				Redact:     []config.RedactionRule{{Field: "subject"}},
+				Sinks: []config.AuditSinkConfig{
+					{Type: "stdout"},
+					{Type: "file", Config: map[string]any{"path": filepath.Join(t.TempDir(), "audit.log")}},
+				},
+			},
+			assert: func(t *testing.T, err error, aud *auditor) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Len(t, aud.sinks, 2)
+				assert.Len(t, aud.redactor, 1)
+				assert.Equal(t, 10, cap(aud.events))
+
+				for _, sink := range aud.sinks {
+					require.NoError(t, sink.Close())
+				}
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// WHEN
+			aud, err := newAuditor(tc.conf, log.Logger)
+
+			// THEN
+			tc.assert(t, err, aud)
+		})
+	}
+}
+
+func TestAuditorDeliversEventsToAllSinks(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	red, err := newRedactor([]config.RedactionRule{{Field: "subject"}})
+	require.NoError(t, err)
+
+	failingSink := &recordingSink{err: errors.New("test error")}
+	sink := &recordingSink{}
+
+	aud := &auditor{
+		logger:   log.Logger,
+		redactor: red,
+		sinks:    []Sink{failingSink, sink},
+		events:   make(chan *Event, 10),
+		done:     make(chan struct{}),
+	}
+
+	require.NoError(t, aud.Start(context.Background()))
+
+	// WHEN
+	aud.Audit(&Event{Subject: "alice", Decision: DecisionAllow, Request: Request{Method: "GET", Path: "/foo"}})
+	aud.Audit(&Event{Subject: "bob", Decision: DecisionDeny, Reason: "test", StatusCode: 403})
+
+	require.NoError(t, aud.Stop(context.Background()))
+
+	// events emitted after stop are ignored
+	aud.Audit(&Event{Subject: "eve"})
+
+	// THEN
+	assert.True(t, sink.closed)
+	assert.True(t, failingSink.closed)
+	assert.Len(t, failingSink.events, 2)
+	require.Len(t, sink.events, 2)
+
+	var event map[string]any
+
+	require.NoError(t, json.Unmarshal(sink.events[0], &event))
+	assert.Equal(t, defaultReplacement, event["subject"])
+	assert.Equal(t, DecisionAllow, event["decision"])
+	assert.Equal(t, map[string]any{"method": "GET", "host": "", "path": "/foo"}, event["request"])
+
+	require.NoError(t, json.Unmarshal(sink.events[1], &event))
+	assert.Equal(t, defaultReplacement, event["subject"])
+	assert.Equal(t, DecisionDeny, event["decision"])
+	assert.Equal(t, "test", event["reason"])
+	assert.InDelta(t, 403, event["status_code"], 0)
+}
+
+func TestAuditorDropsEventsIfQueueIsFull(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	sink := &recordingSink{}
+
+	aud := &auditor{
+		logger: log.Logger,
+		sinks:  []Sink{sink},
+		events: make(chan *Event, 1),
+		done:   make(chan struct{}),
+	}
+
+	// WHEN
+	aud.Audit(&Event{Subject: "alice"})
+	aud.Audit(&Event{Subject: "bob"})
+	aud.Audit(&Event{Subject: "eve"})
+
+	// THEN
+	assert.Equal(t, int64(2), aud.dropped.Load())
+
+	require.NoError(t, aud.Start(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, aud.Stop(ctx))
+
+	require.Len(t, sink.events, 1)
+	assert.Contains(t, string(sink.events[0]), "alice")
+	assert.Equal(t, int64(0), aud.dropped.Load())
+}
+
+func TestAuditorStopDoesNotCloseSinksWhileWriting(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	sink := &blockingSink{}
+
+	aud := &auditor{
+		logger: log.Logger,
+		sinks:  []Sink{sink},
+		events: make(chan *Event, 10),
+		done:   make(chan struct{}),
+	}
+
+	aud.Audit(&Event{Subject: "alice"})
+	aud.Audit(&Event{Subject: "bob"})
+	aud.Audit(&Event{Subject: "eve"})
+
+	require.NoError(t, aud.Start(context.Background()))
+	require.Eventually(t, sink.writing.Load, time.Second, 5*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// WHEN
+	err := aud.Stop(ctx)
+
+	// THEN
+	require.NoError(t, err)
+	assert.True(t, sink.closed.Load())
+	assert.False(t, sink.closedWhileWriting.Load())
+	assert.Equal(t, int32(1), sink.writes.Load())
+}
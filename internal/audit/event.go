@@ -0,0 +1,107 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/dadrus/heimdall/internal/accesscontext"
+	"github.com/dadrus/heimdall/internal/x/opentelemetry/tracecontext"
+)
+
+const (
+	DecisionAllow = "allow"
+	DecisionDeny  = "deny"
+)
+
+// Event is the audit record of a single authorization decision.
+type Event struct {
+	Timestamp  time.Time   `json:"timestamp"`
+	TraceID    string      `json:"trace_id,omitempty"`
+	Request    Request     `json:"request"`
+	Rule       *Rule       `json:"rule,omitempty"`
+	Mechanisms []Mechanism `json:"mechanisms,omitempty"`
+	Subject    string      `json:"subject,omitempty"`
+	Decision   string      `json:"decision"`
+	Reason     string      `json:"reason,omitempty"`
+	StatusCode int         `json:"status_code,omitempty"`
+}
+
+type Request struct {
+	Method   string `json:"method"`
+	Scheme   string `json:"scheme,omitempty"`
+	Host     string `json:"host"`
+	Path     string `json:"path"`
+	ClientIP string `json:"client_ip,omitempty"`
+}
+
+type Rule struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+}
+
+type Mechanism struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Outcome string `json:"outcome"`
+	Error   string `json:"error,omitempty"`
+}
+
+// NewEvent creates an event from the information available in the access context
+// of the given ctx. The request details and the status code are to be set by the caller.
+// The method, scheme, host and path of the request are however taken from the access
+// context, if available, as these reflect the request the decision has been made for.
+func NewEvent(ctx context.Context, req Request) *Event {
+	if resolved := accesscontext.ResolvedRequest(ctx); resolved != nil {
+		req.Method = resolved.Method
+		req.Scheme = resolved.Scheme
+		req.Host = resolved.Host
+		req.Path = resolved.Path
+	}
+
+	event := &Event{
+		Timestamp: time.Now().UTC(),
+		Request:   req,
+		Subject:   accesscontext.Subject(ctx),
+		Decision:  DecisionAllow,
+	}
+
+	if traceCtx := tracecontext.Extract(ctx); traceCtx != nil {
+		event.TraceID = traceCtx.TraceID
+	}
+
+	if id, source := accesscontext.Rule(ctx); len(id) != 0 {
+		event.Rule = &Rule{ID: id, Source: source}
+	}
+
+	for _, result := range accesscontext.Mechanisms(ctx) {
+		mechanism := Mechanism{ID: result.ID, Type: result.Type, Outcome: result.Outcome}
+		if result.Err != nil {
+			mechanism.Error = result.Err.Error()
+		}
+
+		event.Mechanisms = append(event.Mechanisms, mechanism)
+	}
+
+	if err := accesscontext.Error(ctx); err != nil {
+		event.Decision = DecisionDeny
+		event.Reason = err.Error()
+	}
+
+	return event
+}
@@ -0,0 +1,130 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/accesscontext"
+	"github.com/dadrus/heimdall/internal/x"
+)
+
+func TestNewEvent(t *testing.T) {
+	t.Parallel()
+
+	req := Request{Method: "GET", Scheme: "https", Host: "foo.bar", Path: "/baz", ClientIP: "127.0.0.1"}
+
+	for _, tc := range []struct {
+		uc        string
+		configure func(t *testing.T, ctx context.Context)
+		request   Request
+		assert    func(t *testing.T, event *Event)
+	}{
+		{
+			uc:        "without any information in the access context",
+			configure: func(t *testing.T, _ context.Context) { t.Helper() },
+			assert: func(t *testing.T, event *Event) {
+				t.Helper()
+
+				assert.Equal(t, DecisionAllow, event.Decision)
+				assert.Nil(t, event.Rule)
+				assert.Empty(t, event.Mechanisms)
+				assert.Empty(t, event.Subject)
+				assert.Empty(t, event.Reason)
+			},
+		},
+		{
+			uc: "for an allowed request",
+			configure: func(t *testing.T, ctx context.Context) {
+				t.Helper()
+
+				accesscontext.SetRule(ctx, "rule-1", "file_system:/rules.yaml")
+				accesscontext.AddMechanism(ctx, accesscontext.MechanismResult{
+					ID: "jwt", Type: "jwt", Outcome: "fallback", Err: errors.New("no token"),
+				})
+				accesscontext.AddMechanism(ctx, accesscontext.MechanismResult{
+					ID: "anon", Type: "anonymous", Outcome: "success",
+				})
+				accesscontext.SetSubject(ctx, "alice")
+			},
+			assert: func(t *testing.T, event *Event) {
+				t.Helper()
+
+				assert.Equal(t, DecisionAllow, event.Decision)
+				assert.Equal(t, &Rule{ID: "rule-1", Source: "file_system:/rules.yaml"}, event.Rule)
+				assert.Equal(t, []Mechanism{
+					{ID: "jwt", Type: "jwt", Outcome: "fallback", Error: "no token"},
+					{ID: "anon", Type: "anonymous", Outcome: "success"},
+				}, event.Mechanisms)
+				assert.Equal(t, "alice", event.Subject)
+				assert.Empty(t, event.Reason)
+			},
+		},
+		{
+			uc: "for a denied request",
+			configure: func(t *testing.T, ctx context.Context) {
+				t.Helper()
+
+				accesscontext.SetRule(ctx, "rule-1", "file_system:/rules.yaml")
+				accesscontext.SetError(ctx, errors.New("test error"))
+			},
+			assert: func(t *testing.T, event *Event) {
+				t.Helper()
+
+				assert.Equal(t, DecisionDeny, event.Decision)
+				assert.Equal(t, "test error", event.Reason)
+			},
+		},
+		{
+			uc: "with resolved request",
+			configure: func(t *testing.T, ctx context.Context) {
+				t.Helper()
+
+				accesscontext.SetResolvedRequest(ctx, accesscontext.Request{
+					Method: "POST", Scheme: "http", Host: "bar.foo", Path: "/foo",
+				})
+			},
+			request: Request{Method: "POST", Scheme: "http", Host: "bar.foo", Path: "/foo", ClientIP: "127.0.0.1"},
+			assert: func(t *testing.T, event *Event) {
+				t.Helper()
+
+				assert.Equal(t, DecisionAllow, event.Decision)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			ctx := accesscontext.New(context.Background())
+
+			tc.configure(t, ctx)
+
+			// WHEN
+			event := NewEvent(ctx, req)
+
+			// THEN
+			require.NotNil(t, event)
+			assert.Equal(t, x.IfThenElse(tc.request != Request{}, tc.request, req), event.Request)
+			assert.False(t, event.Timestamp.IsZero())
+			tc.assert(t, event)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/inhies/go-bytesize"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+const (
+	defaultMaxFileSize    = 100 * bytesize.MB
+	defaultMaxFileBackups = 5
+	filePermissions       = 0o600
+)
+
+// fileSink writes events to a file, which is rotated as soon as its size would exceed
+// the configured limit. Rotated files are suffixed by a number, with the most recent one
+// having the suffix .1.
+type fileSink struct {
+	mut        sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func newFileSink(rawConfig map[string]any) (*fileSink, error) {
+	type Config struct {
+		Path       string            `mapstructure:"path"        validate:"required"`
+		MaxSize    bytesize.ByteSize `mapstructure:"max_size"`
+		MaxBackups *int              `mapstructure:"max_backups" validate:"omitempty,gte=0"`
+	}
+
+	var conf Config
+	if err := decodeConfig("file", rawConfig, &conf); err != nil {
+		return nil, err
+	}
+
+	sink := &fileSink{
+		path:       conf.Path,
+		maxSize:    int64(x.IfThenElse(conf.MaxSize != 0, conf.MaxSize, defaultMaxFileSize)),
+		maxBackups: defaultMaxFileBackups,
+	}
+
+	if conf.MaxBackups != nil {
+		sink.maxBackups = *conf.MaxBackups
+	}
+
+	if err := sink.open(); err != nil {
+		return nil, err
+	}
+
+	return sink, nil
+}
+
+func (s *fileSink) Write(_ context.Context, event []byte) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	var rotationErr error
+
+	data := append(event, '\n')
+
+	// if rotation fails, the event is still written to the current file
+	if s.size != 0 && s.size+int64(len(data)) > s.maxSize {
+		rotationErr = s.rotate()
+	}
+
+	written, err := s.file.Write(data)
+	s.size += int64(written)
+
+	if err != nil {
+		return err
+	}
+
+	return rotationErr
+}
+
+func (s *fileSink) Close() error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	return s.file.Close()
+}
+
+func (s *fileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePermissions)
+	if err != nil {
+		return errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed to open audit log file %s", s.path).CausedBy(err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+
+		return errorchain.NewWithMessagef(heimdall.ErrInternal,
+			"failed to stat audit log file %s", s.path).CausedBy(err)
+	}
+
+	s.file = file
+	s.size = info.Size()
+
+	return nil
+}
+
+// rotate moves the current file to the backups and opens a new one. The file is reopened
+// even if moving it failed, so that further events are not lost.
+func (s *fileSink) rotate() error {
+	err := s.file.Close()
+	if err == nil {
+		err = s.moveToBackups()
+	}
+
+	if openErr := s.open(); openErr != nil {
+		return openErr
+	}
+
+	if err != nil {
+		return errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed to rotate audit log file").CausedBy(err)
+	}
+
+	return nil
+}
+
+func (s *fileSink) moveToBackups() error {
+	if s.maxBackups == 0 {
+		return os.Remove(s.path)
+	}
+
+	// removing the oldest backup is allowed to fail, as it might not exist
+	_ = os.Remove(s.backupName(s.maxBackups))
+
+	for idx := s.maxBackups - 1; idx > 0; idx-- {
+		_ = os.Rename(s.backupName(idx), s.backupName(idx+1))
+	}
+
+	return os.Rename(s.path, s.backupName(1))
+}
+
+func (s *fileSink) backupName(idx int) string {
+	return fmt.Sprintf("%s.%d", s.path, idx)
+}
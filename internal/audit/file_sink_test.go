@@ -0,0 +1,212 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+)
+
+func TestNewFileSink(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	for _, tc := range []struct {
+		uc     string
+		config map[string]any
+		assert func(t *testing.T, err error, sink *fileSink)
+	}{
+		{
+			uc:     "without path",
+			config: map[string]any{"max_backups": 2},
+			assert: func(t *testing.T, err error, _ *fileSink) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'path' is a required field")
+			},
+		},
+		{
+			uc:     "with unsupported property",
+			config: map[string]any{"path": filepath.Join(dir, "foo.log"), "foo": "bar"},
+			assert: func(t *testing.T, err error, _ *fileSink) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed decoding")
+			},
+		},
+		{
+			uc:     "with not existing directory",
+			config: map[string]any{"path": filepath.Join(dir, "foo", "bar.log")},
+			assert: func(t *testing.T, err error, _ *fileSink) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed to open")
+			},
+		},
+		{
+			uc:     "with defaults",
+			config: map[string]any{"path": filepath.Join(dir, "defaults.log")},
+			assert: func(t *testing.T, err error, sink *fileSink) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, int64(defaultMaxFileSize), sink.maxSize)
+				assert.Equal(t, defaultMaxFileBackups, sink.maxBackups)
+				require.NoError(t, sink.Close())
+			},
+		},
+		{
+			uc:     "with disabled backups",
+			config: map[string]any{"path": filepath.Join(dir, "no-backups.log"), "max_backups": 0},
+			assert: func(t *testing.T, err error, sink *fileSink) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, 0, sink.maxBackups)
+				require.NoError(t, sink.Close())
+			},
+		},
+		{
+			uc:     "with negative backups",
+			config: map[string]any{"path": filepath.Join(dir, "negative.log"), "max_backups": -1},
+			assert: func(t *testing.T, err error, _ *fileSink) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+			},
+		},
+		{
+			uc: "with all properties",
+			config: map[string]any{
+				"path":        filepath.Join(dir, "all.log"),
+				"max_size":    "1KB",
+				"max_backups": 2,
+			},
+			assert: func(t *testing.T, err error, sink *fileSink) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, int64(1024), sink.maxSize)
+				assert.Equal(t, 2, sink.maxBackups)
+				require.NoError(t, sink.Close())
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// WHEN
+			sink, err := newFileSink(tc.config)
+
+			// THEN
+			tc.assert(t, err, sink)
+		})
+	}
+}
+
+func TestFileSinkRotation(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	sink, err := newFileSink(map[string]any{"path": path, "max_size": "10B", "max_backups": 2})
+	require.NoError(t, err)
+
+	// WHEN
+	for _, event := range []string{"event-1", "event-2", "event-3", "event-4"} {
+		require.NoError(t, sink.Write(context.Background(), []byte(event)))
+	}
+
+	require.NoError(t, sink.Close())
+
+	// THEN
+	for name, expected := range map[string]string{
+		path:        "event-4\n",
+		path + ".1": "event-3\n",
+		path + ".2": "event-2\n",
+	} {
+		data, err := os.ReadFile(name)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(data))
+	}
+
+	assert.NoFileExists(t, path+".3")
+}
+
+func TestFileSinkRotationWithoutBackups(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	sink, err := newFileSink(map[string]any{"path": path, "max_size": "10B", "max_backups": 0})
+	require.NoError(t, err)
+
+	// WHEN
+	for _, event := range []string{"event-1", "event-2"} {
+		require.NoError(t, sink.Write(context.Background(), []byte(event)))
+	}
+
+	require.NoError(t, sink.Close())
+
+	// THEN
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "event-2\n", string(data))
+	assert.NoFileExists(t, path+".1")
+}
+
+func TestFileSinkFailingRotation(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	// a not empty directory can neither be removed nor replaced by the file to rotate
+	require.NoError(t, os.MkdirAll(filepath.Join(path+".1", "foo"), 0o700))
+
+	sink, err := newFileSink(map[string]any{"path": path, "max_size": "10B", "max_backups": 1})
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Write(context.Background(), []byte("event-1")))
+
+	// WHEN
+	err = sink.Write(context.Background(), []byte("event-2"))
+
+	// THEN
+	require.Error(t, err)
+	require.ErrorIs(t, err, heimdall.ErrInternal)
+	require.NoError(t, sink.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "event-1\nevent-2\n", string(data))
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/fx"
+
+	"github.com/dadrus/heimdall/internal/config"
+)
+
+//nolint:gochecknoglobals
+var Module = fx.Provide(
+	fx.Annotate(
+		newAuditorFromConfig,
+		fx.OnStart(func(ctx context.Context, a Auditor) error { return a.Start(ctx) }),
+		fx.OnStop(func(ctx context.Context, a Auditor) error { return a.Stop(ctx) }),
+	),
+)
+
+func newAuditorFromConfig(conf *config.Configuration, logger zerolog.Logger) (Auditor, error) {
+	if !conf.Audit.Enabled {
+		logger.Info().Msg("Audit log is disabled")
+
+		return noopAuditor{}, nil
+	}
+
+	logger.Info().Msg("Audit log is enabled")
+
+	auditor, err := newAuditor(conf.Audit, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return auditor, nil
+}
+
+// NewNoopAuditor returns an Auditor, which drops all events.
+func NewNoopAuditor() Auditor { return noopAuditor{} }
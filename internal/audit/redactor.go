@@ -0,0 +1,102 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+const defaultReplacement = "[REDACTED]"
+
+type redactionRule struct {
+	path        []string
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+type redactor []redactionRule
+
+func newRedactor(rules []config.RedactionRule) (redactor, error) {
+	red := make(redactor, len(rules))
+
+	for idx, rule := range rules {
+		if len(rule.Field) == 0 {
+			return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+				"no field specified in redaction rule #%d", idx)
+		}
+
+		red[idx] = redactionRule{
+			path:        strings.Split(rule.Field, "."),
+			replacement: x.IfThenElse(len(rule.Replacement) != 0, rule.Replacement, defaultReplacement),
+		}
+
+		if len(rule.Pattern) != 0 {
+			pattern, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+					"failed to compile pattern of redaction rule for field '%s'", rule.Field).CausedBy(err)
+			}
+
+			red[idx].pattern = pattern
+		}
+	}
+
+	return red, nil
+}
+
+// redact applies all rules to the given value, which is the generic representation of an event.
+func (r redactor) redact(value map[string]any) {
+	for _, rule := range r {
+		rule.apply(value, rule.path)
+	}
+}
+
+func (r redactionRule) apply(value any, path []string) {
+	switch typed := value.(type) {
+	case []any:
+		for _, entry := range typed {
+			r.apply(entry, path)
+		}
+	case map[string]any:
+		entry, ok := typed[path[0]]
+		if !ok {
+			return
+		}
+
+		if len(path) > 1 {
+			r.apply(entry, path[1:])
+
+			return
+		}
+
+		typed[path[0]] = r.replace(entry)
+	}
+}
+
+func (r redactionRule) replace(value any) any {
+	if str, ok := value.(string); ok && r.pattern != nil {
+		return r.pattern.ReplaceAllString(str, r.replacement)
+	}
+
+	return r.replacement
+}
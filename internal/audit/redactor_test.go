@@ -0,0 +1,150 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+)
+
+func TestNewRedactor(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc     string
+		rules  []config.RedactionRule
+		assert func(t *testing.T, err error, red redactor)
+	}{
+		{
+			uc:    "without field",
+			rules: []config.RedactionRule{{Pattern: "foo"}},
+			assert: func(t *testing.T, err error, _ redactor) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "no field specified")
+			},
+		},
+		{
+			uc:    "with invalid pattern",
+			rules: []config.RedactionRule{{Field: "foo", Pattern: "(foo"}},
+			assert: func(t *testing.T, err error, _ redactor) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed to compile pattern")
+			},
+		},
+		{
+			uc: "with valid rules",
+			rules: []config.RedactionRule{
+				{Field: "foo.bar"},
+				{Field: "baz", Pattern: "[0-9]+", Replacement: "*"},
+			},
+			assert: func(t *testing.T, err error, red redactor) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.Len(t, red, 2)
+				assert.Equal(t, []string{"foo", "bar"}, red[0].path)
+				assert.Nil(t, red[0].pattern)
+				assert.Equal(t, defaultReplacement, red[0].replacement)
+				assert.Equal(t, []string{"baz"}, red[1].path)
+				assert.NotNil(t, red[1].pattern)
+				assert.Equal(t, "*", red[1].replacement)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// WHEN
+			red, err := newRedactor(tc.rules)
+
+			// THEN
+			tc.assert(t, err, red)
+		})
+	}
+}
+
+func TestRedactorRedact(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc       string
+		rules    []config.RedactionRule
+		value    map[string]any
+		expected map[string]any
+	}{
+		{
+			uc:       "not existing field",
+			rules:    []config.RedactionRule{{Field: "foo.bar"}},
+			value:    map[string]any{"foo": map[string]any{"baz": "1"}},
+			expected: map[string]any{"foo": map[string]any{"baz": "1"}},
+		},
+		{
+			uc:       "path through a scalar value",
+			rules:    []config.RedactionRule{{Field: "foo.bar"}},
+			value:    map[string]any{"foo": "bar"},
+			expected: map[string]any{"foo": "bar"},
+		},
+		{
+			uc:       "whole value of a nested field",
+			rules:    []config.RedactionRule{{Field: "foo.bar"}},
+			value:    map[string]any{"foo": map[string]any{"bar": map[string]any{"a": 1}, "baz": "1"}},
+			expected: map[string]any{"foo": map[string]any{"bar": defaultReplacement, "baz": "1"}},
+		},
+		{
+			uc:       "part of a value matching the pattern",
+			rules:    []config.RedactionRule{{Field: "path", Pattern: "/users/[^/]+", Replacement: "/users/***"}},
+			value:    map[string]any{"path": "/users/alice/profile"},
+			expected: map[string]any{"path": "/users/***/profile"},
+		},
+		{
+			uc:       "value in array elements",
+			rules:    []config.RedactionRule{{Field: "mechanisms.error"}},
+			value:    map[string]any{"mechanisms": []any{map[string]any{"id": "a", "error": "x"}, map[string]any{"id": "b"}}},
+			expected: map[string]any{"mechanisms": []any{map[string]any{"id": "a", "error": defaultReplacement}, map[string]any{"id": "b"}}},
+		},
+		{
+			uc: "multiple rules",
+			rules: []config.RedactionRule{
+				{Field: "subject", Replacement: "-"},
+				{Field: "request.client_ip", Pattern: "[0-9]+$", Replacement: "0"},
+			},
+			value:    map[string]any{"subject": "alice", "request": map[string]any{"client_ip": "10.1.2.3"}},
+			expected: map[string]any{"subject": "-", "request": map[string]any{"client_ip": "10.1.2.0"}},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			red, err := newRedactor(tc.rules)
+			require.NoError(t, err)
+
+			// WHEN
+			red.redact(tc.value)
+
+			// THEN
+			assert.Equal(t, tc.expected, tc.value)
+		})
+	}
+}
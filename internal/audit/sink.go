@@ -0,0 +1,83 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"os"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/endpoint"
+	"github.com/dadrus/heimdall/internal/rules/endpoint/authstrategy"
+	"github.com/dadrus/heimdall/internal/validation"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+// Sink receives serialized audit events.
+type Sink interface {
+	Write(ctx context.Context, event []byte) error
+	Close() error
+}
+
+func newSink(conf config.AuditSinkConfig) (Sink, error) {
+	switch conf.Type {
+	case "stdout":
+		return newWriterSink(os.Stdout), nil
+	case "file":
+		return newFileSink(conf.Config)
+	case "webhook":
+		return newWebhookSink(conf.Config)
+	case "syslog":
+		return newSyslogSink(conf.Config)
+	default:
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"unsupported audit sink type '%s'", conf.Type)
+	}
+}
+
+func decodeConfig(sinkType string, input, output any) error {
+	dec, err := mapstructure.NewDecoder(
+		&mapstructure.DecoderConfig{
+			DecodeHook: mapstructure.ComposeDecodeHookFunc(
+				authstrategy.DecodeAuthenticationStrategyHookFunc(),
+				endpoint.DecodeEndpointHookFunc(),
+				mapstructure.StringToTimeDurationHookFunc(),
+				config.StringToByteSizeHookFunc(),
+			),
+			Result:      output,
+			ErrorUnused: true,
+		})
+	if err != nil {
+		return errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding '%s' audit sink config", sinkType).CausedBy(err)
+	}
+
+	if err = dec.Decode(input); err != nil {
+		return errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding '%s' audit sink config", sinkType).CausedBy(err)
+	}
+
+	if err = validation.ValidateStruct(output); err != nil {
+		return errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed validating '%s' audit sink config", sinkType).CausedBy(err)
+	}
+
+	return nil
+}
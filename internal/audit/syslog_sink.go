@@ -0,0 +1,61 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !windows && !plan9
+
+package audit
+
+import (
+	"context"
+	"log/syslog"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/stringx"
+)
+
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+func newSyslogSink(rawConfig map[string]any) (*syslogSink, error) {
+	type Config struct {
+		Network string `mapstructure:"network" validate:"omitempty,oneof=tcp udp unix unixgram"`
+		Address string `mapstructure:"address" validate:"required_with=Network"`
+		Tag     string `mapstructure:"tag"`
+	}
+
+	var conf Config
+	if err := decodeConfig("syslog", rawConfig, &conf); err != nil {
+		return nil, err
+	}
+
+	writer, err := syslog.Dial(conf.Network, conf.Address, syslog.LOG_INFO|syslog.LOG_AUTH,
+		x.IfThenElse(len(conf.Tag) != 0, conf.Tag, "heimdall"))
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"failed to connect to syslog").CausedBy(err)
+	}
+
+	return &syslogSink{w: writer}, nil
+}
+
+func (s *syslogSink) Write(_ context.Context, event []byte) error {
+	return s.w.Info(stringx.ToString(event))
+}
+
+func (s *syslogSink) Close() error { return s.w.Close() }
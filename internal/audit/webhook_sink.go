@@ -0,0 +1,62 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"github.com/dadrus/heimdall/internal/rules/endpoint"
+)
+
+type webhookSink struct {
+	e endpoint.Endpoint
+}
+
+func newWebhookSink(rawConfig map[string]any) (*webhookSink, error) {
+	type Config struct {
+		Endpoint endpoint.Endpoint `mapstructure:"endpoint" validate:"required"`
+	}
+
+	var conf Config
+	if err := decodeConfig("webhook", rawConfig, &conf); err != nil {
+		return nil, err
+	}
+
+	if conf.Endpoint.Headers == nil {
+		conf.Endpoint.Headers = make(map[string]string)
+	}
+
+	if _, ok := conf.Endpoint.Headers["Content-Type"]; !ok {
+		conf.Endpoint.Headers["Content-Type"] = "application/json"
+	}
+
+	if len(conf.Endpoint.Method) == 0 {
+		conf.Endpoint.Method = http.MethodPost
+	}
+
+	return &webhookSink{e: conf.Endpoint}, nil
+}
+
+func (s *webhookSink) Write(ctx context.Context, event []byte) error {
+	_, err := s.e.SendRequest(ctx, bytes.NewReader(event), nil)
+
+	return err
+}
+
+func (s *webhookSink) Close() error { return nil }
@@ -0,0 +1,101 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+)
+
+func TestWebhookSinkWrite(t *testing.T) {
+	t.Parallel()
+
+	var (
+		method      string
+		contentType string
+		header      string
+		body        []byte
+		statusCode  int
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		method = req.Method
+		contentType = req.Header.Get("Content-Type")
+		header = req.Header.Get("X-Source")
+		body, _ = io.ReadAll(req.Body)
+
+		rw.WriteHeader(statusCode)
+	}))
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		uc         string
+		statusCode int
+		assert     func(t *testing.T, err error)
+	}{
+		{
+			uc:         "event accepted",
+			statusCode: http.StatusAccepted,
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, http.MethodPost, method)
+				assert.Equal(t, "application/json", contentType)
+				assert.Equal(t, "heimdall", header)
+				assert.Equal(t, `{"decision":"allow"}`, string(body))
+			},
+		},
+		{
+			uc:         "event rejected",
+			statusCode: http.StatusBadRequest,
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrCommunication)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			statusCode = tc.statusCode
+
+			sink, err := newWebhookSink(map[string]any{
+				"endpoint": map[string]any{
+					"url":     srv.URL,
+					"headers": map[string]any{"X-Source": "heimdall"},
+				},
+			})
+			require.NoError(t, err)
+
+			// WHEN
+			err = sink.Write(context.Background(), []byte(`{"decision":"allow"}`))
+
+			// THEN
+			tc.assert(t, err)
+		})
+	}
+}
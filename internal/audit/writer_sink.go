@@ -0,0 +1,43 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+type writerSink struct {
+	mut sync.Mutex
+	w   io.Writer
+}
+
+func newWriterSink(w io.Writer) *writerSink {
+	return &writerSink{w: w}
+}
+
+func (s *writerSink) Write(_ context.Context, event []byte) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	_, err := s.w.Write(append(event, '\n'))
+
+	return err
+}
+
+func (s *writerSink) Close() error { return nil }
@@ -0,0 +1,109 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+
+	"github.com/goccy/go-json"
+
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+var (
+	ErrUnsupportedValueType = errors.New("unsupported cache value type")
+
+	// by intention. Used only during application bootstrap.
+	valueTypesByName = map[string]reflect.Type{} //nolint:gochecknoglobals
+	valueTypeNames   = map[reflect.Type]string{} //nolint:gochecknoglobals
+	valueTypesMu     sync.RWMutex                //nolint:gochecknoglobals
+)
+
+//nolint:gochecknoinits
+func init() {
+	RegisterValueType("string", "")
+	RegisterValueType("bytes", []byte{})
+}
+
+// RegisterValueType makes values of the type of the given prototype storable in cache implementations,
+// which keep the values outside of heimdall's memory. The name identifies the type in the serialized
+// form and must therefore be stable and unique. Values are serialized to and from JSON.
+func RegisterValueType(name string, prototype any) {
+	valueTypesMu.Lock()
+	defer valueTypesMu.Unlock()
+
+	typ := reflect.TypeOf(prototype)
+	if typ == nil {
+		panic("RegisterValueType prototype is nil")
+	}
+
+	if known, ok := valueTypesByName[name]; ok && known != typ {
+		panic("RegisterValueType called twice for " + name)
+	}
+
+	valueTypesByName[name] = typ
+	valueTypeNames[typ] = name
+}
+
+type envelope struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+type valueCodec struct{}
+
+func (valueCodec) Encode(value any) ([]byte, error) {
+	valueTypesMu.RLock()
+	name, ok := valueTypeNames[reflect.TypeOf(value)]
+	valueTypesMu.RUnlock()
+
+	if !ok {
+		return nil, errorchain.NewWithMessagef(ErrUnsupportedValueType, "%T", value)
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(envelope{Type: name, Value: raw})
+}
+
+func (valueCodec) Decode(data []byte) (any, error) {
+	var env envelope
+
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+
+	valueTypesMu.RLock()
+	typ, ok := valueTypesByName[env.Type]
+	valueTypesMu.RUnlock()
+
+	if !ok {
+		return nil, errorchain.NewWithMessagef(ErrUnsupportedValueType, "'%s'", env.Type)
+	}
+
+	value := reflect.New(typ)
+	if err := json.Unmarshal(env.Value, value.Interface()); err != nil {
+		return nil, err
+	}
+
+	return value.Elem().Interface(), nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type codecTestValue struct {
+	Foo string `json:"foo"`
+	Bar int    `json:"bar"`
+}
+
+func TestValueCodecRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	RegisterValueType("codec_test_value", &codecTestValue{})
+
+	for _, tc := range []struct {
+		uc    string
+		value any
+	}{
+		{uc: "string", value: "foobar"},
+		{uc: "byte slice", value: []byte("foobar")},
+		{uc: "registered struct pointer", value: &codecTestValue{Foo: "foo", Bar: 42}},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			codec := valueCodec{}
+
+			// WHEN
+			data, err := codec.Encode(tc.value)
+			require.NoError(t, err)
+
+			value, err := codec.Decode(data)
+
+			// THEN
+			require.NoError(t, err)
+			assert.Equal(t, tc.value, value)
+		})
+	}
+}
+
+func TestValueCodecEncodeUnregisteredType(t *testing.T) {
+	t.Parallel()
+
+	// WHEN
+	_, err := valueCodec{}.Encode(struct{}{})
+
+	// THEN
+	require.Error(t, err)
+	require.ErrorIs(t, err, ErrUnsupportedValueType)
+}
+
+func TestValueCodecDecode(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc   string
+		data []byte
+	}{
+		{uc: "malformed envelope", data: []byte("foo")},
+		{uc: "unknown type", data: []byte(`{"type":"foo","value":"bar"}`)},
+		{uc: "value not matching type", data: []byte(`{"type":"string","value":42}`)},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// WHEN
+			value, err := valueCodec{}.Decode(tc.data)
+
+			// THEN
+			require.Error(t, err)
+			assert.Nil(t, value)
+		})
+	}
+}
+
+func TestRegisterValueTypeTwiceWithDifferentTypes(t *testing.T) {
+	t.Parallel()
+
+	RegisterValueType("codec_test_int", 1)
+
+	assert.NotPanics(t, func() { RegisterValueType("codec_test_int", 2) })
+	assert.Panics(t, func() { RegisterValueType("codec_test_int", "foo") })
+}
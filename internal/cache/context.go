@@ -36,11 +36,18 @@ func WithContext(ctx context.Context, cch Cache) context.Context {
 }
 
 // Ctx returns the Cache associated with the ctx. If no cache is associated, an instance is
-// returned, which does nothing.
+// returned, which does nothing. If an Observer is associated with the ctx, the returned
+// Cache reports all lookups to it.
 func Ctx(ctx context.Context) Cache {
+	var cch Cache = noopCache{}
+
 	if c, ok := ctx.Value(ctxKey{}).(Cache); ok {
-		return c
+		cch = c
+	}
+
+	if observer, ok := ctx.Value(observerCtxKey{}).(Observer); ok {
+		return observedCache{Cache: cch, observer: observer}
 	}
 
-	return noopCache{}
+	return cch
 }
@@ -19,6 +19,7 @@ package cache
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -73,3 +74,37 @@ func TestContextCacheIsNotConfiguredTwice(t *testing.T) {
 	assert.Equal(t, cch1, Ctx(ctx2))
 	assert.Equal(t, cch2, Ctx(ctx3))
 }
+
+type lookupRecorder struct {
+	hits   int
+	misses int
+}
+
+func (r *lookupRecorder) CacheLookup(hit bool) {
+	if hit {
+		r.hits++
+	} else {
+		r.misses++
+	}
+}
+
+func TestContextCacheWithObserver(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	recorder := &lookupRecorder{}
+	ctx := WithObserver(WithContext(context.Background(), memory.New()), recorder)
+
+	// WHEN
+	cch := Ctx(ctx)
+	cch.Set(ctx, "foo", "bar", time.Minute)
+
+	miss := cch.Get(ctx, "baz")
+	hit := cch.Get(ctx, "foo")
+
+	// THEN
+	assert.Nil(t, miss)
+	assert.Equal(t, "bar", hit)
+	assert.Equal(t, 1, recorder.hits)
+	assert.Equal(t, 1, recorder.misses)
+}
@@ -23,26 +23,50 @@ import (
 	"go.uber.org/fx"
 
 	"github.com/dadrus/heimdall/internal/cache/memory"
+	"github.com/dadrus/heimdall/internal/cache/redis"
 	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/health"
 )
 
 //nolint:gochecknoglobals
-var Module = fx.Provide(
-	fx.Annotate(
-		newCache,
-		fx.OnStart(func(ctx context.Context, cch Cache) error { return cch.Start(ctx) }),
-		fx.OnStop(func(ctx context.Context, cch Cache) error { return cch.Stop(ctx) }),
+var Module = fx.Options(
+	fx.Provide(
+		fx.Annotate(
+			newCache,
+			fx.OnStart(func(ctx context.Context, cch Cache) error { return cch.Start(ctx) }),
+			fx.OnStop(func(ctx context.Context, cch Cache) error { return cch.Stop(ctx) }),
+		),
 	),
+	fx.Invoke(registerHealthCheck),
 )
 
-func newCache(conf *config.Configuration, logger zerolog.Logger) Cache {
-	if len(conf.Cache.Type) == 0 {
+func registerHealthCheck(cch Cache, registry *health.Registry) {
+	if checker, ok := cch.(health.Checker); ok {
+		registry.Register(checker)
+	}
+}
+
+func newCache(conf *config.Configuration, logger zerolog.Logger) (Cache, error) {
+	switch conf.Cache.Type {
+	case "", "memory":
 		logger.Info().Msg("Instantiating in memory cache")
 
-		return memory.New()
-	}
+		return memory.New(), nil
+	case "redis":
+		logger.Info().Msg("Instantiating redis cache")
 
-	logger.Info().Msg("Cache is disabled")
+		return redis.NewStandaloneCache(conf.Cache.Config, valueCodec{})
+	case "redis-cluster":
+		logger.Info().Msg("Instantiating redis cluster cache")
 
-	return noopCache{}
+		return redis.NewClusterCache(conf.Cache.Config, valueCodec{})
+	case "redis-sentinel":
+		logger.Info().Msg("Instantiating redis sentinel cache")
+
+		return redis.NewSentinelCache(conf.Cache.Config, valueCodec{})
+	default:
+		logger.Info().Msg("Cache is disabled")
+
+		return noopCache{}, nil
+	}
 }
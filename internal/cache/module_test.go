@@ -17,48 +17,125 @@
 package cache
 
 import (
+	"context"
 	"testing"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/dadrus/heimdall/internal/cache/memory"
+	"github.com/dadrus/heimdall/internal/cache/redis"
 	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
 )
 
 func TestNewCache(t *testing.T) {
 	t.Parallel()
 
+	db := miniredis.RunT(t)
+
 	for _, tc := range []struct {
 		uc     string
 		conf   *config.Configuration
-		assert func(t *testing.T, cch Cache)
+		assert func(t *testing.T, err error, cch Cache)
 	}{
 		{
 			uc:   "in memory cache",
 			conf: &config.Configuration{},
-			assert: func(t *testing.T, cch Cache) {
+			assert: func(t *testing.T, err error, cch Cache) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.IsType(t, &memory.InMemoryCache{}, cch)
+			},
+		},
+		{
+			uc:   "explicitly configured in memory cache",
+			conf: &config.Configuration{Cache: config.CacheConfig{Type: "memory"}},
+			assert: func(t *testing.T, err error, cch Cache) {
 				t.Helper()
 
+				require.NoError(t, err)
 				assert.IsType(t, &memory.InMemoryCache{}, cch)
 			},
 		},
 		{
 			uc:   "disabled cache",
 			conf: &config.Configuration{Cache: config.CacheConfig{Type: "foo"}},
-			assert: func(t *testing.T, cch Cache) {
+			assert: func(t *testing.T, err error, cch Cache) {
 				t.Helper()
 
+				require.NoError(t, err)
 				assert.IsType(t, noopCache{}, cch)
 			},
 		},
+		{
+			uc: "redis cache",
+			conf: &config.Configuration{Cache: config.CacheConfig{
+				Type: "redis",
+				Config: map[string]any{
+					"address": db.Addr(),
+					"tls":     map[string]any{"disabled": true},
+				},
+			}},
+			assert: func(t *testing.T, err error, cch Cache) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.IsType(t, &redis.Cache{}, cch)
+				require.NoError(t, cch.Stop(context.TODO()))
+			},
+		},
+		{
+			uc: "redis cache with invalid configuration",
+			conf: &config.Configuration{Cache: config.CacheConfig{
+				Type:   "redis",
+				Config: map[string]any{"foo": "bar"},
+			}},
+			assert: func(t *testing.T, err error, _ Cache) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+			},
+		},
+		{
+			uc: "redis cluster cache without nodes",
+			conf: &config.Configuration{Cache: config.CacheConfig{
+				Type:   "redis-cluster",
+				Config: map[string]any{"tls": map[string]any{"disabled": true}},
+			}},
+			assert: func(t *testing.T, err error, _ Cache) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'nodes'")
+			},
+		},
+		{
+			uc: "redis sentinel cache without master",
+			conf: &config.Configuration{Cache: config.CacheConfig{
+				Type:   "redis-sentinel",
+				Config: map[string]any{"nodes": []string{db.Addr()}},
+			}},
+			assert: func(t *testing.T, err error, _ Cache) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'master'")
+			},
+		},
 	} {
 		t.Run("case="+tc.uc, func(t *testing.T) {
 			// WHEN
-			cch := newCache(tc.conf, log.Logger)
+			cch, err := newCache(tc.conf, log.Logger)
 
 			// THEN
-			tc.assert(t, cch)
+			tc.assert(t, err, cch)
 		})
 	}
 }
@@ -0,0 +1,48 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"context"
+)
+
+// Observer is notified about the results of cache lookups.
+type Observer interface {
+	CacheLookup(hit bool)
+}
+
+type observerCtxKey struct{}
+
+// WithObserver returns a copy of ctx with the given observer associated. All lookups
+// done via the Cache returned by Ctx for the resulting context are reported to it.
+func WithObserver(ctx context.Context, observer Observer) context.Context {
+	return context.WithValue(ctx, observerCtxKey{}, observer)
+}
+
+type observedCache struct {
+	Cache
+
+	observer Observer
+}
+
+func (c observedCache) Get(ctx context.Context, key string) any {
+	value := c.Cache.Get(ctx, key)
+
+	c.observer.CacheLookup(value != nil)
+
+	return value
+}
@@ -0,0 +1,190 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/rueidis"
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+// Codec translates the values handed over to the cache into their serialized form and back.
+type Codec interface {
+	Encode(value any) ([]byte, error)
+	Decode(data []byte) (any, error)
+}
+
+type Cache struct {
+	c      rueidis.Client
+	prefix string
+	codec  Codec
+}
+
+func NewStandaloneCache(rawConfig map[string]any, codec Codec) (*Cache, error) {
+	type Config struct {
+		baseConfig `mapstructure:",squash"`
+
+		Address string `mapstructure:"address" validate:"required"`
+	}
+
+	var conf Config
+	if err := decodeConfig("redis", rawConfig, &conf); err != nil {
+		return nil, err
+	}
+
+	opts, err := conf.clientOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	opts.InitAddress = []string{conf.Address}
+	opts.ForceSingleClient = true
+
+	return newCache(opts, conf.KeyPrefix, codec)
+}
+
+func NewClusterCache(rawConfig map[string]any, codec Codec) (*Cache, error) {
+	type Config struct {
+		baseConfig `mapstructure:",squash"`
+
+		Nodes []string `mapstructure:"nodes" validate:"required,gt=0,dive,required"`
+	}
+
+	var conf Config
+	if err := decodeConfig("redis-cluster", rawConfig, &conf); err != nil {
+		return nil, err
+	}
+
+	opts, err := conf.clientOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	opts.InitAddress = conf.Nodes
+	opts.ShuffleInit = true
+
+	return newCache(opts, conf.KeyPrefix, codec)
+}
+
+func NewSentinelCache(rawConfig map[string]any, codec Codec) (*Cache, error) {
+	type Config struct {
+		baseConfig `mapstructure:",squash"`
+
+		Nodes  []string `mapstructure:"nodes"  validate:"required,gt=0,dive,required"`
+		Master string   `mapstructure:"master" validate:"required"`
+	}
+
+	var conf Config
+	if err := decodeConfig("redis-sentinel", rawConfig, &conf); err != nil {
+		return nil, err
+	}
+
+	opts, err := conf.clientOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	opts.InitAddress = conf.Nodes
+	opts.Sentinel = rueidis.SentinelOption{
+		Dialer:     opts.Dialer,
+		TLSConfig:  opts.TLSConfig,
+		MasterSet:  conf.Master,
+		Username:   opts.Username,
+		Password:   opts.Password,
+		ClientName: opts.ClientName,
+	}
+
+	return newCache(opts, conf.KeyPrefix, codec)
+}
+
+func newCache(opts rueidis.ClientOption, prefix string, codec Codec) (*Cache, error) {
+	client, err := rueidis.NewClient(opts)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed creating redis client").
+			CausedBy(err)
+	}
+
+	return &Cache{c: client, prefix: prefix, codec: codec}, nil
+}
+
+func (c *Cache) Start(_ context.Context) error { return nil }
+
+func (c *Cache) Name() string { return "cache" }
+
+// Check verifies the redis server is reachable.
+func (c *Cache) Check(ctx context.Context) error {
+	if err := c.c.Do(ctx, c.c.B().Ping().Build()).Error(); err != nil {
+		return errorchain.NewWithMessage(heimdall.ErrCommunication, "redis server is not reachable").CausedBy(err)
+	}
+
+	return nil
+}
+
+func (c *Cache) Stop(_ context.Context) error {
+	c.c.Close()
+
+	return nil
+}
+
+func (c *Cache) Get(ctx context.Context, key string) any {
+	data, err := c.c.Do(ctx, c.c.B().Get().Key(c.prefix+key).Build()).AsBytes()
+	if err != nil {
+		if !rueidis.IsRedisNil(err) {
+			zerolog.Ctx(ctx).Warn().Err(err).Msg("Failed to retrieve value from redis cache")
+		}
+
+		return nil
+	}
+
+	value, err := c.codec.Decode(data)
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Failed to decode value retrieved from redis cache")
+
+		return nil
+	}
+
+	return value
+}
+
+func (c *Cache) Set(ctx context.Context, key string, value any, ttl time.Duration) {
+	if ttl < time.Millisecond {
+		return
+	}
+
+	data, err := c.codec.Encode(value)
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Failed to encode value for redis cache")
+
+		return
+	}
+
+	err = c.c.Do(ctx, c.c.B().Set().Key(c.prefix+key).Value(rueidis.BinaryString(data)).Px(ttl).Build()).Error()
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Failed to store value in redis cache")
+	}
+}
+
+func (c *Cache) Delete(ctx context.Context, key string) {
+	if err := c.c.Do(ctx, c.c.B().Del().Key(c.prefix+key).Build()).Error(); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Failed to delete value from redis cache")
+	}
+}
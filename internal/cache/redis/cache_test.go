@@ -0,0 +1,397 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/x/pkix/pemx"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+var errTest = errors.New("test error")
+
+type testCodec struct{}
+
+func (testCodec) Encode(value any) ([]byte, error) {
+	str, ok := value.(string)
+	if !ok {
+		return nil, errTest
+	}
+
+	return []byte(str), nil
+}
+
+func (testCodec) Decode(data []byte) (any, error) {
+	if string(data) == "broken" {
+		return nil, errTest
+	}
+
+	return string(data), nil
+}
+
+func TestCacheUsage(t *testing.T) {
+	t.Parallel()
+
+	db := miniredis.RunT(t)
+	db.RequireUserAuth("heimdall", "secret")
+
+	cch, err := NewStandaloneCache(map[string]any{
+		"address":     db.Addr(),
+		"key_prefix":  "heimdall:",
+		"credentials": map[string]any{"username": "heimdall", "password": "secret"},
+		"tls":         map[string]any{"disabled": true},
+	}, testCodec{})
+	require.NoError(t, err)
+
+	defer cch.Stop(context.TODO())
+
+	for _, tc := range []struct {
+		uc             string
+		key            string
+		configureCache func(t *testing.T, cch *Cache)
+		assert         func(t *testing.T, data any)
+	}{
+		{
+			uc:  "can retrieve not expired value",
+			key: "foo",
+			configureCache: func(t *testing.T, cch *Cache) {
+				t.Helper()
+
+				cch.Set(context.TODO(), "foo", "bar", 10*time.Minute)
+			},
+			assert: func(t *testing.T, data any) {
+				t.Helper()
+
+				assert.Equal(t, "bar", data)
+
+				value, err := db.Get("heimdall:foo")
+				require.NoError(t, err)
+				assert.Equal(t, "bar", value)
+				assert.Equal(t, 10*time.Minute, db.TTL("heimdall:foo"))
+			},
+		},
+		{
+			uc:  "cannot retrieve expired value",
+			key: "bar",
+			configureCache: func(t *testing.T, cch *Cache) {
+				t.Helper()
+
+				cch.Set(context.TODO(), "bar", "baz", 1*time.Second)
+
+				db.FastForward(2 * time.Second)
+			},
+			assert: func(t *testing.T, data any) {
+				t.Helper()
+
+				assert.Nil(t, data)
+			},
+		},
+		{
+			uc:  "cannot retrieve deleted value",
+			key: "baz",
+			configureCache: func(t *testing.T, cch *Cache) {
+				t.Helper()
+
+				cch.Set(context.TODO(), "baz", "bar", 1*time.Second)
+				cch.Delete(context.TODO(), "baz")
+			},
+			assert: func(t *testing.T, data any) {
+				t.Helper()
+
+				assert.Nil(t, data)
+			},
+		},
+		{
+			uc:  "cannot retrieve not existing value",
+			key: "zab",
+			configureCache: func(t *testing.T, _ *Cache) {
+				t.Helper()
+			},
+			assert: func(t *testing.T, data any) {
+				t.Helper()
+
+				assert.Nil(t, data)
+			},
+		},
+		{
+			uc:  "value which cannot be encoded is not stored",
+			key: "oof",
+			configureCache: func(t *testing.T, cch *Cache) {
+				t.Helper()
+
+				cch.Set(context.TODO(), "oof", 42, 10*time.Minute)
+			},
+			assert: func(t *testing.T, data any) {
+				t.Helper()
+
+				assert.Nil(t, data)
+				assert.False(t, db.Exists("heimdall:oof"))
+			},
+		},
+		{
+			uc:  "value which cannot be decoded is treated as not existing",
+			key: "rab",
+			configureCache: func(t *testing.T, _ *Cache) {
+				t.Helper()
+
+				require.NoError(t, db.Set("heimdall:rab", "broken"))
+			},
+			assert: func(t *testing.T, data any) {
+				t.Helper()
+
+				assert.Nil(t, data)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			tc.configureCache(t, cch)
+
+			// WHEN
+			data := cch.Get(context.TODO(), tc.key)
+
+			// THEN
+			tc.assert(t, data)
+		})
+	}
+}
+
+func TestCacheUsageWithUnavailableServer(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	db := miniredis.RunT(t)
+
+	cch, err := NewStandaloneCache(map[string]any{
+		"address": db.Addr(),
+		"tls":     map[string]any{"disabled": true},
+	}, testCodec{})
+	require.NoError(t, err)
+
+	defer cch.Stop(context.TODO())
+
+	cch.Set(context.TODO(), "foo", "bar", 10*time.Minute)
+	db.Close()
+
+	// WHEN
+	data := cch.Get(context.TODO(), "foo")
+
+	// THEN
+	assert.Nil(t, data)
+}
+
+func TestCacheCheck(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	db := miniredis.RunT(t)
+
+	cch, err := NewStandaloneCache(map[string]any{
+		"address": db.Addr(),
+		"tls":     map[string]any{"disabled": true},
+	}, testCodec{})
+	require.NoError(t, err)
+
+	defer cch.Stop(context.TODO())
+
+	// WHEN
+	err = cch.Check(context.TODO())
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, "cache", cch.Name())
+
+	// WHEN
+	db.Close()
+	err = cch.Check(context.TODO())
+
+	// THEN
+	require.ErrorIs(t, err, heimdall.ErrCommunication)
+}
+
+func TestCreateCacheWithTLS(t *testing.T) {
+	t.Parallel()
+
+	rootCA, err := testsupport.NewRootCA("Test Root CA", 24*time.Hour)
+	require.NoError(t, err)
+
+	privKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	cert, err := rootCA.IssueCertificate(
+		testsupport.WithSubject(pkix.Name{CommonName: "127.0.0.1"}),
+		testsupport.WithIPAddresses([]net.IP{net.ParseIP("127.0.0.1")}),
+		testsupport.WithValidity(time.Now(), 1*time.Hour),
+		testsupport.WithSubjectPubKey(&privKey.PublicKey, x509.ECDSAWithSHA384),
+		testsupport.WithExtendedKeyUsage(x509.ExtKeyUsageServerAuth),
+		testsupport.WithExtendedKeyUsage(x509.ExtKeyUsageClientAuth),
+		testsupport.WithKeyUsage(x509.KeyUsageDigitalSignature))
+	require.NoError(t, err)
+
+	pemBytes, err := pemx.BuildPEM(
+		pemx.WithECDSAPrivateKey(privKey, pemx.WithHeader("X-Key-ID", "foo")),
+		pemx.WithX509Certificate(cert),
+	)
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(t.TempDir(), "keystore.pem")
+	require.NoError(t, os.WriteFile(keyFile, pemBytes, 0o600))
+
+	caPEMBytes, err := pemx.BuildPEM(pemx.WithX509Certificate(rootCA.Certificate))
+	require.NoError(t, err)
+
+	trustStoreFile := filepath.Join(t.TempDir(), "truststore.pem")
+	require.NoError(t, os.WriteFile(trustStoreFile, caPEMBytes, 0o600))
+
+	db, err := miniredis.RunTLS(&tls.Config{
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{cert.Raw},
+			PrivateKey:  privKey,
+			Leaf:        cert,
+		}},
+		MinVersion: tls.VersionTLS13,
+	})
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	for _, tc := range []struct {
+		uc     string
+		config map[string]any
+		assert func(t *testing.T, err error)
+	}{
+		{
+			uc: "not existing key store",
+			config: map[string]any{
+				"address": db.Addr(),
+				"tls":     map[string]any{"key_store": map[string]any{"path": "/no/such/file"}},
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed loading keystore")
+			},
+		},
+		{
+			uc: "not existing key id",
+			config: map[string]any{
+				"address": db.Addr(),
+				"tls": map[string]any{
+					"key_store": map[string]any{"path": keyFile},
+					"key_id":    "bar",
+				},
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed retrieving key")
+			},
+		},
+		{
+			uc: "unsupported tls version",
+			config: map[string]any{
+				"address": db.Addr(),
+				"tls":     map[string]any{"min_version": "TLS1.1"},
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+			},
+		},
+		{
+			uc: "server certificate not trusted",
+			config: map[string]any{
+				"address": db.Addr(),
+				"tls": map[string]any{
+					"key_store": map[string]any{"path": keyFile},
+					"key_id":    "foo",
+				},
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				assert.Contains(t, err.Error(), "certificate")
+			},
+		},
+		{
+			uc: "not existing trust store",
+			config: map[string]any{
+				"address": db.Addr(),
+				"tls":     map[string]any{"trust_store": "/no/such/file"},
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+			},
+		},
+		{
+			uc: "server certificate trusted via trust store",
+			config: map[string]any{
+				"address": db.Addr(),
+				"tls": map[string]any{
+					"key_store":   map[string]any{"path": keyFile},
+					"key_id":      "foo",
+					"trust_store": trustStoreFile,
+				},
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// WHEN
+			cch, err := NewStandaloneCache(tc.config, testCodec{})
+			if err == nil {
+				defer cch.Stop(context.TODO())
+			}
+
+			// THEN
+			tc.assert(t, err)
+		})
+	}
+}
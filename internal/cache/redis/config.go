@@ -0,0 +1,168 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/redis/rueidis"
+
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/keystore"
+	"github.com/dadrus/heimdall/internal/truststore"
+	"github.com/dadrus/heimdall/internal/validation"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+const defaultConnectionTimeout = 5 * time.Second
+
+type credentials struct {
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password" validate:"required"`
+}
+
+type tlsConfig struct {
+	config.TLS `mapstructure:",squash"`
+
+	TrustStore truststore.TrustStore `mapstructure:"trust_store"`
+	Disabled   bool                  `mapstructure:"disabled"`
+}
+
+type baseConfig struct {
+	Credentials       *credentials  `mapstructure:"credentials"`
+	ClientName        string        `mapstructure:"client_name"`
+	DB                int           `mapstructure:"db"                 validate:"gte=0"`
+	KeyPrefix         string        `mapstructure:"key_prefix"`
+	ConnectionTimeout time.Duration `mapstructure:"connection_timeout"`
+	TLS               tlsConfig     `mapstructure:"tls"`
+}
+
+func (c baseConfig) clientOptions() (rueidis.ClientOption, error) {
+	tlsCfg, err := c.tlsConfig()
+	if err != nil {
+		return rueidis.ClientOption{}, err
+	}
+
+	opts := rueidis.ClientOption{
+		ClientName:   c.ClientName,
+		SelectDB:     c.DB,
+		TLSConfig:    tlsCfg,
+		DisableCache: true,
+		DisableRetry: true,
+		Dialer: net.Dialer{
+			Timeout: x.IfThenElse(c.ConnectionTimeout > 0, c.ConnectionTimeout, defaultConnectionTimeout),
+		},
+	}
+
+	if c.Credentials != nil {
+		opts.Username = c.Credentials.Username
+		opts.Password = c.Credentials.Password
+	}
+
+	return opts, nil
+}
+
+func (c baseConfig) tlsConfig() (*tls.Config, error) {
+	if c.TLS.Disabled {
+		return nil, nil //nolint:nilnil
+	}
+
+	// nolint:gosec
+	// configuration ensures, TLS versions below 1.2 are not possible
+	cfg := &tls.Config{
+		MinVersion: c.TLS.MinVersion.OrDefault(),
+	}
+
+	if cfg.MinVersion != tls.VersionTLS13 {
+		cfg.CipherSuites = c.TLS.CipherSuites.OrDefault()
+	}
+
+	if len(c.TLS.TrustStore) != 0 {
+		cfg.RootCAs = x509.NewCertPool()
+
+		for _, cert := range c.TLS.TrustStore {
+			cfg.RootCAs.AddCert(cert)
+		}
+	}
+
+	if len(c.TLS.KeyStore.Path) == 0 {
+		return cfg, nil
+	}
+
+	ks, err := keystore.NewKeyStore(c.TLS.KeyStore)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration, "failed loading keystore").
+			CausedBy(err)
+	}
+
+	var entry *keystore.Entry
+
+	if len(c.TLS.KeyID) != 0 {
+		if entry, err = ks.GetKey(c.TLS.KeyID); err != nil {
+			return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+				"failed retrieving key from key store").CausedBy(err)
+		}
+	} else {
+		entry = ks.Entries()[0]
+	}
+
+	cert, err := keystore.ToTLSCertificate(entry)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"key store entry is not suitable for TLS").CausedBy(err)
+	}
+
+	cfg.Certificates = []tls.Certificate{cert}
+
+	return cfg, nil
+}
+
+func decodeConfig(cacheType string, input, output any) error {
+	dec, err := mapstructure.NewDecoder(
+		&mapstructure.DecoderConfig{
+			DecodeHook: mapstructure.ComposeDecodeHookFunc(
+				mapstructure.StringToTimeDurationHookFunc(),
+				config.DecodeTLSCipherSuiteHookFunc,
+				config.DecodeTLSMinVersionHookFunc,
+				truststore.DecodeTrustStoreHookFunc(),
+			),
+			Result:      output,
+			ErrorUnused: true,
+		})
+	if err != nil {
+		return errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding '%s' cache config", cacheType).CausedBy(err)
+	}
+
+	if err = dec.Decode(input); err != nil {
+		return errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed decoding '%s' cache config", cacheType).CausedBy(err)
+	}
+
+	if err = validation.ValidateStruct(output); err != nil {
+		return errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed validating '%s' cache config", cacheType).CausedBy(err)
+	}
+
+	return nil
+}
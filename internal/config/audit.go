@@ -0,0 +1,35 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+type AuditConfig struct {
+	Enabled    bool              `koanf:"enabled"`
+	BufferSize int               `koanf:"buffer_size"`
+	Redact     []RedactionRule   `koanf:"redact,omitempty"`
+	Sinks      []AuditSinkConfig `koanf:"sinks,omitempty"`
+}
+
+type RedactionRule struct {
+	Field       string `koanf:"field"`
+	Pattern     string `koanf:"pattern,omitempty"`
+	Replacement string `koanf:"replacement,omitempty"`
+}
+
+type AuditSinkConfig struct {
+	Type   string         `koanf:"type"`
+	Config map[string]any `koanf:"config,omitempty"`
+}
@@ -17,5 +17,6 @@
 package config
 
 type CacheConfig struct {
-	Type string `koanf:"type"`
+	Type   string         `koanf:"type"`
+	Config map[string]any `koanf:"config,omitempty"`
 }
@@ -26,26 +26,61 @@ import (
 )
 
 type Configuration struct { //nolint:musttag
-	Serve      ServeConfig          `koanf:"serve"`
-	Log        LoggingConfig        `koanf:"log"`
-	Tracing    TracingConfig        `koanf:"tracing"`
-	Metrics    MetricsConfig        `koanf:"metrics"`
-	Profiling  ProfilingConfig      `koanf:"profiling"`
-	Signer     SignerConfig         `koanf:"signer"`
-	Cache      CacheConfig          `koanf:"cache"`
-	Prototypes *MechanismPrototypes `koanf:"mechanisms,omitempty"`
-	Default    *DefaultRule         `koanf:"default_rule,omitempty"`
-	Providers  RuleProviders        `koanf:"providers,omitempty"`
+	Serve       ServeConfig          `koanf:"serve"`
+	Log         LoggingConfig        `koanf:"log"`
+	Tracing     TracingConfig        `koanf:"tracing"`
+	Metrics     MetricsConfig        `koanf:"metrics"`
+	Profiling   ProfilingConfig      `koanf:"profiling"`
+	Signer      SignerConfig         `koanf:"signer"`
+	Cache       CacheConfig          `koanf:"cache"`
+	Audit       AuditConfig          `koanf:"audit"`
+	UpstreamTLS UpstreamTLS          `koanf:"upstream_tls,omitempty"`
+	Prototypes  *MechanismPrototypes `koanf:"mechanisms,omitempty"`
+	Default     *DefaultRule         `koanf:"default_rule,omitempty"`
+	Providers   RuleProviders        `koanf:"providers,omitempty"`
 }
 
-func NewConfiguration(envPrefix EnvVarPrefix, configFile ConfigurationPath) (*Configuration, error) {
+// NewConfiguration loads the configuration. If a Watcher is passed via the WithWatcher option,
+// it is configured to reload the configuration from the same sources on changes.
+func NewConfiguration(
+	envPrefix EnvVarPrefix, configFile ConfigurationPath, opts ...Option,
+) (*Configuration, error) {
+	options := &options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	loader := newConfigLoader(envPrefix, configFile)
+
+	result, err := loadConfiguration(loader)
+	if err != nil || options.watcher == nil {
+		return result, err
+	}
+
+	file, err := loader.ConfigFile()
+	if err != nil {
+		return result, err
+	}
+
+	options.watcher.init(file, func() (*Configuration, error) { return loadConfiguration(loader) })
+
+	return result, nil
+}
+
+func loadConfiguration(loader parser.ConfigLoader) (*Configuration, error) {
 	// copy defaults
 	result := defaultConfig()
 
+	err := loader.Load(&result)
+
+	return &result, err
+}
+
+func newConfigLoader(envPrefix EnvVarPrefix, configFile ConfigurationPath) parser.ConfigLoader {
 	opts := []parser.Option{
 		parser.WithDecodeHookFunc(mapstructure.StringToTimeDurationHookFunc()),
 		parser.WithDecodeHookFunc(mapstructure.StringToSliceHookFunc(",")),
-		parser.WithDecodeHookFunc(stringToByteSizeHookFunc()),
+		parser.WithDecodeHookFunc(StringToByteSizeHookFunc()),
 		parser.WithDecodeHookFunc(logLevelDecodeHookFunc),
 		parser.WithDecodeHookFunc(logFormatDecodeHookFunc),
 		parser.WithDecodeHookFunc(DecodeTLSCipherSuiteHookFunc),
@@ -73,7 +108,5 @@ func NewConfiguration(envPrefix EnvVarPrefix, configFile ConfigurationPath) (*Co
 
 	opts = append(opts, parser.WithConfigLookupDir("/etc/heimdall/"))
 
-	err = parser.New(opts...).Load(&result)
-
-	return &result, err
+	return parser.New(opts...)
 }
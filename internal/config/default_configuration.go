@@ -38,6 +38,8 @@ const (
 
 	defaultBufferSize = 4 * bytesize.KB
 
+	defaultKeyRotationOverlap = 10 * time.Minute
+
 	loopbackIP = "127.0.0.1"
 )
 
@@ -103,6 +105,9 @@ func defaultConfig() Configuration {
 		},
 		Signer: SignerConfig{
 			Name: "heimdall",
+			KeyRotation: KeyRotation{
+				Overlap: defaultKeyRotationOverlap,
+			},
 		},
 		Prototypes: &MechanismPrototypes{},
 	}
@@ -16,7 +16,18 @@
 
 package config
 
+const (
+	KeyStoreTypePEM    = "pem"
+	KeyStoreTypePKCS12 = "pkcs12"
+	KeyStoreTypeJWKS   = "jwks"
+	KeyStoreTypePKCS11 = "pkcs11"
+)
+
+// KeyStore is comparable by intention, so configurations embedding it, like the tls settings
+// of the upstreams in rules, can be used as keys to share resources.
 type KeyStore struct {
-	Path     string `koanf:"path"`
-	Password string `koanf:"password"`
+	Type       string `json:"type,omitempty"        koanf:"type"        mapstructure:"type"        yaml:"type,omitempty"`        //nolint:lll
+	Path       string `json:"path,omitempty"        koanf:"path"        mapstructure:"path"        yaml:"path,omitempty"`        //nolint:lll
+	Password   string `json:"password,omitempty"    koanf:"password"    mapstructure:"password"    yaml:"password,omitempty"`    //nolint:lll
+	TokenLabel string `json:"token_label,omitempty" koanf:"token_label" mapstructure:"token_label" yaml:"token_label,omitempty"` //nolint:lll
 }
@@ -128,7 +128,7 @@ func DecodeTLSMinVersionHookFunc(from reflect.Type, to reflect.Type, data any) (
 	}
 }
 
-func stringToByteSizeHookFunc() mapstructure.DecodeHookFunc {
+func StringToByteSizeHookFunc() mapstructure.DecodeHookFunc {
 	return func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
 		if f.Kind() != reflect.String {
 			return data, nil
@@ -16,10 +16,30 @@
 
 package config
 
-import "go.uber.org/fx"
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/fx"
+)
 
 //nolint:gochecknoglobals
 var Module = fx.Options(
-	fx.Provide(NewConfiguration),
+	fx.Provide(NewWatcher),
+	fx.Provide(newWatchedConfiguration),
 	fx.Provide(LogConfiguration),
+	fx.Invoke(registerWatcher),
 )
+
+func newWatchedConfiguration(
+	envPrefix EnvVarPrefix, configFile ConfigurationPath, watcher *Watcher,
+) (*Configuration, error) {
+	return NewConfiguration(envPrefix, configFile, WithWatcher(watcher))
+}
+
+func registerWatcher(watcher *Watcher, logger zerolog.Logger, lifecycle fx.Lifecycle) {
+	lifecycle.Append(fx.Hook{
+		OnStart: func(_ context.Context) error { return watcher.start(logger) },
+		OnStop:  watcher.stop,
+	})
+}
@@ -27,6 +27,9 @@ import (
 
 type ConfigLoader interface {
 	Load(config any) error
+	// ConfigFile returns the path of the configuration file, the configuration is loaded from.
+	// It is empty if no configuration file is used.
+	ConfigFile() (string, error)
 }
 
 func New(opts ...Option) ConfigLoader {
@@ -44,7 +47,7 @@ type configLoader struct {
 }
 
 func (c *configLoader) Load(config any) error {
-	configFile, err := c.configFile()
+	configFile, err := c.ConfigFile()
 	if err != nil {
 		return err
 	}
@@ -103,7 +106,7 @@ func (c *configLoader) Load(config any) error {
 	})
 }
 
-func (c *configLoader) configFile() (string, error) {
+func (c *configLoader) ConfigFile() (string, error) {
 	if len(c.o.configFile) != 0 {
 		_, err := os.Stat(c.o.configFile)
 		if err != nil {
@@ -59,6 +59,19 @@ type ServiceConfig struct {
 	TLS              *TLS             `koanf:"tls,omitempty"`
 	TrustedProxies   *[]string        `koanf:"trusted_proxies,omitempty"`
 	Respond          RespondConfig    `koanf:"respond"`
+	// used by the management service only. The endpoints exposing the loaded rules
+	// and explaining requests are disabled by default, as these reveal the rule configuration.
+	Inspection InspectionConfig `koanf:"inspection"`
+	Explain    ExplainConfig    `koanf:"explain"`
+}
+
+type InspectionConfig struct {
+	Enabled bool `koanf:"enabled"`
+}
+
+type ExplainConfig struct {
+	Enabled          bool `koanf:"enabled"`
+	AllowRemoteCalls bool `koanf:"allow_remote_calls"`
 }
 
 func (c ServiceConfig) Address() string { return fmt.Sprintf("%s:%d", c.Host, c.Port) }
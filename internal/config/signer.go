@@ -16,8 +16,15 @@
 
 package config
 
+import "time"
+
 type SignerConfig struct {
-	Name     string   `koanf:"name"`
-	KeyStore KeyStore `koanf:"key_store"`
-	KeyID    string   `koanf:"key_id"`
+	Name        string      `koanf:"name"`
+	KeyStore    KeyStore    `koanf:"key_store"`
+	KeyID       string      `koanf:"key_id"`
+	KeyRotation KeyRotation `koanf:"key_rotation"`
+}
+
+type KeyRotation struct {
+	Overlap time.Duration `koanf:"overlap,string"`
 }
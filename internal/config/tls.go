@@ -45,9 +45,29 @@ func (v TLSMinVersion) OrDefault() uint16 {
 	return uint16(v)
 }
 
+type TLSClientAuth string
+
+const (
+	TLSClientAuthNone    TLSClientAuth = "none"
+	TLSClientAuthRequest TLSClientAuth = "request"
+	TLSClientAuthRequire TLSClientAuth = "require"
+)
+
+func (a TLSClientAuth) Type() tls.ClientAuthType {
+	switch a {
+	case TLSClientAuthRequest:
+		return tls.RequestClientCert
+	case TLSClientAuthRequire:
+		return tls.RequireAnyClientCert
+	default:
+		return tls.NoClientCert
+	}
+}
+
 type TLS struct {
 	KeyStore     KeyStore        `koanf:"key_store"     mapstructure:"key_store"`
 	KeyID        string          `koanf:"key_id"        mapstructure:"key_id"`
 	CipherSuites TLSCipherSuites `koanf:"cipher_suites" mapstructure:"cipher_suites"`
 	MinVersion   TLSMinVersion   `koanf:"min_version"   mapstructure:"min_version"`
+	ClientAuth   TLSClientAuth   `koanf:"client_auth"   mapstructure:"client_auth"`
 }
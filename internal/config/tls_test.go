@@ -72,3 +72,22 @@ func TestTLSCipherSuitesOrDefault(t *testing.T) {
 		})
 	}
 }
+
+func TestTLSClientAuthType(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc         string
+		clientAuth TLSClientAuth
+		expected   tls.ClientAuthType
+	}{
+		{uc: "not configured", expected: tls.NoClientCert},
+		{uc: "none", clientAuth: TLSClientAuthNone, expected: tls.NoClientCert},
+		{uc: "request", clientAuth: TLSClientAuthRequest, expected: tls.RequestClientCert},
+		{uc: "require", clientAuth: TLSClientAuthRequire, expected: tls.RequireAnyClientCert},
+	} {
+		t.Run(tc.uc, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.clientAuth.Type())
+		})
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+// UpstreamTLS declares the key and trust stores the tls settings of the upstreams in rules can
+// refer to by their names. Rules cannot define such stores on their own, as everyone able to author
+// a rule would otherwise be able to make heimdall load arbitrary files or PKCS#11 modules.
+type UpstreamTLS struct {
+	KeyStores   map[string]KeyStore `koanf:"key_stores,omitempty"`
+	TrustStores map[string]string   `koanf:"trust_stores,omitempty"`
+}
@@ -0,0 +1,185 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/x/filewatcher"
+)
+
+// WatchConfiguration defines whether the configuration should be reloaded if the
+// configuration file changes, or heimdall receives a SIGHUP signal.
+type WatchConfiguration bool
+
+// ChangeListener is notified about a reloaded configuration. An implementation must either apply the
+// given configuration completely, or not at all, returning an error in the latter case.
+type ChangeListener interface {
+	OnConfigurationChanged(conf *Configuration) error
+}
+
+type Option func(o *options)
+
+type options struct {
+	watcher *Watcher
+}
+
+// WithWatcher makes the given Watcher reload the configuration from the same sources,
+// it has been initially loaded from.
+func WithWatcher(watcher *Watcher) Option {
+	return func(o *options) {
+		o.watcher = watcher
+	}
+}
+
+// Watcher reloads the configuration on changes to the configuration file, or on SIGHUP and
+// notifies the subscribed listeners. An invalid configuration is logged and ignored, so that
+// the current configuration stays in effect.
+type Watcher struct {
+	enabled   bool
+	l         zerolog.Logger
+	file      string
+	load      func() (*Configuration, error)
+	listeners []ChangeListener
+	mutex     sync.Mutex
+
+	fw   *filewatcher.Watcher
+	sig  chan os.Signal
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewWatcher(enabled WatchConfiguration) *Watcher {
+	return &Watcher{enabled: bool(enabled), l: zerolog.Nop()}
+}
+
+func (w *Watcher) init(file string, load func() (*Configuration, error)) {
+	if len(file) != 0 {
+		if absPath, err := filepath.Abs(file); err == nil {
+			file = absPath
+		}
+	}
+
+	w.file = file
+	w.load = load
+}
+
+// Subscribe registers a listener to be notified about reloaded configurations. Listeners are
+// notified in the order of their registration. If a listener fails, the remaining ones are
+// not notified.
+func (w *Watcher) Subscribe(listener ChangeListener) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.listeners = append(w.listeners, listener)
+}
+
+func (w *Watcher) start(logger zerolog.Logger) error {
+	if !w.enabled || w.load == nil {
+		return nil
+	}
+
+	w.l = logger
+
+	if len(w.file) != 0 {
+		fw := filewatcher.New(w.file, w.reload, logger)
+		if err := fw.Start(); err != nil {
+			return err
+		}
+
+		w.fw = fw
+	}
+
+	w.sig = make(chan os.Signal, 1)
+	w.done = make(chan struct{})
+
+	signal.Notify(w.sig, syscall.SIGHUP)
+
+	w.wg.Add(1)
+
+	go w.watch()
+
+	w.l.Info().Str("_file", w.file).Msg("Watching configuration for changes")
+
+	return nil
+}
+
+func (w *Watcher) stop(_ context.Context) error {
+	if w.done == nil {
+		return nil
+	}
+
+	signal.Stop(w.sig)
+	close(w.done)
+
+	w.wg.Wait()
+
+	if w.fw != nil {
+		return w.fw.Stop()
+	}
+
+	return nil
+}
+
+func (w *Watcher) watch() {
+	defer w.wg.Done()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-w.sig:
+			w.l.Info().Msg("SIGHUP received")
+
+			_ = w.reload()
+		}
+	}
+}
+
+// reload loads the configuration again and notifies the listeners.
+func (w *Watcher) reload() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.l.Info().Msg("Reloading configuration")
+
+	conf, err := w.load()
+	if err != nil {
+		w.l.Error().Err(err).Msg("Failed to reload configuration. Keeping the current one")
+
+		return err
+	}
+
+	for _, listener := range w.listeners {
+		if err = listener.OnConfigurationChanged(conf); err != nil {
+			w.l.Error().Err(err).Msg("Failed to apply reloaded configuration. Keeping the current one")
+
+			return err
+		}
+	}
+
+	w.l.Info().Msg("Configuration reloaded")
+
+	return nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+type testListener struct {
+	err     error
+	changes chan *Configuration
+}
+
+func (l *testListener) OnConfigurationChanged(conf *Configuration) error {
+	l.changes <- conf
+
+	return l.err
+}
+
+func (l *testListener) awaitChange(t *testing.T) *Configuration {
+	t.Helper()
+
+	select {
+	case conf := <-l.changes:
+		return conf
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "configuration has not been reloaded")
+
+		return nil
+	}
+}
+
+func (l *testListener) assertNoChange(t *testing.T) {
+	t.Helper()
+
+	select {
+	case <-l.changes:
+		require.Fail(t, "configuration has been reloaded")
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+func TestWatcherReloadsConfiguration(t *testing.T) {
+	// GIVEN
+	configFile := filepath.Join(t.TempDir(), "heimdall.yaml")
+	writeConfig := func(contents string) {
+		require.NoError(t, os.WriteFile(configFile, []byte(contents), 0o600))
+	}
+
+	writeConfig("log:\n  level: info\n")
+
+	watcher := NewWatcher(true)
+	listener := &testListener{changes: make(chan *Configuration, 10)}
+
+	conf, err := NewConfiguration("HEIMDALLCFG_", ConfigurationPath(configFile), WithWatcher(watcher))
+	require.NoError(t, err)
+	assert.Equal(t, zerolog.InfoLevel, conf.Log.Level)
+
+	watcher.Subscribe(listener)
+	require.NoError(t, watcher.start(log.Logger))
+
+	defer watcher.stop(context.Background()) //nolint:errcheck
+
+	// WHEN
+	writeConfig("log:\n  level: debug\n")
+
+	// THEN
+	assert.Equal(t, zerolog.DebugLevel, listener.awaitChange(t).Log.Level)
+
+	// WHEN
+	writeConfig("log:\n  level: foo\n")
+
+	// THEN
+	listener.assertNoChange(t)
+
+	// WHEN
+	writeConfig("log:\n  level: error\n")
+
+	// THEN
+	assert.Equal(t, zerolog.ErrorLevel, listener.awaitChange(t).Log.Level)
+
+	// WHEN
+	proc, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, proc.Signal(syscall.SIGHUP))
+
+	// THEN
+	assert.Equal(t, zerolog.ErrorLevel, listener.awaitChange(t).Log.Level)
+}
+
+func TestWatcherWithFailingListener(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	configFile := filepath.Join(t.TempDir(), "heimdall.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("log:\n  level: info\n"), 0o600))
+
+	watcher := NewWatcher(true)
+	failing := &testListener{err: testsupport.ErrTestPurpose, changes: make(chan *Configuration, 10)}
+	other := &testListener{changes: make(chan *Configuration, 10)}
+
+	_, err := NewConfiguration("HEIMDALLCFG_", ConfigurationPath(configFile), WithWatcher(watcher))
+	require.NoError(t, err)
+
+	watcher.Subscribe(failing)
+	watcher.Subscribe(other)
+
+	require.NoError(t, os.WriteFile(configFile, []byte("log:\n  level: debug\n"), 0o600))
+
+	// WHEN
+	err = watcher.reload()
+
+	// THEN
+	require.ErrorIs(t, err, testsupport.ErrTestPurpose)
+	assert.Equal(t, zerolog.DebugLevel, failing.awaitChange(t).Log.Level)
+	other.assertNoChange(t)
+}
+
+func TestDisabledWatcher(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	watcher := NewWatcher(false)
+
+	_, err := NewConfiguration("HEIMDALLCFG_", "./test_data/test_config.yaml", WithWatcher(watcher))
+	require.NoError(t, err)
+
+	// WHEN
+	err = watcher.start(log.Logger)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Nil(t, watcher.done)
+	require.NoError(t, watcher.stop(context.Background()))
+}
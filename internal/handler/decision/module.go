@@ -22,6 +22,7 @@ import (
 	"github.com/rs/zerolog"
 	"go.uber.org/fx"
 
+	"github.com/dadrus/heimdall/internal/audit"
 	"github.com/dadrus/heimdall/internal/cache"
 	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/handler/fxlcm"
@@ -39,17 +40,19 @@ var Module = fx.Invoke( // nolint: gochecknoglobals
 
 func newLifecycleManager(
 	conf *config.Configuration,
+	watcher *config.Watcher,
 	logger zerolog.Logger,
 	cch cache.Cache,
 	exec rule.Executor,
 	signer heimdall.JWTSigner,
+	auditor audit.Auditor,
 ) *fxlcm.LifecycleManager {
 	cfg := conf.Serve.Decision
 
 	return &fxlcm.LifecycleManager{
 		ServiceName:    "Decision",
 		ServiceAddress: cfg.Address(),
-		Server:         newService(conf, cch, logger, exec, signer),
+		Server:         newService(conf, watcher, cch, logger, exec, signer, auditor),
 		Logger:         logger,
 		TLSConf:        cfg.TLS,
 	}
@@ -53,11 +53,20 @@ func (r *requestContext) Finalize(_ rule.Backend) error {
 
 	zerolog.Ctx(r.AppContext()).Debug().Msg("Creating response")
 
+	signatureHeaders, err := r.signUpstreamRequest()
+	if err != nil {
+		return err
+	}
+
 	uh := r.UpstreamHeaders()
 	for k := range uh {
 		r.rw.Header().Set(k, uh.Get(k))
 	}
 
+	for k, v := range signatureHeaders {
+		r.rw.Header()[k] = v
+	}
+
 	for k, v := range r.UpstreamCookies() {
 		http.SetCookie(r.rw, &http.Cookie{Name: k, Value: v})
 	}
@@ -66,3 +75,33 @@ func (r *requestContext) Finalize(_ rule.Backend) error {
 
 	return nil
 }
+
+// signUpstreamRequest applies the signers registered by the finalizers to the request, as it
+// is forwarded by the proxy in front of heimdall, including the headers set by the finalizers.
+func (r *requestContext) signUpstreamRequest() (http.Header, error) {
+	signers := r.UpstreamRequestSigners()
+	if len(signers) == 0 {
+		return http.Header{}, nil
+	}
+
+	req := r.Request()
+	headers := make(http.Header)
+
+	for k, v := range r.Headers() {
+		if k != "Host" {
+			headers.Set(k, v)
+		}
+	}
+
+	uh := r.UpstreamHeaders()
+	for k := range uh {
+		headers.Set(k, uh.Get(k))
+	}
+
+	return requestcontext.SignUpstreamRequest(&http.Request{
+		Method: req.Method,
+		URL:    &req.URL.URL,
+		Host:   req.URL.Host,
+		Header: headers,
+	}, signers)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/dadrus/heimdall/internal/handler/requestcontext"
+	"github.com/dadrus/heimdall/internal/heimdall"
 )
 
 func TestRequestContextFinalize(t *testing.T) {
@@ -126,6 +127,48 @@ func TestRequestContextFinalize(t *testing.T) {
 				assert.Equal(t, http.StatusOK, rec.Code)
 			},
 		},
+		{
+			uc:   "upstream request signers are applied",
+			code: http.StatusOK,
+			setup: func(t *testing.T, rc requestcontext.Context) {
+				t.Helper()
+
+				rc.AddHeaderForUpstream("X-Foo", "bar")
+				rc.AddUpstreamRequestSigner(func(req *heimdall.Request) (http.Header, error) {
+					return http.Header{"X-Signature": []string{
+						req.Method + " " + req.URL.Host + req.URL.Path + " " + req.Header("X-Foo"),
+					}}, nil
+				})
+			},
+			assert: func(t *testing.T, err error, rec *httptest.ResponseRecorder) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				assert.Len(t, rec.Header(), 2)
+				assert.Equal(t, "bar", rec.Header().Get("X-Foo"))
+				assert.Equal(t, "POST heimdall.local/foo bar", rec.Header().Get("X-Signature"))
+				assert.Equal(t, http.StatusOK, rec.Code)
+			},
+		},
+		{
+			uc:   "upstream request signer fails",
+			code: http.StatusOK,
+			setup: func(t *testing.T, rc requestcontext.Context) {
+				t.Helper()
+
+				rc.AddHeaderForUpstream("X-Foo", "bar")
+				rc.AddUpstreamRequestSigner(func(_ *heimdall.Request) (http.Header, error) {
+					return nil, heimdall.ErrArgument
+				})
+			},
+			assert: func(t *testing.T, err error, rec *httptest.ResponseRecorder) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				assert.Empty(t, rec.Header())
+			},
+		},
 	} {
 		t.Run(tc.uc, func(t *testing.T) {
 			// GIVEN
@@ -25,9 +25,11 @@ import (
 	"github.com/rs/zerolog"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 
+	"github.com/dadrus/heimdall/internal/audit"
 	"github.com/dadrus/heimdall/internal/cache"
 	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/handler/middleware/http/accesslog"
+	auditmiddleware "github.com/dadrus/heimdall/internal/handler/middleware/http/audit"
 	cachemiddleware "github.com/dadrus/heimdall/internal/handler/middleware/http/cache"
 	"github.com/dadrus/heimdall/internal/handler/middleware/http/dump"
 	"github.com/dadrus/heimdall/internal/handler/middleware/http/errorhandler"
@@ -35,6 +37,7 @@ import (
 	"github.com/dadrus/heimdall/internal/handler/middleware/http/otelmetrics"
 	"github.com/dadrus/heimdall/internal/handler/middleware/http/recovery"
 	"github.com/dadrus/heimdall/internal/handler/middleware/http/trustedproxy"
+	"github.com/dadrus/heimdall/internal/handler/reloadable"
 	"github.com/dadrus/heimdall/internal/handler/service"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/rules/rule"
@@ -45,49 +48,60 @@ import (
 
 func newService(
 	conf *config.Configuration,
+	watcher *config.Watcher,
 	cch cache.Cache,
 	log zerolog.Logger,
 	exec rule.Executor,
 	signer heimdall.JWTSigner,
+	auditor audit.Auditor,
 ) *http.Server {
 	cfg := conf.Serve.Decision
-	eh := errorhandler.New(
-		errorhandler.WithVerboseErrors(cfg.Respond.Verbose),
-		errorhandler.WithPreconditionErrorCode(cfg.Respond.With.ArgumentError.Code),
-		errorhandler.WithAuthenticationErrorCode(cfg.Respond.With.AuthenticationError.Code),
-		errorhandler.WithAuthorizationErrorCode(cfg.Respond.With.AuthorizationError.Code),
-		errorhandler.WithCommunicationErrorCode(cfg.Respond.With.CommunicationError.Code),
-		errorhandler.WithMethodErrorCode(cfg.Respond.With.BadMethodError.Code),
-		errorhandler.WithNoRuleErrorCode(cfg.Respond.With.NoRuleError.Code),
-		errorhandler.WithInternalServerErrorCode(cfg.Respond.With.InternalError.Code),
-	)
-	acceptedCode := x.IfThenElse(cfg.Respond.With.Accepted.Code != 0, cfg.Respond.With.Accepted.Code, http.StatusOK)
+	address := cfg.Address()
 
-	hc := alice.New(
-		trustedproxy.New(
-			log,
-			x.IfThenElseExec(cfg.TrustedProxies != nil,
-				func() []string { return *cfg.TrustedProxies },
-				func() []string { return []string{} },
-			)...,
-		),
-		accesslog.New(log),
-		logger.New(log),
-		dump.New(),
-		recovery.New(eh),
-		otelhttp.NewMiddleware("",
-			otelhttp.WithServerName(cfg.Address()),
-			otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
-				return fmt.Sprintf("EntryPoint %s %s%s",
-					strings.ToLower(req.URL.Scheme), httpx.LocalAddress(req), req.URL.Path)
-			}),
-		),
-		otelmetrics.New(
-			otelmetrics.WithSubsystem("decision"),
-			otelmetrics.WithServerName(cfg.Address()),
-		),
-		cachemiddleware.New(cch),
-	).Then(service.NewHandler(newContextFactory(signer, acceptedCode), exec, eh))
+	// the respond and trusted_proxies settings are taken from the current configuration
+	// and are applied on configuration reloads as well
+	hc := reloadable.NewHandler(conf, watcher, func(conf *config.Configuration) http.Handler {
+		cfg := conf.Serve.Decision
+		eh := errorhandler.New(
+			errorhandler.WithVerboseErrors(cfg.Respond.Verbose),
+			errorhandler.WithPreconditionErrorCode(cfg.Respond.With.ArgumentError.Code),
+			errorhandler.WithAuthenticationErrorCode(cfg.Respond.With.AuthenticationError.Code),
+			errorhandler.WithAuthorizationErrorCode(cfg.Respond.With.AuthorizationError.Code),
+			errorhandler.WithCommunicationErrorCode(cfg.Respond.With.CommunicationError.Code),
+			errorhandler.WithMethodErrorCode(cfg.Respond.With.BadMethodError.Code),
+			errorhandler.WithNoRuleErrorCode(cfg.Respond.With.NoRuleError.Code),
+			errorhandler.WithInternalServerErrorCode(cfg.Respond.With.InternalError.Code),
+		)
+		acceptedCode := x.IfThenElse(cfg.Respond.With.Accepted.Code != 0,
+			cfg.Respond.With.Accepted.Code, http.StatusOK)
+
+		return alice.New(
+			trustedproxy.New(
+				log,
+				x.IfThenElseExec(cfg.TrustedProxies != nil,
+					func() []string { return *cfg.TrustedProxies },
+					func() []string { return []string{} },
+				)...,
+			),
+			accesslog.New(log),
+			auditmiddleware.New(auditor),
+			logger.New(log),
+			dump.New(),
+			recovery.New(eh),
+			otelhttp.NewMiddleware("",
+				otelhttp.WithServerName(address),
+				otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
+					return fmt.Sprintf("EntryPoint %s %s%s",
+						strings.ToLower(req.URL.Scheme), httpx.LocalAddress(req), req.URL.Path)
+				}),
+			),
+			otelmetrics.New(
+				otelmetrics.WithSubsystem("decision"),
+				otelmetrics.WithServerName(address),
+			),
+			cachemiddleware.New(cch),
+		).Then(service.NewHandler(newContextFactory(signer, acceptedCode), exec, eh))
+	})
 
 	return &http.Server{
 		Handler:        hc,
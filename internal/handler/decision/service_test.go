@@ -29,6 +29,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/dadrus/heimdall/internal/audit"
 	"github.com/dadrus/heimdall/internal/cache/mocks"
 	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/handler/listener"
@@ -565,7 +566,7 @@ func TestHandleDecisionEndpointRequest(t *testing.T) {
 
 			client := &http.Client{Transport: &http.Transport{}}
 
-			decision := newService(conf, cch, log.Logger, exec, nil)
+			decision := newService(conf, config.NewWatcher(false), cch, log.Logger, exec, nil, audit.NewNoopAuditor())
 			defer decision.Shutdown(context.Background())
 
 			go func() {
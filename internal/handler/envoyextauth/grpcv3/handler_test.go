@@ -33,6 +33,7 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/test/bufconn"
 
+	"github.com/dadrus/heimdall/internal/audit"
 	"github.com/dadrus/heimdall/internal/cache/mocks"
 	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/heimdall"
@@ -204,7 +205,7 @@ func TestHandleDecisionEndpointRequest(t *testing.T) {
 
 			tc.configureMocks(t, exec)
 
-			srv := newService(conf, cch, log.Logger, exec, nil)
+			srv := newService(conf, config.NewWatcher(false), cch, log.Logger, exec, nil, audit.NewNoopAuditor())
 
 			defer srv.Stop()
 
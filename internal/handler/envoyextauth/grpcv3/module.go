@@ -22,6 +22,7 @@ import (
 	"github.com/rs/zerolog"
 	"go.uber.org/fx"
 
+	"github.com/dadrus/heimdall/internal/audit"
 	"github.com/dadrus/heimdall/internal/cache"
 	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/handler/fxlcm"
@@ -39,10 +40,12 @@ var Module = fx.Invoke( // nolint: gochecknoglobals
 
 func newLifecycleManager(
 	conf *config.Configuration,
+	watcher *config.Watcher,
 	logger zerolog.Logger,
 	exec rule.Executor,
 	signer heimdall.JWTSigner,
 	cch cache.Cache,
+	auditor audit.Auditor,
 ) *fxlcm.LifecycleManager {
 	cfg := conf.Serve.Decision
 
@@ -50,7 +53,7 @@ func newLifecycleManager(
 		ServiceName:    "Decision Envoy ExtAuth",
 		ServiceAddress: cfg.Address(),
 		Server: &adapter{
-			s: newService(conf, cch, logger, exec, signer),
+			s: newService(conf, watcher, cch, logger, exec, signer, auditor),
 		},
 		Logger:  logger,
 		TLSConf: cfg.TLS,
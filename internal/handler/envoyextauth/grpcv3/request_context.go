@@ -18,6 +18,7 @@ package grpcv3
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -30,8 +31,10 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 
+	"github.com/dadrus/heimdall/internal/handler/requestcontext"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/rules/mechanisms/contenttype"
+	"github.com/dadrus/heimdall/internal/truststore"
 	"github.com/dadrus/heimdall/internal/x"
 )
 
@@ -40,15 +43,18 @@ type RequestContext struct {
 	ips             []string
 	reqMethod       string
 	reqHeaders      map[string]string
-	reqURL          *url.URL
+	reqURL          *heimdall.URL
 	reqBody         string
 	reqRawBody      []byte
+	reqClientCert   string
 	upstreamHeaders http.Header
 	upstreamCookies map[string]string
+	upstreamSigners []heimdall.UpstreamRequestSigner
 	jwtSigner       heimdall.JWTSigner
 	err             error
 
-	savedBody any
+	savedBody   any
+	clientCerts []*x509.Certificate
 }
 
 func NewRequestContext(ctx context.Context, req *envoy_auth.CheckRequest, signer heimdall.JWTSigner) *RequestContext {
@@ -66,15 +72,16 @@ func NewRequestContext(ctx context.Context, req *envoy_auth.CheckRequest, signer
 		ips:        clientIPs,
 		reqMethod:  req.GetAttributes().GetRequest().GetHttp().GetMethod(),
 		reqHeaders: canonicalizeHeaders(req.GetAttributes().GetRequest().GetHttp().GetHeaders()),
-		reqURL: &url.URL{
+		reqURL: &heimdall.URL{URL: url.URL{
 			Scheme:   req.GetAttributes().GetRequest().GetHttp().GetScheme(),
 			Host:     req.GetAttributes().GetRequest().GetHttp().GetHost(),
 			Path:     req.GetAttributes().GetRequest().GetHttp().GetPath(),
 			RawQuery: req.GetAttributes().GetRequest().GetHttp().GetQuery(),
 			Fragment: req.GetAttributes().GetRequest().GetHttp().GetFragment(),
-		},
+		}},
 		reqBody:         req.GetAttributes().GetRequest().GetHttp().GetBody(),
 		reqRawBody:      req.GetAttributes().GetRequest().GetHttp().GetRawBody(),
+		reqClientCert:   req.GetAttributes().GetSource().GetCertificate(),
 		jwtSigner:       signer,
 		upstreamHeaders: make(http.Header),
 		upstreamCookies: make(map[string]string),
@@ -103,6 +110,16 @@ func (r *RequestContext) Request() *heimdall.Request {
 func (r *RequestContext) Headers() map[string]string { return r.reqHeaders }
 func (r *RequestContext) Header(name string) string  { return r.reqHeaders[name] }
 
+// HeaderValues returns the value of the given header as a single entry, as envoy
+// already joins the values of repeated headers.
+func (r *RequestContext) HeaderValues(name string) []string {
+	if value, ok := r.reqHeaders[name]; ok {
+		return []string{value}
+	}
+
+	return nil
+}
+
 func (r *RequestContext) Cookie(name string) string {
 	values, ok := r.reqHeaders["Cookie"]
 	if !ok {
@@ -118,6 +135,26 @@ func (r *RequestContext) Cookie(name string) string {
 	return ""
 }
 
+func (r *RequestContext) ClientCertificates() []*x509.Certificate {
+	if r.clientCerts == nil && len(r.reqClientCert) != 0 {
+		// envoy sends the certificate of the downstream peer URL encoded in PEM format.
+		// PathUnescape is used as the PEM contents may include "+" characters.
+		pemData, err := url.PathUnescape(r.reqClientCert)
+		if err != nil {
+			return nil
+		}
+
+		certs, err := truststore.NewTrustStoreFromPEMBytes([]byte(pemData), true)
+		if err != nil {
+			return nil
+		}
+
+		r.clientCerts = certs
+	}
+
+	return r.clientCerts
+}
+
 func (r *RequestContext) Body() any {
 	if r.savedBody == nil {
 		decoder, err := contenttype.NewDecoder(r.Header("Content-Type"))
@@ -140,12 +177,26 @@ func (r *RequestContext) Body() any {
 	return r.savedBody
 }
 
+// RawBody returns the body of the request as forwarded by envoy. Depending on the configuration
+// of envoy, it is either sent as bytes or as string.
+func (r *RequestContext) RawBody() []byte {
+	if len(r.reqRawBody) != 0 {
+		return r.reqRawBody
+	}
+
+	return []byte(r.reqBody)
+}
+
 func (r *RequestContext) AppContext() context.Context             { return r.ctx }
 func (r *RequestContext) SetPipelineError(err error)              { r.err = err }
 func (r *RequestContext) AddHeaderForUpstream(name, value string) { r.upstreamHeaders.Add(name, value) }
 func (r *RequestContext) AddCookieForUpstream(name, value string) { r.upstreamCookies[name] = value }
 func (r *RequestContext) Signer() heimdall.JWTSigner              { return r.jwtSigner }
 
+func (r *RequestContext) AddUpstreamRequestSigner(signer heimdall.UpstreamRequestSigner) {
+	r.upstreamSigners = append(r.upstreamSigners, signer)
+}
+
 func (r *RequestContext) Finalize() (*envoy_auth.CheckResponse, error) {
 	if r.err != nil {
 		return nil, r.err
@@ -153,6 +204,10 @@ func (r *RequestContext) Finalize() (*envoy_auth.CheckResponse, error) {
 
 	zerolog.Ctx(r.ctx).Debug().Msg("Creating response")
 
+	if err := r.signUpstreamRequest(); err != nil {
+		return nil, err
+	}
+
 	headers := make([]*envoy_core.HeaderValueOption,
 		len(r.upstreamHeaders)+x.IfThenElse(len(r.upstreamCookies) == 0, 0, 1))
 	hidx := 0
@@ -192,3 +247,42 @@ func (r *RequestContext) Finalize() (*envoy_auth.CheckResponse, error) {
 		},
 	}, nil
 }
+
+// signUpstreamRequest applies the signers registered by the finalizers to the request, as it
+// is forwarded by envoy, including the headers set by the finalizers. The resulting headers
+// are added to the headers for the upstream.
+func (r *RequestContext) signUpstreamRequest() error {
+	if len(r.upstreamSigners) == 0 {
+		return nil
+	}
+
+	headers := make(http.Header, len(r.reqHeaders)+len(r.upstreamHeaders))
+
+	for k, v := range r.reqHeaders {
+		headers.Set(k, v)
+	}
+
+	for k := range r.upstreamHeaders {
+		headers.Set(k, strings.Join(r.upstreamHeaders.Values(k), ","))
+	}
+
+	reqURL := r.reqURL.URL
+
+	signatureHeaders, err := requestcontext.SignUpstreamRequest(&http.Request{
+		Method: r.reqMethod,
+		URL:    &reqURL,
+		Host:   reqURL.Host,
+		Header: headers,
+	}, r.upstreamSigners)
+	if err != nil {
+		return err
+	}
+
+	for k, values := range signatureHeaders {
+		for _, v := range values {
+			r.upstreamHeaders.Add(k, v)
+		}
+	}
+
+	return nil
+}
@@ -20,8 +20,10 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_auth "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
@@ -32,12 +34,20 @@ import (
 
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/x/pkix/pemx"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
 )
 
 func TestNewRequestContext(t *testing.T) {
 	t.Parallel()
 
 	// GIVEN
+	ca, err := testsupport.NewRootCA("Test Root CA", 24*time.Hour)
+	require.NoError(t, err)
+
+	certPEM, err := pemx.BuildPEM(pemx.WithX509Certificate(ca.Certificate))
+	require.NoError(t, err)
+
 	httpReq := &envoy_auth.AttributeContext_HttpRequest{
 		Method:   http.MethodPatch,
 		Scheme:   "https",
@@ -58,6 +68,9 @@ func TestNewRequestContext(t *testing.T) {
 			Request: &envoy_auth.AttributeContext_Request{
 				Http: httpReq,
 			},
+			Source: &envoy_auth.AttributeContext_Peer{
+				Certificate: url.PathEscape(string(certPEM)),
+			},
 		},
 	}
 	md := metadata.New(nil)
@@ -89,6 +102,8 @@ func TestNewRequestContext(t *testing.T) {
 	require.NotNil(t, ctx.AppContext())
 	require.NotNil(t, ctx.Signer())
 	assert.Equal(t, []string{"127.0.0.1", "192.168.1.1"}, ctx.Request().ClientIPAddresses)
+	require.Len(t, ctx.Request().ClientCertificates(), 1)
+	assert.True(t, ca.Certificate.Equal(ctx.Request().ClientCertificates()[0]))
 }
 
 func TestFinalizeRequestContext(t *testing.T) {
@@ -194,6 +209,50 @@ func TestFinalizeRequestContext(t *testing.T) {
 				assert.Equal(t, "some-cookie=value-1", header.GetValue())
 			},
 		},
+		{
+			uc: "successful with upstream request signer",
+			updateContext: func(t *testing.T, ctx heimdall.Context) {
+				t.Helper()
+
+				ctx.AddHeaderForUpstream("x-for-upstream", "some-value")
+				ctx.AddUpstreamRequestSigner(func(req *heimdall.Request) (http.Header, error) {
+					return http.Header{"X-Signature": []string{
+						req.Method + " " + req.URL.Host + req.URL.Path + " " +
+							req.Header("X-Foo-Bar") + " " + req.Header("X-For-Upstream"),
+					}}, nil
+				})
+			},
+			assert: func(t *testing.T, err error, response *envoy_auth.CheckResponse) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, response)
+
+				okResponse := response.GetOkResponse()
+				require.NotNil(t, okResponse)
+
+				require.Len(t, okResponse.GetHeaders(), 2)
+				header := findHeader(okResponse.GetHeaders(), "X-Signature")
+				require.NotNil(t, header)
+				assert.Equal(t, "PATCH foo.bar:8080/test barfoo some-value", header.GetValue())
+			},
+		},
+		{
+			uc: "erroneous upstream request signer",
+			updateContext: func(t *testing.T, ctx heimdall.Context) {
+				t.Helper()
+
+				ctx.AddUpstreamRequestSigner(func(_ *heimdall.Request) (http.Header, error) {
+					return nil, heimdall.ErrArgument
+				})
+			},
+			assert: func(t *testing.T, err error, response *envoy_auth.CheckResponse) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				require.Nil(t, response)
+			},
+		},
 		{
 			uc: "erroneous with header and cookie",
 			updateContext: func(t *testing.T, ctx heimdall.Context) {
@@ -26,23 +26,28 @@ import (
 	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/status"
 
+	"github.com/dadrus/heimdall/internal/audit"
 	"github.com/dadrus/heimdall/internal/cache"
 	"github.com/dadrus/heimdall/internal/config"
 	accesslogmiddleware "github.com/dadrus/heimdall/internal/handler/middleware/grpc/accesslog"
+	auditmiddleware "github.com/dadrus/heimdall/internal/handler/middleware/grpc/audit"
 	cachemiddleware "github.com/dadrus/heimdall/internal/handler/middleware/grpc/cache"
 	"github.com/dadrus/heimdall/internal/handler/middleware/grpc/errorhandler"
 	loggermiddleware "github.com/dadrus/heimdall/internal/handler/middleware/grpc/logger"
 	"github.com/dadrus/heimdall/internal/handler/middleware/grpc/otelmetrics"
+	"github.com/dadrus/heimdall/internal/handler/reloadable"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/rules/rule"
 )
 
 func newService(
 	conf *config.Configuration,
+	watcher *config.Watcher,
 	cch cache.Cache,
 	logger zerolog.Logger,
 	exec rule.Executor,
 	signer heimdall.JWTSigner,
+	auditor audit.Auditor,
 ) *grpc.Server {
 	service := conf.Serve.Decision
 	accessLogger := accesslogmiddleware.New(logger)
@@ -65,22 +70,33 @@ func newService(
 		metrics.UnaryServerInterceptor(),
 	}
 
+	// the respond settings are taken from the current configuration and are applied
+	// on configuration reloads as well
+	errorHandler := reloadable.NewUnaryInterceptor(conf, watcher,
+		func(conf *config.Configuration) grpc.UnaryServerInterceptor {
+			service := conf.Serve.Decision
+
+			return errorhandler.New(
+				errorhandler.WithVerboseErrors(service.Respond.Verbose),
+				errorhandler.WithPreconditionErrorCode(service.Respond.With.ArgumentError.Code),
+				errorhandler.WithAuthenticationErrorCode(service.Respond.With.AuthenticationError.Code),
+				errorhandler.WithAuthorizationErrorCode(service.Respond.With.AuthorizationError.Code),
+				errorhandler.WithCommunicationErrorCode(service.Respond.With.CommunicationError.Code),
+				errorhandler.WithMethodErrorCode(service.Respond.With.BadMethodError.Code),
+				errorhandler.WithNoRuleErrorCode(service.Respond.With.NoRuleError.Code),
+				errorhandler.WithInternalServerErrorCode(service.Respond.With.InternalError.Code),
+			)
+		},
+	)
+
 	unaryInterceptors = append(unaryInterceptors,
-		errorhandler.New(
-			errorhandler.WithVerboseErrors(service.Respond.Verbose),
-			errorhandler.WithPreconditionErrorCode(service.Respond.With.ArgumentError.Code),
-			errorhandler.WithAuthenticationErrorCode(service.Respond.With.AuthenticationError.Code),
-			errorhandler.WithAuthorizationErrorCode(service.Respond.With.AuthorizationError.Code),
-			errorhandler.WithCommunicationErrorCode(service.Respond.With.CommunicationError.Code),
-			errorhandler.WithMethodErrorCode(service.Respond.With.BadMethodError.Code),
-			errorhandler.WithNoRuleErrorCode(service.Respond.With.NoRuleError.Code),
-			errorhandler.WithInternalServerErrorCode(service.Respond.With.InternalError.Code),
-		),
+		errorHandler.Unary(),
 		// the accesslogger is used here to have access to the error object
 		// as it will be replaced by a CheckResponse object returned to envoy
 		// and will not contain all the details, typically required to enable
 		// error traceback
 		accessLogger.Unary(),
+		auditmiddleware.New(auditor),
 		loggermiddleware.New(logger),
 		cachemiddleware.New(cch),
 	)
@@ -112,7 +112,7 @@ func newTLSListener(tlsConf *config.TLS, listener net.Listener) (net.Listener, e
 		err   error
 	)
 
-	ks, err := keystore.NewKeyStoreFromPEMFile(tlsConf.KeyStore.Path, tlsConf.KeyStore.Password)
+	ks, err := keystore.NewKeyStore(tlsConf.KeyStore)
 	if err != nil {
 		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed loading keystore").
 			CausedBy(err)
@@ -139,6 +139,7 @@ func newTLSListener(tlsConf *config.TLS, listener net.Listener) (net.Listener, e
 		Certificates: []tls.Certificate{cert},
 		MinVersion:   tlsConf.MinVersion.OrDefault(),
 		NextProtos:   []string{"h2", "http/1.1"},
+		ClientAuth:   tlsConf.ClientAuth.Type(),
 	}
 
 	if cfg.MinVersion != tls.VersionTLS13 {
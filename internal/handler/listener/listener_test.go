@@ -212,6 +212,45 @@ func TestNewListener(t *testing.T) {
 				assert.Contains(t, ln.Addr().String(), port)
 			},
 		},
+		{
+			uc:      "successful with client certificate requested",
+			network: "tcp",
+			serviceConf: config.ServiceConfig{
+				TLS: &config.TLS{
+					KeyStore:   config.KeyStore{Path: pemFile.Name()},
+					KeyID:      "key1",
+					ClientAuth: config.TLSClientAuthRequest,
+				},
+			},
+			assert: func(t *testing.T, err error, ln net.Listener, port string) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, ln)
+
+				go func() {
+					con, err := ln.Accept()
+					if err == nil {
+						_ = con.(*tls.Conn).Handshake() //nolint:forcetypeassert
+						con.Close()
+					}
+				}()
+
+				requested := false
+				con, err := tls.Dial("tcp", ln.Addr().String(), &tls.Config{
+					InsecureSkipVerify: true, //nolint:gosec
+					GetClientCertificate: func(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+						requested = true
+
+						return &tls.Certificate{}, nil
+					},
+				})
+				require.NoError(t, err)
+				con.Close()
+
+				assert.True(t, requested)
+			},
+		},
 	} {
 		t.Run(tc.uc, func(t *testing.T) {
 			// GIVEN
@@ -17,6 +17,13 @@
 package management
 
 const (
-	EndpointHealth = "/.well-known/health"
-	EndpointJWKS   = "/.well-known/jwks"
+	EndpointHealth          = "/.well-known/health"
+	EndpointLiveness        = "/.well-known/health/live"
+	EndpointReadiness       = "/.well-known/health/ready"
+	EndpointUpstreamsHealth = "/.well-known/health/upstreams"
+	EndpointJWKS            = "/.well-known/jwks"
+	EndpointRuleSets        = "/rulesets"
+	EndpointRules           = "/rules"
+	EndpointProviders       = "/providers"
+	EndpointExplain         = "/explain"
 )
@@ -0,0 +1,216 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package management
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/goccy/go-json"
+
+	"github.com/dadrus/heimdall/internal/accesscontext"
+	"github.com/dadrus/heimdall/internal/handler/requestcontext"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/upstream"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/httpx"
+)
+
+const redactedValue = "[REDACTED]"
+
+type explainRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	Cookies map[string]string `json:"cookies"`
+	Body    string            `json:"body"`
+	Stubs   []httpx.Stub      `json:"stubs"`
+	Offline *bool             `json:"offline"`
+}
+
+type explainedRule struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+}
+
+type explainedStep struct {
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	ConditionMet bool   `json:"condition_met"`
+	Outcome      string `json:"outcome"`
+	Error        string `json:"error,omitempty"`
+}
+
+type explainedUpstream struct {
+	URL     string            `json:"url,omitempty"`
+	Headers http.Header       `json:"headers"`
+	Cookies map[string]string `json:"cookies"`
+}
+
+type explainResponse struct {
+	Rule     *explainedRule    `json:"rule,omitempty"`
+	Steps    []explainedStep   `json:"steps"`
+	Upstream explainedUpstream `json:"upstream"`
+	Status   int               `json:"status"`
+	Location string            `json:"location,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// explain implements an endpoint, which executes the rule matching the synthetic request
+// given in the request body and reports what happened without forwarding anything to
+// the upstream service. Remote calls done by the pipeline mechanisms are answered by the
+// given stubs. Unless explicitly requested and allowed by the configuration, remote calls
+// not matching any stub fail. The values of the headers and cookies created for the upstream
+// service are redacted, as these usually carry credentials, like tokens.
+func (h *handler) explain(rw http.ResponseWriter, req *http.Request) {
+	var exReq explainRequest
+
+	if err := json.NewDecoder(req.Body).Decode(&exReq); err != nil {
+		h.eh.HandleError(rw, req, errorchain.NewWithMessage(heimdall.ErrArgument,
+			"failed to decode explain request").CausedBy(err))
+
+		return
+	}
+
+	synReq, err := exReq.toHTTPRequest(req, h.allowRemote)
+	if err != nil {
+		h.eh.HandleError(rw, req, err)
+
+		return
+	}
+
+	rc := requestcontext.New(h.xs, synReq)
+
+	// the upstream target is not selected (see toHTTPRequest). So there is no need
+	// to report the outcome of the communication with it via Done.
+	backend, err := h.e.Execute(rc)
+
+	resp := explainResponse{
+		Steps: []explainedStep{},
+		Upstream: explainedUpstream{
+			Headers: make(http.Header),
+			Cookies: make(map[string]string),
+		},
+		Status: h.acceptedCode,
+	}
+
+	for name, values := range rc.UpstreamHeaders() {
+		for range values {
+			resp.Upstream.Headers.Add(name, redactedValue)
+		}
+	}
+
+	for name := range rc.UpstreamCookies() {
+		resp.Upstream.Cookies[name] = redactedValue
+	}
+
+	if id, source := accesscontext.Rule(synReq.Context()); len(id) != 0 {
+		resp.Rule = &explainedRule{ID: id, Source: source}
+	}
+
+	for _, result := range accesscontext.Mechanisms(synReq.Context()) {
+		step := explainedStep{
+			ID:           result.ID,
+			Type:         result.Type,
+			ConditionMet: result.Outcome != "skipped",
+			Outcome:      result.Outcome,
+		}
+
+		if result.Err != nil {
+			step.Error = result.Err.Error()
+		}
+
+		resp.Steps = append(resp.Steps, step)
+	}
+
+	if backend != nil {
+		resp.Upstream.URL = backend.URL().String()
+	}
+
+	if err != nil {
+		recorder := &statusRecorder{header: make(http.Header)}
+		h.deh.HandleError(recorder, synReq, err)
+
+		resp.Status = recorder.code
+		resp.Location = recorder.header.Get("Location")
+		resp.Error = err.Error()
+	}
+
+	h.writeJSON(rw, req, resp)
+}
+
+func (r explainRequest) toHTTPRequest(req *http.Request, allowRemote bool) (*http.Request, error) {
+	reqURL, err := url.Parse(r.URL)
+	if err != nil || !reqURL.IsAbs() {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrArgument,
+			"explain request requires an absolute url, got '%s'", r.URL)
+	}
+
+	method := strings.ToUpper(r.Method)
+	if len(method) == 0 {
+		method = http.MethodGet
+	}
+
+	offline := r.Offline == nil || *r.Offline
+	if !offline && !allowRemote {
+		return nil, errorchain.NewWithMessage(heimdall.ErrArgument,
+			"remote calls are not allowed while explaining requests")
+	}
+
+	// the synthetic request is never forwarded. So there is no need to select an upstream target
+	ctx := upstream.WithoutTargetSelection(accesscontext.New(req.Context()))
+	if len(r.Stubs) != 0 || offline {
+		ctx = httpx.WithStubs(ctx, r.Stubs, offline)
+	}
+
+	synReq, err := http.NewRequestWithContext(ctx, method, reqURL.String(), strings.NewReader(r.Body))
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrArgument,
+			"failed to create request from explain request").CausedBy(err)
+	}
+
+	if reqURL.Scheme == "https" {
+		// the scheme of the inbound request is derived from the used connection
+		synReq.TLS = &tls.ConnectionState{}
+	}
+
+	for name, value := range r.Headers {
+		if strings.EqualFold(name, "Host") {
+			synReq.Host = value
+		} else {
+			synReq.Header.Set(name, value)
+		}
+	}
+
+	for name, value := range r.Cookies {
+		synReq.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+
+	return synReq, nil
+}
+
+// statusRecorder captures the status code and the headers an error handler would respond with.
+type statusRecorder struct {
+	header http.Header
+	code   int
+}
+
+func (r *statusRecorder) Header() http.Header            { return r.header }
+func (r *statusRecorder) Write(data []byte) (int, error) { return len(data), nil }
+func (r *statusRecorder) WriteHeader(code int)           { r.code = code }
@@ -18,6 +18,7 @@ package management
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/go-http-utils/etag"
 	"github.com/goccy/go-json"
@@ -25,15 +26,39 @@ import (
 	"github.com/rs/zerolog"
 	"gopkg.in/square/go-jose.v2"
 
+	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/handler/middleware/http/errorhandler"
 	"github.com/dadrus/heimdall/internal/handler/middleware/http/methodfilter"
+	"github.com/dadrus/heimdall/internal/health"
 	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/rule"
+	"github.com/dadrus/heimdall/internal/rules/upstream"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
 )
 
-func newManagementHandler(signer heimdall.JWTSigner, eh errorhandler.ErrorHandler) http.Handler {
+func newManagementHandler(
+	signer heimdall.JWTSigner,
+	explainSigner heimdall.JWTSigner,
+	upstreams *upstream.Registry,
+	readiness *health.Registry,
+	inspector rule.Inspector,
+	exec rule.Executor,
+	eh errorhandler.ErrorHandler,
+	decisionEH errorhandler.ErrorHandler,
+	acceptedCode int,
+	conf config.ServiceConfig,
+) http.Handler {
 	mh := &handler{
-		s:  signer,
-		eh: eh,
+		s:            signer,
+		xs:           explainSigner,
+		u:            upstreams,
+		r:            readiness,
+		i:            inspector,
+		e:            exec,
+		eh:           eh,
+		deh:          decisionEH,
+		acceptedCode: acceptedCode,
+		allowRemote:  conf.Explain.AllowRemoteCalls,
 	}
 
 	mux := http.NewServeMux()
@@ -41,16 +66,56 @@ func newManagementHandler(signer heimdall.JWTSigner, eh errorhandler.ErrorHandle
 	mux.Handle(EndpointHealth,
 		alice.New(methodfilter.New(http.MethodGet)).
 			Then(http.HandlerFunc(mh.health)))
+	mux.Handle(EndpointLiveness,
+		alice.New(methodfilter.New(http.MethodGet)).
+			Then(http.HandlerFunc(mh.health)))
+	mux.Handle(EndpointReadiness,
+		alice.New(methodfilter.New(http.MethodGet)).
+			Then(http.HandlerFunc(mh.readiness)))
+	mux.Handle(EndpointUpstreamsHealth,
+		alice.New(methodfilter.New(http.MethodGet)).
+			Then(http.HandlerFunc(mh.upstreamsHealth)))
 	mux.Handle(EndpointJWKS,
 		alice.New(methodfilter.New(http.MethodGet)).
 			Then(etag.Handler(http.HandlerFunc(mh.jwks), false)))
 
+	// the endpoints below expose the rule configuration and are for this reason
+	// available only if explicitly enabled
+	if conf.Inspection.Enabled {
+		mux.Handle(EndpointRuleSets,
+			alice.New(methodfilter.New(http.MethodGet)).
+				Then(http.HandlerFunc(mh.ruleSets)))
+		mux.Handle(EndpointRules,
+			alice.New(methodfilter.New(http.MethodGet)).
+				Then(http.HandlerFunc(mh.rules)))
+		mux.Handle(EndpointRules+"/",
+			alice.New(methodfilter.New(http.MethodGet)).
+				Then(http.HandlerFunc(mh.rule)))
+		mux.Handle(EndpointProviders,
+			alice.New(methodfilter.New(http.MethodGet)).
+				Then(http.HandlerFunc(mh.providers)))
+	}
+
+	if conf.Explain.Enabled {
+		mux.Handle(EndpointExplain,
+			alice.New(methodfilter.New(http.MethodPost)).
+				Then(http.HandlerFunc(mh.explain)))
+	}
+
 	return mux
 }
 
 type handler struct {
-	s  heimdall.JWTSigner
-	eh errorhandler.ErrorHandler
+	s            heimdall.JWTSigner
+	xs           heimdall.JWTSigner
+	u            *upstream.Registry
+	r            *health.Registry
+	i            rule.Inspector
+	e            rule.Executor
+	eh           errorhandler.ErrorHandler
+	deh          errorhandler.ErrorHandler
+	acceptedCode int
+	allowRemote  bool
 }
 
 // jwks implements an endpoint returning JWKS objects according to
@@ -84,3 +149,105 @@ func (h *handler) health(rw http.ResponseWriter, req *http.Request) {
 	rw.Header().Set("Content-Type", "application/json")
 	_, _ = rw.Write(res)
 }
+
+// readiness implements an endpoint reporting whether heimdall is ready to serve requests.
+// It responds with 503 as long as any of the registered components, like the configured
+// rule providers, the signer or the cache, is not ready.
+func (h *handler) readiness(rw http.ResponseWriter, req *http.Request) {
+	report := h.r.Check(req.Context())
+
+	res, err := json.Marshal(report)
+	if err != nil {
+		zerolog.Ctx(req.Context()).Error().Err(err).Msg("Failed to marshal readiness report")
+		h.eh.HandleError(rw, req, err)
+
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	if !report.Ready() {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	_, _ = rw.Write(res)
+}
+
+// upstreamsHealth implements an endpoint returning the state of all upstream
+// targets, the currently loaded rules forward requests to.
+func (h *handler) upstreamsHealth(rw http.ResponseWriter, req *http.Request) {
+	type status struct {
+		Upstreams []upstream.TargetState `json:"upstreams"`
+	}
+
+	res, err := json.Marshal(status{Upstreams: h.u.Targets()})
+	if err != nil {
+		zerolog.Ctx(req.Context()).Error().Err(err).Msg("Failed to marshal upstreams status object")
+		h.eh.HandleError(rw, req, err)
+
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	_, _ = rw.Write(res)
+}
+
+// ruleSets implements an endpoint listing the currently loaded rule sets.
+func (h *handler) ruleSets(rw http.ResponseWriter, req *http.Request) {
+	type response struct {
+		RuleSets []rule.SetInfo `json:"rule_sets"`
+	}
+
+	h.writeJSON(rw, req, response{RuleSets: h.i.RuleSets()})
+}
+
+// rules implements an endpoint listing the currently loaded rules.
+func (h *handler) rules(rw http.ResponseWriter, req *http.Request) {
+	type response struct {
+		Rules []rule.Info `json:"rules"`
+	}
+
+	h.writeJSON(rw, req, response{Rules: h.i.Rules()})
+}
+
+// rule implements an endpoint returning a single rule. As rule ids are only unique within
+// a rule set, the rule set can be selected by making use of the src query parameter.
+func (h *handler) rule(rw http.ResponseWriter, req *http.Request) {
+	id := strings.TrimPrefix(req.URL.Path, EndpointRules+"/")
+	srcID := req.URL.Query().Get("src")
+
+	rules := h.i.Rule(id, srcID)
+
+	switch len(rules) {
+	case 0:
+		h.eh.HandleError(rw, req, errorchain.NewWithMessagef(heimdall.ErrNoRuleFound,
+			"no rule with id=%s found", id))
+	case 1:
+		h.writeJSON(rw, req, rules[0])
+	default:
+		h.eh.HandleError(rw, req, errorchain.NewWithMessagef(heimdall.ErrArgument,
+			"rule id=%s is defined in multiple rule sets, use the src query parameter to select one", id))
+	}
+}
+
+// providers implements an endpoint returning the state of the rule providers.
+func (h *handler) providers(rw http.ResponseWriter, req *http.Request) {
+	type response struct {
+		Providers []rule.ProviderStatus `json:"providers"`
+	}
+
+	h.writeJSON(rw, req, response{Providers: h.i.Providers()})
+}
+
+func (h *handler) writeJSON(rw http.ResponseWriter, req *http.Request, value any) {
+	res, err := json.Marshal(value)
+	if err != nil {
+		zerolog.Ctx(req.Context()).Error().Err(err).Msg("Failed to marshal response object")
+		h.eh.HandleError(rw, req, err)
+
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	_, _ = rw.Write(res)
+}
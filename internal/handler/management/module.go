@@ -24,7 +24,11 @@ import (
 
 	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/handler/fxlcm"
+	"github.com/dadrus/heimdall/internal/health"
 	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/rule"
+	"github.com/dadrus/heimdall/internal/rules/upstream"
+	"github.com/dadrus/heimdall/internal/signer"
 )
 
 var Module = fx.Invoke( // nolint: gochecknoglobals
@@ -37,16 +41,29 @@ var Module = fx.Invoke( // nolint: gochecknoglobals
 
 func newLifecycleManager(
 	conf *config.Configuration,
+	watcher *config.Watcher,
 	logger zerolog.Logger,
-	signer heimdall.JWTSigner,
-) *fxlcm.LifecycleManager {
+	jwtSigner heimdall.JWTSigner,
+	upstreams *upstream.Registry,
+	readiness *health.Registry,
+	inspector rule.Inspector,
+	exec rule.Executor,
+) (*fxlcm.LifecycleManager, error) {
 	cfg := conf.Serve.Management
 
+	// JWTs issued while explaining requests must not be accepted by the upstream services.
+	// That is why these are signed with a throwaway key not exposed via the JWKS endpoint.
+	explainSigner, err := signer.NewJWTSigner(
+		&config.Configuration{Signer: config.SignerConfig{Name: conf.Signer.Name}}, zerolog.Nop())
+	if err != nil {
+		return nil, err
+	}
+
 	return &fxlcm.LifecycleManager{
 		ServiceName:    "Management",
 		ServiceAddress: cfg.Address(),
-		Server:         newService(conf, logger, signer),
+		Server:         newService(conf, watcher, logger, jwtSigner, explainSigner, upstreams, readiness, inspector, exec),
 		Logger:         logger,
 		TLSConf:        cfg.TLS,
-	}
+	}, nil
 }
@@ -34,7 +34,11 @@ import (
 	"github.com/dadrus/heimdall/internal/handler/middleware/http/otelmetrics"
 	"github.com/dadrus/heimdall/internal/handler/middleware/http/passthrough"
 	"github.com/dadrus/heimdall/internal/handler/middleware/http/recovery"
+	"github.com/dadrus/heimdall/internal/handler/reloadable"
+	"github.com/dadrus/heimdall/internal/health"
 	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/rule"
+	"github.com/dadrus/heimdall/internal/rules/upstream"
 	"github.com/dadrus/heimdall/internal/x"
 	"github.com/dadrus/heimdall/internal/x/httpx"
 	"github.com/dadrus/heimdall/internal/x/loggeradapter"
@@ -42,47 +46,82 @@ import (
 
 func newService(
 	conf *config.Configuration,
+	watcher *config.Watcher,
 	log zerolog.Logger,
 	signer heimdall.JWTSigner,
+	explainSigner heimdall.JWTSigner,
+	upstreams *upstream.Registry,
+	readiness *health.Registry,
+	inspector rule.Inspector,
+	exec rule.Executor,
 ) *http.Server {
 	cfg := conf.Serve.Management
+	address := cfg.Address()
 	eh := errorhandler2.New()
-	opFilter := func(req *http.Request) bool { return req.URL.Path != EndpointHealth }
+	opFilter := func(req *http.Request) bool {
+		switch req.URL.Path {
+		case EndpointHealth, EndpointLiveness, EndpointReadiness, EndpointUpstreamsHealth:
+			return false
+		default:
+			return true
+		}
+	}
+
+	// the cors, inspection and explain settings, as well as the respond settings of the decision
+	// service are taken from the current configuration and are applied on configuration reloads
+	hc := reloadable.NewHandler(conf, watcher, func(conf *config.Configuration) http.Handler {
+		cfg := conf.Serve.Management
+		// the explain endpoint reports the status code the decision service would respond with
+		decisionCfg := conf.Serve.Decision
+		decisionEH := errorhandler2.New(
+			errorhandler2.WithVerboseErrors(decisionCfg.Respond.Verbose),
+			errorhandler2.WithPreconditionErrorCode(decisionCfg.Respond.With.ArgumentError.Code),
+			errorhandler2.WithAuthenticationErrorCode(decisionCfg.Respond.With.AuthenticationError.Code),
+			errorhandler2.WithAuthorizationErrorCode(decisionCfg.Respond.With.AuthorizationError.Code),
+			errorhandler2.WithCommunicationErrorCode(decisionCfg.Respond.With.CommunicationError.Code),
+			errorhandler2.WithMethodErrorCode(decisionCfg.Respond.With.BadMethodError.Code),
+			errorhandler2.WithNoRuleErrorCode(decisionCfg.Respond.With.NoRuleError.Code),
+			errorhandler2.WithInternalServerErrorCode(decisionCfg.Respond.With.InternalError.Code),
+		)
+		acceptedCode := x.IfThenElse(decisionCfg.Respond.With.Accepted.Code != 0,
+			decisionCfg.Respond.With.Accepted.Code, http.StatusOK)
 
-	hc := alice.New(
-		accesslog.New(log),
-		logger.New(log),
-		dump.New(),
-		recovery.New(eh),
-		otelhttp.NewMiddleware("",
-			otelhttp.WithServerName(cfg.Address()),
-			otelhttp.WithFilter(opFilter),
-			otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
-				return fmt.Sprintf("EntryPoint %s %s%s",
-					strings.ToLower(req.URL.Scheme), httpx.LocalAddress(req), req.URL.Path)
-			}),
-		),
-		otelmetrics.New(
-			otelmetrics.WithSubsystem("management"),
-			otelmetrics.WithServerName(cfg.Address()),
-			otelmetrics.WithOperationFilter(opFilter),
-		),
-		x.IfThenElseExec(cfg.CORS != nil,
-			func() func(http.Handler) http.Handler {
-				return cors.New(
-					cors.Options{
-						AllowedOrigins:   cfg.CORS.AllowedOrigins,
-						AllowedMethods:   cfg.CORS.AllowedMethods,
-						AllowedHeaders:   cfg.CORS.AllowedHeaders,
-						AllowCredentials: cfg.CORS.AllowCredentials,
-						ExposedHeaders:   cfg.CORS.ExposedHeaders,
-						MaxAge:           int(cfg.CORS.MaxAge.Seconds()),
-					},
-				).Handler
-			},
-			func() func(http.Handler) http.Handler { return passthrough.New },
-		),
-	).Then(newManagementHandler(signer, eh))
+		return alice.New(
+			accesslog.New(log),
+			logger.New(log),
+			dump.New(),
+			recovery.New(eh),
+			otelhttp.NewMiddleware("",
+				otelhttp.WithServerName(address),
+				otelhttp.WithFilter(opFilter),
+				otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
+					return fmt.Sprintf("EntryPoint %s %s%s",
+						strings.ToLower(req.URL.Scheme), httpx.LocalAddress(req), req.URL.Path)
+				}),
+			),
+			otelmetrics.New(
+				otelmetrics.WithSubsystem("management"),
+				otelmetrics.WithServerName(address),
+				otelmetrics.WithOperationFilter(opFilter),
+			),
+			x.IfThenElseExec(cfg.CORS != nil,
+				func() func(http.Handler) http.Handler {
+					return cors.New(
+						cors.Options{
+							AllowedOrigins:   cfg.CORS.AllowedOrigins,
+							AllowedMethods:   cfg.CORS.AllowedMethods,
+							AllowedHeaders:   cfg.CORS.AllowedHeaders,
+							AllowCredentials: cfg.CORS.AllowCredentials,
+							ExposedHeaders:   cfg.CORS.ExposedHeaders,
+							MaxAge:           int(cfg.CORS.MaxAge.Seconds()),
+						},
+					).Handler
+				},
+				func() func(http.Handler) http.Handler { return passthrough.New },
+			),
+		).Then(newManagementHandler(
+			signer, explainSigner, upstreams, readiness, inspector, exec, eh, decisionEH, acceptedCode, cfg))
+	})
 
 	return &http.Server{
 		Handler:        hc,
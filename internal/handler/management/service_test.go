@@ -25,18 +25,33 @@ import (
 	"crypto/x509/pkix"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/goccy/go-json"
 	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/metric/noop"
 	"gopkg.in/square/go-jose.v2"
 
+	"github.com/dadrus/heimdall/internal/accesscontext"
 	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/handler/listener"
+	"github.com/dadrus/heimdall/internal/handler/middleware/http/errorhandler"
+	"github.com/dadrus/heimdall/internal/health"
+	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/heimdall/mocks"
 	"github.com/dadrus/heimdall/internal/keystore"
+	rulesconfig "github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/rules/rule"
+	rulemocks "github.com/dadrus/heimdall/internal/rules/rule/mocks"
+	"github.com/dadrus/heimdall/internal/rules/upstream"
+	"github.com/dadrus/heimdall/internal/x/httpx"
 	"github.com/dadrus/heimdall/internal/x/pkix/pemx"
 	"github.com/dadrus/heimdall/internal/x/testsupport"
 )
@@ -48,10 +63,14 @@ type ServiceTestSuite struct {
 	ee1     *testsupport.EndEntity
 	ee2     *testsupport.EndEntity
 
-	srv    *http.Server
-	ks     keystore.KeyStore
-	signer *mocks.JWTSignerMock
-	addr   string
+	srv       *http.Server
+	ks        keystore.KeyStore
+	signer    *mocks.JWTSignerMock
+	upstreams *upstream.Registry
+	readiness *health.Registry
+	inspector *rulemocks.InspectorMock
+	executor  *rulemocks.ExecutorMock
+	addr      string
 }
 
 func (suite *ServiceTestSuite) SetupSuite() {
@@ -115,9 +134,11 @@ func (suite *ServiceTestSuite) SetupTest() {
 	conf := &config.Configuration{
 		Serve: config.ServeConfig{
 			Management: config.ServiceConfig{
-				Host: "127.0.0.1",
-				Port: port,
-				CORS: &config.CORS{},
+				Host:       "127.0.0.1",
+				Port:       port,
+				CORS:       &config.CORS{},
+				Inspection: config.InspectionConfig{Enabled: true},
+				Explain:    config.ExplainConfig{Enabled: true},
 			},
 		},
 		Metrics: config.MetricsConfig{Enabled: true},
@@ -128,7 +149,15 @@ func (suite *ServiceTestSuite) SetupTest() {
 	suite.addr = "http://" + listener.Addr().String()
 
 	suite.signer = mocks.NewJWTSignerMock(suite.T())
-	suite.srv = newService(conf, log.Logger, suite.signer)
+	suite.upstreams, err = upstream.NewRegistry(log.Logger, noop.NewMeterProvider())
+	suite.Require().NoError(err)
+
+	suite.readiness = health.NewRegistry()
+	suite.inspector = rulemocks.NewInspectorMock(suite.T())
+	suite.executor = rulemocks.NewExecutorMock(suite.T())
+
+	suite.srv = newService(conf, config.NewWatcher(false), log.Logger, suite.signer, mocks.NewJWTSignerMock(suite.T()),
+		suite.upstreams, suite.readiness, suite.inspector, suite.executor)
 
 	go func() {
 		err = suite.srv.Serve(listener)
@@ -268,3 +297,511 @@ func (suite *ServiceTestSuite) TestHealthRequest() {
 
 	suite.JSONEq(`{ "status": "ok"}`, string(rawResp))
 }
+
+func (suite *ServiceTestSuite) TestLivenessRequest() {
+	// GIVEN
+	client := &http.Client{Transport: &http.Transport{}}
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, suite.addr+"/.well-known/health/live", nil)
+	suite.Require().NoError(err)
+
+	// WHEN
+	resp, err := client.Do(req)
+
+	// THEN
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	defer resp.Body.Close()
+
+	rawResp, err := io.ReadAll(resp.Body)
+	suite.Require().NoError(err)
+
+	suite.JSONEq(`{ "status": "ok"}`, string(rawResp))
+}
+
+func (suite *ServiceTestSuite) TestReadinessRequest() {
+	// GIVEN
+	tracker := health.NewLoadTracker("rule_provider:http_endpoint", "http://foo.bar/rules")
+	suite.readiness.Register(health.NewChecker("signer", func(_ context.Context) error { return nil }))
+	suite.readiness.Register(tracker)
+
+	client := &http.Client{Transport: &http.Transport{}}
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, suite.addr+"/.well-known/health/ready", nil)
+	suite.Require().NoError(err)
+
+	// WHEN
+	resp, err := client.Do(req)
+
+	// THEN
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusServiceUnavailable, resp.StatusCode)
+
+	rawResp, err := io.ReadAll(resp.Body)
+	suite.Require().NoError(err)
+	resp.Body.Close()
+
+	suite.JSONEq(`{
+  "status": "not_ready",
+  "components": [
+    { "name": "signer", "status": "ready" },
+    {
+      "name": "rule_provider:http_endpoint",
+      "status": "not_ready",
+      "error": "initial load not completed for http://foo.bar/rules"
+    }
+  ]
+}`, string(rawResp))
+
+	// WHEN
+	tracker.Loaded("http://foo.bar/rules")
+	resp, err = client.Do(req)
+
+	// THEN
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	defer resp.Body.Close()
+
+	rawResp, err = io.ReadAll(resp.Body)
+	suite.Require().NoError(err)
+
+	suite.JSONEq(`{
+  "status": "ready",
+  "components": [
+    { "name": "signer", "status": "ready" },
+    { "name": "rule_provider:http_endpoint", "status": "ready" }
+  ]
+}`, string(rawResp))
+}
+
+func (suite *ServiceTestSuite) TestUpstreamsHealthRequest() {
+	// GIVEN
+	pool := suite.upstreams.NewPool(&rulesconfig.Backend{Targets: []string{"foo:8080", "bar:8080"}})
+	pool.Acquire()
+
+	defer pool.Release()
+
+	client := &http.Client{Transport: &http.Transport{}}
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet,
+		suite.addr+"/.well-known/health/upstreams", nil)
+	suite.Require().NoError(err)
+
+	// WHEN
+	resp, err := client.Do(req)
+
+	// THEN
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	defer resp.Body.Close()
+
+	rawResp, err := io.ReadAll(resp.Body)
+	suite.Require().NoError(err)
+
+	suite.JSONEq(`{
+  "upstreams": [
+    { "host": "bar:8080", "health": "unchecked", "ejected": false, "active_requests": 0 },
+    { "host": "foo:8080", "health": "unchecked", "ejected": false, "active_requests": 0 }
+  ]
+}`, string(rawResp))
+}
+
+func (suite *ServiceTestSuite) doGet(path string) (int, string) {
+	suite.T().Helper()
+
+	client := &http.Client{Transport: &http.Transport{}}
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, suite.addr+path, nil)
+	suite.Require().NoError(err)
+
+	resp, err := client.Do(req)
+	suite.Require().NoError(err)
+
+	defer resp.Body.Close()
+
+	rawResp, err := io.ReadAll(resp.Body)
+	suite.Require().NoError(err)
+
+	return resp.StatusCode, string(rawResp)
+}
+
+func (suite *ServiceTestSuite) TestRuleSetsRequest() {
+	// GIVEN
+	loadedAt := time.Date(2024, 5, 10, 9, 41, 17, 0, time.UTC)
+
+	suite.inspector.EXPECT().RuleSets().Return([]rule.SetInfo{
+		{
+			Source:   "file_system:/rules.yaml",
+			Provider: "file_system",
+			Name:     "test",
+			Hash:     "0102",
+			LoadedAt: loadedAt,
+			Rules:    []string{"bar", "foo"},
+		},
+	})
+
+	// WHEN
+	code, body := suite.doGet(EndpointRuleSets)
+
+	// THEN
+	suite.Equal(http.StatusOK, code)
+	suite.JSONEq(`{
+  "rule_sets": [{
+    "source": "file_system:/rules.yaml",
+    "provider": "file_system",
+    "name": "test",
+    "hash": "0102",
+    "loaded_at": "2024-05-10T09:41:17Z",
+    "rules": ["bar", "foo"]
+  }]
+}`, body)
+}
+
+func (suite *ServiceTestSuite) TestRulesRequest() {
+	// GIVEN
+	loadedAt := time.Date(2024, 5, 10, 9, 41, 17, 0, time.UTC)
+
+	suite.inspector.EXPECT().Rules().Return([]rule.Info{
+		{
+			ID:      "foo",
+			Source:  "file_system:/rules.yaml",
+			Match:   rulesconfig.Matcher{URL: "http://foo.bar/<**>", Strategy: "glob"},
+			Methods: []string{http.MethodGet},
+			Mechanisms: rule.MechanismsInfo{
+				Authenticators: []string{"jwt"},
+				Handlers:       []string{"allow_all"},
+			},
+			Hash:     "0102",
+			LoadedAt: loadedAt,
+		},
+	})
+
+	// WHEN
+	code, body := suite.doGet(EndpointRules)
+
+	// THEN
+	suite.Equal(http.StatusOK, code)
+	suite.JSONEq(`{
+  "rules": [{
+    "id": "foo",
+    "source": "file_system:/rules.yaml",
+    "match": { "url": "http://foo.bar/<**>", "strategy": "glob" },
+    "methods": ["GET"],
+    "mechanisms": { "authenticators": ["jwt"], "handlers": ["allow_all"] },
+    "hash": "0102",
+    "loaded_at": "2024-05-10T09:41:17Z"
+  }]
+}`, body)
+}
+
+func (suite *ServiceTestSuite) TestRuleRequest() {
+	for _, tc := range []struct {
+		uc     string
+		path   string
+		id     string
+		src    string
+		rules  []rule.Info
+		code   int
+		assert func(body string)
+	}{
+		{
+			uc:    "rule not found",
+			path:  EndpointRules + "/foo",
+			id:    "foo",
+			code:  http.StatusNotFound,
+			rules: []rule.Info{},
+		},
+		{
+			uc:    "rule id defined in multiple rule sets",
+			path:  EndpointRules + "/foo",
+			id:    "foo",
+			code:  http.StatusBadRequest,
+			rules: []rule.Info{{ID: "foo", Source: "bar"}, {ID: "foo", Source: "baz"}},
+		},
+		{
+			uc:    "rule found",
+			path:  EndpointRules + "/foo:bar?src=baz",
+			id:    "foo:bar",
+			src:   "baz",
+			code:  http.StatusOK,
+			rules: []rule.Info{{ID: "foo:bar", Source: "baz", Methods: []string{http.MethodGet}}},
+			assert: func(body string) {
+				suite.JSONEq(`{
+  "id": "foo:bar",
+  "source": "baz",
+  "match": { "url": "", "strategy": "" },
+  "methods": ["GET"],
+  "mechanisms": { "authenticators": null },
+  "hash": "",
+  "loaded_at": "0001-01-01T00:00:00Z"
+}`, body)
+			},
+		},
+	} {
+		suite.Run("case="+tc.uc, func() {
+			// GIVEN
+			suite.inspector.EXPECT().Rule(tc.id, tc.src).Return(tc.rules).Once()
+
+			// WHEN
+			code, body := suite.doGet(tc.path)
+
+			// THEN
+			suite.Equal(tc.code, code)
+
+			if tc.assert != nil {
+				tc.assert(body)
+			}
+		})
+	}
+}
+
+func (suite *ServiceTestSuite) TestProvidersRequest() {
+	// GIVEN
+	lastSuccess := time.Date(2024, 5, 10, 9, 41, 17, 0, time.UTC)
+	lastError := time.Date(2024, 5, 10, 9, 42, 17, 0, time.UTC)
+
+	suite.inspector.EXPECT().Providers().Return([]rule.ProviderStatus{
+		{Name: "file_system", RuleSets: 2, LastSuccess: &lastSuccess},
+		{Name: "http_endpoint", LastError: "test error", LastErrorSource: "http_endpoint:foo", LastErrorAt: &lastError},
+	})
+
+	// WHEN
+	code, body := suite.doGet(EndpointProviders)
+
+	// THEN
+	suite.Equal(http.StatusOK, code)
+	suite.JSONEq(`{
+  "providers": [
+    { "name": "file_system", "rule_sets": 2, "last_success": "2024-05-10T09:41:17Z" },
+    {
+      "name": "http_endpoint",
+      "rule_sets": 0,
+      "last_error": "test error",
+      "last_error_source": "http_endpoint:foo",
+      "last_error_at": "2024-05-10T09:42:17Z"
+    }
+  ]
+}`, body)
+}
+
+func (suite *ServiceTestSuite) doPost(path, body string) (int, string) {
+	suite.T().Helper()
+
+	client := &http.Client{Transport: &http.Transport{}}
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodPost, suite.addr+path, strings.NewReader(body))
+	suite.Require().NoError(err)
+
+	resp, err := client.Do(req)
+	suite.Require().NoError(err)
+
+	defer resp.Body.Close()
+
+	rawResp, err := io.ReadAll(resp.Body)
+	suite.Require().NoError(err)
+
+	return resp.StatusCode, string(rawResp)
+}
+
+func (suite *ServiceTestSuite) TestExplainRequest() {
+	for _, tc := range []struct {
+		uc        string
+		body      string
+		configure func(exec *rulemocks.ExecutorMock)
+		code      int
+		assert    func(body string)
+	}{
+		{
+			uc:   "malformed request body",
+			body: `{"method": `,
+			code: http.StatusBadRequest,
+		},
+		{
+			uc:   "relative url",
+			body: `{"method": "GET", "url": "/foo"}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			uc:   "remote calls requested, but not allowed",
+			body: `{"method": "GET", "url": "https://foo.bar/baz", "offline": false}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			uc: "request allowed by the matched rule",
+			body: `{
+  "method": "post",
+  "url": "https://foo.bar/baz?foo=bar",
+  "headers": { "X-Foo": "bar", "Host": "bar.foo" },
+  "cookies": { "session": "abc" },
+  "body": "hello",
+  "stubs": [{ "url": "https://idp.local/*", "status": 200, "body": "stubbed" }],
+  "offline": true
+}`,
+			configure: func(exec *rulemocks.ExecutorMock) {
+				backendURL := &url.URL{Scheme: "http", Host: "upstream:8080", Path: "/baz"}
+				backend := rulemocks.NewBackendMock(suite.T())
+				backend.EXPECT().URL().Return(backendURL)
+
+				exec.EXPECT().Execute(mock.Anything).
+					Run(func(ctx heimdall.Context) {
+						req := ctx.Request()
+						suite.Equal(http.MethodPost, req.Method)
+						suite.Equal("https://bar.foo/baz?foo=bar", req.URL.String())
+						suite.Equal("bar", req.Header("X-Foo"))
+						suite.Equal("abc", req.Cookie("session"))
+						suite.Equal("hello", req.Body())
+
+						// the request is never forwarded, so no upstream target must be selected
+						suite.True(upstream.TargetSelectionDisabled(ctx.AppContext()))
+
+						// remote calls are answered by the stubs
+						client := &http.Client{Transport: httpx.NewStubRoundTripper(http.DefaultTransport)}
+						stubReq, err := http.NewRequestWithContext(ctx.AppContext(),
+							http.MethodGet, "https://idp.local/userinfo", nil)
+						suite.Require().NoError(err)
+
+						resp, err := client.Do(stubReq)
+						suite.Require().NoError(err)
+
+						defer resp.Body.Close()
+
+						data, err := io.ReadAll(resp.Body)
+						suite.Require().NoError(err)
+						suite.Equal("stubbed", string(data))
+
+						accesscontext.SetRule(ctx.AppContext(), "foo", "test:rules")
+						accesscontext.AddMechanism(ctx.AppContext(),
+							accesscontext.MechanismResult{ID: "jwt", Type: "authenticator", Outcome: "success"})
+						accesscontext.AddMechanism(ctx.AppContext(),
+							accesscontext.MechanismResult{ID: "opa", Type: "authorizer", Outcome: "skipped"})
+
+						ctx.AddHeaderForUpstream("X-User", "alice")
+						ctx.AddCookieForUpstream("user", "alice")
+					}).
+					Return(backend, nil).Once()
+			},
+			code: http.StatusOK,
+			assert: func(body string) {
+				suite.JSONEq(`{
+  "rule": { "id": "foo", "source": "test:rules" },
+  "steps": [
+    { "id": "jwt", "type": "authenticator", "condition_met": true, "outcome": "success" },
+    { "id": "opa", "type": "authorizer", "condition_met": false, "outcome": "skipped" }
+  ],
+  "upstream": {
+    "url": "http://upstream:8080/baz",
+    "headers": { "X-User": ["[REDACTED]"] },
+    "cookies": { "user": "[REDACTED]" }
+  },
+  "status": 200
+}`, body)
+			},
+		},
+		{
+			uc:   "request denied by the matched rule",
+			body: `{ "url": "http://foo.bar/baz" }`,
+			configure: func(exec *rulemocks.ExecutorMock) {
+				exec.EXPECT().Execute(mock.Anything).
+					Run(func(ctx heimdall.Context) {
+						suite.Equal(http.MethodGet, ctx.Request().Method)
+
+						// remote calls fail by default
+						client := &http.Client{Transport: httpx.NewStubRoundTripper(http.DefaultTransport)}
+						remoteReq, err := http.NewRequestWithContext(ctx.AppContext(),
+							http.MethodGet, "https://idp.local/userinfo", nil)
+						suite.Require().NoError(err)
+
+						_, err = client.Do(remoteReq) //nolint:bodyclose
+						suite.Require().ErrorIs(err, httpx.ErrNoStubFound)
+
+						accesscontext.SetRule(ctx.AppContext(), "foo", "test:rules")
+						accesscontext.AddMechanism(ctx.AppContext(),
+							accesscontext.MechanismResult{
+								ID: "jwt", Type: "authenticator", Outcome: "failure", Err: heimdall.ErrAuthentication,
+							})
+					}).
+					Return(nil, heimdall.ErrAuthentication).Once()
+			},
+			code: http.StatusOK,
+			assert: func(body string) {
+				suite.JSONEq(`{
+  "rule": { "id": "foo", "source": "test:rules" },
+  "steps": [
+    {
+      "id": "jwt",
+      "type": "authenticator",
+      "condition_met": true,
+      "outcome": "failure",
+      "error": "authentication error"
+    }
+  ],
+  "upstream": { "headers": {}, "cookies": {} },
+  "status": 401,
+  "error": "authentication error"
+}`, body)
+			},
+		},
+		{
+			uc:   "no matching rule",
+			body: `{ "url": "http://foo.bar/baz" }`,
+			configure: func(exec *rulemocks.ExecutorMock) {
+				exec.EXPECT().Execute(mock.Anything).Return(nil, heimdall.ErrNoRuleFound).Once()
+			},
+			code: http.StatusOK,
+			assert: func(body string) {
+				suite.JSONEq(`{
+  "steps": [],
+  "upstream": { "headers": {}, "cookies": {} },
+  "status": 404,
+  "error": "no rule found"
+}`, body)
+			},
+		},
+	} {
+		suite.Run("case="+tc.uc, func() {
+			// GIVEN
+			if tc.configure != nil {
+				tc.configure(suite.executor)
+			}
+
+			// WHEN
+			code, body := suite.doPost(EndpointExplain, tc.body)
+
+			// THEN
+			suite.Equal(tc.code, code)
+
+			if tc.assert != nil {
+				tc.assert(body)
+			}
+		})
+	}
+}
+
+func TestManagementHandlerWithDefaultConfiguration(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	mh := newManagementHandler(mocks.NewJWTSignerMock(t), mocks.NewJWTSignerMock(t), nil, health.NewRegistry(),
+		rulemocks.NewInspectorMock(t), rulemocks.NewExecutorMock(t), errorhandler.New(), errorhandler.New(),
+		http.StatusOK, config.ServiceConfig{})
+
+	for _, tc := range []struct {
+		method string
+		path   string
+	}{
+		{method: http.MethodGet, path: EndpointRuleSets},
+		{method: http.MethodGet, path: EndpointRules},
+		{method: http.MethodGet, path: EndpointRules + "/foo"},
+		{method: http.MethodGet, path: EndpointProviders},
+		{method: http.MethodPost, path: EndpointExplain},
+	} {
+		t.Run("case="+tc.path, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+
+			// WHEN
+			mh.ServeHTTP(rw, req)
+
+			// THEN
+			assert.Equal(t, http.StatusNotFound, rw.Code)
+		})
+	}
+}
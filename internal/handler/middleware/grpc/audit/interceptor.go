@@ -0,0 +1,59 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+
+	envoy_auth "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	"google.golang.org/grpc"
+
+	"github.com/dadrus/heimdall/internal/audit"
+)
+
+// New creates an interceptor, which emits an audit event for each handled envoy check request.
+// It relies on the access context and must therefore be used after the access log interceptor.
+func New(auditor audit.Auditor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+
+		checkReq, ok := req.(*envoy_auth.CheckRequest)
+		if !ok {
+			return resp, err
+		}
+
+		httpReq := checkReq.GetAttributes().GetRequest().GetHttp()
+		event := audit.NewEvent(ctx, audit.Request{
+			Method:   httpReq.GetMethod(),
+			Scheme:   httpReq.GetScheme(),
+			Host:     httpReq.GetHost(),
+			Path:     httpReq.GetPath(),
+			ClientIP: checkReq.GetAttributes().GetSource().GetAddress().GetSocketAddress().GetAddress(),
+		})
+
+		// the error is converted to a check response by the error handler interceptor
+		// and is for this reason not available in the access context
+		if err != nil {
+			event.Decision = audit.DecisionDeny
+			event.Reason = err.Error()
+		}
+
+		auditor.Audit(event)
+
+		return resp, err
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	envoy_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_auth "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/dadrus/heimdall/internal/accesscontext"
+	"github.com/dadrus/heimdall/internal/audit"
+)
+
+type recordingAuditor struct {
+	events []*audit.Event
+}
+
+func (a *recordingAuditor) Audit(event *audit.Event)      { a.events = append(a.events, event) }
+func (a *recordingAuditor) Start(_ context.Context) error { return nil }
+func (a *recordingAuditor) Stop(_ context.Context) error  { return nil }
+
+func TestInterceptorExecution(t *testing.T) {
+	t.Parallel()
+
+	checkReq := &envoy_auth.CheckRequest{
+		Attributes: &envoy_auth.AttributeContext{
+			Source: &envoy_auth.AttributeContext_Peer{
+				Address: &envoy_core.Address{
+					Address: &envoy_core.Address_SocketAddress{
+						SocketAddress: &envoy_core.SocketAddress{Address: "10.0.0.1"},
+					},
+				},
+			},
+			Request: &envoy_auth.AttributeContext_Request{
+				Http: &envoy_auth.AttributeContext_HttpRequest{
+					Method: "GET",
+					Scheme: "https",
+					Host:   "foo.bar",
+					Path:   "/baz",
+				},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		uc      string
+		req     any
+		handler grpc.UnaryHandler
+		assert  func(t *testing.T, events []*audit.Event)
+	}{
+		{
+			uc:  "not a check request",
+			req: "foo",
+			handler: func(_ context.Context, _ any) (any, error) {
+				return "bar", nil
+			},
+			assert: func(t *testing.T, events []*audit.Event) {
+				t.Helper()
+
+				assert.Empty(t, events)
+			},
+		},
+		{
+			uc:  "allowed check request",
+			req: checkReq,
+			handler: func(ctx context.Context, _ any) (any, error) {
+				accesscontext.SetSubject(ctx, "alice")
+
+				return &envoy_auth.CheckResponse{}, nil
+			},
+			assert: func(t *testing.T, events []*audit.Event) {
+				t.Helper()
+
+				require.Len(t, events, 1)
+				assert.Equal(t, audit.Request{
+					Method: "GET", Scheme: "https", Host: "foo.bar", Path: "/baz", ClientIP: "10.0.0.1",
+				}, events[0].Request)
+				assert.Equal(t, audit.DecisionAllow, events[0].Decision)
+				assert.Equal(t, "alice", events[0].Subject)
+			},
+		},
+		{
+			uc:  "denied check request",
+			req: checkReq,
+			handler: func(_ context.Context, _ any) (any, error) {
+				return nil, errors.New("test error")
+			},
+			assert: func(t *testing.T, events []*audit.Event) {
+				t.Helper()
+
+				require.Len(t, events, 1)
+				assert.Equal(t, audit.DecisionDeny, events[0].Decision)
+				assert.Equal(t, "test error", events[0].Reason)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			auditor := &recordingAuditor{}
+			interceptor := New(auditor)
+
+			// WHEN
+			_, _ = interceptor(accesscontext.New(context.Background()), tc.req, &grpc.UnaryServerInfo{}, tc.handler)
+
+			// THEN
+			tc.assert(t, auditor.events)
+		})
+	}
+}
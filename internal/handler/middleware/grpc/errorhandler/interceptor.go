@@ -76,19 +76,31 @@ func (h *interceptor) intercept(
 
 		errors.As(err, &redirectError)
 
+		headers := []*envoy_core.HeaderValueOption{
+			{
+				Header: &envoy_core.HeaderValue{
+					Key:   "Location",
+					Value: redirectError.RedirectTo,
+				},
+			},
+		}
+
+		for _, cookie := range redirectError.Cookies {
+			headers = append(headers, &envoy_core.HeaderValueOption{
+				Header: &envoy_core.HeaderValue{
+					Key:   "Set-Cookie",
+					Value: cookie.String(),
+				},
+				AppendAction: envoy_core.HeaderValueOption_APPEND_IF_EXISTS_OR_ADD,
+			})
+		}
+
 		return &envoy_auth.CheckResponse{
 			Status: &status.Status{Code: int32(codes.FailedPrecondition)},
 			HttpResponse: &envoy_auth.CheckResponse_DeniedResponse{
 				DeniedResponse: &envoy_auth.DeniedHttpResponse{
-					Status: &envoy_type.HttpStatus{Code: envoy_type.StatusCode(redirectError.Code)},
-					Headers: []*envoy_core.HeaderValueOption{
-						{
-							Header: &envoy_core.HeaderValue{
-								Key:   "Location",
-								Value: redirectError.RedirectTo,
-							},
-						},
-					},
+					Status:  &envoy_type.HttpStatus{Code: envoy_type.StatusCode(redirectError.Code)},
+					Headers: headers,
 				},
 			},
 		}, nil
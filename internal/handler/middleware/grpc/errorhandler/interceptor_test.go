@@ -47,6 +47,7 @@ func TestErrorInterceptor(t *testing.T) {
 		expGRPCCode codes.Code
 		expHTTPCode envoy_type.StatusCode
 		expBody     string
+		expHeaders  map[string]string
 	}{
 		{
 			uc:          "no error",
@@ -222,6 +223,21 @@ func TestErrorInterceptor(t *testing.T) {
 			expGRPCCode: codes.FailedPrecondition,
 			expHTTPCode: http.StatusFound,
 		},
+		{
+			uc:          "redirect error with cookies",
+			interceptor: New(),
+			err: &heimdall.RedirectError{
+				RedirectTo: "http://foo.local",
+				Code:       http.StatusFound,
+				Cookies:    []*http.Cookie{{Name: "foo", Value: "bar", HttpOnly: true}},
+			},
+			expGRPCCode: codes.FailedPrecondition,
+			expHTTPCode: http.StatusFound,
+			expHeaders: map[string]string{
+				"Location":   "http://foo.local",
+				"Set-Cookie": "foo=bar; HttpOnly",
+			},
+		},
 		{
 			uc:          "internal error default",
 			interceptor: New(),
@@ -302,6 +318,15 @@ func TestErrorInterceptor(t *testing.T) {
 				require.NotNil(t, deniedResp)
 				assert.Equal(t, tc.expHTTPCode, deniedResp.GetStatus().GetCode())
 				assert.Equal(t, tc.expBody, deniedResp.GetBody())
+
+				headers := make(map[string]string)
+				for _, hdr := range deniedResp.GetHeaders() {
+					headers[hdr.GetHeader().GetKey()] = hdr.GetHeader().GetValue()
+				}
+
+				for key, value := range tc.expHeaders {
+					assert.Equal(t, value, headers[key])
+				}
 			}
 		})
 	}
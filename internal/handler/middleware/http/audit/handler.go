@@ -0,0 +1,48 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"net/http"
+
+	"github.com/felixge/httpsnoop"
+
+	"github.com/dadrus/heimdall/internal/audit"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/httpx"
+)
+
+// New creates a middleware, which emits an audit event for each handled request. It relies on
+// the access context and must therefore be used after the access log middleware.
+func New(auditor audit.Auditor) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			metrics := httpsnoop.CaptureMetrics(next, rw, req)
+
+			event := audit.NewEvent(req.Context(), audit.Request{
+				Method:   req.Method,
+				Scheme:   x.IfThenElse(req.TLS != nil, "https", "http"),
+				Host:     req.Host,
+				Path:     req.URL.Path,
+				ClientIP: httpx.IPFromHostPort(req.RemoteAddr),
+			})
+			event.StatusCode = metrics.Code
+
+			auditor.Audit(event)
+		})
+	}
+}
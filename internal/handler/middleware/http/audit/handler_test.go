@@ -0,0 +1,123 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/justinas/alice"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/accesscontext"
+	"github.com/dadrus/heimdall/internal/audit"
+	"github.com/dadrus/heimdall/internal/handler/middleware/http/accesslog"
+)
+
+type recordingAuditor struct {
+	events []*audit.Event
+}
+
+func (a *recordingAuditor) Audit(event *audit.Event)      { a.events = append(a.events, event) }
+func (a *recordingAuditor) Start(_ context.Context) error { return nil }
+func (a *recordingAuditor) Stop(_ context.Context) error  { return nil }
+
+func TestHandlerExecution(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc            string
+		handleRequest func(t *testing.T, rw http.ResponseWriter, req *http.Request)
+		assert        func(t *testing.T, event *audit.Event)
+	}{
+		{
+			uc: "allowed request",
+			handleRequest: func(t *testing.T, rw http.ResponseWriter, req *http.Request) {
+				t.Helper()
+
+				accesscontext.SetRule(req.Context(), "foo", "bar")
+				accesscontext.SetSubject(req.Context(), "alice")
+				rw.WriteHeader(http.StatusOK)
+			},
+			assert: func(t *testing.T, event *audit.Event) {
+				t.Helper()
+
+				assert.Equal(t, audit.DecisionAllow, event.Decision)
+				assert.Equal(t, http.StatusOK, event.StatusCode)
+				assert.Equal(t, "alice", event.Subject)
+				assert.Equal(t, &audit.Rule{ID: "foo", Source: "bar"}, event.Rule)
+				assert.Empty(t, event.Reason)
+			},
+		},
+		{
+			uc: "denied request",
+			handleRequest: func(t *testing.T, rw http.ResponseWriter, req *http.Request) {
+				t.Helper()
+
+				accesscontext.SetError(req.Context(), errors.New("test error"))
+				rw.WriteHeader(http.StatusForbidden)
+			},
+			assert: func(t *testing.T, event *audit.Event) {
+				t.Helper()
+
+				assert.Equal(t, audit.DecisionDeny, event.Decision)
+				assert.Equal(t, http.StatusForbidden, event.StatusCode)
+				assert.Equal(t, "test error", event.Reason)
+				assert.Nil(t, event.Rule)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			auditor := &recordingAuditor{}
+
+			srv := httptest.NewServer(
+				alice.New(accesslog.New(log.Logger), New(auditor)).
+					ThenFunc(func(rw http.ResponseWriter, req *http.Request) {
+						tc.handleRequest(t, rw, req)
+					}))
+			defer srv.Close()
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL+"/foo", nil)
+			require.NoError(t, err)
+
+			// WHEN
+			resp, err := srv.Client().Do(req)
+
+			// THEN
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			require.Len(t, auditor.events, 1)
+
+			event := auditor.events[0]
+			assert.Equal(t, audit.Request{
+				Method:   http.MethodPost,
+				Scheme:   "http",
+				Host:     req.URL.Host,
+				Path:     "/foo",
+				ClientIP: "127.0.0.1",
+			}, event.Request)
+			tc.assert(t, event)
+		})
+	}
+}
@@ -60,7 +60,7 @@ func New() func(http.Handler) http.Handler { // nolint: funlen, gocognit, cyclop
 		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 			logger := zerolog.Ctx(req.Context())
 
-			if logger.GetLevel() != zerolog.TraceLevel {
+			if logger.GetLevel() != zerolog.TraceLevel || zerolog.GlobalLevel() != zerolog.TraceLevel {
 				next.ServeHTTP(rw, req)
 
 				return
@@ -67,6 +67,10 @@ func (h *errorHandler) HandleError(rw http.ResponseWriter, req *http.Request, er
 
 		errors.As(err, &redirectError)
 
+		for _, cookie := range redirectError.Cookies {
+			http.SetCookie(rw, cookie)
+		}
+
 		rw.Header().Set("Location", redirectError.RedirectTo)
 		rw.WriteHeader(redirectError.Code)
 
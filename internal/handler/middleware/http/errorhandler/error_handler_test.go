@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/x/errorchain"
@@ -222,3 +223,35 @@ func TestHandlerHandle(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlerHandleRedirectErrorWithCookies(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/foo", nil)
+
+	err := &heimdall.RedirectError{
+		RedirectTo: "http://foo.local",
+		Code:       http.StatusSeeOther,
+		Cookies: []*http.Cookie{
+			{Name: "foo", Value: "bar", HttpOnly: true},
+			{Name: "baz", MaxAge: -1},
+		},
+	}
+
+	// WHEN
+	New().HandleError(recorder, req, err)
+
+	// THEN
+	assert.Equal(t, http.StatusSeeOther, recorder.Code)
+	assert.Equal(t, "http://foo.local", recorder.Header().Get("Location"))
+
+	cookies := recorder.Result().Cookies()
+	require.Len(t, cookies, 2)
+	assert.Equal(t, "foo", cookies[0].Name)
+	assert.Equal(t, "bar", cookies[0].Value)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.Equal(t, "baz", cookies[1].Name)
+	assert.Equal(t, -1, cookies[1].MaxAge)
+}
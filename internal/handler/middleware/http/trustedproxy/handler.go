@@ -34,6 +34,8 @@ var untrustedHeader = []string{ //nolint:gochecknoglobals
 	"X-Forwarded-Uri",
 	"X-Forwarded-Path",
 	"X-Forwarded-Method",
+	"X-Forwarded-Client-Cert",
+	"Ssl-Client-Cert",
 }
 
 type ipHolder interface {
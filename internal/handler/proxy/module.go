@@ -22,11 +22,13 @@ import (
 	"github.com/rs/zerolog"
 	"go.uber.org/fx"
 
+	"github.com/dadrus/heimdall/internal/audit"
 	"github.com/dadrus/heimdall/internal/cache"
 	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/handler/fxlcm"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/rules/rule"
+	"github.com/dadrus/heimdall/internal/rules/upstream"
 )
 
 var Module = fx.Invoke( // nolint: gochecknoglobals
@@ -39,17 +41,20 @@ var Module = fx.Invoke( // nolint: gochecknoglobals
 
 func newLifecycleManager(
 	conf *config.Configuration,
+	watcher *config.Watcher,
 	logger zerolog.Logger,
 	cch cache.Cache,
 	executor rule.Executor,
 	signer heimdall.JWTSigner,
+	auditor audit.Auditor,
+	upstreams *upstream.Registry,
 ) *fxlcm.LifecycleManager {
 	cfg := conf.Serve.Proxy
 
 	return &fxlcm.LifecycleManager{
 		ServiceName:    "Proxy",
 		ServiceAddress: cfg.Address(),
-		Server:         newService(conf, cch, logger, executor, signer),
+		Server:         newService(conf, watcher, cch, logger, executor, signer, auditor, upstreams),
 		Logger:         logger,
 		TLSConf:        cfg.TLS,
 	}
@@ -17,18 +17,13 @@
 package proxy
 
 import (
-	"crypto/tls"
 	"fmt"
-	"net"
 	"net/http"
 	"net/http/httputil"
-	"net/url"
-	"time"
 
 	"github.com/rs/zerolog"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 
-	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/handler/requestcontext"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/rules/rule"
@@ -40,40 +35,16 @@ import (
 type requestContext struct {
 	*requestcontext.RequestContext
 
-	rw        http.ResponseWriter
-	req       *http.Request
-	transport *http.Transport
+	rw         http.ResponseWriter
+	req        *http.Request
+	transports *transports
 }
 
-func newContextFactory(
-	signer heimdall.JWTSigner,
-	cfg config.ServiceConfig,
-	tlsCfg *tls.Config,
-) requestcontext.ContextFactory {
-	transport := &http.Transport{
-		// tlsClientConfig used for test purposes only
-		// must be removed as soon as tls configuration
-		// is possible per upstream
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout:   30 * time.Second, //nolint:gomnd
-			KeepAlive: 30 * time.Second, //nolint:gomnd
-		}).DialContext,
-		ResponseHeaderTimeout: cfg.Timeout.Read,
-		MaxIdleConns:          cfg.ConnectionsLimit.MaxIdle,
-		MaxIdleConnsPerHost:   cfg.ConnectionsLimit.MaxIdlePerHost,
-		MaxConnsPerHost:       cfg.ConnectionsLimit.MaxPerHost,
-		IdleConnTimeout:       cfg.Timeout.Idle,
-		TLSHandshakeTimeout:   10 * time.Second, //nolint:gomnd
-		ExpectContinueTimeout: 1 * time.Second,
-		ForceAttemptHTTP2:     true,
-		TLSClientConfig:       tlsCfg,
-	}
-
+func newContextFactory(signer heimdall.JWTSigner, transports *transports) requestcontext.ContextFactory {
 	return requestcontext.FactoryFunc(func(rw http.ResponseWriter, req *http.Request) requestcontext.Context {
 		return &requestContext{
 			RequestContext: requestcontext.New(signer, req),
-			transport:      transport,
+			transports:     transports,
 			rw:             rw,
 			req:            req,
 		}
@@ -82,6 +53,12 @@ func newContextFactory(
 
 func (r *requestContext) Finalize(upstream rule.Backend) error {
 	logger := zerolog.Ctx(r.AppContext())
+	errHolder := struct{ err error }{}
+
+	if upstream != nil {
+		// the selected upstream target must be released on every exit path
+		defer func() { upstream.Done(errHolder.err) }()
+	}
 
 	if err := r.PipelineError(); err != nil {
 		return err
@@ -96,21 +73,44 @@ func (r *requestContext) Finalize(upstream rule.Backend) error {
 		Str("_upstream", upstream.URL().String()).
 		Msg("Forwarding request")
 
-	errHolder := struct{ err error }{}
+	transport, err := r.transports.get(upstream.TLS())
+	if err != nil {
+		errHolder.err = err
+
+		return err
+	}
+
+	// the request forwarded to the upstream is final only after all rewrites have been applied.
+	// So, it is signed right before being sent
+	signingTransport := &upstreamSigningTransport{
+		signers: r.UpstreamRequestSigners(),
+		next: otelhttp.NewTransport(
+			httpx.NewTraceRoundTripper(transport),
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return fmt.Sprintf("%s %s %s @%s", r.Proto, r.Method, r.URL.Path, r.URL.Host)
+			})),
+	}
 
 	proxy := &httputil.ReverseProxy{
 		ErrorHandler: func(rw http.ResponseWriter, req *http.Request, err error) {
+			if signingTransport.err != nil {
+				errHolder.err = signingTransport.err
+
+				return
+			}
+
 			logger.Error().Err(err).Msg("Proxying error")
 
 			errHolder.err = errorchain.NewWithMessage(heimdall.ErrCommunication, "Failed to proxy request").
 				CausedBy(err)
 		},
-		Rewrite: r.rewriteRequest(upstream.URL()),
-		Transport: otelhttp.NewTransport(
-			httpx.NewTraceRoundTripper(r.transport),
-			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
-				return fmt.Sprintf("%s %s %s @%s", r.Proto, r.Method, r.URL.Path, r.URL.Host)
-			})),
+		Rewrite: r.rewriteRequest(upstream),
+		ModifyResponse: func(resp *http.Response) error {
+			upstream.RewriteResponseHeaders(resp.Header)
+
+			return nil
+		},
+		Transport: signingTransport,
 	}
 
 	proxy.ServeHTTP(r.rw, r.req)
@@ -119,7 +119,9 @@ func (r *requestContext) Finalize(upstream rule.Backend) error {
 	return errHolder.err
 }
 
-func (r *requestContext) rewriteRequest(targetURL *url.URL) func(req *httputil.ProxyRequest) {
+func (r *requestContext) rewriteRequest(upstream rule.Backend) func(req *httputil.ProxyRequest) {
+	targetURL := upstream.URL()
+
 	return func(proxyReq *httputil.ProxyRequest) {
 		proxyReq.Out.Method = r.Request().Method
 		proxyReq.Out.URL = targetURL
@@ -174,5 +176,39 @@ func (r *requestContext) rewriteRequest(targetURL *url.URL) func(req *httputil.P
 						forwarded, clientIP, proxyReq.In.Host, proto)
 				}))
 		}
+
+		// apply the header modifications configured in the rule as last to allow
+		// overriding or removal of any of the headers set above
+		upstream.RewriteRequestHeaders(proxyReq.Out.Header)
+
+		if host := proxyReq.Out.Header.Get("Host"); len(host) != 0 {
+			proxyReq.Out.Host = host
+			proxyReq.Out.Header.Del("Host")
+		}
+	}
+}
+
+// upstreamSigningTransport applies the signers registered by the finalizers to the request
+// forwarded to the upstream service.
+type upstreamSigningTransport struct {
+	next    http.RoundTripper
+	signers []heimdall.UpstreamRequestSigner
+	err     error
+}
+
+func (t *upstreamSigningTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(t.signers) == 0 {
+		return t.next.RoundTrip(req)
 	}
+
+	// a RoundTripper must not modify the given request
+	req = req.Clone(req.Context())
+
+	if _, err := requestcontext.SignUpstreamRequest(req, t.signers); err != nil {
+		t.err = err
+
+		return nil, err
+	}
+
+	return t.next.RoundTrip(req)
 }
@@ -26,10 +26,13 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/handler/requestcontext"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	config2 "github.com/dadrus/heimdall/internal/rules/config"
 	"github.com/dadrus/heimdall/internal/rules/rule"
 	mocks2 "github.com/dadrus/heimdall/internal/rules/rule/mocks"
 )
@@ -43,6 +46,7 @@ func TestRequestContextFinalize(t *testing.T) {
 		headers        http.Header
 		setup          func(*testing.T, requestcontext.Context, *url.URL) rule.Backend
 		assertRequest  func(*testing.T, *http.Request)
+		assertResponse func(*testing.T, *httptest.ResponseRecorder)
 	}{
 		{
 			uc: "error was present, forwarding aborted",
@@ -55,6 +59,50 @@ func TestRequestContextFinalize(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			uc: "error was present, selected upstream released",
+			setup: func(t *testing.T, ctx requestcontext.Context, _ *url.URL) rule.Backend {
+				t.Helper()
+
+				ctx.SetPipelineError(errors.New("test error"))
+
+				backend := mocks2.NewBackendMock(t)
+				backend.EXPECT().Done(nil)
+
+				return backend
+			},
+		},
+		{
+			uc: "invalid upstream tls configuration",
+			setup: func(t *testing.T, _ requestcontext.Context, _ *url.URL) rule.Backend {
+				t.Helper()
+
+				backend := mocks2.NewBackendMock(t)
+				backend.EXPECT().URL().Return(&url.URL{Scheme: "https", Host: "127.0.0.1:1"})
+				backend.EXPECT().TLS().Return(&config2.BackendTLS{TrustStore: "not-declared"})
+				backend.EXPECT().Done(mock.MatchedBy(func(err error) bool {
+					return errors.Is(err, heimdall.ErrConfiguration)
+				}))
+
+				return backend
+			},
+		},
+		{
+			uc: "upstream not reachable",
+			setup: func(t *testing.T, _ requestcontext.Context, _ *url.URL) rule.Backend {
+				t.Helper()
+
+				backend := mocks2.NewBackendMock(t)
+				backend.EXPECT().URL().Return(&url.URL{Scheme: "http", Host: "127.0.0.1:1"})
+				backend.EXPECT().TLS().Return(nil)
+				backend.EXPECT().RewriteRequestHeaders(mock.Anything)
+				backend.EXPECT().Done(mock.MatchedBy(func(err error) bool {
+					return errors.Is(err, heimdall.ErrCommunication)
+				}))
+
+				return backend
+			},
+		},
 		{
 			uc:             "no headers set",
 			upstreamCalled: true,
@@ -63,6 +111,10 @@ func TestRequestContextFinalize(t *testing.T) {
 
 				backend := mocks2.NewBackendMock(t)
 				backend.EXPECT().URL().Return(upstreamURL)
+				backend.EXPECT().TLS().Return(nil)
+				backend.EXPECT().RewriteRequestHeaders(mock.Anything)
+				backend.EXPECT().RewriteResponseHeaders(mock.Anything)
+				backend.EXPECT().Done(nil)
 
 				return backend
 			},
@@ -95,6 +147,10 @@ func TestRequestContextFinalize(t *testing.T) {
 
 				backend := mocks2.NewBackendMock(t)
 				backend.EXPECT().URL().Return(upstreamURL)
+				backend.EXPECT().TLS().Return(nil)
+				backend.EXPECT().RewriteRequestHeaders(mock.Anything)
+				backend.EXPECT().RewriteResponseHeaders(mock.Anything)
+				backend.EXPECT().Done(nil)
 
 				return backend
 			},
@@ -124,6 +180,10 @@ func TestRequestContextFinalize(t *testing.T) {
 
 				backend := mocks2.NewBackendMock(t)
 				backend.EXPECT().URL().Return(upstreamURL)
+				backend.EXPECT().TLS().Return(nil)
+				backend.EXPECT().RewriteRequestHeaders(mock.Anything)
+				backend.EXPECT().RewriteResponseHeaders(mock.Anything)
+				backend.EXPECT().Done(nil)
 
 				return backend
 			},
@@ -156,6 +216,10 @@ func TestRequestContextFinalize(t *testing.T) {
 
 				backend := mocks2.NewBackendMock(t)
 				backend.EXPECT().URL().Return(upstreamURL)
+				backend.EXPECT().TLS().Return(nil)
+				backend.EXPECT().RewriteRequestHeaders(mock.Anything)
+				backend.EXPECT().RewriteResponseHeaders(mock.Anything)
+				backend.EXPECT().Done(nil)
 
 				return backend
 			},
@@ -187,6 +251,10 @@ func TestRequestContextFinalize(t *testing.T) {
 
 				backend := mocks2.NewBackendMock(t)
 				backend.EXPECT().URL().Return(upstreamURL)
+				backend.EXPECT().TLS().Return(nil)
+				backend.EXPECT().RewriteRequestHeaders(mock.Anything)
+				backend.EXPECT().RewriteResponseHeaders(mock.Anything)
+				backend.EXPECT().Done(nil)
 
 				return backend
 			},
@@ -213,6 +281,10 @@ func TestRequestContextFinalize(t *testing.T) {
 
 				backend := mocks2.NewBackendMock(t)
 				backend.EXPECT().URL().Return(upstreamURL)
+				backend.EXPECT().TLS().Return(nil)
+				backend.EXPECT().RewriteRequestHeaders(mock.Anything)
+				backend.EXPECT().RewriteResponseHeaders(mock.Anything)
+				backend.EXPECT().Done(nil)
 
 				return backend
 			},
@@ -241,6 +313,10 @@ func TestRequestContextFinalize(t *testing.T) {
 
 				backend := mocks2.NewBackendMock(t)
 				backend.EXPECT().URL().Return(upstreamURL)
+				backend.EXPECT().TLS().Return(nil)
+				backend.EXPECT().RewriteRequestHeaders(mock.Anything)
+				backend.EXPECT().RewriteResponseHeaders(mock.Anything)
+				backend.EXPECT().Done(nil)
 
 				return backend
 			},
@@ -269,6 +345,10 @@ func TestRequestContextFinalize(t *testing.T) {
 
 				backend := mocks2.NewBackendMock(t)
 				backend.EXPECT().URL().Return(upstreamURL)
+				backend.EXPECT().TLS().Return(nil)
+				backend.EXPECT().RewriteRequestHeaders(mock.Anything)
+				backend.EXPECT().RewriteResponseHeaders(mock.Anything)
+				backend.EXPECT().Done(nil)
 
 				return backend
 			},
@@ -286,6 +366,100 @@ func TestRequestContextFinalize(t *testing.T) {
 				assert.Equal(t, "172.2.34.1, 192.0.2.1", req.Header.Get("X-Forwarded-For"))
 			},
 		},
+		{
+			uc:             "header rewrites configured in the rule are applied",
+			upstreamCalled: true,
+			headers: http.Header{
+				"X-Internal": []string{"foo"},
+			},
+			setup: func(t *testing.T, ctx requestcontext.Context, upstreamURL *url.URL) rule.Backend {
+				t.Helper()
+
+				ctx.AddHeaderForUpstream("X-Foo", "from finalizer")
+
+				backend := mocks2.NewBackendMock(t)
+				backend.EXPECT().URL().Return(upstreamURL)
+				backend.EXPECT().TLS().Return(nil)
+				backend.EXPECT().RewriteRequestHeaders(mock.Anything).Run(func(header http.Header) {
+					header.Del("X-Internal")
+					header.Del("Forwarded")
+					header.Set("X-Foo", "from rewrite")
+					header.Set("Host", "bar.foo")
+				})
+				backend.EXPECT().RewriteResponseHeaders(mock.Anything).Run(func(header http.Header) {
+					header.Set("X-Bar", "from rewrite")
+				})
+				backend.EXPECT().Done(nil)
+
+				return backend
+			},
+			assertRequest: func(t *testing.T, req *http.Request) {
+				t.Helper()
+
+				assert.Equal(t, "bar.foo", req.Host)
+				assert.Empty(t, req.Header.Get("X-Internal"))
+				assert.Empty(t, req.Header.Get("Forwarded"))
+				assert.Equal(t, "from rewrite", req.Header.Get("X-Foo"))
+			},
+			assertResponse: func(t *testing.T, rw *httptest.ResponseRecorder) {
+				t.Helper()
+
+				assert.Equal(t, "from rewrite", rw.Header().Get("X-Bar"))
+			},
+		},
+		{
+			uc:             "upstream request signers are applied to the rewritten request",
+			upstreamCalled: true,
+			setup: func(t *testing.T, ctx requestcontext.Context, upstreamURL *url.URL) rule.Backend {
+				t.Helper()
+
+				ctx.AddHeaderForUpstream("X-Foo", "from finalizer")
+				ctx.AddUpstreamRequestSigner(func(req *heimdall.Request) (http.Header, error) {
+					return http.Header{"X-Signature": []string{
+						req.Method + " " + req.URL.Host + " " + req.Header("X-Foo"),
+					}}, nil
+				})
+				ctx.AddUpstreamRequestSigner(func(req *heimdall.Request) (http.Header, error) {
+					return http.Header{"X-Signature-2": []string{req.Header("X-Signature")}}, nil
+				})
+
+				backend := mocks2.NewBackendMock(t)
+				backend.EXPECT().URL().Return(upstreamURL)
+				backend.EXPECT().TLS().Return(nil)
+				backend.EXPECT().RewriteRequestHeaders(mock.Anything).Run(func(header http.Header) {
+					header.Set("X-Foo", "from rewrite")
+					header.Set("Host", "bar.foo")
+				})
+				backend.EXPECT().RewriteResponseHeaders(mock.Anything)
+				backend.EXPECT().Done(nil)
+
+				return backend
+			},
+			assertRequest: func(t *testing.T, req *http.Request) {
+				t.Helper()
+
+				assert.Equal(t, "GET bar.foo from rewrite", req.Header.Get("X-Signature"))
+				assert.Equal(t, "GET bar.foo from rewrite", req.Header.Get("X-Signature-2"))
+			},
+		},
+		{
+			uc: "upstream request signer fails",
+			setup: func(t *testing.T, ctx requestcontext.Context, upstreamURL *url.URL) rule.Backend {
+				t.Helper()
+
+				ctx.AddUpstreamRequestSigner(func(_ *heimdall.Request) (http.Header, error) {
+					return nil, heimdall.ErrArgument
+				})
+
+				backend := mocks2.NewBackendMock(t)
+				backend.EXPECT().URL().Return(upstreamURL)
+				backend.EXPECT().TLS().Return(nil)
+				backend.EXPECT().RewriteRequestHeaders(mock.Anything)
+				backend.EXPECT().Done(heimdall.ErrArgument)
+
+				return backend
+			},
+		},
 	} {
 		t.Run(tc.uc, func(t *testing.T) {
 			// GIVEN
@@ -308,7 +482,8 @@ func TestRequestContextFinalize(t *testing.T) {
 				Write: 100 * time.Millisecond,
 				Idle:  1 * time.Second,
 			}
-			ctx := newContextFactory(nil, config.ServiceConfig{Timeout: timeouts}, nil).Create(rw, req)
+			ctx := newContextFactory(nil,
+				newTransports(config.ServiceConfig{Timeout: timeouts}, newTestUpstreamRegistry(t))).Create(rw, req)
 
 			backend := tc.setup(t, ctx, targetURL)
 
@@ -320,6 +495,10 @@ func TestRequestContextFinalize(t *testing.T) {
 			if !tc.upstreamCalled {
 				require.Error(t, err)
 			}
+
+			if tc.assertResponse != nil {
+				tc.assertResponse(t, rw)
+			}
 		})
 	}
 }
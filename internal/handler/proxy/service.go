@@ -29,9 +29,11 @@ import (
 	"github.com/rs/zerolog"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 
+	"github.com/dadrus/heimdall/internal/audit"
 	"github.com/dadrus/heimdall/internal/cache"
 	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/handler/middleware/http/accesslog"
+	auditmiddleware "github.com/dadrus/heimdall/internal/handler/middleware/http/audit"
 	cachemiddleware "github.com/dadrus/heimdall/internal/handler/middleware/http/cache"
 	"github.com/dadrus/heimdall/internal/handler/middleware/http/dump"
 	"github.com/dadrus/heimdall/internal/handler/middleware/http/errorhandler"
@@ -40,19 +42,16 @@ import (
 	"github.com/dadrus/heimdall/internal/handler/middleware/http/passthrough"
 	"github.com/dadrus/heimdall/internal/handler/middleware/http/recovery"
 	"github.com/dadrus/heimdall/internal/handler/middleware/http/trustedproxy"
+	"github.com/dadrus/heimdall/internal/handler/reloadable"
 	"github.com/dadrus/heimdall/internal/handler/service"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/rules/rule"
+	"github.com/dadrus/heimdall/internal/rules/upstream"
 	"github.com/dadrus/heimdall/internal/x"
 	"github.com/dadrus/heimdall/internal/x/httpx"
 	"github.com/dadrus/heimdall/internal/x/loggeradapter"
 )
 
-// tlsClientConfig used for test purposes only to
-// set the certificate pool for peer certificate verification
-// purposes.
-var tlsClientConfig *tls.Config // nolint: gochecknoglobals
-
 type deadlineResetter struct{}
 
 func (dr *deadlineResetter) handler(next http.Handler) http.Handler {
@@ -83,65 +82,79 @@ func (dr *deadlineResetter) contexter(ctx context.Context, con net.Conn) context
 
 func newService(
 	conf *config.Configuration,
+	watcher *config.Watcher,
 	cch cache.Cache,
 	log zerolog.Logger,
 	exec rule.Executor,
 	signer heimdall.JWTSigner,
+	auditor audit.Auditor,
+	upstreams *upstream.Registry,
 ) *http.Server {
 	der := &deadlineResetter{}
 	cfg := conf.Serve.Proxy
-	eh := errorhandler.New(
-		errorhandler.WithVerboseErrors(cfg.Respond.Verbose),
-		errorhandler.WithPreconditionErrorCode(cfg.Respond.With.ArgumentError.Code),
-		errorhandler.WithAuthenticationErrorCode(cfg.Respond.With.AuthenticationError.Code),
-		errorhandler.WithAuthorizationErrorCode(cfg.Respond.With.AuthorizationError.Code),
-		errorhandler.WithCommunicationErrorCode(cfg.Respond.With.CommunicationError.Code),
-		errorhandler.WithMethodErrorCode(cfg.Respond.With.BadMethodError.Code),
-		errorhandler.WithNoRuleErrorCode(cfg.Respond.With.NoRuleError.Code),
-		errorhandler.WithInternalServerErrorCode(cfg.Respond.With.InternalError.Code),
-	)
-
-	hc := alice.New(
-		trustedproxy.New(
-			log,
-			x.IfThenElseExec(cfg.TrustedProxies != nil,
-				func() []string { return *cfg.TrustedProxies },
-				func() []string { return []string{} },
-			)...,
-		),
-		accesslog.New(log),
-		logger.New(log),
-		dump.New(),
-		der.handler,
-		recovery.New(eh),
-		otelhttp.NewMiddleware("",
-			otelhttp.WithServerName(cfg.Address()),
-			otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
-				return fmt.Sprintf("EntryPoint %s %s%s",
-					strings.ToLower(req.URL.Scheme), httpx.LocalAddress(req), req.URL.Path)
-			}),
-		),
-		otelmetrics.New(
-			otelmetrics.WithSubsystem("proxy"),
-			otelmetrics.WithServerName(cfg.Address()),
-		),
-		x.IfThenElseExec(cfg.CORS != nil,
-			func() func(http.Handler) http.Handler {
-				return cors.New(
-					cors.Options{
-						AllowedOrigins:   cfg.CORS.AllowedOrigins,
-						AllowedMethods:   cfg.CORS.AllowedMethods,
-						AllowedHeaders:   cfg.CORS.AllowedHeaders,
-						AllowCredentials: cfg.CORS.AllowCredentials,
-						ExposedHeaders:   cfg.CORS.ExposedHeaders,
-						MaxAge:           int(cfg.CORS.MaxAge.Seconds()),
-					},
-				).Handler
-			},
-			func() func(http.Handler) http.Handler { return passthrough.New },
-		),
-		cachemiddleware.New(cch),
-	).Then(service.NewHandler(newContextFactory(signer, cfg, tlsClientConfig), exec, eh))
+	address := cfg.Address()
+	transports := newTransports(cfg, upstreams)
+
+	upstreams.OnTLSConfigReleased(transports.release)
+
+	// the respond, cors and trusted_proxies settings are taken from the current configuration
+	// and are applied on configuration reloads as well
+	hc := reloadable.NewHandler(conf, watcher, func(conf *config.Configuration) http.Handler {
+		cfg := conf.Serve.Proxy
+		eh := errorhandler.New(
+			errorhandler.WithVerboseErrors(cfg.Respond.Verbose),
+			errorhandler.WithPreconditionErrorCode(cfg.Respond.With.ArgumentError.Code),
+			errorhandler.WithAuthenticationErrorCode(cfg.Respond.With.AuthenticationError.Code),
+			errorhandler.WithAuthorizationErrorCode(cfg.Respond.With.AuthorizationError.Code),
+			errorhandler.WithCommunicationErrorCode(cfg.Respond.With.CommunicationError.Code),
+			errorhandler.WithMethodErrorCode(cfg.Respond.With.BadMethodError.Code),
+			errorhandler.WithNoRuleErrorCode(cfg.Respond.With.NoRuleError.Code),
+			errorhandler.WithInternalServerErrorCode(cfg.Respond.With.InternalError.Code),
+		)
+
+		return alice.New(
+			trustedproxy.New(
+				log,
+				x.IfThenElseExec(cfg.TrustedProxies != nil,
+					func() []string { return *cfg.TrustedProxies },
+					func() []string { return []string{} },
+				)...,
+			),
+			accesslog.New(log),
+			auditmiddleware.New(auditor),
+			logger.New(log),
+			dump.New(),
+			der.handler,
+			recovery.New(eh),
+			otelhttp.NewMiddleware("",
+				otelhttp.WithServerName(address),
+				otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
+					return fmt.Sprintf("EntryPoint %s %s%s",
+						strings.ToLower(req.URL.Scheme), httpx.LocalAddress(req), req.URL.Path)
+				}),
+			),
+			otelmetrics.New(
+				otelmetrics.WithSubsystem("proxy"),
+				otelmetrics.WithServerName(address),
+			),
+			x.IfThenElseExec(cfg.CORS != nil,
+				func() func(http.Handler) http.Handler {
+					return cors.New(
+						cors.Options{
+							AllowedOrigins:   cfg.CORS.AllowedOrigins,
+							AllowedMethods:   cfg.CORS.AllowedMethods,
+							AllowedHeaders:   cfg.CORS.AllowedHeaders,
+							AllowCredentials: cfg.CORS.AllowCredentials,
+							ExposedHeaders:   cfg.CORS.ExposedHeaders,
+							MaxAge:           int(cfg.CORS.MaxAge.Seconds()),
+						},
+					).Handler
+				},
+				func() func(http.Handler) http.Handler { return passthrough.New },
+			),
+			cachemiddleware.New(cch),
+		).Then(service.NewHandler(newContextFactory(signer, transports), exec, eh))
+	})
 
 	return &http.Server{
 		Handler:        hc,
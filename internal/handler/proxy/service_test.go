@@ -44,15 +44,19 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric/noop"
 	"go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	"go.opentelemetry.io/otel/sdk/resource"
 
+	"github.com/dadrus/heimdall/internal/audit"
 	"github.com/dadrus/heimdall/internal/cache/mocks"
 	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/handler/listener"
 	"github.com/dadrus/heimdall/internal/heimdall"
+	config2 "github.com/dadrus/heimdall/internal/rules/config"
 	mocks4 "github.com/dadrus/heimdall/internal/rules/rule/mocks"
+	"github.com/dadrus/heimdall/internal/rules/upstream"
 	"github.com/dadrus/heimdall/internal/x"
 	"github.com/dadrus/heimdall/internal/x/pkix/pemx"
 	"github.com/dadrus/heimdall/internal/x/stringx"
@@ -104,7 +108,7 @@ func TestProxyService(t *testing.T) {
 		disableHTTP2   bool
 		createRequest  func(t *testing.T, host string) *http.Request
 		createClient   func(t *testing.T) *http.Client
-		configureMocks func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL)
+		configureMocks func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL, upstreamTLS *config2.BackendTLS)
 		processRequest func(t *testing.T, rw http.ResponseWriter, req *http.Request)
 		assertResponse func(t *testing.T, err error, upstreamCalled bool, resp *http.Response)
 	}{
@@ -123,7 +127,7 @@ func TestProxyService(t *testing.T) {
 
 				return req
 			},
-			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL) {
+			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL, upstreamTLS *config2.BackendTLS) {
 				t.Helper()
 
 				exec.EXPECT().Execute(mock.Anything).Return(nil, heimdall.ErrNoRuleFound)
@@ -156,7 +160,7 @@ func TestProxyService(t *testing.T) {
 
 				return req
 			},
-			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL) {
+			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL, upstreamTLS *config2.BackendTLS) {
 				t.Helper()
 
 				exec.EXPECT().Execute(mock.Anything).Return(nil, heimdall.ErrMethodNotAllowed)
@@ -189,7 +193,7 @@ func TestProxyService(t *testing.T) {
 
 				return req
 			},
-			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL) {
+			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL, upstreamTLS *config2.BackendTLS) {
 				t.Helper()
 
 				exec.EXPECT().Execute(mock.Anything).Return(nil, heimdall.ErrConfiguration)
@@ -222,7 +226,7 @@ func TestProxyService(t *testing.T) {
 
 				return req
 			},
-			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL) {
+			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL, upstreamTLS *config2.BackendTLS) {
 				t.Helper()
 
 				exec.EXPECT().Execute(mock.Anything).Return(nil, heimdall.ErrAuthentication)
@@ -255,7 +259,7 @@ func TestProxyService(t *testing.T) {
 
 				return req
 			},
-			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL) {
+			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL, upstreamTLS *config2.BackendTLS) {
 				t.Helper()
 
 				exec.EXPECT().Execute(mock.Anything).Return(nil, heimdall.ErrAuthorization)
@@ -295,7 +299,7 @@ func TestProxyService(t *testing.T) {
 
 				return req
 			},
-			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL) {
+			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL, upstreamTLS *config2.BackendTLS) {
 				t.Helper()
 
 				backend := mocks4.NewBackendMock(t)
@@ -304,6 +308,10 @@ func TestProxyService(t *testing.T) {
 					Host:   upstreamURL.Host,
 					Path:   "/foobar",
 				})
+				backend.EXPECT().TLS().Return(upstreamTLS)
+				backend.EXPECT().RewriteRequestHeaders(mock.Anything)
+				backend.EXPECT().RewriteResponseHeaders(mock.Anything)
+				backend.EXPECT().Done(nil)
 
 				exec.EXPECT().Execute(
 					mock.MatchedBy(func(ctx heimdall.Context) bool {
@@ -378,7 +386,7 @@ func TestProxyService(t *testing.T) {
 
 				return req
 			},
-			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL) {
+			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL, upstreamTLS *config2.BackendTLS) {
 				t.Helper()
 
 				backend := mocks4.NewBackendMock(t)
@@ -387,6 +395,10 @@ func TestProxyService(t *testing.T) {
 					Host:   upstreamURL.Host,
 					Path:   "/[id]/foobar",
 				})
+				backend.EXPECT().TLS().Return(upstreamTLS)
+				backend.EXPECT().RewriteRequestHeaders(mock.Anything)
+				backend.EXPECT().RewriteResponseHeaders(mock.Anything)
+				backend.EXPECT().Done(nil)
 
 				exec.EXPECT().Execute(
 					mock.MatchedBy(func(ctx heimdall.Context) bool {
@@ -460,7 +472,7 @@ func TestProxyService(t *testing.T) {
 
 				return req
 			},
-			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL) {
+			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL, upstreamTLS *config2.BackendTLS) {
 				t.Helper()
 
 				backend := mocks4.NewBackendMock(t)
@@ -469,6 +481,10 @@ func TestProxyService(t *testing.T) {
 					Host:   upstreamURL.Host,
 					Path:   "/[barfoo]",
 				})
+				backend.EXPECT().TLS().Return(upstreamTLS)
+				backend.EXPECT().RewriteRequestHeaders(mock.Anything)
+				backend.EXPECT().RewriteResponseHeaders(mock.Anything)
+				backend.EXPECT().Done(nil)
 
 				exec.EXPECT().Execute(
 					mock.MatchedBy(func(ctx heimdall.Context) bool {
@@ -548,7 +564,7 @@ func TestProxyService(t *testing.T) {
 
 				return req
 			},
-			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL) {
+			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL, upstreamTLS *config2.BackendTLS) {
 				t.Helper()
 
 				backend := mocks4.NewBackendMock(t)
@@ -557,6 +573,10 @@ func TestProxyService(t *testing.T) {
 					Host:   upstreamURL.Host,
 					Path:   "/bar",
 				})
+				backend.EXPECT().TLS().Return(upstreamTLS)
+				backend.EXPECT().RewriteRequestHeaders(mock.Anything)
+				backend.EXPECT().RewriteResponseHeaders(mock.Anything)
+				backend.EXPECT().Done(nil)
 
 				exec.EXPECT().Execute(
 					mock.MatchedBy(func(ctx heimdall.Context) bool {
@@ -633,7 +653,7 @@ func TestProxyService(t *testing.T) {
 
 				return req
 			},
-			configureMocks: func(t *testing.T, _ *mocks4.ExecutorMock, upstreamURL *url.URL) { t.Helper() },
+			configureMocks: func(t *testing.T, _ *mocks4.ExecutorMock, _ *url.URL, _ *config2.BackendTLS) { t.Helper() },
 			assertResponse: func(t *testing.T, err error, upstreamCalled bool, resp *http.Response) {
 				t.Helper()
 
@@ -676,7 +696,7 @@ func TestProxyService(t *testing.T) {
 
 				return req
 			},
-			configureMocks: func(t *testing.T, _ *mocks4.ExecutorMock, upstreamURL *url.URL) { t.Helper() },
+			configureMocks: func(t *testing.T, _ *mocks4.ExecutorMock, _ *url.URL, _ *config2.BackendTLS) { t.Helper() },
 			assertResponse: func(t *testing.T, err error, upstreamCalled bool, resp *http.Response) {
 				t.Helper()
 
@@ -724,7 +744,7 @@ func TestProxyService(t *testing.T) {
 
 				return req
 			},
-			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL) {
+			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL, upstreamTLS *config2.BackendTLS) {
 				t.Helper()
 
 				backend := mocks4.NewBackendMock(t)
@@ -733,6 +753,10 @@ func TestProxyService(t *testing.T) {
 					Host:   upstreamURL.Host,
 					Path:   "/bar",
 				})
+				backend.EXPECT().TLS().Return(upstreamTLS)
+				backend.EXPECT().RewriteRequestHeaders(mock.Anything)
+				backend.EXPECT().RewriteResponseHeaders(mock.Anything)
+				backend.EXPECT().Done(nil)
 
 				exec.EXPECT().Execute(
 					mock.MatchedBy(func(ctx heimdall.Context) bool {
@@ -819,7 +843,7 @@ func TestProxyService(t *testing.T) {
 
 				return req
 			},
-			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL) {
+			configureMocks: func(t *testing.T, exec *mocks4.ExecutorMock, upstreamURL *url.URL, upstreamTLS *config2.BackendTLS) {
 				t.Helper()
 
 				backend := mocks4.NewBackendMock(t)
@@ -828,6 +852,10 @@ func TestProxyService(t *testing.T) {
 					Host:   upstreamURL.Host,
 					Path:   "/bar",
 				})
+				backend.EXPECT().TLS().Return(upstreamTLS)
+				backend.EXPECT().RewriteRequestHeaders(mock.Anything)
+				backend.EXPECT().RewriteResponseHeaders(mock.Anything)
+				backend.EXPECT().Done(nil)
 
 				exec.EXPECT().Execute(
 					mock.MatchedBy(func(ctx heimdall.Context) bool {
@@ -906,13 +934,26 @@ func TestProxyService(t *testing.T) {
 			upstreamSrv.EnableHTTP2 = !tc.disableHTTP2
 			upstreamSrv.StartTLS()
 
-			certPool := x509.NewCertPool()
-			certPool.AddCert(upstreamSrv.Certificate())
-			tlsClientConfig = &tls.Config{RootCAs: certPool} //nolint:gosec
+			trustStoreFile := filepath.Join(t.TempDir(), "truststore.pem")
+			certPEM, err := pemx.BuildPEM(pemx.WithX509Certificate(upstreamSrv.Certificate()))
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(trustStoreFile, certPEM, 0o600))
+
+			upstreamTLS := &config2.BackendTLS{TrustStore: "upstream-ca"}
 
 			upstreamURL, err := url.Parse(upstreamSrv.URL)
 			require.NoError(t, err)
 
+			upstreams := newTestUpstreamRegistry(t, upstream.WithTLSStores(config.UpstreamTLS{
+				TrustStores: map[string]string{"upstream-ca": trustStoreFile},
+			}))
+
+			// that is what the rule forwarding the request to the upstream would do
+			pool := upstreams.NewPool(&config2.Backend{Host: upstreamURL.Host, TLS: upstreamTLS})
+			pool.Acquire()
+
+			defer pool.Release()
+
 			createClient := x.IfThenElse(tc.createClient != nil,
 				tc.createClient,
 				func(t *testing.T) *http.Client {
@@ -938,11 +979,11 @@ func TestProxyService(t *testing.T) {
 			cch := mocks.NewCacheMock(t)
 			exec := mocks4.NewExecutorMock(t)
 
-			tc.configureMocks(t, exec, upstreamURL)
+			tc.configureMocks(t, exec, upstreamURL, upstreamTLS)
 
 			client := createClient(t)
 
-			proxy := newService(conf, cch, log.Logger, exec, nil)
+			proxy := newService(conf, config.NewWatcher(false), cch, log.Logger, exec, nil, audit.NewNoopAuditor(), upstreams)
 
 			defer proxy.Shutdown(context.Background())
 
@@ -1022,6 +1063,10 @@ func TestWebSocketSupport(t *testing.T) {
 		Host:   upstreamURL.Host,
 		Path:   "/bar",
 	})
+	backend.EXPECT().TLS().Return(nil)
+	backend.EXPECT().RewriteRequestHeaders(mock.Anything)
+	backend.EXPECT().RewriteResponseHeaders(mock.Anything)
+	backend.EXPECT().Done(mock.Anything).Maybe()
 
 	exec.EXPECT().Execute(
 		mock.MatchedBy(func(ctx heimdall.Context) bool {
@@ -1046,7 +1091,7 @@ func TestWebSocketSupport(t *testing.T) {
 		},
 	}
 
-	proxy := newService(conf, mocks.NewCacheMock(t), log.Logger, exec, nil)
+	proxy := newService(conf, config.NewWatcher(false), mocks.NewCacheMock(t), log.Logger, exec, nil, audit.NewNoopAuditor(), newTestUpstreamRegistry(t))
 
 	defer proxy.Shutdown(context.Background())
 
@@ -1122,6 +1167,10 @@ func TestServerSentEventsSupport(t *testing.T) {
 		Host:   upstreamURL.Host,
 		Path:   "/bar",
 	})
+	backend.EXPECT().TLS().Return(nil)
+	backend.EXPECT().RewriteRequestHeaders(mock.Anything)
+	backend.EXPECT().RewriteResponseHeaders(mock.Anything)
+	backend.EXPECT().Done(mock.Anything).Maybe()
 
 	exec.EXPECT().Execute(
 		mock.MatchedBy(func(ctx heimdall.Context) bool {
@@ -1146,7 +1195,7 @@ func TestServerSentEventsSupport(t *testing.T) {
 		},
 	}
 
-	proxy := newService(conf, mocks.NewCacheMock(t), log.Logger, exec, nil)
+	proxy := newService(conf, config.NewWatcher(false), mocks.NewCacheMock(t), log.Logger, exec, nil, audit.NewNoopAuditor(), newTestUpstreamRegistry(t))
 
 	defer proxy.Shutdown(context.Background())
 
@@ -1182,3 +1231,12 @@ func TestServerSentEventsSupport(t *testing.T) {
 
 	time.Sleep(60 * time.Millisecond)
 }
+
+func newTestUpstreamRegistry(t *testing.T, opts ...upstream.Option) *upstream.Registry {
+	t.Helper()
+
+	registry, err := upstream.NewRegistry(log.Logger, noop.NewMeterProvider(), opts...)
+	require.NoError(t, err)
+
+	return registry
+}
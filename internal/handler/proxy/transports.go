@@ -0,0 +1,120 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	config2 "github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/rules/upstream"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+// transports holds one http.Transport per distinct upstream tls configuration used by
+// the active rules, so that connections to the upstreams can be reused across requests
+// and rules.
+type transports struct {
+	cfg       config.ServiceConfig
+	upstreams *upstream.Registry
+
+	mut       sync.Mutex
+	def       *http.Transport
+	perConfig map[config2.BackendTLS]*http.Transport
+}
+
+func newTransports(cfg config.ServiceConfig, upstreams *upstream.Registry) *transports {
+	return &transports{
+		cfg:       cfg,
+		upstreams: upstreams,
+		def:       newTransport(cfg, nil),
+		perConfig: make(map[config2.BackendTLS]*http.Transport),
+	}
+}
+
+func (t *transports) get(tlsConf *config2.BackendTLS) (*http.Transport, error) {
+	if tlsConf == nil {
+		return t.def, nil
+	}
+
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	if transport, ok := t.perConfig[*tlsConf]; ok {
+		return transport, nil
+	}
+
+	tlsCfg, err := t.upstreams.TLSConfig(*tlsConf)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"failed to create tls configuration for the upstream").CausedBy(err)
+	}
+
+	transport := newTransport(t.cfg, tlsCfg)
+
+	// requests still in flight for a rule, which has been removed, must not recreate a cache
+	// entry, as the configuration has been released already and the entry would never be
+	// evicted. Such requests get a transport, which does not keep idle connections.
+	// The check happens while holding the lock, release waits for, so a configuration
+	// released afterwards is evicted by that release call.
+	if !t.upstreams.TLSConfigInUse(*tlsConf) {
+		transport.DisableKeepAlives = true
+
+		return transport, nil
+	}
+
+	t.perConfig[*tlsConf] = transport
+
+	return transport, nil
+}
+
+// release drops the transport created for the given tls configuration and closes its idle
+// connections. It is called when no rule is using that configuration anymore.
+func (t *transports) release(tlsConf config2.BackendTLS) {
+	t.mut.Lock()
+	transport, ok := t.perConfig[tlsConf]
+	delete(t.perConfig, tlsConf)
+	t.mut.Unlock()
+
+	if ok {
+		transport.CloseIdleConnections()
+	}
+}
+
+func newTransport(cfg config.ServiceConfig, tlsCfg *tls.Config) *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second, //nolint:gomnd
+			KeepAlive: 30 * time.Second, //nolint:gomnd
+		}).DialContext,
+		ResponseHeaderTimeout: cfg.Timeout.Read,
+		MaxIdleConns:          cfg.ConnectionsLimit.MaxIdle,
+		MaxIdleConnsPerHost:   cfg.ConnectionsLimit.MaxIdlePerHost,
+		MaxConnsPerHost:       cfg.ConnectionsLimit.MaxPerHost,
+		IdleConnTimeout:       cfg.Timeout.Idle,
+		TLSHandshakeTimeout:   10 * time.Second, //nolint:gomnd
+		ExpectContinueTimeout: 1 * time.Second,
+		ForceAttemptHTTP2:     true,
+		TLSClientConfig:       tlsCfg,
+	}
+}
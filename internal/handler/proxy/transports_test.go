@@ -0,0 +1,248 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	config2 "github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/rules/upstream"
+	"github.com/dadrus/heimdall/internal/x/pkix/pemx"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestTransportsGet(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	upstreams, err := upstream.NewRegistry(log.Logger, noop.NewMeterProvider(),
+		upstream.WithTLSStores(config.UpstreamTLS{
+			TrustStores: map[string]string{"missing": "/does/not/exist.pem"},
+		}))
+	require.NoError(t, err)
+
+	trs := newTransports(config.ServiceConfig{}, upstreams)
+	upstreams.OnTLSConfigReleased(trs.release)
+
+	fooPool := upstreams.NewPool(&config2.Backend{Host: "foo.local", TLS: &config2.BackendTLS{ServerName: "foo"}})
+	fooPool.Acquire()
+
+	barPool := upstreams.NewPool(&config2.Backend{Host: "bar.local", TLS: &config2.BackendTLS{ServerName: "bar"}})
+	barPool.Acquire()
+
+	defer barPool.Release()
+
+	// WHEN
+	def, err := trs.get(nil)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Nil(t, def.TLSClientConfig)
+
+	// WHEN
+	first, err := trs.get(&config2.BackendTLS{ServerName: "foo"})
+
+	// THEN
+	require.NoError(t, err)
+	assert.NotSame(t, def, first)
+	assert.Equal(t, "foo", first.TLSClientConfig.ServerName)
+
+	// WHEN
+	second, err := trs.get(&config2.BackendTLS{ServerName: "foo"})
+
+	// THEN
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	// WHEN
+	third, err := trs.get(&config2.BackendTLS{ServerName: "bar"})
+
+	// THEN
+	require.NoError(t, err)
+	assert.NotSame(t, first, third)
+	assert.Equal(t, "bar", third.TLSClientConfig.ServerName)
+
+	// WHEN
+	_, err = trs.get(&config2.BackendTLS{TrustStore: "missing"})
+
+	// THEN
+	require.Error(t, err)
+	require.ErrorIs(t, err, heimdall.ErrConfiguration)
+
+	// WHEN
+	fooPool.Release()
+	fourth, err := trs.get(&config2.BackendTLS{ServerName: "foo"})
+
+	// THEN
+	// a request in flight for a released configuration must not recreate the cache entry
+	require.NoError(t, err)
+	assert.NotSame(t, first, fourth)
+	assert.True(t, fourth.DisableKeepAlives)
+	assert.NotContains(t, trs.perConfig, config2.BackendTLS{ServerName: "foo"})
+	assert.Same(t, third, trs.perConfig[config2.BackendTLS{ServerName: "bar"}])
+}
+
+func TestTransportsWithMutualTLS(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	testDir := t.TempDir()
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	clientCert, err := testsupport.NewCertificateBuilder(
+		testsupport.WithSerialNumber(big.NewInt(1)),
+		testsupport.WithValidity(time.Now(), 10*time.Hour),
+		testsupport.WithSubject(pkix.Name{
+			CommonName:   "heimdall",
+			Organization: []string{"Test"},
+			Country:      []string{"EU"},
+		}),
+		testsupport.WithSubjectPubKey(&clientKey.PublicKey, x509.ECDSAWithSHA256),
+		testsupport.WithSignaturePrivKey(clientKey),
+		testsupport.WithKeyUsage(x509.KeyUsageDigitalSignature),
+		testsupport.WithExtendedKeyUsage(x509.ExtKeyUsageClientAuth),
+		testsupport.WithGeneratedSubjectKeyID(),
+		testsupport.WithSelfSigned(),
+	).Build()
+	require.NoError(t, err)
+
+	keyStorePEM, err := pemx.BuildPEM(
+		pemx.WithECDSAPrivateKey(clientKey),
+		pemx.WithX509Certificate(clientCert),
+	)
+	require.NoError(t, err)
+
+	keyStoreFile := filepath.Join(testDir, "keystore.pem")
+	require.NoError(t, os.WriteFile(keyStoreFile, keyStorePEM, 0o600))
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+
+	var clientCN string
+
+	upstreamSrv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		clientCN = req.TLS.PeerCertificates[0].Subject.CommonName
+
+		rw.WriteHeader(http.StatusOK)
+	}))
+	upstreamSrv.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+	upstreamSrv.StartTLS()
+
+	defer upstreamSrv.Close()
+
+	trustStorePEM, err := pemx.BuildPEM(pemx.WithX509Certificate(upstreamSrv.Certificate()))
+	require.NoError(t, err)
+
+	trustStoreFile := filepath.Join(testDir, "truststore.pem")
+	require.NoError(t, os.WriteFile(trustStoreFile, trustStorePEM, 0o600))
+
+	upstreams, err := upstream.NewRegistry(log.Logger, noop.NewMeterProvider(),
+		upstream.WithTLSStores(config.UpstreamTLS{
+			KeyStores:   map[string]config.KeyStore{"client": {Path: keyStoreFile}},
+			TrustStores: map[string]string{"upstream-ca": trustStoreFile},
+		}))
+	require.NoError(t, err)
+
+	trs := newTransports(config.ServiceConfig{}, upstreams)
+
+	for _, tc := range []struct {
+		uc     string
+		conf   *config2.BackendTLS
+		assert func(t *testing.T, err error, resp *http.Response)
+	}{
+		{
+			uc: "without tls configuration",
+			assert: func(t *testing.T, err error, _ *http.Response) {
+				t.Helper()
+
+				require.Error(t, err)
+
+				var certErr *tls.CertificateVerificationError
+
+				require.ErrorAs(t, err, &certErr)
+			},
+		},
+		{
+			uc:   "with trust store only",
+			conf: &config2.BackendTLS{TrustStore: "upstream-ca"},
+			assert: func(t *testing.T, err error, _ *http.Response) {
+				t.Helper()
+
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "certificate")
+			},
+		},
+		{
+			uc: "with trust store and client certificate",
+			conf: &config2.BackendTLS{
+				TrustStore: "upstream-ca",
+				KeyStore:   "client",
+			},
+			assert: func(t *testing.T, err error, resp *http.Response) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, "heimdall", clientCN)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			transport, err := trs.get(tc.conf)
+			require.NoError(t, err)
+
+			req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, upstreamSrv.URL, nil)
+			require.NoError(t, err)
+
+			// WHEN
+			resp, err := transport.RoundTrip(req)
+
+			// THEN
+			if err == nil {
+				defer resp.Body.Close()
+			}
+
+			tc.assert(t, err, resp)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package reloadable
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/dadrus/heimdall/internal/config"
+)
+
+// HandlerFactory creates a http.Handler from the given configuration.
+type HandlerFactory func(conf *config.Configuration) http.Handler
+
+// Handler is a http.Handler, which delegates to the handler created from the current heimdall
+// configuration. On configuration changes a new handler is created and swapped in atomically,
+// so that requests in flight are still served by the previous one.
+type Handler struct {
+	create  HandlerFactory
+	handler atomic.Pointer[http.Handler]
+}
+
+func NewHandler(conf *config.Configuration, watcher *config.Watcher, create HandlerFactory) *Handler {
+	rh := &Handler{create: create}
+	rh.set(create(conf))
+
+	watcher.Subscribe(rh)
+
+	return rh
+}
+
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	(*h.handler.Load()).ServeHTTP(rw, req)
+}
+
+func (h *Handler) OnConfigurationChanged(conf *config.Configuration) error {
+	h.set(h.create(conf))
+
+	return nil
+}
+
+func (h *Handler) set(handler http.Handler) { h.handler.Store(&handler) }
@@ -0,0 +1,66 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package reloadable
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/config"
+)
+
+func TestHandlerOnConfigurationChanged(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	conf := &config.Configuration{}
+	conf.Serve.Proxy.Respond.With.NoRuleError.Code = http.StatusNotFound
+
+	handler := NewHandler(conf, config.NewWatcher(false), func(conf *config.Configuration) http.Handler {
+		code := conf.Serve.Proxy.Respond.With.NoRuleError.Code
+
+		return http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) { rw.WriteHeader(code) })
+	})
+
+	serve := func() int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		return rec.Code
+	}
+
+	// WHEN
+	code := serve()
+
+	// THEN
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// WHEN
+	newConf := &config.Configuration{}
+	newConf.Serve.Proxy.Respond.With.NoRuleError.Code = http.StatusForbidden
+
+	err := handler.OnConfigurationChanged(newConf)
+	code = serve()
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, code)
+}
@@ -0,0 +1,65 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package reloadable
+
+import (
+	"context"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+
+	"github.com/dadrus/heimdall/internal/config"
+)
+
+// UnaryInterceptorFactory creates a grpc.UnaryServerInterceptor from the given configuration.
+type UnaryInterceptorFactory func(conf *config.Configuration) grpc.UnaryServerInterceptor
+
+// UnaryInterceptor delegates to the grpc.UnaryServerInterceptor created from the current heimdall
+// configuration and recreates it on configuration changes.
+type UnaryInterceptor struct {
+	create      UnaryInterceptorFactory
+	interceptor atomic.Pointer[grpc.UnaryServerInterceptor]
+}
+
+func NewUnaryInterceptor(
+	conf *config.Configuration, watcher *config.Watcher, create UnaryInterceptorFactory,
+) *UnaryInterceptor {
+	ri := &UnaryInterceptor{create: create}
+	ri.set(create(conf))
+
+	watcher.Subscribe(ri)
+
+	return ri
+}
+
+func (i *UnaryInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+	) (any, error) {
+		return (*i.interceptor.Load())(ctx, req, info, handler)
+	}
+}
+
+func (i *UnaryInterceptor) OnConfigurationChanged(conf *config.Configuration) error {
+	i.set(i.create(conf))
+
+	return nil
+}
+
+func (i *UnaryInterceptor) set(interceptor grpc.UnaryServerInterceptor) {
+	i.interceptor.Store(&interceptor)
+}
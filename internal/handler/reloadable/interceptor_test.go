@@ -0,0 +1,74 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package reloadable
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/dadrus/heimdall/internal/config"
+)
+
+func TestUnaryInterceptorOnConfigurationChanged(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	conf := &config.Configuration{}
+	conf.Serve.Decision.Respond.With.NoRuleError.Code = 404
+
+	interceptor := NewUnaryInterceptor(conf, config.NewWatcher(false),
+		func(conf *config.Configuration) grpc.UnaryServerInterceptor {
+			code := conf.Serve.Decision.Respond.With.NoRuleError.Code
+
+			return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+				res, err := handler(ctx, req)
+				require.NoError(t, err)
+
+				return []any{res, code}, nil
+			}
+		},
+	)
+
+	call := func() any {
+		res, err := interceptor.Unary()(context.Background(), "foo", &grpc.UnaryServerInfo{},
+			func(_ context.Context, req any) (any, error) { return req, nil })
+		require.NoError(t, err)
+
+		return res
+	}
+
+	// WHEN
+	res := call()
+
+	// THEN
+	assert.Equal(t, []any{"foo", 404}, res)
+
+	// WHEN
+	newConf := &config.Configuration{}
+	newConf.Serve.Decision.Respond.With.NoRuleError.Code = 403
+
+	err := interceptor.OnConfigurationChanged(newConf)
+	res = call()
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, []any{"foo", 403}, res)
+}
@@ -0,0 +1,139 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package requestcontext
+
+import (
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/dadrus/heimdall/internal/truststore"
+)
+
+const (
+	headerForwardedClientCert = "X-Forwarded-Client-Cert"
+	headerSSLClientCert       = "Ssl-Client-Cert"
+)
+
+// forwardedClientCertificates returns the client certificate chain forwarded by a proxy, which terminated
+// the TLS connection. Envoy's X-Forwarded-Client-Cert and NGINX' ssl-client-cert headers are supported.
+// Both are expected to hold URL encoded PEM data. If the certificates cannot be parsed, nil is returned.
+func forwardedClientCertificates(header http.Header) []*x509.Certificate {
+	if value := header.Get(headerForwardedClientCert); len(value) != 0 {
+		return certificatesFromXFCC(value)
+	}
+
+	if value := header.Get(headerSSLClientCert); len(value) != 0 {
+		return certificatesFromURLEncodedPEM(value)
+	}
+
+	return nil
+}
+
+// certificatesFromXFCC extracts the certificates from the X-Forwarded-Client-Cert header as
+// defined by envoy. If the header holds multiple elements, the last one is used, as it is
+// the one added by the proxy closest to heimdall. The Chain key is preferred over the Cert key.
+func certificatesFromXFCC(value string) []*x509.Certificate {
+	elements := splitXFCC(value, ',')
+	if len(elements) == 0 {
+		return nil
+	}
+
+	var cert, chain string
+
+	for _, pair := range splitXFCC(elements[len(elements)-1], ';') {
+		key, val, found := strings.Cut(pair, "=")
+		if !found {
+			continue
+		}
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "cert":
+			cert = unquote(val)
+		case "chain":
+			chain = unquote(val)
+		}
+	}
+
+	if len(chain) != 0 {
+		return certificatesFromURLEncodedPEM(chain)
+	}
+
+	return certificatesFromURLEncodedPEM(cert)
+}
+
+func certificatesFromURLEncodedPEM(value string) []*x509.Certificate {
+	if len(value) == 0 {
+		return nil
+	}
+
+	// PathUnescape is used by intention, as the base64 encoded PEM contents may include
+	// "+" characters, which would be converted to spaces by QueryUnescape
+	pemData, err := url.PathUnescape(value)
+	if err != nil {
+		return nil
+	}
+
+	certs, err := truststore.NewTrustStoreFromPEMBytes([]byte(pemData), true)
+	if err != nil || len(certs) == 0 {
+		return nil
+	}
+
+	return certs
+}
+
+// splitXFCC splits the given value by the given separator ignoring separators
+// appearing in quoted strings.
+func splitXFCC(value string, separator rune) []string {
+	var (
+		parts    []string
+		quoted   bool
+		escaped  bool
+		startIdx int
+	)
+
+	for idx, char := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case char == '\\' && quoted:
+			escaped = true
+		case char == '"':
+			quoted = !quoted
+		case char == separator && !quoted:
+			parts = append(parts, strings.TrimSpace(value[startIdx:idx]))
+			startIdx = idx + 1
+		}
+	}
+
+	if last := strings.TrimSpace(value[startIdx:]); len(last) != 0 {
+		parts = append(parts, last)
+	}
+
+	return parts
+}
+
+func unquote(value string) string {
+	value = strings.TrimSpace(value)
+
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		return strings.ReplaceAll(value[1:len(value)-1], `\"`, `"`)
+	}
+
+	return value
+}
@@ -0,0 +1,206 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package requestcontext
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/x/pkix/pemx"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestRequestContextClientCertificates(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	rootCA, err := testsupport.NewRootCA("Test Root CA", 24*time.Hour)
+	require.NoError(t, err)
+
+	intCAPrivKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	intCACert, err := rootCA.IssueCertificate(
+		testsupport.WithSubject(pkix.Name{CommonName: "Test Int CA"}),
+		testsupport.WithIsCA(),
+		testsupport.WithValidity(time.Now(), 24*time.Hour),
+		testsupport.WithSubjectPubKey(&intCAPrivKey.PublicKey, x509.ECDSAWithSHA384))
+	require.NoError(t, err)
+	intCA := testsupport.NewCA(intCAPrivKey, intCACert)
+
+	eePrivKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	eeCert, err := intCA.IssueCertificate(
+		testsupport.WithSubject(pkix.Name{CommonName: "Test Client"}),
+		testsupport.WithValidity(time.Now(), 24*time.Hour),
+		testsupport.WithSubjectPubKey(&eePrivKey.PublicKey, x509.ECDSAWithSHA256))
+	require.NoError(t, err)
+
+	eePEM, err := pemx.BuildPEM(pemx.WithX509Certificate(eeCert))
+	require.NoError(t, err)
+
+	chainPEM, err := pemx.BuildPEM(pemx.WithX509Certificate(eeCert), pemx.WithX509Certificate(intCACert))
+	require.NoError(t, err)
+
+	rootPEM, err := pemx.BuildPEM(pemx.WithX509Certificate(rootCA.Certificate))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		uc               string
+		configureRequest func(t *testing.T, req *http.Request)
+		assert           func(t *testing.T, certs []*x509.Certificate)
+	}{
+		{
+			uc:               "no client certificates present",
+			configureRequest: func(t *testing.T, _ *http.Request) { t.Helper() },
+			assert: func(t *testing.T, certs []*x509.Certificate) {
+				t.Helper()
+
+				assert.Empty(t, certs)
+			},
+		},
+		{
+			uc: "certificates from the TLS connection",
+			configureRequest: func(t *testing.T, req *http.Request) {
+				t.Helper()
+
+				req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{eeCert, intCACert}}
+				req.Header.Set("Ssl-Client-Cert", url.PathEscape(string(rootPEM)))
+			},
+			assert: func(t *testing.T, certs []*x509.Certificate) {
+				t.Helper()
+
+				require.Len(t, certs, 2)
+				assert.Equal(t, eeCert, certs[0])
+				assert.Equal(t, intCACert, certs[1])
+			},
+		},
+		{
+			uc: "certificate from the X-Forwarded-Client-Cert header Cert key",
+			configureRequest: func(t *testing.T, req *http.Request) {
+				t.Helper()
+
+				req.Header.Set("X-Forwarded-Client-Cert",
+					`By=spiffe://foo.bar/heimdall;Hash=1234;Cert="`+url.PathEscape(string(eePEM))+
+						`";Subject="CN=Test Client,O=Test";URI=spiffe://foo.bar/client`)
+			},
+			assert: func(t *testing.T, certs []*x509.Certificate) {
+				t.Helper()
+
+				require.Len(t, certs, 1)
+				assert.True(t, eeCert.Equal(certs[0]))
+			},
+		},
+		{
+			uc: "certificates from the X-Forwarded-Client-Cert header Chain key",
+			configureRequest: func(t *testing.T, req *http.Request) {
+				t.Helper()
+
+				req.Header.Set("X-Forwarded-Client-Cert",
+					`Hash=1234;Cert="`+url.PathEscape(string(eePEM))+`";Chain="`+url.PathEscape(string(chainPEM))+`"`)
+			},
+			assert: func(t *testing.T, certs []*x509.Certificate) {
+				t.Helper()
+
+				require.Len(t, certs, 2)
+				assert.True(t, eeCert.Equal(certs[0]))
+				assert.True(t, intCACert.Equal(certs[1]))
+			},
+		},
+		{
+			uc: "certificate from the last element of the X-Forwarded-Client-Cert header",
+			configureRequest: func(t *testing.T, req *http.Request) {
+				t.Helper()
+
+				req.Header.Set("X-Forwarded-Client-Cert",
+					`Cert="`+url.PathEscape(string(rootPEM))+`";Subject="CN=foo,O=\"bar;baz\"",`+
+						`Cert="`+url.PathEscape(string(eePEM))+`"`)
+			},
+			assert: func(t *testing.T, certs []*x509.Certificate) {
+				t.Helper()
+
+				require.Len(t, certs, 1)
+				assert.True(t, eeCert.Equal(certs[0]))
+			},
+		},
+		{
+			uc: "malformed X-Forwarded-Client-Cert header",
+			configureRequest: func(t *testing.T, req *http.Request) {
+				t.Helper()
+
+				req.Header.Set("X-Forwarded-Client-Cert", `Hash=1234;Cert="foo%ZZbar"`)
+			},
+			assert: func(t *testing.T, certs []*x509.Certificate) {
+				t.Helper()
+
+				assert.Empty(t, certs)
+			},
+		},
+		{
+			uc: "certificates from the ssl-client-cert header",
+			configureRequest: func(t *testing.T, req *http.Request) {
+				t.Helper()
+
+				req.Header.Set("ssl-client-cert", url.PathEscape(string(chainPEM)))
+			},
+			assert: func(t *testing.T, certs []*x509.Certificate) {
+				t.Helper()
+
+				require.Len(t, certs, 2)
+				assert.True(t, eeCert.Equal(certs[0]))
+				assert.True(t, intCACert.Equal(certs[1]))
+			},
+		},
+		{
+			uc: "ssl-client-cert header with unexpected contents",
+			configureRequest: func(t *testing.T, req *http.Request) {
+				t.Helper()
+
+				req.Header.Set("ssl-client-cert", "foobar")
+			},
+			assert: func(t *testing.T, certs []*x509.Certificate) {
+				t.Helper()
+
+				assert.Empty(t, certs)
+			},
+		},
+	} {
+		t.Run(tc.uc, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "https://foo.bar/test", nil)
+			tc.configureRequest(t, req)
+
+			ctx := New(nil, req)
+
+			// WHEN
+			certs := ctx.Request().ClientCertificates()
+
+			// THEN
+			tc.assert(t, certs)
+		})
+	}
+}
@@ -19,10 +19,10 @@ package requestcontext
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
 	"io"
 	"net/http"
 	"net/textproto"
-	"net/url"
 	"strings"
 
 	"github.com/dadrus/heimdall/internal/heimdall"
@@ -33,25 +33,28 @@ import (
 
 type RequestContext struct {
 	reqMethod       string
-	reqURL          *url.URL
+	reqURL          *heimdall.URL
 	upstreamHeaders http.Header
 	upstreamCookies map[string]string
+	upstreamSigners []heimdall.UpstreamRequestSigner
 	jwtSigner       heimdall.JWTSigner
 	req             *http.Request
 	err             error
 
 	// the following properties are created lazy and cached
 
-	savedBody any
-	hmdlReq   *heimdall.Request
-	headers   map[string]string
+	rawBody     []byte
+	savedBody   any
+	hmdlReq     *heimdall.Request
+	headers     map[string]string
+	clientCerts []*x509.Certificate
 }
 
 func New(signer heimdall.JWTSigner, req *http.Request) *RequestContext {
 	return &RequestContext{
 		jwtSigner:       signer,
 		reqMethod:       extractMethod(req),
-		reqURL:          extractURL(req),
+		reqURL:          &heimdall.URL{URL: *extractURL(req)},
 		upstreamHeaders: make(http.Header),
 		upstreamCookies: make(map[string]string),
 		req:             req,
@@ -67,6 +70,15 @@ func (r *RequestContext) Header(name string) string {
 	return strings.Join(r.req.Header.Values(key), ",")
 }
 
+func (r *RequestContext) HeaderValues(name string) []string {
+	key := textproto.CanonicalMIMEHeaderKey(name)
+	if key == "Host" {
+		return []string{r.req.Host}
+	}
+
+	return r.req.Header.Values(key)
+}
+
 func (r *RequestContext) Cookie(name string) string {
 	if cookie, err := r.req.Cookie(name); err == nil {
 		return cookie.Value
@@ -89,24 +101,12 @@ func (r *RequestContext) Headers() map[string]string {
 }
 
 func (r *RequestContext) Body() any {
-	if r.req.Body == nil || r.req.Body == http.NoBody {
-		return ""
-	}
-
 	if r.savedBody == nil {
-		// drain body by reading its contents into memory and preserving
-		var buf bytes.Buffer
-		if _, err := buf.ReadFrom(r.req.Body); err != nil {
-			return ""
-		}
-
-		if err := r.req.Body.Close(); err != nil {
+		body := r.RawBody()
+		if len(body) == 0 {
 			return ""
 		}
 
-		body := buf.Bytes()
-		r.req.Body = io.NopCloser(bytes.NewReader(body))
-
 		decoder, err := contenttype.NewDecoder(r.Header("Content-Type"))
 		if err != nil {
 			r.savedBody = string(body)
@@ -127,6 +127,41 @@ func (r *RequestContext) Body() any {
 	return r.savedBody
 }
 
+func (r *RequestContext) RawBody() []byte {
+	if r.req.Body == nil || r.req.Body == http.NoBody {
+		return nil
+	}
+
+	if r.rawBody == nil {
+		// drain body by reading its contents into memory and preserving
+		var buf bytes.Buffer
+		if _, err := buf.ReadFrom(r.req.Body); err != nil {
+			return nil
+		}
+
+		if err := r.req.Body.Close(); err != nil {
+			return nil
+		}
+
+		r.rawBody = buf.Bytes()
+		r.req.Body = io.NopCloser(bytes.NewReader(r.rawBody))
+	}
+
+	return r.rawBody
+}
+
+func (r *RequestContext) ClientCertificates() []*x509.Certificate {
+	if r.clientCerts == nil {
+		if r.req.TLS != nil && len(r.req.TLS.PeerCertificates) != 0 {
+			r.clientCerts = r.req.TLS.PeerCertificates
+		} else {
+			r.clientCerts = forwardedClientCertificates(r.req.Header)
+		}
+	}
+
+	return r.clientCerts
+}
+
 func (r *RequestContext) Request() *heimdall.Request {
 	if r.hmdlReq == nil {
 		r.hmdlReq = &heimdall.Request{
@@ -175,3 +210,11 @@ func (r *RequestContext) AppContext() context.Context             { return r.req
 func (r *RequestContext) SetPipelineError(err error)              { r.err = err }
 func (r *RequestContext) PipelineError() error                    { return r.err }
 func (r *RequestContext) Signer() heimdall.JWTSigner              { return r.jwtSigner }
+
+func (r *RequestContext) AddUpstreamRequestSigner(signer heimdall.UpstreamRequestSigner) {
+	r.upstreamSigners = append(r.upstreamSigners, signer)
+}
+
+func (r *RequestContext) UpstreamRequestSigners() []heimdall.UpstreamRequestSigner {
+	return r.upstreamSigners
+}
@@ -155,6 +155,28 @@ func TestRequestContextHeader(t *testing.T) {
 	assert.Empty(t, emptyValue)
 }
 
+func TestRequestContextHeaderValues(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	req := httptest.NewRequest(http.MethodHead, "https://foo.bar/test", nil)
+	req.Header.Set("X-Foo-Bar", "foo")
+	req.Header.Add("X-Foo-Bar", "bar")
+	req.Host = "bar.foo"
+
+	ctx := New(nil, req)
+
+	// WHEN
+	xFooBarValues := ctx.Request().HeaderValues("x-foo-bar")
+	hostValues := ctx.Request().HeaderValues("Host")
+	emptyValues := ctx.Request().HeaderValues("X-Not-Present")
+
+	// THEN
+	assert.Equal(t, []string{"foo", "bar"}, xFooBarValues)
+	assert.Equal(t, []string{"bar.foo"}, hostValues)
+	assert.Empty(t, emptyValues)
+}
+
 func TestRequestContextCookie(t *testing.T) {
 	t.Parallel()
 
@@ -240,3 +262,26 @@ func TestRequestContextBody(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestContextRawBody(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	req := httptest.NewRequest(http.MethodPost, "https://foo.bar/test", bytes.NewBufferString(`{ "content": "heimdall" }`))
+	req.Header.Set("Content-Type", "application/json")
+
+	ctx := New(nil, req)
+
+	// WHEN
+	decoded := ctx.Request().Body()
+	raw := ctx.Request().RawBody()
+
+	// THEN
+	assert.Equal(t, map[string]any{"content": "heimdall"}, decoded)
+	assert.Equal(t, `{ "content": "heimdall" }`, string(raw))
+
+	// the body is still available for forwarding
+	forwarded, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, raw, forwarded)
+}
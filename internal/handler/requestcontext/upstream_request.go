@@ -0,0 +1,111 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package requestcontext
+
+import (
+	"crypto/x509"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+)
+
+// SignUpstreamRequest applies the given signers in the given order to the request, which represents
+// the request forwarded to the upstream service. The headers returned by the signers are added to
+// the request, so that subsequent signers can cover these as well. All added headers are returned.
+func SignUpstreamRequest(req *http.Request, signers []heimdall.UpstreamRequestSigner) (http.Header, error) {
+	added := make(http.Header)
+
+	for _, sign := range signers {
+		headers, err := sign(&heimdall.Request{
+			RequestFunctions: upstreamRequest{req: req},
+			Method:           req.Method,
+			URL:              &heimdall.URL{URL: upstreamURL(req)},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for name, values := range headers {
+			for _, value := range values {
+				req.Header.Add(name, value)
+				added.Add(name, value)
+			}
+		}
+	}
+
+	return added, nil
+}
+
+func upstreamURL(req *http.Request) url.URL {
+	reqURL := *req.URL
+	if len(req.Host) != 0 {
+		reqURL.Host = req.Host
+	}
+
+	return reqURL
+}
+
+// upstreamRequest gives access to the headers of the request forwarded to the upstream service.
+type upstreamRequest struct {
+	req *http.Request
+}
+
+func (r upstreamRequest) Header(name string) string {
+	key := textproto.CanonicalMIMEHeaderKey(name)
+	if key == "Host" {
+		return upstreamURL(r.req).Host
+	}
+
+	return strings.Join(r.req.Header.Values(key), ",")
+}
+
+func (r upstreamRequest) HeaderValues(name string) []string {
+	key := textproto.CanonicalMIMEHeaderKey(name)
+	if key == "Host" {
+		return []string{upstreamURL(r.req).Host}
+	}
+
+	return r.req.Header.Values(key)
+}
+
+func (r upstreamRequest) Cookie(name string) string {
+	if cookie, err := r.req.Cookie(name); err == nil {
+		return cookie.Value
+	}
+
+	return ""
+}
+
+func (r upstreamRequest) Headers() map[string]string {
+	headers := make(map[string]string, len(r.req.Header)+1)
+
+	headers["Host"] = upstreamURL(r.req).Host
+	for k, v := range r.req.Header {
+		headers[textproto.CanonicalMIMEHeaderKey(k)] = strings.Join(v, ",")
+	}
+
+	return headers
+}
+
+func (r upstreamRequest) Body() any { return "" }
+
+func (r upstreamRequest) RawBody() []byte { return nil }
+
+func (r upstreamRequest) ClientCertificates() []*x509.Certificate { return nil }
@@ -0,0 +1,108 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package requestcontext
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+)
+
+func TestSignUpstreamRequest(t *testing.T) {
+	t.Parallel()
+
+	testErr := errors.New("test error")
+
+	for _, tc := range []struct {
+		uc      string
+		signers []heimdall.UpstreamRequestSigner
+		assert  func(t *testing.T, err error, req *http.Request, added http.Header)
+	}{
+		{
+			uc: "without signers",
+			assert: func(t *testing.T, err error, req *http.Request, added http.Header) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Empty(t, added)
+				assert.Len(t, req.Header, 1)
+			},
+		},
+		{
+			uc: "with failing signer",
+			signers: []heimdall.UpstreamRequestSigner{
+				func(_ *heimdall.Request) (http.Header, error) { return nil, testErr },
+			},
+			assert: func(t *testing.T, err error, _ *http.Request, _ http.Header) {
+				t.Helper()
+
+				require.ErrorIs(t, err, testErr)
+			},
+		},
+		{
+			uc: "with multiple signers",
+			signers: []heimdall.UpstreamRequestSigner{
+				func(req *heimdall.Request) (http.Header, error) {
+					return http.Header{"X-Sig-1": []string{
+						req.Method + " " + req.URL.String() + " " + req.Header("Host") + " " + req.Header("X-Foo"),
+					}}, nil
+				},
+				func(req *heimdall.Request) (http.Header, error) {
+					return http.Header{"X-Sig-2": []string{req.Headers()["X-Sig-1"]}}, nil
+				},
+			},
+			assert: func(t *testing.T, err error, req *http.Request, added http.Header) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				expected := "PATCH http://bar.foo/foo?bar=baz bar.foo foo"
+
+				assert.Len(t, added, 2)
+				assert.Equal(t, expected, added.Get("X-Sig-1"))
+				assert.Equal(t, expected, added.Get("X-Sig-2"))
+				assert.Equal(t, expected, req.Header.Get("X-Sig-1"))
+				assert.Equal(t, expected, req.Header.Get("X-Sig-2"))
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			reqURL, err := url.Parse("http://foo.bar/foo?bar=baz")
+			require.NoError(t, err)
+
+			req := &http.Request{
+				Method: http.MethodPatch,
+				URL:    reqURL,
+				Host:   "bar.foo",
+				Header: http.Header{"X-Foo": []string{"foo"}},
+			}
+
+			// WHEN
+			added, err := SignUpstreamRequest(req, tc.signers)
+
+			// THEN
+			tc.assert(t, err, req, added)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var ErrInitialLoadPending = errors.New("initial load not completed")
+
+// LoadTracker is a Checker, which is ready as soon as the initial load from all of
+// the given sources, like the endpoints a rule provider fetches rule sets from, has
+// been completed. Failures of later loads do not affect the readiness. These are
+// reported by the sources themselves, e.g. via logs or the status of the rule sets.
+type LoadTracker struct {
+	name    string
+	pending map[string]struct{}
+	mutex   sync.RWMutex
+}
+
+func NewLoadTracker(name string, sources ...string) *LoadTracker {
+	pending := make(map[string]struct{}, len(sources))
+	for _, source := range sources {
+		pending[source] = struct{}{}
+	}
+
+	return &LoadTracker{name: name, pending: pending}
+}
+
+// Loaded marks the initial load from the given source as completed.
+func (t *LoadTracker) Loaded(source string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.pending, source)
+}
+
+func (t *LoadTracker) Name() string { return t.name }
+
+func (t *LoadTracker) Check(_ context.Context) error {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	if len(t.pending) == 0 {
+		return nil
+	}
+
+	sources := make([]string, 0, len(t.pending))
+	for source := range t.pending {
+		sources = append(sources, source)
+	}
+
+	sort.Strings(sources)
+
+	return fmt.Errorf("%w for %s", ErrInitialLoadPending, strings.Join(sources, ", "))
+}
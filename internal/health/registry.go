@@ -0,0 +1,101 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	StatusReady    = "ready"
+	StatusNotReady = "not_ready"
+)
+
+// Checker is implemented by the components contributing to the readiness of heimdall.
+type Checker interface {
+	Name() string
+	// Check returns an error describing why the component is not ready, or nil if it is.
+	Check(ctx context.Context) error
+}
+
+// NewChecker creates a Checker with the given name from the given function.
+func NewChecker(name string, check func(ctx context.Context) error) Checker {
+	return &checkerFunc{name: name, check: check}
+}
+
+type checkerFunc struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+func (c *checkerFunc) Name() string                    { return c.name }
+func (c *checkerFunc) Check(ctx context.Context) error { return c.check(ctx) }
+
+// ComponentStatus describes the readiness of a single component.
+type ComponentStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report describes the readiness of heimdall. It is only ready if all of its components are ready.
+type Report struct {
+	Status     string            `json:"status"`
+	Components []ComponentStatus `json:"components"`
+}
+
+func (r Report) Ready() bool { return r.Status == StatusReady }
+
+// Registry keeps track of the components contributing to the readiness of heimdall.
+type Registry struct {
+	checkers []Checker
+	mutex    sync.RWMutex
+}
+
+func NewRegistry() *Registry { return &Registry{} }
+
+func (r *Registry) Register(checker Checker) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.checkers = append(r.checkers, checker)
+}
+
+// Check runs the checks of all registered components in the order of their registration.
+func (r *Registry) Check(ctx context.Context) Report {
+	r.mutex.RLock()
+	checkers := make([]Checker, len(r.checkers))
+	copy(checkers, r.checkers)
+	r.mutex.RUnlock()
+
+	report := Report{Status: StatusReady, Components: make([]ComponentStatus, len(checkers))}
+
+	for idx, checker := range checkers {
+		status := ComponentStatus{Name: checker.Name(), Status: StatusReady}
+
+		if err := checker.Check(ctx); err != nil {
+			status.Status = StatusNotReady
+			status.Error = err.Error()
+			report.Status = StatusNotReady
+		}
+
+		report.Components[idx] = status
+	}
+
+	return report
+}
@@ -0,0 +1,127 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryCheck(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	for _, tc := range []struct {
+		uc       string
+		checkers []Checker
+		assert   func(t *testing.T, report Report)
+	}{
+		{
+			uc: "without registered components",
+			assert: func(t *testing.T, report Report) {
+				t.Helper()
+
+				assert.True(t, report.Ready())
+				assert.Empty(t, report.Components)
+			},
+		},
+		{
+			uc: "all components ready",
+			checkers: []Checker{
+				NewChecker("foo", func(_ context.Context) error { return nil }),
+				NewLoadTracker("bar"),
+			},
+			assert: func(t *testing.T, report Report) {
+				t.Helper()
+
+				assert.True(t, report.Ready())
+				assert.Equal(t, StatusReady, report.Status)
+				assert.Equal(t, []ComponentStatus{
+					{Name: "foo", Status: StatusReady},
+					{Name: "bar", Status: StatusReady},
+				}, report.Components)
+			},
+		},
+		{
+			uc: "one component not ready",
+			checkers: []Checker{
+				NewChecker("foo", func(_ context.Context) error { return nil }),
+				NewChecker("bar", func(_ context.Context) error { return errTest }),
+			},
+			assert: func(t *testing.T, report Report) {
+				t.Helper()
+
+				assert.False(t, report.Ready())
+				assert.Equal(t, StatusNotReady, report.Status)
+				assert.Equal(t, []ComponentStatus{
+					{Name: "foo", Status: StatusReady},
+					{Name: "bar", Status: StatusNotReady, Error: "test error"},
+				}, report.Components)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			registry := NewRegistry()
+
+			for _, checker := range tc.checkers {
+				registry.Register(checker)
+			}
+
+			// WHEN
+			report := registry.Check(context.Background())
+
+			// THEN
+			tc.assert(t, report)
+		})
+	}
+}
+
+func TestLoadTracker(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	tracker := NewLoadTracker("foo", "b", "a")
+
+	// WHEN
+	err := tracker.Check(context.Background())
+
+	// THEN
+	assert.Equal(t, "foo", tracker.Name())
+	require.ErrorIs(t, err, ErrInitialLoadPending)
+	assert.Contains(t, err.Error(), "a, b")
+
+	// WHEN
+	tracker.Loaded("a")
+	err = tracker.Check(context.Background())
+
+	// THEN
+	require.ErrorIs(t, err, ErrInitialLoadPending)
+	assert.NotContains(t, err.Error(), "a,")
+
+	// WHEN
+	tracker.Loaded("b")
+	err = tracker.Check(context.Background())
+
+	// THEN
+	require.NoError(t, err)
+}
@@ -18,6 +18,8 @@ package heimdall
 
 import (
 	"context"
+	"crypto/x509"
+	"net/http"
 	"net/url"
 )
 
@@ -28,6 +30,7 @@ type Context interface {
 
 	AddHeaderForUpstream(name, value string)
 	AddCookieForUpstream(name, value string)
+	AddUpstreamRequestSigner(signer UpstreamRequestSigner)
 
 	AppContext() context.Context
 
@@ -36,19 +39,34 @@ type Context interface {
 	Signer() JWTSigner
 }
 
+// UpstreamRequestSigner signs the request forwarded to the upstream service and returns the
+// headers to be added to it. It is called after all modifications of that request are done.
+type UpstreamRequestSigner func(req *Request) (http.Header, error)
+
 //go:generate mockery --name RequestFunctions --structname RequestFunctionsMock
 
 type RequestFunctions interface {
 	Header(name string) string
+	HeaderValues(name string) []string
 	Cookie(name string) string
 	Headers() map[string]string
 	Body() any
+	RawBody() []byte
+	ClientCertificates() []*x509.Certificate
 }
 
 type Request struct {
 	RequestFunctions
 
 	Method            string
-	URL               *url.URL
+	URL               *URL
 	ClientIPAddresses []string
 }
+
+// URL is the url of the request together with the values captured by the named
+// sections of the url pattern of the matched rule.
+type URL struct {
+	url.URL
+
+	Captures map[string]string
+}
@@ -18,6 +18,7 @@ package heimdall
 
 import (
 	"errors"
+	"net/http"
 	"reflect"
 )
 
@@ -37,6 +38,8 @@ type RedirectError struct {
 	Message    string
 	Code       int
 	RedirectTo string
+	// Cookies are set in the client (user agent) together with the redirect
+	Cookies []*http.Cookie
 }
 
 func (e *RedirectError) Error() string { return e.Message }
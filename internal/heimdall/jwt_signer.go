@@ -17,6 +17,7 @@
 package heimdall
 
 import (
+	"crypto"
 	"time"
 
 	"gopkg.in/square/go-jose.v2"
@@ -28,4 +29,5 @@ type JWTSigner interface {
 	Sign(sub string, ttl time.Duration, claims map[string]any) (string, error)
 	Hash() []byte
 	Keys() []jose.JSONWebKey
+	ActiveKey() (jose.JSONWebKey, crypto.Signer)
 }
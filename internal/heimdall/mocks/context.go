@@ -90,6 +90,39 @@ func (_c *ContextMock_AddHeaderForUpstream_Call) RunAndReturn(run func(string, s
 	return _c
 }
 
+// AddUpstreamRequestSigner provides a mock function with given fields: signer
+func (_m *ContextMock) AddUpstreamRequestSigner(signer heimdall.UpstreamRequestSigner) {
+	_m.Called(signer)
+}
+
+// ContextMock_AddUpstreamRequestSigner_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddUpstreamRequestSigner'
+type ContextMock_AddUpstreamRequestSigner_Call struct {
+	*mock.Call
+}
+
+// AddUpstreamRequestSigner is a helper method to define mock.On call
+//   - signer heimdall.UpstreamRequestSigner
+func (_e *ContextMock_Expecter) AddUpstreamRequestSigner(signer interface{}) *ContextMock_AddUpstreamRequestSigner_Call {
+	return &ContextMock_AddUpstreamRequestSigner_Call{Call: _e.mock.On("AddUpstreamRequestSigner", signer)}
+}
+
+func (_c *ContextMock_AddUpstreamRequestSigner_Call) Run(run func(signer heimdall.UpstreamRequestSigner)) *ContextMock_AddUpstreamRequestSigner_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(heimdall.UpstreamRequestSigner))
+	})
+	return _c
+}
+
+func (_c *ContextMock_AddUpstreamRequestSigner_Call) Return() *ContextMock_AddUpstreamRequestSigner_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *ContextMock_AddUpstreamRequestSigner_Call) RunAndReturn(run func(heimdall.UpstreamRequestSigner)) *ContextMock_AddUpstreamRequestSigner_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // AppContext provides a mock function with given fields:
 func (_m *ContextMock) AppContext() context.Context {
 	ret := _m.Called()
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	crypto "crypto"
+
 	mock "github.com/stretchr/testify/mock"
 	jose "gopkg.in/square/go-jose.v2"
 
@@ -22,6 +24,59 @@ func (_m *JWTSignerMock) EXPECT() *JWTSignerMock_Expecter {
 	return &JWTSignerMock_Expecter{mock: &_m.Mock}
 }
 
+// ActiveKey provides a mock function with given fields:
+func (_m *JWTSignerMock) ActiveKey() (jose.JSONWebKey, crypto.Signer) {
+	ret := _m.Called()
+
+	var r0 jose.JSONWebKey
+	var r1 crypto.Signer
+	if rf, ok := ret.Get(0).(func() (jose.JSONWebKey, crypto.Signer)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() jose.JSONWebKey); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(jose.JSONWebKey)
+	}
+
+	if rf, ok := ret.Get(1).(func() crypto.Signer); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(crypto.Signer)
+		}
+	}
+
+	return r0, r1
+}
+
+// JWTSignerMock_ActiveKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ActiveKey'
+type JWTSignerMock_ActiveKey_Call struct {
+	*mock.Call
+}
+
+// ActiveKey is a helper method to define mock.On call
+func (_e *JWTSignerMock_Expecter) ActiveKey() *JWTSignerMock_ActiveKey_Call {
+	return &JWTSignerMock_ActiveKey_Call{Call: _e.mock.On("ActiveKey")}
+}
+
+func (_c *JWTSignerMock_ActiveKey_Call) Run(run func()) *JWTSignerMock_ActiveKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *JWTSignerMock_ActiveKey_Call) Return(_a0 jose.JSONWebKey, _a1 crypto.Signer) *JWTSignerMock_ActiveKey_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *JWTSignerMock_ActiveKey_Call) RunAndReturn(run func() (jose.JSONWebKey, crypto.Signer)) *JWTSignerMock_ActiveKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Hash provides a mock function with given fields:
 func (_m *JWTSignerMock) Hash() []byte {
 	ret := _m.Called()
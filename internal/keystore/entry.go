@@ -18,10 +18,13 @@ package keystore
 
 import (
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
 	"crypto/x509"
 	"fmt"
 
 	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/cryptosigner"
 )
 
 const (
@@ -52,6 +55,18 @@ func (e *Entry) JWK() jose.JSONWebKey {
 	}
 }
 
+// JOSESigningKey returns the given private key in a form usable by go-jose for signing purposes.
+// Keys, go-jose cannot use directly, like the ones residing in a hardware security module, are
+// wrapped.
+func JOSESigningKey(key crypto.Signer) any {
+	switch key.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey:
+		return key
+	default:
+		return cryptosigner.Opaque(key)
+	}
+}
+
 func (e *Entry) JOSEAlgorithm() jose.SignatureAlgorithm {
 	switch e.Alg {
 	case AlgRSA:
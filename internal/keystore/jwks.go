@@ -0,0 +1,70 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package keystore
+
+import (
+	"crypto/x509"
+	"encoding/json"
+
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+func NewKeyStoreFromJWKSFile(jwksFilePath string) (KeyStore, error) {
+	contents, err := readFile(jwksFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyStoreFromJWKSBytes(contents)
+}
+
+// NewKeyStoreFromJWKSBytes creates a key store from a JWK set containing private keys. The kid of
+// a key is used as key id. If not set, the key id is derived as for the other key store formats.
+// Certificates are taken from the x5c parameter.
+func NewKeyStoreFromJWKSBytes(data []byte) (KeyStore, error) {
+	var jwks jose.JSONWebKeySet
+
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"failed to parse JWK set").CausedBy(err)
+	}
+
+	var (
+		entries []*Entry
+		certs   []*x509.Certificate
+	)
+
+	for idx, jwk := range jwks.Keys {
+		if jwk.IsPublic() {
+			return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+				"%d entry in the JWK set is not a private key", idx)
+		}
+
+		entry, err := createEntry(jwk.Key, jwk.KeyID)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+		certs = append(certs, jwk.Certificates...)
+	}
+
+	return verifyAndBuildKeyStore(entries, certs)
+}
@@ -0,0 +1,177 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package keystore_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/keystore"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestCreateKeyStoreFromJWKSBytes(t *testing.T) {
+	t.Parallel()
+
+	ca, err := testsupport.NewRootCA("Test CA", time.Hour*24)
+	require.NoError(t, err)
+
+	ecPrivKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	rsaPrivKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	cert, err := ca.IssueCertificate(
+		testsupport.WithSubject(pkix.Name{
+			CommonName:   "Test EE 1",
+			Organization: []string{"Test"},
+			Country:      []string{"EU"},
+		}),
+		testsupport.WithValidity(time.Now(), time.Hour*1),
+		testsupport.WithSubjectPubKey(&ecPrivKey.PublicKey, x509.ECDSAWithSHA256),
+		testsupport.WithKeyUsage(x509.KeyUsageDigitalSignature))
+	require.NoError(t, err)
+
+	jwks := func(t *testing.T, keys ...jose.JSONWebKey) []byte {
+		t.Helper()
+
+		data, err := json.Marshal(jose.JSONWebKeySet{Keys: keys})
+		require.NoError(t, err)
+
+		return data
+	}
+
+	for _, tc := range []struct {
+		uc       string
+		contents func(t *testing.T) []byte
+		assert   func(t *testing.T, ks keystore.KeyStore, err error)
+	}{
+		{
+			uc: "not a JWK set",
+			contents: func(t *testing.T) []byte {
+				t.Helper()
+
+				return []byte("foo")
+			},
+			assert: func(t *testing.T, ks keystore.KeyStore, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed to parse JWK set")
+			},
+		},
+		{
+			uc: "JWK set with public key",
+			contents: func(t *testing.T) []byte {
+				t.Helper()
+
+				return jwks(t, jose.JSONWebKey{Key: &ecPrivKey.PublicKey, KeyID: "foo"})
+			},
+			assert: func(t *testing.T, ks keystore.KeyStore, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "not a private key")
+			},
+		},
+		{
+			uc: "JWK set with symmetric key",
+			contents: func(t *testing.T) []byte {
+				t.Helper()
+
+				return jwks(t, jose.JSONWebKey{Key: []byte("foobar"), KeyID: "foo"})
+			},
+			assert: func(t *testing.T, ks keystore.KeyStore, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				assert.Contains(t, err.Error(), "unsupported key type")
+			},
+		},
+		{
+			uc: "JWK set with duplicate key ids",
+			contents: func(t *testing.T) []byte {
+				t.Helper()
+
+				return jwks(t,
+					jose.JSONWebKey{Key: ecPrivKey, KeyID: "foo"},
+					jose.JSONWebKey{Key: rsaPrivKey, KeyID: "foo"},
+				)
+			},
+			assert: func(t *testing.T, ks keystore.KeyStore, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "duplicate entry for key_id=foo")
+			},
+		},
+		{
+			uc: "JWK set with private keys",
+			contents: func(t *testing.T) []byte {
+				t.Helper()
+
+				return jwks(t,
+					jose.JSONWebKey{Key: ecPrivKey, KeyID: "foo", Certificates: []*x509.Certificate{cert, ca.Certificate}},
+					jose.JSONWebKey{Key: rsaPrivKey},
+				)
+			},
+			assert: func(t *testing.T, ks keystore.KeyStore, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.Len(t, ks.Entries(), 2)
+
+				entry, err := ks.GetKey("foo")
+				require.NoError(t, err)
+				assert.Equal(t, keystore.AlgECDSA, entry.Alg)
+				assert.Equal(t, 256, entry.KeySize)
+				assert.Len(t, entry.CertChain, 2)
+
+				entry = ks.Entries()[1]
+				assert.NotEmpty(t, entry.KeyID)
+				assert.Equal(t, keystore.AlgRSA, entry.Alg)
+				assert.Equal(t, 2048, entry.KeySize)
+				assert.Empty(t, entry.CertChain)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// WHEN
+			ks, err := keystore.NewKeyStoreFromJWKSBytes(tc.contents(t))
+
+			// THEN
+			tc.assert(t, ks, err)
+		})
+	}
+}
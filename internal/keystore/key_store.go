@@ -28,6 +28,7 @@ import (
 
 	"github.com/youmark/pkcs8"
 
+	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/x/errorchain"
 	"github.com/dadrus/heimdall/internal/x/pkix"
@@ -77,21 +78,27 @@ func NewKeyStoreFromKey(privateKey crypto.Signer) (KeyStore, error) {
 	return verifyAndBuildKeyStore([]*Entry{entry}, nil)
 }
 
-func NewKeyStoreFromPEMFile(pemFilePath, password string) (KeyStore, error) {
-	fInfo, err := os.Stat(pemFilePath)
-	if err != nil {
+// NewKeyStore creates a key store of the configured type.
+func NewKeyStore(conf config.KeyStore) (KeyStore, error) {
+	switch conf.Type {
+	case "", config.KeyStoreTypePEM:
+		return NewKeyStoreFromPEMFile(conf.Path, conf.Password)
+	case config.KeyStoreTypePKCS12:
+		return NewKeyStoreFromPKCS12File(conf.Path, conf.Password)
+	case config.KeyStoreTypeJWKS:
+		return NewKeyStoreFromJWKSFile(conf.Path)
+	case config.KeyStoreTypePKCS11:
+		return NewKeyStoreFromPKCS11(conf.Path, conf.TokenLabel, conf.Password)
+	default:
 		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
-			"failed to get information about %s", pemFilePath).CausedBy(err)
-	}
-
-	if fInfo.IsDir() {
-		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration, "'%s' is not a file", pemFilePath)
+			"unsupported key store type '%s'", conf.Type)
 	}
+}
 
-	contents, err := os.ReadFile(pemFilePath)
+func NewKeyStoreFromPEMFile(pemFilePath, password string) (KeyStore, error) {
+	contents, err := readFile(pemFilePath)
 	if err != nil {
-		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
-			"failed to read %s", pemFilePath).CausedBy(err)
+		return nil, err
 	}
 
 	return NewKeyStoreFromPEMBytes(contents, password)
@@ -211,6 +218,26 @@ func generateKeyID(chain []*x509.Certificate, entry *Entry) (string, error) {
 	return hex.EncodeToString(keyID), nil
 }
 
+func readFile(path string) ([]byte, error) {
+	fInfo, err := os.Stat(path)
+	if err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed to get information about %s", path).CausedBy(err)
+	}
+
+	if fInfo.IsDir() {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration, "'%s' is not a file", path)
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed to read %s", path).CausedBy(err)
+	}
+
+	return contents, nil
+}
+
 func readPEMContents(data []byte) []*pem.Block {
 	var (
 		blocks []*pem.Block
@@ -221,6 +248,10 @@ func readPEMContents(data []byte) []*pem.Block {
 
 	for {
 		block, next = pem.Decode(next)
+		if block == nil {
+			break
+		}
+
 		blocks = append(blocks, block)
 
 		if len(next) == 0 {
@@ -249,6 +280,9 @@ func createEntry(key any, keyID string) (*Entry, error) {
 		algorithm = AlgECDSA
 		sigKey = typedKey
 		size = typedKey.Params().BitSize
+	case crypto.Signer:
+		// keys not leaving e.g. a hardware security module
+		return createOpaqueEntry(typedKey, keyID)
 	default:
 		return nil, errorchain.NewWithMessage(heimdall.ErrInternal,
 			"unsupported key type; only rsa and ecdsa keys are supported")
@@ -261,3 +295,23 @@ func createEntry(key any, keyID string) (*Entry, error) {
 		PrivateKey: sigKey,
 	}, nil
 }
+
+func createOpaqueEntry(key crypto.Signer, keyID string) (*Entry, error) {
+	const bitsInByte = 8
+
+	entry := &Entry{KeyID: keyID, PrivateKey: key}
+
+	switch pubKey := key.Public().(type) {
+	case *rsa.PublicKey:
+		entry.Alg = AlgRSA
+		entry.KeySize = pubKey.Size() * bitsInByte
+	case *ecdsa.PublicKey:
+		entry.Alg = AlgECDSA
+		entry.KeySize = pubKey.Params().BitSize
+	default:
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal,
+			"unsupported key type; only rsa and ecdsa keys are supported")
+	}
+
+	return entry, nil
+}
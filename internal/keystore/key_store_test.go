@@ -26,14 +26,18 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/hex"
+	"encoding/json"
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
 
+	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/keystore"
 	pkix2 "github.com/dadrus/heimdall/internal/x/pkix"
@@ -405,6 +409,20 @@ xijD/4gPFRBfs2GsfVZzSL9kH7HH0chB9w==
 				assert.Contains(t, err.Error(), "unsupported entry")
 			},
 		},
+		{
+			uc: "pem contains no entries",
+			pemContents: func(t *testing.T) []byte {
+				t.Helper()
+
+				return []byte("foo")
+			},
+			assert: func(t *testing.T, ks keystore.KeyStore, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Empty(t, ks.Entries())
+			},
+		},
 		{
 			uc: "key decoding error",
 			pemContents: func(t *testing.T) []byte {
@@ -765,3 +783,84 @@ func TestKeyStoreGetKey(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateKeyStore(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	pemBytes, err := pemx.BuildPEM(pemx.WithECDSAPrivateKey(privKey, pemx.WithHeader("X-Key-ID", "foo")))
+	require.NoError(t, err)
+
+	jwksBytes, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: privKey, KeyID: "foo"}}})
+	require.NoError(t, err)
+
+	testDir := t.TempDir()
+	pemFile := filepath.Join(testDir, "keys.pem")
+	jwksFile := filepath.Join(testDir, "keys.json")
+
+	require.NoError(t, os.WriteFile(pemFile, pemBytes, 0o600))
+	require.NoError(t, os.WriteFile(jwksFile, jwksBytes, 0o600))
+
+	for _, tc := range []struct {
+		uc     string
+		conf   config.KeyStore
+		assert func(t *testing.T, ks keystore.KeyStore, err error)
+	}{
+		{
+			uc:   "unsupported type",
+			conf: config.KeyStore{Type: "foo", Path: pemFile},
+			assert: func(t *testing.T, ks keystore.KeyStore, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "unsupported key store type")
+			},
+		},
+		{
+			uc:   "pem key store used by default",
+			conf: config.KeyStore{Path: pemFile},
+			assert: func(t *testing.T, ks keystore.KeyStore, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				_, err = ks.GetKey("foo")
+				require.NoError(t, err)
+			},
+		},
+		{
+			uc:   "jwks key store",
+			conf: config.KeyStore{Type: config.KeyStoreTypeJWKS, Path: jwksFile},
+			assert: func(t *testing.T, ks keystore.KeyStore, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				_, err = ks.GetKey("foo")
+				require.NoError(t, err)
+			},
+		},
+		{
+			uc:   "pkcs12 key store from a file in a different format",
+			conf: config.KeyStore{Type: config.KeyStoreTypePKCS12, Path: pemFile},
+			assert: func(t *testing.T, ks keystore.KeyStore, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "PKCS#12")
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// WHEN
+			ks, err := keystore.NewKeyStore(tc.conf)
+
+			// THEN
+			tc.assert(t, ks, err)
+		})
+	}
+}
@@ -0,0 +1,511 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build cgo
+
+package keystore
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"io"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+const maxObjectsPerSearch = 16
+
+var (
+	// DER encoded DigestInfo prefixes required for RSA PKCS#1 v1.5 signatures.
+	digestInfoPrefixes = map[crypto.Hash][]byte{ //nolint:gochecknoglobals
+		crypto.SHA256: {
+			0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00,
+			0x04, 0x20,
+		},
+		crypto.SHA384: {
+			0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00,
+			0x04, 0x30,
+		},
+		crypto.SHA512: {
+			0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00,
+			0x04, 0x40,
+		},
+	}
+
+	pssParams = map[crypto.Hash][2]uint{ //nolint:gochecknoglobals
+		crypto.SHA256: {pkcs11.CKM_SHA256, pkcs11.CKG_MGF1_SHA256},
+		crypto.SHA384: {pkcs11.CKM_SHA384, pkcs11.CKG_MGF1_SHA384},
+		crypto.SHA512: {pkcs11.CKM_SHA512, pkcs11.CKG_MGF1_SHA512},
+	}
+
+	namedCurves = map[string]elliptic.Curve{ //nolint:gochecknoglobals
+		asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}.String(): elliptic.P256(),
+		asn1.ObjectIdentifier{1, 3, 132, 0, 34}.String():          elliptic.P384(),
+		asn1.ObjectIdentifier{1, 3, 132, 0, 35}.String():          elliptic.P521(),
+	}
+)
+
+// NewKeyStoreFromPKCS11 creates a key store from the private keys and certificates residing on the
+// token with the given label, which is accessed using the PKCS#11 module at the given path. The
+// private keys never leave the token. The CKA_LABEL of a private key is used as its key id. If not
+// set, the key id is derived as for the other key store formats. The key store is created only once
+// per token and shared by all users of it, as the token session stays open for signing purposes.
+func NewKeyStoreFromPKCS11(modulePath, tokenLabel, pin string) (KeyStore, error) {
+	pkcs11KeyStores.mutex.Lock()
+	defer pkcs11KeyStores.mutex.Unlock()
+
+	key := modulePath + "#" + tokenLabel
+
+	if entry, ok := pkcs11KeyStores.stores[key]; ok {
+		if entry.pin != pin {
+			return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+				"PKCS#11 token %s is already in use with a different pin", tokenLabel)
+		}
+
+		return entry.ks, nil
+	}
+
+	token, err := openPKCS11Token(modulePath, tokenLabel, pin)
+	if err != nil {
+		return nil, err
+	}
+
+	ks, err := newKeyStoreFromToken(token)
+	if err != nil {
+		token.close()
+
+		return nil, err
+	}
+
+	pkcs11KeyStores.stores[key] = pkcs11KeyStore{ks: ks, pin: pin}
+
+	return ks, nil
+}
+
+type pkcs11KeyStore struct {
+	ks  KeyStore
+	pin string
+}
+
+var pkcs11KeyStores = struct { //nolint:gochecknoglobals
+	stores map[string]pkcs11KeyStore
+	mutex  sync.Mutex
+}{stores: make(map[string]pkcs11KeyStore)}
+
+func newKeyStoreFromToken(token *pkcs11Token) (KeyStore, error) {
+	certs, err := token.certificates()
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := token.privateKeys(certs)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*Entry, len(keys))
+
+	for idx, key := range keys {
+		if entries[idx], err = createEntry(key, key.label); err != nil {
+			return nil, err
+		}
+	}
+
+	certificates := make([]*x509.Certificate, 0, len(certs))
+	for _, cert := range certs {
+		certificates = append(certificates, cert)
+	}
+
+	return verifyAndBuildKeyStore(entries, certificates)
+}
+
+type pkcs11Token struct {
+	ctx      *pkcs11.Ctx
+	session  pkcs11.SessionHandle
+	finalize bool
+	mutex    sync.Mutex
+}
+
+func openPKCS11Token(modulePath, tokenLabel, pin string) (*pkcs11Token, error) { //nolint:cyclop
+	ctx := pkcs11.New(modulePath)
+	if ctx == nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed to load PKCS#11 module %s", modulePath)
+	}
+
+	token := &pkcs11Token{ctx: ctx}
+
+	// the module might have been initialized by another user in this process already.
+	// In that case it must not be finalized, when the token is closed
+	err := ctx.Initialize()
+	if err != nil && !errors.Is(err, pkcs11.Error(pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED)) {
+		ctx.Destroy()
+
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed to initialize PKCS#11 module %s", modulePath).CausedBy(err)
+	}
+
+	token.finalize = err == nil
+
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		token.close()
+
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"failed to list PKCS#11 slots").CausedBy(err)
+	}
+
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil || strings.TrimSpace(info.Label) != tokenLabel {
+			continue
+		}
+
+		session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION)
+		if err != nil {
+			token.close()
+
+			return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+				"failed to open session to PKCS#11 token %s", tokenLabel).CausedBy(err)
+		}
+
+		token.session = session
+
+		if err = ctx.Login(session, pkcs11.CKU_USER, pin); err != nil &&
+			!errors.Is(err, pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN)) {
+			token.close()
+
+			return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+				"failed to log in to PKCS#11 token %s", tokenLabel).CausedBy(err)
+		}
+
+		return token, nil
+	}
+
+	token.close()
+
+	return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+		"no PKCS#11 token with label %s found", tokenLabel)
+}
+
+// close logs out from the token, closes the session to it, if opened, and finalizes the module
+// if it has been initialized by the token.
+func (t *pkcs11Token) close() {
+	if t.session != 0 {
+		_ = t.ctx.Logout(t.session)
+		_ = t.ctx.CloseSession(t.session)
+	}
+
+	if t.finalize {
+		_ = t.ctx.Finalize()
+	}
+
+	t.ctx.Destroy()
+}
+
+// certificates returns the certificates residing on the token by their CKA_ID.
+func (t *pkcs11Token) certificates() (map[string]*x509.Certificate, error) {
+	handles, err := t.findObjects(pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_CERTIFICATE))
+	if err != nil {
+		return nil, err
+	}
+
+	certs := make(map[string]*x509.Certificate, len(handles))
+
+	for idx, handle := range handles {
+		attrs, err := t.attributes(handle, pkcs11.CKA_ID, pkcs11.CKA_VALUE)
+		if err != nil {
+			return nil, err
+		}
+
+		cert, err := x509.ParseCertificate(attrs[pkcs11.CKA_VALUE])
+		if err != nil {
+			return nil, errorchain.NewWithMessagef(heimdall.ErrInternal,
+				"failed to parse %d certificate on the PKCS#11 token", idx).CausedBy(err)
+		}
+
+		certs[string(attrs[pkcs11.CKA_ID])] = cert
+	}
+
+	return certs, nil
+}
+
+func (t *pkcs11Token) privateKeys(certs map[string]*x509.Certificate) ([]*pkcs11Key, error) {
+	var keys []*pkcs11Key
+
+	for _, keyType := range []uint{pkcs11.CKK_RSA, pkcs11.CKK_EC} {
+		handles, err := t.findObjects(
+			pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY),
+			pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, keyType),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, handle := range handles {
+			key, err := t.privateKey(handle, keyType, certs)
+			if err != nil {
+				return nil, err
+			}
+
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+func (t *pkcs11Token) privateKey(
+	handle pkcs11.ObjectHandle, keyType uint, certs map[string]*x509.Certificate,
+) (*pkcs11Key, error) {
+	attrs, err := t.attributes(handle, pkcs11.CKA_ID, pkcs11.CKA_LABEL)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &pkcs11Key{token: t, handle: handle, label: string(attrs[pkcs11.CKA_LABEL])}
+
+	if keyType == pkcs11.CKK_RSA {
+		key.pub, err = t.rsaPublicKey(handle)
+	} else {
+		key.pub, err = t.ecPublicKey(attrs[pkcs11.CKA_ID])
+	}
+
+	// fall back to the public key of the certificate, if the public key is not available on the token
+	if err != nil {
+		cert, ok := certs[string(attrs[pkcs11.CKA_ID])]
+		if !ok {
+			return nil, err
+		}
+
+		key.pub = cert.PublicKey
+	}
+
+	return key, nil
+}
+
+func (t *pkcs11Token) rsaPublicKey(handle pkcs11.ObjectHandle) (*rsa.PublicKey, error) {
+	attrs, err := t.attributes(handle, pkcs11.CKA_MODULUS, pkcs11.CKA_PUBLIC_EXPONENT)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(attrs[pkcs11.CKA_MODULUS]),
+		E: int(new(big.Int).SetBytes(attrs[pkcs11.CKA_PUBLIC_EXPONENT]).Int64()),
+	}, nil
+}
+
+func (t *pkcs11Token) ecPublicKey(id []byte) (*ecdsa.PublicKey, error) {
+	handles, err := t.findObjects(
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PUBLIC_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, id),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(handles) == 0 {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"no public key for an EC private key found on the PKCS#11 token")
+	}
+
+	attrs, err := t.attributes(handles[0], pkcs11.CKA_EC_PARAMS, pkcs11.CKA_EC_POINT)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseECPublicKey(attrs[pkcs11.CKA_EC_PARAMS], attrs[pkcs11.CKA_EC_POINT])
+}
+
+func parseECPublicKey(params, point []byte) (*ecdsa.PublicKey, error) {
+	var oid asn1.ObjectIdentifier
+
+	if _, err := asn1.Unmarshal(params, &oid); err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed to parse EC parameters").CausedBy(err)
+	}
+
+	curve, ok := namedCurves[oid.String()]
+	if !ok {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration, "unsupported curve %s", oid)
+	}
+
+	// the point is typically DER encoded as OCTET STRING
+	var raw []byte
+	if rest, err := asn1.Unmarshal(point, &raw); err != nil || len(rest) != 0 {
+		raw = point
+	}
+
+	byteLen := (curve.Params().BitSize + 7) / 8 //nolint:gomnd
+
+	// only the uncompressed form is supported
+	if len(raw) != 1+2*byteLen || raw[0] != 4 { //nolint:gomnd
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "unsupported EC point format")
+	}
+
+	x := new(big.Int).SetBytes(raw[1 : 1+byteLen])
+	y := new(big.Int).SetBytes(raw[1+byteLen:])
+
+	if !curve.IsOnCurve(x, y) {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "EC point is not on the curve")
+	}
+
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+func (t *pkcs11Token) findObjects(template ...*pkcs11.Attribute) ([]pkcs11.ObjectHandle, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if err := t.ctx.FindObjectsInit(t.session, template); err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed to search objects on the PKCS#11 token").CausedBy(err)
+	}
+
+	defer func() { _ = t.ctx.FindObjectsFinal(t.session) }()
+
+	var handles []pkcs11.ObjectHandle
+
+	for {
+		objects, _, err := t.ctx.FindObjects(t.session, maxObjectsPerSearch)
+		if err != nil {
+			return nil, errorchain.NewWithMessage(heimdall.ErrInternal,
+				"failed to search objects on the PKCS#11 token").CausedBy(err)
+		}
+
+		if len(objects) == 0 {
+			return handles, nil
+		}
+
+		handles = append(handles, objects...)
+	}
+}
+
+func (t *pkcs11Token) attributes(handle pkcs11.ObjectHandle, types ...uint) (map[uint][]byte, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	template := make([]*pkcs11.Attribute, len(types))
+	for idx, typ := range types {
+		template[idx] = pkcs11.NewAttribute(typ, nil)
+	}
+
+	attrs, err := t.ctx.GetAttributeValue(t.session, handle, template)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed to read object attributes from the PKCS#11 token").CausedBy(err)
+	}
+
+	values := make(map[uint][]byte, len(attrs))
+	for _, attr := range attrs {
+		values[attr.Type] = attr.Value
+	}
+
+	return values, nil
+}
+
+func (t *pkcs11Token) sign(handle pkcs11.ObjectHandle, mechanism *pkcs11.Mechanism, data []byte) ([]byte, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if err := t.ctx.SignInit(t.session, []*pkcs11.Mechanism{mechanism}, handle); err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed to initialize PKCS#11 signing operation").CausedBy(err)
+	}
+
+	signature, err := t.ctx.Sign(t.session, data)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal,
+			"PKCS#11 signing operation failed").CausedBy(err)
+	}
+
+	return signature, nil
+}
+
+// pkcs11Key is a crypto.Signer delegating the signing operations to the PKCS#11 token.
+type pkcs11Key struct {
+	token  *pkcs11Token
+	handle pkcs11.ObjectHandle
+	label  string
+	pub    crypto.PublicKey
+}
+
+func (k *pkcs11Key) Public() crypto.PublicKey { return k.pub }
+
+func (k *pkcs11Key) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if _, ok := k.pub.(*ecdsa.PublicKey); ok {
+		return k.signECDSA(digest)
+	}
+
+	return k.signRSA(digest, opts)
+}
+
+func (k *pkcs11Key) signECDSA(digest []byte) ([]byte, error) {
+	signature, err := k.token.sign(k.handle, pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil), digest)
+	if err != nil {
+		return nil, err
+	}
+
+	// PKCS#11 returns r || s, whereas a crypto.Signer is expected to return an ASN.1 encoded signature
+	half := len(signature) / 2 //nolint:gomnd
+
+	return asn1.Marshal(struct{ R, S *big.Int }{
+		R: new(big.Int).SetBytes(signature[:half]),
+		S: new(big.Int).SetBytes(signature[half:]),
+	})
+}
+
+func (k *pkcs11Key) signRSA(digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	hash := opts.HashFunc()
+
+	if pssOpts, ok := opts.(*rsa.PSSOptions); ok {
+		params, ok := pssParams[hash]
+		if !ok {
+			return nil, errorchain.NewWithMessagef(heimdall.ErrInternal, "unsupported hash function %s", hash)
+		}
+
+		// JWA and TLS require the salt length to be equal to the hash size
+		saltLength := pssOpts.SaltLength
+		if saltLength == rsa.PSSSaltLengthAuto || saltLength == rsa.PSSSaltLengthEqualsHash {
+			saltLength = hash.Size()
+		}
+
+		return k.token.sign(k.handle,
+			pkcs11.NewMechanism(pkcs11.CKM_RSA_PKCS_PSS, pkcs11.NewPSSParams(params[0], params[1], uint(saltLength))),
+			digest)
+	}
+
+	prefix, ok := digestInfoPrefixes[hash]
+	if !ok {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrInternal, "unsupported hash function %s", hash)
+	}
+
+	return k.token.sign(k.handle,
+		pkcs11.NewMechanism(pkcs11.CKM_RSA_PKCS, nil),
+		append(append(make([]byte, 0, len(prefix)+len(digest)), prefix...), digest...))
+}
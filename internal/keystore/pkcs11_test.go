@@ -0,0 +1,249 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build cgo
+
+package keystore_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/miekg/pkcs11"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/keystore"
+)
+
+func softHSMModule(t *testing.T) string {
+	t.Helper()
+
+	for _, path := range []string{
+		os.Getenv("SOFTHSM2_MODULE"),
+		"/usr/lib/softhsm/libsofthsm2.so",
+		"/usr/lib/x86_64-linux-gnu/softhsm/libsofthsm2.so",
+		"/usr/local/lib/softhsm/libsofthsm2.so",
+	} {
+		if _, err := os.Stat(path); len(path) != 0 && err == nil {
+			return path
+		}
+	}
+
+	t.Skip("SoftHSM is not available")
+
+	return ""
+}
+
+func setupSoftHSMToken(t *testing.T, module, label, pin string) {
+	t.Helper()
+
+	tokenDir := t.TempDir()
+	confFile := filepath.Join(tokenDir, "softhsm2.conf")
+
+	require.NoError(t, os.WriteFile(confFile,
+		[]byte("directories.tokendir = "+tokenDir+"\nobjectstore.backend = file\n"), 0o600))
+	t.Setenv("SOFTHSM2_CONF", confFile)
+
+	ctx := pkcs11.New(module)
+	require.NotNil(t, ctx)
+	require.NoError(t, ctx.Initialize())
+
+	slots, err := ctx.GetSlotList(false)
+	require.NoError(t, err)
+	require.NotEmpty(t, slots)
+	require.NoError(t, ctx.InitToken(slots[0], "so-pin", label))
+
+	// SoftHSM reassigns the slot ids after the token has been initialized
+	slots, err = ctx.GetSlotList(true)
+	require.NoError(t, err)
+
+	var slot uint
+
+	for _, id := range slots {
+		info, err := ctx.GetTokenInfo(id)
+		require.NoError(t, err)
+
+		if strings.TrimSpace(info.Label) == label {
+			slot = id
+		}
+	}
+
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	require.NoError(t, err)
+
+	require.NoError(t, ctx.Login(session, pkcs11.CKU_SO, "so-pin"))
+	require.NoError(t, ctx.InitPIN(session, pin))
+	require.NoError(t, ctx.Logout(session))
+	require.NoError(t, ctx.Login(session, pkcs11.CKU_USER, pin))
+
+	p256, err := asn1.Marshal(asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7})
+	require.NoError(t, err)
+
+	_, _, err = ctx.GenerateKeyPair(session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil)},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+			pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, p256),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, "ec-key"),
+			pkcs11.NewAttribute(pkcs11.CKA_ID, []byte{1}),
+		},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, "ec-key"),
+			pkcs11.NewAttribute(pkcs11.CKA_ID, []byte{1}),
+		})
+	require.NoError(t, err)
+
+	_, _, err = ctx.GenerateKeyPair(session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_RSA_PKCS_KEY_PAIR_GEN, nil)},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+			pkcs11.NewAttribute(pkcs11.CKA_MODULUS_BITS, 2048),
+			pkcs11.NewAttribute(pkcs11.CKA_PUBLIC_EXPONENT, []byte{1, 0, 1}),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, "rsa-key"),
+			pkcs11.NewAttribute(pkcs11.CKA_ID, []byte{2}),
+		},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, "rsa-key"),
+			pkcs11.NewAttribute(pkcs11.CKA_ID, []byte{2}),
+		})
+	require.NoError(t, err)
+
+	require.NoError(t, ctx.Logout(session))
+	require.NoError(t, ctx.CloseSession(session))
+}
+
+//nolint:paralleltest
+func TestCreateKeyStoreFromPKCS11(t *testing.T) {
+	// GIVEN
+	module := softHSMModule(t)
+
+	setupSoftHSMToken(t, module, "heimdall", "1234")
+
+	digest := sha256.Sum256([]byte("foobar"))
+
+	// the cases are executed in order, as the login state is shared by all sessions to the token
+	for _, tc := range []struct {
+		uc     string
+		label  string
+		pin    string
+		assert func(t *testing.T, ks keystore.KeyStore, err error)
+	}{
+		{
+			uc:    "not existing token",
+			label: "foo",
+			pin:   "1234",
+			assert: func(t *testing.T, ks keystore.KeyStore, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "no PKCS#11 token")
+			},
+		},
+		{
+			uc:    "wrong pin",
+			label: "heimdall",
+			pin:   "4321",
+			assert: func(t *testing.T, ks keystore.KeyStore, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed to log in")
+			},
+		},
+		{
+			uc:    "keys are loaded from the token and usable for signing",
+			label: "heimdall",
+			pin:   "1234",
+			assert: func(t *testing.T, ks keystore.KeyStore, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.Len(t, ks.Entries(), 2)
+
+				rsaEntry, err := ks.GetKey("rsa-key")
+				require.NoError(t, err)
+				assert.Equal(t, keystore.AlgRSA, rsaEntry.Alg)
+				assert.Equal(t, 2048, rsaEntry.KeySize)
+
+				rsaPubKey, ok := rsaEntry.PrivateKey.Public().(*rsa.PublicKey)
+				require.True(t, ok)
+
+				sig, err := rsaEntry.PrivateKey.Sign(rand.Reader, digest[:],
+					&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA256})
+				require.NoError(t, err)
+				require.NoError(t, rsa.VerifyPSS(rsaPubKey, crypto.SHA256, digest[:], sig, nil))
+
+				sig, err = rsaEntry.PrivateKey.Sign(rand.Reader, digest[:], crypto.SHA256)
+				require.NoError(t, err)
+				require.NoError(t, rsa.VerifyPKCS1v15(rsaPubKey, crypto.SHA256, digest[:], sig))
+
+				ecEntry, err := ks.GetKey("ec-key")
+				require.NoError(t, err)
+				assert.Equal(t, keystore.AlgECDSA, ecEntry.Alg)
+				assert.Equal(t, 256, ecEntry.KeySize)
+
+				ecPubKey, ok := ecEntry.PrivateKey.Public().(*ecdsa.PublicKey)
+				require.True(t, ok)
+
+				sig, err = ecEntry.PrivateKey.Sign(rand.Reader, digest[:], crypto.SHA256)
+				require.NoError(t, err)
+				assert.True(t, ecdsa.VerifyASN1(ecPubKey, digest[:], sig))
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// WHEN
+			ks, err := keystore.NewKeyStoreFromPKCS11(module, tc.label, tc.pin)
+
+			// THEN
+			tc.assert(t, ks, err)
+		})
+	}
+
+	// WHEN
+	ks1, err1 := keystore.NewKeyStoreFromPKCS11(module, "heimdall", "1234")
+	ks2, err2 := keystore.NewKeyStoreFromPKCS11(module, "heimdall", "1234")
+	_, err3 := keystore.NewKeyStoreFromPKCS11(module, "heimdall", "4321")
+
+	// THEN the token is opened only once and the key store is shared
+	require.NoError(t, err1)
+	require.NoError(t, err2)
+	assert.Same(t, ks1.Entries()[0], ks2.Entries()[0])
+	require.ErrorIs(t, err3, heimdall.ErrConfiguration)
+	assert.Contains(t, err3.Error(), "different pin")
+}
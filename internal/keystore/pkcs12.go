@@ -0,0 +1,53 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package keystore
+
+import (
+	"crypto/x509"
+
+	"software.sslmate.com/src/go-pkcs12"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+func NewKeyStoreFromPKCS12File(pkcs12FilePath, password string) (KeyStore, error) {
+	contents, err := readFile(pkcs12FilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyStoreFromPKCS12Bytes(contents, password)
+}
+
+// NewKeyStoreFromPKCS12Bytes creates a key store from a PKCS#12 bundle, holding a private key, its
+// certificate and optionally further CA certificates. As PKCS#12 does not define a key id, it is
+// derived from the certificate.
+func NewKeyStoreFromPKCS12Bytes(data []byte, password string) (KeyStore, error) {
+	key, cert, caCerts, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"failed to decode PKCS#12 key store").CausedBy(err)
+	}
+
+	entry, err := createEntry(key, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return verifyAndBuildKeyStore([]*Entry{entry}, append([]*x509.Certificate{cert}, caCerts...))
+}
@@ -0,0 +1,156 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package keystore_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"software.sslmate.com/src/go-pkcs12"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/keystore"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestCreateKeyStoreFromPKCS12Bytes(t *testing.T) {
+	t.Parallel()
+
+	ca, err := testsupport.NewRootCA("Test CA", time.Hour*24)
+	require.NoError(t, err)
+
+	privKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	cert, err := ca.IssueCertificate(
+		testsupport.WithSubject(pkix.Name{
+			CommonName:   "Test EE 1",
+			Organization: []string{"Test"},
+			Country:      []string{"EU"},
+		}),
+		testsupport.WithValidity(time.Now(), time.Hour*1),
+		testsupport.WithSubjectKeyID([]byte("bar")),
+		testsupport.WithSubjectPubKey(&privKey.PublicKey, x509.ECDSAWithSHA384),
+		testsupport.WithKeyUsage(x509.KeyUsageDigitalSignature))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		uc       string
+		password string
+		contents func(t *testing.T) []byte
+		assert   func(t *testing.T, ks keystore.KeyStore, err error)
+	}{
+		{
+			uc: "not a PKCS#12 bundle",
+			contents: func(t *testing.T) []byte {
+				t.Helper()
+
+				return []byte("foo")
+			},
+			assert: func(t *testing.T, ks keystore.KeyStore, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed to decode")
+			},
+		},
+		{
+			uc:       "wrong password",
+			password: "bar",
+			contents: func(t *testing.T) []byte {
+				t.Helper()
+
+				data, err := pkcs12.Modern.Encode(privKey, cert, []*x509.Certificate{ca.Certificate}, "foo")
+				require.NoError(t, err)
+
+				return data
+			},
+			assert: func(t *testing.T, ks keystore.KeyStore, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed to decode")
+			},
+		},
+		{
+			uc:       "bundle with key, certificate and ca certificate",
+			password: "foo",
+			contents: func(t *testing.T) []byte {
+				t.Helper()
+
+				data, err := pkcs12.Modern.Encode(privKey, cert, []*x509.Certificate{ca.Certificate}, "foo")
+				require.NoError(t, err)
+
+				return data
+			},
+			assert: func(t *testing.T, ks keystore.KeyStore, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.Len(t, ks.Entries(), 1)
+
+				entry := ks.Entries()[0]
+				assert.Equal(t, privKey, entry.PrivateKey)
+				assert.Equal(t, keystore.AlgECDSA, entry.Alg)
+				assert.Equal(t, 384, entry.KeySize)
+				assert.Len(t, entry.CertChain, 2)
+				assert.Equal(t, hex.EncodeToString(cert.SubjectKeyId), entry.KeyID)
+			},
+		},
+		{
+			uc: "bundle with unrelated ca certificate",
+			contents: func(t *testing.T) []byte {
+				t.Helper()
+
+				otherCA, err := testsupport.NewRootCA("Other CA", time.Hour*24)
+				require.NoError(t, err)
+
+				data, err := pkcs12.Modern.Encode(privKey, cert, []*x509.Certificate{otherCA.Certificate}, "")
+				require.NoError(t, err)
+
+				return data
+			},
+			assert: func(t *testing.T, ks keystore.KeyStore, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.Len(t, ks.Entries(), 1)
+
+				// the unrelated ca certificate is not part of the chain
+				assert.Len(t, ks.Entries()[0].CertChain, 1)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// WHEN
+			ks, err := keystore.NewKeyStoreFromPKCS12Bytes(tc.contents(t), tc.password)
+
+			// THEN
+			tc.assert(t, ks, err)
+		})
+	}
+}
@@ -27,11 +27,16 @@ import (
 	"github.com/dadrus/heimdall/internal/x"
 )
 
+// NewLogger creates a logger for the given configuration. The configured level is set as the
+// global one, so that it can be changed for all loggers derived from the created one on
+// configuration reloads.
 func NewLogger(conf config.LoggingConfig) zerolog.Logger {
+	zerolog.SetGlobalLevel(conf.Level)
+
 	if conf.Format == config.LogTextFormat {
 		return zerolog.New(zerolog.NewConsoleWriter(func(w *zerolog.ConsoleWriter) {
 			w.TimeFormat = time.RFC3339
-		})).Level(conf.Level).With().Timestamp().Logger()
+		})).With().Timestamp().Logger()
 	}
 
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
@@ -45,7 +50,7 @@ func NewLogger(conf config.LoggingConfig) zerolog.Logger {
 	zerolog.CallerFieldName = "_caller"
 	hostname, err := os.Hostname()
 
-	return zerolog.New(os.Stdout).Level(conf.Level).With().
+	return zerolog.New(os.Stdout).With().
 		Str("version", "1.1").
 		Str("host", x.IfThenElse(err != nil, hostname, "unknown")).
 		Timestamp().
@@ -20,6 +20,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -81,3 +82,18 @@ func TestNewGelfLogger(t *testing.T) {
 	assert.Contains(t, string(data), `"level":6`)
 	assert.Contains(t, string(data), `"short_message":"Hello Heimdall"`)
 }
+
+func TestLevelReloader(t *testing.T) {
+	// GIVEN
+	NewLogger(config.LoggingConfig{Format: config.LogGelfFormat, Level: zerolog.ErrorLevel})
+	require.Equal(t, zerolog.ErrorLevel, zerolog.GlobalLevel())
+
+	// WHEN
+	err := levelReloader{}.OnConfigurationChanged(&config.Configuration{
+		Log: config.LoggingConfig{Level: zerolog.DebugLevel},
+	})
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, zerolog.DebugLevel, zerolog.GlobalLevel())
+}
@@ -17,9 +17,26 @@
 package logging
 
 import (
+	"github.com/rs/zerolog"
 	"go.uber.org/fx"
+
+	"github.com/dadrus/heimdall/internal/config"
 )
 
 var Module = fx.Options( // nolint: gochecknoglobals
 	fx.Provide(NewLogger),
 )
+
+// LevelReloadModule applies the log level of a reloaded configuration. As changing the level
+// cannot fail, it must be included after the modules, which might reject a reloaded configuration.
+var LevelReloadModule = fx.Invoke( // nolint: gochecknoglobals
+	func(watcher *config.Watcher) { watcher.Subscribe(levelReloader{}) },
+)
+
+type levelReloader struct{}
+
+func (levelReloader) OnConfigurationChanged(conf *config.Configuration) error {
+	zerolog.SetGlobalLevel(conf.Log.Level)
+
+	return nil
+}
@@ -20,11 +20,13 @@ import (
 	"github.com/rs/zerolog"
 	"go.uber.org/fx"
 
+	"github.com/dadrus/heimdall/internal/audit"
 	"github.com/dadrus/heimdall/internal/cache"
 	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/handler/management"
 	"github.com/dadrus/heimdall/internal/handler/metrics"
 	"github.com/dadrus/heimdall/internal/handler/profiling"
+	"github.com/dadrus/heimdall/internal/health"
 	"github.com/dadrus/heimdall/internal/logging"
 	"github.com/dadrus/heimdall/internal/otel"
 	"github.com/dadrus/heimdall/internal/rules"
@@ -40,10 +42,13 @@ var Module = fx.Options( //nolint:gochecknoglobals
 		logger.Info().Str("_version", version.Version).Msg("Starting heimdall")
 	}),
 	otel.Module,
+	health.Module,
 	cache.Module,
+	audit.Module,
 	signer.Module,
 	mechanisms.Module,
 	rules.Module,
+	logging.LevelReloadModule,
 	management.Module,
 	metrics.Module,
 	profiling.Module,
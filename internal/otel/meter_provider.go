@@ -21,6 +21,7 @@ import (
 
 	"github.com/rs/zerolog"
 	"go.opentelemetry.io/otel"
+	metricapi "go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.uber.org/fx"
@@ -29,21 +30,21 @@ import (
 	"github.com/dadrus/heimdall/internal/x/opentelemetry/exporters"
 )
 
-func initMeterProvider(
+func newMeterProvider(
 	conf *config.Configuration,
 	res *resource.Resource,
 	logger zerolog.Logger,
 	lifecycle fx.Lifecycle,
-) error {
+) (metricapi.MeterProvider, error) {
 	if !conf.Metrics.Enabled {
 		logger.Info().Msg("OpenTelemetry metrics disabled.")
 
-		return nil
+		return otel.GetMeterProvider(), nil
 	}
 
 	metricsReaders, err := exporters.NewMetricReaders(context.Background())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	opts := make([]metric.Option, len(metricsReaders)+1)
@@ -59,5 +60,5 @@ func initMeterProvider(
 
 	logger.Info().Msg("OpenTelemetry metrics initialized.")
 
-	return nil
+	return mp, nil
 }
@@ -24,6 +24,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.uber.org/fx"
 
@@ -33,20 +35,21 @@ import (
 	"github.com/dadrus/heimdall/internal/x/testsupport"
 )
 
-func TestInitMeterProvider(t *testing.T) {
+func TestNewMeterProvider(t *testing.T) {
 	for _, tc := range []struct {
 		uc         string
 		conf       config.MetricsConfig
 		setupMocks func(t *testing.T, lcMock *mockLifecycle)
-		assert     func(t *testing.T, err error, logged string)
+		assert     func(t *testing.T, err error, mp metric.MeterProvider, logged string)
 	}{
 		{
 			uc:   "disabled tracing",
 			conf: config.MetricsConfig{Enabled: false},
-			assert: func(t *testing.T, err error, logged string) {
+			assert: func(t *testing.T, err error, mp metric.MeterProvider, logged string) {
 				t.Helper()
 
 				require.NoError(t, err)
+				assert.NotNil(t, mp)
 				assert.Contains(t, logged, "metrics disabled")
 			},
 		},
@@ -58,7 +61,7 @@ func TestInitMeterProvider(t *testing.T) {
 
 				t.Setenv("OTEL_METRICS_EXPORTER", "does_not_exist")
 			},
-			assert: func(t *testing.T, err error, logged string) {
+			assert: func(t *testing.T, err error, mp metric.MeterProvider, logged string) {
 				t.Helper()
 
 				require.Error(t, err)
@@ -78,10 +81,11 @@ func TestInitMeterProvider(t *testing.T) {
 					}),
 				)
 			},
-			assert: func(t *testing.T, err error, logged string) {
+			assert: func(t *testing.T, err error, mp metric.MeterProvider, logged string) {
 				t.Helper()
 
 				require.NoError(t, err)
+				assert.Equal(t, otel.GetMeterProvider(), mp)
 				assert.Contains(t, logged, "metrics initialized")
 			},
 		},
@@ -99,7 +103,7 @@ func TestInitMeterProvider(t *testing.T) {
 			setupMocks(t, mock)
 
 			// WHEN
-			err := initMeterProvider(
+			mp, err := newMeterProvider(
 				&config.Configuration{Metrics: tc.conf},
 				resource.Default(),
 				logger,
@@ -107,7 +111,7 @@ func TestInitMeterProvider(t *testing.T) {
 			)
 
 			// THEN
-			tc.assert(t, err, tb.CollectedLog())
+			tc.assert(t, err, mp, tb.CollectedLog())
 			mock.AssertExpectations(t)
 		})
 	}
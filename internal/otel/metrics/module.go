@@ -51,24 +51,21 @@ func monitorCertificateExpiry(conf *config.Configuration) error {
 	mSrvTLSConf := conf.Serve.Management.TLS
 
 	if dSrvTLSConf != nil {
-		decisionSrvKS, _ = keystore.NewKeyStoreFromPEMFile(dSrvTLSConf.KeyStore.Path, dSrvTLSConf.KeyStore.Password)
+		decisionSrvKS, _ = keystore.NewKeyStore(dSrvTLSConf.KeyStore)
 		decisionSrvKeyID = dSrvTLSConf.KeyID
 	}
 
 	if pSrvTLSConf != nil {
-		proxySrvKS, _ = keystore.NewKeyStoreFromPEMFile(pSrvTLSConf.KeyStore.Path, pSrvTLSConf.KeyStore.Password)
+		proxySrvKS, _ = keystore.NewKeyStore(pSrvTLSConf.KeyStore)
 		proxySrvKeyID = pSrvTLSConf.KeyID
 	}
 
 	if mSrvTLSConf != nil {
-		managementSrvKS, _ = keystore.NewKeyStoreFromPEMFile(mSrvTLSConf.KeyStore.Path, mSrvTLSConf.KeyStore.Password)
+		managementSrvKS, _ = keystore.NewKeyStore(mSrvTLSConf.KeyStore)
 		managementSrvKeyID = mSrvTLSConf.KeyID
 	}
 
-	signerKS, _ = keystore.NewKeyStoreFromPEMFile(
-		conf.Signer.KeyStore.Path,
-		conf.Signer.KeyStore.Password,
-	)
+	signerKS, _ = keystore.NewKeyStore(conf.Signer.KeyStore)
 	signerKeyID = conf.Signer.KeyID
 
 	return certificate.Start(
@@ -19,6 +19,7 @@ package otel
 import (
 	"github.com/rs/zerolog"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/fx"
 
 	"github.com/dadrus/heimdall/internal/otel/metrics"
@@ -32,6 +33,9 @@ var Module = fx.Options(
 		otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) { logger.Warn().Err(err).Msg("OTEL Error") }))
 	}),
 	fx.Invoke(initTraceProvider),
-	fx.Invoke(initMeterProvider),
+	fx.Provide(newMeterProvider),
+	// the meter provider is set as global one as well. So it must be created even if
+	// no component depends on it explicitly
+	fx.Invoke(func(metric.MeterProvider) {}),
 	metrics.Module,
 )
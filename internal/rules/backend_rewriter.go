@@ -0,0 +1,203 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/template"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+// backendRewriter implements the parts of the forward_to.rewrite configuration, which
+// require compiled regular expressions or templates. The remaining parts are implemented
+// by config.URLRewriter.
+type backendRewriter struct {
+	pathRegex       *regexp.Regexp
+	pathReplacement string
+	queryParams     map[string]template.Template
+	requestHeaders  *headersRewriter
+	responseHeaders *headersRewriter
+}
+
+type headersRewriter struct {
+	set    map[string]template.Template
+	remove []string
+}
+
+// headerModifications holds the rendered header modifications to apply.
+type headerModifications struct {
+	set    map[string]string
+	remove []string
+}
+
+func (m *headerModifications) apply(header http.Header) {
+	if m == nil {
+		return
+	}
+
+	for _, name := range m.remove {
+		header.Del(name)
+	}
+
+	for name, value := range m.set {
+		header.Set(name, value)
+	}
+}
+
+func newBackendRewriter(conf *config.URLRewriter) (*backendRewriter, error) {
+	if conf == nil || (conf.Path == nil && len(conf.QueryParamsToAdd) == 0 &&
+		conf.RequestHeaders == nil && conf.ResponseHeaders == nil) {
+		return nil, nil // nolint: nilnil
+	}
+
+	var (
+		rewriter backendRewriter
+		err      error
+	)
+
+	if conf.Path != nil {
+		if len(conf.Path.Regex) == 0 {
+			return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration, "path rewrite requires a regex")
+		}
+
+		if rewriter.pathRegex, err = regexp.Compile(conf.Path.Regex); err != nil {
+			return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+				"failed to compile path rewrite regex").CausedBy(err)
+		}
+
+		rewriter.pathReplacement = conf.Path.Replacement
+	}
+
+	if rewriter.queryParams, err = newTemplates(conf.QueryParamsToAdd); err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"failed to parse add_query_parameters").CausedBy(err)
+	}
+
+	if rewriter.requestHeaders, err = newHeadersRewriter(conf.RequestHeaders); err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"failed to parse request_headers").CausedBy(err)
+	}
+
+	if rewriter.responseHeaders, err = newHeadersRewriter(conf.ResponseHeaders); err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"failed to parse response_headers").CausedBy(err)
+	}
+
+	return &rewriter, nil
+}
+
+func newHeadersRewriter(conf *config.HeadersRewriter) (*headersRewriter, error) {
+	if conf == nil {
+		return nil, nil // nolint: nilnil
+	}
+
+	set, err := newTemplates(conf.Set)
+	if err != nil {
+		return nil, err
+	}
+
+	return &headersRewriter{set: set, remove: conf.Remove}, nil
+}
+
+func newTemplates(values map[string]string) (map[string]template.Template, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	templates := make(map[string]template.Template, len(values))
+
+	for name, value := range values {
+		tpl, err := template.New(value)
+		if err != nil {
+			return nil, err
+		}
+
+		templates[name] = tpl
+	}
+
+	return templates, nil
+}
+
+// rewrite applies the path and query rewrites to the url of the given backend and renders
+// the header modifications to be applied while forwarding the request and processing the
+// response.
+func (r *backendRewriter) rewrite(be *backend, values map[string]any) error {
+	if r.pathRegex != nil {
+		rawPath := r.pathRegex.ReplaceAllString(be.targetURL.EscapedPath(), r.pathReplacement)
+
+		path, err := url.PathUnescape(rawPath)
+		if err != nil {
+			return errorchain.NewWithMessage(heimdall.ErrInternal,
+				"rewritten path is not properly escaped").CausedBy(err)
+		}
+
+		be.targetURL.Path = path
+		be.targetURL.RawPath = x.IfThenElse(path != rawPath, rawPath, "")
+	}
+
+	if len(r.queryParams) != 0 {
+		query := be.targetURL.Query()
+
+		for name, tpl := range r.queryParams {
+			value, err := tpl.Render(values)
+			if err != nil {
+				return errorchain.NewWithMessagef(heimdall.ErrInternal,
+					"failed to render value for '%s' query parameter", name).CausedBy(err)
+			}
+
+			query.Set(name, value)
+		}
+
+		be.targetURL.RawQuery = query.Encode()
+	}
+
+	var err error
+
+	if be.requestHeaders, err = r.requestHeaders.render(values); err != nil {
+		return err
+	}
+
+	be.responseHeaders, err = r.responseHeaders.render(values)
+
+	return err
+}
+
+func (h *headersRewriter) render(values map[string]any) (*headerModifications, error) {
+	if h == nil {
+		return nil, nil // nolint: nilnil
+	}
+
+	mods := &headerModifications{remove: h.remove, set: make(map[string]string, len(h.set))}
+
+	for name, tpl := range h.set {
+		value, err := tpl.Render(values)
+		if err != nil {
+			return nil, errorchain.NewWithMessagef(heimdall.ErrInternal,
+				"failed to render value for '%s' header", name).CausedBy(err)
+		}
+
+		mods.set[name] = value
+	}
+
+	return mods, nil
+}
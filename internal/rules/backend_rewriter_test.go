@@ -0,0 +1,296 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	heimdallmocks "github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+)
+
+func TestNewBackendRewriter(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc     string
+		conf   *config.URLRewriter
+		assert func(t *testing.T, err error, rewriter *backendRewriter)
+	}{
+		{
+			uc: "without configuration",
+			assert: func(t *testing.T, err error, rewriter *backendRewriter) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Nil(t, rewriter)
+			},
+		},
+		{
+			uc:   "with prefix based rewrites only",
+			conf: &config.URLRewriter{Scheme: "https", PathPrefixToCut: "/api"},
+			assert: func(t *testing.T, err error, rewriter *backendRewriter) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Nil(t, rewriter)
+			},
+		},
+		{
+			uc:   "with path rewrite without regex",
+			conf: &config.URLRewriter{Path: &config.PathRewriter{Replacement: "/foo"}},
+			assert: func(t *testing.T, err error, _ *backendRewriter) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "requires a regex")
+			},
+		},
+		{
+			uc:   "with path rewrite with bad regex",
+			conf: &config.URLRewriter{Path: &config.PathRewriter{Regex: "(foo", Replacement: "/foo"}},
+			assert: func(t *testing.T, err error, _ *backendRewriter) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed to compile")
+			},
+		},
+		{
+			uc:   "with bad query parameter template",
+			conf: &config.URLRewriter{QueryParamsToAdd: map[string]string{"foo": "{{ .Foo "}},
+			assert: func(t *testing.T, err error, _ *backendRewriter) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "add_query_parameters")
+			},
+		},
+		{
+			uc: "with bad request header template",
+			conf: &config.URLRewriter{
+				RequestHeaders: &config.HeadersRewriter{Set: map[string]string{"X-Foo": "{{ .Foo "}},
+			},
+			assert: func(t *testing.T, err error, _ *backendRewriter) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "request_headers")
+			},
+		},
+		{
+			uc: "with bad response header template",
+			conf: &config.URLRewriter{
+				ResponseHeaders: &config.HeadersRewriter{Set: map[string]string{"X-Foo": "{{ .Foo "}},
+			},
+			assert: func(t *testing.T, err error, _ *backendRewriter) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "response_headers")
+			},
+		},
+		{
+			uc: "with all rewrites",
+			conf: &config.URLRewriter{
+				Path:             &config.PathRewriter{Regex: "^/api/(.*)$", Replacement: "/$1"},
+				QueryParamsToAdd: map[string]string{"foo": "bar"},
+				RequestHeaders:   &config.HeadersRewriter{Set: map[string]string{"X-Foo": "bar"}, Remove: []string{"X-Bar"}},
+				ResponseHeaders:  &config.HeadersRewriter{Remove: []string{"Server"}},
+			},
+			assert: func(t *testing.T, err error, rewriter *backendRewriter) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, rewriter)
+				assert.NotNil(t, rewriter.pathRegex)
+				assert.Equal(t, "/$1", rewriter.pathReplacement)
+				assert.Len(t, rewriter.queryParams, 1)
+				require.NotNil(t, rewriter.requestHeaders)
+				assert.Len(t, rewriter.requestHeaders.set, 1)
+				assert.Equal(t, []string{"X-Bar"}, rewriter.requestHeaders.remove)
+				require.NotNil(t, rewriter.responseHeaders)
+				assert.Empty(t, rewriter.responseHeaders.set)
+				assert.Equal(t, []string{"Server"}, rewriter.responseHeaders.remove)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// WHEN
+			rewriter, err := newBackendRewriter(tc.conf)
+
+			// THEN
+			tc.assert(t, err, rewriter)
+		})
+	}
+}
+
+func TestBackendRewriterRewrite(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc        string
+		conf      *config.URLRewriter
+		targetURL string
+		assert    func(t *testing.T, err error, be *backend)
+	}{
+		{
+			uc: "path is rewritten using capture groups",
+			conf: &config.URLRewriter{
+				Path: &config.PathRewriter{
+					Regex:       "^/api/(?P<version>v[0-9]+)/users/([^/]+)$",
+					Replacement: "/${version}/accounts/$2/profile",
+				},
+			},
+			targetURL: "http://foo.bar/api/v1/users/alice?foo=bar",
+			assert: func(t *testing.T, err error, be *backend) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "http://foo.bar/v1/accounts/alice/profile?foo=bar", be.targetURL.String())
+			},
+		},
+		{
+			uc:        "url encoded path is rewritten",
+			conf:      &config.URLRewriter{Path: &config.PathRewriter{Regex: "^/api/(.*)$", Replacement: "/$1"}},
+			targetURL: "http://foo.bar/api/foo%2Fbar",
+			assert: func(t *testing.T, err error, be *backend) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "/foo/bar", be.targetURL.Path)
+				assert.Equal(t, "/foo%2Fbar", be.targetURL.RawPath)
+			},
+		},
+		{
+			uc:        "not matching path is not rewritten",
+			conf:      &config.URLRewriter{Path: &config.PathRewriter{Regex: "^/api/(.*)$", Replacement: "/$1"}},
+			targetURL: "http://foo.bar/foo/bar",
+			assert: func(t *testing.T, err error, be *backend) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "http://foo.bar/foo/bar", be.targetURL.String())
+			},
+		},
+		{
+			uc: "query parameters are added from templates",
+			conf: &config.URLRewriter{
+				QueryParamsToAdd: map[string]string{
+					"user": "{{ .Subject.ID }}",
+					"foo":  "{{ .Request.Header \"X-Foo\" }}",
+				},
+			},
+			targetURL: "http://foo.bar/foo?foo=baz&bar=foo",
+			assert: func(t *testing.T, err error, be *backend) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "bar=foo&foo=bar&user=alice", be.targetURL.RawQuery)
+			},
+		},
+		{
+			uc:        "rendering of a query parameter fails",
+			conf:      &config.URLRewriter{QueryParamsToAdd: map[string]string{"foo": "{{ .Subject.Foo.Bar }}"}},
+			targetURL: "http://foo.bar/foo",
+			assert: func(t *testing.T, err error, _ *backend) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				assert.Contains(t, err.Error(), "'foo' query parameter")
+			},
+		},
+		{
+			uc: "header modifications are rendered",
+			conf: &config.URLRewriter{
+				RequestHeaders: &config.HeadersRewriter{
+					Set:    map[string]string{"X-User": "{{ .Subject.ID }}"},
+					Remove: []string{"X-Internal"},
+				},
+				ResponseHeaders: &config.HeadersRewriter{
+					Set:    map[string]string{"X-Served-For": "{{ .Subject.ID }}"},
+					Remove: []string{"Server"},
+				},
+			},
+			targetURL: "http://foo.bar/foo",
+			assert: func(t *testing.T, err error, be *backend) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				reqHeader := http.Header{"X-Internal": []string{"foo"}, "X-User": []string{"bob"}}
+				be.RewriteRequestHeaders(reqHeader)
+				assert.Equal(t, http.Header{"X-User": []string{"alice"}}, reqHeader)
+
+				respHeader := http.Header{"Server": []string{"foo"}}
+				be.RewriteResponseHeaders(respHeader)
+				assert.Equal(t, http.Header{"X-Served-For": []string{"alice"}}, respHeader)
+			},
+		},
+		{
+			uc: "rendering of a header fails",
+			conf: &config.URLRewriter{
+				ResponseHeaders: &config.HeadersRewriter{Set: map[string]string{"X-Foo": "{{ .Subject.Foo.Bar }}"}},
+			},
+			targetURL: "http://foo.bar/foo",
+			assert: func(t *testing.T, err error, _ *backend) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				assert.Contains(t, err.Error(), "'X-Foo' header")
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			rewriter, err := newBackendRewriter(tc.conf)
+			require.NoError(t, err)
+
+			targetURL, err := url.Parse(tc.targetURL)
+			require.NoError(t, err)
+
+			fnt := heimdallmocks.NewRequestFunctionsMock(t)
+			fnt.EXPECT().Header("X-Foo").Return("bar").Maybe()
+
+			be := &backend{targetURL: targetURL}
+			values := map[string]any{
+				"Request": &heimdall.Request{RequestFunctions: fnt, URL: &heimdall.URL{URL: *targetURL}},
+				"Subject": &subject.Subject{ID: "alice"},
+			}
+
+			// WHEN
+			err = rewriter.rewrite(be, values)
+
+			// THEN
+			tc.assert(t, err, be)
+		})
+	}
+}
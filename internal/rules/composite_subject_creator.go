@@ -30,6 +30,7 @@ type compositeSubjectCreator []subjectCreator
 
 func (ca compositeSubjectCreator) Execute(ctx heimdall.Context) (*subject.Subject, error) {
 	logger := zerolog.Ctx(ctx.AppContext())
+	observer := observerFrom(ctx)
 
 	var (
 		sub *subject.Subject
@@ -37,6 +38,8 @@ func (ca compositeSubjectCreator) Execute(ctx heimdall.Context) (*subject.Subjec
 	)
 
 	for idx, a := range ca {
+		start := observer.mechanismStarted()
+
 		sub, err = a.Execute(ctx)
 		if err != nil {
 			logger.Info().Err(err).Msg("Pipeline step execution failed")
@@ -44,12 +47,18 @@ func (ca compositeSubjectCreator) Execute(ctx heimdall.Context) (*subject.Subjec
 			if (errors.Is(err, heimdall.ErrArgument) || a.IsFallbackOnErrorAllowed()) && idx < len(ca) {
 				logger.Info().Msg("Falling back to next configured one.")
 
+				observer.mechanismFinished(ctx.AppContext(), start, a, "authenticator", outcomeFallback, err)
+
 				continue
 			}
 
+			observer.mechanismFinished(ctx.AppContext(), start, a, "authenticator", outcomeFailure, err)
+
 			break
 		}
 
+		observer.mechanismFinished(ctx.AppContext(), start, a, "authenticator", outcomeSuccess, nil)
+
 		accesscontext.SetSubject(ctx.AppContext(), sub.ID)
 
 		return sub, nil
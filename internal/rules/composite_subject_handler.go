@@ -27,17 +27,27 @@ type compositeSubjectHandler []subjectHandler
 
 func (cm compositeSubjectHandler) Execute(ctx heimdall.Context, sub *subject.Subject) error {
 	logger := zerolog.Ctx(ctx.AppContext())
+	observer := observerFrom(ctx)
 
 	for _, handler := range cm {
+		typ := mechanismType(handler)
+		start := observer.mechanismStarted()
+
 		err := handler.Execute(ctx, sub)
 		if err != nil {
 			logger.Info().Err(err).Msg("Pipeline step execution failed")
 
 			if handler.ContinueOnError() {
 				logger.Info().Msg("Error ignored. Continuing pipeline execution")
+
+				observer.mechanismFinished(ctx.AppContext(), start, handler, typ, outcomeIgnored, err)
 			} else {
+				observer.mechanismFinished(ctx.AppContext(), start, handler, typ, outcomeFailure, err)
+
 				return err
 			}
+		} else {
+			observer.mechanismFinished(ctx.AppContext(), start, handler, typ, outcomeSuccess, nil)
 		}
 	}
 
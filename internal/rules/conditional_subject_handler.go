@@ -26,8 +26,9 @@ import (
 )
 
 type conditionalSubjectHandler struct {
-	h subjectHandler
-	c executionCondition
+	h   subjectHandler
+	c   executionCondition
+	typ string
 }
 
 func (h *conditionalSubjectHandler) Execute(ctx heimdall.Context, sub *subject.Subject) error {
@@ -35,7 +36,7 @@ func (h *conditionalSubjectHandler) Execute(ctx heimdall.Context, sub *subject.S
 
 	logger.Debug().Str("_id", h.h.ID()).Msg("Checking execution condition")
 
-	if logger.GetLevel() == zerolog.TraceLevel {
+	if logger.GetLevel() == zerolog.TraceLevel && zerolog.GlobalLevel() == zerolog.TraceLevel {
 		dump, err := json.Marshal(sub)
 		if err != nil {
 			logger.Trace().Err(err).Msg("Failed to dump subject")
@@ -51,10 +52,13 @@ func (h *conditionalSubjectHandler) Execute(ctx heimdall.Context, sub *subject.S
 	}
 
 	logger.Debug().Str("_id", h.h.ID()).Msg("Execution skipped")
+	observerFrom(ctx).executionSkipped()
 
 	return nil
 }
 
 func (h *conditionalSubjectHandler) ID() string { return h.h.ID() }
 
+func (h *conditionalSubjectHandler) Type() string { return h.typ }
+
 func (h *conditionalSubjectHandler) ContinueOnError() bool { return h.h.ContinueOnError() }
@@ -23,8 +23,12 @@ import (
 )
 
 type Backend struct {
-	Host        string       `json:"host"    yaml:"host"`
-	URLRewriter *URLRewriter `json:"rewrite" yaml:"rewrite"`
+	Host          string         `json:"host"                     yaml:"host"`
+	Targets       []string       `json:"targets,omitempty"        yaml:"targets,omitempty"`
+	LoadBalancing *LoadBalancing `json:"load_balancing,omitempty" yaml:"load_balancing,omitempty"`
+	URLRewriter   *URLRewriter   `json:"rewrite"                  yaml:"rewrite"`
+	TLS           *BackendTLS    `json:"tls,omitempty"            yaml:"tls,omitempty"`
+	HealthCheck   *HealthCheck   `json:"health_check,omitempty"   yaml:"health_check,omitempty"`
 }
 
 func (f *Backend) CreateURL(value *url.URL) *url.URL {
@@ -43,6 +47,15 @@ func (f *Backend) CreateURL(value *url.URL) *url.URL {
 	return upstreamURL
 }
 
+// Hosts returns the hosts requests can be forwarded to.
+func (f *Backend) Hosts() []string {
+	if len(f.Targets) != 0 {
+		return f.Targets
+	}
+
+	return []string{f.Host}
+}
+
 func (f *Backend) DeepCopyInto(out *Backend) {
 	if f == nil {
 		return
@@ -19,6 +19,7 @@ package config
 import (
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -80,6 +81,11 @@ func TestUpstreamURLFactoryDeepCopyInto(t *testing.T) {
 			PathPrefixToAdd:     "/baz",
 			QueryParamsToRemove: QueryParamsRemover{"foo", "bar"},
 		},
+		LoadBalancing: &LoadBalancing{
+			Strategy:           LoadBalancingLeastConnections,
+			PassiveHealthCheck: &PassiveHealthCheck{MaxFailures: 2, CoolDown: 10 * time.Second},
+		},
+		TLS: &BackendTLS{ServerName: "foo.local"},
 	}
 
 	// WHEN
@@ -88,3 +94,28 @@ func TestUpstreamURLFactoryDeepCopyInto(t *testing.T) {
 	// THEN
 	require.Equal(t, in, out)
 }
+
+func TestBackendHosts(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc       string
+		backend  *Backend
+		expected []string
+	}{
+		{uc: "host only", backend: &Backend{Host: "foo:8080"}, expected: []string{"foo:8080"}},
+		{
+			uc:       "targets only",
+			backend:  &Backend{Targets: []string{"foo:8080", "bar:8080"}},
+			expected: []string{"foo:8080", "bar:8080"},
+		},
+	} {
+		t.Run(tc.uc, func(t *testing.T) {
+			// WHEN
+			hosts := tc.backend.Hosts()
+
+			// THEN
+			assert.Equal(t, tc.expected, hosts)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/keystore"
+	"github.com/dadrus/heimdall/internal/truststore"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+// BackendTLS is comparable by intention, so it can be used as a key to share
+// resources, like http transports, between backends having the same configuration.
+// The key and the trust store are referenced by their names and are resolved using
+// the stores declared in the static heimdall configuration.
+type BackendTLS struct {
+	TrustStore string               `json:"trust_store,omitempty" yaml:"trust_store,omitempty"`
+	KeyStore   string               `json:"key_store,omitempty"   yaml:"key_store,omitempty"`
+	KeyID      string               `json:"key_id,omitempty"      yaml:"key_id,omitempty"`
+	ServerName string               `json:"server_name,omitempty" yaml:"server_name,omitempty"`
+	MinVersion config.TLSMinVersion `json:"min_version,omitempty" yaml:"min_version,omitempty"`
+}
+
+func (t *BackendTLS) UnmarshalJSON(data []byte) error {
+	var rawData map[string]any
+
+	if err := json.Unmarshal(data, &rawData); err != nil {
+		return err
+	}
+
+	// the alias type prevents endless recursion
+	type backendTLS BackendTLS
+
+	var conf backendTLS
+	if err := DecodeConfig(rawData, &conf); err != nil {
+		return err
+	}
+
+	*t = BackendTLS(conf)
+
+	return nil
+}
+
+func (t *BackendTLS) TLSConfig(stores config.UpstreamTLS) (*tls.Config, error) {
+	// nolint:gosec
+	// configuration ensures, TLS versions below 1.2 are not possible
+	cfg := &tls.Config{
+		// unlike for the services exposed by heimdall, TLS 1.2 is the default
+		// to not break communication with upstreams not supporting TLS 1.3
+		MinVersion: x.IfThenElse(t.MinVersion != 0, uint16(t.MinVersion), tls.VersionTLS12),
+		ServerName: t.ServerName,
+	}
+
+	var err error
+
+	if len(t.TrustStore) != 0 {
+		if cfg.RootCAs, err = t.rootCAs(stores); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(t.KeyStore) != 0 {
+		cert, err := t.clientCertificate(stores)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+func (t *BackendTLS) rootCAs(stores config.UpstreamTLS) (*x509.CertPool, error) {
+	path, ok := stores.TrustStores[t.TrustStore]
+	if !ok {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"trust store '%s' is not declared in upstream_tls", t.TrustStore)
+	}
+
+	ts, err := truststore.NewTrustStoreFromPEMFile(path, true)
+	if err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed loading trust store '%s'", t.TrustStore).CausedBy(err)
+	}
+
+	pool := x509.NewCertPool()
+	for _, cert := range ts {
+		pool.AddCert(cert)
+	}
+
+	return pool, nil
+}
+
+func (t *BackendTLS) clientCertificate(stores config.UpstreamTLS) (tls.Certificate, error) {
+	ksConf, ok := stores.KeyStores[t.KeyStore]
+	if !ok {
+		return tls.Certificate{}, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"key store '%s' is not declared in upstream_tls", t.KeyStore)
+	}
+
+	ks, err := keystore.NewKeyStore(ksConf)
+	if err != nil {
+		return tls.Certificate{}, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed loading key store '%s'", t.KeyStore).CausedBy(err)
+	}
+	var entry *keystore.Entry
+
+	if len(t.KeyID) != 0 {
+		if entry, err = ks.GetKey(t.KeyID); err != nil {
+			return tls.Certificate{}, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+				"failed retrieving key from key store").CausedBy(err)
+		}
+	} else {
+		entries := ks.Entries()
+		if len(entries) == 0 {
+			return tls.Certificate{}, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+				"key store does not contain any keys")
+		}
+
+		entry = entries[0]
+	}
+
+	cert, err := keystore.ToTLSCertificate(entry)
+	if err != nil {
+		return tls.Certificate{}, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"key store entry is not suitable for TLS").CausedBy(err)
+	}
+
+	return cert, nil
+}
@@ -0,0 +1,312 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/x/pkix/pemx"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestBackendTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	testDir := t.TempDir()
+
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	cert, err := testsupport.NewCertificateBuilder(
+		testsupport.WithSerialNumber(big.NewInt(1)),
+		testsupport.WithValidity(time.Now(), 10*time.Hour),
+		testsupport.WithSubject(pkix.Name{
+			CommonName:   "test client",
+			Organization: []string{"Test"},
+			Country:      []string{"EU"},
+		}),
+		testsupport.WithSubjectPubKey(&privKey.PublicKey, x509.ECDSAWithSHA256),
+		testsupport.WithSignaturePrivKey(privKey),
+		testsupport.WithKeyUsage(x509.KeyUsageDigitalSignature),
+		testsupport.WithExtendedKeyUsage(x509.ExtKeyUsageClientAuth),
+		testsupport.WithGeneratedSubjectKeyID(),
+		testsupport.WithSelfSigned(),
+	).Build()
+	require.NoError(t, err)
+
+	keyStorePEM, err := pemx.BuildPEM(
+		pemx.WithECDSAPrivateKey(privKey, pemx.WithHeader("X-Key-ID", "foo")),
+		pemx.WithX509Certificate(cert),
+	)
+	require.NoError(t, err)
+
+	keyStoreFile := filepath.Join(testDir, "keystore.pem")
+	require.NoError(t, os.WriteFile(keyStoreFile, keyStorePEM, 0o600))
+
+	trustStorePEM, err := pemx.BuildPEM(pemx.WithX509Certificate(cert))
+	require.NoError(t, err)
+
+	trustStoreFile := filepath.Join(testDir, "truststore.pem")
+	require.NoError(t, os.WriteFile(trustStoreFile, trustStorePEM, 0o600))
+
+	stores := config.UpstreamTLS{
+		KeyStores: map[string]config.KeyStore{
+			"missing":     {Path: filepath.Join(testDir, "missing.pem")},
+			"unsupported": {Type: "foo", Path: keyStoreFile},
+			"client":      {Path: keyStoreFile},
+			"no-cert":     {Path: trustStoreFile},
+		},
+		TrustStores: map[string]string{
+			"missing": filepath.Join(testDir, "missing.pem"),
+			"ca":      trustStoreFile,
+		},
+	}
+
+	for _, tc := range []struct {
+		uc     string
+		conf   BackendTLS
+		assert func(t *testing.T, err error, cfg *tls.Config)
+	}{
+		{
+			uc: "empty configuration",
+			assert: func(t *testing.T, err error, cfg *tls.Config) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, uint16(tls.VersionTLS12), cfg.MinVersion)
+				assert.Empty(t, cfg.ServerName)
+				assert.Nil(t, cfg.RootCAs)
+				assert.Empty(t, cfg.Certificates)
+			},
+		},
+		{
+			uc:   "with server name and min version",
+			conf: BackendTLS{ServerName: "foo.local", MinVersion: config.TLSMinVersion(tls.VersionTLS13)},
+			assert: func(t *testing.T, err error, cfg *tls.Config) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, uint16(tls.VersionTLS13), cfg.MinVersion)
+				assert.Equal(t, "foo.local", cfg.ServerName)
+			},
+		},
+		{
+			uc:   "with undeclared trust store",
+			conf: BackendTLS{TrustStore: trustStoreFile},
+			assert: func(t *testing.T, err error, _ *tls.Config) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "not declared")
+			},
+		},
+		{
+			uc:   "with not existing trust store",
+			conf: BackendTLS{TrustStore: "missing"},
+			assert: func(t *testing.T, err error, _ *tls.Config) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "trust store")
+			},
+		},
+		{
+			uc:   "with trust store",
+			conf: BackendTLS{TrustStore: "ca"},
+			assert: func(t *testing.T, err error, cfg *tls.Config) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				expected := x509.NewCertPool()
+				expected.AddCert(cert)
+
+				require.NotNil(t, cfg.RootCAs)
+				assert.True(t, expected.Equal(cfg.RootCAs))
+			},
+		},
+		{
+			uc:   "with undeclared key store",
+			conf: BackendTLS{KeyStore: keyStoreFile},
+			assert: func(t *testing.T, err error, _ *tls.Config) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "not declared")
+			},
+		},
+		{
+			uc:   "with not existing key store",
+			conf: BackendTLS{KeyStore: "missing"},
+			assert: func(t *testing.T, err error, _ *tls.Config) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "key store")
+			},
+		},
+		{
+			uc:   "with unsupported key store type",
+			conf: BackendTLS{KeyStore: "unsupported"},
+			assert: func(t *testing.T, err error, _ *tls.Config) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "unsupported key store type")
+			},
+		},
+		{
+			uc:   "with key store and not existing key id",
+			conf: BackendTLS{KeyStore: "client", KeyID: "bar"},
+			assert: func(t *testing.T, err error, _ *tls.Config) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "retrieving key")
+			},
+		},
+		{
+			uc:   "with key store without certificate",
+			conf: BackendTLS{KeyStore: "no-cert"},
+			assert: func(t *testing.T, err error, _ *tls.Config) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+			},
+		},
+		{
+			uc:   "with key store and key id",
+			conf: BackendTLS{KeyStore: "client", KeyID: "foo"},
+			assert: func(t *testing.T, err error, cfg *tls.Config) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.Len(t, cfg.Certificates, 1)
+				assert.Equal(t, cert, cfg.Certificates[0].Leaf)
+				assert.Equal(t, privKey, cfg.Certificates[0].PrivateKey)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// WHEN
+			cfg, err := tc.conf.TLSConfig(stores)
+
+			// THEN
+			tc.assert(t, err, cfg)
+		})
+	}
+}
+
+func TestBackendTLSUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc     string
+		config []byte
+		assert func(t *testing.T, err error, conf *BackendTLS)
+	}{
+		{
+			uc:     "with all settings",
+			config: []byte(`{"trust_store":"ca","key_store":"client","key_id":"foo","server_name":"foo.local","min_version":"TLS1.3"}`),
+			assert: func(t *testing.T, err error, conf *BackendTLS) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, BackendTLS{
+					TrustStore: "ca",
+					KeyStore:   "client",
+					KeyID:      "foo",
+					ServerName: "foo.local",
+					MinVersion: config.TLSMinVersion(tls.VersionTLS13),
+				}, *conf)
+			},
+		},
+		{
+			uc:     "with key store defined in the rule",
+			config: []byte(`{"key_store":{"type":"pkcs11","path":"/usr/lib/evil.so"}}`),
+			assert: func(t *testing.T, err error, _ *BackendTLS) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+			},
+		},
+		{
+			uc:     "with key store path defined in the rule",
+			config: []byte(`{"key_store":{"path":"/etc/heimdall/signer.pem"}}`),
+			assert: func(t *testing.T, err error, _ *BackendTLS) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+			},
+		},
+		{
+			uc:     "with unsupported min version",
+			config: []byte(`{"min_version":"TLS1.1"}`),
+			assert: func(t *testing.T, err error, _ *BackendTLS) {
+				t.Helper()
+
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "TLS1.1")
+			},
+		},
+		{
+			uc:     "with unknown property",
+			config: []byte(`{"foo":"bar"}`),
+			assert: func(t *testing.T, err error, _ *BackendTLS) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			var conf BackendTLS
+
+			// WHEN
+			err := json.Unmarshal(tc.config, &conf)
+
+			// THEN
+			tc.assert(t, err, &conf)
+		})
+	}
+}
@@ -19,6 +19,7 @@ package config
 import (
 	"github.com/mitchellh/mapstructure"
 
+	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/validation"
 	"github.com/dadrus/heimdall/internal/x/errorchain"
@@ -29,6 +30,7 @@ func DecodeConfig(input any, output any) error {
 		&mapstructure.DecoderConfig{
 			DecodeHook: mapstructure.ComposeDecodeHookFunc(
 				matcherDecodeHookFunc,
+				config.DecodeTLSMinVersionHookFunc,
 				mapstructure.StringToTimeDurationHookFunc(),
 			),
 			Result:      output,
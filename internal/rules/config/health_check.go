@@ -0,0 +1,43 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+type HealthCheck struct {
+	Path               string        `json:"path,omitempty"                yaml:"path,omitempty"`
+	Scheme             string        `json:"scheme,omitempty"              yaml:"scheme,omitempty"              validate:"omitempty,oneof=http https"` //nolint:lll,tagalign
+	ExpectedStatus     int           `json:"expected_status,omitempty"     yaml:"expected_status,omitempty"     validate:"omitempty,gte=100,lte=599"`  //nolint:lll,tagalign
+	Interval           time.Duration `json:"interval,omitempty"            yaml:"interval,omitempty"            validate:"gte=0"`                      //nolint:lll,tagalign
+	Timeout            time.Duration `json:"timeout,omitempty"             yaml:"timeout,omitempty"             validate:"gte=0"`                      //nolint:lll,tagalign
+	HealthyThreshold   int           `json:"healthy_threshold,omitempty"   yaml:"healthy_threshold,omitempty"   validate:"gte=0"`                      //nolint:lll,tagalign
+	UnhealthyThreshold int           `json:"unhealthy_threshold,omitempty" yaml:"unhealthy_threshold,omitempty" validate:"gte=0"`                      //nolint:lll,tagalign
+}
+
+func (h *HealthCheck) UnmarshalJSON(data []byte) error {
+	var rawData map[string]any
+
+	if err := json.Unmarshal(data, &rawData); err != nil {
+		return err
+	}
+
+	return DecodeConfig(rawData, h)
+}
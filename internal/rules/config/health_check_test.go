@@ -0,0 +1,108 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthCheckUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc     string
+		data   string
+		assert func(t *testing.T, err error, hc *HealthCheck)
+	}{
+		{
+			uc: "all properties specified",
+			data: `{
+"path": "/health",
+"scheme": "https",
+"expected_status": 204,
+"interval": "5s",
+"timeout": "1s",
+"healthy_threshold": 1,
+"unhealthy_threshold": 2
+}`,
+			assert: func(t *testing.T, err error, hc *HealthCheck) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "/health", hc.Path)
+				assert.Equal(t, "https", hc.Scheme)
+				assert.Equal(t, 204, hc.ExpectedStatus)
+				assert.Equal(t, 5*time.Second, hc.Interval)
+				assert.Equal(t, time.Second, hc.Timeout)
+				assert.Equal(t, 1, hc.HealthyThreshold)
+				assert.Equal(t, 2, hc.UnhealthyThreshold)
+			},
+		},
+		{
+			uc:   "unsupported scheme",
+			data: `{"scheme": "ftp"}`,
+			assert: func(t *testing.T, err error, _ *HealthCheck) {
+				t.Helper()
+
+				require.Error(t, err)
+			},
+		},
+		{
+			uc:   "invalid expected status",
+			data: `{"expected_status": 42}`,
+			assert: func(t *testing.T, err error, _ *HealthCheck) {
+				t.Helper()
+
+				require.Error(t, err)
+			},
+		},
+		{
+			uc:   "negative interval",
+			data: `{"interval": "-1s"}`,
+			assert: func(t *testing.T, err error, _ *HealthCheck) {
+				t.Helper()
+
+				require.Error(t, err)
+			},
+		},
+		{
+			uc:   "unknown property",
+			data: `{"foo": "bar"}`,
+			assert: func(t *testing.T, err error, _ *HealthCheck) {
+				t.Helper()
+
+				require.Error(t, err)
+			},
+		},
+	} {
+		t.Run(tc.uc, func(t *testing.T) {
+			// GIVEN
+			var hc HealthCheck
+
+			// WHEN
+			err := json.Unmarshal([]byte(tc.data), &hc)
+
+			// THEN
+			tc.assert(t, err, &hc)
+		})
+	}
+}
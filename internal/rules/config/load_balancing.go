@@ -0,0 +1,57 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+type LoadBalancingStrategy string
+
+const (
+	LoadBalancingRoundRobin       LoadBalancingStrategy = "round_robin"
+	LoadBalancingLeastConnections LoadBalancingStrategy = "least_connections"
+	LoadBalancingConsistentHash   LoadBalancingStrategy = "consistent_hash"
+)
+
+type LoadBalancing struct {
+	Strategy           LoadBalancingStrategy `json:"strategy,omitempty"             yaml:"strategy,omitempty"             validate:"omitempty,oneof=round_robin least_connections consistent_hash"` //nolint:lll,tagalign
+	HashOn             *HashOn               `json:"hash_on,omitempty"              yaml:"hash_on,omitempty"`
+	PassiveHealthCheck *PassiveHealthCheck   `json:"passive_health_check,omitempty" yaml:"passive_health_check,omitempty"`
+}
+
+type HashOn struct {
+	Header    string `json:"header,omitempty"     yaml:"header,omitempty"`
+	SubjectID bool   `json:"subject_id,omitempty" yaml:"subject_id,omitempty"`
+}
+
+type PassiveHealthCheck struct {
+	MaxFailures int           `json:"max_failures,omitempty" yaml:"max_failures,omitempty" validate:"gte=0"` //nolint:lll,tagalign
+	CoolDown    time.Duration `json:"cool_down,omitempty"    yaml:"cool_down,omitempty"    validate:"gte=0"` //nolint:lll,tagalign
+}
+
+func (p *PassiveHealthCheck) UnmarshalJSON(data []byte) error {
+	var rawData map[string]any
+
+	if err := json.Unmarshal(data, &rawData); err != nil {
+		return err
+	}
+
+	return DecodeConfig(rawData, p)
+}
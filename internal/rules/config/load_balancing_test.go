@@ -0,0 +1,87 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPassiveHealthCheckUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc     string
+		data   string
+		assert func(t *testing.T, err error, phc *PassiveHealthCheck)
+	}{
+		{
+			uc:   "cool down specified as string",
+			data: `{"max_failures": 4, "cool_down": "1m30s"}`,
+			assert: func(t *testing.T, err error, phc *PassiveHealthCheck) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, 4, phc.MaxFailures)
+				assert.Equal(t, 90*time.Second, phc.CoolDown)
+			},
+		},
+		{
+			uc:   "cool down specified in nanoseconds",
+			data: `{"cool_down": 1000000000}`,
+			assert: func(t *testing.T, err error, phc *PassiveHealthCheck) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, time.Second, phc.CoolDown)
+			},
+		},
+		{
+			uc:   "negative max failures",
+			data: `{"max_failures": -1}`,
+			assert: func(t *testing.T, err error, _ *PassiveHealthCheck) {
+				t.Helper()
+
+				require.Error(t, err)
+			},
+		},
+		{
+			uc:   "unknown property",
+			data: `{"foo": "bar"}`,
+			assert: func(t *testing.T, err error, _ *PassiveHealthCheck) {
+				t.Helper()
+
+				require.Error(t, err)
+			},
+		},
+	} {
+		t.Run(tc.uc, func(t *testing.T) {
+			// GIVEN
+			var phc PassiveHealthCheck
+
+			// WHEN
+			err := json.Unmarshal([]byte(tc.data), &phc)
+
+			// THEN
+			tc.assert(t, err, &phc)
+		})
+	}
+}
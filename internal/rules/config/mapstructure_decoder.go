@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"reflect"
 
+	"github.com/mitchellh/mapstructure"
+
 	"github.com/dadrus/heimdall/internal/x"
 )
 
@@ -29,6 +31,7 @@ var (
 	ErrURLType             = errors.New("bad url type")
 	ErrStrategyType        = errors.New("bad strategy type")
 	ErrUnsupportedStrategy = errors.New("unsupported strategy")
+	ErrConditionsType      = errors.New("bad matching conditions")
 )
 
 func matcherDecodeHookFunc(from reflect.Type, to reflect.Type, data any) (any, error) {
@@ -74,8 +77,42 @@ func matcherDecodeHookFunc(from reflect.Type, to reflect.Type, data any) (any, e
 		}
 	}
 
+	var conditions struct {
+		Host        string            `json:"host"`
+		Headers     map[string]string `json:"headers"`
+		QueryParams map[string]string `json:"query_params"`
+		ClientIPs   []string          `json:"client_ip"`
+	}
+
+	// url and strategy are already handled above. Everything else must be a known matching
+	// condition, as an ignored typo would make the rule match more broadly than intended
+	conditionValues := make(map[string]any, len(values))
+
+	for key, value := range values {
+		if key != "url" && key != "strategy" {
+			conditionValues[key] = value
+		}
+	}
+
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:      &conditions,
+		TagName:     "json",
+		ErrorUnused: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = dec.Decode(conditionValues); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConditionsType, err)
+	}
+
 	return Matcher{
-		URL:      urlValue,
-		Strategy: x.IfThenElse(strategyPresent, strategyValue, "glob"),
+		URL:         urlValue,
+		Strategy:    x.IfThenElse(strategyPresent, strategyValue, "glob"),
+		Host:        conditions.Host,
+		Headers:     conditions.Headers,
+		QueryParams: conditions.QueryParams,
+		ClientIPs:   conditions.ClientIPs,
 	}, nil
 }
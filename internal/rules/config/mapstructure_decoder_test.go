@@ -146,6 +146,63 @@ match:
 				assert.Equal(t, "regex", matcher.Strategy)
 			},
 		},
+		{
+			uc: "specified as structured type with matching conditions",
+			config: []byte(`
+match: 
+  url: foo.bar
+  host: <*>.example.com
+  headers:
+    X-Api-Version: v2
+  query_params:
+    version: <{v2,v3}>
+  client_ip:
+    - 10.0.0.0/8
+    - 192.168.1.1
+`),
+			assert: func(t *testing.T, err error, matcher *Matcher) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "foo.bar", matcher.URL)
+				assert.Equal(t, "glob", matcher.Strategy)
+				assert.Equal(t, "<*>.example.com", matcher.Host)
+				assert.Equal(t, map[string]string{"X-Api-Version": "v2"}, matcher.Headers)
+				assert.Equal(t, map[string]string{"version": "<{v2,v3}>"}, matcher.QueryParams)
+				assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.1"}, matcher.ClientIPs)
+			},
+		},
+		{
+			uc: "specified as structured type with bad matching conditions",
+			config: []byte(`
+match: 
+  url: foo.bar
+  headers:
+    - X-Api-Version
+`),
+			assert: func(t *testing.T, err error, matcher *Matcher) {
+				t.Helper()
+
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), ErrConditionsType.Error())
+			},
+		},
+		{
+			uc: "specified as structured type with unknown matching condition",
+			config: []byte(`
+match: 
+  url: foo.bar
+  header:
+    X-Api-Version: v2
+`),
+			assert: func(t *testing.T, err error, _ *Matcher) {
+				t.Helper()
+
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), ErrConditionsType.Error())
+				assert.Contains(t, err.Error(), "header")
+			},
+		},
 	} {
 		t.Run(tc.uc, func(t *testing.T) {
 			// GIVEN
@@ -23,8 +23,12 @@ import (
 )
 
 type Matcher struct {
-	URL      string `json:"url"      yaml:"url"`
-	Strategy string `json:"strategy" yaml:"strategy"`
+	URL         string            `json:"url"                    yaml:"url"`
+	Strategy    string            `json:"strategy"               yaml:"strategy"`
+	Host        string            `json:"host,omitempty"         yaml:"host,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"      yaml:"headers,omitempty"`
+	QueryParams map[string]string `json:"query_params,omitempty" yaml:"query_params,omitempty"`
+	ClientIPs   []string          `json:"client_ip,omitempty"    yaml:"client_ip,omitempty"`
 }
 
 func (m *Matcher) UnmarshalJSON(data []byte) error {
@@ -44,3 +48,26 @@ func (m *Matcher) UnmarshalJSON(data []byte) error {
 
 	return DecodeConfig(rawData, m)
 }
+
+func (m *Matcher) DeepCopyInto(out *Matcher) {
+	*out = *m
+
+	if m.Headers != nil {
+		out.Headers = make(map[string]string, len(m.Headers))
+		for key, value := range m.Headers {
+			out.Headers[key] = value
+		}
+	}
+
+	if m.QueryParams != nil {
+		out.QueryParams = make(map[string]string, len(m.QueryParams))
+		for key, value := range m.QueryParams {
+			out.QueryParams[key] = value
+		}
+	}
+
+	if m.ClientIPs != nil {
+		out.ClientIPs = make([]string, len(m.ClientIPs))
+		copy(out.ClientIPs, m.ClientIPs)
+	}
+}
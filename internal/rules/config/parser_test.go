@@ -19,6 +19,7 @@ package config
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -120,6 +121,63 @@ rules:
 				assert.Len(t, ruleSet.Rules, 1)
 			},
 		},
+		{
+			uc:          "YAML content type with load balanced forward_to",
+			contentType: "application/yaml",
+			content: []byte(`
+version: "1"
+name: foo
+rules:
+- id: bar
+  forward_to:
+    targets:
+    - foo:8080
+    - bar:8080
+    load_balancing:
+      strategy: consistent_hash
+      hash_on:
+        header: X-User
+      passive_health_check:
+        max_failures: 5
+        cool_down: 1m
+`),
+			assert: func(t *testing.T, err error, ruleSet *RuleSet) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, ruleSet)
+				require.Len(t, ruleSet.Rules, 1)
+
+				backend := ruleSet.Rules[0].Backend
+				require.NotNil(t, backend)
+				assert.Equal(t, []string{"foo:8080", "bar:8080"}, backend.Hosts())
+				require.NotNil(t, backend.LoadBalancing)
+				assert.Equal(t, LoadBalancingConsistentHash, backend.LoadBalancing.Strategy)
+				assert.Equal(t, &HashOn{Header: "X-User"}, backend.LoadBalancing.HashOn)
+				assert.Equal(t, &PassiveHealthCheck{MaxFailures: 5, CoolDown: time.Minute},
+					backend.LoadBalancing.PassiveHealthCheck)
+			},
+		},
+		{
+			uc:          "YAML content type with unsupported load balancing strategy",
+			contentType: "application/yaml",
+			content: []byte(`
+version: "1"
+name: foo
+rules:
+- id: bar
+  forward_to:
+    host: foo:8080
+    load_balancing:
+      strategy: random
+`),
+			assert: func(t *testing.T, err error, ruleSet *RuleSet) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.Nil(t, ruleSet)
+			},
+		},
 		{
 			uc:          "YAML content type and validation error",
 			contentType: "application/yaml",
@@ -241,6 +299,51 @@ rules:
 				assert.Equal(t, "bar", ruleSet.Rules[0].ID)
 			},
 		},
+		{
+			uc: "rule set spec with a key store defined in the upstream tls settings of a rule",
+			conf: []byte(`
+version: "1"
+name: foo
+rules:
+- id: bar
+  forward_to:
+    host: foo.local
+    tls:
+      key_store:
+        type: pkcs11
+        path: /tmp/evil.so
+`),
+			assert: func(t *testing.T, err error, ruleSet *RuleSet) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.Nil(t, ruleSet)
+			},
+		},
+		{
+			uc: "rule set spec referencing key and trust stores in the upstream tls settings of a rule",
+			conf: []byte(`
+version: "1"
+name: foo
+rules:
+- id: bar
+  forward_to:
+    host: foo.local
+    tls:
+      trust_store: private-ca
+      key_store: upstream-client
+`),
+			assert: func(t *testing.T, err error, ruleSet *RuleSet) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, ruleSet)
+				require.Len(t, ruleSet.Rules, 1)
+				require.NotNil(t, ruleSet.Rules[0].Backend)
+				assert.Equal(t, &BackendTLS{TrustStore: "private-ca", KeyStore: "upstream-client"},
+					ruleSet.Rules[0].Backend.TLS)
+			},
+		},
 		{
 			uc: "valid rule set spec with valid env usage, which is however not enabled",
 			conf: []byte(`
@@ -30,6 +30,7 @@ const (
 
 type Rule struct {
 	ID                     string                   `json:"id"                    yaml:"id"`
+	Priority               int                      `json:"priority,omitempty"    yaml:"priority,omitempty"`
 	EncodedSlashesHandling EncodedSlashesHandling   `json:"allow_encoded_slashes" yaml:"allow_encoded_slashes" validate:"omitempty,oneof=off on no_decode"` //nolint:lll,tagalign
 	RuleMatcher            Matcher                  `json:"match"                 yaml:"match"`
 	Backend                *Backend                 `json:"forward_to"            yaml:"forward_to"`
@@ -40,7 +41,7 @@ type Rule struct {
 
 func (in *Rule) DeepCopyInto(out *Rule) {
 	*out = *in
-	out.RuleMatcher = in.RuleMatcher
+	in.RuleMatcher.DeepCopyInto(&out.RuleMatcher)
 
 	if in.Backend != nil {
 		in, out := in.Backend, out.Backend
@@ -25,9 +25,10 @@ import (
 )
 
 type MetaData struct {
-	Hash    []byte    `json:"-" yaml:"-"`
-	Source  string    `json:"-" yaml:"-"`
-	ModTime time.Time `json:"-" yaml:"-"`
+	Hash     []byte    `json:"-" yaml:"-"`
+	Source   string    `json:"-" yaml:"-"`
+	Provider string    `json:"-" yaml:"-"`
+	ModTime  time.Time `json:"-" yaml:"-"`
 }
 
 type RuleSet struct {
@@ -65,11 +65,25 @@ func (r QueryParamsRemover) RemoveFrom(value string) string {
 	return query.Encode()
 }
 
+type PathRewriter struct {
+	Regex       string `json:"regex"       yaml:"regex"       validate:"required"` //nolint:tagalign
+	Replacement string `json:"replacement" yaml:"replacement"`
+}
+
+type HeadersRewriter struct {
+	Set    map[string]string `json:"set,omitempty"    yaml:"set,omitempty"`
+	Remove []string          `json:"remove,omitempty" yaml:"remove,omitempty"`
+}
+
 type URLRewriter struct {
-	Scheme              string             `json:"scheme"                 yaml:"scheme"`
-	PathPrefixToCut     PrefixCutter       `json:"strip_path_prefix"      yaml:"strip_path_prefix"`
-	PathPrefixToAdd     PrefixAdder        `json:"add_path_prefix"        yaml:"add_path_prefix"`
-	QueryParamsToRemove QueryParamsRemover `json:"strip_query_parameters" yaml:"strip_query_parameters"`
+	Scheme              string             `json:"scheme"                         yaml:"scheme"`
+	PathPrefixToCut     PrefixCutter       `json:"strip_path_prefix"              yaml:"strip_path_prefix"`
+	PathPrefixToAdd     PrefixAdder        `json:"add_path_prefix"                yaml:"add_path_prefix"`
+	QueryParamsToRemove QueryParamsRemover `json:"strip_query_parameters"         yaml:"strip_query_parameters"`
+	Path                *PathRewriter      `json:"path,omitempty"                 yaml:"path,omitempty"`
+	QueryParamsToAdd    map[string]string  `json:"add_query_parameters,omitempty" yaml:"add_query_parameters,omitempty"`
+	RequestHeaders      *HeadersRewriter   `json:"request_headers,omitempty"      yaml:"request_headers,omitempty"`
+	ResponseHeaders     *HeadersRewriter   `json:"response_headers,omitempty"     yaml:"response_headers,omitempty"`
 }
 
 func (r *URLRewriter) Rewrite(value *url.URL) {
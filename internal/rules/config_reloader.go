@@ -0,0 +1,147 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"sync"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/dadrus/heimdall/internal/config"
+	config2 "github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms"
+	"github.com/dadrus/heimdall/internal/rules/rule"
+	"github.com/dadrus/heimdall/internal/rules/upstream"
+)
+
+// reloadableRuleFactory delegates to the rule factory created from the current heimdall
+// configuration. That way components holding a reference to it, like the admission controller
+// of the kubernetes rule provider, make use of the mechanisms of a reloaded configuration.
+type reloadableRuleFactory struct {
+	f     rule.Factory
+	hf    mechanisms.Factory
+	mutex sync.RWMutex
+}
+
+func newReloadableRuleFactory(
+	hf mechanisms.Factory,
+	conf *config.Configuration,
+	mode config.OperationMode,
+	upstreams *upstream.Registry,
+	meterProvider metric.MeterProvider,
+	logger zerolog.Logger,
+) (*reloadableRuleFactory, error) {
+	factory, err := NewRuleFactory(hf, conf, mode, upstreams, meterProvider, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reloadableRuleFactory{f: factory, hf: hf}, nil
+}
+
+func (f *reloadableRuleFactory) current() rule.Factory {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	return f.f
+}
+
+// set replaces the current rule factory together with the mechanisms factory it has been created
+// from and returns the replaced mechanisms factory.
+func (f *reloadableRuleFactory) set(factory rule.Factory, hf mechanisms.Factory) mechanisms.Factory {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	previous := f.hf
+
+	f.f = factory
+	f.hf = hf
+
+	return previous
+}
+
+func (f *reloadableRuleFactory) CreateRule(version, srcID string, ruleConfig config2.Rule) (rule.Rule, error) {
+	return f.current().CreateRule(version, srcID, ruleConfig)
+}
+
+func (f *reloadableRuleFactory) DefaultRule() rule.Rule { return f.current().DefaultRule() }
+func (f *reloadableRuleFactory) HasDefaultRule() bool   { return f.current().HasDefaultRule() }
+
+// configReloader applies a reloaded heimdall configuration by creating new mechanisms, a new
+// default rule and recreating all loaded rules from them. The existing ones are only replaced
+// if that succeeds. The resources held by the mechanisms, which are not used anymore, are
+// released in both cases.
+type configReloader struct {
+	newMechanisms func(conf *config.Configuration, logger zerolog.Logger) (mechanisms.Factory, error)
+	mode          config.OperationMode
+	upstreams     *upstream.Registry
+	meterProvider metric.MeterProvider
+	factory       *reloadableRuleFactory
+	processor     *ruleSetProcessor
+	logger        zerolog.Logger
+}
+
+func (r *configReloader) OnConfigurationChanged(conf *config.Configuration) error {
+	mechanismsFactory, err := r.newMechanisms(conf, r.logger)
+	if err != nil {
+		return err
+	}
+
+	ruleFactory, err := NewRuleFactory(mechanismsFactory, conf, r.mode, r.upstreams, r.meterProvider, r.logger)
+	if err != nil {
+		r.release(mechanismsFactory)
+
+		return err
+	}
+
+	if err = r.processor.reload(ruleFactory); err != nil {
+		r.release(mechanismsFactory)
+
+		return err
+	}
+
+	r.release(r.factory.set(ruleFactory, mechanismsFactory))
+
+	return nil
+}
+
+func (r *configReloader) release(factory mechanisms.Factory) {
+	if err := factory.Close(); err != nil {
+		r.logger.Warn().Err(err).Msg("Failed to release the resources of the mechanisms")
+	}
+}
+
+func registerConfigReloader(
+	watcher *config.Watcher,
+	mode config.OperationMode,
+	upstreams *upstream.Registry,
+	meterProvider metric.MeterProvider,
+	factory *reloadableRuleFactory,
+	processor *ruleSetProcessor,
+	logger zerolog.Logger,
+) {
+	watcher.Subscribe(&configReloader{
+		newMechanisms: mechanisms.NewFactory,
+		mode:          mode,
+		upstreams:     upstreams,
+		meterProvider: meterProvider,
+		factory:       factory,
+		processor:     processor,
+		logger:        logger,
+	})
+}
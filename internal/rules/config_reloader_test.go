@@ -0,0 +1,130 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	"github.com/dadrus/heimdall/internal/config"
+	config2 "github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/rules/event"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/authenticators"
+	"github.com/dadrus/heimdall/internal/x"
+)
+
+type trackingMechanismsFactory struct {
+	mechanisms.Factory
+
+	open *atomic.Int32
+}
+
+func (f *trackingMechanismsFactory) Close() error {
+	f.open.Add(-1)
+
+	return f.Factory.Close()
+}
+
+func TestConfigReloaderReleasesMechanisms(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	keysFile := filepath.Join(t.TempDir(), "keys.yaml")
+	require.NoError(t, os.WriteFile(keysFile, []byte("[]"), 0o600))
+
+	// the api_key authenticator watches the keys file for changes
+	configWith := func(authenticatorID string) *config.Configuration {
+		return &config.Configuration{Prototypes: &config.MechanismPrototypes{
+			Authenticators: []config.Mechanism{
+				{
+					ID:     authenticatorID,
+					Type:   authenticators.AuthenticatorAPIKey,
+					Config: map[string]any{"keys_file": keysFile},
+				},
+			},
+		}}
+	}
+
+	var open atomic.Int32
+
+	newMechanisms := func(conf *config.Configuration, logger zerolog.Logger) (mechanisms.Factory, error) {
+		factory, err := mechanisms.NewFactory(conf, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		open.Add(1)
+
+		return &trackingMechanismsFactory{Factory: factory, open: &open}, nil
+	}
+
+	initial, err := newMechanisms(configWith("foo"), log.Logger)
+	require.NoError(t, err)
+
+	factory, err := newReloadableRuleFactory(
+		initial, configWith("foo"), config.DecisionMode, nil, noop.NewMeterProvider(), log.Logger)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = factory.hf.Close() })
+
+	processor := newRuleSetProcessor(make(event.RuleSetChangedEventQueue, 10), factory, log.Logger)
+	require.NoError(t, processor.OnCreated(&config2.RuleSet{
+		MetaData: config2.MetaData{Source: "test"},
+		Version:  config2.CurrentRuleSetVersion,
+		Rules: []config2.Rule{
+			{
+				ID:          "foo",
+				RuleMatcher: config2.Matcher{URL: "http://foo.bar", Strategy: "glob"},
+				Execute:     []config.MechanismConfig{{"authenticator": "foo"}},
+				Methods:     []string{"GET"},
+			},
+		},
+	}))
+
+	reloader := &configReloader{
+		newMechanisms: newMechanisms,
+		mode:          config.DecisionMode,
+		meterProvider: noop.NewMeterProvider(),
+		factory:       factory,
+		processor:     processor,
+		logger:        log.Logger,
+	}
+
+	for idx := 0; idx < 6; idx++ {
+		// WHEN
+		// every second reload is rolled back, as the loaded rule references an unknown authenticator
+		err = reloader.OnConfigurationChanged(configWith(x.IfThenElse(idx%2 == 0, "foo", "bar")))
+
+		// THEN
+		if idx%2 == 0 {
+			require.NoError(t, err)
+		} else {
+			require.Error(t, err)
+		}
+
+		assert.Equal(t, int32(1), open.Load())
+	}
+}
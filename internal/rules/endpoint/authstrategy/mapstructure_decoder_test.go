@@ -24,6 +24,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/dadrus/heimdall/internal/rules/endpoint"
+	"github.com/dadrus/heimdall/internal/rules/oauth2/clientcredentials"
 	"github.com/dadrus/heimdall/internal/x/testsupport"
 )
 
@@ -364,6 +365,51 @@ auth:
 				assert.ElementsMatch(t, ccs.Scopes, []string{"foo", "bar"})
 			},
 		},
+		{
+			uc: "client credentials with private_key_jwt auth method",
+			config: []byte(`
+auth:
+  type: oauth2_client_credentials
+  config:
+    client_id: foo
+    token_url: http://foobar.foo
+    auth_method: private_key_jwt
+    signing_key:
+      key_store:
+        path: /foo/bar.pem
+      key_id: baz
+`),
+			assert: func(t *testing.T, err error, as endpoint.AuthenticationStrategy) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.IsType(t, &OAuth2ClientCredentials{}, as)
+				ccs := as.(*OAuth2ClientCredentials) // nolint: forcetypeassert
+				assert.Equal(t, "foo", ccs.ClientID)
+				assert.Empty(t, ccs.ClientSecret)
+				assert.Equal(t, clientcredentials.AuthMethodPrivateKeyJWT, ccs.AuthMethod)
+				require.NotNil(t, ccs.SigningKey)
+				assert.Equal(t, "/foo/bar.pem", ccs.SigningKey.KeyStore.Path)
+				assert.Equal(t, "baz", ccs.SigningKey.KeyID)
+			},
+		},
+		{
+			uc: "client credentials with tls_client_auth auth method without tls property",
+			config: []byte(`
+auth:
+  type: oauth2_client_credentials
+  config:
+    client_id: foo
+    token_url: http://foobar.foo
+    auth_method: tls_client_auth
+`),
+			assert: func(t *testing.T, err error, as endpoint.AuthenticationStrategy) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorContains(t, err, "'tls' is a required field")
+			},
+		},
 		{
 			uc: "client credentials without client_id property",
 			config: []byte(`
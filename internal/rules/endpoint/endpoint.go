@@ -56,12 +56,17 @@ type Endpoint struct {
 	AuthStrategy AuthenticationStrategy `mapstructure:"auth"`
 	Headers      map[string]string      `mapstructure:"headers"`
 	HTTPCache    *HTTPCache             `mapstructure:"http_cache"`
+	// Transport is used instead of the default transport if set. It is not configurable
+	// but set programmatically, e.g. to present a client certificate to the server.
+	Transport http.RoundTripper `mapstructure:"-"`
 }
 
 func (e Endpoint) CreateClient(peerName string) *http.Client {
+	transport := x.IfThenElse(e.Transport != nil, e.Transport, http.DefaultTransport)
+
 	client := &http.Client{
 		Transport: otelhttp.NewTransport(
-			httpx.NewTraceRoundTripper(http.DefaultTransport),
+			httpx.NewTraceRoundTripper(httpx.NewStubRoundTripper(transport)),
 			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
 				return fmt.Sprintf("%s %s %s @%s", r.Proto, r.Method, r.URL.Path, peerName)
 			})),
@@ -27,6 +27,9 @@ const (
 	Create ChangeType = 1 << iota
 	Remove
 	Update
+	Failed
+	// Reload replaces all rules and the default rule at once, e.g. after the heimdall configuration changed.
+	Reload
 )
 
 func (t ChangeType) String() string {
@@ -37,6 +40,10 @@ func (t ChangeType) String() string {
 		return "Remove"
 	case Update:
 		return "Update"
+	case Failed:
+		return "Failed"
+	case Reload:
+		return "Reload"
 	default:
 		return "Unknown"
 	}
@@ -44,7 +51,13 @@ func (t ChangeType) String() string {
 
 type RuleSetChanged struct {
 	Source     string
+	Provider   string
 	Name       string
+	Hash       []byte
 	Rules      []rule.Rule
 	ChangeType ChangeType
+	// DefaultRule is only set for the Reload ChangeType and is nil if no default rule is configured.
+	DefaultRule rule.Rule
+	// Err is only set for the Failed ChangeType and describes why the rule set could not be loaded.
+	Err error
 }
@@ -0,0 +1,217 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authenticators
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tidwall/gjson"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/endpoint"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/authenticators/extractors"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	registerTypeFactory(
+		func(id string, typ string, conf map[string]any, logger zerolog.Logger) (bool, Authenticator, error) {
+			if typ != AuthenticatorAPIKey {
+				return false, nil, nil
+			}
+
+			auth, err := newAPIKeyAuthenticator(id, conf, logger)
+
+			return true, auth, err
+		})
+}
+
+type apiKeyAuthenticator struct {
+	id                   string
+	ads                  extractors.AuthDataExtractStrategy
+	store                apiKeyStore
+	sf                   SubjectFactory
+	allowFallbackOnError bool
+}
+
+func newAPIKeyAuthenticator(id string, rawConfig map[string]any, logger zerolog.Logger) (*apiKeyAuthenticator, error) {
+	type Config struct {
+		KeySource            extractors.CompositeExtractStrategy `mapstructure:"key_source"`
+		KeysFile             string                              `mapstructure:"keys_file"               validate:"required_without=LookupEndpoint,excluded_with=LookupEndpoint"` //nolint:lll
+		LookupEndpoint       *endpoint.Endpoint                  `mapstructure:"lookup_endpoint"`
+		Subject              *SubjectInfo                        `mapstructure:"subject"`
+		CacheTTL             *time.Duration                      `mapstructure:"cache_ttl"`
+		AllowFallbackOnError bool                                `mapstructure:"allow_fallback_on_error"`
+	}
+
+	var conf Config
+	if err := decodeConfig(AuthenticatorAPIKey, rawConfig, &conf); err != nil {
+		return nil, err
+	}
+
+	var (
+		store apiKeyStore
+		err   error
+	)
+
+	if len(conf.KeysFile) != 0 {
+		if conf.CacheTTL != nil {
+			return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+				"cache_ttl can only be used together with lookup_endpoint")
+		}
+
+		store, err = newAPIKeyFileStore(conf.KeysFile, logger)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		store = newAPIKeyEndpointStore(conf.LookupEndpoint,
+			x.IfThenElseExec(conf.CacheTTL != nil,
+				func() time.Duration { return *conf.CacheTTL },
+				func() time.Duration { return 0 }))
+	}
+
+	return &apiKeyAuthenticator{
+		id: id,
+		ads: x.IfThenElseExec(len(conf.KeySource) != 0,
+			func() extractors.AuthDataExtractStrategy { return conf.KeySource },
+			func() extractors.AuthDataExtractStrategy {
+				return extractors.HeaderValueExtractStrategy{Name: "X-API-Key"}
+			}),
+		store: store,
+		sf: x.IfThenElseExec(conf.Subject != nil,
+			func() *SubjectInfo { return conf.Subject },
+			func() *SubjectInfo { return &SubjectInfo{IDFrom: "owner"} }),
+		allowFallbackOnError: conf.AllowFallbackOnError,
+	}, nil
+}
+
+func (a *apiKeyAuthenticator) Execute(ctx heimdall.Context) (*subject.Subject, error) {
+	logger := zerolog.Ctx(ctx.AppContext())
+	logger.Debug().Str("_id", a.id).Msg("Authenticating using api_key authenticator")
+
+	key, err := a.ads.GetAuthData(ctx)
+	if err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrAuthentication, "no API key present").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	info, err := a.store.lookup(ctx.AppContext(), key)
+	if err != nil {
+		if errors.Is(err, errUnknownAPIKey) {
+			return nil, errorchain.
+				NewWithMessage(heimdall.ErrAuthentication, "invalid API key").
+				WithErrorContext(a)
+		}
+
+		// the errors of the lookup are already classified and are only enriched with the context
+		return nil, errorchain.New(err).WithErrorContext(a)
+	}
+
+	if expiresAt := gjson.GetBytes(info, "expires_at"); expiresAt.Exists() {
+		exp, err := time.Parse(time.RFC3339, expiresAt.String())
+		if err != nil {
+			return nil, errorchain.
+				NewWithMessage(heimdall.ErrAuthentication, "API key has an invalid expiry").
+				WithErrorContext(a).
+				CausedBy(err)
+		}
+
+		if !time.Now().Before(exp) {
+			return nil, errorchain.
+				NewWithMessage(heimdall.ErrAuthentication, "API key expired").
+				WithErrorContext(a)
+		}
+	}
+
+	sub, err := a.sf.CreateSubject(info)
+	if err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrInternal, "failed to extract subject information from API key metadata").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	return sub, nil
+}
+
+func (a *apiKeyAuthenticator) WithConfig(config map[string]any) (Authenticator, error) {
+	// this authenticator allows cache ttl and fallback behavior to be redefined on the rule level
+	if len(config) == 0 {
+		return a, nil
+	}
+
+	type Config struct {
+		CacheTTL             *time.Duration `mapstructure:"cache_ttl"`
+		AllowFallbackOnError *bool          `mapstructure:"allow_fallback_on_error"`
+	}
+
+	var conf Config
+	if err := decodeConfig(AuthenticatorAPIKey, config, &conf); err != nil {
+		return nil, err
+	}
+
+	store := a.store
+
+	if conf.CacheTTL != nil {
+		eps, ok := a.store.(*apiKeyEndpointStore)
+		if !ok {
+			return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+				"cache_ttl can only be used together with lookup_endpoint")
+		}
+
+		store = &apiKeyEndpointStore{ep: eps.ep, epHash: eps.epHash, ttl: *conf.CacheTTL}
+	}
+
+	return &apiKeyAuthenticator{
+		id:    a.id,
+		ads:   a.ads,
+		store: store,
+		sf:    a.sf,
+		allowFallbackOnError: x.IfThenElseExec(conf.AllowFallbackOnError != nil,
+			func() bool { return *conf.AllowFallbackOnError },
+			func() bool { return a.allowFallbackOnError }),
+	}, nil
+}
+
+// Close releases the resources held by the store of the authenticator. As the authenticators
+// created from a prototype share its store, it is only called for the prototype.
+func (a *apiKeyAuthenticator) Close() error {
+	if closer, ok := a.store.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+func (a *apiKeyAuthenticator) IsFallbackOnErrorAllowed() bool {
+	return a.allowFallbackOnError
+}
+
+func (a *apiKeyAuthenticator) ID() string {
+	return a.id
+}
@@ -0,0 +1,838 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authenticators
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/cache/memory"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/authenticators/extractors"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/x/filewatcher"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func sha256APIKeyHash(key string) string {
+	digest := sha256.Sum256([]byte(key))
+
+	return "sha256:" + hex.EncodeToString(digest[:])
+}
+
+func sha512APIKeyHash(key string) string {
+	digest := sha512.Sum512([]byte(key))
+
+	return "sha512:" + hex.EncodeToString(digest[:])
+}
+
+func writeAPIKeysFile(t *testing.T, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+
+	return path
+}
+
+func TestCreateAPIKeyAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	validKeysFile := writeAPIKeysFile(t, `
+- hash: `+sha256APIKeyHash("foo")+`
+  owner: alice
+  scopes: [read, write]
+`)
+
+	for _, tc := range []struct {
+		uc     string
+		config []byte
+		assert func(t *testing.T, err error, auth *apiKeyAuthenticator)
+	}{
+		{
+			uc: "with keys file and defaults",
+			config: []byte(`
+keys_file: ` + validKeysFile),
+			assert: func(t *testing.T, err error, auth *apiKeyAuthenticator) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				assert.Equal(t, "api_key_auth", auth.ID())
+				assert.False(t, auth.IsFallbackOnErrorAllowed())
+				assert.Equal(t, extractors.HeaderValueExtractStrategy{Name: "X-API-Key"}, auth.ads)
+				assert.Equal(t, &SubjectInfo{IDFrom: "owner"}, auth.sf)
+
+				store, ok := auth.store.(*apiKeyFileStore)
+				require.True(t, ok)
+				assert.Len(t, store.keys["sha256"], 1)
+			},
+		},
+		{
+			uc: "with lookup endpoint and all other settings",
+			config: []byte(`
+key_source:
+  - header: Authorization
+    scheme: ApiKey
+  - query_parameter: api_key
+lookup_endpoint:
+  url: http://foo.bar
+subject:
+  id: client_id
+cache_ttl: 5m
+allow_fallback_on_error: true
+`),
+			assert: func(t *testing.T, err error, auth *apiKeyAuthenticator) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				assert.True(t, auth.IsFallbackOnErrorAllowed())
+				assert.Equal(t, extractors.CompositeExtractStrategy{
+					&extractors.HeaderValueExtractStrategy{Name: "Authorization", Scheme: "ApiKey"},
+					&extractors.QueryParameterExtractStrategy{Name: "api_key"},
+				}, auth.ads)
+				assert.Equal(t, &SubjectInfo{IDFrom: "client_id"}, auth.sf)
+
+				store, ok := auth.store.(*apiKeyEndpointStore)
+				require.True(t, ok)
+				assert.Equal(t, 5*time.Minute, store.ttl)
+				assert.Equal(t, http.MethodPost, store.ep.Method)
+				assert.Equal(t, "application/json", store.ep.Headers["Accept"])
+				assert.Equal(t, "application/json", store.ep.Headers["Content-Type"])
+			},
+		},
+		{
+			uc:     "without keys file and lookup endpoint",
+			config: []byte(`allow_fallback_on_error: true`),
+			assert: func(t *testing.T, err error, _ *apiKeyAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'keys_file' is a required field")
+			},
+		},
+		{
+			uc: "with keys file and lookup endpoint",
+			config: []byte(`
+keys_file: ` + validKeysFile + `
+lookup_endpoint:
+  url: http://foo.bar
+`),
+			assert: func(t *testing.T, err error, _ *apiKeyAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "keys_file")
+			},
+		},
+		{
+			uc: "with keys file and cache ttl",
+			config: []byte(`
+keys_file: ` + validKeysFile + `
+cache_ttl: 5m
+`),
+			assert: func(t *testing.T, err error, _ *apiKeyAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "cache_ttl")
+			},
+		},
+		{
+			uc:     "with not existing keys file",
+			config: []byte(`keys_file: /does/not/exist.yaml`),
+			assert: func(t *testing.T, err error, _ *apiKeyAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed to read API keys file")
+			},
+		},
+		{
+			uc:     "with malformed keys file",
+			config: []byte(`keys_file: ` + writeAPIKeysFile(t, `foo: bar`)),
+			assert: func(t *testing.T, err error, _ *apiKeyAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed to parse API keys file")
+			},
+		},
+		{
+			uc: "with unsupported hash algorithm in keys file",
+			config: []byte(`keys_file: ` + writeAPIKeysFile(t, `
+- hash: md5:acbd18db4cc2f85cedef654fccc4a4d8
+  owner: alice
+`)),
+			assert: func(t *testing.T, err error, _ *apiKeyAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "unsupported hash algorithm 'md5'")
+			},
+		},
+		{
+			uc: "with hash of wrong length in keys file",
+			config: []byte(`keys_file: ` + writeAPIKeysFile(t, `
+- hash: sha512:`+sha256APIKeyHash("foo")[7:]+`
+  owner: alice
+`)),
+			assert: func(t *testing.T, err error, _ *apiKeyAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "not a valid hex encoded sha512 digest")
+			},
+		},
+		{
+			uc: "with entry without owner in keys file",
+			config: []byte(`keys_file: ` + writeAPIKeysFile(t, `
+- hash: `+sha256APIKeyHash("foo")+`
+  scopes: [read]
+`)),
+			assert: func(t *testing.T, err error, _ *apiKeyAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "has no owner")
+			},
+		},
+		{
+			uc: "with invalid expiry in keys file",
+			config: []byte(`keys_file: ` + writeAPIKeysFile(t, `
+- hash: `+sha256APIKeyHash("foo")+`
+  owner: alice
+  expires_at: tomorrow
+`)),
+			assert: func(t *testing.T, err error, _ *apiKeyAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "invalid expires_at")
+			},
+		},
+		{
+			uc: "with duplicate entries in keys file",
+			config: []byte(`keys_file: ` + writeAPIKeysFile(t, `
+- hash: `+sha256APIKeyHash("foo")+`
+  owner: alice
+- hash: `+sha256APIKeyHash("foo")+`
+  owner: bob
+`)),
+			assert: func(t *testing.T, err error, _ *apiKeyAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "duplicate")
+			},
+		},
+		{
+			uc: "with unexpected config attribute",
+			config: []byte(`
+keys_file: ` + validKeysFile + `
+foo: bar
+`),
+			assert: func(t *testing.T, err error, _ *apiKeyAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			// WHEN
+			auth, err := newAPIKeyAuthenticator("api_key_auth", conf, log.Logger)
+
+			// THEN
+			tc.assert(t, err, auth)
+		})
+	}
+}
+
+func TestCreateAPIKeyAuthenticatorFromPrototype(t *testing.T) {
+	t.Parallel()
+
+	keysFile := writeAPIKeysFile(t, `
+- hash: `+sha256APIKeyHash("foo")+`
+  owner: alice
+`)
+
+	for _, tc := range []struct {
+		uc        string
+		prototype []byte
+		config    []byte
+		assert    func(t *testing.T, err error, prototype *apiKeyAuthenticator, configured *apiKeyAuthenticator)
+	}{
+		{
+			uc:        "no new configuration provided",
+			prototype: []byte(`keys_file: ` + keysFile),
+			assert: func(t *testing.T, err error, prototype *apiKeyAuthenticator, configured *apiKeyAuthenticator) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, prototype, configured)
+			},
+		},
+		{
+			uc:        "fallback on error reconfigured",
+			prototype: []byte(`keys_file: ` + keysFile),
+			config:    []byte(`allow_fallback_on_error: true`),
+			assert: func(t *testing.T, err error, prototype *apiKeyAuthenticator, configured *apiKeyAuthenticator) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.NotEqual(t, prototype, configured)
+				assert.False(t, prototype.IsFallbackOnErrorAllowed())
+				assert.True(t, configured.IsFallbackOnErrorAllowed())
+				assert.Equal(t, prototype.ID(), configured.ID())
+				assert.Equal(t, prototype.ads, configured.ads)
+				assert.Equal(t, prototype.sf, configured.sf)
+				assert.Same(t, prototype.store, configured.store)
+			},
+		},
+		{
+			uc: "cache ttl reconfigured for lookup endpoint",
+			prototype: []byte(`
+lookup_endpoint:
+  url: http://foo.bar
+cache_ttl: 5m
+`),
+			config: []byte(`cache_ttl: 1m`),
+			assert: func(t *testing.T, err error, prototype *apiKeyAuthenticator, configured *apiKeyAuthenticator) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				protoStore, ok := prototype.store.(*apiKeyEndpointStore)
+				require.True(t, ok)
+
+				configuredStore, ok := configured.store.(*apiKeyEndpointStore)
+				require.True(t, ok)
+
+				assert.Equal(t, 5*time.Minute, protoStore.ttl)
+				assert.Equal(t, time.Minute, configuredStore.ttl)
+				assert.Equal(t, protoStore.ep, configuredStore.ep)
+				assert.Equal(t, prototype.IsFallbackOnErrorAllowed(), configured.IsFallbackOnErrorAllowed())
+			},
+		},
+		{
+			uc:        "cache ttl configured for keys file",
+			prototype: []byte(`keys_file: ` + keysFile),
+			config:    []byte(`cache_ttl: 1m`),
+			assert: func(t *testing.T, err error, _ *apiKeyAuthenticator, _ *apiKeyAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "cache_ttl")
+			},
+		},
+		{
+			uc:        "with not allowed attribute",
+			prototype: []byte(`keys_file: ` + keysFile),
+			config:    []byte(`keys_file: /foo/bar.yaml`),
+			assert: func(t *testing.T, err error, _ *apiKeyAuthenticator, _ *apiKeyAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			pc, err := testsupport.DecodeTestConfig(tc.prototype)
+			require.NoError(t, err)
+
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			prototype, err := newAPIKeyAuthenticator("api_key_auth", pc, log.Logger)
+			require.NoError(t, err)
+
+			// WHEN
+			auth, err := prototype.WithConfig(conf)
+
+			// THEN
+			var (
+				akAuth *apiKeyAuthenticator
+				ok     bool
+			)
+
+			if err == nil {
+				akAuth, ok = auth.(*apiKeyAuthenticator)
+				require.True(t, ok)
+			}
+
+			tc.assert(t, err, prototype, akAuth)
+		})
+	}
+}
+
+func TestAPIKeyAuthenticatorExecuteWithKeysFile(t *testing.T) {
+	t.Parallel()
+
+	type HandlerIdentifier interface {
+		ID() string
+	}
+
+	keysFile := writeAPIKeysFile(t, `
+- hash: `+sha256APIKeyHash("alice-key")+`
+  id: key-1
+  owner: alice
+  scopes: [read, write]
+  expires_at: "`+time.Now().Add(time.Hour).UTC().Format(time.RFC3339)+`"
+  attributes:
+    team: payments
+- hash: `+sha512APIKeyHash("bob-key")+`
+  owner: bob
+- hash: `+sha256APIKeyHash("expired-key")+`
+  owner: carol
+  expires_at: "2020-01-01T00:00:00Z"
+- hash: `+sha256APIKeyHash("unquoted-expiry-key")+`
+  owner: dave
+  expires_at: 2099-01-01T00:00:00Z
+- hash: `+sha256APIKeyHash("unquoted-expired-key")+`
+  owner: erin
+  expires_at: 2020-01-01T00:00:00Z
+`)
+
+	for _, tc := range []struct {
+		uc     string
+		config []byte
+		key    string
+		assert func(t *testing.T, err error, sub *subject.Subject)
+	}{
+		{
+			uc: "no API key present",
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				assert.Contains(t, err.Error(), "no API key present")
+				assert.Nil(t, sub)
+
+				var identifier HandlerIdentifier
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "api_key_auth", identifier.ID())
+			},
+		},
+		{
+			uc:  "unknown API key",
+			key: "foo",
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				assert.Contains(t, err.Error(), "invalid API key")
+				assert.Nil(t, sub)
+			},
+		},
+		{
+			uc:  "expired API key",
+			key: "expired-key",
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				assert.Contains(t, err.Error(), "API key expired")
+				assert.Nil(t, sub)
+			},
+		},
+		{
+			uc:  "expired API key with unquoted expiry",
+			key: "unquoted-expired-key",
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				assert.Contains(t, err.Error(), "API key expired")
+				assert.Nil(t, sub)
+			},
+		},
+		{
+			uc:  "valid API key with unquoted expiry",
+			key: "unquoted-expiry-key",
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, sub)
+
+				assert.Equal(t, "dave", sub.ID)
+				assert.Equal(t, "2099-01-01T00:00:00Z", sub.Attributes["expires_at"])
+			},
+		},
+		{
+			uc:  "valid API key with sha256 hash and metadata",
+			key: "alice-key",
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, sub)
+
+				assert.Equal(t, "alice", sub.ID)
+				assert.Equal(t, "key-1", sub.Attributes["id"])
+				assert.Equal(t, []any{"read", "write"}, sub.Attributes["scopes"])
+				assert.Contains(t, sub.Attributes, "expires_at")
+				assert.Equal(t, map[string]any{"team": "payments"}, sub.Attributes["attributes"])
+				assert.NotContains(t, sub.Attributes, "hash")
+			},
+		},
+		{
+			uc:     "valid API key with sha512 hash and custom subject",
+			config: []byte("subject:\n  id: owner\n  attributes: '{name:owner}'\n"),
+			key:    "bob-key",
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, sub)
+
+				assert.Equal(t, "bob", sub.ID)
+				assert.Equal(t, map[string]any{"name": "bob"}, sub.Attributes)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			conf, err := testsupport.DecodeTestConfig(
+				append([]byte("keys_file: "+keysFile+"\n"), tc.config...))
+			require.NoError(t, err)
+
+			auth, err := newAPIKeyAuthenticator("api_key_auth", conf, log.Logger)
+			require.NoError(t, err)
+
+			fnt := mocks.NewRequestFunctionsMock(t)
+			fnt.EXPECT().Header("X-API-Key").Return(tc.key)
+
+			ctx := mocks.NewContextMock(t)
+			ctx.EXPECT().AppContext().Return(context.Background())
+			ctx.EXPECT().Request().Return(&heimdall.Request{RequestFunctions: fnt})
+
+			// WHEN
+			sub, err := auth.Execute(ctx)
+
+			// THEN
+			tc.assert(t, err, sub)
+		})
+	}
+}
+
+func TestAPIKeyAuthenticatorExecuteWithLookupEndpoint(t *testing.T) {
+	t.Parallel()
+
+	type HandlerIdentifier interface {
+		ID() string
+	}
+
+	var (
+		requests     int
+		receivedKey  string
+		responseCode int
+		responseBody []byte
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests++
+
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		assert.Equal(t, "application/json", req.Header.Get("Accept"))
+
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		var payload map[string]string
+		require.NoError(t, json.Unmarshal(body, &payload))
+
+		receivedKey = payload["api_key"]
+
+		if responseBody != nil {
+			rw.Header().Set("Content-Type", "application/json")
+		}
+
+		rw.WriteHeader(responseCode)
+
+		if responseBody != nil {
+			_, err = rw.Write(responseBody)
+			require.NoError(t, err)
+		}
+	}))
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		uc           string
+		config       []byte
+		responseCode int
+		responseBody []byte
+		assert       func(t *testing.T, err error, sub *subject.Subject)
+	}{
+		{
+			uc:           "unknown API key",
+			responseCode: http.StatusNotFound,
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				assert.Contains(t, err.Error(), "invalid API key")
+				assert.Nil(t, sub)
+				assert.Equal(t, 1, requests)
+			},
+		},
+		{
+			uc:           "lookup endpoint responds with an error",
+			responseCode: http.StatusInternalServerError,
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrCommunication)
+				assert.Contains(t, err.Error(), "unexpected response code")
+				assert.Nil(t, sub)
+
+				var identifier HandlerIdentifier
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "api_key_auth", identifier.ID())
+			},
+		},
+		{
+			uc:           "lookup endpoint responds with malformed information",
+			responseCode: http.StatusOK,
+			responseBody: []byte(`foo`),
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				assert.Nil(t, sub)
+
+				var identifier HandlerIdentifier
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "api_key_auth", identifier.ID())
+			},
+		},
+		{
+			uc:           "expired API key",
+			responseCode: http.StatusOK,
+			responseBody: []byte(`{"owner": "alice", "expires_at": "2020-01-01T00:00:00Z"}`),
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				assert.Contains(t, err.Error(), "API key expired")
+				assert.Nil(t, sub)
+			},
+		},
+		{
+			uc:           "valid API key without caching",
+			responseCode: http.StatusOK,
+			responseBody: []byte(`{"owner": "alice", "scopes": ["read"]}`),
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, sub)
+
+				assert.Equal(t, "alice", sub.ID)
+				assert.Equal(t, []any{"read"}, sub.Attributes["scopes"])
+				assert.Equal(t, 2, requests)
+			},
+		},
+		{
+			uc:           "valid API key with caching",
+			config:       []byte(`cache_ttl: 1m`),
+			responseCode: http.StatusOK,
+			responseBody: []byte(`{"owner": "alice"}`),
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, sub)
+
+				assert.Equal(t, "alice", sub.ID)
+				assert.Equal(t, 1, requests)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			requests = 0
+			receivedKey = ""
+			responseCode = tc.responseCode
+			responseBody = tc.responseBody
+
+			conf, err := testsupport.DecodeTestConfig(append([]byte(`
+key_source:
+  - query_parameter: api_key
+lookup_endpoint:
+  url: `+srv.URL+"\n"), tc.config...))
+			require.NoError(t, err)
+
+			auth, err := newAPIKeyAuthenticator("api_key_auth", conf, log.Logger)
+			require.NoError(t, err)
+
+			appCtx := cache.WithContext(context.Background(), memory.New())
+
+			ctx := mocks.NewContextMock(t)
+			ctx.EXPECT().AppContext().Return(appCtx)
+			ctx.EXPECT().Request().Return(&heimdall.Request{URL: &heimdall.URL{URL: url.URL{RawQuery: "api_key=secret"}}})
+
+			// WHEN
+			sub, err := auth.Execute(ctx)
+			if err == nil {
+				// a second request is either served from the cache or sent to the endpoint again
+				_, err = auth.Execute(ctx)
+			}
+
+			// THEN
+			assert.Equal(t, "secret", receivedKey)
+			tc.assert(t, err, sub)
+		})
+	}
+}
+
+func TestAPIKeyFileStoreReload(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	path := writeAPIKeysFile(t, `
+- hash: `+sha256APIKeyHash("foo")+`
+  owner: alice
+`)
+
+	store, err := newAPIKeyFileStore(path, log.Logger)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = store.Close() })
+
+	_, err = store.lookup(context.Background(), "foo")
+	require.NoError(t, err)
+
+	// WHEN
+	require.NoError(t, os.WriteFile(path, []byte(`
+- hash: `+sha256APIKeyHash("bar")+`
+  owner: bob
+`), 0o600))
+
+	// THEN
+	require.Eventually(t, func() bool {
+		_, err := store.lookup(context.Background(), "bar")
+
+		return err == nil
+	}, 10*filewatcher.ChangeDelay, filewatcher.ChangeDelay/5)
+
+	info, err := store.lookup(context.Background(), "bar")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"owner": "bob"}`, string(info))
+
+	_, err = store.lookup(context.Background(), "foo")
+	require.ErrorIs(t, err, errUnknownAPIKey)
+
+	// WHEN
+	require.NoError(t, os.WriteFile(path, []byte(`foo: bar`), 0o600))
+
+	// THEN
+	// the previously loaded keys stay in effect if the file cannot be parsed
+	// and the error is reported by the next lookup only once
+	require.Eventually(t, func() bool {
+		store.mu.RLock()
+		defer store.mu.RUnlock()
+
+		return store.reloadErr != nil
+	}, 10*filewatcher.ChangeDelay, filewatcher.ChangeDelay/5)
+
+	_, err = store.lookup(context.Background(), "bar")
+	require.NoError(t, err)
+
+	store.mu.RLock()
+	require.NoError(t, store.reloadErr)
+	store.mu.RUnlock()
+}
+
+func TestAPIKeyFileStoreClose(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	path := writeAPIKeysFile(t, `
+- hash: `+sha256APIKeyHash("foo")+`
+  owner: alice
+`)
+
+	store, err := newAPIKeyFileStore(path, log.Logger)
+	require.NoError(t, err)
+
+	// WHEN
+	require.NoError(t, store.Close())
+
+	// THEN
+	// closing again is a noop
+	require.NoError(t, store.Close())
+
+	// changes of the file are not picked up anymore, but the loaded keys stay available
+	require.NoError(t, os.WriteFile(path, []byte(`
+- hash: `+sha256APIKeyHash("bar")+`
+  owner: bob
+`), 0o600))
+
+	time.Sleep(3 * filewatcher.ChangeDelay)
+
+	_, err = store.lookup(context.Background(), "foo")
+	require.NoError(t, err)
+
+	_, err = store.lookup(context.Background(), "bar")
+	require.ErrorIs(t, err, errUnknownAPIKey)
+}
@@ -0,0 +1,362 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authenticators
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+	"gopkg.in/yaml.v3"
+
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/endpoint"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/filewatcher"
+	"github.com/dadrus/heimdall/internal/x/stringx"
+)
+
+var (
+	errUnknownAPIKey            = errors.New("unknown API key")
+	errMalformedAPIKeyHash      = errors.New("hash must be a string in the '<algorithm>:<hex encoded digest>' format")
+	errUnsupportedAPIKeyHashAlg = errors.New("unsupported hash algorithm")
+	errInvalidAPIKeyHashDigest  = errors.New("digest is not a valid hex encoded")
+	errMalformedAPIKeyExpiry    = errors.New("expiry must be a timestamp in the RFC3339 format")
+)
+
+// apiKeyStore looks up the metadata of an API key. The metadata is returned in its JSON
+// representation and is used as is to create the subject.
+type apiKeyStore interface {
+	lookup(ctx context.Context, key string) ([]byte, error)
+}
+
+var apiKeyHashAlgorithms = map[string]func() hash.Hash{ //nolint:gochecknoglobals
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// apiKeyFileStore holds the hashes of the API keys read from a YAML or JSON file together
+// with their metadata. The file is watched for changes until the store is closed and the keys
+// are replaced as a whole on reload. If the file cannot be read or parsed, the previously loaded
+// keys stay in effect and the error is logged by the next lookup.
+type apiKeyFileStore struct {
+	path    string
+	watcher *filewatcher.Watcher
+	closed  sync.Once
+
+	mu        sync.RWMutex
+	keys      map[string]map[string][]byte
+	reloadErr error
+}
+
+func newAPIKeyFileStore(path string, logger zerolog.Logger) (*apiKeyFileStore, error) {
+	store := &apiKeyFileStore{path: path}
+
+	if err := store.reload(); err != nil {
+		return nil, err
+	}
+
+	store.watcher = filewatcher.New(path, store.reload, logger)
+	if err := store.watcher.Start(); err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrConfiguration, "failed to watch API keys file").
+			CausedBy(err)
+	}
+
+	return store, nil
+}
+
+// Close stops watching the file. The keys loaded so far stay available for lookups.
+func (s *apiKeyFileStore) Close() error {
+	var err error
+
+	s.closed.Do(func() { err = s.watcher.Stop() })
+
+	return err
+}
+
+func (s *apiKeyFileStore) lookup(ctx context.Context, key string) ([]byte, error) {
+	s.mu.RLock()
+	keys := s.keys
+	reloadErr := s.reloadErr
+	s.mu.RUnlock()
+
+	if reloadErr != nil {
+		s.reportReloadError(ctx)
+	}
+
+	for alg, hashes := range keys {
+		md := apiKeyHashAlgorithms[alg]()
+		md.Write(stringx.ToBytes(key))
+
+		if info, ok := hashes[hex.EncodeToString(md.Sum(nil))]; ok {
+			return info, nil
+		}
+	}
+
+	return nil, errUnknownAPIKey
+}
+
+func (s *apiKeyFileStore) reportReloadError(ctx context.Context) {
+	s.mu.Lock()
+	err := s.reloadErr
+	s.reloadErr = nil
+	s.mu.Unlock()
+
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Str("_file", s.path).
+			Msg("Failed to reload API keys. Keeping the current keys")
+	}
+}
+
+func (s *apiKeyFileStore) reload() error {
+	keys, err := s.load()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		s.reloadErr = err
+
+		return err
+	}
+
+	s.keys = keys
+	s.reloadErr = nil
+
+	return nil
+}
+
+func (s *apiKeyFileStore) load() (map[string]map[string][]byte, error) {
+	contents, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrConfiguration, "failed to read API keys file").
+			CausedBy(err)
+	}
+
+	return parseAPIKeys(contents)
+}
+
+func parseAPIKeys(contents []byte) (map[string]map[string][]byte, error) {
+	var entries []map[string]any
+
+	if err := yaml.Unmarshal(contents, &entries); err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrConfiguration, "failed to parse API keys file").
+			CausedBy(err)
+	}
+
+	keys := make(map[string]map[string][]byte)
+
+	for idx, entry := range entries {
+		alg, digest, err := parseAPIKeyHash(entry["hash"])
+		if err != nil {
+			return nil, errorchain.
+				NewWithMessagef(heimdall.ErrConfiguration, "invalid hash of API key entry %d", idx).
+				CausedBy(err)
+		}
+
+		if owner, ok := entry["owner"].(string); !ok || len(owner) == 0 {
+			return nil, errorchain.
+				NewWithMessagef(heimdall.ErrConfiguration, "API key entry %d has no owner", idx)
+		}
+
+		if exp, present := entry["expires_at"]; present {
+			expiresAt, err := parseAPIKeyExpiry(exp)
+			if err != nil {
+				return nil, errorchain.
+					NewWithMessagef(heimdall.ErrConfiguration, "invalid expires_at of API key entry %d", idx).
+					CausedBy(err)
+			}
+
+			entry["expires_at"] = expiresAt
+		}
+
+		delete(entry, "hash")
+
+		info, err := json.Marshal(entry)
+		if err != nil {
+			return nil, errorchain.
+				NewWithMessagef(heimdall.ErrConfiguration, "invalid metadata of API key entry %d", idx).
+				CausedBy(err)
+		}
+
+		if keys[alg] == nil {
+			keys[alg] = make(map[string][]byte)
+		}
+
+		if _, exists := keys[alg][digest]; exists {
+			return nil, errorchain.
+				NewWithMessagef(heimdall.ErrConfiguration, "API key entry %d is a duplicate", idx)
+		}
+
+		keys[alg][digest] = info
+	}
+
+	return keys, nil
+}
+
+// parseAPIKeyExpiry returns the given expiry in the RFC3339 format. YAML decodes unquoted
+// timestamps into time.Time values, which are accepted as well.
+func parseAPIKeyExpiry(value any) (string, error) {
+	switch exp := value.(type) {
+	case time.Time:
+		return exp.Format(time.RFC3339), nil
+	case string:
+		if _, err := time.Parse(time.RFC3339, exp); err != nil {
+			return "", err
+		}
+
+		return exp, nil
+	default:
+		return "", errMalformedAPIKeyExpiry
+	}
+}
+
+func parseAPIKeyHash(value any) (string, string, error) {
+	str, ok := value.(string)
+	if !ok {
+		return "", "", errMalformedAPIKeyHash
+	}
+
+	alg, digest, found := strings.Cut(str, ":")
+	if !found {
+		return "", "", errMalformedAPIKeyHash
+	}
+
+	newHash, ok := apiKeyHashAlgorithms[alg]
+	if !ok {
+		return "", "", fmt.Errorf("%w '%s'", errUnsupportedAPIKeyHashAlg, alg)
+	}
+
+	digest = strings.ToLower(digest)
+
+	if raw, err := hex.DecodeString(digest); err != nil || len(raw) != newHash().Size() {
+		return "", "", fmt.Errorf("%w %s digest", errInvalidAPIKeyHashDigest, alg)
+	}
+
+	return alg, digest, nil
+}
+
+// apiKeyEndpointStore looks up the metadata of an API key by sending it to a remote endpoint.
+type apiKeyEndpointStore struct {
+	ep     *endpoint.Endpoint
+	epHash []byte
+	ttl    time.Duration
+}
+
+func newAPIKeyEndpointStore(ep *endpoint.Endpoint, ttl time.Duration) *apiKeyEndpointStore {
+	if ep.Headers == nil {
+		ep.Headers = make(map[string]string)
+	}
+
+	if _, ok := ep.Headers["Accept"]; !ok {
+		ep.Headers["Accept"] = "application/json"
+	}
+
+	if _, ok := ep.Headers["Content-Type"]; !ok {
+		ep.Headers["Content-Type"] = "application/json"
+	}
+
+	if len(ep.Method) == 0 {
+		ep.Method = http.MethodPost
+	}
+
+	// the hash is calculated once, as the endpoint does not change and iterating over its
+	// headers does not happen in a stable order
+	return &apiKeyEndpointStore{ep: ep, epHash: ep.Hash(), ttl: ttl}
+}
+
+func (s *apiKeyEndpointStore) lookup(ctx context.Context, key string) ([]byte, error) {
+	cch := cache.Ctx(ctx)
+	logger := zerolog.Ctx(ctx)
+	cacheKey := s.calculateCacheKey(key)
+
+	if s.ttl > 0 {
+		if entry := cch.Get(ctx, cacheKey); entry != nil {
+			if info, ok := entry.([]byte); ok {
+				logger.Debug().Msg("Reusing API key information from cache")
+
+				return info, nil
+			}
+
+			logger.Warn().Msg("Wrong object type from cache")
+			cch.Delete(ctx, cacheKey)
+		}
+	}
+
+	body, err := json.Marshal(map[string]string{"api_key": key})
+	if err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrInternal, "failed to marshal API key lookup request").
+			CausedBy(err)
+	}
+
+	info, err := s.ep.SendRequest(ctx, bytes.NewReader(body), nil, s.readResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.ttl > 0 {
+		cch.Set(ctx, cacheKey, info, s.ttl)
+	}
+
+	return info, nil
+}
+
+func (s *apiKeyEndpointStore) readResponse(resp *http.Response) ([]byte, error) {
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnauthorized ||
+		resp.StatusCode == http.StatusForbidden:
+		return nil, errUnknownAPIKey
+	case resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices:
+		return nil, errorchain.
+			NewWithMessagef(heimdall.ErrCommunication, "unexpected response code: %v", resp.StatusCode)
+	}
+
+	var info map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrInternal, "failed to unmarshal API key information").
+			CausedBy(err)
+	}
+
+	return json.Marshal(info)
+}
+
+func (s *apiKeyEndpointStore) calculateCacheKey(key string) string {
+	digest := sha256.New()
+	digest.Write(stringx.ToBytes("api_key"))
+	digest.Write(s.epHash)
+	digest.Write(stringx.ToBytes(key))
+
+	return hex.EncodeToString(digest.Sum(nil))
+}
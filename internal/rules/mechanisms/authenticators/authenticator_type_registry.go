@@ -20,6 +20,8 @@ import (
 	"errors"
 	"sync"
 
+	"github.com/rs/zerolog"
+
 	"github.com/dadrus/heimdall/internal/x/errorchain"
 )
 
@@ -31,7 +33,9 @@ var (
 	authenticatorTypeFactoriesMu sync.RWMutex               //nolint:gochecknoglobals
 )
 
-type AuthenticatorTypeFactory func(id string, typ string, config map[string]any) (bool, Authenticator, error)
+type AuthenticatorTypeFactory func(
+	id string, typ string, config map[string]any, logger zerolog.Logger,
+) (bool, Authenticator, error)
 
 func registerTypeFactory(factory AuthenticatorTypeFactory) {
 	authenticatorTypeFactoriesMu.Lock()
@@ -44,12 +48,12 @@ func registerTypeFactory(factory AuthenticatorTypeFactory) {
 	authenticatorTypeFactories = append(authenticatorTypeFactories, factory)
 }
 
-func CreatePrototype(id string, typ string, config map[string]any) (Authenticator, error) {
+func CreatePrototype(id string, typ string, config map[string]any, logger zerolog.Logger) (Authenticator, error) {
 	authenticatorTypeFactoriesMu.RLock()
 	defer authenticatorTypeFactoriesMu.RUnlock()
 
 	for _, create := range authenticatorTypeFactories {
-		if ok, at, err := create(id, typ, config); ok {
+		if ok, at, err := create(id, typ, config, logger); ok {
 			return at, err
 		}
 	}
@@ -17,10 +17,14 @@
 package authenticators
 
 const (
-	AuthenticatorUnauthorized        = "unauthorized"
-	AuthenticatorBasicAuth           = "basic_auth"
-	AuthenticatorAnonymous           = "anonymous"
-	AuthenticatorOAuth2Introspection = "oauth2_introspection"
-	AuthenticatorJwt                 = "jwt"
-	AuthenticatorGeneric             = "generic"
+	AuthenticatorUnauthorized          = "unauthorized"
+	AuthenticatorBasicAuth             = "basic_auth"
+	AuthenticatorAnonymous             = "anonymous"
+	AuthenticatorOAuth2Introspection   = "oauth2_introspection"
+	AuthenticatorJwt                   = "jwt"
+	AuthenticatorGeneric               = "generic"
+	AuthenticatorOIDC                  = "oidc"
+	AuthenticatorX509                  = "x509"
+	AuthenticatorHTTPMessageSignatures = "http_message_signatures"
+	AuthenticatorAPIKey                = "api_key"
 )
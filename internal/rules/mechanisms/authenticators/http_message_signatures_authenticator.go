@@ -0,0 +1,498 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authenticators
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/endpoint"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/httpsig"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/stringx"
+)
+
+var errHTTPSigAlgorithmMismatch = errors.New("signature algorithm does not match the algorithm of the key")
+
+const (
+	defaultHTTPMessageSignaturesMaxAge   = 5 * time.Minute
+	defaultHTTPMessageSignaturesLeeway   = 10 * time.Second
+	defaultHTTPMessageSignaturesCacheTTL = 10 * time.Minute
+)
+
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	registerTypeFactory(
+		func(id string, typ string, conf map[string]any, _ zerolog.Logger) (bool, Authenticator, error) {
+			if typ != AuthenticatorHTTPMessageSignatures {
+				return false, nil, nil
+			}
+
+			auth, err := newHTTPMessageSignaturesAuthenticator(id, conf)
+
+			return true, auth, err
+		})
+}
+
+type httpMessageSignaturesAuthenticator struct {
+	id                   string
+	ep                   *endpoint.Endpoint
+	components           []httpsig.Item
+	label                string
+	tag                  string
+	maxAge               time.Duration
+	leeway               time.Duration
+	ttl                  time.Duration
+	sf                   SubjectFactory
+	allowFallbackOnError bool
+}
+
+type signatureInfo struct {
+	KeyID      string   `json:"keyid"`
+	Label      string   `json:"label"`
+	Algorithm  string   `json:"alg,omitempty"`
+	Tag        string   `json:"tag,omitempty"`
+	Nonce      string   `json:"nonce,omitempty"`
+	Created    int64    `json:"created"`
+	Expires    int64    `json:"expires,omitempty"`
+	Components []string `json:"components"`
+}
+
+func newHTTPMessageSignaturesAuthenticator(
+	id string, rawConfig map[string]any,
+) (*httpMessageSignaturesAuthenticator, error) {
+	type Config struct {
+		JWKSEndpoint         *endpoint.Endpoint `mapstructure:"jwks_endpoint"           validate:"required"`
+		RequiredComponents   []string           `mapstructure:"required_components"`
+		Label                string             `mapstructure:"label"`
+		Tag                  string             `mapstructure:"tag"`
+		MaxAge               *time.Duration     `mapstructure:"max_age"`
+		ValidityLeeway       *time.Duration     `mapstructure:"validity_leeway"`
+		Subject              *SubjectInfo       `mapstructure:"subject"`
+		CacheTTL             *time.Duration     `mapstructure:"cache_ttl"`
+		AllowFallbackOnError bool               `mapstructure:"allow_fallback_on_error"`
+	}
+
+	var conf Config
+	if err := decodeConfig(AuthenticatorHTTPMessageSignatures, rawConfig, &conf); err != nil {
+		return nil, err
+	}
+
+	components, err := parseComponentIdentifiers(
+		x.IfThenElse(len(conf.RequiredComponents) != 0,
+			conf.RequiredComponents,
+			[]string{"@method", "@authority", "@path", "@query"}))
+	if err != nil {
+		return nil, err
+	}
+
+	ep := conf.JWKSEndpoint
+	if ep.Headers == nil {
+		ep.Headers = make(map[string]string)
+	}
+
+	if _, ok := ep.Headers["Accept"]; !ok {
+		ep.Headers["Accept"] = "application/json"
+	}
+
+	if len(ep.Method) == 0 {
+		ep.Method = http.MethodGet
+	}
+
+	return &httpMessageSignaturesAuthenticator{
+		id:         id,
+		ep:         ep,
+		components: components,
+		label:      conf.Label,
+		tag:        conf.Tag,
+		maxAge: x.IfThenElseExec(conf.MaxAge != nil,
+			func() time.Duration { return *conf.MaxAge },
+			func() time.Duration { return defaultHTTPMessageSignaturesMaxAge }),
+		leeway: x.IfThenElseExec(conf.ValidityLeeway != nil,
+			func() time.Duration { return *conf.ValidityLeeway },
+			func() time.Duration { return defaultHTTPMessageSignaturesLeeway }),
+		ttl: x.IfThenElseExec(conf.CacheTTL != nil,
+			func() time.Duration { return *conf.CacheTTL },
+			func() time.Duration { return defaultHTTPMessageSignaturesCacheTTL }),
+		sf: x.IfThenElseExec(conf.Subject != nil,
+			func() *SubjectInfo { return conf.Subject },
+			func() *SubjectInfo { return &SubjectInfo{IDFrom: "keyid"} }),
+		allowFallbackOnError: conf.AllowFallbackOnError,
+	}, nil
+}
+
+func parseComponentIdentifiers(identifiers []string) ([]httpsig.Item, error) {
+	components := make([]httpsig.Item, len(identifiers))
+
+	for idx, identifier := range identifiers {
+		component, err := httpsig.ParseComponentIdentifier(identifier)
+		if err != nil {
+			return nil, errorchain.
+				NewWithMessagef(heimdall.ErrConfiguration, "invalid component identifier '%s'", identifier).
+				CausedBy(err)
+		}
+
+		components[idx] = component
+	}
+
+	return components, nil
+}
+
+func (a *httpMessageSignaturesAuthenticator) Execute(ctx heimdall.Context) (*subject.Subject, error) {
+	logger := zerolog.Ctx(ctx.AppContext())
+	logger.Debug().Str("_id", a.id).Msg("Authenticating using http_message_signatures authenticator")
+
+	sig, err := a.selectSignature(ctx.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	if err = a.validateSignatureParameters(sig); err != nil {
+		return nil, err
+	}
+
+	jwk, err := a.getKey(ctx, sig.KeyID())
+	if err != nil {
+		return nil, err
+	}
+
+	alg, err := a.signatureAlgorithm(sig, jwk)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = sig.Verify(ctx.Request(), alg, jwk.Key); err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrAuthentication, "failed to verify HTTP message signature").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	if err = a.verifyContentDigest(ctx.Request(), sig); err != nil {
+		return nil, err
+	}
+
+	created, _ := sig.Created()
+	expires, _ := sig.Expires()
+
+	rawData, err := json.Marshal(signatureInfo{
+		KeyID:      sig.KeyID(),
+		Label:      sig.Label,
+		Algorithm:  string(alg),
+		Tag:        sig.Tag(),
+		Nonce:      sig.Nonce(),
+		Created:    created.Unix(),
+		Expires:    x.IfThenElseExec(expires.IsZero(), func() int64 { return 0 }, expires.Unix),
+		Components: sig.Components(),
+	})
+	if err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrInternal, "failed to marshal signature information").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	sub, err := a.sf.CreateSubject(rawData)
+	if err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrInternal, "failed to extract subject information from signature").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	return sub, nil
+}
+
+func (a *httpMessageSignaturesAuthenticator) WithConfig(rawConfig map[string]any) (Authenticator, error) {
+	// this authenticator allows the expectations regarding the signature, the cache ttl
+	// and the fallback to be redefined on the rule level
+	if len(rawConfig) == 0 {
+		return a, nil
+	}
+
+	type Config struct {
+		RequiredComponents   []string       `mapstructure:"required_components"`
+		Tag                  *string        `mapstructure:"tag"`
+		MaxAge               *time.Duration `mapstructure:"max_age"`
+		CacheTTL             *time.Duration `mapstructure:"cache_ttl"`
+		AllowFallbackOnError *bool          `mapstructure:"allow_fallback_on_error"`
+	}
+
+	var conf Config
+	if err := decodeConfig(AuthenticatorHTTPMessageSignatures, rawConfig, &conf); err != nil {
+		return nil, err
+	}
+
+	components := a.components
+
+	if len(conf.RequiredComponents) != 0 {
+		var err error
+
+		if components, err = parseComponentIdentifiers(conf.RequiredComponents); err != nil {
+			return nil, err
+		}
+	}
+
+	return &httpMessageSignaturesAuthenticator{
+		id:         a.id,
+		ep:         a.ep,
+		components: components,
+		label:      a.label,
+		tag: x.IfThenElseExec(conf.Tag != nil,
+			func() string { return *conf.Tag },
+			func() string { return a.tag }),
+		maxAge: x.IfThenElseExec(conf.MaxAge != nil,
+			func() time.Duration { return *conf.MaxAge },
+			func() time.Duration { return a.maxAge }),
+		leeway: a.leeway,
+		ttl: x.IfThenElseExec(conf.CacheTTL != nil,
+			func() time.Duration { return *conf.CacheTTL },
+			func() time.Duration { return a.ttl }),
+		sf: a.sf,
+		allowFallbackOnError: x.IfThenElseExec(conf.AllowFallbackOnError != nil,
+			func() bool { return *conf.AllowFallbackOnError },
+			func() bool { return a.allowFallbackOnError }),
+	}, nil
+}
+
+func (a *httpMessageSignaturesAuthenticator) IsFallbackOnErrorAllowed() bool {
+	return a.allowFallbackOnError
+}
+
+func (a *httpMessageSignaturesAuthenticator) ID() string {
+	return a.id
+}
+
+func (a *httpMessageSignaturesAuthenticator) selectSignature(req *heimdall.Request) (*httpsig.Signature, error) {
+	sigs, err := httpsig.FromRequest(req)
+	if err != nil {
+		if errors.Is(err, httpsig.ErrNoSignature) {
+			return nil, errorchain.
+				NewWithMessage(heimdall.ErrAuthentication, "no HTTP message signature present").
+				WithErrorContext(a).
+				CausedBy(heimdall.ErrArgument)
+		}
+
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrAuthentication, "failed to parse HTTP message signature").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	for _, sig := range sigs {
+		if (len(a.label) == 0 || sig.Label == a.label) && (len(a.tag) == 0 || sig.Tag() == a.tag) {
+			return sig, nil
+		}
+	}
+
+	return nil, errorchain.
+		NewWithMessage(heimdall.ErrAuthentication, "no HTTP message signature with expected label and tag present").
+		WithErrorContext(a).
+		CausedBy(heimdall.ErrArgument)
+}
+
+func (a *httpMessageSignaturesAuthenticator) validateSignatureParameters(sig *httpsig.Signature) error {
+	for _, component := range a.components {
+		if !sig.Covers(component) {
+			return errorchain.
+				NewWithMessagef(heimdall.ErrAuthentication,
+					"HTTP message signature does not cover the required component %s", component).
+				WithErrorContext(a)
+		}
+	}
+
+	if len(sig.KeyID()) == 0 {
+		return errorchain.
+			NewWithMessage(heimdall.ErrAuthentication, "HTTP message signature does not reference a key").
+			WithErrorContext(a)
+	}
+
+	now := time.Now()
+
+	created, present := sig.Created()
+	if !present {
+		return errorchain.
+			NewWithMessage(heimdall.ErrAuthentication, "HTTP message signature has no creation time").
+			WithErrorContext(a)
+	}
+
+	if created.After(now.Add(a.leeway)) {
+		return errorchain.
+			NewWithMessage(heimdall.ErrAuthentication, "HTTP message signature has been created in the future").
+			WithErrorContext(a)
+	}
+
+	if now.Sub(created) > a.maxAge+a.leeway {
+		return errorchain.
+			NewWithMessage(heimdall.ErrAuthentication, "HTTP message signature is too old").
+			WithErrorContext(a)
+	}
+
+	if expires, present := sig.Expires(); present && now.After(expires.Add(a.leeway)) {
+		return errorchain.
+			NewWithMessage(heimdall.ErrAuthentication, "HTTP message signature is expired").
+			WithErrorContext(a)
+	}
+
+	return nil
+}
+
+func (a *httpMessageSignaturesAuthenticator) signatureAlgorithm(
+	sig *httpsig.Signature, jwk *jose.JSONWebKey,
+) (httpsig.Algorithm, error) {
+	var (
+		alg httpsig.Algorithm
+		err error
+	)
+
+	_, isRSAKey := jwk.Key.(*rsa.PublicKey)
+
+	// the alg parameter takes precedence. Otherwise, the algorithm is derived from the key,
+	// which is ambiguous for RSA keys without an algorithm
+	switch {
+	case len(sig.Algorithm()) != 0:
+		alg, err = httpsig.ParseAlgorithm(sig.Algorithm())
+	case len(jwk.Algorithm) != 0:
+		alg, err = httpsig.AlgorithmFromJOSE(jwk.Algorithm)
+	case isRSAKey:
+		err = fmt.Errorf("%w: neither signature nor RSA key define the algorithm", httpsig.ErrUnsupportedAlgorithm)
+	default:
+		alg, err = httpsig.AlgorithmForKey(jwk.Key)
+	}
+
+	// a key restricted to a specific algorithm must not be used with any other one
+	if err == nil && len(sig.Algorithm()) != 0 && len(jwk.Algorithm) != 0 {
+		var keyAlg httpsig.Algorithm
+
+		if keyAlg, err = httpsig.AlgorithmFromJOSE(jwk.Algorithm); err == nil && keyAlg != alg {
+			err = fmt.Errorf("%w: signature uses %s, key is restricted to %s",
+				errHTTPSigAlgorithmMismatch, alg, jwk.Algorithm)
+		}
+	}
+
+	if err == nil && !alg.SupportsKey(jwk.Key) {
+		err = fmt.Errorf("%w: %s", httpsig.ErrUnsupportedKey, alg)
+	}
+
+	if err != nil {
+		return "", errorchain.
+			NewWithMessagef(heimdall.ErrAuthentication,
+				"cannot determine signature algorithm to use with key %s", sig.KeyID()).
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	return alg, nil
+}
+
+// verifyContentDigest verifies the Content-Digest header against the body of the request if it is
+// covered by the signature. Otherwise, the signature would protect the digest, but not the body.
+func (a *httpMessageSignaturesAuthenticator) verifyContentDigest(req *heimdall.Request, sig *httpsig.Signature) error {
+	for _, component := range sig.Input.Items {
+		if name, _ := component.Value.(string); name != "content-digest" {
+			continue
+		}
+
+		if err := httpsig.VerifyContentDigest(req.Header(httpsig.HeaderContentDigest), req.RawBody()); err != nil {
+			return errorchain.
+				NewWithMessage(heimdall.ErrAuthentication, "failed to verify the content digest").
+				WithErrorContext(a).
+				CausedBy(err)
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+func (a *httpMessageSignaturesAuthenticator) getKey(ctx heimdall.Context, keyID string) (*jose.JSONWebKey, error) {
+	cch := cache.Ctx(ctx.AppContext())
+	logger := zerolog.Ctx(ctx.AppContext())
+	cacheKey := a.calculateCacheKey(keyID)
+
+	if a.ttl > 0 {
+		if entry := cch.Get(ctx.AppContext(), cacheKey); entry != nil {
+			if jwk, ok := entry.(*jose.JSONWebKey); ok {
+				logger.Debug().Msg("Reusing JWK from cache")
+
+				return jwk, nil
+			}
+
+			logger.Warn().Msg("Wrong object type from cache")
+			cch.Delete(ctx.AppContext(), cacheKey)
+		}
+	}
+
+	logger.Debug().Msg("Retrieving JWKS from configured endpoint")
+
+	rawData, err := a.ep.SendRequest(ctx.AppContext(), nil, nil)
+	if err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrCommunication, "failed to retrieve JWKS").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	var jwks jose.JSONWebKeySet
+	if err = json.Unmarshal(rawData, &jwks); err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrInternal, "failed to unmarshal received jwks").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	keys := jwks.Key(keyID)
+	if len(keys) != 1 || keys[0].Use == "enc" {
+		return nil, errorchain.
+			NewWithMessagef(heimdall.ErrAuthentication,
+				"no (unique) signature key found for the keyid='%s' referenced in the signature", keyID).
+			WithErrorContext(a)
+	}
+
+	jwk := &keys[0]
+
+	if a.ttl > 0 {
+		cch.Set(ctx.AppContext(), cacheKey, jwk, a.ttl)
+	}
+
+	return jwk, nil
+}
+
+func (a *httpMessageSignaturesAuthenticator) calculateCacheKey(keyID string) string {
+	digest := sha256.New()
+	digest.Write(stringx.ToBytes("http_message_signatures"))
+	digest.Write(a.ep.Hash())
+	digest.Write(stringx.ToBytes(keyID))
+
+	return hex.EncodeToString(digest.Sum(nil))
+}
@@ -0,0 +1,843 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authenticators
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/cache/memory"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/httpsig"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestCreateHTTPMessageSignaturesAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc     string
+		id     string
+		config []byte
+		assert func(t *testing.T, err error, auth *httpMessageSignaturesAuthenticator)
+	}{
+		{
+			uc: "without jwks endpoint",
+			config: []byte(`
+max_age: 1m
+`),
+			assert: func(t *testing.T, err error, _ *httpMessageSignaturesAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'jwks_endpoint' is a required field")
+			},
+		},
+		{
+			uc: "with unsupported properties",
+			config: []byte(`
+jwks_endpoint:
+  url: http://test.com
+foo: bar
+`),
+			assert: func(t *testing.T, err error, _ *httpMessageSignaturesAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed decoding")
+			},
+		},
+		{
+			uc: "with malformed component identifier",
+			config: []byte(`
+jwks_endpoint:
+  url: http://test.com
+required_components:
+  - "@query-param;name="
+`),
+			assert: func(t *testing.T, err error, _ *httpMessageSignaturesAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "invalid component identifier")
+			},
+		},
+		{
+			uc: "with minimal configuration",
+			id: "auth1",
+			config: []byte(`
+jwks_endpoint:
+  url: http://test.com
+`),
+			assert: func(t *testing.T, err error, auth *httpMessageSignaturesAuthenticator) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "auth1", auth.ID())
+				assert.Equal(t, "http://test.com", auth.ep.URL)
+				assert.Equal(t, http.MethodGet, auth.ep.Method)
+				assert.Equal(t, "application/json", auth.ep.Headers["Accept"])
+				assert.Equal(t, []httpsig.Item{
+					{Value: "@method"}, {Value: "@authority"}, {Value: "@path"}, {Value: "@query"},
+				}, auth.components)
+				assert.Empty(t, auth.label)
+				assert.Empty(t, auth.tag)
+				assert.Equal(t, defaultHTTPMessageSignaturesMaxAge, auth.maxAge)
+				assert.Equal(t, defaultHTTPMessageSignaturesLeeway, auth.leeway)
+				assert.Equal(t, defaultHTTPMessageSignaturesCacheTTL, auth.ttl)
+				assert.Equal(t, &SubjectInfo{IDFrom: "keyid"}, auth.sf)
+				assert.False(t, auth.IsFallbackOnErrorAllowed())
+			},
+		},
+		{
+			uc: "with full configuration",
+			id: "auth1",
+			config: []byte(`
+jwks_endpoint:
+  url: http://test.com
+  method: POST
+required_components:
+  - "@method"
+  - "@query-param;name=\"foo\""
+  - Content-Digest
+label: sig1
+tag: my-app
+max_age: 1m
+validity_leeway: 1s
+cache_ttl: 0s
+subject:
+  id: label
+allow_fallback_on_error: true
+`),
+			assert: func(t *testing.T, err error, auth *httpMessageSignaturesAuthenticator) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, http.MethodPost, auth.ep.Method)
+				assert.Equal(t, []httpsig.Item{
+					{Value: "@method"},
+					{Value: "@query-param", Params: httpsig.Params{{Name: "name", Value: "foo"}}},
+					{Value: "content-digest"},
+				}, auth.components)
+				assert.Equal(t, "sig1", auth.label)
+				assert.Equal(t, "my-app", auth.tag)
+				assert.Equal(t, 1*time.Minute, auth.maxAge)
+				assert.Equal(t, 1*time.Second, auth.leeway)
+				assert.Equal(t, time.Duration(0), auth.ttl)
+				assert.Equal(t, &SubjectInfo{IDFrom: "label"}, auth.sf)
+				assert.True(t, auth.IsFallbackOnErrorAllowed())
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			// WHEN
+			auth, err := newHTTPMessageSignaturesAuthenticator(tc.id, conf)
+
+			// THEN
+			tc.assert(t, err, auth)
+		})
+	}
+}
+
+func TestCreateHTTPMessageSignaturesAuthenticatorFromPrototype(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc     string
+		config []byte
+		assert func(t *testing.T, err error, prototype, configured *httpMessageSignaturesAuthenticator)
+	}{
+		{
+			uc: "without new configuration",
+			assert: func(t *testing.T, err error, prototype, configured *httpMessageSignaturesAuthenticator) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, prototype, configured)
+			},
+		},
+		{
+			uc: "with expectations, cache ttl and fallback reconfigured",
+			config: []byte(`
+required_components: ["@method", "@target-uri"]
+tag: other-app
+max_age: 30s
+cache_ttl: 1m
+allow_fallback_on_error: true
+`),
+			assert: func(t *testing.T, err error, prototype, configured *httpMessageSignaturesAuthenticator) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.NotEqual(t, prototype, configured)
+				assert.Equal(t, prototype.ID(), configured.ID())
+				assert.Equal(t, prototype.ep, configured.ep)
+				assert.Equal(t, prototype.label, configured.label)
+				assert.Equal(t, prototype.leeway, configured.leeway)
+				assert.Equal(t, prototype.sf, configured.sf)
+				assert.Equal(t, []httpsig.Item{{Value: "@method"}, {Value: "@target-uri"}}, configured.components)
+				assert.Equal(t, "my-app", prototype.tag)
+				assert.Equal(t, "other-app", configured.tag)
+				assert.Equal(t, 30*time.Second, configured.maxAge)
+				assert.Equal(t, 1*time.Minute, configured.ttl)
+				assert.False(t, prototype.IsFallbackOnErrorAllowed())
+				assert.True(t, configured.IsFallbackOnErrorAllowed())
+			},
+		},
+		{
+			uc: "with malformed component identifier",
+			config: []byte(`
+required_components: ["@query-param;name="]
+`),
+			assert: func(t *testing.T, err error, _, _ *httpMessageSignaturesAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "invalid component identifier")
+			},
+		},
+		{
+			uc: "with jwks endpoint reconfigured",
+			config: []byte(`
+jwks_endpoint:
+  url: http://foo.bar
+`),
+			assert: func(t *testing.T, err error, _, _ *httpMessageSignaturesAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed decoding")
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			pc, err := testsupport.DecodeTestConfig([]byte(`
+jwks_endpoint:
+  url: http://test.com
+tag: my-app
+`))
+			require.NoError(t, err)
+
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			prototype, err := newHTTPMessageSignaturesAuthenticator("auth1", pc)
+			require.NoError(t, err)
+
+			// WHEN
+			auth, err := prototype.WithConfig(conf)
+
+			// THEN
+			var (
+				hma *httpMessageSignaturesAuthenticator
+				ok  bool
+			)
+
+			if err == nil {
+				hma, ok = auth.(*httpMessageSignaturesAuthenticator)
+				require.True(t, ok)
+			}
+
+			tc.assert(t, err, prototype, hma)
+		})
+	}
+}
+
+func TestHTTPMessageSignaturesAuthenticatorExecute(t *testing.T) {
+	t.Parallel()
+
+	type HandlerIdentifier interface {
+		ID() string
+	}
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwks, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+		{KeyID: "ed", Key: edKey.Public(), Use: "sig"},
+		{KeyID: "ec", Key: ecKey.Public(), Algorithm: "ES256", Use: "sig"},
+		{KeyID: "rsa", Key: rsaKey.Public(), Use: "sig"},
+		{KeyID: "rsa-ps512", Key: rsaKey.Public(), Algorithm: "PS512", Use: "sig"},
+		{KeyID: "enc", Key: ecKey.Public(), Use: "enc"},
+	}})
+	require.NoError(t, err)
+
+	var (
+		jwksRequests int
+		jwksStatus   int
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		jwksRequests++
+
+		if jwksStatus != http.StatusOK {
+			rw.WriteHeader(jwksStatus)
+
+			return
+		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		_, err := rw.Write(jwks)
+		require.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	reqURL, err := url.Parse("https://example.com/foo?bar=baz")
+	require.NoError(t, err)
+
+	components := []httpsig.Item{{Value: "@method"}, {Value: "@authority"}, {Value: "@path"}, {Value: "@query"}}
+
+	body := []byte(`{"foo": "bar"}`)
+	bodyDigest := sha256.Sum256(body)
+	contentDigest := "sha-256=:" + base64.StdEncoding.EncodeToString(bodyDigest[:]) + ":"
+
+	sign := func(t *testing.T, label string, comps []httpsig.Item, params httpsig.Params,
+		alg httpsig.Algorithm, key crypto.Signer,
+	) map[string]string {
+		t.Helper()
+
+		fnt := mocks.NewRequestFunctionsMock(t)
+		fnt.EXPECT().Header(mock.Anything).RunAndReturn(func(name string) string {
+			return x.IfThenElse(http.CanonicalHeaderKey(name) == httpsig.HeaderContentDigest, contentDigest, "")
+		}).Maybe()
+		fnt.EXPECT().HeaderValues(mock.Anything).RunAndReturn(func(name string) []string {
+			return x.IfThenElse(http.CanonicalHeaderKey(name) == httpsig.HeaderContentDigest,
+				[]string{contentDigest}, nil)
+		}).Maybe()
+
+		req := &heimdall.Request{RequestFunctions: fnt, Method: http.MethodGet, URL: &heimdall.URL{URL: *reqURL}}
+
+		sig, err := httpsig.Sign(req, label, comps, params, alg, key)
+		require.NoError(t, err)
+
+		return map[string]string{
+			"Signature-Input":           sig.SignatureInputField(),
+			"Signature":                 sig.SignatureField(),
+			httpsig.HeaderContentDigest: contentDigest,
+		}
+	}
+
+	now := time.Now().Unix()
+
+	for _, tc := range []struct {
+		uc         string
+		config     []byte
+		jwksStatus int
+		url        string
+		body       []byte
+		headers    func(t *testing.T) map[string]string
+		assert     func(t *testing.T, err error, sub *subject.Subject)
+	}{
+		{
+			uc:      "no signature present",
+			headers: func(_ *testing.T) map[string]string { return nil },
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				assert.Contains(t, err.Error(), "no HTTP message signature present")
+
+				var identifier HandlerIdentifier
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "hms_auth", identifier.ID())
+
+				assert.Nil(t, sub)
+			},
+		},
+		{
+			uc: "malformed signature",
+			headers: func(_ *testing.T) map[string]string {
+				return map[string]string{"Signature-Input": `sig=("@method"`, "Signature": "sig=:AA==:"}
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.NotErrorIs(t, err, heimdall.ErrArgument)
+				require.ErrorIs(t, err, httpsig.ErrMalformedSignature)
+				assert.Contains(t, err.Error(), "failed to parse HTTP message signature")
+			},
+		},
+		{
+			uc:     "no signature with expected label",
+			config: []byte(`label: other`),
+			headers: func(t *testing.T) map[string]string {
+				t.Helper()
+
+				return sign(t, "sig", components, httpsig.Params{
+					{Name: "created", Value: now}, {Name: "keyid", Value: "ed"},
+				}, httpsig.AlgorithmED25519, edKey)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				assert.Contains(t, err.Error(), "no HTTP message signature with expected label and tag present")
+			},
+		},
+		{
+			uc: "required component not covered",
+			headers: func(t *testing.T) map[string]string {
+				t.Helper()
+
+				return sign(t, "sig", components[:2], httpsig.Params{
+					{Name: "created", Value: now}, {Name: "keyid", Value: "ed"},
+				}, httpsig.AlgorithmED25519, edKey)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				assert.Contains(t, err.Error(), `does not cover the required component "@path"`)
+			},
+		},
+		{
+			uc: "no key referenced",
+			headers: func(t *testing.T) map[string]string {
+				t.Helper()
+
+				return sign(t, "sig", components, httpsig.Params{{Name: "created", Value: now}},
+					httpsig.AlgorithmED25519, edKey)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				assert.Contains(t, err.Error(), "does not reference a key")
+			},
+		},
+		{
+			uc: "no creation time",
+			headers: func(t *testing.T) map[string]string {
+				t.Helper()
+
+				return sign(t, "sig", components, httpsig.Params{{Name: "keyid", Value: "ed"}},
+					httpsig.AlgorithmED25519, edKey)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				assert.Contains(t, err.Error(), "has no creation time")
+			},
+		},
+		{
+			uc: "created in the future",
+			headers: func(t *testing.T) map[string]string {
+				t.Helper()
+
+				return sign(t, "sig", components, httpsig.Params{
+					{Name: "created", Value: now + 60}, {Name: "keyid", Value: "ed"},
+				}, httpsig.AlgorithmED25519, edKey)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				assert.Contains(t, err.Error(), "has been created in the future")
+			},
+		},
+		{
+			uc:     "signature too old",
+			config: []byte(`max_age: 1m`),
+			headers: func(t *testing.T) map[string]string {
+				t.Helper()
+
+				return sign(t, "sig", components, httpsig.Params{
+					{Name: "created", Value: now - 120}, {Name: "keyid", Value: "ed"},
+				}, httpsig.AlgorithmED25519, edKey)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				assert.Contains(t, err.Error(), "is too old")
+			},
+		},
+		{
+			uc: "signature expired",
+			headers: func(t *testing.T) map[string]string {
+				t.Helper()
+
+				return sign(t, "sig", components, httpsig.Params{
+					{Name: "created", Value: now - 60}, {Name: "expires", Value: now - 30}, {Name: "keyid", Value: "ed"},
+				}, httpsig.AlgorithmED25519, edKey)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				assert.Contains(t, err.Error(), "is expired")
+			},
+		},
+		{
+			uc:         "jwks endpoint not available",
+			jwksStatus: http.StatusInternalServerError,
+			headers: func(t *testing.T) map[string]string {
+				t.Helper()
+
+				return sign(t, "sig", components, httpsig.Params{
+					{Name: "created", Value: now}, {Name: "keyid", Value: "ed"},
+				}, httpsig.AlgorithmED25519, edKey)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrCommunication)
+				assert.Contains(t, err.Error(), "failed to retrieve JWKS")
+			},
+		},
+		{
+			uc: "unknown key",
+			headers: func(t *testing.T) map[string]string {
+				t.Helper()
+
+				return sign(t, "sig", components, httpsig.Params{
+					{Name: "created", Value: now}, {Name: "keyid", Value: "foo"},
+				}, httpsig.AlgorithmED25519, edKey)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				assert.Contains(t, err.Error(), "no (unique) signature key found for the keyid='foo'")
+			},
+		},
+		{
+			uc: "key not intended for signatures",
+			headers: func(t *testing.T) map[string]string {
+				t.Helper()
+
+				return sign(t, "sig", components, httpsig.Params{
+					{Name: "created", Value: now}, {Name: "keyid", Value: "enc"},
+				}, httpsig.AlgorithmECDSAP256SHA256, ecKey)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				assert.Contains(t, err.Error(), "no (unique) signature key found for the keyid='enc'")
+			},
+		},
+		{
+			uc: "algorithm cannot be derived from rsa key",
+			headers: func(t *testing.T) map[string]string {
+				t.Helper()
+
+				return sign(t, "sig", components, httpsig.Params{
+					{Name: "created", Value: now}, {Name: "keyid", Value: "rsa"},
+				}, httpsig.AlgorithmRSAV15SHA256, rsaKey)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				assert.Contains(t, err.Error(), "cannot determine signature algorithm")
+			},
+		},
+		{
+			uc: "algorithm does not match the key",
+			headers: func(t *testing.T) map[string]string {
+				t.Helper()
+
+				return sign(t, "sig", components, httpsig.Params{
+					{Name: "created", Value: now}, {Name: "keyid", Value: "ed"},
+					{Name: "alg", Value: string(httpsig.AlgorithmECDSAP256SHA256)},
+				}, httpsig.AlgorithmECDSAP256SHA256, ecKey)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorIs(t, err, httpsig.ErrUnsupportedKey)
+				assert.Contains(t, err.Error(), "cannot determine signature algorithm")
+			},
+		},
+		{
+			uc: "algorithm does not match the algorithm of the jwk",
+			headers: func(t *testing.T) map[string]string {
+				t.Helper()
+
+				return sign(t, "sig", components, httpsig.Params{
+					{Name: "created", Value: now}, {Name: "keyid", Value: "rsa-ps512"},
+					{Name: "alg", Value: string(httpsig.AlgorithmRSAV15SHA256)},
+				}, httpsig.AlgorithmRSAV15SHA256, rsaKey)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorIs(t, err, errHTTPSigAlgorithmMismatch)
+				assert.Contains(t, err.Error(), "restricted to PS512")
+			},
+		},
+		{
+			uc:   "content digest does not match the body",
+			body: []byte(`{"foo": "baz"}`),
+			headers: func(t *testing.T) map[string]string {
+				t.Helper()
+
+				return sign(t, "sig", append(slices.Clone(components), httpsig.Item{Value: "content-digest"}),
+					httpsig.Params{{Name: "created", Value: now}, {Name: "keyid", Value: "ed"}},
+					httpsig.AlgorithmED25519, edKey)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorIs(t, err, httpsig.ErrContentDigestMismatch)
+				assert.Contains(t, err.Error(), "failed to verify the content digest")
+			},
+		},
+		{
+			uc: "valid signature covering the content digest",
+			headers: func(t *testing.T) map[string]string {
+				t.Helper()
+
+				return sign(t, "sig", append(slices.Clone(components), httpsig.Item{Value: "content-digest"}),
+					httpsig.Params{{Name: "created", Value: now}, {Name: "keyid", Value: "ed"}},
+					httpsig.AlgorithmED25519, edKey)
+			},
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, sub)
+				assert.Equal(t, []any{`"@method"`, `"@authority"`, `"@path"`, `"@query"`, `"content-digest"`},
+					sub.Attributes["components"])
+			},
+		},
+		{
+			uc: "invalid signature",
+			headers: func(t *testing.T) map[string]string {
+				t.Helper()
+
+				return sign(t, "sig", components, httpsig.Params{
+					{Name: "created", Value: now}, {Name: "keyid", Value: "ec"},
+				}, httpsig.AlgorithmED25519, edKey)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorIs(t, err, httpsig.ErrInvalidSignature)
+				assert.Contains(t, err.Error(), "failed to verify HTTP message signature")
+			},
+		},
+		{
+			uc:  "tampered query",
+			url: "https://example.com/foo?bar=qux",
+			headers: func(t *testing.T) map[string]string {
+				t.Helper()
+
+				return sign(t, "sig", components, httpsig.Params{
+					{Name: "created", Value: now}, {Name: "keyid", Value: "ed"},
+				}, httpsig.AlgorithmED25519, edKey)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorIs(t, err, httpsig.ErrInvalidSignature)
+				assert.Contains(t, err.Error(), "failed to verify HTTP message signature")
+			},
+		},
+		{
+			uc:     "valid signature with algorithm derived from the key",
+			config: []byte(`tag: my-app`),
+			headers: func(t *testing.T) map[string]string {
+				t.Helper()
+
+				other := sign(t, "other", components, httpsig.Params{
+					{Name: "created", Value: now}, {Name: "keyid", Value: "ec"},
+				}, httpsig.AlgorithmECDSAP256SHA256, ecKey)
+				headers := sign(t, "sig", components, httpsig.Params{
+					{Name: "created", Value: now}, {Name: "expires", Value: now + 60},
+					{Name: "keyid", Value: "ed"}, {Name: "tag", Value: "my-app"},
+				}, httpsig.AlgorithmED25519, edKey)
+
+				headers["Signature-Input"] = other["Signature-Input"] + ", " + headers["Signature-Input"]
+				headers["Signature"] = other["Signature"] + ", " + headers["Signature"]
+
+				return headers
+			},
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, sub)
+				assert.Equal(t, "ed", sub.ID)
+				assert.Equal(t, "sig", sub.Attributes["label"])
+				assert.Equal(t, "ed25519", sub.Attributes["alg"])
+				assert.Equal(t, "my-app", sub.Attributes["tag"])
+				assert.InDelta(t, float64(now), sub.Attributes["created"], 0)
+				assert.InDelta(t, float64(now+60), sub.Attributes["expires"], 0)
+				assert.Equal(t, []any{`"@method"`, `"@authority"`, `"@path"`, `"@query"`},
+					sub.Attributes["components"])
+			},
+		},
+		{
+			uc: "valid signature with algorithm from the jwk and parameter",
+			headers: func(t *testing.T) map[string]string {
+				t.Helper()
+
+				return sign(t, "sig", components, httpsig.Params{
+					{Name: "created", Value: now}, {Name: "keyid", Value: "ec"},
+				}, httpsig.AlgorithmECDSAP256SHA256, ecKey)
+			},
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, sub)
+				assert.Equal(t, "ec", sub.ID)
+				assert.Equal(t, "ecdsa-p256-sha256", sub.Attributes["alg"])
+			},
+		},
+		{
+			uc: "valid signature with algorithm from the parameter",
+			headers: func(t *testing.T) map[string]string {
+				t.Helper()
+
+				return sign(t, "sig", components, httpsig.Params{
+					{Name: "created", Value: now}, {Name: "keyid", Value: "rsa"},
+					{Name: "alg", Value: string(httpsig.AlgorithmRSAPSSSHA512)},
+				}, httpsig.AlgorithmRSAPSSSHA512, rsaKey)
+			},
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, sub)
+				assert.Equal(t, "rsa", sub.ID)
+				assert.Equal(t, "rsa-pss-sha512", sub.Attributes["alg"])
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			jwksRequests = 0
+			jwksStatus = x.IfThenElse(tc.jwksStatus != 0, tc.jwksStatus, http.StatusOK)
+
+			conf, err := testsupport.DecodeTestConfig(
+				append([]byte("jwks_endpoint:\n  url: "+srv.URL+"\n"), tc.config...))
+			require.NoError(t, err)
+
+			auth, err := newHTTPMessageSignaturesAuthenticator("hms_auth", conf)
+			require.NoError(t, err)
+
+			headers := tc.headers(t)
+
+			fnt := mocks.NewRequestFunctionsMock(t)
+			fnt.EXPECT().Header(mock.Anything).RunAndReturn(func(name string) string {
+				return headers[http.CanonicalHeaderKey(name)]
+			})
+			fnt.EXPECT().HeaderValues(mock.Anything).RunAndReturn(func(name string) []string {
+				if value, ok := headers[http.CanonicalHeaderKey(name)]; ok {
+					return []string{value}
+				}
+
+				return nil
+			}).Maybe()
+
+			requestURL := reqURL
+			if len(tc.url) != 0 {
+				requestURL, err = url.Parse(tc.url)
+				require.NoError(t, err)
+			}
+			fnt.EXPECT().RawBody().Return(x.IfThenElse(tc.body != nil, tc.body, body)).Maybe()
+
+			appCtx := cache.WithContext(context.Background(), memory.New())
+
+			ctx := mocks.NewContextMock(t)
+			ctx.EXPECT().AppContext().Return(appCtx)
+			ctx.EXPECT().Request().Return(
+				&heimdall.Request{RequestFunctions: fnt, Method: http.MethodGet, URL: &heimdall.URL{URL: *requestURL}})
+
+			// WHEN
+			sub, err := auth.Execute(ctx)
+
+			// THEN
+			tc.assert(t, err, sub)
+
+			if err == nil {
+				// the key is taken from the cache on subsequent requests
+				_, err = auth.Execute(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, 1, jwksRequests)
+			}
+		})
+	}
+}
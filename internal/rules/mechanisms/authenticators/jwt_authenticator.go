@@ -33,6 +33,7 @@ import (
 	"gopkg.in/square/go-jose.v2/jwt"
 
 	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/rules/endpoint"
 	"github.com/dadrus/heimdall/internal/rules/mechanisms/authenticators/extractors"
@@ -53,7 +54,7 @@ const defaultJWTAuthenticatorTTL = 10 * time.Minute
 //nolint:gochecknoinits
 func init() {
 	registerTypeFactory(
-		func(id string, typ string, conf map[string]any) (bool, Authenticator, error) {
+		func(id string, typ string, conf map[string]any, _ zerolog.Logger) (bool, Authenticator, error) {
 			if typ != AuthenticatorJwt {
 				return false, nil, nil
 			}
@@ -62,6 +63,8 @@ func init() {
 
 			return true, auth, err
 		})
+
+	cache.RegisterValueType("authenticators/jwt/jwk", &jose.JSONWebKey{})
 }
 
 type jwtAuthenticator struct {
@@ -74,9 +77,15 @@ type jwtAuthenticator struct {
 	allowFallbackOnError bool
 	trustStore           truststore.TrustStore
 	validateJWKCert      bool
+	dec                  *jwtDecrypter
 }
 
 func newJwtAuthenticator(id string, rawConfig map[string]any) (*jwtAuthenticator, error) { // nolint: funlen
+	type DecryptionConfig struct {
+		KeyStore            config.KeyStore `mapstructure:"key_store"             validate:"required"`
+		AllowUnsignedTokens bool            `mapstructure:"allow_unsigned_tokens"`
+	}
+
 	type Config struct {
 		JWKSEndpoint         *endpoint.Endpoint                  `mapstructure:"jwks_endpoint"        validate:"required_without=MetadataEndpoint,excluded_with=MetadataEndpoint"` //nolint:lll,tagalign
 		MetadataEndpoint     *oauth2.MetadataEndpoint            `mapstructure:"metadata_endpoint"    validate:"required_without=JWKSEndpoint,excluded_with=JWKSEndpoint"`         //nolint:lll,tagalign
@@ -87,6 +96,7 @@ func newJwtAuthenticator(id string, rawConfig map[string]any) (*jwtAuthenticator
 		AllowFallbackOnError bool                                `mapstructure:"allow_fallback_on_error"`
 		ValidateJWK          *bool                               `mapstructure:"validate_jwk"`
 		TrustStore           truststore.TrustStore               `mapstructure:"trust_store"`
+		Decryption           *DecryptionConfig                   `mapstructure:"decryption"`
 	}
 
 	var conf Config
@@ -103,6 +113,17 @@ func newJwtAuthenticator(id string, rawConfig map[string]any) (*jwtAuthenticator
 		conf.Assertions.AllowedAlgorithms = defaultAllowedAlgorithms()
 	}
 
+	var (
+		dec *jwtDecrypter
+		err error
+	)
+
+	if conf.Decryption != nil {
+		if dec, err = newJWTDecrypter(conf.Decryption.KeyStore, conf.Decryption.AllowUnsignedTokens); err != nil {
+			return nil, err
+		}
+	}
+
 	if conf.Assertions.ScopesMatcher == nil {
 		conf.Assertions.ScopesMatcher = oauth2.NoopMatcher{}
 	}
@@ -161,6 +182,7 @@ func newJwtAuthenticator(id string, rawConfig map[string]any) (*jwtAuthenticator
 		allowFallbackOnError: conf.AllowFallbackOnError,
 		validateJWKCert:      validateJWKCert,
 		trustStore:           conf.TrustStore,
+		dec:                  dec,
 	}, nil
 }
 
@@ -176,16 +198,14 @@ func (a *jwtAuthenticator) Execute(ctx heimdall.Context) (*subject.Subject, erro
 			CausedBy(err)
 	}
 
-	token, err := jwt.ParseSigned(jwtAd)
-	if err != nil {
-		return nil, errorchain.
-			NewWithMessage(heimdall.ErrAuthentication, "failed to parse JWT").
-			WithErrorContext(a).
-			CausedBy(heimdall.ErrArgument).
-			CausedBy(err)
+	var rawClaims json.RawMessage
+
+	if isEncryptedJWT(jwtAd) {
+		rawClaims, err = a.verifyEncryptedToken(ctx, jwtAd)
+	} else {
+		rawClaims, err = a.verifySignedToken(ctx, jwtAd)
 	}
 
-	rawClaims, err := a.verifyToken(ctx, token)
 	if err != nil {
 		return nil, err
 	}
@@ -230,6 +250,7 @@ func (a *jwtAuthenticator) WithConfig(config map[string]any) (Authenticator, err
 			func() bool { return a.allowFallbackOnError }),
 		validateJWKCert: a.validateJWKCert,
 		trustStore:      a.trustStore,
+		dec:             a.dec,
 	}, nil
 }
 
@@ -300,6 +321,71 @@ func (a *jwtAuthenticator) serverMetadata(ctx heimdall.Context, claims map[strin
 	return metadata, nil
 }
 
+func (a *jwtAuthenticator) verifySignedToken(ctx heimdall.Context, rawToken string) (json.RawMessage, error) {
+	token, err := jwt.ParseSigned(rawToken)
+	if err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrAuthentication, "failed to parse JWT").
+			WithErrorContext(a).
+			CausedBy(heimdall.ErrArgument).
+			CausedBy(err)
+	}
+
+	return a.verifyToken(ctx, token)
+}
+
+func (a *jwtAuthenticator) verifyEncryptedToken(ctx heimdall.Context, rawToken string) (json.RawMessage, error) {
+	if a.dec == nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrAuthentication, "received an encrypted JWT, but decryption is not configured").
+			WithErrorContext(a)
+	}
+
+	payload, nested, err := a.dec.decrypt(rawToken)
+	if err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrAuthentication, "failed to decrypt JWT").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	if nested {
+		return a.verifySignedToken(ctx, stringx.ToString(payload))
+	}
+
+	return a.verifyUnsignedClaims(ctx, payload)
+}
+
+func (a *jwtAuthenticator) verifyUnsignedClaims(ctx heimdall.Context, payload []byte) (json.RawMessage, error) {
+	var (
+		mapClaims map[string]any
+		claims    oauth2.Claims
+	)
+
+	if err := json.Unmarshal(payload, &mapClaims); err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrAuthentication, "failed to deserialize JWT").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrAuthentication, "failed to deserialize JWT").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	metadata, err := a.serverMetadata(ctx, mapClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	assertions := a.a.Merge(&oauth2.Expectation{
+		TrustedIssuers: []string{metadata.Issuer},
+	})
+
+	return a.validateClaims(mapClaims, &claims, &assertions)
+}
+
 func (a *jwtAuthenticator) verifyToken(ctx heimdall.Context, token *jwt.JSONWebToken) (json.RawMessage, error) {
 	claims := map[string]any{}
 	if err := token.UnsafeClaimsWithoutVerification(&claims); err != nil {
@@ -550,6 +636,12 @@ func (a *jwtAuthenticator) verifyTokenWithKey(
 			CausedBy(err)
 	}
 
+	return a.validateClaims(mapClaims, &claims, assertions)
+}
+
+func (a *jwtAuthenticator) validateClaims(
+	mapClaims map[string]any, claims *oauth2.Claims, assertions *oauth2.Expectation,
+) (json.RawMessage, error) {
 	if err := claims.Validate(*assertions); err != nil {
 		return nil, errorchain.
 			NewWithMessage(heimdall.ErrAuthentication, "access token does not satisfy assertion conditions").
@@ -568,6 +660,12 @@ func (a *jwtAuthenticator) verifyTokenWithKey(
 	return rawPayload, nil
 }
 
+func isEncryptedJWT(rawToken string) bool {
+	// JWE compact serialization consists of five parts, JWS compact serialization of three
+	// see https://www.rfc-editor.org/rfc/rfc7516#section-7.1 and https://www.rfc-editor.org/rfc/rfc7519#section-7.2
+	return strings.Count(rawToken, ".") == 4 //nolint:gomnd
+}
+
 func (a *jwtAuthenticator) calculateCacheKey(ep *endpoint.Endpoint, renderedURL, reference string) string {
 	digest := sha256.New()
 	digest.Write(ep.Hash())
@@ -441,6 +441,41 @@ cache_ttl: 5s`),
 				assert.Equal(t, "auth1", auth.ID())
 			},
 		},
+		{
+			uc: "with decryption configured, but not existing key store",
+			id: "auth1",
+			config: []byte(`
+metadata_endpoint:
+  url: http://foo.bar
+decryption:
+  key_store:
+    path: /does/not/exist.pem
+`),
+			assert: func(t *testing.T, err error, _ *jwtAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed loading decryption key store")
+			},
+		},
+		{
+			uc: "with decryption configured without key store",
+			id: "auth1",
+			config: []byte(`
+metadata_endpoint:
+  url: http://foo.bar
+decryption:
+  allow_unsigned_tokens: true
+`),
+			assert: func(t *testing.T, err error, _ *jwtAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'decryption'.'key_store' is a required field")
+			},
+		},
 	} {
 		t.Run("case="+tc.uc, func(t *testing.T) {
 			conf, err := testsupport.DecodeTestConfig(tc.config)
@@ -872,6 +907,18 @@ func TestJwtAuthenticatorExecute(t *testing.T) {
 	jwtSignedWithKeyAndCertJWK := createJWT(t, keyAndCertEntry, subjectID, issuer, audience, true)
 	jwtWithoutKIDSignedWithKeyAndCertJWK := createJWT(t, keyAndCertEntry, subjectID, issuer, audience, false)
 
+	decryptionKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	decryptionKS, err := keystore.NewKeyStoreFromKey(decryptionKey)
+	require.NoError(t, err)
+
+	decryptionEntry := decryptionKS.Entries()[0]
+	jweWithNestedJWT := createJWE(t, &decryptionKey.PublicKey, decryptionEntry.KeyID, "JWT",
+		[]byte(jwtSignedWithKeyOnlyJWK))
+	jweWithClaims := createJWE(t, &decryptionKey.PublicKey, decryptionEntry.KeyID, "", []byte(`{
+		"sub": "`+subjectID+`", "iss": "`+issuer+`", "aud": ["`+audience+`"], "exp": `+
+		fmt.Sprintf("%d", time.Now().Unix()+60)+`}`))
+
 	jwksSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		jwksEndpointCalled = true
 
@@ -2329,6 +2376,187 @@ func TestJwtAuthenticatorExecute(t *testing.T) {
 				require.ErrorContains(t, err, "issuer foobar is not trusted")
 			},
 		},
+		{
+			uc:            "with encrypted JWT, but without decryption configured",
+			authenticator: &jwtAuthenticator{id: "auth3"},
+			configureMocks: func(t *testing.T,
+				ctx *heimdallmocks.ContextMock,
+				_ *mocks.CacheMock,
+				ads *mocks2.AuthDataExtractStrategyMock,
+				_ *jwtAuthenticator,
+			) {
+				t.Helper()
+
+				ads.EXPECT().GetAuthData(ctx).Return(jweWithNestedJWT, nil)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				assert.False(t, jwksEndpointCalled)
+				assert.False(t, metadataEndpointCalled)
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorContains(t, err, "decryption is not configured")
+
+				var identifier HandlerIdentifier
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "auth3", identifier.ID())
+			},
+		},
+		{
+			uc: "with encrypted JWT, which cannot be decrypted",
+			authenticator: &jwtAuthenticator{
+				id:  "auth3",
+				dec: &jwtDecrypter{entries: ks.Entries()},
+			},
+			configureMocks: func(t *testing.T,
+				ctx *heimdallmocks.ContextMock,
+				_ *mocks.CacheMock,
+				ads *mocks2.AuthDataExtractStrategyMock,
+				_ *jwtAuthenticator,
+			) {
+				t.Helper()
+
+				ads.EXPECT().GetAuthData(ctx).Return(jweWithNestedJWT, nil)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				assert.False(t, jwksEndpointCalled)
+				assert.False(t, metadataEndpointCalled)
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorIs(t, err, errNoDecryptionKey)
+				require.ErrorContains(t, err, "failed to decrypt JWT")
+			},
+		},
+		{
+			uc: "with encrypted unsigned JWT, which is not allowed",
+			authenticator: &jwtAuthenticator{
+				id:  "auth3",
+				dec: &jwtDecrypter{entries: decryptionKS.Entries()},
+			},
+			configureMocks: func(t *testing.T,
+				ctx *heimdallmocks.ContextMock,
+				_ *mocks.CacheMock,
+				ads *mocks2.AuthDataExtractStrategyMock,
+				_ *jwtAuthenticator,
+			) {
+				t.Helper()
+
+				ads.EXPECT().GetAuthData(ctx).Return(jweWithClaims, nil)
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject) {
+				t.Helper()
+
+				assert.False(t, jwksEndpointCalled)
+				assert.False(t, metadataEndpointCalled)
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorIs(t, err, errUnsignedJWT)
+			},
+		},
+		{
+			uc: "successful with encrypted nested JWT",
+			authenticator: &jwtAuthenticator{
+				r: oauth2.ResolverAdapterFunc(func(_ context.Context, _ map[string]any) (oauth2.ServerMetadata, error) {
+					return oauth2.ServerMetadata{
+						JWKSEndpoint: &endpoint.Endpoint{
+							URL:     jwksSrv.URL,
+							Headers: map[string]string{"Accept": "application/json"},
+						},
+					}, nil
+				}),
+				a: oauth2.Expectation{
+					AllowedAlgorithms: []string{"ES384"},
+					TrustedIssuers:    []string{issuer},
+					ScopesMatcher:     oauth2.ExactScopeStrategyMatcher{},
+				},
+				sf:  &SubjectInfo{IDFrom: "sub"},
+				ttl: &tenSecondsTTL,
+				dec: &jwtDecrypter{entries: decryptionKS.Entries()},
+			},
+			configureMocks: func(t *testing.T,
+				ctx *heimdallmocks.ContextMock,
+				cch *mocks.CacheMock,
+				ads *mocks2.AuthDataExtractStrategyMock,
+				auth *jwtAuthenticator,
+			) {
+				t.Helper()
+
+				ep := &endpoint.Endpoint{
+					URL:     jwksSrv.URL,
+					Headers: map[string]string{"Accept": "application/json"},
+				}
+				cacheKey := auth.calculateCacheKey(ep, jwksSrv.URL, kidKeyWithoutCert)
+
+				var jwks jose.JSONWebKeySet
+				err := json.Unmarshal(jwksWithOneKeyOnlyEntry, &jwks)
+				require.NoError(t, err)
+
+				keys := jwks.Key(kidKeyWithoutCert)
+
+				ads.EXPECT().GetAuthData(ctx).Return(jweWithNestedJWT, nil)
+				cch.EXPECT().Get(mock.Anything, cacheKey).Return(&keys[0])
+			},
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				assert.False(t, jwksEndpointCalled)
+				assert.False(t, metadataEndpointCalled)
+
+				require.NoError(t, err)
+
+				require.NotNil(t, sub)
+				assert.Equal(t, subjectID, sub.ID)
+				assert.Equal(t, issuer, sub.Attributes["iss"])
+				assert.Contains(t, sub.Attributes["scp"], "foo")
+			},
+		},
+		{
+			uc: "successful with encrypted unsigned JWT",
+			authenticator: &jwtAuthenticator{
+				r: oauth2.ResolverAdapterFunc(func(_ context.Context, _ map[string]any) (oauth2.ServerMetadata, error) {
+					return oauth2.ServerMetadata{
+						JWKSEndpoint: &endpoint.Endpoint{URL: jwksSrv.URL},
+					}, nil
+				}),
+				a: oauth2.Expectation{
+					AllowedAlgorithms: []string{"ES384"},
+					TrustedIssuers:    []string{issuer},
+					TargetAudiences:   []string{audience},
+					ScopesMatcher:     oauth2.NoopMatcher{},
+				},
+				sf:  &SubjectInfo{IDFrom: "sub"},
+				dec: &jwtDecrypter{entries: decryptionKS.Entries(), allowUnsigned: true},
+			},
+			configureMocks: func(t *testing.T,
+				ctx *heimdallmocks.ContextMock,
+				_ *mocks.CacheMock,
+				ads *mocks2.AuthDataExtractStrategyMock,
+				_ *jwtAuthenticator,
+			) {
+				t.Helper()
+
+				ads.EXPECT().GetAuthData(ctx).Return(jweWithClaims, nil)
+			},
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				assert.False(t, jwksEndpointCalled)
+				assert.False(t, metadataEndpointCalled)
+
+				require.NoError(t, err)
+
+				require.NotNil(t, sub)
+				assert.Equal(t, subjectID, sub.ID)
+				assert.Len(t, sub.Attributes, 4)
+				assert.Equal(t, issuer, sub.Attributes["iss"])
+			},
+		},
 	} {
 		t.Run("case="+tc.uc, func(t *testing.T) {
 			// GIVEN
@@ -2476,6 +2704,27 @@ func createJWT(t *testing.T, keyEntry *keystore.Entry, subject, issuer, audience
 	return rawJwt
 }
 
+func createJWE(t *testing.T, key any, keyID, contentType string, payload []byte) string {
+	t.Helper()
+
+	opts := &jose.EncrypterOptions{}
+	if len(contentType) != 0 {
+		opts = opts.WithContentType(jose.ContentType(contentType))
+	}
+
+	encrypter, err := jose.NewEncrypter(jose.A256GCM,
+		jose.Recipient{Algorithm: jose.RSA_OAEP_256, Key: key, KeyID: keyID}, opts)
+	require.NoError(t, err)
+
+	jwe, err := encrypter.Encrypt(payload)
+	require.NoError(t, err)
+
+	token, err := jwe.CompactSerialize()
+	require.NoError(t, err)
+
+	return token
+}
+
 func TestJwtAuthenticatorGetCacheTTL(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,150 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authenticators
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/keystore"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+var (
+	errNoDecryptionKey  = errors.New("no matching decryption key available")
+	errUnsignedJWT      = errors.New("encrypted JWT does not contain a signed JWT")
+	errKeyAlgNotAllowed = errors.New("key management algorithm is not allowed")
+	errDecryptionFailed = errors.New("none of the available keys could decrypt the JWT")
+	errNotCompactJWE    = errors.New("JWT is not in JWE compact serialization")
+	errCompressedJWE    = errors.New("compressed JWTs are not allowed")
+)
+
+// headerCompression is the JWE header referencing the algorithm used to compress the plaintext.
+const headerCompression jose.HeaderKey = "zip"
+
+func supportedKeyManagementAlgorithms() []string {
+	// RSA PKCS v1.5 is not allowed by intention
+	return []string{
+		// RSA-OAEP
+		string(jose.RSA_OAEP), string(jose.RSA_OAEP_256),
+		// ECDH-ES
+		string(jose.ECDH_ES), string(jose.ECDH_ES_A128KW), string(jose.ECDH_ES_A192KW), string(jose.ECDH_ES_A256KW),
+	}
+}
+
+// jwtDecrypter decrypts JWE encoded JWTs using the RSA and EC keys from the configured key store.
+type jwtDecrypter struct {
+	entries       []*keystore.Entry
+	allowUnsigned bool
+}
+
+func newJWTDecrypter(conf config.KeyStore, allowUnsigned bool) (*jwtDecrypter, error) {
+	ks, err := keystore.NewKeyStore(conf)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration, "failed loading decryption key store").
+			CausedBy(err)
+	}
+
+	// only keys available in memory can be used by go-jose for decryption purposes
+	entries := slices.DeleteFunc(slices.Clone(ks.Entries()), func(entry *keystore.Entry) bool {
+		switch entry.PrivateKey.(type) {
+		case *rsa.PrivateKey, *ecdsa.PrivateKey:
+			return false
+		default:
+			return true
+		}
+	})
+
+	if len(entries) == 0 {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"decryption key store does not contain any RSA or EC private keys")
+	}
+
+	return &jwtDecrypter{entries: entries, allowUnsigned: allowUnsigned}, nil
+}
+
+// decrypt decrypts the given JWE and returns its payload. The returned flag is true if the
+// payload is a nested JWT, which has to be verified by the caller. Otherwise, the payload
+// holds the claims of an unsigned JWT, which is only returned if allowed by configuration.
+func (d *jwtDecrypter) decrypt(rawToken string) ([]byte, bool, error) {
+	// JWTs always use the compact serialization (see https://www.rfc-editor.org/rfc/rfc7519#section-1).
+	// The JSON serialization would moreover allow per recipient headers, which are not visible here.
+	if strings.HasPrefix(strings.TrimSpace(rawToken), "{") {
+		return nil, false, errNotCompactJWE
+	}
+
+	jwe, err := jose.ParseEncrypted(rawToken)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// go-jose inflates compressed payloads without any size limit, which would allow
+	// decompression bombs. Compression is not used with JWTs in practice anyway.
+	if _, present := jwe.Header.ExtraHeaders[headerCompression]; present {
+		return nil, false, errCompressedJWE
+	}
+
+	if !slices.Contains(supportedKeyManagementAlgorithms(), jwe.Header.Algorithm) {
+		return nil, false, fmt.Errorf("%w: %s", errKeyAlgNotAllowed, jwe.Header.Algorithm)
+	}
+
+	// see https://www.rfc-editor.org/rfc/rfc7519#section-5.2
+	contentType, _ := jwe.Header.ExtraHeaders[jose.HeaderContentType].(string)
+	nested := strings.EqualFold(contentType, "JWT")
+
+	if !nested && !d.allowUnsigned {
+		return nil, false, errUnsignedJWT
+	}
+
+	payload, err := d.decryptPayload(jwe)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return payload, nested, nil
+}
+
+func (d *jwtDecrypter) decryptPayload(jwe *jose.JSONWebEncryption) ([]byte, error) {
+	keyID := jwe.Header.KeyID
+
+	candidates := d.entries
+	if len(keyID) != 0 {
+		candidates = slices.DeleteFunc(slices.Clone(d.entries), func(entry *keystore.Entry) bool {
+			return entry.KeyID != keyID
+		})
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: kid %s", errNoDecryptionKey, keyID)
+	}
+
+	for _, entry := range candidates {
+		if payload, err := jwe.Decrypt(entry.PrivateKey); err == nil {
+			return payload, nil
+		}
+	}
+
+	return nil, errDecryptionFailed
+}
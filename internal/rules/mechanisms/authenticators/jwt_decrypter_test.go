@@ -0,0 +1,311 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authenticators
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/x/pkix/pemx"
+)
+
+func TestNewJWTDecrypter(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+
+	for _, tc := range []struct {
+		uc     string
+		pem    func(t *testing.T) []byte
+		assert func(t *testing.T, err error, dec *jwtDecrypter)
+	}{
+		{
+			uc: "not existing key store",
+			assert: func(t *testing.T, err error, _ *jwtDecrypter) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed loading decryption key store")
+			},
+		},
+		{
+			uc: "key store with usable keys",
+			pem: func(t *testing.T) []byte {
+				t.Helper()
+
+				data, err := pemx.BuildPEM(pemx.WithRSAPrivateKey(rsaKey, pemx.WithHeader("X-Key-ID", "rsa")))
+				require.NoError(t, err)
+
+				return data
+			},
+			assert: func(t *testing.T, err error, dec *jwtDecrypter) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.Len(t, dec.entries, 1)
+				assert.Equal(t, "rsa", dec.entries[0].KeyID)
+				assert.True(t, dec.allowUnsigned)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			path := filepath.Join(dir, "does_not_exist.pem")
+
+			if tc.pem != nil {
+				file, err := os.CreateTemp(dir, "keys-*.pem")
+				require.NoError(t, err)
+
+				_, err = file.Write(tc.pem(t))
+				require.NoError(t, err)
+				require.NoError(t, file.Close())
+
+				path = file.Name()
+			}
+
+			// WHEN
+			dec, err := newJWTDecrypter(config.KeyStore{Path: path}, true)
+
+			// THEN
+			tc.assert(t, err, dec)
+		})
+	}
+}
+
+func TestJWTDecrypterDecrypt(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	pemBytes, err := pemx.BuildPEM(
+		pemx.WithRSAPrivateKey(rsaKey, pemx.WithHeader("X-Key-ID", "rsa")),
+		pemx.WithECDSAPrivateKey(ecKey, pemx.WithHeader("X-Key-ID", "ec")),
+	)
+	require.NoError(t, err)
+
+	keyFile, err := os.CreateTemp(t.TempDir(), "keys-*.pem")
+	require.NoError(t, err)
+	_, err = keyFile.Write(pemBytes)
+	require.NoError(t, err)
+	require.NoError(t, keyFile.Close())
+
+	dec, err := newJWTDecrypter(config.KeyStore{Path: keyFile.Name()}, false)
+	require.NoError(t, err)
+
+	encrypt := func(t *testing.T, alg jose.KeyAlgorithm, key any, keyID, contentType string) string {
+		t.Helper()
+
+		opts := &jose.EncrypterOptions{}
+		if len(contentType) != 0 {
+			opts = opts.WithContentType(jose.ContentType(contentType))
+		}
+
+		encrypter, err := jose.NewEncrypter(jose.A128CBC_HS256,
+			jose.Recipient{Algorithm: alg, Key: key, KeyID: keyID}, opts)
+		require.NoError(t, err)
+
+		jwe, err := encrypter.Encrypt([]byte("foo.bar.baz"))
+		require.NoError(t, err)
+
+		token, err := jwe.CompactSerialize()
+		require.NoError(t, err)
+
+		return token
+	}
+
+	for _, tc := range []struct {
+		uc     string
+		token  func(t *testing.T) string
+		assert func(t *testing.T, err error, payload []byte, nested bool)
+	}{
+		{
+			uc: "malformed token",
+			token: func(t *testing.T) string {
+				t.Helper()
+
+				return "a.b.c.d.e"
+			},
+			assert: func(t *testing.T, err error, _ []byte, _ bool) {
+				t.Helper()
+
+				require.Error(t, err)
+			},
+		},
+		{
+			uc: "not allowed key management algorithm",
+			token: func(t *testing.T) string {
+				t.Helper()
+
+				return encrypt(t, jose.RSA1_5, &rsaKey.PublicKey, "rsa", "JWT")
+			},
+			assert: func(t *testing.T, err error, _ []byte, _ bool) {
+				t.Helper()
+
+				require.ErrorIs(t, err, errKeyAlgNotAllowed)
+				require.ErrorContains(t, err, "RSA1_5")
+			},
+		},
+		{
+			uc: "token in JSON serialization",
+			token: func(t *testing.T) string {
+				t.Helper()
+
+				encrypter, err := jose.NewEncrypter(jose.A128CBC_HS256,
+					jose.Recipient{Algorithm: jose.RSA_OAEP, Key: &rsaKey.PublicKey, KeyID: "rsa"},
+					(&jose.EncrypterOptions{}).WithContentType("JWT"))
+				require.NoError(t, err)
+
+				jwe, err := encrypter.Encrypt([]byte("foo.bar.baz"))
+				require.NoError(t, err)
+
+				return jwe.FullSerialize()
+			},
+			assert: func(t *testing.T, err error, _ []byte, _ bool) {
+				t.Helper()
+
+				require.ErrorIs(t, err, errNotCompactJWE)
+			},
+		},
+		{
+			uc: "DEF compressed token",
+			token: func(t *testing.T) string {
+				t.Helper()
+
+				encrypter, err := jose.NewEncrypter(jose.A128CBC_HS256,
+					jose.Recipient{Algorithm: jose.RSA_OAEP, Key: &rsaKey.PublicKey, KeyID: "rsa"},
+					&jose.EncrypterOptions{
+						Compression:  jose.DEFLATE,
+						ExtraHeaders: map[jose.HeaderKey]any{jose.HeaderContentType: "JWT"},
+					})
+				require.NoError(t, err)
+
+				jwe, err := encrypter.Encrypt([]byte(strings.Repeat("a", 1024*1024)))
+				require.NoError(t, err)
+
+				token, err := jwe.CompactSerialize()
+				require.NoError(t, err)
+
+				return token
+			},
+			assert: func(t *testing.T, err error, _ []byte, _ bool) {
+				t.Helper()
+
+				require.ErrorIs(t, err, errCompressedJWE)
+			},
+		},
+		{
+			uc: "unsigned JWT",
+			token: func(t *testing.T) string {
+				t.Helper()
+
+				return encrypt(t, jose.RSA_OAEP, &rsaKey.PublicKey, "rsa", "")
+			},
+			assert: func(t *testing.T, err error, _ []byte, _ bool) {
+				t.Helper()
+
+				require.ErrorIs(t, err, errUnsignedJWT)
+			},
+		},
+		{
+			uc: "unknown key id",
+			token: func(t *testing.T) string {
+				t.Helper()
+
+				return encrypt(t, jose.RSA_OAEP, &rsaKey.PublicKey, "foo", "JWT")
+			},
+			assert: func(t *testing.T, err error, _ []byte, _ bool) {
+				t.Helper()
+
+				require.ErrorIs(t, err, errNoDecryptionKey)
+			},
+		},
+		{
+			uc: "encrypted for another key without key id",
+			token: func(t *testing.T) string {
+				t.Helper()
+
+				otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+				require.NoError(t, err)
+
+				return encrypt(t, jose.RSA_OAEP_256, &otherKey.PublicKey, "", "JWT")
+			},
+			assert: func(t *testing.T, err error, _ []byte, _ bool) {
+				t.Helper()
+
+				require.ErrorIs(t, err, errDecryptionFailed)
+			},
+		},
+		{
+			uc: "successful with RSA key referenced by key id",
+			token: func(t *testing.T) string {
+				t.Helper()
+
+				return encrypt(t, jose.RSA_OAEP_256, &rsaKey.PublicKey, "rsa", "JWT")
+			},
+			assert: func(t *testing.T, err error, payload []byte, nested bool) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.True(t, nested)
+				assert.Equal(t, "foo.bar.baz", string(payload))
+			},
+		},
+		{
+			uc: "successful with EC key without key id",
+			token: func(t *testing.T) string {
+				t.Helper()
+
+				return encrypt(t, jose.ECDH_ES_A256KW, &ecKey.PublicKey, "", "jwt")
+			},
+			assert: func(t *testing.T, err error, payload []byte, nested bool) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.True(t, nested)
+				assert.Equal(t, "foo.bar.baz", string(payload))
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// WHEN
+			payload, nested, err := dec.decrypt(tc.token(t))
+
+			// THEN
+			tc.assert(t, err, payload, nested)
+		})
+	}
+}
@@ -0,0 +1,627 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authenticators
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog"
+	"github.com/tidwall/gjson"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/endpoint/authstrategy"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/oauth2"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/rules/oauth2/clientcredentials"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/stringx"
+)
+
+const (
+	defaultOIDCSessionLifespan  = 24 * time.Hour
+	defaultOIDCSessionCookie    = "heimdall_session"
+	oidcLoginFlowTTL            = 10 * time.Minute
+	oidcAccessTokenExpiryLeeway = 10 * time.Second
+)
+
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	registerTypeFactory(
+		func(id string, typ string, conf map[string]any, _ zerolog.Logger) (bool, Authenticator, error) {
+			if typ != AuthenticatorOIDC {
+				return false, nil, nil
+			}
+
+			auth, err := newOIDCAuthenticator(id, conf)
+
+			return true, auth, err
+		})
+}
+
+type oidcLoginFlow struct {
+	State        string    `json:"state"`
+	Nonce        string    `json:"nonce"`
+	CodeVerifier string    `json:"code_verifier"`
+	RedirectTo   string    `json:"redirect_to"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+type oidcCookie struct {
+	name     string
+	domain   string
+	path     string
+	sameSite http.SameSite
+	secure   bool
+}
+
+type oidcAuthenticator struct {
+	id                    string
+	r                     oauth2.ServerMetadataResolver
+	tv                    *jwtAuthenticator
+	clientID              string
+	clientSecret          string
+	redirectURI           *url.URL
+	scopes                []string
+	logoutPath            string
+	postLogoutRedirectURI string
+	lifespan              time.Duration
+	cookie                oidcCookie
+	cipher                *cookieCipher
+	store                 oidcSessionStore
+	sf                    SubjectFactory
+	allowFallbackOnError  bool
+}
+
+func newOIDCAuthenticator(id string, rawConfig map[string]any) (*oidcAuthenticator, error) { // nolint: funlen
+	type CookieConfig struct {
+		Name     string `mapstructure:"name"`
+		Domain   string `mapstructure:"domain"`
+		Path     string `mapstructure:"path"`
+		SameSite string `mapstructure:"same_site" validate:"omitempty,oneof=lax strict none"`
+		Secure   *bool  `mapstructure:"secure"`
+	}
+
+	type SessionConfig struct {
+		Store    string        `mapstructure:"store"    validate:"omitempty,oneof=cookie cache"`
+		Secret   string        `mapstructure:"secret"   validate:"required,min=32"`
+		Lifespan time.Duration `mapstructure:"lifespan"`
+		Cookie   CookieConfig  `mapstructure:"cookie"`
+	}
+
+	type Config struct {
+		MetadataEndpoint      *oauth2.MetadataEndpoint `mapstructure:"metadata_endpoint"        validate:"required"`
+		ClientID              string                   `mapstructure:"client_id"                validate:"required"`
+		ClientSecret          string                   `mapstructure:"client_secret"            validate:"required"`
+		RedirectURI           string                   `mapstructure:"redirect_uri"             validate:"required,url"`
+		Scopes                []string                 `mapstructure:"scopes"`
+		LogoutPath            string                   `mapstructure:"logout_path"`
+		PostLogoutRedirectURI string                   `mapstructure:"post_logout_redirect_uri" validate:"omitempty,url"` //nolint:lll
+		Session               SessionConfig            `mapstructure:"session"`
+		SubjectInfo           SubjectInfo              `mapstructure:"subject"                  validate:"-"`
+		AllowFallbackOnError  bool                     `mapstructure:"allow_fallback_on_error"`
+	}
+
+	var conf Config
+	if err := decodeConfig(AuthenticatorOIDC, rawConfig, &conf); err != nil {
+		return nil, err
+	}
+
+	redirectURI, err := url.Parse(conf.RedirectURI)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration, "failed to parse redirect_uri").
+			CausedBy(err)
+	}
+
+	if len(conf.SubjectInfo.IDFrom) == 0 {
+		conf.SubjectInfo.IDFrom = "sub"
+	}
+
+	if !slices.Contains(conf.Scopes, "openid") {
+		conf.Scopes = append([]string{"openid"}, conf.Scopes...)
+	}
+
+	cipher, err := newCookieCipher(conf.Session.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	cookie := oidcCookie{
+		name:   x.IfThenElse(len(conf.Session.Cookie.Name) != 0, conf.Session.Cookie.Name, defaultOIDCSessionCookie),
+		domain: conf.Session.Cookie.Domain,
+		path:   x.IfThenElse(len(conf.Session.Cookie.Path) != 0, conf.Session.Cookie.Path, "/"),
+		secure: conf.Session.Cookie.Secure == nil || *conf.Session.Cookie.Secure,
+	}
+
+	switch conf.Session.Cookie.SameSite {
+	case "strict":
+		cookie.sameSite = http.SameSiteStrictMode
+	case "none":
+		cookie.sameSite = http.SameSiteNoneMode
+	default:
+		cookie.sameSite = http.SameSiteLaxMode
+	}
+
+	var store oidcSessionStore
+	if conf.Session.Store == oidcSessionStoreCache {
+		store = &cacheSessionStore{prefix: "authenticators/oidc/" + id + "/" + conf.ClientID}
+	} else {
+		store = &cookieSessionStore{name: cookie.name, cipher: cipher}
+	}
+
+	return &oidcAuthenticator{
+		id: id,
+		r:  conf.MetadataEndpoint,
+		tv: &jwtAuthenticator{
+			id: id,
+			r:  conf.MetadataEndpoint,
+			a: oauth2.Expectation{
+				TargetAudiences:   []string{conf.ClientID},
+				AllowedAlgorithms: defaultAllowedAlgorithms(),
+				ScopesMatcher:     oauth2.NoopMatcher{},
+			},
+			sf:              &conf.SubjectInfo,
+			validateJWKCert: true,
+		},
+		clientID:              conf.ClientID,
+		clientSecret:          conf.ClientSecret,
+		redirectURI:           redirectURI,
+		scopes:                conf.Scopes,
+		logoutPath:            conf.LogoutPath,
+		postLogoutRedirectURI: conf.PostLogoutRedirectURI,
+		lifespan: x.IfThenElse(conf.Session.Lifespan > 0,
+			conf.Session.Lifespan, defaultOIDCSessionLifespan),
+		cookie:               cookie,
+		cipher:               cipher,
+		store:                store,
+		sf:                   &conf.SubjectInfo,
+		allowFallbackOnError: conf.AllowFallbackOnError,
+	}, nil
+}
+
+func (a *oidcAuthenticator) Execute(ctx heimdall.Context) (*subject.Subject, error) {
+	logger := zerolog.Ctx(ctx.AppContext())
+	logger.Debug().Str("_id", a.id).Msg("Authenticating using OIDC authenticator")
+
+	switch path := ctx.Request().URL.Path; {
+	case path == a.redirectURI.Path:
+		return nil, a.finishLogin(ctx)
+	case len(a.logoutPath) != 0 && path == a.logoutPath:
+		return nil, a.logout(ctx)
+	}
+
+	value := ctx.Request().Cookie(a.cookie.name)
+	if len(value) == 0 {
+		logger.Debug().Msg("No session present")
+
+		return nil, a.startLogin(ctx)
+	}
+
+	sess, err := a.store.load(ctx.AppContext(), value)
+	if err != nil {
+		logger.Debug().Err(err).Msg("Session is not usable")
+
+		return nil, a.startLogin(ctx)
+	}
+
+	if !sess.AccessTokenExpiry.IsZero() && time.Now().Add(oidcAccessTokenExpiryLeeway).After(sess.AccessTokenExpiry) {
+		newValue, err := a.refresh(ctx, value, sess)
+		if err != nil {
+			return nil, err
+		}
+
+		// the session cookie has to be updated, which requires a roundtrip to the user agent
+		if newValue != value {
+			return nil, &heimdall.RedirectError{
+				Message:    "session renewed",
+				Code:       http.StatusTemporaryRedirect,
+				RedirectTo: ctx.Request().URL.String(),
+				Cookies:    []*http.Cookie{a.sessionCookie(newValue, sess.Expiry)},
+			}
+		}
+	}
+
+	sub, err := a.sf.CreateSubject(sess.Claims)
+	if err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrInternal, "failed to extract subject information from id_token").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	return sub, nil
+}
+
+func (a *oidcAuthenticator) WithConfig(rawConfig map[string]any) (Authenticator, error) {
+	// this authenticator allows only the fallback behavior to be redefined on the rule level
+	if len(rawConfig) == 0 {
+		return a, nil
+	}
+
+	type Config struct {
+		AllowFallbackOnError *bool `mapstructure:"allow_fallback_on_error"`
+	}
+
+	var conf Config
+	if err := decodeConfig(AuthenticatorOIDC, rawConfig, &conf); err != nil {
+		return nil, err
+	}
+
+	auth := *a
+	auth.allowFallbackOnError = x.IfThenElseExec(conf.AllowFallbackOnError != nil,
+		func() bool { return *conf.AllowFallbackOnError },
+		func() bool { return a.allowFallbackOnError })
+
+	return &auth, nil
+}
+
+func (a *oidcAuthenticator) IsFallbackOnErrorAllowed() bool {
+	return a.allowFallbackOnError
+}
+
+func (a *oidcAuthenticator) ID() string {
+	return a.id
+}
+
+func (a *oidcAuthenticator) startLogin(ctx heimdall.Context) error {
+	metadata, err := a.serverMetadata(ctx)
+	if err != nil {
+		return err
+	}
+
+	authURL, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		return errorchain.NewWithMessage(heimdall.ErrInternal, "failed to parse authorization_endpoint").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	flow := oidcLoginFlow{
+		RedirectTo: ctx.Request().URL.String(),
+		Expiry:     time.Now().Add(oidcLoginFlowTTL),
+	}
+
+	for _, val := range []*string{&flow.State, &flow.Nonce, &flow.CodeVerifier} {
+		if *val, err = randomString(); err != nil {
+			return err
+		}
+	}
+
+	value, err := a.cipher.encrypt(a.flowCookieName(), &flow)
+	if err != nil {
+		return err
+	}
+
+	challenge := sha256.Sum256(stringx.ToBytes(flow.CodeVerifier))
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", a.clientID)
+	query.Set("redirect_uri", a.redirectURI.String())
+	query.Set("scope", strings.Join(a.scopes, " "))
+	query.Set("state", flow.State)
+	query.Set("nonce", flow.Nonce)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	return &heimdall.RedirectError{
+		Message:    "authentication required",
+		Code:       http.StatusFound,
+		RedirectTo: authURL.String(),
+		Cookies:    []*http.Cookie{a.flowCookie(value, oidcLoginFlowTTL)},
+	}
+}
+
+func (a *oidcAuthenticator) finishLogin(ctx heimdall.Context) error {
+	logger := zerolog.Ctx(ctx.AppContext())
+	logger.Debug().Msg("Handling authorization response")
+
+	value := ctx.Request().Cookie(a.flowCookieName())
+	if len(value) == 0 {
+		return errorchain.NewWithMessage(heimdall.ErrAuthentication, "no login flow in progress").
+			WithErrorContext(a)
+	}
+
+	var flow oidcLoginFlow
+	if err := a.cipher.decrypt(a.flowCookieName(), value, &flow); err != nil || time.Now().After(flow.Expiry) {
+		return errorchain.NewWithMessage(heimdall.ErrAuthentication, "login flow state is invalid or expired").
+			WithErrorContext(a)
+	}
+
+	query := ctx.Request().URL.Query()
+	if errType := query.Get("error"); len(errType) != 0 {
+		return errorchain.NewWithMessage(heimdall.ErrAuthentication, "authorization request failed").
+			WithErrorContext(a).
+			CausedBy(&clientcredentials.TokenErrorResponse{
+				ErrorType:        errType,
+				ErrorDescription: query.Get("error_description"),
+				ErrorURI:         query.Get("error_uri"),
+			})
+	}
+
+	if query.Get("state") != flow.State {
+		return errorchain.NewWithMessage(heimdall.ErrAuthentication, "state parameter mismatch").
+			WithErrorContext(a)
+	}
+
+	code := query.Get("code")
+	if len(code) == 0 {
+		return errorchain.NewWithMessage(heimdall.ErrAuthentication, "no authorization code present").
+			WithErrorContext(a)
+	}
+
+	token, err := a.requestToken(ctx, url.Values{
+		"grant_type":    []string{"authorization_code"},
+		"code":          []string{code},
+		"redirect_uri":  []string{a.redirectURI.String()},
+		"code_verifier": []string{flow.CodeVerifier},
+	})
+	if err != nil {
+		return err
+	}
+
+	idToken, _ := token.Extra("id_token").(string)
+	if len(idToken) == 0 {
+		return errorchain.NewWithMessage(heimdall.ErrAuthentication,
+			"token endpoint response does not contain an id_token").
+			WithErrorContext(a)
+	}
+
+	claims, err := a.verifyIDToken(ctx, idToken, flow.Nonce)
+	if err != nil {
+		return err
+	}
+
+	sess := &oidcSession{
+		IDToken:           idToken,
+		AccessToken:       token.AccessToken,
+		RefreshToken:      token.RefreshToken,
+		AccessTokenExpiry: token.Expiry,
+		Claims:            claims,
+		Expiry:            time.Now().Add(a.lifespan),
+	}
+
+	if value, err = a.store.save(ctx.AppContext(), "", sess); err != nil {
+		return err
+	}
+
+	return &heimdall.RedirectError{
+		Message:    "login finished",
+		Code:       http.StatusFound,
+		RedirectTo: flow.RedirectTo,
+		Cookies: []*http.Cookie{
+			a.sessionCookie(value, sess.Expiry),
+			a.flowCookie("", 0),
+		},
+	}
+}
+
+func (a *oidcAuthenticator) refresh(ctx heimdall.Context, value string, sess *oidcSession) (string, error) {
+	logger := zerolog.Ctx(ctx.AppContext())
+
+	if len(sess.RefreshToken) == 0 {
+		logger.Debug().Msg("Access token expired and no refresh token available")
+		a.store.delete(ctx.AppContext(), value)
+
+		return "", a.startLogin(ctx)
+	}
+
+	logger.Debug().Msg("Refreshing session")
+
+	token, err := a.requestToken(ctx, url.Values{
+		"grant_type":    []string{"refresh_token"},
+		"refresh_token": []string{sess.RefreshToken},
+	})
+	if err != nil {
+		var ter *clientcredentials.TokenErrorResponse
+		if !errors.As(err, &ter) {
+			return "", err
+		}
+
+		logger.Info().Err(err).Msg("Failed to refresh session")
+		a.store.delete(ctx.AppContext(), value)
+
+		return "", a.startLogin(ctx)
+	}
+
+	// a new id_token is optional in a refresh response and does not contain a nonce if present
+	// see https://openid.net/specs/openid-connect-core-1_0.html#RefreshTokenResponse
+	if idToken, _ := token.Extra("id_token").(string); len(idToken) != 0 {
+		claims, err := a.verifyIDToken(ctx, idToken, "")
+		if err != nil {
+			return "", err
+		}
+
+		sess.IDToken = idToken
+		sess.Claims = claims
+	}
+
+	sess.AccessToken = token.AccessToken
+	sess.AccessTokenExpiry = token.Expiry
+
+	if len(token.RefreshToken) != 0 {
+		sess.RefreshToken = token.RefreshToken
+	}
+
+	return a.store.save(ctx.AppContext(), value, sess)
+}
+
+func (a *oidcAuthenticator) logout(ctx heimdall.Context) error {
+	logger := zerolog.Ctx(ctx.AppContext())
+	logger.Debug().Msg("Terminating session")
+
+	req := ctx.Request()
+	if req.Method != http.MethodPost {
+		return errorchain.NewWithMessage(heimdall.ErrMethodNotAllowed, "logout requires a POST request").
+			WithErrorContext(a)
+	}
+
+	// the session cookie is sent with cross site requests as well (if SameSite is not strict),
+	// so a request, initiated from another origin, must not be able to terminate the session
+	if origin := req.Header("Origin"); len(origin) != 0 && origin != req.URL.Scheme+"://"+req.URL.Host {
+		return errorchain.NewWithMessage(heimdall.ErrAuthorization, "cross origin logout request").
+			WithErrorContext(a)
+	}
+
+	var idToken string
+
+	if value := req.Cookie(a.cookie.name); len(value) != 0 {
+		if sess, err := a.store.load(ctx.AppContext(), value); err == nil {
+			idToken = sess.IDToken
+		}
+
+		a.store.delete(ctx.AppContext(), value)
+	}
+
+	metadata, err := a.serverMetadata(ctx)
+	if err != nil {
+		return err
+	}
+
+	target := x.IfThenElse(len(a.postLogoutRedirectURI) != 0, a.postLogoutRedirectURI, "/")
+
+	if len(metadata.EndSessionEndpoint) != 0 {
+		endSessionURL, err := url.Parse(metadata.EndSessionEndpoint)
+		if err != nil {
+			return errorchain.NewWithMessage(heimdall.ErrInternal, "failed to parse end_session_endpoint").
+				WithErrorContext(a).
+				CausedBy(err)
+		}
+
+		query := endSessionURL.Query()
+		query.Set("client_id", a.clientID)
+
+		if len(idToken) != 0 {
+			query.Set("id_token_hint", idToken)
+		}
+
+		if len(a.postLogoutRedirectURI) != 0 {
+			query.Set("post_logout_redirect_uri", a.postLogoutRedirectURI)
+		}
+
+		endSessionURL.RawQuery = query.Encode()
+		target = endSessionURL.String()
+	}
+
+	return &heimdall.RedirectError{
+		Message:    "logout",
+		Code:       http.StatusFound,
+		RedirectTo: target,
+		Cookies:    []*http.Cookie{a.sessionCookie("", time.Time{})},
+	}
+}
+
+func (a *oidcAuthenticator) requestToken(
+	ctx heimdall.Context, data url.Values,
+) (*clientcredentials.TokenInfo, error) {
+	metadata, err := a.serverMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ept := *metadata.TokenEndpoint
+	// see https://www.rfc-editor.org/rfc/rfc6749#section-2.3.1
+	ept.AuthStrategy = &authstrategy.BasicAuth{
+		User:     url.QueryEscape(a.clientID),
+		Password: url.QueryEscape(a.clientSecret),
+	}
+
+	return clientcredentials.RequestToken(ctx.AppContext(), ept, data)
+}
+
+func (a *oidcAuthenticator) verifyIDToken(ctx heimdall.Context, rawToken, nonce string) (json.RawMessage, error) {
+	token, err := jwt.ParseSigned(rawToken)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrAuthentication, "failed to parse id_token").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	claims, err := a.tv.verifyToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nonce) != 0 && gjson.GetBytes(claims, "nonce").String() != nonce {
+		return nil, errorchain.NewWithMessage(heimdall.ErrAuthentication, "nonce mismatch in id_token").
+			WithErrorContext(a)
+	}
+
+	return claims, nil
+}
+
+func (a *oidcAuthenticator) serverMetadata(ctx heimdall.Context) (oauth2.ServerMetadata, error) {
+	metadata, err := a.r.Get(ctx.AppContext(), nil)
+	if err != nil {
+		return oauth2.ServerMetadata{}, errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed retrieving oauth2 server metadata").CausedBy(err).WithErrorContext(a)
+	}
+
+	if len(metadata.AuthorizationEndpoint) == 0 || metadata.TokenEndpoint == nil {
+		return oauth2.ServerMetadata{}, errorchain.NewWithMessage(heimdall.ErrInternal,
+			"received server metadata does not contain the required authorization_endpoint and token_endpoint").
+			WithErrorContext(a)
+	}
+
+	return metadata, nil
+}
+
+func (a *oidcAuthenticator) flowCookieName() string { return a.cookie.name + "_flow" }
+
+func (a *oidcAuthenticator) flowCookie(value string, ttl time.Duration) *http.Cookie {
+	// a ttl of 0 results in removal of the cookie
+	cookie := a.newCookie(a.flowCookieName(), value, x.IfThenElse(ttl > 0, int(ttl.Seconds()), -1))
+	// the user agent must send this cookie with the top level navigation from the authorization server
+	cookie.SameSite = x.IfThenElse(a.cookie.sameSite == http.SameSiteNoneMode,
+		http.SameSiteNoneMode, http.SameSiteLaxMode)
+
+	return cookie
+}
+
+func (a *oidcAuthenticator) sessionCookie(value string, expiry time.Time) *http.Cookie {
+	maxAge := -1
+	if !expiry.IsZero() {
+		maxAge = int(time.Until(expiry).Seconds())
+	}
+
+	return a.newCookie(a.cookie.name, value, maxAge)
+}
+
+func (a *oidcAuthenticator) newCookie(name, value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     a.cookie.path,
+		Domain:   a.cookie.domain,
+		MaxAge:   maxAge,
+		Secure:   a.cookie.secure,
+		HttpOnly: true,
+		SameSite: a.cookie.sameSite,
+	}
+}
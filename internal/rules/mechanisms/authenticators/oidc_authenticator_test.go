@@ -0,0 +1,1091 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authenticators
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/cache/memory"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	heimdallmocks "github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/keystore"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/rules/oauth2/clientcredentials"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+const oidcTestSecret = "0123456789abcdef0123456789abcdef"
+
+func TestCreateOIDCAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc     string
+		id     string
+		config []byte
+		assert func(t *testing.T, err error, auth *oidcAuthenticator)
+	}{
+		{
+			uc: "without any configuration",
+			assert: func(t *testing.T, err error, _ *oidcAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'metadata_endpoint' is a required field")
+				require.ErrorContains(t, err, "'client_id' is a required field")
+				require.ErrorContains(t, err, "'redirect_uri' is a required field")
+			},
+		},
+		{
+			uc: "with unsupported properties",
+			config: []byte(`
+metadata_endpoint:
+  url: http://idp.test/.well-known/openid-configuration
+client_id: foo
+client_secret: bar
+redirect_uri: https://app.test/oidc/callback
+session:
+  secret: ` + oidcTestSecret + `
+foo: bar
+`),
+			assert: func(t *testing.T, err error, _ *oidcAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "failed decoding")
+			},
+		},
+		{
+			uc: "with too short session secret",
+			config: []byte(`
+metadata_endpoint:
+  url: http://idp.test/.well-known/openid-configuration
+client_id: foo
+client_secret: bar
+redirect_uri: https://app.test/oidc/callback
+session:
+  secret: foo
+`),
+			assert: func(t *testing.T, err error, _ *oidcAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'session'.'secret'")
+			},
+		},
+		{
+			uc: "with unsupported session store",
+			config: []byte(`
+metadata_endpoint:
+  url: http://idp.test/.well-known/openid-configuration
+client_id: foo
+client_secret: bar
+redirect_uri: https://app.test/oidc/callback
+session:
+  store: foo
+  secret: ` + oidcTestSecret + `
+`),
+			assert: func(t *testing.T, err error, _ *oidcAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'session'.'store'")
+			},
+		},
+		{
+			uc: "with malformed redirect_uri",
+			config: []byte(`
+metadata_endpoint:
+  url: http://idp.test/.well-known/openid-configuration
+client_id: foo
+client_secret: bar
+redirect_uri: foo
+session:
+  secret: ` + oidcTestSecret + `
+`),
+			assert: func(t *testing.T, err error, _ *oidcAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'redirect_uri' must be a valid URL")
+			},
+		},
+		{
+			uc: "with minimal configuration",
+			id: "auth1",
+			config: []byte(`
+metadata_endpoint:
+  url: http://idp.test/.well-known/openid-configuration
+client_id: foo
+client_secret: bar
+redirect_uri: https://app.test/oidc/callback
+session:
+  secret: ` + oidcTestSecret + `
+`),
+			assert: func(t *testing.T, err error, auth *oidcAuthenticator) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				assert.Equal(t, "auth1", auth.ID())
+				assert.Equal(t, "foo", auth.clientID)
+				assert.Equal(t, "bar", auth.clientSecret)
+				assert.Equal(t, "https://app.test/oidc/callback", auth.redirectURI.String())
+				assert.Equal(t, []string{"openid"}, auth.scopes)
+				assert.Empty(t, auth.logoutPath)
+				assert.Empty(t, auth.postLogoutRedirectURI)
+				assert.Equal(t, defaultOIDCSessionLifespan, auth.lifespan)
+				assert.Equal(t, oidcCookie{
+					name:     defaultOIDCSessionCookie,
+					path:     "/",
+					sameSite: http.SameSiteLaxMode,
+					secure:   true,
+				}, auth.cookie)
+				assert.IsType(t, &cookieSessionStore{}, auth.store)
+				assert.NotNil(t, auth.cipher)
+				assert.Equal(t, &SubjectInfo{IDFrom: "sub"}, auth.sf)
+				assert.False(t, auth.IsFallbackOnErrorAllowed())
+
+				require.NotNil(t, auth.tv)
+				assert.Equal(t, []string{"foo"}, auth.tv.a.TargetAudiences)
+				assert.ElementsMatch(t, defaultAllowedAlgorithms(), auth.tv.a.AllowedAlgorithms)
+				assert.True(t, auth.tv.validateJWKCert)
+			},
+		},
+		{
+			uc: "with full configuration",
+			id: "auth2",
+			config: []byte(`
+metadata_endpoint:
+  url: http://idp.test/.well-known/openid-configuration
+client_id: foo
+client_secret: bar
+redirect_uri: https://app.test/oidc/callback
+scopes:
+  - profile
+  - email
+logout_path: /logout
+post_logout_redirect_uri: https://app.test/
+session:
+  store: cache
+  secret: ` + oidcTestSecret + `
+  lifespan: 1h
+  cookie:
+    name: session
+    domain: app.test
+    path: /app
+    same_site: strict
+    secure: false
+subject:
+  id: email
+allow_fallback_on_error: true
+`),
+			assert: func(t *testing.T, err error, auth *oidcAuthenticator) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				assert.Equal(t, "auth2", auth.ID())
+				assert.Equal(t, []string{"openid", "profile", "email"}, auth.scopes)
+				assert.Equal(t, "/logout", auth.logoutPath)
+				assert.Equal(t, "https://app.test/", auth.postLogoutRedirectURI)
+				assert.Equal(t, 1*time.Hour, auth.lifespan)
+				assert.Equal(t, oidcCookie{
+					name:     "session",
+					domain:   "app.test",
+					path:     "/app",
+					sameSite: http.SameSiteStrictMode,
+					secure:   false,
+				}, auth.cookie)
+				assert.IsType(t, &cacheSessionStore{}, auth.store)
+				assert.Equal(t, &SubjectInfo{IDFrom: "email"}, auth.sf)
+				assert.True(t, auth.IsFallbackOnErrorAllowed())
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			// WHEN
+			auth, err := newOIDCAuthenticator(tc.id, conf)
+
+			// THEN
+			tc.assert(t, err, auth)
+		})
+	}
+}
+
+func TestCreateOIDCAuthenticatorFromPrototype(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc     string
+		config []byte
+		assert func(t *testing.T, err error, prototype *oidcAuthenticator, configured *oidcAuthenticator)
+	}{
+		{
+			uc: "without target config",
+			assert: func(t *testing.T, err error, prototype *oidcAuthenticator, configured *oidcAuthenticator) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, prototype, configured)
+			},
+		},
+		{
+			uc:     "with unsupported properties",
+			config: []byte(`client_id: bar`),
+			assert: func(t *testing.T, err error, _ *oidcAuthenticator, _ *oidcAuthenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+			},
+		},
+		{
+			uc:     "with allow_fallback_on_error reconfigured",
+			config: []byte(`allow_fallback_on_error: true`),
+			assert: func(t *testing.T, err error, prototype *oidcAuthenticator, configured *oidcAuthenticator) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.NotEqual(t, prototype, configured)
+				assert.False(t, prototype.IsFallbackOnErrorAllowed())
+				assert.True(t, configured.IsFallbackOnErrorAllowed())
+				assert.Equal(t, prototype.ID(), configured.ID())
+				assert.Equal(t, prototype.clientID, configured.clientID)
+				assert.Equal(t, prototype.store, configured.store)
+				assert.Equal(t, prototype.cipher, configured.cipher)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			pc, err := testsupport.DecodeTestConfig([]byte(`
+metadata_endpoint:
+  url: http://idp.test/.well-known/openid-configuration
+client_id: foo
+client_secret: bar
+redirect_uri: https://app.test/oidc/callback
+session:
+  secret: ` + oidcTestSecret + `
+`))
+			require.NoError(t, err)
+
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			prototype, err := newOIDCAuthenticator("auth1", pc)
+			require.NoError(t, err)
+
+			// WHEN
+			auth, err := prototype.WithConfig(conf)
+
+			// THEN
+			var (
+				oa *oidcAuthenticator
+				ok bool
+			)
+
+			if err == nil {
+				oa, ok = auth.(*oidcAuthenticator)
+				require.True(t, ok)
+			}
+
+			tc.assert(t, err, prototype, oa)
+		})
+	}
+}
+
+func TestOIDCAuthenticatorExecute(t *testing.T) { //nolint:maintidx
+	t.Parallel()
+
+	const (
+		clientID     = "foo"
+		clientSecret = "bar"
+		appURL       = "https://app.test/foo?bar=baz"
+		callbackURL  = "https://app.test/oidc/callback"
+		logoutURL    = "https://app.test/logout"
+	)
+
+	type HandlerIdentifier interface {
+		ID() string
+	}
+
+	var (
+		tokenEndpoint   func(t *testing.T, w http.ResponseWriter, r *http.Request)
+		rejectedSession string
+	)
+
+	ks := createKS(t)
+	signingKey, err := ks.GetKey(kidKeyWithoutCert)
+	require.NoError(t, err)
+
+	jwks, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{signingKey.JWK()}})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+
+	defer srv.Close()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                 srv.URL,
+			"jwks_uri":               srv.URL + "/jwks",
+			"authorization_endpoint": srv.URL + "/authorize",
+			"token_endpoint":         srv.URL + "/token",
+			"end_session_endpoint":   srv.URL + "/end_session",
+		})
+		require.NoError(t, err)
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write(jwks)
+		require.NoError(t, err)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if tokenEndpoint == nil {
+			t.Errorf("unexpected request to the token endpoint")
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		tokenEndpoint(t, w, r)
+	})
+
+	idToken := func(t *testing.T, nonce string) string {
+		t.Helper()
+
+		return createIDToken(t, signingKey, map[string]any{
+			"sub":   "foo",
+			"iss":   srv.URL,
+			"aud":   []string{clientID},
+			"iat":   time.Now().Unix() - 1,
+			"exp":   time.Now().Unix() + 60,
+			"nonce": nonce,
+		})
+	}
+
+	writeTokenResponse := func(t *testing.T, w http.ResponseWriter, code int, resp map[string]any) {
+		t.Helper()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}
+
+	checkClientAuthentication := func(t *testing.T, r *http.Request) {
+		t.Helper()
+
+		user, password, ok := r.BasicAuth()
+		require.True(t, ok)
+		assert.Equal(t, clientID, user)
+		assert.Equal(t, clientSecret, password)
+		require.NoError(t, r.ParseForm())
+	}
+
+	newFlowCookie := func(t *testing.T, auth *oidcAuthenticator, flow oidcLoginFlow) string {
+		t.Helper()
+
+		value, err := auth.cipher.encrypt(auth.flowCookieName(), &flow)
+		require.NoError(t, err)
+
+		return value
+	}
+
+	newSessionCookie := func(t *testing.T, auth *oidcAuthenticator, appCtx context.Context, sess *oidcSession) string {
+		t.Helper()
+
+		value, err := auth.store.save(appCtx, "", sess)
+		require.NoError(t, err)
+
+		return value
+	}
+
+	validFlow := oidcLoginFlow{
+		State:        "state",
+		Nonce:        "nonce",
+		CodeVerifier: "verifier",
+		RedirectTo:   appURL,
+		Expiry:       time.Now().Add(time.Minute),
+	}
+
+	requireLoginRedirect := func(t *testing.T, err error, auth *oidcAuthenticator) {
+		t.Helper()
+
+		var redirErr *heimdall.RedirectError
+
+		require.ErrorAs(t, err, &redirErr)
+		assert.Equal(t, http.StatusFound, redirErr.Code)
+		assert.Contains(t, redirErr.RedirectTo, srv.URL+"/authorize?")
+		require.Len(t, redirErr.Cookies, 1)
+		assert.Equal(t, auth.flowCookieName(), redirErr.Cookies[0].Name)
+	}
+
+	for _, tc := range []struct {
+		uc            string
+		store         string
+		method        string
+		requestURL    string
+		headers       map[string]string
+		cookies       func(t *testing.T, auth *oidcAuthenticator, appCtx context.Context) map[string]string
+		tokenEndpoint func(t *testing.T, w http.ResponseWriter, r *http.Request)
+		assert        func(t *testing.T, err error, sub *subject.Subject, auth *oidcAuthenticator, appCtx context.Context)
+	}{
+		{
+			uc:         "without session starts login flow",
+			requestURL: appURL,
+			assert: func(t *testing.T, err error, _ *subject.Subject, auth *oidcAuthenticator, _ context.Context) {
+				t.Helper()
+
+				var redirErr *heimdall.RedirectError
+
+				require.ErrorAs(t, err, &redirErr)
+				assert.Equal(t, http.StatusFound, redirErr.Code)
+
+				authURL, err := url.Parse(redirErr.RedirectTo)
+				require.NoError(t, err)
+				assert.Equal(t, srv.URL+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+
+				query := authURL.Query()
+				assert.Equal(t, "code", query.Get("response_type"))
+				assert.Equal(t, clientID, query.Get("client_id"))
+				assert.Equal(t, callbackURL, query.Get("redirect_uri"))
+				assert.Equal(t, "openid profile", query.Get("scope"))
+				assert.Equal(t, "S256", query.Get("code_challenge_method"))
+
+				require.Len(t, redirErr.Cookies, 1)
+				cookie := redirErr.Cookies[0]
+				assert.Equal(t, "heimdall_session_flow", cookie.Name)
+				assert.True(t, cookie.HttpOnly)
+				assert.True(t, cookie.Secure)
+				assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+				assert.Equal(t, int(oidcLoginFlowTTL.Seconds()), cookie.MaxAge)
+
+				var flow oidcLoginFlow
+				require.NoError(t, auth.cipher.decrypt(cookie.Name, cookie.Value, &flow))
+				assert.Equal(t, appURL, flow.RedirectTo)
+				assert.Equal(t, flow.State, query.Get("state"))
+				assert.Equal(t, flow.Nonce, query.Get("nonce"))
+
+				challenge := sha256.Sum256([]byte(flow.CodeVerifier))
+				assert.Equal(t, base64.RawURLEncoding.EncodeToString(challenge[:]), query.Get("code_challenge"))
+			},
+		},
+		{
+			uc:         "with invalid session cookie starts login flow",
+			requestURL: appURL,
+			cookies: func(t *testing.T, _ *oidcAuthenticator, _ context.Context) map[string]string {
+				t.Helper()
+
+				return map[string]string{"heimdall_session": "foo"}
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, auth *oidcAuthenticator, _ context.Context) {
+				t.Helper()
+
+				requireLoginRedirect(t, err, auth)
+			},
+		},
+		{
+			uc:         "with expired session starts login flow",
+			requestURL: appURL,
+			cookies: func(t *testing.T, auth *oidcAuthenticator, appCtx context.Context) map[string]string {
+				t.Helper()
+
+				return map[string]string{"heimdall_session": newSessionCookie(t, auth, appCtx, &oidcSession{
+					Claims: json.RawMessage(`{"sub":"foo"}`),
+					Expiry: time.Now().Add(-time.Minute),
+				})}
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, auth *oidcAuthenticator, _ context.Context) {
+				t.Helper()
+
+				requireLoginRedirect(t, err, auth)
+			},
+		},
+		{
+			uc:         "callback without login flow in progress",
+			requestURL: callbackURL + "?code=foo&state=state",
+			assert: func(t *testing.T, err error, _ *subject.Subject, auth *oidcAuthenticator, _ context.Context) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorContains(t, err, "no login flow in progress")
+
+				var identifier HandlerIdentifier
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, auth.ID(), identifier.ID())
+			},
+		},
+		{
+			uc:         "callback with expired login flow",
+			requestURL: callbackURL + "?code=foo&state=state",
+			cookies: func(t *testing.T, auth *oidcAuthenticator, _ context.Context) map[string]string {
+				t.Helper()
+
+				flow := validFlow
+				flow.Expiry = time.Now().Add(-time.Minute)
+
+				return map[string]string{"heimdall_session_flow": newFlowCookie(t, auth, flow)}
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, _ *oidcAuthenticator, _ context.Context) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorContains(t, err, "invalid or expired")
+			},
+		},
+		{
+			uc:         "callback with login flow cookie bound to another cookie name",
+			requestURL: callbackURL + "?code=foo&state=state",
+			cookies: func(t *testing.T, auth *oidcAuthenticator, _ context.Context) map[string]string {
+				t.Helper()
+
+				value, err := auth.cipher.encrypt("foo", &validFlow)
+				require.NoError(t, err)
+
+				return map[string]string{"heimdall_session_flow": value}
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, _ *oidcAuthenticator, _ context.Context) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorContains(t, err, "invalid or expired")
+			},
+		},
+		{
+			uc:         "callback with error from the authorization server",
+			requestURL: callbackURL + "?error=access_denied&error_description=denied&state=state",
+			cookies: func(t *testing.T, auth *oidcAuthenticator, _ context.Context) map[string]string {
+				t.Helper()
+
+				return map[string]string{"heimdall_session_flow": newFlowCookie(t, auth, validFlow)}
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, _ *oidcAuthenticator, _ context.Context) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorContains(t, err, "authorization request failed")
+
+				var ter *clientcredentials.TokenErrorResponse
+				require.ErrorAs(t, err, &ter)
+				assert.Equal(t, "access_denied", ter.ErrorType)
+				assert.Equal(t, "denied", ter.ErrorDescription)
+			},
+		},
+		{
+			uc:         "callback with state mismatch",
+			requestURL: callbackURL + "?code=foo&state=bar",
+			cookies: func(t *testing.T, auth *oidcAuthenticator, _ context.Context) map[string]string {
+				t.Helper()
+
+				return map[string]string{"heimdall_session_flow": newFlowCookie(t, auth, validFlow)}
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, _ *oidcAuthenticator, _ context.Context) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorContains(t, err, "state parameter mismatch")
+			},
+		},
+		{
+			uc:         "callback without authorization code",
+			requestURL: callbackURL + "?state=state",
+			cookies: func(t *testing.T, auth *oidcAuthenticator, _ context.Context) map[string]string {
+				t.Helper()
+
+				return map[string]string{"heimdall_session_flow": newFlowCookie(t, auth, validFlow)}
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, _ *oidcAuthenticator, _ context.Context) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorContains(t, err, "no authorization code present")
+			},
+		},
+		{
+			uc:         "callback with code rejected by the token endpoint",
+			requestURL: callbackURL + "?code=foo&state=state",
+			cookies: func(t *testing.T, auth *oidcAuthenticator, _ context.Context) map[string]string {
+				t.Helper()
+
+				return map[string]string{"heimdall_session_flow": newFlowCookie(t, auth, validFlow)}
+			},
+			tokenEndpoint: func(t *testing.T, w http.ResponseWriter, _ *http.Request) {
+				t.Helper()
+
+				writeTokenResponse(t, w, http.StatusBadRequest, map[string]any{"error": "invalid_grant"})
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, _ *oidcAuthenticator, _ context.Context) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrCommunication)
+
+				var ter *clientcredentials.TokenErrorResponse
+				require.ErrorAs(t, err, &ter)
+				assert.Equal(t, "invalid_grant", ter.ErrorType)
+			},
+		},
+		{
+			uc:         "callback with token endpoint response without id_token",
+			requestURL: callbackURL + "?code=foo&state=state",
+			cookies: func(t *testing.T, auth *oidcAuthenticator, _ context.Context) map[string]string {
+				t.Helper()
+
+				return map[string]string{"heimdall_session_flow": newFlowCookie(t, auth, validFlow)}
+			},
+			tokenEndpoint: func(t *testing.T, w http.ResponseWriter, _ *http.Request) {
+				t.Helper()
+
+				writeTokenResponse(t, w, http.StatusOK, map[string]any{
+					"access_token": "foo",
+					"token_type":   "Bearer",
+					"expires_in":   300,
+				})
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, _ *oidcAuthenticator, _ context.Context) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorContains(t, err, "does not contain an id_token")
+			},
+		},
+		{
+			uc:         "callback with id_token having a wrong nonce",
+			requestURL: callbackURL + "?code=foo&state=state",
+			cookies: func(t *testing.T, auth *oidcAuthenticator, _ context.Context) map[string]string {
+				t.Helper()
+
+				return map[string]string{"heimdall_session_flow": newFlowCookie(t, auth, validFlow)}
+			},
+			tokenEndpoint: func(t *testing.T, w http.ResponseWriter, _ *http.Request) {
+				t.Helper()
+
+				writeTokenResponse(t, w, http.StatusOK, map[string]any{
+					"access_token": "foo",
+					"token_type":   "Bearer",
+					"expires_in":   300,
+					"id_token":     idToken(t, "foo"),
+				})
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, _ *oidcAuthenticator, _ context.Context) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorContains(t, err, "nonce mismatch")
+			},
+		},
+		{
+			uc:         "successful callback",
+			requestURL: callbackURL + "?code=foo&state=state",
+			cookies: func(t *testing.T, auth *oidcAuthenticator, _ context.Context) map[string]string {
+				t.Helper()
+
+				return map[string]string{"heimdall_session_flow": newFlowCookie(t, auth, validFlow)}
+			},
+			tokenEndpoint: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				t.Helper()
+
+				checkClientAuthentication(t, r)
+				assert.Equal(t, "authorization_code", r.PostForm.Get("grant_type"))
+				assert.Equal(t, "foo", r.PostForm.Get("code"))
+				assert.Equal(t, callbackURL, r.PostForm.Get("redirect_uri"))
+				assert.Equal(t, "verifier", r.PostForm.Get("code_verifier"))
+
+				writeTokenResponse(t, w, http.StatusOK, map[string]any{
+					"access_token":  "foo",
+					"refresh_token": "bar",
+					"token_type":    "Bearer",
+					"expires_in":    300,
+					"id_token":      idToken(t, "nonce"),
+				})
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, auth *oidcAuthenticator, appCtx context.Context) {
+				t.Helper()
+
+				var redirErr *heimdall.RedirectError
+
+				require.ErrorAs(t, err, &redirErr)
+				assert.Equal(t, http.StatusFound, redirErr.Code)
+				assert.Equal(t, appURL, redirErr.RedirectTo)
+				require.Len(t, redirErr.Cookies, 2)
+
+				sessionCookie := redirErr.Cookies[0]
+				assert.Equal(t, "heimdall_session", sessionCookie.Name)
+				assert.True(t, sessionCookie.HttpOnly)
+				assert.InDelta(t, defaultOIDCSessionLifespan.Seconds(), sessionCookie.MaxAge, 5)
+
+				flowCookie := redirErr.Cookies[1]
+				assert.Equal(t, "heimdall_session_flow", flowCookie.Name)
+				assert.Empty(t, flowCookie.Value)
+				assert.Negative(t, flowCookie.MaxAge)
+
+				sess, err := auth.store.load(appCtx, sessionCookie.Value)
+				require.NoError(t, err)
+				assert.Equal(t, "foo", sess.AccessToken)
+				assert.Equal(t, "bar", sess.RefreshToken)
+				assert.NotEmpty(t, sess.IDToken)
+				assert.Equal(t, "foo", gjson.GetBytes(sess.Claims, "sub").String())
+			},
+		},
+		{
+			uc:         "with valid session",
+			requestURL: appURL,
+			cookies: func(t *testing.T, auth *oidcAuthenticator, appCtx context.Context) map[string]string {
+				t.Helper()
+
+				return map[string]string{"heimdall_session": newSessionCookie(t, auth, appCtx, &oidcSession{
+					AccessToken:       "foo",
+					AccessTokenExpiry: time.Now().Add(time.Minute),
+					Claims:            json.RawMessage(`{"sub":"foo","email":"foo@bar.baz"}`),
+					Expiry:            time.Now().Add(time.Hour),
+				})}
+			},
+			assert: func(t *testing.T, err error, sub *subject.Subject, _ *oidcAuthenticator, _ context.Context) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, sub)
+				assert.Equal(t, "foo", sub.ID)
+				assert.Equal(t, "foo@bar.baz", sub.Attributes["email"])
+			},
+		},
+		{
+			uc:         "with valid session in cache",
+			store:      oidcSessionStoreCache,
+			requestURL: appURL,
+			cookies: func(t *testing.T, auth *oidcAuthenticator, appCtx context.Context) map[string]string {
+				t.Helper()
+
+				return map[string]string{"heimdall_session": newSessionCookie(t, auth, appCtx, &oidcSession{
+					AccessToken: "foo",
+					Claims:      json.RawMessage(`{"sub":"foo"}`),
+					Expiry:      time.Now().Add(time.Hour),
+				})}
+			},
+			assert: func(t *testing.T, err error, sub *subject.Subject, _ *oidcAuthenticator, _ context.Context) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, sub)
+				assert.Equal(t, "foo", sub.ID)
+			},
+		},
+		{
+			uc:         "with expired access token and without refresh token",
+			requestURL: appURL,
+			cookies: func(t *testing.T, auth *oidcAuthenticator, appCtx context.Context) map[string]string {
+				t.Helper()
+
+				return map[string]string{"heimdall_session": newSessionCookie(t, auth, appCtx, &oidcSession{
+					AccessToken:       "foo",
+					AccessTokenExpiry: time.Now().Add(-time.Minute),
+					Claims:            json.RawMessage(`{"sub":"foo"}`),
+					Expiry:            time.Now().Add(time.Hour),
+				})}
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, auth *oidcAuthenticator, _ context.Context) {
+				t.Helper()
+
+				requireLoginRedirect(t, err, auth)
+			},
+		},
+		{
+			uc:         "with expired access token and rejected refresh token",
+			store:      oidcSessionStoreCache,
+			requestURL: appURL,
+			cookies: func(t *testing.T, auth *oidcAuthenticator, appCtx context.Context) map[string]string {
+				t.Helper()
+
+				rejectedSession = newSessionCookie(t, auth, appCtx, &oidcSession{
+					AccessToken:       "foo",
+					RefreshToken:      "bar",
+					AccessTokenExpiry: time.Now().Add(-time.Minute),
+					Claims:            json.RawMessage(`{"sub":"foo"}`),
+					Expiry:            time.Now().Add(time.Hour),
+				})
+
+				return map[string]string{"heimdall_session": rejectedSession}
+			},
+			tokenEndpoint: func(t *testing.T, w http.ResponseWriter, _ *http.Request) {
+				t.Helper()
+
+				writeTokenResponse(t, w, http.StatusBadRequest, map[string]any{"error": "invalid_grant"})
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, auth *oidcAuthenticator, appCtx context.Context) {
+				t.Helper()
+
+				requireLoginRedirect(t, err, auth)
+
+				_, err = auth.store.load(appCtx, rejectedSession)
+				require.ErrorIs(t, err, errOIDCSessionExpired)
+			},
+		},
+		{
+			uc:         "with expired access token refreshed in cookie store",
+			requestURL: appURL,
+			cookies: func(t *testing.T, auth *oidcAuthenticator, appCtx context.Context) map[string]string {
+				t.Helper()
+
+				return map[string]string{"heimdall_session": newSessionCookie(t, auth, appCtx, &oidcSession{
+					AccessToken:       "foo",
+					RefreshToken:      "bar",
+					AccessTokenExpiry: time.Now().Add(-time.Minute),
+					Claims:            json.RawMessage(`{"sub":"foo"}`),
+					Expiry:            time.Now().Add(time.Hour),
+				})}
+			},
+			tokenEndpoint: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				t.Helper()
+
+				checkClientAuthentication(t, r)
+				assert.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+				assert.Equal(t, "bar", r.PostForm.Get("refresh_token"))
+
+				writeTokenResponse(t, w, http.StatusOK, map[string]any{
+					"access_token":  "baz",
+					"refresh_token": "zab",
+					"token_type":    "Bearer",
+					"expires_in":    300,
+				})
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, auth *oidcAuthenticator, appCtx context.Context) {
+				t.Helper()
+
+				var redirErr *heimdall.RedirectError
+
+				require.ErrorAs(t, err, &redirErr)
+				assert.Equal(t, http.StatusTemporaryRedirect, redirErr.Code)
+				assert.Equal(t, appURL, redirErr.RedirectTo)
+				require.Len(t, redirErr.Cookies, 1)
+				assert.Equal(t, "heimdall_session", redirErr.Cookies[0].Name)
+
+				sess, err := auth.store.load(appCtx, redirErr.Cookies[0].Value)
+				require.NoError(t, err)
+				assert.Equal(t, "baz", sess.AccessToken)
+				assert.Equal(t, "zab", sess.RefreshToken)
+				assert.True(t, sess.AccessTokenExpiry.After(time.Now()))
+			},
+		},
+		{
+			uc:         "with expired access token refreshed in cache store",
+			store:      oidcSessionStoreCache,
+			requestURL: appURL,
+			cookies: func(t *testing.T, auth *oidcAuthenticator, appCtx context.Context) map[string]string {
+				t.Helper()
+
+				return map[string]string{"heimdall_session": newSessionCookie(t, auth, appCtx, &oidcSession{
+					AccessToken:       "foo",
+					RefreshToken:      "bar",
+					AccessTokenExpiry: time.Now().Add(-time.Minute),
+					Claims:            json.RawMessage(`{"sub":"foo"}`),
+					Expiry:            time.Now().Add(time.Hour),
+				})}
+			},
+			tokenEndpoint: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				t.Helper()
+
+				checkClientAuthentication(t, r)
+				assert.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+
+				writeTokenResponse(t, w, http.StatusOK, map[string]any{
+					"access_token": "baz",
+					"token_type":   "Bearer",
+					"expires_in":   300,
+					"id_token":     idToken(t, ""),
+				})
+			},
+			assert: func(t *testing.T, err error, sub *subject.Subject, _ *oidcAuthenticator, _ context.Context) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, sub)
+				assert.Equal(t, "foo", sub.ID)
+				assert.Equal(t, srv.URL, sub.Attributes["iss"])
+			},
+		},
+		{
+			uc:         "logout using GET",
+			requestURL: logoutURL,
+			assert: func(t *testing.T, err error, _ *subject.Subject, _ *oidcAuthenticator, _ context.Context) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrMethodNotAllowed)
+				require.ErrorContains(t, err, "POST")
+			},
+		},
+		{
+			uc:         "cross origin logout",
+			method:     http.MethodPost,
+			requestURL: logoutURL,
+			headers:    map[string]string{"Origin": "https://evil.test"},
+			assert: func(t *testing.T, err error, _ *subject.Subject, _ *oidcAuthenticator, _ context.Context) {
+				t.Helper()
+
+				require.ErrorIs(t, err, heimdall.ErrAuthorization)
+				require.ErrorContains(t, err, "cross origin")
+			},
+		},
+		{
+			uc:         "logout",
+			method:     http.MethodPost,
+			requestURL: logoutURL,
+			headers:    map[string]string{"Origin": "https://app.test"},
+			cookies: func(t *testing.T, auth *oidcAuthenticator, appCtx context.Context) map[string]string {
+				t.Helper()
+
+				return map[string]string{"heimdall_session": newSessionCookie(t, auth, appCtx, &oidcSession{
+					IDToken:     "foo.bar.baz",
+					AccessToken: "foo",
+					Claims:      json.RawMessage(`{"sub":"foo"}`),
+					Expiry:      time.Now().Add(time.Hour),
+				})}
+			},
+			assert: func(t *testing.T, err error, _ *subject.Subject, _ *oidcAuthenticator, _ context.Context) {
+				t.Helper()
+
+				var redirErr *heimdall.RedirectError
+
+				require.ErrorAs(t, err, &redirErr)
+				assert.Equal(t, http.StatusFound, redirErr.Code)
+
+				endSessionURL, err := url.Parse(redirErr.RedirectTo)
+				require.NoError(t, err)
+				assert.Equal(t, srv.URL+"/end_session",
+					endSessionURL.Scheme+"://"+endSessionURL.Host+endSessionURL.Path)
+				assert.Equal(t, clientID, endSessionURL.Query().Get("client_id"))
+				assert.Equal(t, "foo.bar.baz", endSessionURL.Query().Get("id_token_hint"))
+				assert.Equal(t, "https://app.test/", endSessionURL.Query().Get("post_logout_redirect_uri"))
+
+				require.Len(t, redirErr.Cookies, 1)
+				assert.Equal(t, "heimdall_session", redirErr.Cookies[0].Name)
+				assert.Empty(t, redirErr.Cookies[0].Value)
+				assert.Negative(t, redirErr.Cookies[0].MaxAge)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			tokenEndpoint = tc.tokenEndpoint
+
+			store := tc.store
+			if len(store) == 0 {
+				store = oidcSessionStoreCookie
+			}
+
+			conf, err := testsupport.DecodeTestConfig([]byte(`
+metadata_endpoint:
+  url: ` + srv.URL + `/.well-known/openid-configuration
+client_id: ` + clientID + `
+client_secret: ` + clientSecret + `
+redirect_uri: ` + callbackURL + `
+scopes:
+  - profile
+logout_path: /logout
+post_logout_redirect_uri: https://app.test/
+session:
+  store: ` + store + `
+  secret: ` + oidcTestSecret + `
+`))
+			require.NoError(t, err)
+
+			auth, err := newOIDCAuthenticator("auth1", conf)
+			require.NoError(t, err)
+
+			appCtx := cache.WithContext(context.Background(), memory.New())
+
+			cookies := map[string]string{}
+			if tc.cookies != nil {
+				cookies = tc.cookies(t, auth, appCtx)
+			}
+
+			reqURL, err := url.Parse(tc.requestURL)
+			require.NoError(t, err)
+
+			reqf := heimdallmocks.NewRequestFunctionsMock(t)
+			reqf.EXPECT().Cookie(mock.Anything).RunAndReturn(func(name string) string {
+				return cookies[name]
+			}).Maybe()
+
+			reqf.EXPECT().Header(mock.Anything).RunAndReturn(func(name string) string {
+				return tc.headers[name]
+			}).Maybe()
+
+			ctx := heimdallmocks.NewContextMock(t)
+			ctx.EXPECT().AppContext().Return(appCtx)
+			ctx.EXPECT().Request().Return(&heimdall.Request{
+				RequestFunctions: reqf,
+				Method:           x.IfThenElse(len(tc.method) != 0, tc.method, http.MethodGet),
+				URL:              &heimdall.URL{URL: *reqURL},
+			})
+
+			// WHEN
+			sub, err := auth.Execute(ctx)
+
+			// THEN
+			tc.assert(t, err, sub, auth, appCtx)
+		})
+	}
+}
+
+func createIDToken(t *testing.T, keyEntry *keystore.Entry, claims map[string]any) string {
+	t.Helper()
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: keyEntry.JOSEAlgorithm(), Key: keyEntry.PrivateKey},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", keyEntry.KeyID))
+	require.NoError(t, err)
+
+	rawJwt, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	require.NoError(t, err)
+
+	return rawJwt
+}
@@ -0,0 +1,241 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authenticators
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/stringx"
+)
+
+const (
+	oidcSessionStoreCookie = "cookie"
+	oidcSessionStoreCache  = "cache"
+
+	oidcRandomValueLength = 32
+
+	// most user agents do not accept cookies exceeding 4096 bytes (name and value)
+	oidcMaxCookieSize = 4096
+)
+
+var (
+	errOIDCSessionInvalid  = errors.New("invalid session")
+	errOIDCSessionExpired  = errors.New("session expired")
+	errOIDCSessionTooLarge = errors.New("session too large")
+)
+
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	cache.RegisterValueType("authenticators/oidc/session", &oidcSession{})
+}
+
+type oidcSession struct {
+	IDToken           string          `json:"id_token"`
+	AccessToken       string          `json:"access_token"`
+	RefreshToken      string          `json:"refresh_token,omitempty"`
+	AccessTokenExpiry time.Time       `json:"access_token_expiry"`
+	Claims            json.RawMessage `json:"claims"`
+	Expiry            time.Time       `json:"expiry"`
+}
+
+// oidcSessionStore persists sessions and references these by the value of the session cookie.
+type oidcSessionStore interface {
+	// load returns the session referenced by the given cookie value.
+	load(ctx context.Context, value string) (*oidcSession, error)
+	// save persists the session and returns the value to be used for the session cookie. value is the
+	// value of the current session cookie and is empty for new sessions.
+	save(ctx context.Context, value string, sess *oidcSession) (string, error)
+	// delete removes the session referenced by the given cookie value.
+	delete(ctx context.Context, value string)
+}
+
+// cookieCipher encrypts and authenticates cookie values with AES-GCM using a key derived from the
+// configured secret. The name of the cookie is used as additional data to bind a value to its cookie.
+type cookieCipher struct {
+	aead cipher.AEAD
+}
+
+func newCookieCipher(secret string) (*cookieCipher, error) {
+	key := sha256.Sum256(stringx.ToBytes(secret))
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed to create cookie cipher").
+			CausedBy(err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed to create cookie cipher").
+			CausedBy(err)
+	}
+
+	return &cookieCipher{aead: aead}, nil
+}
+
+func (c *cookieCipher) encrypt(name string, value any) (string, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return "", errorchain.NewWithMessage(heimdall.ErrInternal, "failed to marshal cookie value").
+			CausedBy(err)
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errorchain.NewWithMessage(heimdall.ErrInternal, "failed to generate nonce").
+			CausedBy(err)
+	}
+
+	ciphertext := c.aead.Seal(nonce, nonce, plaintext, stringx.ToBytes(name))
+
+	return base64.RawURLEncoding.EncodeToString(ciphertext), nil
+}
+
+func (c *cookieCipher) decrypt(name, value string, result any) error {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(data) < c.aead.NonceSize() {
+		return errOIDCSessionInvalid
+	}
+
+	nonce, ciphertext := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, stringx.ToBytes(name))
+	if err != nil {
+		return errOIDCSessionInvalid
+	}
+
+	if err = json.Unmarshal(plaintext, result); err != nil {
+		return errOIDCSessionInvalid
+	}
+
+	return nil
+}
+
+// cookieSessionStore keeps the entire session encrypted in the session cookie.
+type cookieSessionStore struct {
+	name   string
+	cipher *cookieCipher
+}
+
+func (s *cookieSessionStore) load(_ context.Context, value string) (*oidcSession, error) {
+	var sess oidcSession
+
+	if err := s.cipher.decrypt(s.name, value, &sess); err != nil {
+		return nil, err
+	}
+
+	if time.Now().After(sess.Expiry) {
+		return nil, errOIDCSessionExpired
+	}
+
+	return &sess, nil
+}
+
+func (s *cookieSessionStore) save(_ context.Context, _ string, sess *oidcSession) (string, error) {
+	value, err := s.cipher.encrypt(s.name, sess)
+	if err != nil {
+		return "", err
+	}
+
+	if len(s.name)+len(value)+1 > oidcMaxCookieSize {
+		return "", errorchain.NewWithMessagef(heimdall.ErrInternal,
+			"session exceeds the max cookie size of %d bytes, use the cache session store instead",
+			oidcMaxCookieSize).CausedBy(errOIDCSessionTooLarge)
+	}
+
+	return value, nil
+}
+
+func (s *cookieSessionStore) delete(_ context.Context, _ string) {}
+
+// cacheSessionStore keeps the session in the configured cache and uses a random identifier
+// as value for the session cookie.
+type cacheSessionStore struct {
+	prefix string
+}
+
+func (s *cacheSessionStore) load(ctx context.Context, value string) (*oidcSession, error) {
+	entry := cache.Ctx(ctx).Get(ctx, s.key(value))
+	if entry == nil {
+		return nil, errOIDCSessionExpired
+	}
+
+	sess, ok := entry.(*oidcSession)
+	if !ok {
+		cache.Ctx(ctx).Delete(ctx, s.key(value))
+
+		return nil, errOIDCSessionInvalid
+	}
+
+	if time.Now().After(sess.Expiry) {
+		return nil, errOIDCSessionExpired
+	}
+
+	return sess, nil
+}
+
+func (s *cacheSessionStore) save(ctx context.Context, value string, sess *oidcSession) (string, error) {
+	if len(value) == 0 {
+		var err error
+
+		if value, err = randomString(); err != nil {
+			return "", err
+		}
+	}
+
+	cache.Ctx(ctx).Set(ctx, s.key(value), sess, time.Until(sess.Expiry))
+
+	return value, nil
+}
+
+func (s *cacheSessionStore) delete(ctx context.Context, value string) {
+	cache.Ctx(ctx).Delete(ctx, s.key(value))
+}
+
+func (s *cacheSessionStore) key(value string) string {
+	digest := sha256.New()
+	digest.Write(stringx.ToBytes(s.prefix))
+	digest.Write(stringx.ToBytes(value))
+
+	return hex.EncodeToString(digest.Sum(nil))
+}
+
+func randomString() (string, error) {
+	buf := make([]byte, oidcRandomValueLength)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		return "", errorchain.NewWithMessage(heimdall.ErrInternal, "failed to generate random value").
+			CausedBy(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
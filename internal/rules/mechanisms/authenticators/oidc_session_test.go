@@ -0,0 +1,253 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authenticators
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/cache/memory"
+	"github.com/dadrus/heimdall/internal/heimdall"
+)
+
+func TestCookieCipher(t *testing.T) {
+	t.Parallel()
+
+	type value struct {
+		Foo string `json:"foo"`
+	}
+
+	for _, tc := range []struct {
+		uc     string
+		modify func(t *testing.T, name, encrypted string) (string, string)
+		assert func(t *testing.T, err error, result value)
+	}{
+		{
+			uc: "successful round trip",
+			modify: func(t *testing.T, name, encrypted string) (string, string) {
+				t.Helper()
+
+				return name, encrypted
+			},
+			assert: func(t *testing.T, err error, result value) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "bar", result.Foo)
+			},
+		},
+		{
+			uc: "value bound to another cookie",
+			modify: func(t *testing.T, _, encrypted string) (string, string) {
+				t.Helper()
+
+				return "bar", encrypted
+			},
+			assert: func(t *testing.T, err error, _ value) {
+				t.Helper()
+
+				require.ErrorIs(t, err, errOIDCSessionInvalid)
+			},
+		},
+		{
+			uc: "tampered value",
+			modify: func(t *testing.T, name, encrypted string) (string, string) {
+				t.Helper()
+
+				raw := []byte(encrypted)
+				if raw[0] == 'A' {
+					raw[0] = 'B'
+				} else {
+					raw[0] = 'A'
+				}
+
+				return name, string(raw)
+			},
+			assert: func(t *testing.T, err error, _ value) {
+				t.Helper()
+
+				require.ErrorIs(t, err, errOIDCSessionInvalid)
+			},
+		},
+		{
+			uc: "not base64 encoded value",
+			modify: func(t *testing.T, name, _ string) (string, string) {
+				t.Helper()
+
+				return name, "!foo"
+			},
+			assert: func(t *testing.T, err error, _ value) {
+				t.Helper()
+
+				require.ErrorIs(t, err, errOIDCSessionInvalid)
+			},
+		},
+		{
+			uc: "too short value",
+			modify: func(t *testing.T, name, _ string) (string, string) {
+				t.Helper()
+
+				return name, "Zm9v"
+			},
+			assert: func(t *testing.T, err error, _ value) {
+				t.Helper()
+
+				require.ErrorIs(t, err, errOIDCSessionInvalid)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			cc, err := newCookieCipher(oidcTestSecret)
+			require.NoError(t, err)
+
+			encrypted, err := cc.encrypt("foo", &value{Foo: "bar"})
+			require.NoError(t, err)
+
+			name, encrypted := tc.modify(t, "foo", encrypted)
+
+			// WHEN
+			var result value
+			err = cc.decrypt(name, encrypted, &result)
+
+			// THEN
+			tc.assert(t, err, result)
+		})
+	}
+}
+
+func TestCookieSessionStore(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	cc, err := newCookieCipher(oidcTestSecret)
+	require.NoError(t, err)
+
+	store := &cookieSessionStore{name: "foo", cipher: cc}
+	ctx := context.Background()
+
+	// WHEN
+	value, err := store.save(ctx, "", &oidcSession{
+		AccessToken: "foo",
+		Claims:      json.RawMessage(`{"sub":"bar"}`),
+		Expiry:      time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+
+	sess, err := store.load(ctx, value)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, "foo", sess.AccessToken)
+	assert.JSONEq(t, `{"sub":"bar"}`, string(sess.Claims))
+
+	// WHEN
+	value, err = store.save(ctx, value, &oidcSession{Expiry: time.Now().Add(-time.Minute)})
+	require.NoError(t, err)
+
+	_, err = store.load(ctx, value)
+
+	// THEN
+	require.ErrorIs(t, err, errOIDCSessionExpired)
+}
+
+func TestCookieSessionStoreWithTooLargeSession(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	cc, err := newCookieCipher(oidcTestSecret)
+	require.NoError(t, err)
+
+	store := &cookieSessionStore{name: "foo", cipher: cc}
+
+	// WHEN
+	_, err = store.save(context.Background(), "", &oidcSession{
+		AccessToken: strings.Repeat("a", oidcMaxCookieSize),
+		Expiry:      time.Now().Add(time.Minute),
+	})
+
+	// THEN
+	require.ErrorIs(t, err, heimdall.ErrInternal)
+	require.ErrorIs(t, err, errOIDCSessionTooLarge)
+	require.ErrorContains(t, err, "cache session store")
+}
+
+func TestCacheSessionStore(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	cch := memory.New()
+	ctx := cache.WithContext(context.Background(), cch)
+	store := &cacheSessionStore{prefix: "foo"}
+
+	// WHEN
+	value, err := store.save(ctx, "", &oidcSession{
+		AccessToken: "foo",
+		Claims:      json.RawMessage(`{"sub":"bar"}`),
+		Expiry:      time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+
+	sess, err := store.load(ctx, value)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, "foo", sess.AccessToken)
+	assert.Nil(t, cch.Get(ctx, value), "the cookie value must not be used as cache key")
+
+	// WHEN
+	updated, err := store.save(ctx, value, &oidcSession{AccessToken: "bar", Expiry: time.Now().Add(time.Minute)})
+	require.NoError(t, err)
+
+	sess, err = store.load(ctx, value)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, value, updated)
+	assert.Equal(t, "bar", sess.AccessToken)
+
+	// WHEN
+	_, err = (&cacheSessionStore{prefix: "bar"}).load(ctx, value)
+
+	// THEN
+	require.ErrorIs(t, err, errOIDCSessionExpired)
+
+	// WHEN
+	cch.Set(ctx, store.key(value), "foo", time.Minute)
+	_, err = store.load(ctx, value)
+
+	// THEN
+	require.ErrorIs(t, err, errOIDCSessionInvalid)
+	assert.Nil(t, cch.Get(ctx, store.key(value)))
+
+	// WHEN
+	value, err = store.save(ctx, "", &oidcSession{Expiry: time.Now().Add(time.Minute)})
+	require.NoError(t, err)
+
+	store.delete(ctx, value)
+	_, err = store.load(ctx, value)
+
+	// THEN
+	require.ErrorIs(t, err, errOIDCSessionExpired)
+}
@@ -0,0 +1,274 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authenticators
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/truststore"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+const spiffeScheme = "spiffe"
+
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	registerTypeFactory(
+		func(id string, typ string, conf map[string]any, _ zerolog.Logger) (bool, Authenticator, error) {
+			if typ != AuthenticatorX509 {
+				return false, nil, nil
+			}
+
+			auth, err := newX509Authenticator(id, conf)
+
+			return true, auth, err
+		})
+
+	cache.RegisterValueType("authenticators/x509/revocation_status", &revocationStatus{})
+	cache.RegisterValueType("authenticators/x509/revocation_list", &revocationList{})
+}
+
+type x509Authenticator struct {
+	id                   string
+	roots                *x509.CertPool
+	rc                   *revocationChecker
+	sf                   SubjectFactory
+	allowFallbackOnError bool
+}
+
+func newX509Authenticator(id string, rawConfig map[string]any) (*x509Authenticator, error) {
+	type Config struct {
+		TrustStore           truststore.TrustStore `mapstructure:"trust_store"             validate:"required"`
+		Subject              *SubjectInfo          `mapstructure:"subject"`
+		Revocation           *RevocationConfig     `mapstructure:"revocation"`
+		AllowFallbackOnError bool                  `mapstructure:"allow_fallback_on_error"`
+	}
+
+	var conf Config
+	if err := decodeConfig(AuthenticatorX509, rawConfig, &conf); err != nil {
+		return nil, err
+	}
+
+	roots := x509.NewCertPool()
+	for _, cert := range conf.TrustStore {
+		roots.AddCert(cert)
+	}
+
+	return &x509Authenticator{
+		id:    id,
+		roots: roots,
+		rc:    newRevocationChecker(conf.Revocation),
+		sf: x.IfThenElseExec(conf.Subject != nil,
+			func() *SubjectInfo { return conf.Subject },
+			func() *SubjectInfo { return &SubjectInfo{IDFrom: "subject.dn"} }),
+		allowFallbackOnError: conf.AllowFallbackOnError,
+	}, nil
+}
+
+func (a *x509Authenticator) Execute(ctx heimdall.Context) (*subject.Subject, error) {
+	logger := zerolog.Ctx(ctx.AppContext())
+	logger.Debug().Str("_id", a.id).Msg("Authenticating using x509 authenticator")
+
+	certs := ctx.Request().ClientCertificates()
+	if len(certs) == 0 {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrAuthentication, "no client certificate present").
+			WithErrorContext(a).
+			CausedBy(heimdall.ErrArgument)
+	}
+
+	chain, err := a.verifyChain(certs)
+	if err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrAuthentication, "client certificate validation failed").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	if a.rc != nil {
+		if err = a.rc.check(ctx.AppContext(), chain); err != nil {
+			return nil, errorchain.
+				NewWithMessage(heimdall.ErrAuthentication,
+					x.IfThenElse(errors.Is(err, errCertificateRevoked),
+						"client certificate is revoked",
+						"failed to verify revocation status of the client certificate")).
+				WithErrorContext(a).
+				CausedBy(err)
+		}
+	}
+
+	rawData, err := json.Marshal(newCertificateInfo(certs[0]))
+	if err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrInternal, "failed to marshal client certificate information").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	sub, err := a.sf.CreateSubject(rawData)
+	if err != nil {
+		return nil, errorchain.
+			NewWithMessage(heimdall.ErrInternal, "failed to extract subject information from client certificate").
+			WithErrorContext(a).
+			CausedBy(err)
+	}
+
+	return sub, nil
+}
+
+func (a *x509Authenticator) WithConfig(rawConfig map[string]any) (Authenticator, error) {
+	// this authenticator allows revocation checking and fallback to be redefined on the rule level
+	if len(rawConfig) == 0 {
+		return a, nil
+	}
+
+	type Config struct {
+		Revocation           *RevocationConfig `mapstructure:"revocation"`
+		AllowFallbackOnError *bool             `mapstructure:"allow_fallback_on_error"`
+	}
+
+	var conf Config
+	if err := decodeConfig(AuthenticatorX509, rawConfig, &conf); err != nil {
+		return nil, err
+	}
+
+	return &x509Authenticator{
+		id:    a.id,
+		roots: a.roots,
+		rc: x.IfThenElseExec(conf.Revocation != nil,
+			func() *revocationChecker { return newRevocationChecker(conf.Revocation) },
+			func() *revocationChecker { return a.rc }),
+		sf: a.sf,
+		allowFallbackOnError: x.IfThenElseExec(conf.AllowFallbackOnError != nil,
+			func() bool { return *conf.AllowFallbackOnError },
+			func() bool { return a.allowFallbackOnError }),
+	}, nil
+}
+
+func (a *x509Authenticator) IsFallbackOnErrorAllowed() bool {
+	return a.allowFallbackOnError
+}
+
+func (a *x509Authenticator) ID() string {
+	return a.id
+}
+
+func (a *x509Authenticator) verifyChain(certs []*x509.Certificate) ([]*x509.Certificate, error) {
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	chains, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         a.roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return chains[0], nil
+}
+
+type certificateName struct {
+	DN                 string   `json:"dn"`
+	CommonName         string   `json:"common_name,omitempty"`
+	SerialNumber       string   `json:"serial_number,omitempty"`
+	Organization       []string `json:"organization,omitempty"`
+	OrganizationalUnit []string `json:"organizational_unit,omitempty"`
+	Country            []string `json:"country,omitempty"`
+	Province           []string `json:"province,omitempty"`
+	Locality           []string `json:"locality,omitempty"`
+}
+
+type certificateInfo struct {
+	Subject        certificateName `json:"subject"`
+	Issuer         certificateName `json:"issuer"`
+	SerialNumber   string          `json:"serial_number"`
+	NotBefore      int64           `json:"not_before"`
+	NotAfter       int64           `json:"not_after"`
+	Fingerprint    string          `json:"fingerprint"`
+	DNSNames       []string        `json:"dns_names,omitempty"`
+	EmailAddresses []string        `json:"email_addresses,omitempty"`
+	IPAddresses    []string        `json:"ip_addresses,omitempty"`
+	URIs           []string        `json:"uris,omitempty"`
+	SPIFFEID       string          `json:"spiffe_id,omitempty"`
+}
+
+func newCertificateName(name pkix.Name) certificateName {
+	return certificateName{
+		DN:                 name.String(),
+		CommonName:         name.CommonName,
+		SerialNumber:       name.SerialNumber,
+		Organization:       name.Organization,
+		OrganizationalUnit: name.OrganizationalUnit,
+		Country:            name.Country,
+		Province:           name.Province,
+		Locality:           name.Locality,
+	}
+}
+
+func newCertificateInfo(cert *x509.Certificate) certificateInfo {
+	fingerprint := sha256.Sum256(cert.Raw)
+
+	info := certificateInfo{
+		Subject:        newCertificateName(cert.Subject),
+		Issuer:         newCertificateName(cert.Issuer),
+		SerialNumber:   cert.SerialNumber.String(),
+		NotBefore:      cert.NotBefore.Unix(),
+		NotAfter:       cert.NotAfter.Unix(),
+		Fingerprint:    hex.EncodeToString(fingerprint[:]),
+		DNSNames:       cert.DNSNames,
+		EmailAddresses: cert.EmailAddresses,
+	}
+
+	for _, ip := range cert.IPAddresses {
+		info.IPAddresses = append(info.IPAddresses, ip.String())
+	}
+
+	var spiffeIDs []string
+
+	for _, uri := range cert.URIs {
+		info.URIs = append(info.URIs, uri.String())
+
+		if uri.Scheme == spiffeScheme {
+			spiffeIDs = append(spiffeIDs, uri.String())
+		}
+	}
+
+	// according to the SPIFFE X509-SVID specification, an SVID contains exactly one
+	// URI SAN with the spiffe scheme
+	if len(spiffeIDs) == 1 {
+		info.SPIFFEID = spiffeIDs[0]
+	}
+
+	return info
+}
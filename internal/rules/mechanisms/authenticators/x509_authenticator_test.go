@@ -0,0 +1,533 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authenticators
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/cache/memory"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/x/pkix/pemx"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func createX509TrustStoreFile(t *testing.T, certs ...*x509.Certificate) string {
+	t.Helper()
+
+	opts := make([]pemx.EntryOption, len(certs))
+	for idx, cert := range certs {
+		opts[idx] = pemx.WithX509Certificate(cert)
+	}
+
+	pemBytes, err := pemx.BuildPEM(opts...)
+	require.NoError(t, err)
+
+	file, err := os.Create(t.TempDir() + "/trust_store.pem")
+	require.NoError(t, err)
+
+	_, err = file.Write(pemBytes)
+	require.NoError(t, err)
+
+	require.NoError(t, file.Close())
+
+	return file.Name()
+}
+
+func TestCreateX509Authenticator(t *testing.T) {
+	t.Parallel()
+
+	rootCA, err := testsupport.NewRootCA("Test Root CA", 24*time.Hour)
+	require.NoError(t, err)
+
+	trustStorePath := createX509TrustStoreFile(t, rootCA.Certificate)
+
+	for _, tc := range []struct {
+		uc     string
+		id     string
+		config []byte
+		assert func(t *testing.T, err error, auth *x509Authenticator)
+	}{
+		{
+			uc: "without trust store",
+			config: []byte(`
+allow_fallback_on_error: true
+`),
+			assert: func(t *testing.T, err error, auth *x509Authenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'trust_store' is a required field")
+			},
+		},
+		{
+			uc: "with not existing trust store",
+			config: []byte(`
+trust_store: /no/such/file.pem
+`),
+			assert: func(t *testing.T, err error, auth *x509Authenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed decoding")
+			},
+		},
+		{
+			uc: "with unsupported properties",
+			config: []byte(`
+trust_store: ` + trustStorePath + `
+foo: bar
+`),
+			assert: func(t *testing.T, err error, auth *x509Authenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed decoding")
+			},
+		},
+		{
+			uc: "with revocation config without any revocation mechanism enabled",
+			config: []byte(`
+trust_store: ` + trustStorePath + `
+revocation:
+  soft_fail: true
+`),
+			assert: func(t *testing.T, err error, auth *x509Authenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'ocsp' is a required field")
+			},
+		},
+		{
+			uc: "with minimal valid configuration",
+			id: "auth1",
+			config: []byte(`
+trust_store: ` + trustStorePath + `
+`),
+			assert: func(t *testing.T, err error, auth *x509Authenticator) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, auth)
+
+				assert.Equal(t, "auth1", auth.ID())
+				assert.NotNil(t, auth.roots)
+				assert.Nil(t, auth.rc)
+				assert.Equal(t, &SubjectInfo{IDFrom: "subject.dn"}, auth.sf)
+				assert.False(t, auth.IsFallbackOnErrorAllowed())
+			},
+		},
+		{
+			uc: "with full configuration",
+			id: "auth2",
+			config: []byte(`
+trust_store: ` + trustStorePath + `
+subject:
+  id: spiffe_id
+  attributes: subject
+revocation:
+  crl: true
+  ocsp: true
+  soft_fail: true
+allow_fallback_on_error: true
+`),
+			assert: func(t *testing.T, err error, auth *x509Authenticator) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, auth)
+
+				assert.Equal(t, "auth2", auth.ID())
+				assert.NotNil(t, auth.roots)
+				assert.Equal(t, &revocationChecker{useOCSP: true, useCRL: true, softFail: true}, auth.rc)
+				assert.Equal(t, &SubjectInfo{IDFrom: "spiffe_id", AttributesFrom: "subject"}, auth.sf)
+				assert.True(t, auth.IsFallbackOnErrorAllowed())
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			// WHEN
+			auth, err := newX509Authenticator(tc.id, conf)
+
+			// THEN
+			tc.assert(t, err, auth)
+		})
+	}
+}
+
+func TestCreateX509AuthenticatorFromPrototype(t *testing.T) {
+	t.Parallel()
+
+	rootCA, err := testsupport.NewRootCA("Test Root CA", 24*time.Hour)
+	require.NoError(t, err)
+
+	trustStorePath := createX509TrustStoreFile(t, rootCA.Certificate)
+
+	for _, tc := range []struct {
+		uc              string
+		prototypeConfig []byte
+		config          []byte
+		assert          func(t *testing.T, err error, prototype *x509Authenticator, configured *x509Authenticator)
+	}{
+		{
+			uc: "without new configuration",
+			prototypeConfig: []byte(`
+trust_store: ` + trustStorePath + `
+`),
+			assert: func(t *testing.T, err error, prototype *x509Authenticator, configured *x509Authenticator) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, prototype, configured)
+			},
+		},
+		{
+			uc: "with revocation checking and fallback reconfigured",
+			prototypeConfig: []byte(`
+trust_store: ` + trustStorePath + `
+revocation:
+  crl: true
+`),
+			config: []byte(`
+revocation:
+  ocsp: true
+allow_fallback_on_error: true
+`),
+			assert: func(t *testing.T, err error, prototype *x509Authenticator, configured *x509Authenticator) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.NotEqual(t, prototype, configured)
+				assert.Equal(t, prototype.ID(), configured.ID())
+				assert.Equal(t, prototype.roots, configured.roots)
+				assert.Equal(t, prototype.sf, configured.sf)
+				assert.Equal(t, &revocationChecker{useCRL: true}, prototype.rc)
+				assert.Equal(t, &revocationChecker{useOCSP: true}, configured.rc)
+				assert.False(t, prototype.IsFallbackOnErrorAllowed())
+				assert.True(t, configured.IsFallbackOnErrorAllowed())
+			},
+		},
+		{
+			uc: "with trust store reconfigured",
+			prototypeConfig: []byte(`
+trust_store: ` + trustStorePath + `
+`),
+			config: []byte(`
+trust_store: ` + trustStorePath + `
+`),
+			assert: func(t *testing.T, err error, prototype *x509Authenticator, configured *x509Authenticator) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed decoding")
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			pc, err := testsupport.DecodeTestConfig(tc.prototypeConfig)
+			require.NoError(t, err)
+
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			prototype, err := newX509Authenticator("auth1", pc)
+			require.NoError(t, err)
+
+			// WHEN
+			auth, err := prototype.WithConfig(conf)
+
+			// THEN
+			var (
+				xa *x509Authenticator
+				ok bool
+			)
+
+			if err == nil {
+				xa, ok = auth.(*x509Authenticator)
+				require.True(t, ok)
+			}
+
+			tc.assert(t, err, prototype, xa)
+		})
+	}
+}
+
+func TestX509AuthenticatorExecute(t *testing.T) {
+	t.Parallel()
+
+	type HandlerIdentifier interface {
+		ID() string
+	}
+
+	pki := newTestPKI(t)
+	trustStorePath := createX509TrustStoreFile(t, pki.rootCA.Certificate)
+
+	spiffeID, err := url.Parse("spiffe://example.org/ns/default/sa/client")
+	require.NoError(t, err)
+
+	clientCert := pki.issue(t, "Test Client",
+		testsupport.WithURIs([]*url.URL{spiffeID}),
+		testsupport.WithDNSNames([]string{"client.example.org"}),
+		testsupport.WithIPAddresses([]net.IP{net.ParseIP("127.0.0.1")}),
+		testsupport.WithEMailAddresses([]string{"client@example.org"}),
+		testsupport.WithCRLDistributionPoints([]string{pki.srv.URL + "/int/crl"}))
+
+	revokedCert := pki.issue(t, "Revoked Client",
+		testsupport.WithOCSPServers([]string{pki.srv.URL + "/int/ocsp"}))
+	pki.revoke(revokedCert)
+
+	serverPrivKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serverCert, err := pki.intCA.IssueCertificate(
+		testsupport.WithSubject(pkix.Name{CommonName: "Test Server"}),
+		testsupport.WithValidity(time.Now(), 24*time.Hour),
+		testsupport.WithSubjectPubKey(&serverPrivKey.PublicKey, x509.ECDSAWithSHA256),
+		testsupport.WithExtendedKeyUsage(x509.ExtKeyUsageServerAuth))
+	require.NoError(t, err)
+
+	foreignCA, err := testsupport.NewRootCA("Foreign Root CA", 24*time.Hour)
+	require.NoError(t, err)
+
+	foreignPrivKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	foreignCert, err := foreignCA.IssueCertificate(
+		testsupport.WithSubject(pkix.Name{CommonName: "Foreign Client"}),
+		testsupport.WithValidity(time.Now(), 24*time.Hour),
+		testsupport.WithSubjectPubKey(&foreignPrivKey.PublicKey, x509.ECDSAWithSHA256),
+		testsupport.WithExtendedKeyUsage(x509.ExtKeyUsageClientAuth))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		uc     string
+		config []byte
+		certs  []*x509.Certificate
+		assert func(t *testing.T, err error, sub *subject.Subject)
+	}{
+		{
+			uc: "no client certificate present",
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				assert.Contains(t, err.Error(), "no client certificate present")
+
+				var identifier HandlerIdentifier
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "x509_auth", identifier.ID())
+
+				assert.Nil(t, sub)
+			},
+		},
+		{
+			uc:    "client certificate issued by a not trusted CA",
+			certs: []*x509.Certificate{foreignCert, foreignCA.Certificate},
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				assert.Contains(t, err.Error(), "client certificate validation failed")
+
+				var identifier HandlerIdentifier
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "x509_auth", identifier.ID())
+
+				assert.Nil(t, sub)
+			},
+		},
+		{
+			uc:    "client certificate without intermediate CA certificate",
+			certs: []*x509.Certificate{clientCert},
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				assert.Contains(t, err.Error(), "client certificate validation failed")
+
+				assert.Nil(t, sub)
+			},
+		},
+		{
+			uc:    "certificate not usable for client authentication",
+			certs: []*x509.Certificate{serverCert, pki.intCA.Certificate},
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				assert.Contains(t, err.Error(), "client certificate validation failed")
+
+				assert.Nil(t, sub)
+			},
+		},
+		{
+			uc: "revoked client certificate",
+			config: []byte(`
+revocation:
+  ocsp: true
+  crl: true
+`),
+			certs: []*x509.Certificate{revokedCert, pki.intCA.Certificate},
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				assert.Contains(t, err.Error(), "client certificate is revoked")
+
+				assert.Nil(t, sub)
+			},
+		},
+		{
+			uc: "revocation status cannot be determined",
+			config: []byte(`
+revocation:
+  crl: true
+`),
+			certs: []*x509.Certificate{revokedCert, pki.intCA.Certificate},
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrAuthentication)
+				assert.Contains(t, err.Error(), "failed to verify revocation status")
+
+				assert.Nil(t, sub)
+			},
+		},
+		{
+			uc: "subject id cannot be extracted",
+			config: []byte(`
+subject:
+  id: spiffe_id
+`),
+			certs: []*x509.Certificate{revokedCert, pki.intCA.Certificate},
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				assert.Contains(t, err.Error(), "failed to extract subject information")
+
+				assert.Nil(t, sub)
+			},
+		},
+		{
+			uc: "successful with default subject and revocation check",
+			config: []byte(`
+revocation:
+  crl: true
+`),
+			certs: []*x509.Certificate{clientCert, pki.intCA.Certificate},
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, sub)
+
+				assert.Equal(t, "CN=Test Client,O=Test,C=EU", sub.ID)
+				assert.Equal(t, "spiffe://example.org/ns/default/sa/client", sub.Attributes["spiffe_id"])
+				assert.Equal(t, []any{"client.example.org"}, sub.Attributes["dns_names"])
+				assert.Equal(t, []any{"127.0.0.1"}, sub.Attributes["ip_addresses"])
+				assert.Equal(t, []any{"client@example.org"}, sub.Attributes["email_addresses"])
+				assert.Equal(t, []any{"spiffe://example.org/ns/default/sa/client"}, sub.Attributes["uris"])
+				assert.Equal(t, clientCert.SerialNumber.String(), sub.Attributes["serial_number"])
+				assert.Len(t, sub.Attributes["fingerprint"], 64)
+
+				subjectName, ok := sub.Attributes["subject"].(map[string]any)
+				require.True(t, ok)
+				assert.Equal(t, "CN=Test Client,O=Test,C=EU", subjectName["dn"])
+				assert.Equal(t, "Test Client", subjectName["common_name"])
+				assert.Equal(t, []any{"Test"}, subjectName["organization"])
+
+				issuerName, ok := sub.Attributes["issuer"].(map[string]any)
+				require.True(t, ok)
+				assert.Equal(t, "CN=Test Int CA,O=Test,C=EU", issuerName["dn"])
+			},
+		},
+		{
+			uc: "successful with SPIFFE ID as subject id",
+			config: []byte(`
+subject:
+  id: spiffe_id
+  attributes: subject
+`),
+			certs: []*x509.Certificate{clientCert, pki.intCA.Certificate, pki.rootCA.Certificate},
+			assert: func(t *testing.T, err error, sub *subject.Subject) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, sub)
+
+				assert.Equal(t, "spiffe://example.org/ns/default/sa/client", sub.ID)
+				assert.Equal(t, "Test Client", sub.Attributes["common_name"])
+				assert.Equal(t, "CN=Test Client,O=Test,C=EU", sub.Attributes["dn"])
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			conf, err := testsupport.DecodeTestConfig(append([]byte("trust_store: "+trustStorePath+"\n"), tc.config...))
+			require.NoError(t, err)
+
+			auth, err := newX509Authenticator("x509_auth", conf)
+			require.NoError(t, err)
+
+			fnt := mocks.NewRequestFunctionsMock(t)
+			fnt.EXPECT().ClientCertificates().Return(tc.certs)
+
+			ctx := mocks.NewContextMock(t)
+			ctx.EXPECT().AppContext().Return(cache.WithContext(context.Background(), memory.New()))
+			ctx.EXPECT().Request().Return(&heimdall.Request{RequestFunctions: fnt})
+
+			// WHEN
+			sub, err := auth.Execute(ctx)
+
+			// THEN
+			tc.assert(t, err, sub)
+		})
+	}
+}
@@ -0,0 +1,369 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authenticators
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/endpoint"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/stringx"
+)
+
+const (
+	defaultRevocationStatusCacheTTL = 5 * time.Minute
+
+	maxOCSPResponseSize = 64 * 1024        //nolint:gomnd
+	maxCRLSize          = 16 * 1024 * 1024 //nolint:gomnd
+)
+
+var (
+	errCertificateRevoked            = errors.New("certificate revoked")
+	errRevocationStatusUnknown       = errors.New("revocation status could not be determined")
+	errNoRevocationDataLocation      = errors.New("no applicable OCSP responder or CRL distribution point present")
+	errUnsupportedRevocationLocation = errors.New("unsupported revocation data location")
+	errRevocationDataTooLarge        = errors.New("revocation data exceeds the allowed size")
+	errRevocationDataOutdated        = errors.New("revocation data is outdated")
+	errOCSPStatusUnknown             = errors.New("OCSP responder does not know the certificate")
+)
+
+type RevocationConfig struct {
+	OCSP     bool `mapstructure:"ocsp"      validate:"required_without=CRL"`
+	CRL      bool `mapstructure:"crl"`
+	SoftFail bool `mapstructure:"soft_fail"`
+}
+
+type revocationStatus struct {
+	Revoked bool
+}
+
+// revocationList holds the relevant parts of a CRL, which has already been verified.
+// Serial numbers of the revoked certificates are hex encoded.
+type revocationList struct {
+	RevokedSerials map[string]bool `json:"revoked_serials"`
+	NextUpdate     time.Time       `json:"next_update"`
+}
+
+type revocationChecker struct {
+	useOCSP  bool
+	useCRL   bool
+	softFail bool
+}
+
+func newRevocationChecker(conf *RevocationConfig) *revocationChecker {
+	if conf == nil {
+		return nil
+	}
+
+	return &revocationChecker{
+		useOCSP:  conf.OCSP,
+		useCRL:   conf.CRL,
+		softFail: conf.SoftFail,
+	}
+}
+
+// check verifies the revocation status of all certificates in the given verified chain,
+// except the trust anchor, which is expected to be the last element.
+func (rc *revocationChecker) check(ctx context.Context, chain []*x509.Certificate) error {
+	logger := zerolog.Ctx(ctx)
+
+	for idx := 0; idx < len(chain)-1; idx++ {
+		cert, issuer := chain[idx], chain[idx+1]
+
+		status, err := rc.status(ctx, cert, issuer)
+		if err != nil {
+			if !rc.softFail {
+				return err
+			}
+
+			logger.Warn().Err(err).
+				Msgf("Ignoring unknown revocation status of certificate with DN='%s' and SN=%s",
+					cert.Subject.String(), cert.SerialNumber.String())
+
+			continue
+		}
+
+		if status.Revoked {
+			return fmt.Errorf("%w: DN='%s', SN=%s",
+				errCertificateRevoked, cert.Subject.String(), cert.SerialNumber.String())
+		}
+	}
+
+	return nil
+}
+
+func (rc *revocationChecker) status(
+	ctx context.Context, cert, issuer *x509.Certificate,
+) (*revocationStatus, error) {
+	cch := cache.Ctx(ctx)
+	logger := zerolog.Ctx(ctx)
+	cacheKey := rc.calculateCacheKey(cert, issuer)
+
+	if entry := cch.Get(ctx, cacheKey); entry != nil {
+		if status, ok := entry.(*revocationStatus); ok {
+			logger.Debug().Msg("Reusing revocation status from cache")
+
+			return status, nil
+		}
+
+		logger.Warn().Msg("Wrong object type from cache")
+		cch.Delete(ctx, cacheKey)
+	}
+
+	var (
+		status     *revocationStatus
+		nextUpdate time.Time
+		errs       []error
+		err        error
+	)
+
+	if rc.useOCSP && len(cert.OCSPServer) != 0 {
+		if status, nextUpdate, err = rc.ocspStatus(ctx, cert, issuer); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if status == nil && rc.useCRL && len(cert.CRLDistributionPoints) != 0 {
+		if status, nextUpdate, err = rc.crlStatus(ctx, cert, issuer); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if status == nil {
+		if len(errs) == 0 {
+			errs = append(errs, errNoRevocationDataLocation)
+		}
+
+		return nil, fmt.Errorf("%w for certificate with DN='%s' and SN=%s: %w",
+			errRevocationStatusUnknown, cert.Subject.String(), cert.SerialNumber.String(), errors.Join(errs...))
+	}
+
+	ttl := defaultRevocationStatusCacheTTL
+	if !nextUpdate.IsZero() {
+		ttl = time.Until(nextUpdate)
+	}
+
+	if ttl > 0 {
+		cch.Set(ctx, cacheKey, status, ttl)
+	}
+
+	return status, nil
+}
+
+func (rc *revocationChecker) ocspStatus(
+	ctx context.Context, cert, issuer *x509.Certificate,
+) (*revocationStatus, time.Time, error) {
+	ocspReq, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to create OCSP request: %w", err)
+	}
+
+	var errs []error
+
+	for _, server := range cert.OCSPServer {
+		raw, err := fetchRevocationData(ctx, endpoint.Endpoint{
+			URL:     server,
+			Method:  http.MethodPost,
+			Headers: map[string]string{"Content-Type": "application/ocsp-request"},
+		}, ocspReq, maxOCSPResponseSize)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		resp, err := ocsp.ParseResponseForCert(raw, cert, issuer)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid OCSP response from %s: %w", server, err))
+
+			continue
+		}
+
+		if rc.outdated(resp.NextUpdate) {
+			errs = append(errs, fmt.Errorf("%w: OCSP response from %s", errRevocationDataOutdated, server))
+
+			continue
+		}
+
+		switch resp.Status {
+		case ocsp.Good:
+			return &revocationStatus{Revoked: false}, resp.NextUpdate, nil
+		case ocsp.Revoked:
+			return &revocationStatus{Revoked: true}, resp.NextUpdate, nil
+		default:
+			errs = append(errs, fmt.Errorf("%w: %s", errOCSPStatusUnknown, server))
+		}
+	}
+
+	return nil, time.Time{}, errors.Join(errs...)
+}
+
+func (rc *revocationChecker) crlStatus(
+	ctx context.Context, cert, issuer *x509.Certificate,
+) (*revocationStatus, time.Time, error) {
+	var errs []error
+
+	for _, distributionPoint := range cert.CRLDistributionPoints {
+		crl, err := rc.revocationList(ctx, distributionPoint, issuer)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		return &revocationStatus{Revoked: crl.RevokedSerials[serialNumberKey(cert.SerialNumber)]}, crl.NextUpdate, nil
+	}
+
+	return nil, time.Time{}, errors.Join(errs...)
+}
+
+// revocationList returns the CRL from the given distribution point, verified to be issued by the given
+// issuer. As CRLs can become large, these are cached until their next update is due.
+func (rc *revocationChecker) revocationList(
+	ctx context.Context, distributionPoint string, issuer *x509.Certificate,
+) (*revocationList, error) {
+	cch := cache.Ctx(ctx)
+	logger := zerolog.Ctx(ctx)
+	cacheKey := rc.calculateCRLCacheKey(distributionPoint, issuer)
+
+	if entry := cch.Get(ctx, cacheKey); entry != nil {
+		if crl, ok := entry.(*revocationList); ok && !rc.outdated(crl.NextUpdate) {
+			logger.Debug().Msgf("Reusing CRL from %s from cache", distributionPoint)
+
+			return crl, nil
+		}
+
+		cch.Delete(ctx, cacheKey)
+	}
+
+	raw, err := fetchRevocationData(ctx,
+		endpoint.Endpoint{URL: distributionPoint, Method: http.MethodGet}, nil, maxCRLSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if block, _ := pem.Decode(raw); block != nil {
+		raw = block.Bytes
+	}
+
+	parsed, err := x509.ParseRevocationList(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CRL from %s: %w", distributionPoint, err)
+	}
+
+	if err = parsed.CheckSignatureFrom(issuer); err != nil {
+		return nil, fmt.Errorf("CRL from %s is not signed by the issuer: %w", distributionPoint, err)
+	}
+
+	if rc.outdated(parsed.NextUpdate) {
+		return nil, fmt.Errorf("%w: CRL from %s", errRevocationDataOutdated, distributionPoint)
+	}
+
+	crl := &revocationList{
+		RevokedSerials: make(map[string]bool, len(parsed.RevokedCertificateEntries)),
+		NextUpdate:     parsed.NextUpdate,
+	}
+
+	for _, entry := range parsed.RevokedCertificateEntries {
+		crl.RevokedSerials[serialNumberKey(entry.SerialNumber)] = true
+	}
+
+	ttl := defaultRevocationStatusCacheTTL
+	if !crl.NextUpdate.IsZero() {
+		ttl = time.Until(crl.NextUpdate)
+	}
+
+	cch.Set(ctx, cacheKey, crl, ttl)
+
+	return crl, nil
+}
+
+func (rc *revocationChecker) outdated(nextUpdate time.Time) bool {
+	return !nextUpdate.IsZero() && time.Now().After(nextUpdate)
+}
+
+func (rc *revocationChecker) calculateCacheKey(cert, issuer *x509.Certificate) string {
+	digest := sha256.New()
+	digest.Write(issuer.RawSubject)
+	digest.Write(issuer.RawSubjectPublicKeyInfo)
+	digest.Write(cert.SerialNumber.Bytes())
+
+	return hex.EncodeToString(digest.Sum(nil))
+}
+
+func (rc *revocationChecker) calculateCRLCacheKey(distributionPoint string, issuer *x509.Certificate) string {
+	digest := sha256.New()
+	digest.Write(stringx.ToBytes("crl"))
+	digest.Write(stringx.ToBytes(distributionPoint))
+	digest.Write(issuer.RawSubject)
+	digest.Write(issuer.RawSubjectPublicKeyInfo)
+
+	return hex.EncodeToString(digest.Sum(nil))
+}
+
+func serialNumberKey(serial *big.Int) string {
+	return serial.Text(16) //nolint:gomnd
+}
+
+func fetchRevocationData(ctx context.Context, ep endpoint.Endpoint, body []byte, maxSize int64) ([]byte, error) {
+	// only http based distribution points and responders are supported
+	if parsed, err := url.Parse(ep.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, fmt.Errorf("%w: %s", errUnsupportedRevocationLocation, ep.URL)
+	}
+
+	raw, err := ep.SendRequest(ctx, bytes.NewReader(body), nil, func(resp *http.Response) ([]byte, error) {
+		if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+			return nil, errorchain.
+				NewWithMessagef(heimdall.ErrCommunication, "unexpected response code: %v", resp.StatusCode)
+		}
+
+		// read one byte more than allowed to detect oversized responses
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+		if err != nil {
+			return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed to read response").
+				CausedBy(err)
+		}
+
+		if int64(len(data)) > maxSize {
+			return nil, fmt.Errorf("%w: max %d bytes", errRevocationDataTooLarge, maxSize)
+		}
+
+		return data, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch revocation data from %s: %w", ep.URL, err)
+	}
+
+	return raw, nil
+}
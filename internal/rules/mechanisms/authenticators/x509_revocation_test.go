@@ -0,0 +1,464 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authenticators
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/cache/memory"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+// testPKI is a two level PKI, with a root and an intermediate CA, serving CRLs and OCSP
+// responses for the issued certificates from a local http server.
+type testPKI struct {
+	rootCA *testsupport.CA
+	intCA  *testsupport.CA
+	srv    *httptest.Server
+
+	mu       sync.Mutex
+	revoked  map[string]bool
+	requests atomic.Int32
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+
+	pki := &testPKI{revoked: make(map[string]bool)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/root/crl", func(rw http.ResponseWriter, _ *http.Request) {
+		pki.writeCRL(t, rw, pki.rootCA)
+	})
+	mux.HandleFunc("/int/crl", func(rw http.ResponseWriter, _ *http.Request) {
+		pki.writeCRL(t, rw, pki.intCA)
+	})
+	mux.HandleFunc("/int/ocsp", func(rw http.ResponseWriter, req *http.Request) {
+		pki.writeOCSPResponse(t, rw, req, pki.intCA)
+	})
+	mux.HandleFunc("/broken", func(rw http.ResponseWriter, _ *http.Request) {
+		pki.requests.Add(1)
+		rw.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/large/crl", func(rw http.ResponseWriter, _ *http.Request) {
+		pki.requests.Add(1)
+		_, err := rw.Write(make([]byte, maxCRLSize+1))
+		require.NoError(t, err)
+	})
+	mux.HandleFunc("/foreign/crl", func(rw http.ResponseWriter, _ *http.Request) {
+		foreignCA, err := testsupport.NewRootCA("Foreign CA", 24*time.Hour)
+		require.NoError(t, err)
+
+		pki.writeCRL(t, rw, foreignCA)
+	})
+
+	pki.srv = httptest.NewServer(mux)
+	t.Cleanup(pki.srv.Close)
+
+	rootCA, err := testsupport.NewRootCA("Test Root CA", 24*time.Hour)
+	require.NoError(t, err)
+
+	intCAPrivKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	intCACert, err := rootCA.IssueCertificate(
+		testsupport.WithSubject(pkix.Name{
+			CommonName:   "Test Int CA",
+			Organization: []string{"Test"},
+			Country:      []string{"EU"},
+		}),
+		testsupport.WithIsCA(),
+		testsupport.WithValidity(time.Now(), 24*time.Hour),
+		testsupport.WithSubjectPubKey(&intCAPrivKey.PublicKey, x509.ECDSAWithSHA384),
+		testsupport.WithCRLDistributionPoints([]string{pki.srv.URL + "/root/crl"}))
+	require.NoError(t, err)
+
+	pki.rootCA = rootCA
+	pki.intCA = testsupport.NewCA(intCAPrivKey, intCACert)
+
+	return pki
+}
+
+func (p *testPKI) issue(t *testing.T, cn string, opts ...testsupport.CertificateBuilderOption) *x509.Certificate {
+	t.Helper()
+
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	cert, err := p.intCA.IssueCertificate(append([]testsupport.CertificateBuilderOption{
+		testsupport.WithSubject(pkix.Name{
+			CommonName:   cn,
+			Organization: []string{"Test"},
+			Country:      []string{"EU"},
+		}),
+		testsupport.WithValidity(time.Now(), 24*time.Hour),
+		testsupport.WithSubjectPubKey(&privKey.PublicKey, x509.ECDSAWithSHA256),
+		testsupport.WithExtendedKeyUsage(x509.ExtKeyUsageClientAuth),
+	}, opts...)...)
+	require.NoError(t, err)
+
+	return cert
+}
+
+func (p *testPKI) revoke(cert *x509.Certificate) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.revoked[cert.SerialNumber.String()] = true
+}
+
+func (p *testPKI) isRevoked(serial *big.Int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.revoked[serial.String()]
+}
+
+func (p *testPKI) chain(cert *x509.Certificate) []*x509.Certificate {
+	return []*x509.Certificate{cert, p.intCA.Certificate, p.rootCA.Certificate}
+}
+
+func (p *testPKI) writeCRL(t *testing.T, rw http.ResponseWriter, ca *testsupport.CA) {
+	t.Helper()
+
+	p.requests.Add(1)
+
+	var entries []x509.RevocationListEntry
+
+	// the serial numbers of certificates issued by the root and the intermediate CA overlap.
+	// Only the certificates issued by the intermediate CA are subject to revocation here
+	if ca == p.intCA {
+		p.mu.Lock()
+		for serial := range p.revoked {
+			sn, _ := new(big.Int).SetString(serial, 10)
+			entries = append(entries, x509.RevocationListEntry{
+				SerialNumber:   sn,
+				RevocationTime: time.Now().Add(-1 * time.Minute),
+			})
+		}
+		p.mu.Unlock()
+	}
+
+	crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(1),
+		ThisUpdate:                time.Now().Add(-1 * time.Minute),
+		NextUpdate:                time.Now().Add(1 * time.Hour),
+		RevokedCertificateEntries: entries,
+	}, ca.Certificate, ca.PrivKey)
+	require.NoError(t, err)
+
+	rw.Header().Set("Content-Type", "application/pkix-crl")
+	_, err = rw.Write(crl)
+	require.NoError(t, err)
+}
+
+func (p *testPKI) writeOCSPResponse(t *testing.T, rw http.ResponseWriter, req *http.Request, ca *testsupport.CA) {
+	t.Helper()
+
+	p.requests.Add(1)
+
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+
+	ocspReq, err := ocsp.ParseRequest(body)
+	require.NoError(t, err)
+
+	status := ocsp.Good
+	if p.isRevoked(ocspReq.SerialNumber) {
+		status = ocsp.Revoked
+	}
+
+	resp, err := ocsp.CreateResponse(ca.Certificate, ca.Certificate, ocsp.Response{
+		Status:       status,
+		SerialNumber: ocspReq.SerialNumber,
+		ThisUpdate:   time.Now().Add(-1 * time.Minute),
+		NextUpdate:   time.Now().Add(1 * time.Hour),
+		RevokedAt:    time.Now().Add(-1 * time.Minute),
+	}, ca.PrivKey)
+	require.NoError(t, err)
+
+	rw.Header().Set("Content-Type", "application/ocsp-response")
+	_, err = rw.Write(resp)
+	require.NoError(t, err)
+}
+
+func TestRevocationCheckerCheck(t *testing.T) {
+	t.Parallel()
+
+	pki := newTestPKI(t)
+
+	for _, tc := range []struct {
+		uc     string
+		conf   RevocationConfig
+		cert   func(t *testing.T) *x509.Certificate
+		assert func(t *testing.T, err error)
+	}{
+		{
+			uc:   "not revoked certificate checked using CRL",
+			conf: RevocationConfig{CRL: true},
+			cert: func(t *testing.T) *x509.Certificate {
+				t.Helper()
+
+				return pki.issue(t, "crl valid",
+					testsupport.WithCRLDistributionPoints([]string{pki.srv.URL + "/int/crl"}))
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		{
+			uc:   "revoked certificate checked using CRL",
+			conf: RevocationConfig{CRL: true},
+			cert: func(t *testing.T) *x509.Certificate {
+				t.Helper()
+
+				cert := pki.issue(t, "crl revoked",
+					testsupport.WithCRLDistributionPoints([]string{pki.srv.URL + "/int/crl"}))
+				pki.revoke(cert)
+
+				return cert
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, errCertificateRevoked)
+				assert.Contains(t, err.Error(), "crl revoked")
+			},
+		},
+		{
+			uc:   "not revoked certificate checked using OCSP",
+			conf: RevocationConfig{OCSP: true, CRL: true},
+			cert: func(t *testing.T) *x509.Certificate {
+				t.Helper()
+
+				return pki.issue(t, "ocsp valid",
+					testsupport.WithOCSPServers([]string{pki.srv.URL + "/int/ocsp"}))
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		{
+			uc:   "revoked certificate checked using OCSP",
+			conf: RevocationConfig{OCSP: true},
+			cert: func(t *testing.T) *x509.Certificate {
+				t.Helper()
+
+				cert := pki.issue(t, "ocsp revoked",
+					testsupport.WithOCSPServers([]string{pki.srv.URL + "/int/ocsp"}),
+					testsupport.WithCRLDistributionPoints([]string{pki.srv.URL + "/int/crl"}))
+				pki.revoke(cert)
+
+				return cert
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, errCertificateRevoked)
+			},
+		},
+		{
+			uc:   "OCSP responder not available, but CRL can be used",
+			conf: RevocationConfig{OCSP: true, CRL: true},
+			cert: func(t *testing.T) *x509.Certificate {
+				t.Helper()
+
+				cert := pki.issue(t, "ocsp broken",
+					testsupport.WithOCSPServers([]string{pki.srv.URL + "/broken"}),
+					testsupport.WithCRLDistributionPoints([]string{pki.srv.URL + "/int/crl"}))
+				pki.revoke(cert)
+
+				return cert
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, errCertificateRevoked)
+			},
+		},
+		{
+			uc:   "only OCSP enabled, but certificate references CRLs only",
+			conf: RevocationConfig{OCSP: true},
+			cert: func(t *testing.T) *x509.Certificate {
+				t.Helper()
+
+				return pki.issue(t, "crl only",
+					testsupport.WithCRLDistributionPoints([]string{pki.srv.URL + "/int/crl"}))
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, errRevocationStatusUnknown)
+				assert.Contains(t, err.Error(), "no applicable OCSP responder or CRL distribution point")
+			},
+		},
+		{
+			uc:   "CRL not signed by the issuer of the certificate",
+			conf: RevocationConfig{CRL: true},
+			cert: func(t *testing.T) *x509.Certificate {
+				t.Helper()
+
+				return pki.issue(t, "foreign crl",
+					testsupport.WithCRLDistributionPoints([]string{pki.srv.URL + "/foreign/crl"}))
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, errRevocationStatusUnknown)
+				assert.Contains(t, err.Error(), "not signed by the issuer")
+			},
+		},
+		{
+			uc:   "unsupported CRL distribution point",
+			conf: RevocationConfig{CRL: true},
+			cert: func(t *testing.T) *x509.Certificate {
+				t.Helper()
+
+				return pki.issue(t, "ldap crl",
+					testsupport.WithCRLDistributionPoints([]string{"ldap://ldap.example.com/cn=Test%20Int%20CA"}))
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, errRevocationStatusUnknown)
+				assert.Contains(t, err.Error(), "unsupported revocation data location")
+			},
+		},
+		{
+			uc:   "CRL exceeding the allowed size",
+			conf: RevocationConfig{CRL: true},
+			cert: func(t *testing.T) *x509.Certificate {
+				t.Helper()
+
+				return pki.issue(t, "large crl",
+					testsupport.WithCRLDistributionPoints([]string{pki.srv.URL + "/large/crl"}))
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.ErrorIs(t, err, errRevocationStatusUnknown)
+				require.ErrorIs(t, err, errRevocationDataTooLarge)
+			},
+		},
+		{
+			uc:   "revocation status cannot be determined with soft fail enabled",
+			conf: RevocationConfig{OCSP: true, CRL: true, SoftFail: true},
+			cert: func(t *testing.T) *x509.Certificate {
+				t.Helper()
+
+				return pki.issue(t, "soft fail",
+					testsupport.WithOCSPServers([]string{pki.srv.URL + "/broken"}),
+					testsupport.WithCRLDistributionPoints([]string{pki.srv.URL + "/broken"}))
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			rc := newRevocationChecker(&tc.conf)
+			ctx := cache.WithContext(context.Background(), memory.New())
+
+			// WHEN
+			err := rc.check(ctx, pki.chain(tc.cert(t)))
+
+			// THEN
+			tc.assert(t, err)
+		})
+	}
+}
+
+func TestRevocationCheckerCachesRevocationStatus(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	pki := newTestPKI(t)
+	cert := pki.issue(t, "cached",
+		testsupport.WithOCSPServers([]string{pki.srv.URL + "/int/ocsp"}))
+
+	rc := newRevocationChecker(&RevocationConfig{OCSP: true, CRL: true})
+	ctx := cache.WithContext(context.Background(), memory.New())
+
+	// WHEN
+	err1 := rc.check(ctx, pki.chain(cert))
+	err2 := rc.check(ctx, pki.chain(cert))
+
+	// THEN
+	require.NoError(t, err1)
+	require.NoError(t, err2)
+
+	// one OCSP request for the end entity and one CRL request for the intermediate CA certificate
+	assert.Equal(t, int32(2), pki.requests.Load())
+}
+
+func TestRevocationCheckerCachesCRL(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	pki := newTestPKI(t)
+	cert1 := pki.issue(t, "first",
+		testsupport.WithCRLDistributionPoints([]string{pki.srv.URL + "/int/crl"}))
+	cert2 := pki.issue(t, "second",
+		testsupport.WithCRLDistributionPoints([]string{pki.srv.URL + "/int/crl"}))
+
+	pki.revoke(cert2)
+
+	rc := newRevocationChecker(&RevocationConfig{CRL: true})
+	ctx := cache.WithContext(context.Background(), memory.New())
+
+	// WHEN
+	err1 := rc.check(ctx, pki.chain(cert1))
+	err2 := rc.check(ctx, pki.chain(cert2))
+
+	// THEN
+	require.NoError(t, err1)
+	require.ErrorIs(t, err2, errCertificateRevoked)
+
+	// one CRL request for each CA, the CRL of the intermediate CA is reused for the second certificate
+	assert.Equal(t, int32(2), pki.requests.Load())
+}
@@ -27,6 +27,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/goccy/go-json"
 	"github.com/google/cel-go/cel"
 	"github.com/rs/zerolog"
 
@@ -59,6 +60,8 @@ func init() {
 
 			return true, auth, err
 		})
+
+	cache.RegisterValueType("authorizers/remote", &authorizationInformation{})
 }
 
 type remoteAuthorizer struct {
@@ -77,6 +80,26 @@ type authorizationInformation struct {
 	payload any
 }
 
+func (ai *authorizationInformation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{"headers": ai.headers, "payload": ai.payload})
+}
+
+func (ai *authorizationInformation) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Headers http.Header `json:"headers"`
+		Payload any         `json:"payload"`
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	ai.headers = raw.Headers
+	ai.payload = raw.Payload
+
+	return nil
+}
+
 func (ai *authorizationInformation) addHeadersTo(headerNames []string, ctx heimdall.Context) {
 	for _, headerName := range headerNames {
 		headerValue := ai.headers.Get(headerName)
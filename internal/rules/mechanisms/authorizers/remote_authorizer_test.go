@@ -201,7 +201,7 @@ values:
 					"Subject": &subject.Subject{ID: "bar"},
 					"Request": &heimdall.Request{
 						RequestFunctions: rfunc,
-						URL:              &url.URL{Scheme: "http", Host: "foo.bar", Path: "/foo/bar"},
+						URL:              &heimdall.URL{URL: url.URL{Scheme: "http", Host: "foo.bar", Path: "/foo/bar"}},
 					},
 				})
 				require.NoError(t, err)
@@ -1318,3 +1318,26 @@ func TestRemoteAuthorizerExecute(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthorizationInformationJSONRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	authInfo := &authorizationInformation{
+		headers: http.Header{"X-Foo": []string{"bar"}},
+		payload: map[string]any{"foo": "bar"},
+	}
+
+	// WHEN
+	data, err := json.Marshal(authInfo)
+	require.NoError(t, err)
+
+	var decoded authorizationInformation
+
+	err = json.Unmarshal(data, &decoded)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, authInfo.headers, decoded.headers)
+	assert.Equal(t, authInfo.payload, decoded.payload)
+}
@@ -52,7 +52,7 @@ func TestRequests(t *testing.T) {
 	req := &heimdall.Request{
 		RequestFunctions:  reqf,
 		Method:            http.MethodHead,
-		URL:               uri,
+		URL:               &heimdall.URL{URL: *uri, Captures: map[string]string{"tenant": "foo"}},
 		ClientIPAddresses: []string{"127.0.0.1"},
 	}
 
@@ -61,6 +61,9 @@ func TestRequests(t *testing.T) {
 	}{
 		{expr: `Request.Method == "HEAD"`},
 		{expr: `Request.URL.String() == "` + rawURI + `"`},
+		{expr: `Request.URL.Path == "/foo/bar"`},
+		{expr: `Request.URL.Captures.tenant == "foo"`},
+		{expr: `Request.URL.Captures["tenant"] == "foo"`},
 		{expr: `Request.Cookie("foo") == "bar"`},
 		{expr: `Request.Header("bar") == "baz"`},
 		{expr: `Request.Header("zab").contains("bar")`},
@@ -22,6 +22,8 @@ import (
 
 	"github.com/google/cel-go/cel"
 	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
 )
 
 func TestUrls(t *testing.T) {
@@ -43,6 +45,8 @@ func TestUrls(t *testing.T) {
 		{expr: `uri.String() == "` + rawURI + `"`},
 		{expr: `uri.Query() == {"foo":["bar", "baz"], "bar": ["foo"]}`},
 		{expr: `uri.Query().bar == ["foo"]`},
+		{expr: `uri.Path == "/foo/bar"`},
+		{expr: `uri.Captures.tenant == "foo"`},
 	} {
 		t.Run(tc.expr, func(t *testing.T) {
 			ast, iss := env.Compile(tc.expr)
@@ -58,7 +62,7 @@ func TestUrls(t *testing.T) {
 			prg, err := env.Program(ast, cel.EvalOptions(cel.OptOptimize))
 			require.NoError(t, err)
 
-			out, _, err := prg.Eval(map[string]any{"uri": uri})
+			out, _, err := prg.Eval(map[string]any{"uri": &heimdall.URL{URL: *uri, Captures: map[string]string{"tenant": "foo"}}})
 			require.NoError(t, err)
 			require.Equal(t, true, out.Value()) //nolint:testifylint
 		})
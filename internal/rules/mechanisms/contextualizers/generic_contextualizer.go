@@ -27,6 +27,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/goccy/go-json"
 	"github.com/rs/zerolog"
 
 	"github.com/dadrus/heimdall/internal/cache"
@@ -61,12 +62,32 @@ func init() {
 
 			return true, eh, err
 		})
+
+	cache.RegisterValueType("contextualizers/generic", &contextualizerData{})
 }
 
 type contextualizerData struct {
 	payload any
 }
 
+func (cd *contextualizerData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{"payload": cd.payload})
+}
+
+func (cd *contextualizerData) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Payload any `json:"payload"`
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	cd.payload = raw.Payload
+
+	return nil
+}
+
 type genericContextualizer struct {
 	id              string
 	e               endpoint.Endpoint
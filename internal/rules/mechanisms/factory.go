@@ -43,4 +43,7 @@ type Factory interface {
 	CreateContextualizer(version, id string, conf config.MechanismConfig) (contextualizers.Contextualizer, error)
 	CreateFinalizer(version, id string, conf config.MechanismConfig) (finalizers.Finalizer, error)
 	CreateErrorHandler(version, id string, conf config.MechanismConfig) (errorhandlers.ErrorHandler, error)
+	// Close releases the resources held by the mechanisms prototypes. It must be called
+	// as soon as the factory is not used to create mechanisms anymore.
+	Close() error
 }
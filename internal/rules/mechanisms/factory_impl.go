@@ -45,6 +45,8 @@ type mechanismsFactory struct {
 	r *prototypeRepository
 }
 
+func (hf *mechanismsFactory) Close() error { return hf.r.Close() }
+
 func (hf *mechanismsFactory) CreateAuthenticator(_, id string, conf config.MechanismConfig) (
 	authenticators.Authenticator, error,
 ) {
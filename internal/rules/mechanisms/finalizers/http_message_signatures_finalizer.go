@@ -0,0 +1,214 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package finalizers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/httpsig"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+const (
+	defaultHTTPMessageSignatureLabel = "heimdall"
+	defaultHTTPMessageSignatureTTL   = 1 * time.Minute
+)
+
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	registerTypeFactory(
+		func(id string, typ string, conf map[string]any) (bool, Finalizer, error) {
+			if typ != FinalizerHTTPMessageSignatures {
+				return false, nil, nil
+			}
+
+			finalizer, err := newHTTPMessageSignaturesFinalizer(id, conf)
+
+			return true, finalizer, err
+		})
+}
+
+type httpMessageSignaturesFinalizer struct {
+	id         string
+	components []httpsig.Item
+	label      string
+	tag        string
+	ttl        time.Duration
+}
+
+func newHTTPMessageSignaturesFinalizer(id string, rawConfig map[string]any) (*httpMessageSignaturesFinalizer, error) {
+	type Config struct {
+		Components []string       `mapstructure:"components"`
+		Label      string         `mapstructure:"label"`
+		Tag        string         `mapstructure:"tag"`
+		TTL        *time.Duration `mapstructure:"ttl"`
+	}
+
+	var conf Config
+	if err := decodeConfig(FinalizerHTTPMessageSignatures, rawConfig, &conf); err != nil {
+		return nil, err
+	}
+
+	label := x.IfThenElse(len(conf.Label) != 0, conf.Label, defaultHTTPMessageSignatureLabel)
+	if err := httpsig.ValidateLabel(label); err != nil {
+		return nil, errorchain.
+			NewWithMessagef(heimdall.ErrConfiguration, "invalid label '%s'", label).
+			CausedBy(err)
+	}
+
+	components, err := parseSignatureComponents(
+		x.IfThenElse(len(conf.Components) != 0, conf.Components, []string{"@method", "@path", "@query"}))
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpMessageSignaturesFinalizer{
+		id:         id,
+		components: components,
+		label:      label,
+		tag:        conf.Tag,
+		ttl: x.IfThenElseExec(conf.TTL != nil,
+			func() time.Duration { return *conf.TTL },
+			func() time.Duration { return defaultHTTPMessageSignatureTTL }),
+	}, nil
+}
+
+func parseSignatureComponents(identifiers []string) ([]httpsig.Item, error) {
+	components := make([]httpsig.Item, len(identifiers))
+
+	for idx, identifier := range identifiers {
+		component, err := httpsig.ParseComponentIdentifier(identifier)
+		if err != nil {
+			return nil, errorchain.
+				NewWithMessagef(heimdall.ErrConfiguration, "invalid component identifier '%s'", identifier).
+				CausedBy(err)
+		}
+
+		components[idx] = component
+	}
+
+	return components, nil
+}
+
+func (f *httpMessageSignaturesFinalizer) Execute(ctx heimdall.Context, _ *subject.Subject) error {
+	logger := zerolog.Ctx(ctx.AppContext())
+	logger.Debug().Str("_id", f.id).Msg("Finalizing using http_message_signatures finalizer")
+
+	jwk, key := ctx.Signer().ActiveKey()
+	if key == nil {
+		return errorchain.
+			NewWithMessage(heimdall.ErrInternal, "no signing key available").
+			WithErrorContext(f)
+	}
+
+	alg, err := httpsig.AlgorithmForKey(key.Public())
+	if err != nil {
+		return errorchain.
+			NewWithMessage(heimdall.ErrInternal, "signing key cannot be used for HTTP message signatures").
+			WithErrorContext(f).
+			CausedBy(err)
+	}
+
+	now := time.Now()
+	params := httpsig.Params{{Name: "created", Value: now.Unix()}}
+
+	if f.ttl > 0 {
+		params = append(params, httpsig.Param{Name: "expires", Value: now.Add(f.ttl).Unix()})
+	}
+
+	params = append(params,
+		httpsig.Param{Name: "keyid", Value: jwk.KeyID},
+		httpsig.Param{Name: "alg", Value: string(alg)})
+
+	if len(f.tag) != 0 {
+		params = append(params, httpsig.Param{Name: "tag", Value: f.tag})
+	}
+
+	// the request is signed as forwarded to the upstream, that is after all rewrites
+	// and with the headers set by the finalizers
+	ctx.AddUpstreamRequestSigner(func(req *heimdall.Request) (http.Header, error) {
+		sig, err := httpsig.Sign(req, f.label, f.components, params, alg, key)
+		if err != nil {
+			return nil, errorchain.
+				NewWithMessage(
+					x.IfThenElse(errors.Is(err, httpsig.ErrMissingComponent), heimdall.ErrArgument, heimdall.ErrInternal),
+					"failed to sign the request").
+				WithErrorContext(f).
+				CausedBy(err)
+		}
+
+		headers := make(http.Header)
+		headers.Set(httpsig.HeaderSignatureInput, sig.SignatureInputField())
+		headers.Set(httpsig.HeaderSignature, sig.SignatureField())
+
+		return headers, nil
+	})
+
+	return nil
+}
+
+func (f *httpMessageSignaturesFinalizer) WithConfig(rawConfig map[string]any) (Finalizer, error) {
+	if len(rawConfig) == 0 {
+		return f, nil
+	}
+
+	type Config struct {
+		Components []string       `mapstructure:"components"`
+		Tag        *string        `mapstructure:"tag"`
+		TTL        *time.Duration `mapstructure:"ttl"`
+	}
+
+	var conf Config
+	if err := decodeConfig(FinalizerHTTPMessageSignatures, rawConfig, &conf); err != nil {
+		return nil, err
+	}
+
+	components := f.components
+
+	if len(conf.Components) != 0 {
+		var err error
+
+		if components, err = parseSignatureComponents(conf.Components); err != nil {
+			return nil, err
+		}
+	}
+
+	return &httpMessageSignaturesFinalizer{
+		id:         f.id,
+		components: components,
+		label:      f.label,
+		tag: x.IfThenElseExec(conf.Tag != nil,
+			func() string { return *conf.Tag },
+			func() string { return f.tag }),
+		ttl: x.IfThenElseExec(conf.TTL != nil,
+			func() time.Duration { return *conf.TTL },
+			func() time.Duration { return f.ttl }),
+	}, nil
+}
+
+func (f *httpMessageSignaturesFinalizer) ID() string { return f.id }
+
+func (f *httpMessageSignaturesFinalizer) ContinueOnError() bool { return false }
@@ -0,0 +1,403 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package finalizers
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	heimdallmocks "github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/httpsig"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestCreateHTTPMessageSignaturesFinalizer(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc     string
+		id     string
+		config []byte
+		assert func(t *testing.T, err error, finalizer *httpMessageSignaturesFinalizer)
+	}{
+		{
+			uc: "without configuration",
+			id: "fin",
+			assert: func(t *testing.T, err error, finalizer *httpMessageSignaturesFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "fin", finalizer.ID())
+				assert.Equal(t, []httpsig.Item{{Value: "@method"}, {Value: "@path"}, {Value: "@query"}},
+					finalizer.components)
+				assert.Equal(t, defaultHTTPMessageSignatureLabel, finalizer.label)
+				assert.Empty(t, finalizer.tag)
+				assert.Equal(t, defaultHTTPMessageSignatureTTL, finalizer.ttl)
+				assert.False(t, finalizer.ContinueOnError())
+			},
+		},
+		{
+			uc: "with full configuration",
+			config: []byte(`
+components: ["@method", "@target-uri", "Content-Type"]
+label: sig1
+tag: my-app
+ttl: 0s
+`),
+			assert: func(t *testing.T, err error, finalizer *httpMessageSignaturesFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, []httpsig.Item{{Value: "@method"}, {Value: "@target-uri"}, {Value: "content-type"}},
+					finalizer.components)
+				assert.Equal(t, "sig1", finalizer.label)
+				assert.Equal(t, "my-app", finalizer.tag)
+				assert.Equal(t, time.Duration(0), finalizer.ttl)
+			},
+		},
+		{
+			uc:     "with invalid label",
+			config: []byte(`label: "Foo Bar"`),
+			assert: func(t *testing.T, err error, _ *httpMessageSignaturesFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "invalid label")
+			},
+		},
+		{
+			uc:     "with malformed component identifier",
+			config: []byte(`components: ["@query-param;name="]`),
+			assert: func(t *testing.T, err error, _ *httpMessageSignaturesFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "invalid component identifier")
+			},
+		},
+		{
+			uc:     "with unsupported properties",
+			config: []byte(`foo: bar`),
+			assert: func(t *testing.T, err error, _ *httpMessageSignaturesFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed decoding")
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			// WHEN
+			finalizer, err := newHTTPMessageSignaturesFinalizer(tc.id, conf)
+
+			// THEN
+			tc.assert(t, err, finalizer)
+		})
+	}
+}
+
+func TestCreateHTTPMessageSignaturesFinalizerFromPrototype(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc     string
+		config []byte
+		assert func(t *testing.T, err error, prototype, configured *httpMessageSignaturesFinalizer)
+	}{
+		{
+			uc: "without new configuration",
+			assert: func(t *testing.T, err error, prototype, configured *httpMessageSignaturesFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, prototype, configured)
+			},
+		},
+		{
+			uc: "with components, tag and ttl reconfigured",
+			config: []byte(`
+components: ["@method"]
+tag: other-app
+ttl: 5m
+`),
+			assert: func(t *testing.T, err error, prototype, configured *httpMessageSignaturesFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.NotEqual(t, prototype, configured)
+				assert.Equal(t, prototype.ID(), configured.ID())
+				assert.Equal(t, prototype.label, configured.label)
+				assert.Equal(t, []httpsig.Item{{Value: "@method"}}, configured.components)
+				assert.Equal(t, "my-app", prototype.tag)
+				assert.Equal(t, "other-app", configured.tag)
+				assert.Equal(t, 5*time.Minute, configured.ttl)
+			},
+		},
+		{
+			uc:     "with malformed component identifier",
+			config: []byte(`components: ["@query-param;name="]`),
+			assert: func(t *testing.T, err error, _, _ *httpMessageSignaturesFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "invalid component identifier")
+			},
+		},
+		{
+			uc:     "with label reconfigured",
+			config: []byte(`label: foo`),
+			assert: func(t *testing.T, err error, _, _ *httpMessageSignaturesFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed decoding")
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			pc, err := testsupport.DecodeTestConfig([]byte(`tag: my-app`))
+			require.NoError(t, err)
+
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			prototype, err := newHTTPMessageSignaturesFinalizer("fin", pc)
+			require.NoError(t, err)
+
+			// WHEN
+			finalizer, err := prototype.WithConfig(conf)
+
+			// THEN
+			var (
+				hmf *httpMessageSignaturesFinalizer
+				ok  bool
+			)
+
+			if err == nil {
+				hmf, ok = finalizer.(*httpMessageSignaturesFinalizer)
+				require.True(t, ok)
+			}
+
+			tc.assert(t, err, prototype, hmf)
+		})
+	}
+}
+
+func TestHTTPMessageSignaturesFinalizerExecute(t *testing.T) {
+	t.Parallel()
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ecP521Key, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	reqURL, err := url.Parse("https://example.com/foo?bar=baz")
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		uc     string
+		config []byte
+		key    crypto.Signer
+		assert func(t *testing.T, err error, req *heimdall.Request, upstreamHeaders map[string]string)
+	}{
+		{
+			uc: "without signing key",
+			assert: func(t *testing.T, err error, _ *heimdall.Request, _ map[string]string) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				assert.Contains(t, err.Error(), "no signing key available")
+
+				var identifier interface{ ID() string }
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "fin", identifier.ID())
+			},
+		},
+		{
+			uc:  "with unsupported signing key",
+			key: ecP521Key,
+			assert: func(t *testing.T, err error, _ *heimdall.Request, _ map[string]string) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				require.ErrorIs(t, err, httpsig.ErrUnsupportedKey)
+				assert.Contains(t, err.Error(), "cannot be used for HTTP message signatures")
+			},
+		},
+		{
+			uc:     "with component not present in the request",
+			config: []byte(`components: ["@method", "content-type"]`),
+			key:    ecKey,
+			assert: func(t *testing.T, err error, _ *heimdall.Request, _ map[string]string) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrArgument)
+				require.ErrorIs(t, err, httpsig.ErrMissingComponent)
+				assert.Contains(t, err.Error(), "failed to sign the request")
+			},
+		},
+		{
+			uc:  "with ecdsa key and default configuration",
+			key: ecKey,
+			assert: func(t *testing.T, err error, req *heimdall.Request, upstreamHeaders map[string]string) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				sig := verifyUpstreamSignature(t, req, upstreamHeaders, &ecKey.PublicKey)
+				created, _ := sig.Created()
+				expires, _ := sig.Expires()
+
+				assert.Equal(t, "heimdall", sig.Label)
+				assert.Equal(t, "key1", sig.KeyID())
+				assert.Equal(t, "ecdsa-p256-sha256", sig.Algorithm())
+				assert.Empty(t, sig.Tag())
+				assert.Equal(t, []string{`"@method"`, `"@path"`, `"@query"`}, sig.Components())
+				assert.Equal(t, defaultHTTPMessageSignatureTTL, expires.Sub(created))
+			},
+		},
+		{
+			uc: "with rsa key and custom configuration",
+			config: []byte(`
+components: ["@method", "@target-uri", "@query-param;name=\"bar\""]
+label: sig1
+tag: my-app
+ttl: 0s
+`),
+			key: rsaKey,
+			assert: func(t *testing.T, err error, req *heimdall.Request, upstreamHeaders map[string]string) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				sig := verifyUpstreamSignature(t, req, upstreamHeaders, &rsaKey.PublicKey)
+				_, hasExpires := sig.Expires()
+
+				assert.Equal(t, "sig1", sig.Label)
+				assert.Equal(t, "rsa-pss-sha512", sig.Algorithm())
+				assert.Equal(t, "my-app", sig.Tag())
+				assert.False(t, hasExpires)
+				assert.Equal(t, []string{`"@method"`, `"@target-uri"`, `"@query-param";name="bar"`}, sig.Components())
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			conf, err := testsupport.DecodeTestConfig(tc.config)
+			require.NoError(t, err)
+
+			finalizer, err := newHTTPMessageSignaturesFinalizer("fin", conf)
+			require.NoError(t, err)
+
+			fnt := heimdallmocks.NewRequestFunctionsMock(t)
+			fnt.EXPECT().Header(mock.Anything).Return("").Maybe()
+			fnt.EXPECT().HeaderValues(mock.Anything).Return(nil).Maybe()
+
+			req := &heimdall.Request{RequestFunctions: fnt, Method: http.MethodPost, URL: &heimdall.URL{URL: *reqURL}}
+			upstreamHeaders := make(map[string]string)
+
+			var upstreamSigner heimdall.UpstreamRequestSigner
+
+			signer := heimdallmocks.NewJWTSignerMock(t)
+			signer.EXPECT().ActiveKey().Return(jose.JSONWebKey{KeyID: "key1"}, tc.key)
+
+			ctx := heimdallmocks.NewContextMock(t)
+			ctx.EXPECT().AppContext().Return(context.Background())
+			ctx.EXPECT().Signer().Return(signer)
+			ctx.EXPECT().Request().Return(req).Maybe()
+			ctx.EXPECT().AddUpstreamRequestSigner(mock.Anything).
+				Run(func(sign heimdall.UpstreamRequestSigner) { upstreamSigner = sign }).Maybe()
+
+			// WHEN
+			err = finalizer.Execute(ctx, &subject.Subject{ID: "foo"})
+			if err == nil {
+				// done by the handler for the request forwarded to the upstream
+				require.NotNil(t, upstreamSigner)
+
+				var headers http.Header
+
+				headers, err = upstreamSigner(req)
+				for name := range headers {
+					upstreamHeaders[name] = headers.Get(name)
+				}
+			}
+
+			// THEN
+			tc.assert(t, err, req, upstreamHeaders)
+		})
+	}
+}
+
+func verifyUpstreamSignature(
+	t *testing.T, req *heimdall.Request, upstreamHeaders map[string]string, key crypto.PublicKey,
+) *httpsig.Signature {
+	t.Helper()
+
+	fnt := heimdallmocks.NewRequestFunctionsMock(t)
+	fnt.EXPECT().Header(mock.Anything).RunAndReturn(func(name string) string { return upstreamHeaders[name] })
+	fnt.EXPECT().HeaderValues(mock.Anything).RunAndReturn(func(name string) []string {
+		if value, ok := upstreamHeaders[name]; ok {
+			return []string{value}
+		}
+
+		return nil
+	}).Maybe()
+
+	upstreamReq := &heimdall.Request{RequestFunctions: fnt, Method: req.Method, URL: req.URL}
+
+	sigs, err := httpsig.FromRequest(upstreamReq)
+	require.NoError(t, err)
+	require.Len(t, sigs, 1)
+
+	alg, err := httpsig.ParseAlgorithm(sigs[0].Algorithm())
+	require.NoError(t, err)
+	require.NoError(t, sigs[0].Verify(upstreamReq, alg, key))
+
+	return sigs[0]
+}
@@ -0,0 +1,168 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package finalizers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/goccy/go-json"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/endpoint"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/stringx"
+)
+
+var errNoEncryptionKey = errors.New("no suitable encryption key found in the recipient JWKS")
+
+// jwtEncrypter encrypts issued JWTs (as nested JWTs) for a recipient, the public key of which is
+// retrieved from the configured JWKS endpoint.
+type jwtEncrypter struct {
+	jwksEndpoint      *endpoint.Endpoint
+	keyID             string
+	contentEncryption jose.ContentEncryption
+}
+
+func newJWTEncrypter(ep *endpoint.Endpoint, keyID, contentEncryption string) *jwtEncrypter {
+	if ep.Headers == nil {
+		ep.Headers = make(map[string]string)
+	}
+
+	if _, ok := ep.Headers["Accept"]; !ok {
+		ep.Headers["Accept"] = "application/json"
+	}
+
+	if len(ep.Method) == 0 {
+		ep.Method = http.MethodGet
+	}
+
+	if ep.HTTPCache == nil {
+		ep.HTTPCache = &endpoint.HTTPCache{Enabled: true, DefaultTTL: 30 * time.Minute} //nolint:gomnd
+	}
+
+	if len(contentEncryption) == 0 {
+		contentEncryption = string(jose.A256GCM)
+	}
+
+	return &jwtEncrypter{
+		jwksEndpoint:      ep,
+		keyID:             keyID,
+		contentEncryption: jose.ContentEncryption(contentEncryption),
+	}
+}
+
+func (e *jwtEncrypter) encrypt(ctx context.Context, token string) (string, error) {
+	key, alg, err := e.recipientKey(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	encrypter, err := jose.NewEncrypter(
+		e.contentEncryption,
+		jose.Recipient{Algorithm: alg, Key: key.Key, KeyID: key.KeyID},
+		// see https://www.rfc-editor.org/rfc/rfc7519#section-5.2
+		(&jose.EncrypterOptions{}).WithType("JWT").WithContentType("JWT"),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	jwe, err := encrypter.Encrypt(stringx.ToBytes(token))
+	if err != nil {
+		return "", err
+	}
+
+	return jwe.CompactSerialize()
+}
+
+func (e *jwtEncrypter) recipientKey(ctx context.Context) (*jose.JSONWebKey, jose.KeyAlgorithm, error) {
+	rawData, err := e.jwksEndpoint.SendRequest(ctx, nil, nil)
+	if err != nil {
+		return nil, "", errorchain.NewWithMessage(heimdall.ErrCommunication,
+			"failed to fetch recipient jwks").CausedBy(err)
+	}
+
+	var jwks jose.JSONWebKeySet
+	if err = json.Unmarshal(rawData, &jwks); err != nil {
+		return nil, "", errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed to unmarshal received jwks").CausedBy(err)
+	}
+
+	for idx := range jwks.Keys {
+		key := &jwks.Keys[idx]
+
+		if key.Use == "sig" || (len(e.keyID) != 0 && key.KeyID != e.keyID) {
+			continue
+		}
+
+		if alg := keyManagementAlgorithm(key); len(alg) != 0 {
+			return key, alg, nil
+		}
+	}
+
+	if len(e.keyID) != 0 {
+		return nil, "", fmt.Errorf("%w: kid %s", errNoEncryptionKey, e.keyID)
+	}
+
+	return nil, "", errNoEncryptionKey
+}
+
+func (e *jwtEncrypter) Hash() []byte {
+	hash := sha256.New()
+
+	hash.Write(e.jwksEndpoint.Hash())
+	hash.Write(stringx.ToBytes(e.keyID))
+	hash.Write(stringx.ToBytes(string(e.contentEncryption)))
+
+	return hash.Sum(nil)
+}
+
+// keyManagementAlgorithm returns the algorithm to use with the given key, or an empty string
+// if the key cannot be used. RSA PKCS v1.5 is not supported by intention.
+func keyManagementAlgorithm(key *jose.JSONWebKey) jose.KeyAlgorithm {
+	var supported []jose.KeyAlgorithm
+
+	switch key.Key.(type) {
+	case *rsa.PublicKey:
+		supported = []jose.KeyAlgorithm{jose.RSA_OAEP_256, jose.RSA_OAEP}
+	case *ecdsa.PublicKey:
+		supported = []jose.KeyAlgorithm{
+			jose.ECDH_ES_A256KW, jose.ECDH_ES_A192KW, jose.ECDH_ES_A128KW, jose.ECDH_ES,
+		}
+	default:
+		return ""
+	}
+
+	if len(key.Algorithm) == 0 {
+		return supported[0]
+	}
+
+	if slices.Contains(supported, jose.KeyAlgorithm(key.Algorithm)) {
+		return jose.KeyAlgorithm(key.Algorithm)
+	}
+
+	return ""
+}
@@ -20,6 +20,7 @@ import (
 	"crypto/sha256"
 	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"time"
 
@@ -28,6 +29,7 @@ import (
 
 	"github.com/dadrus/heimdall/internal/cache"
 	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/endpoint"
 	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
 	"github.com/dadrus/heimdall/internal/rules/mechanisms/template"
 	"github.com/dadrus/heimdall/internal/x"
@@ -62,6 +64,7 @@ type jwtFinalizer struct {
 	ttl          time.Duration
 	headerName   string
 	headerScheme string
+	enc          *jwtEncrypter
 }
 
 func newJWTFinalizer(id string, rawConfig map[string]any) (*jwtFinalizer, error) {
@@ -70,10 +73,17 @@ func newJWTFinalizer(id string, rawConfig map[string]any) (*jwtFinalizer, error)
 		Scheme string `mapstructure:"scheme"`
 	}
 
+	type EncryptionConfig struct {
+		JWKSEndpoint      *endpoint.Endpoint `mapstructure:"jwks_endpoint"      validate:"required"`
+		KeyID             string             `mapstructure:"key_id"`
+		ContentEncryption string             `mapstructure:"content_encryption" validate:"omitempty,oneof=A128GCM A192GCM A256GCM A128CBC-HS256 A192CBC-HS384 A256CBC-HS512"` //nolint:lll
+	}
+
 	type Config struct {
-		TTL    *time.Duration    `mapstructure:"ttl"    validate:"omitempty,gt=1s"`
-		Claims template.Template `mapstructure:"claims"`
-		Header *HeaderConfig     `mapstructure:"header"`
+		TTL        *time.Duration    `mapstructure:"ttl"        validate:"omitempty,gt=1s"`
+		Claims     template.Template `mapstructure:"claims"`
+		Header     *HeaderConfig     `mapstructure:"header"`
+		Encryption *EncryptionConfig `mapstructure:"encryption"`
 	}
 
 	var conf Config
@@ -81,6 +91,11 @@ func newJWTFinalizer(id string, rawConfig map[string]any) (*jwtFinalizer, error)
 		return nil, err
 	}
 
+	var enc *jwtEncrypter
+	if conf.Encryption != nil {
+		enc = newJWTEncrypter(conf.Encryption.JWKSEndpoint, conf.Encryption.KeyID, conf.Encryption.ContentEncryption)
+	}
+
 	return &jwtFinalizer{
 		id:     id,
 		claims: conf.Claims,
@@ -93,6 +108,7 @@ func newJWTFinalizer(id string, rawConfig map[string]any) (*jwtFinalizer, error)
 		headerScheme: x.IfThenElseExec(conf.Header != nil,
 			func() string { return conf.Header.Scheme },
 			func() string { return "Bearer" }),
+		enc: enc,
 	}, nil
 }
 
@@ -168,6 +184,7 @@ func (u *jwtFinalizer) WithConfig(rawConfig map[string]any) (Finalizer, error) {
 			func() time.Duration { return u.ttl }),
 		headerName:   u.headerName,
 		headerScheme: u.headerScheme,
+		enc:          u.enc,
 	}, nil
 }
 
@@ -209,6 +226,21 @@ func (u *jwtFinalizer) generateToken(ctx heimdall.Context, sub *subject.Subject)
 			CausedBy(err)
 	}
 
+	if u.enc == nil {
+		return token, nil
+	}
+
+	token, err = u.enc.encrypt(ctx.AppContext(), token)
+	if err != nil {
+		return "", errorchain.
+			NewWithMessage(
+				x.IfThenElse(errors.Is(err, heimdall.ErrCommunication), heimdall.ErrCommunication, heimdall.ErrInternal),
+				"failed to encrypt token",
+			).
+			WithErrorContext(u).
+			CausedBy(err)
+	}
+
 	return token, nil
 }
 
@@ -225,6 +257,9 @@ func (u *jwtFinalizer) calculateCacheKey(sub *subject.Subject, iss heimdall.JWTS
 		func() []byte { return []byte{} }))
 	hash.Write(ttlBytes)
 	hash.Write(sub.Hash())
+	hash.Write(x.IfThenElseExec(u.enc != nil,
+		func() []byte { return u.enc.Hash() },
+		func() []byte { return []byte{} }))
 
 	return hex.EncodeToString(hash.Sum(nil))
 }
@@ -18,12 +18,21 @@ package finalizers
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/goccy/go-json"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
 
 	"github.com/dadrus/heimdall/internal/cache"
 	"github.com/dadrus/heimdall/internal/cache/mocks"
@@ -227,6 +236,84 @@ header:
 				assert.Equal(t, "Bar", finalizer.headerScheme)
 			},
 		},
+		{
+			uc: "with encryption config without jwks endpoint",
+			id: "jun",
+			config: []byte(`
+encryption:
+  key_id: foo
+`),
+			assert: func(t *testing.T, err error, _ *jwtFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'encryption'.'jwks_endpoint' is a required field")
+			},
+		},
+		{
+			uc: "with encryption config with unsupported content encryption",
+			id: "jun",
+			config: []byte(`
+encryption:
+  jwks_endpoint: http://foo.bar/jwks
+  content_encryption: foo
+`),
+			assert: func(t *testing.T, err error, _ *jwtFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorContains(t, err, "'encryption'.'content_encryption' must be one of")
+			},
+		},
+		{
+			uc: "with minimal encryption config",
+			id: "jun",
+			config: []byte(`
+encryption:
+  jwks_endpoint: http://foo.bar/jwks
+`),
+			assert: func(t *testing.T, err error, finalizer *jwtFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, finalizer)
+				require.NotNil(t, finalizer.enc)
+				assert.Equal(t, "http://foo.bar/jwks", finalizer.enc.jwksEndpoint.URL)
+				assert.Equal(t, http.MethodGet, finalizer.enc.jwksEndpoint.Method)
+				assert.Equal(t, "application/json", finalizer.enc.jwksEndpoint.Headers["Accept"])
+				require.NotNil(t, finalizer.enc.jwksEndpoint.HTTPCache)
+				assert.True(t, finalizer.enc.jwksEndpoint.HTTPCache.Enabled)
+				assert.Empty(t, finalizer.enc.keyID)
+				assert.Equal(t, jose.A256GCM, finalizer.enc.contentEncryption)
+			},
+		},
+		{
+			uc: "with full encryption config",
+			id: "jun",
+			config: []byte(`
+encryption:
+  jwks_endpoint:
+    url: http://foo.bar/jwks
+    method: POST
+    http_cache:
+      enabled: false
+  key_id: foo
+  content_encryption: A128CBC-HS256
+`),
+			assert: func(t *testing.T, err error, finalizer *jwtFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, finalizer)
+				require.NotNil(t, finalizer.enc)
+				assert.Equal(t, http.MethodPost, finalizer.enc.jwksEndpoint.Method)
+				assert.False(t, finalizer.enc.jwksEndpoint.HTTPCache.Enabled)
+				assert.Equal(t, "foo", finalizer.enc.keyID)
+				assert.Equal(t, jose.A128CBC_HS256, finalizer.enc.contentEncryption)
+			},
+		},
 	} {
 		t.Run("case="+tc.uc, func(t *testing.T) {
 			conf, err := testsupport.DecodeTestConfig(tc.config)
@@ -411,6 +498,32 @@ func TestJWTFinalizerExecute(t *testing.T) {
 
 	const configuredTTL = 1 * time.Minute
 
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	encryptionKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwks, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+		{KeyID: "sig", Key: &signingKey.PublicKey, Use: "sig"},
+		{KeyID: "enc", Key: &encryptionKey.PublicKey, Use: "enc"},
+	}})
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write(jwks)
+		require.NoError(t, err)
+	}))
+
+	defer srv.Close()
+
+	failingSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+
+	defer failingSrv.Close()
+
 	for _, tc := range []struct {
 		uc             string
 		id             string
@@ -607,6 +720,120 @@ claims: '{
 				assert.Equal(t, "jun3", identifier.ID())
 			},
 		},
+		{
+			uc: "with no cache hit and encryption",
+			id: "jun4",
+			config: []byte(`
+encryption:
+  jwks_endpoint: ` + srv.URL + `
+`),
+			subject: &subject.Subject{ID: "foo", Attributes: map[string]any{"baz": "bar"}},
+			configureMocks: func(t *testing.T, ctx *heimdallmocks.ContextMock, signer *heimdallmocks.JWTSignerMock,
+				cch *mocks.CacheMock, sub *subject.Subject,
+			) {
+				t.Helper()
+
+				signer.EXPECT().Hash().Return([]byte("foobar"))
+				signer.EXPECT().Sign(sub.ID, defaultJWTTTL, map[string]any{}).Return("foo.bar.baz", nil)
+
+				isEncryptedToken := func(value string) bool {
+					jwe, err := jose.ParseEncrypted(strings.TrimPrefix(value, "Bearer "))
+					require.NoError(t, err)
+
+					assert.Equal(t, "enc", jwe.Header.KeyID)
+					assert.Equal(t, string(jose.RSA_OAEP_256), jwe.Header.Algorithm)
+					assert.Equal(t, "JWT", jwe.Header.ExtraHeaders[jose.HeaderContentType])
+
+					payload, err := jwe.Decrypt(encryptionKey)
+					require.NoError(t, err)
+
+					return string(payload) == "foo.bar.baz"
+				}
+
+				ctx.EXPECT().Signer().Return(signer)
+				ctx.EXPECT().AddHeaderForUpstream("Authorization", mock.MatchedBy(isEncryptedToken))
+
+				// http cache and token cache
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil)
+				cch.EXPECT().Set(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+				cch.EXPECT().Set(mock.Anything, mock.Anything,
+					mock.MatchedBy(func(value string) bool { return strings.Count(value, ".") == 4 }),
+					defaultJWTTTL-defaultCacheLeeway)
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		{
+			uc: "with encryption, but without a matching key in the jwks",
+			id: "jun5",
+			config: []byte(`
+encryption:
+  jwks_endpoint: ` + srv.URL + `
+  key_id: sig
+`),
+			subject: &subject.Subject{ID: "foo", Attributes: map[string]any{"baz": "bar"}},
+			configureMocks: func(t *testing.T, ctx *heimdallmocks.ContextMock, signer *heimdallmocks.JWTSignerMock,
+				cch *mocks.CacheMock, sub *subject.Subject,
+			) {
+				t.Helper()
+
+				signer.EXPECT().Hash().Return([]byte("foobar"))
+				signer.EXPECT().Sign(sub.ID, defaultJWTTTL, map[string]any{}).Return("foo.bar.baz", nil)
+
+				ctx.EXPECT().Signer().Return(signer)
+
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil)
+				cch.EXPECT().Set(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrInternal)
+				require.ErrorIs(t, err, errNoEncryptionKey)
+				require.ErrorContains(t, err, "failed to encrypt token")
+
+				var identifier interface{ ID() string }
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "jun5", identifier.ID())
+			},
+		},
+		{
+			uc: "with encryption, but failing jwks endpoint",
+			id: "jun6",
+			config: []byte(`
+encryption:
+  jwks_endpoint: ` + failingSrv.URL + `
+`),
+			subject: &subject.Subject{ID: "foo", Attributes: map[string]any{"baz": "bar"}},
+			configureMocks: func(t *testing.T, ctx *heimdallmocks.ContextMock, signer *heimdallmocks.JWTSignerMock,
+				cch *mocks.CacheMock, sub *subject.Subject,
+			) {
+				t.Helper()
+
+				signer.EXPECT().Hash().Return([]byte("foobar"))
+				signer.EXPECT().Sign(sub.ID, defaultJWTTTL, map[string]any{}).Return("foo.bar.baz", nil)
+
+				ctx.EXPECT().Signer().Return(signer)
+
+				cch.EXPECT().Get(mock.Anything, mock.Anything).Return(nil)
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrCommunication)
+				require.NotErrorIs(t, err, heimdall.ErrInternal)
+				require.ErrorContains(t, err, "failed to fetch recipient jwks")
+
+				var identifier interface{ ID() string }
+				require.ErrorAs(t, err, &identifier)
+				assert.Equal(t, "jun6", identifier.ID())
+			},
+		},
 	} {
 		t.Run("case="+tc.uc, func(t *testing.T) {
 			// GIVEN
@@ -73,7 +73,7 @@ func newOAuth2ClientCredentialsFinalizer(
 	conf.AuthMethod = x.IfThenElse(
 		len(conf.AuthMethod) == 0,
 		clientcredentials.AuthMethodBasicAuth,
-		clientcredentials.AuthMethodRequestBody,
+		conf.AuthMethod,
 	)
 
 	return &oauth2ClientCredentialsFinalizer{
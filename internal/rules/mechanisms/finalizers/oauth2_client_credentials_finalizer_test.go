@@ -103,7 +103,7 @@ auth_method: bar
 
 				require.Error(t, err)
 				require.ErrorIs(t, err, heimdall.ErrConfiguration)
-				assert.Contains(t, err.Error(), "'auth_method' must be one of [basic_auth request_body]")
+				assert.Contains(t, err.Error(), "'auth_method' must be one of [basic_auth request_body private_key_jwt tls_client_auth]")
 			},
 		},
 		{
@@ -131,6 +131,138 @@ client_secret: bar
 				assert.Equal(t, "Authorization", finalizer.headerName)
 			},
 		},
+		{
+			uc: "with private_key_jwt auth method but without signing key",
+			config: []byte(`
+token_url: https://foo.bar
+client_id: foo
+auth_method: private_key_jwt
+`),
+			assert: func(t *testing.T, err error, _ *oauth2ClientCredentialsFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'signing_key' is a required field")
+			},
+		},
+		{
+			uc: "with tls_client_auth auth method but without tls config",
+			config: []byte(`
+token_url: https://foo.bar
+client_id: foo
+auth_method: tls_client_auth
+`),
+			assert: func(t *testing.T, err error, _ *oauth2ClientCredentialsFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'tls' is a required field")
+			},
+		},
+		{
+			uc: "with signing key but without auth method",
+			config: []byte(`
+token_url: https://foo.bar
+client_id: foo
+signing_key:
+  key_store:
+    path: /some/file.pem
+`),
+			assert: func(t *testing.T, err error, _ *oauth2ClientCredentialsFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'signing_key' can only be set if auth_method is private_key_jwt")
+			},
+		},
+		{
+			uc: "with tls config but other auth method",
+			config: []byte(`
+token_url: https://foo.bar
+client_id: foo
+client_secret: bar
+auth_method: request_body
+tls:
+  key_store:
+    path: /some/file.pem
+`),
+			assert: func(t *testing.T, err error, _ *oauth2ClientCredentialsFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'tls' can only be set if auth_method is tls_client_auth")
+			},
+		},
+		{
+			uc: "with basic_auth auth method but without client secret",
+			config: []byte(`
+token_url: https://foo.bar
+client_id: foo
+auth_method: basic_auth
+signing_key:
+  key_store:
+    path: /some/file.pem
+`),
+			assert: func(t *testing.T, err error, _ *oauth2ClientCredentialsFinalizer) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "'client_secret' is a required field")
+			},
+		},
+		{
+			uc: "with valid private_key_jwt config",
+			config: []byte(`
+token_url: https://foo.bar
+client_id: foo
+auth_method: private_key_jwt
+signing_key:
+  key_store:
+    path: /some/file.pem
+  key_id: bar
+`),
+			assert: func(t *testing.T, err error, finalizer *oauth2ClientCredentialsFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, finalizer)
+
+				assert.Equal(t, clientcredentials.AuthMethodPrivateKeyJWT, finalizer.cfg.AuthMethod)
+				assert.Empty(t, finalizer.cfg.ClientSecret)
+				require.NotNil(t, finalizer.cfg.SigningKey)
+				assert.Equal(t, "/some/file.pem", finalizer.cfg.SigningKey.KeyStore.Path)
+				assert.Equal(t, "bar", finalizer.cfg.SigningKey.KeyID)
+				assert.Nil(t, finalizer.cfg.TLS)
+			},
+		},
+		{
+			uc: "with valid tls_client_auth config",
+			config: []byte(`
+token_url: https://foo.bar
+client_id: foo
+auth_method: tls_client_auth
+tls:
+  key_store:
+    path: /some/file.pem
+`),
+			assert: func(t *testing.T, err error, finalizer *oauth2ClientCredentialsFinalizer) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.NotNil(t, finalizer)
+
+				assert.Equal(t, clientcredentials.AuthMethodTLSClientAuth, finalizer.cfg.AuthMethod)
+				require.NotNil(t, finalizer.cfg.TLS)
+				assert.Equal(t, "/some/file.pem", finalizer.cfg.TLS.KeyStore.Path)
+				assert.Empty(t, finalizer.cfg.TLS.KeyID)
+				assert.Nil(t, finalizer.cfg.SigningKey)
+			},
+		},
 		{
 			uc: "with full valid config",
 			id: "full",
@@ -0,0 +1,216 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package httpsig
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported signature algorithm")
+	ErrUnsupportedKey       = errors.New("key cannot be used with the signature algorithm")
+	ErrInvalidSignature     = errors.New("invalid signature")
+)
+
+// Algorithm is one of the asymmetric signature algorithms registered by RFC 9421.
+type Algorithm string
+
+const (
+	AlgorithmRSAPSSSHA512    Algorithm = "rsa-pss-sha512"
+	AlgorithmRSAV15SHA256    Algorithm = "rsa-v1_5-sha256"
+	AlgorithmECDSAP256SHA256 Algorithm = "ecdsa-p256-sha256"
+	AlgorithmECDSAP384SHA384 Algorithm = "ecdsa-p384-sha384"
+	AlgorithmED25519         Algorithm = "ed25519"
+)
+
+const rsaPSSSaltLength = 64
+
+// ParseAlgorithm returns the algorithm identified by the value of the alg signature parameter.
+func ParseAlgorithm(name string) (Algorithm, error) {
+	switch alg := Algorithm(name); alg {
+	case AlgorithmRSAPSSSHA512, AlgorithmRSAV15SHA256, AlgorithmECDSAP256SHA256,
+		AlgorithmECDSAP384SHA384, AlgorithmED25519:
+		return alg, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, name)
+	}
+}
+
+// AlgorithmFromJOSE maps the JOSE algorithm of a JWK to the corresponding algorithm.
+func AlgorithmFromJOSE(name string) (Algorithm, error) {
+	switch name {
+	case "PS512":
+		return AlgorithmRSAPSSSHA512, nil
+	case "RS256":
+		return AlgorithmRSAV15SHA256, nil
+	case "ES256":
+		return AlgorithmECDSAP256SHA256, nil
+	case "ES384":
+		return AlgorithmECDSAP384SHA384, nil
+	case "EdDSA":
+		return AlgorithmED25519, nil
+	default:
+		return "", fmt.Errorf("%w: no equivalent for JOSE algorithm %s", ErrUnsupportedAlgorithm, name)
+	}
+}
+
+// AlgorithmForKey determines the algorithm to be used for signing with the given key.
+// RSA keys are used with RSASSA-PSS.
+func AlgorithmForKey(key crypto.PublicKey) (Algorithm, error) {
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		return AlgorithmRSAPSSSHA512, nil
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			return AlgorithmECDSAP256SHA256, nil
+		case elliptic.P384():
+			return AlgorithmECDSAP384SHA384, nil
+		}
+	case ed25519.PublicKey:
+		return AlgorithmED25519, nil
+	}
+
+	return "", fmt.Errorf("%w: no algorithm available for %T", ErrUnsupportedKey, key)
+}
+
+// SupportsKey returns true if the given public key can be used with the algorithm.
+func (a Algorithm) SupportsKey(key crypto.PublicKey) bool {
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		return a == AlgorithmRSAPSSSHA512 || a == AlgorithmRSAV15SHA256
+	case *ecdsa.PublicKey:
+		return (a == AlgorithmECDSAP256SHA256 && pub.Curve == elliptic.P256()) ||
+			(a == AlgorithmECDSAP384SHA384 && pub.Curve == elliptic.P384())
+	case ed25519.PublicKey:
+		return a == AlgorithmED25519
+	default:
+		return false
+	}
+}
+
+func (a Algorithm) hash() crypto.Hash {
+	switch a {
+	case AlgorithmRSAPSSSHA512:
+		return crypto.SHA512
+	case AlgorithmRSAV15SHA256, AlgorithmECDSAP256SHA256:
+		return crypto.SHA256
+	case AlgorithmECDSAP384SHA384:
+		return crypto.SHA384
+	default:
+		return 0
+	}
+}
+
+func (a Algorithm) digest(data []byte) []byte {
+	if a.hash() == 0 {
+		return data
+	}
+
+	md := a.hash().New()
+	md.Write(data)
+
+	return md.Sum(nil)
+}
+
+func (a Algorithm) sign(key crypto.Signer, data []byte) ([]byte, error) {
+	if !a.SupportsKey(key.Public()) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedKey, a)
+	}
+
+	var opts crypto.SignerOpts = a.hash()
+	if a == AlgorithmRSAPSSSHA512 {
+		opts = &rsa.PSSOptions{SaltLength: rsaPSSSaltLength, Hash: crypto.SHA512}
+	}
+
+	signature, err := key.Sign(rand.Reader, a.digest(data), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if pub, ok := key.Public().(*ecdsa.PublicKey); ok {
+		// RFC 9421 requires the raw concatenation of r and s instead of the ASN.1 encoding
+		return ecdsaSignatureToRaw(signature, pub.Curve)
+	}
+
+	return signature, nil
+}
+
+func (a Algorithm) verify(key crypto.PublicKey, data, signature []byte) error {
+	if !a.SupportsKey(key) {
+		return fmt.Errorf("%w: %s", ErrUnsupportedKey, a)
+	}
+
+	var valid bool
+
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		var err error
+
+		if a == AlgorithmRSAPSSSHA512 {
+			err = rsa.VerifyPSS(pub, crypto.SHA512, a.digest(data), signature,
+				&rsa.PSSOptions{SaltLength: rsaPSSSaltLength, Hash: crypto.SHA512})
+		} else {
+			err = rsa.VerifyPKCS1v15(pub, crypto.SHA256, a.digest(data), signature)
+		}
+
+		valid = err == nil
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8 //nolint:gomnd
+
+		if len(signature) == 2*size {
+			r := new(big.Int).SetBytes(signature[:size])
+			s := new(big.Int).SetBytes(signature[size:])
+			valid = ecdsa.Verify(pub, a.digest(data), r, s)
+		}
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(pub, data, signature)
+	}
+
+	if !valid {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+func ecdsaSignatureToRaw(signature []byte, curve elliptic.Curve) ([]byte, error) {
+	var sig struct {
+		R, S *big.Int
+	}
+
+	if _, err := asn1.Unmarshal(signature, &sig); err != nil {
+		return nil, err
+	}
+
+	size := (curve.Params().BitSize + 7) / 8 //nolint:gomnd
+	raw := make([]byte, 2*size)
+
+	sig.R.FillBytes(raw[:size])
+	sig.S.FillBytes(raw[size:])
+
+	return raw, nil
+}
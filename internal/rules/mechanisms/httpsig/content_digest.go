@@ -0,0 +1,82 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package httpsig
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+)
+
+const HeaderContentDigest = "Content-Digest"
+
+var (
+	ErrMalformedContentDigest   = errors.New("malformed content digest")
+	ErrUnsupportedContentDigest = errors.New("no supported content digest algorithm used")
+	ErrContentDigestMismatch    = errors.New("content digest does not match the content")
+)
+
+// VerifyContentDigest verifies the given value of the Content-Digest field as defined by RFC 9530
+// against the given content. Digests using other algorithms than sha-256 and sha-512 are ignored.
+// At least one digest using these must however be present and all of them must match.
+func VerifyContentDigest(value string, content []byte) error {
+	digests, err := ParseDictionary(value)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformedContentDigest, err)
+	}
+
+	verified := false
+
+	for _, digest := range digests {
+		var expected []byte
+
+		switch digest.Name {
+		case "sha-256":
+			sum := sha256.Sum256(content)
+			expected = sum[:]
+		case "sha-512":
+			sum := sha512.Sum512(content)
+			expected = sum[:]
+		default:
+			continue
+		}
+
+		item, ok := digest.Value.(Item)
+		if !ok {
+			return fmt.Errorf("%w: %s digest is not an item", ErrMalformedContentDigest, digest.Name)
+		}
+
+		actual, ok := item.Value.([]byte)
+		if !ok {
+			return fmt.Errorf("%w: %s digest is not a byte sequence", ErrMalformedContentDigest, digest.Name)
+		}
+
+		if subtle.ConstantTimeCompare(expected, actual) != 1 {
+			return fmt.Errorf("%w: %s", ErrContentDigestMismatch, digest.Name)
+		}
+
+		verified = true
+	}
+
+	if !verified {
+		return ErrUnsupportedContentDigest
+	}
+
+	return nil
+}
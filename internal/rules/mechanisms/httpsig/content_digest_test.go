@@ -0,0 +1,68 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package httpsig
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyContentDigest(t *testing.T) {
+	t.Parallel()
+
+	content := []byte(`{"hello": "world"}`)
+	sha256Sum := sha256.Sum256(content)
+	sha512Sum := sha512.Sum512(content)
+	otherSum := sha256.Sum256([]byte("foo"))
+
+	sha256Digest := "sha-256=:" + base64.StdEncoding.EncodeToString(sha256Sum[:]) + ":"
+	sha512Digest := "sha-512=:" + base64.StdEncoding.EncodeToString(sha512Sum[:]) + ":"
+	otherDigest := "sha-256=:" + base64.StdEncoding.EncodeToString(otherSum[:]) + ":"
+
+	for _, tc := range []struct {
+		uc    string
+		value string
+		err   error
+	}{
+		{uc: "valid sha-256 digest", value: sha256Digest},
+		{uc: "valid sha-512 digest", value: sha512Digest},
+		{uc: "valid digests with unsupported one", value: "md5=:AAAA:, " + sha256Digest + ", " + sha512Digest},
+		{uc: "not matching digest", value: otherDigest, err: ErrContentDigestMismatch},
+		{uc: "one of the digests does not match", value: sha512Digest + ", " + otherDigest, err: ErrContentDigestMismatch},
+		{uc: "only unsupported digests", value: "md5=:AAAA:, sha=:AAAA:", err: ErrUnsupportedContentDigest},
+		{uc: "empty value", value: "", err: ErrUnsupportedContentDigest},
+		{uc: "digest is not a byte sequence", value: `sha-256="foo"`, err: ErrMalformedContentDigest},
+		{uc: "digest is an inner list", value: "sha-256=(:AAAA:)", err: ErrMalformedContentDigest},
+		{uc: "malformed value", value: "sha-256=:AAAA", err: ErrMalformedContentDigest},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// WHEN
+			err := VerifyContentDigest(tc.value, content)
+
+			// THEN
+			if tc.err == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tc.err)
+			}
+		})
+	}
+}
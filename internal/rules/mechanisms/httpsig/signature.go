@@ -0,0 +1,227 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package httpsig
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+)
+
+const (
+	HeaderSignature      = "Signature"
+	HeaderSignatureInput = "Signature-Input"
+)
+
+var (
+	ErrNoSignature        = errors.New("no signature present")
+	ErrMalformedSignature = errors.New("malformed signature")
+)
+
+// Signature is a single signature as conveyed by a member of the Signature-Input and
+// the Signature fields.
+type Signature struct {
+	Label string
+	Input InnerList
+	Value []byte
+}
+
+// FromRequest extracts all signatures present in the Signature-Input and Signature fields
+// of the given request in the order of their definition in the Signature-Input field.
+func FromRequest(req *heimdall.Request) ([]*Signature, error) {
+	rawInput := req.Header(HeaderSignatureInput)
+	rawSignature := req.Header(HeaderSignature)
+
+	if len(rawInput) == 0 && len(rawSignature) == 0 {
+		return nil, ErrNoSignature
+	}
+
+	inputs, err := ParseDictionary(rawInput)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedSignature, err)
+	}
+
+	values, err := ParseDictionary(rawSignature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedSignature, err)
+	}
+
+	var signatures []*Signature
+
+	known := make(map[string]bool, len(inputs))
+
+	for _, member := range inputs {
+		if known[member.Name] {
+			continue
+		}
+
+		known[member.Name] = true
+
+		sig, err := newSignature(member.Name, inputs, values)
+		if err != nil {
+			return nil, err
+		}
+
+		signatures = append(signatures, sig)
+	}
+
+	if len(signatures) == 0 {
+		return nil, ErrNoSignature
+	}
+
+	return signatures, nil
+}
+
+func newSignature(label string, inputs, values Dictionary) (*Signature, error) {
+	rawInput, _ := inputs.Get(label)
+
+	input, ok := rawInput.(InnerList)
+	if !ok {
+		return nil, fmt.Errorf("%w: signature input %s is not an inner list", ErrMalformedSignature, label)
+	}
+
+	for _, item := range input.Items {
+		if _, ok = item.Value.(string); !ok {
+			return nil, fmt.Errorf("%w: signature input %s contains a component identifier, "+
+				"which is not a string", ErrMalformedSignature, label)
+		}
+	}
+
+	rawValue, present := values.Get(label)
+	if !present {
+		return nil, fmt.Errorf("%w: no signature present for label %s", ErrMalformedSignature, label)
+	}
+
+	value, ok := rawValue.(Item)
+	if !ok {
+		return nil, fmt.Errorf("%w: signature %s is not a byte sequence", ErrMalformedSignature, label)
+	}
+
+	sigValue, ok := value.Value.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("%w: signature %s is not a byte sequence", ErrMalformedSignature, label)
+	}
+
+	return &Signature{Label: label, Input: input, Value: sigValue}, nil
+}
+
+// ValidateLabel checks whether the given label can be used as a key in the Signature-Input
+// and Signature fields.
+func ValidateLabel(label string) error {
+	if dict, err := ParseDictionary(label); err != nil || len(dict) != 1 || dict[0].Name != label {
+		return fmt.Errorf("%w: invalid label %s", ErrMalformedSignature, label)
+	}
+
+	return nil
+}
+
+// Sign creates a signature over the given components of the request using the given key and
+// algorithm. The params are included in the signature input as is.
+func Sign(
+	req *heimdall.Request, label string, components []Item, params Params, alg Algorithm, key crypto.Signer,
+) (*Signature, error) {
+	if err := ValidateLabel(label); err != nil {
+		return nil, err
+	}
+
+	input := InnerList{Items: components, Params: params}
+
+	base, err := signatureBase(req, input)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := alg.sign(key, base)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signature{Label: label, Input: input, Value: value}, nil
+}
+
+// Verify verifies the signature using the given key and algorithm.
+func (s *Signature) Verify(req *heimdall.Request, alg Algorithm, key crypto.PublicKey) error {
+	base, err := signatureBase(req, s.Input)
+	if err != nil {
+		return err
+	}
+
+	return alg.verify(key, base, s.Value)
+}
+
+// Covers returns true if the given component is covered by the signature.
+func (s *Signature) Covers(component Item) bool {
+	identifier := component.String()
+
+	for _, item := range s.Input.Items {
+		if item.String() == identifier {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Components returns the serialized identifiers of all covered components.
+func (s *Signature) Components() []string {
+	components := make([]string, len(s.Input.Items))
+
+	for idx, item := range s.Input.Items {
+		components[idx] = item.String()
+	}
+
+	return components
+}
+
+func (s *Signature) KeyID() string     { return s.stringParam("keyid") }
+func (s *Signature) Algorithm() string { return s.stringParam("alg") }
+func (s *Signature) Tag() string       { return s.stringParam("tag") }
+func (s *Signature) Nonce() string     { return s.stringParam("nonce") }
+
+func (s *Signature) Created() (time.Time, bool) { return s.timeParam("created") }
+func (s *Signature) Expires() (time.Time, bool) { return s.timeParam("expires") }
+
+// SignatureInputField returns the serialized member of the Signature-Input field.
+func (s *Signature) SignatureInputField() string {
+	return s.Label + "=" + s.Input.String()
+}
+
+// SignatureField returns the serialized member of the Signature field.
+func (s *Signature) SignatureField() string {
+	return s.Label + "=" + Item{Value: s.Value}.String()
+}
+
+func (s *Signature) stringParam(name string) string {
+	value, _ := s.Input.Params.Get(name)
+	str, _ := value.(string)
+
+	return str
+}
+
+func (s *Signature) timeParam(name string) (time.Time, bool) {
+	value, _ := s.Input.Params.Get(name)
+
+	timestamp, ok := value.(int64)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return time.Unix(timestamp, 0), true
+}
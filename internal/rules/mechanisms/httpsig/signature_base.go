@@ -0,0 +1,227 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package httpsig
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+)
+
+var (
+	ErrUnsupportedComponent = errors.New("unsupported component")
+	ErrMissingComponent     = errors.New("component not present in the request")
+	ErrDuplicateComponent   = errors.New("component covered more than once")
+)
+
+// ParseComponentIdentifier parses a component identifier as used in configurations, like
+// @method, content-type or @query-param;name="foo". The quotes around the component name,
+// required by RFC 9421, are optional.
+func ParseComponentIdentifier(value string) (Item, error) {
+	if !strings.HasPrefix(value, `"`) {
+		name, params, hasParams := strings.Cut(value, ";")
+		value = Item{Value: strings.ToLower(strings.TrimSpace(name))}.String()
+
+		if hasParams {
+			value += ";" + params
+		}
+	}
+
+	item, err := ParseItem(value)
+	if err != nil {
+		return Item{}, err
+	}
+
+	if name, ok := item.Value.(string); !ok || len(name) == 0 {
+		return Item{}, fmt.Errorf("%w: component name must be a non empty string", ErrUnsupportedComponent)
+	}
+
+	return item, nil
+}
+
+// signatureBase creates the signature base as defined in RFC 9421, section 2.5 for the
+// components and signature parameters given by the inner list.
+func signatureBase(req *heimdall.Request, input InnerList) ([]byte, error) {
+	var sb strings.Builder
+
+	seen := make(map[string]bool, len(input.Items))
+
+	for _, component := range input.Items {
+		identifier := component.String()
+		if seen[identifier] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateComponent, identifier)
+		}
+
+		seen[identifier] = true
+
+		values, err := componentValues(req, component)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, value := range values {
+			sb.WriteString(identifier)
+			sb.WriteString(": ")
+			sb.WriteString(value)
+			sb.WriteByte('\n')
+		}
+	}
+
+	sb.WriteString(`"@signature-params": `)
+	sb.WriteString(input.String())
+
+	return []byte(sb.String()), nil
+}
+
+func componentValues(req *heimdall.Request, component Item) ([]string, error) {
+	name, ok := component.Value.(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: component identifier must be a string", ErrUnsupportedComponent)
+	}
+
+	if name == "@query-param" {
+		return queryParamValues(req, component)
+	}
+
+	if len(component.Params) != 0 {
+		return nil, fmt.Errorf("%w: parameters are not supported for %s", ErrUnsupportedComponent, name)
+	}
+
+	if !strings.HasPrefix(name, "@") {
+		value, err := headerValue(req, name)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{value}, nil
+	}
+
+	value, err := derivedComponentValue(req, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{value}, nil
+}
+
+// headerValue returns the canonicalized value of the given header field as defined in
+// RFC 9421, section 2.1. That is, the values of all field lines are trimmed and joined
+// with a comma followed by a single space.
+func headerValue(req *heimdall.Request, name string) (string, error) {
+	values := req.HeaderValues(name)
+	if len(values) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrMissingComponent, name)
+	}
+
+	trimmed := make([]string, len(values))
+	for idx, value := range values {
+		trimmed[idx] = strings.TrimSpace(value)
+	}
+
+	return strings.Join(trimmed, ", "), nil
+}
+
+func derivedComponentValue(req *heimdall.Request, name string) (string, error) {
+	reqURL := &req.URL.URL
+
+	switch name {
+	case "@method":
+		return req.Method, nil
+	case "@target-uri":
+		targetURI := url.URL{
+			Scheme:   strings.ToLower(reqURL.Scheme),
+			Host:     authority(reqURL),
+			Path:     reqURL.Path,
+			RawPath:  reqURL.RawPath,
+			RawQuery: reqURL.RawQuery,
+		}
+
+		return targetURI.String(), nil
+	case "@authority":
+		return authority(reqURL), nil
+	case "@scheme":
+		return strings.ToLower(reqURL.Scheme), nil
+	case "@request-target":
+		if len(reqURL.RawQuery) == 0 {
+			return path(reqURL), nil
+		}
+
+		return path(reqURL) + "?" + reqURL.RawQuery, nil
+	case "@path":
+		return path(reqURL), nil
+	case "@query":
+		return "?" + reqURL.RawQuery, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedComponent, name)
+	}
+}
+
+func queryParamValues(req *heimdall.Request, component Item) ([]string, error) {
+	value, _ := component.Params.Get("name")
+
+	encodedName, ok := value.(string)
+	if !ok || len(component.Params) != 1 {
+		return nil, fmt.Errorf("%w: @query-param requires exactly one name parameter", ErrUnsupportedComponent)
+	}
+
+	name, err := url.QueryUnescape(encodedName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid name parameter of @query-param: %w", ErrUnsupportedComponent, err)
+	}
+
+	query, err := url.ParseQuery(req.URL.RawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed query: %w", ErrMissingComponent, err)
+	}
+
+	values := query[name]
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%w: query parameter %s", ErrMissingComponent, name)
+	}
+
+	encoded := make([]string, len(values))
+	for idx, val := range values {
+		encoded[idx] = strings.ReplaceAll(url.QueryEscape(val), "+", "%20")
+	}
+
+	return encoded, nil
+}
+
+func authority(reqURL *url.URL) string {
+	host := strings.ToLower(reqURL.Host)
+	scheme := strings.ToLower(reqURL.Scheme)
+
+	switch scheme {
+	case "http":
+		return strings.TrimSuffix(host, ":80")
+	case "https":
+		return strings.TrimSuffix(host, ":443")
+	default:
+		return host
+	}
+}
+
+func path(reqURL *url.URL) string {
+	if escaped := reqURL.EscapedPath(); len(escaped) != 0 {
+		return escaped
+	}
+
+	return "/"
+}
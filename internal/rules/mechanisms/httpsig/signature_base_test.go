@@ -0,0 +1,290 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package httpsig
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/x"
+)
+
+func TestParseComponentIdentifier(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc     string
+		value  string
+		assert func(t *testing.T, err error, item Item)
+	}{
+		{
+			uc:    "derived component",
+			value: "@method",
+			assert: func(t *testing.T, err error, item Item) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, `"@method"`, item.String())
+			},
+		},
+		{
+			uc:    "field name is lowercased",
+			value: "Content-Type",
+			assert: func(t *testing.T, err error, item Item) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, `"content-type"`, item.String())
+			},
+		},
+		{
+			uc:    "unquoted name with parameters",
+			value: `@query-param;name="foo"`,
+			assert: func(t *testing.T, err error, item Item) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, `"@query-param";name="foo"`, item.String())
+			},
+		},
+		{
+			uc:    "quoted name",
+			value: `"@path"`,
+			assert: func(t *testing.T, err error, item Item) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, `"@path"`, item.String())
+			},
+		},
+		{
+			uc:    "empty name",
+			value: `""`,
+			assert: func(t *testing.T, err error, _ Item) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrUnsupportedComponent)
+			},
+		},
+		{
+			uc:    "malformed parameters",
+			value: `@query-param;name=`,
+			assert: func(t *testing.T, err error, _ Item) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrMalformedStructuredField)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// WHEN
+			item, err := ParseComponentIdentifier(tc.value)
+
+			// THEN
+			tc.assert(t, err, item)
+		})
+	}
+}
+
+func TestSignatureBase(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc         string
+		url        string
+		components []string
+		configure  func(t *testing.T, fnt *mocks.RequestFunctionsMock)
+		assert     func(t *testing.T, err error, base string)
+	}{
+		{
+			uc:  "all derived components",
+			url: "HTTPS://www.Example.com:443/foo%2Fbar?param=Value&Pet=dog&pet=cat%20fish&pet=bird",
+			components: []string{
+				"@method", "@target-uri", "@authority", "@scheme", "@request-target", "@path", "@query",
+				`@query-param;name="pet"`, `@query-param;name="Pet"`,
+			},
+			assert: func(t *testing.T, err error, base string) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, `"@method": POST
+"@target-uri": https://www.example.com/foo%2Fbar?param=Value&Pet=dog&pet=cat%20fish&pet=bird
+"@authority": www.example.com
+"@scheme": https
+"@request-target": /foo%2Fbar?param=Value&Pet=dog&pet=cat%20fish&pet=bird
+"@path": /foo%2Fbar
+"@query": ?param=Value&Pet=dog&pet=cat%20fish&pet=bird
+"@query-param";name="pet": cat%20fish
+"@query-param";name="pet": bird
+"@query-param";name="Pet": dog
+"@signature-params": ("@method" "@target-uri" "@authority" "@scheme" "@request-target" "@path" "@query" `+
+					`"@query-param";name="pet" "@query-param";name="Pet");created=1618884473`, base)
+			},
+		},
+		{
+			uc:         "empty path and query and non default port",
+			url:        "http://example.com:8080",
+			components: []string{"@authority", "@path", "@query", "@request-target"},
+			assert: func(t *testing.T, err error, base string) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, `"@authority": example.com:8080
+"@path": /
+"@query": ?
+"@request-target": /
+"@signature-params": ("@authority" "@path" "@query" "@request-target");created=1618884473`, base)
+			},
+		},
+		{
+			uc:         "header fields",
+			url:        "http://example.com/foo",
+			components: []string{"content-type", "X-Foo"},
+			configure: func(t *testing.T, fnt *mocks.RequestFunctionsMock) {
+				t.Helper()
+
+				fnt.EXPECT().HeaderValues("content-type").Return([]string{"application/json"})
+				fnt.EXPECT().HeaderValues("x-foo").Return([]string{"  bar,  baz "})
+			},
+			assert: func(t *testing.T, err error, base string) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, `"content-type": application/json
+"x-foo": bar,  baz
+"@signature-params": ("content-type" "x-foo");created=1618884473`, base)
+			},
+		},
+		{
+			uc:         "repeated header field",
+			url:        "http://example.com/foo",
+			components: []string{"x-foo"},
+			configure: func(t *testing.T, fnt *mocks.RequestFunctionsMock) {
+				t.Helper()
+
+				// see RFC 9421, section 2.1
+				fnt.EXPECT().HeaderValues("x-foo").Return([]string{"  bar  ", "baz "})
+			},
+			assert: func(t *testing.T, err error, base string) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, `"x-foo": bar, baz
+"@signature-params": ("x-foo");created=1618884473`, base)
+			},
+		},
+		{
+			uc:         "missing header field",
+			url:        "http://example.com/foo",
+			components: []string{"content-type"},
+			configure: func(t *testing.T, fnt *mocks.RequestFunctionsMock) {
+				t.Helper()
+
+				fnt.EXPECT().HeaderValues("content-type").Return(nil)
+			},
+			assert: func(t *testing.T, err error, _ string) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrMissingComponent)
+			},
+		},
+		{
+			uc:         "missing query parameter",
+			url:        "http://example.com/foo?bar=baz",
+			components: []string{`@query-param;name="foo"`},
+			assert: func(t *testing.T, err error, _ string) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrMissingComponent)
+			},
+		},
+		{
+			uc:         "query parameter without name",
+			url:        "http://example.com/foo?bar=baz",
+			components: []string{`@query-param`},
+			assert: func(t *testing.T, err error, _ string) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrUnsupportedComponent)
+			},
+		},
+		{
+			uc:         "unsupported derived component",
+			url:        "http://example.com/foo",
+			components: []string{"@status"},
+			assert: func(t *testing.T, err error, _ string) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrUnsupportedComponent)
+			},
+		},
+		{
+			uc:         "header field with parameters",
+			url:        "http://example.com/foo",
+			components: []string{"content-type;sf"},
+			assert: func(t *testing.T, err error, _ string) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrUnsupportedComponent)
+			},
+		},
+		{
+			uc:         "duplicate component",
+			url:        "http://example.com/foo",
+			components: []string{"@path", "@path"},
+			assert: func(t *testing.T, err error, _ string) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrDuplicateComponent)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			reqURL, err := url.Parse(tc.url)
+			require.NoError(t, err)
+
+			configure := x.IfThenElse(tc.configure != nil,
+				tc.configure,
+				func(t *testing.T, _ *mocks.RequestFunctionsMock) { t.Helper() })
+			fnt := mocks.NewRequestFunctionsMock(t)
+			configure(t, fnt)
+
+			input := InnerList{Params: Params{{Name: "created", Value: int64(1618884473)}}}
+
+			for _, component := range tc.components {
+				item, err := ParseComponentIdentifier(component)
+				require.NoError(t, err)
+
+				input.Items = append(input.Items, item)
+			}
+
+			req := &heimdall.Request{RequestFunctions: fnt, Method: "POST", URL: &heimdall.URL{URL: *reqURL}}
+
+			// WHEN
+			base, err := signatureBase(req, input)
+
+			// THEN
+			tc.assert(t, err, string(base))
+		})
+	}
+}
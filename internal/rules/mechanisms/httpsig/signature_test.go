@@ -0,0 +1,293 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package httpsig
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/heimdall/mocks"
+)
+
+func testRequest(t *testing.T, rawURL string, headers map[string]string) *heimdall.Request {
+	t.Helper()
+
+	reqURL, err := url.Parse(rawURL)
+	require.NoError(t, err)
+
+	fnt := mocks.NewRequestFunctionsMock(t)
+	fnt.EXPECT().Header(mock.Anything).RunAndReturn(func(name string) string { return headers[name] }).Maybe()
+	fnt.EXPECT().HeaderValues(mock.Anything).RunAndReturn(func(name string) []string {
+		if value, ok := headers[name]; ok {
+			return []string{value}
+		}
+
+		return nil
+	}).Maybe()
+
+	return &heimdall.Request{RequestFunctions: fnt, Method: "POST", URL: &heimdall.URL{URL: *reqURL}}
+}
+
+func TestVerifyRFC9421TestVector(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	// request, key and signature from RFC 9421, appendix B.2.6
+	block, _ := pem.Decode([]byte(`-----BEGIN PUBLIC KEY-----
+MCowBQYDK2VwAyEAJrQLj5P/89iXES9+vFgrIy29clF9CC/oPPsw3c5D0bs=
+-----END PUBLIC KEY-----`))
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	require.NoError(t, err)
+
+	req := testRequest(t, "https://example.com/foo?param=Value&Pet=dog", map[string]string{
+		"Signature-Input": `sig-b26=("date" "@method" "@path" "@authority" "content-type" "content-length")` +
+			`;created=1618884473;keyid="test-key-ed25519"`,
+		"Signature": `sig-b26=:wqcAqbmYJ2ji2glfAMaRy4gruYYnx2nEFN2HN6jrnDnQCK1u02Gb04v9EDgwUPiu4A0w6vuQv5lIp5WPpB` +
+			`KRCw==:`,
+		"date":           "Tue, 20 Apr 2021 02:07:55 GMT",
+		"content-type":   "application/json",
+		"content-length": "18",
+	})
+
+	// WHEN
+	sigs, err := FromRequest(req)
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, sigs, 1)
+
+	sig := sigs[0]
+	created, ok := sig.Created()
+
+	assert.True(t, ok)
+	assert.Equal(t, int64(1618884473), created.Unix())
+	assert.Equal(t, "test-key-ed25519", sig.KeyID())
+	assert.Empty(t, sig.Algorithm())
+	assert.True(t, sig.Covers(Item{Value: "@authority"}))
+	assert.False(t, sig.Covers(Item{Value: "@query"}))
+	require.NoError(t, sig.Verify(req, AlgorithmED25519, key))
+
+	// and the signature is rejected if a covered component differs
+	tampered := testRequest(t, "https://example.com/bar?param=Value&Pet=dog", map[string]string{
+		"date":           "Tue, 20 Apr 2021 02:07:55 GMT",
+		"content-type":   "application/json",
+		"content-length": "18",
+	})
+	require.ErrorIs(t, sig.Verify(tampered, AlgorithmED25519, key), ErrInvalidSignature)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecP256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ecP384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		alg Algorithm
+		key crypto.Signer
+	}{
+		{alg: AlgorithmRSAPSSSHA512, key: rsaKey},
+		{alg: AlgorithmRSAV15SHA256, key: rsaKey},
+		{alg: AlgorithmECDSAP256SHA256, key: ecP256Key},
+		{alg: AlgorithmECDSAP384SHA384, key: ecP384Key},
+		{alg: AlgorithmED25519, key: edKey},
+	} {
+		t.Run("alg="+string(tc.alg), func(t *testing.T) {
+			// GIVEN
+			req := testRequest(t, "https://example.com/foo?bar=baz", map[string]string{"content-type": "text/plain"})
+			components := []Item{{Value: "@method"}, {Value: "@authority"}, {Value: "content-type"}}
+			params := Params{
+				{Name: "created", Value: time.Now().Unix()},
+				{Name: "keyid", Value: "foo"},
+				{Name: "alg", Value: string(tc.alg)},
+			}
+
+			// WHEN
+			sig, err := Sign(req, "test", components, params, tc.alg, tc.key)
+
+			// THEN
+			require.NoError(t, err)
+
+			signedReq := testRequest(t, "https://example.com:443/foo?bar=baz", map[string]string{
+				"content-type":    "text/plain",
+				"Signature-Input": "other=();created=1, " + sig.SignatureInputField(),
+				"Signature":       "other=:AA==:, " + sig.SignatureField(),
+			})
+
+			sigs, err := FromRequest(signedReq)
+			require.NoError(t, err)
+			require.Len(t, sigs, 2)
+
+			received := sigs[1]
+			assert.Equal(t, "test", received.Label)
+			assert.Equal(t, "foo", received.KeyID())
+			assert.Equal(t, string(tc.alg), received.Algorithm())
+			assert.Equal(t, []string{`"@method"`, `"@authority"`, `"content-type"`}, received.Components())
+
+			alg, err := ParseAlgorithm(received.Algorithm())
+			require.NoError(t, err)
+			require.NoError(t, received.Verify(signedReq, alg, tc.key.Public()))
+
+			received.Value[0] ^= 0xff
+			require.ErrorIs(t, received.Verify(signedReq, alg, tc.key.Public()), ErrInvalidSignature)
+		})
+	}
+}
+
+func TestSignWithUnsuitableKey(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	req := testRequest(t, "https://example.com/foo", nil)
+
+	// WHEN
+	_, err = Sign(req, "test", []Item{{Value: "@method"}}, nil, AlgorithmECDSAP384SHA384, key)
+
+	// THEN
+	require.ErrorIs(t, err, ErrUnsupportedKey)
+}
+
+func TestSignWithInvalidLabel(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	req := testRequest(t, "https://example.com/foo", nil)
+
+	// WHEN
+	_, err = Sign(req, "Foo Bar", []Item{{Value: "@method"}}, nil, AlgorithmECDSAP256SHA256, key)
+
+	// THEN
+	require.ErrorIs(t, err, ErrMalformedSignature)
+}
+
+func TestFromRequest(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc      string
+		headers map[string]string
+		err     error
+	}{
+		{uc: "no signature headers", err: ErrNoSignature},
+		{
+			uc:      "malformed signature input",
+			headers: map[string]string{"Signature-Input": `sig=(`, "Signature": `sig=:AA==:`},
+			err:     ErrMalformedSignature,
+		},
+		{
+			uc:      "malformed signature",
+			headers: map[string]string{"Signature-Input": `sig=("@method")`, "Signature": `sig=:AA`},
+			err:     ErrMalformedSignature,
+		},
+		{
+			uc:      "signature input is not an inner list",
+			headers: map[string]string{"Signature-Input": `sig="@method"`, "Signature": `sig=:AA==:`},
+			err:     ErrMalformedSignature,
+		},
+		{
+			uc:      "component identifier is not a string",
+			headers: map[string]string{"Signature-Input": `sig=(method)`, "Signature": `sig=:AA==:`},
+			err:     ErrMalformedSignature,
+		},
+		{
+			uc:      "signature missing for label",
+			headers: map[string]string{"Signature-Input": `sig=("@method")`, "Signature": `other=:AA==:`},
+			err:     ErrMalformedSignature,
+		},
+		{
+			uc:      "signature is not a byte sequence",
+			headers: map[string]string{"Signature-Input": `sig=("@method")`, "Signature": `sig="AA=="`},
+			err:     ErrMalformedSignature,
+		},
+		{
+			uc:      "signature without input",
+			headers: map[string]string{"Signature": `sig=:AA==:`},
+			err:     ErrNoSignature,
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			req := testRequest(t, "https://example.com/foo", tc.headers)
+
+			// WHEN
+			_, err := FromRequest(req)
+
+			// THEN
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestAlgorithmSelection(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecP521Key, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+
+	alg, err := AlgorithmForKey(rsaKey.Public())
+	require.NoError(t, err)
+	assert.Equal(t, AlgorithmRSAPSSSHA512, alg)
+
+	_, err = AlgorithmForKey(ecP521Key.Public())
+	require.ErrorIs(t, err, ErrUnsupportedKey)
+
+	alg, err = AlgorithmFromJOSE("ES384")
+	require.NoError(t, err)
+	assert.Equal(t, AlgorithmECDSAP384SHA384, alg)
+
+	_, err = AlgorithmFromJOSE("PS256")
+	require.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+
+	_, err = ParseAlgorithm("hmac-sha256")
+	require.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+
+	assert.False(t, AlgorithmECDSAP256SHA256.SupportsKey(ecP521Key.Public()))
+	assert.True(t, AlgorithmRSAV15SHA256.SupportsKey(rsaKey.Public()))
+}
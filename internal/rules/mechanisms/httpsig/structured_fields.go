@@ -0,0 +1,510 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package httpsig
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// This file implements the subset of RFC 8941 (Structured Field Values for HTTP) required
+// to parse and serialize the Signature and Signature-Input fields defined by RFC 9421.
+// Decimals are not supported as neither of these fields makes use of them.
+
+var ErrMalformedStructuredField = errors.New("malformed structured field")
+
+// Token represents a structured field token value.
+type Token string
+
+// Param is a single, named parameter of an item or an inner list. The value is of
+// type int64, string, Token, []byte or bool.
+type Param struct {
+	Name  string
+	Value any
+}
+
+// Params is the ordered list of parameters of an item or an inner list.
+type Params []Param
+
+// Get returns the value of the parameter with the given name.
+func (p Params) Get(name string) (any, bool) {
+	for _, param := range p {
+		if param.Name == name {
+			return param.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// Item is a bare item together with its parameters.
+type Item struct {
+	Value  any
+	Params Params
+}
+
+// InnerList is a list of items together with the parameters of the list.
+type InnerList struct {
+	Items  []Item
+	Params Params
+}
+
+// DictMember is an entry of a dictionary. Its value is either an Item or an InnerList.
+type DictMember struct {
+	Name  string
+	Value any
+}
+
+// Dictionary is the ordered list of members of a structured field dictionary.
+type Dictionary []DictMember
+
+// Get returns the value of the member with the given name. As mandated by RFC 8941, the
+// last occurrence wins if a name is used more than once.
+func (d Dictionary) Get(name string) (any, bool) {
+	for i := len(d) - 1; i >= 0; i-- {
+		if d[i].Name == name {
+			return d[i].Value, true
+		}
+	}
+
+	return nil, false
+}
+
+func (i Item) String() string {
+	var sb strings.Builder
+
+	serializeBareItem(&sb, i.Value)
+	serializeParams(&sb, i.Params)
+
+	return sb.String()
+}
+
+func (l InnerList) String() string {
+	var sb strings.Builder
+
+	sb.WriteByte('(')
+
+	for idx, item := range l.Items {
+		if idx != 0 {
+			sb.WriteByte(' ')
+		}
+
+		sb.WriteString(item.String())
+	}
+
+	sb.WriteByte(')')
+	serializeParams(&sb, l.Params)
+
+	return sb.String()
+}
+
+func serializeParams(sb *strings.Builder, params Params) {
+	for _, param := range params {
+		sb.WriteByte(';')
+		sb.WriteString(param.Name)
+
+		if val, ok := param.Value.(bool); ok && val {
+			continue
+		}
+
+		sb.WriteByte('=')
+		serializeBareItem(sb, param.Value)
+	}
+}
+
+func serializeBareItem(sb *strings.Builder, value any) {
+	switch val := value.(type) {
+	case int64:
+		sb.WriteString(strconv.FormatInt(val, 10))
+	case string:
+		sb.WriteByte('"')
+
+		for idx := 0; idx < len(val); idx++ {
+			if val[idx] == '"' || val[idx] == '\\' {
+				sb.WriteByte('\\')
+			}
+
+			sb.WriteByte(val[idx])
+		}
+
+		sb.WriteByte('"')
+	case Token:
+		sb.WriteString(string(val))
+	case []byte:
+		sb.WriteByte(':')
+		sb.WriteString(base64.StdEncoding.EncodeToString(val))
+		sb.WriteByte(':')
+	case bool:
+		if val {
+			sb.WriteString("?1")
+		} else {
+			sb.WriteString("?0")
+		}
+	}
+}
+
+type sfParser struct {
+	data string
+	pos  int
+}
+
+// ParseDictionary parses the given field value as a structured field dictionary.
+func ParseDictionary(value string) (Dictionary, error) {
+	parser := &sfParser{data: value}
+	parser.skipSP()
+
+	var dict Dictionary
+
+	for !parser.eof() {
+		name, err := parser.parseKey()
+		if err != nil {
+			return nil, err
+		}
+
+		var member any
+
+		if parser.peek() == '=' {
+			parser.pos++
+
+			if member, err = parser.parseItemOrInnerList(); err != nil {
+				return nil, err
+			}
+		} else {
+			params, err := parser.parseParams()
+			if err != nil {
+				return nil, err
+			}
+
+			member = Item{Value: true, Params: params}
+		}
+
+		dict = append(dict, DictMember{Name: name, Value: member})
+
+		parser.skipOWS()
+
+		if parser.eof() {
+			return dict, nil
+		}
+
+		if parser.peek() != ',' {
+			return nil, parser.errorf("expected ','")
+		}
+
+		parser.pos++
+		parser.skipOWS()
+
+		if parser.eof() {
+			return nil, parser.errorf("trailing ','")
+		}
+	}
+
+	return dict, nil
+}
+
+// ParseItem parses the given field value as a structured field item.
+func ParseItem(value string) (Item, error) {
+	parser := &sfParser{data: value}
+	parser.skipSP()
+
+	item, err := parser.parseItem()
+	if err != nil {
+		return Item{}, err
+	}
+
+	parser.skipSP()
+
+	if !parser.eof() {
+		return Item{}, parser.errorf("unexpected trailing characters")
+	}
+
+	return item, nil
+}
+
+func (p *sfParser) eof() bool { return p.pos >= len(p.data) }
+
+func (p *sfParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+
+	return p.data[p.pos]
+}
+
+func (p *sfParser) skipSP() {
+	for !p.eof() && p.data[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *sfParser) skipOWS() {
+	for !p.eof() && (p.data[p.pos] == ' ' || p.data[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *sfParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s at position %d", ErrMalformedStructuredField, fmt.Sprintf(format, args...), p.pos)
+}
+
+func (p *sfParser) parseItemOrInnerList() (any, error) {
+	if p.peek() == '(' {
+		return p.parseInnerList()
+	}
+
+	return p.parseItem()
+}
+
+func (p *sfParser) parseInnerList() (InnerList, error) {
+	var list InnerList
+
+	p.pos++
+
+	for !p.eof() {
+		p.skipSP()
+
+		if p.peek() == ')' {
+			p.pos++
+
+			params, err := p.parseParams()
+			if err != nil {
+				return InnerList{}, err
+			}
+
+			list.Params = params
+
+			return list, nil
+		}
+
+		item, err := p.parseItem()
+		if err != nil {
+			return InnerList{}, err
+		}
+
+		list.Items = append(list.Items, item)
+
+		if next := p.peek(); next != ' ' && next != ')' {
+			return InnerList{}, p.errorf("expected ' ' or ')' in inner list")
+		}
+	}
+
+	return InnerList{}, p.errorf("unterminated inner list")
+}
+
+func (p *sfParser) parseItem() (Item, error) {
+	value, err := p.parseBareItem()
+	if err != nil {
+		return Item{}, err
+	}
+
+	params, err := p.parseParams()
+	if err != nil {
+		return Item{}, err
+	}
+
+	return Item{Value: value, Params: params}, nil
+}
+
+func (p *sfParser) parseParams() (Params, error) {
+	var params Params
+
+	for p.peek() == ';' {
+		p.pos++
+		p.skipSP()
+
+		name, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+
+		var value any = true
+
+		if p.peek() == '=' {
+			p.pos++
+
+			if value, err = p.parseBareItem(); err != nil {
+				return nil, err
+			}
+		}
+
+		replaced := false
+
+		for idx := range params {
+			if params[idx].Name == name {
+				params[idx].Value = value
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			params = append(params, Param{Name: name, Value: value})
+		}
+	}
+
+	return params, nil
+}
+
+func (p *sfParser) parseKey() (string, error) {
+	start := p.pos
+
+	if chr := p.peek(); chr != '*' && !isLCAlpha(chr) {
+		return "", p.errorf("invalid key")
+	}
+
+	for !p.eof() {
+		chr := p.data[p.pos]
+		if !isLCAlpha(chr) && !isDigit(chr) && chr != '_' && chr != '-' && chr != '.' && chr != '*' {
+			break
+		}
+
+		p.pos++
+	}
+
+	return p.data[start:p.pos], nil
+}
+
+func (p *sfParser) parseBareItem() (any, error) {
+	chr := p.peek()
+
+	switch {
+	case chr == '-' || isDigit(chr):
+		return p.parseInteger()
+	case chr == '"':
+		return p.parseString()
+	case chr == '*' || isAlpha(chr):
+		return p.parseToken(), nil
+	case chr == ':':
+		return p.parseByteSequence()
+	case chr == '?':
+		return p.parseBoolean()
+	default:
+		return nil, p.errorf("unexpected character")
+	}
+}
+
+func (p *sfParser) parseInteger() (int64, error) {
+	const maxIntegerDigits = 15
+
+	start := p.pos
+
+	if p.peek() == '-' {
+		p.pos++
+	}
+
+	digitsStart := p.pos
+
+	for !p.eof() && isDigit(p.data[p.pos]) {
+		p.pos++
+	}
+
+	if p.pos == digitsStart || p.pos-digitsStart > maxIntegerDigits {
+		return 0, p.errorf("invalid integer")
+	}
+
+	if p.peek() == '.' {
+		return 0, p.errorf("decimals are not supported")
+	}
+
+	return strconv.ParseInt(p.data[start:p.pos], 10, 64)
+}
+
+func (p *sfParser) parseString() (string, error) {
+	var sb strings.Builder
+
+	p.pos++
+
+	for !p.eof() {
+		chr := p.data[p.pos]
+		p.pos++
+
+		switch {
+		case chr == '\\':
+			if p.eof() || (p.data[p.pos] != '"' && p.data[p.pos] != '\\') {
+				return "", p.errorf("invalid escape sequence in string")
+			}
+
+			sb.WriteByte(p.data[p.pos])
+			p.pos++
+		case chr == '"':
+			return sb.String(), nil
+		case chr < 0x20 || chr > 0x7e: //nolint:gomnd
+			return "", p.errorf("invalid character in string")
+		default:
+			sb.WriteByte(chr)
+		}
+	}
+
+	return "", p.errorf("unterminated string")
+}
+
+func (p *sfParser) parseToken() Token {
+	start := p.pos
+
+	for !p.eof() && (isTChar(p.data[p.pos]) || p.data[p.pos] == ':' || p.data[p.pos] == '/') {
+		p.pos++
+	}
+
+	return Token(p.data[start:p.pos])
+}
+
+func (p *sfParser) parseByteSequence() ([]byte, error) {
+	p.pos++
+
+	end := strings.IndexByte(p.data[p.pos:], ':')
+	if end == -1 {
+		return nil, p.errorf("unterminated byte sequence")
+	}
+
+	encoded := p.data[p.pos : p.pos+end]
+	p.pos += end + 1
+
+	value, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid byte sequence: %w", ErrMalformedStructuredField, err)
+	}
+
+	return value, nil
+}
+
+func (p *sfParser) parseBoolean() (bool, error) {
+	p.pos++
+
+	switch p.peek() {
+	case '0':
+		p.pos++
+
+		return false, nil
+	case '1':
+		p.pos++
+
+		return true, nil
+	default:
+		return false, p.errorf("invalid boolean")
+	}
+}
+
+func isLCAlpha(chr byte) bool { return chr >= 'a' && chr <= 'z' }
+
+func isAlpha(chr byte) bool { return isLCAlpha(chr) || (chr >= 'A' && chr <= 'Z') }
+
+func isDigit(chr byte) bool { return chr >= '0' && chr <= '9' }
+
+func isTChar(chr byte) bool {
+	return isAlpha(chr) || isDigit(chr) || strings.IndexByte("!#$%&'*+-.^_`|~", chr) != -1
+}
@@ -0,0 +1,185 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package httpsig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDictionary(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc     string
+		value  string
+		assert func(t *testing.T, err error, dict Dictionary)
+	}{
+		{
+			uc:    "empty value",
+			value: "",
+			assert: func(t *testing.T, err error, dict Dictionary) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Empty(t, dict)
+			},
+		},
+		{
+			uc:    "all supported types",
+			value: `a=1, b="foo \"bar\"", c=tok/en:1, d=:AQID:, e=?0, f, g=("x" "y";n=-2);p`,
+			assert: func(t *testing.T, err error, dict Dictionary) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, Dictionary{
+					{Name: "a", Value: Item{Value: int64(1)}},
+					{Name: "b", Value: Item{Value: `foo "bar"`}},
+					{Name: "c", Value: Item{Value: Token("tok/en:1")}},
+					{Name: "d", Value: Item{Value: []byte{1, 2, 3}}},
+					{Name: "e", Value: Item{Value: false}},
+					{Name: "f", Value: Item{Value: true}},
+					{Name: "g", Value: InnerList{
+						Items: []Item{
+							{Value: "x"},
+							{Value: "y", Params: Params{{Name: "n", Value: int64(-2)}}},
+						},
+						Params: Params{{Name: "p", Value: true}},
+					}},
+				}, dict)
+			},
+		},
+		{
+			uc:    "duplicate keys",
+			value: `a=1, a=2`,
+			assert: func(t *testing.T, err error, dict Dictionary) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				val, ok := dict.Get("a")
+				require.True(t, ok)
+				assert.Equal(t, Item{Value: int64(2)}, val)
+			},
+		},
+		{
+			uc:    "trailing comma",
+			value: `a=1,`,
+			assert: func(t *testing.T, err error, _ Dictionary) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrMalformedStructuredField)
+			},
+		},
+		{
+			uc:    "invalid key",
+			value: `A=1`,
+			assert: func(t *testing.T, err error, _ Dictionary) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrMalformedStructuredField)
+			},
+		},
+		{
+			uc:    "unterminated string",
+			value: `a="foo`,
+			assert: func(t *testing.T, err error, _ Dictionary) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrMalformedStructuredField)
+			},
+		},
+		{
+			uc:    "unterminated inner list",
+			value: `a=("foo"`,
+			assert: func(t *testing.T, err error, _ Dictionary) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrMalformedStructuredField)
+			},
+		},
+		{
+			uc:    "invalid byte sequence",
+			value: `a=:#foo:`,
+			assert: func(t *testing.T, err error, _ Dictionary) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrMalformedStructuredField)
+			},
+		},
+		{
+			uc:    "decimal",
+			value: `a=1.5`,
+			assert: func(t *testing.T, err error, _ Dictionary) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrMalformedStructuredField)
+			},
+		},
+		{
+			uc:    "missing separator",
+			value: `a=1 b=2`,
+			assert: func(t *testing.T, err error, _ Dictionary) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrMalformedStructuredField)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// WHEN
+			dict, err := ParseDictionary(tc.value)
+
+			// THEN
+			tc.assert(t, err, dict)
+		})
+	}
+}
+
+func TestSerializeInnerList(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	list := InnerList{
+		Items: []Item{
+			{Value: "@method"},
+			{Value: "@query-param", Params: Params{{Name: "name", Value: `a"b\c`}}},
+		},
+		Params: Params{
+			{Name: "created", Value: int64(1618884473)},
+			{Name: "alg", Value: Token("foo")},
+			{Name: "nonce", Value: []byte("bar")},
+			{Name: "a", Value: true},
+			{Name: "b", Value: false},
+		},
+	}
+
+	// WHEN
+	value := list.String()
+
+	// THEN
+	assert.Equal(t,
+		`("@method" "@query-param";name="a\"b\\c");created=1618884473;alg=foo;nonce=:YmFy:;a;b=?0`, value)
+
+	dict, err := ParseDictionary("sig=" + value)
+	require.NoError(t, err)
+
+	parsed, _ := dict.Get("sig")
+	assert.Equal(t, list, parsed)
+}
@@ -30,6 +30,47 @@ func (_m *FactoryMock) EXPECT() *FactoryMock_Expecter {
 	return &FactoryMock_Expecter{mock: &_m.Mock}
 }
 
+// Close provides a mock function with given fields:
+func (_m *FactoryMock) Close() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FactoryMock_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
+type FactoryMock_Close_Call struct {
+	*mock.Call
+}
+
+// Close is a helper method to define mock.On call
+func (_e *FactoryMock_Expecter) Close() *FactoryMock_Close_Call {
+	return &FactoryMock_Close_Call{Call: _e.mock.On("Close")}
+}
+
+func (_c *FactoryMock_Close_Call) Run(run func()) *FactoryMock_Close_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *FactoryMock_Close_Call) Return(_a0 error) *FactoryMock_Close_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *FactoryMock_Close_Call) RunAndReturn(run func() error) *FactoryMock_Close_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateAuthenticator provides a mock function with given fields: version, id, conf
 func (_m *FactoryMock) CreateAuthenticator(version string, id string, conf config.MechanismConfig) (authenticators.Authenticator, error) {
 	ret := _m.Called(version, id, conf)
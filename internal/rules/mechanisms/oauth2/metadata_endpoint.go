@@ -95,6 +95,9 @@ func (e *MetadataEndpoint) decodeResponse(resp *http.Response) (ServerMetadata,
 		Issuer                   string `json:"issuer"`
 		JWKSEndpointURL          string `json:"jwks_uri"`
 		IntrospectionEndpointURL string `json:"introspection_endpoint"`
+		TokenEndpointURL         string `json:"token_endpoint"`
+		AuthorizationEndpointURL string `json:"authorization_endpoint"`
+		EndSessionEndpointURL    string `json:"end_session_endpoint"`
 	}
 
 	var spec metadata
@@ -115,9 +118,16 @@ func (e *MetadataEndpoint) decodeResponse(resp *http.Response) (ServerMetadata,
 			"received introspection_endpoint contains a template, which is not allowed")
 	}
 
+	if strings.Contains(spec.TokenEndpointURL, "{{") &&
+		strings.Contains(spec.TokenEndpointURL, "}}") {
+		return ServerMetadata{}, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"received token_endpoint contains a template, which is not allowed")
+	}
+
 	var (
 		jwksEP          *endpoint.Endpoint
 		introspectionEP *endpoint.Endpoint
+		tokenEP         *endpoint.Endpoint
 	)
 
 	if len(spec.JWKSEndpointURL) != 0 {
@@ -139,9 +149,23 @@ func (e *MetadataEndpoint) decodeResponse(resp *http.Response) (ServerMetadata,
 		}
 	}
 
+	if len(spec.TokenEndpointURL) != 0 {
+		tokenEP = &endpoint.Endpoint{
+			URL:    spec.TokenEndpointURL,
+			Method: http.MethodPost,
+			Headers: map[string]string{
+				"Content-Type": "application/x-www-form-urlencoded",
+				"Accept":       "application/json",
+			},
+		}
+	}
+
 	return ServerMetadata{
 		Issuer:                spec.Issuer,
 		JWKSEndpoint:          jwksEP,
 		IntrospectionEndpoint: introspectionEP,
+		TokenEndpoint:         tokenEP,
+		AuthorizationEndpoint: spec.AuthorizationEndpointURL,
+		EndSessionEndpoint:    spec.EndSessionEndpointURL,
 	}, nil
 }
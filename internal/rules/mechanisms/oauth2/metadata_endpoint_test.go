@@ -23,6 +23,9 @@ func TestMetadataEndpointGet(t *testing.T) {
 		Issuer                             string   `json:"issuer"`
 		JWKSEndpointURL                    string   `json:"jwks_uri"`
 		IntrospectionEndpointURL           string   `json:"introspection_endpoint"`
+		TokenEndpointURL                   string   `json:"token_endpoint,omitempty"`
+		AuthorizationEndpointURL           string   `json:"authorization_endpoint,omitempty"`
+		EndSessionEndpointURL              string   `json:"end_session_endpoint,omitempty"`
 		TokenEndpointAuthSigningAlgorithms []string `json:"token_endpoint_auth_signing_alg_values_supported"`
 	}
 
@@ -234,6 +237,9 @@ func TestMetadataEndpointGet(t *testing.T) {
 					Issuer:                             fmt.Sprintf("%s/bar", srv.URL),
 					JWKSEndpointURL:                    "https://foo.bar/jwks",
 					IntrospectionEndpointURL:           "https://foo.bar/introspection",
+					TokenEndpointURL:                   "https://foo.bar/token",
+					AuthorizationEndpointURL:           "https://foo.bar/auth",
+					EndSessionEndpointURL:              "https://foo.bar/logout",
 					TokenEndpointAuthSigningAlgorithms: []string{"RS256", "PS384"},
 				})
 				require.NoError(t, err)
@@ -245,6 +251,11 @@ func TestMetadataEndpointGet(t *testing.T) {
 				require.NoError(t, err)
 
 				assert.Equal(t, fmt.Sprintf("%s/bar", srv.URL), sm.Issuer)
+				assert.Equal(t, "https://foo.bar/auth", sm.AuthorizationEndpoint)
+				assert.Equal(t, "https://foo.bar/logout", sm.EndSessionEndpoint)
+				require.NotNil(t, sm.TokenEndpoint)
+				assert.Equal(t, "https://foo.bar/token", sm.TokenEndpoint.URL)
+				assert.Equal(t, http.MethodPost, sm.TokenEndpoint.Method)
 
 				exp := endpoint.Endpoint{
 					URL:     "https://foo.bar/jwks",
@@ -18,6 +18,7 @@ package mechanisms
 
 import (
 	"errors"
+	"io"
 
 	"github.com/rs/zerolog"
 
@@ -36,10 +37,25 @@ func newPrototypeRepository(
 	conf *config.Configuration,
 	logger zerolog.Logger,
 ) (*prototypeRepository, error) {
+	var (
+		repository prototypeRepository
+		err        error
+	)
+
+	// the prototypes created so far hold resources, which must be released if
+	// a subsequent one cannot be created
+	defer func() {
+		if err != nil {
+			repository.Close() //nolint:errcheck,gosec
+		}
+	}()
+
 	logger.Debug().Msg("Loading definitions for authenticators")
 
-	authenticatorMap, err := createPipelineObjects(conf.Prototypes.Authenticators, logger,
-		authenticators.CreatePrototype)
+	repository.authenticators, err = createPipelineObjects(conf.Prototypes.Authenticators, logger,
+		func(id string, typ string, c map[string]any) (authenticators.Authenticator, error) {
+			return authenticators.CreatePrototype(id, typ, c, logger)
+		})
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed loading authenticators definitions")
 
@@ -48,7 +64,7 @@ func newPrototypeRepository(
 
 	logger.Debug().Msg("Loading definitions for authorizers")
 
-	authorizerMap, err := createPipelineObjects(conf.Prototypes.Authorizers, logger,
+	repository.authorizers, err = createPipelineObjects(conf.Prototypes.Authorizers, logger,
 		authorizers.CreatePrototype)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed loading authorizers definitions")
@@ -58,7 +74,7 @@ func newPrototypeRepository(
 
 	logger.Debug().Msg("Loading definitions for contextualizer")
 
-	contextualizerMap, err := createPipelineObjects(conf.Prototypes.Contextualizers, logger,
+	repository.contextualizers, err = createPipelineObjects(conf.Prototypes.Contextualizers, logger,
 		contextualizers.CreatePrototype)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed loading contextualizer definitions")
@@ -68,7 +84,7 @@ func newPrototypeRepository(
 
 	logger.Debug().Msg("Loading definitions for finalizers")
 
-	finalizerMap, err := createPipelineObjects(conf.Prototypes.Finalizers, logger,
+	repository.finalizers, err = createPipelineObjects(conf.Prototypes.Finalizers, logger,
 		finalizers.CreatePrototype)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed loading finalizer definitions")
@@ -78,7 +94,7 @@ func newPrototypeRepository(
 
 	logger.Debug().Msg("Loading definitions for error handler")
 
-	ehMap, err := createPipelineObjects(conf.Prototypes.ErrorHandlers, logger,
+	repository.errorHandlers, err = createPipelineObjects(conf.Prototypes.ErrorHandlers, logger,
 		errorhandlers.CreatePrototype)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed loading error handler definitions")
@@ -86,13 +102,7 @@ func newPrototypeRepository(
 		return nil, err
 	}
 
-	return &prototypeRepository{
-		authenticators:  authenticatorMap,
-		authorizers:     authorizerMap,
-		contextualizers: contextualizerMap,
-		finalizers:      finalizerMap,
-		errorHandlers:   ehMap,
-	}, nil
+	return &repository, nil
 }
 
 func createPipelineObjects[T any](
@@ -112,6 +122,8 @@ func createPipelineObjects[T any](
 		if r, err := create(pe.ID, pe.Type, pe.Config); err == nil {
 			objects[pe.ID] = r
 		} else {
+			closeAll(objects) //nolint:errcheck,gosec
+
 			return nil, err
 		}
 	}
@@ -119,6 +131,20 @@ func createPipelineObjects[T any](
 	return objects, nil
 }
 
+// closeAll releases the resources held by the given pipeline objects, like the watchers
+// of the files they read.
+func closeAll[T any](objects map[string]T) error {
+	var errs []error
+
+	for _, object := range objects {
+		if closer, ok := any(object).(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 type prototypeRepository struct {
 	authenticators  map[string]authenticators.Authenticator
 	authorizers     map[string]authorizers.Authorizer
@@ -127,6 +153,19 @@ type prototypeRepository struct {
 	errorHandlers   map[string]errorhandlers.ErrorHandler
 }
 
+// Close releases the resources held by the prototypes, like the watchers of the files they read.
+// The mechanisms created from them stay usable to serve in-flight requests, but do not pick up
+// changes of these files anymore.
+func (r *prototypeRepository) Close() error {
+	return errors.Join(
+		closeAll(r.authenticators),
+		closeAll(r.authorizers),
+		closeAll(r.contextualizers),
+		closeAll(r.finalizers),
+		closeAll(r.errorHandlers),
+	)
+}
+
 func (r *prototypeRepository) Authenticator(id string) (authenticators.Authenticator, error) {
 	authenticator, ok := r.authenticators[id]
 	if !ok {
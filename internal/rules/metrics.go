@@ -0,0 +1,267 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/dadrus/heimdall/internal/accesscontext"
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/version"
+)
+
+const (
+	instrumentationName = "github.com/dadrus/heimdall/internal/rules"
+
+	ruleIDAttrKey        = attribute.Key("rule.id")
+	ruleSourceAttrKey    = attribute.Key("rule.source")
+	mechanismIDAttrKey   = attribute.Key("mechanism.id")
+	mechanismTypeAttrKey = attribute.Key("mechanism.type")
+	outcomeAttrKey       = attribute.Key("outcome")
+	errorClassAttrKey    = attribute.Key("error.class")
+	cacheAttrKey         = attribute.Key("cache")
+
+	outcomeSuccess  = "success"
+	outcomeFailure  = "failure"
+	outcomeFallback = "fallback"
+	outcomeIgnored  = "ignored"
+	outcomeSkipped  = "skipped"
+)
+
+type metrics struct {
+	ruleExecutions      metric.Int64Counter
+	ruleDuration        metric.Float64Histogram
+	mechanismExecutions metric.Int64Counter
+	mechanismDuration   metric.Float64Histogram
+}
+
+func newMetrics(provider metric.MeterProvider) (*metrics, error) {
+	meter := provider.Meter(instrumentationName, metric.WithInstrumentationVersion(version.Version))
+
+	ruleExecutions, err := meter.Int64Counter(
+		"rule.executions",
+		metric.WithDescription("Measures the number of rule executions."),
+		metric.WithUnit("{execution}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleDuration, err := meter.Float64Histogram(
+		"rule.execution.duration",
+		metric.WithDescription("Measures the duration of rule executions."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	mechanismExecutions, err := meter.Int64Counter(
+		"mechanism.executions",
+		metric.WithDescription("Measures the number of pipeline mechanism executions."),
+		metric.WithUnit("{execution}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	mechanismDuration, err := meter.Float64Histogram(
+		"mechanism.execution.duration",
+		metric.WithDescription("Measures the duration of pipeline mechanism executions."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &metrics{
+		ruleExecutions:      ruleExecutions,
+		ruleDuration:        ruleDuration,
+		mechanismExecutions: mechanismExecutions,
+		mechanismDuration:   mechanismDuration,
+	}, nil
+}
+
+// observe returns a context, which makes an executionObserver for the given rule available to
+// the pipeline mechanisms, together with the observer itself. If no metrics are configured,
+// the given context is returned as is.
+func (m *metrics) observe(ctx heimdall.Context, rul *ruleImpl) (heimdall.Context, *executionObserver) {
+	if m == nil {
+		return ctx, nil
+	}
+
+	observer := &executionObserver{
+		metrics:    m,
+		ruleID:     rul.id,
+		ruleSource: rul.srcID,
+		ruleAttrs:  []attribute.KeyValue{ruleIDAttrKey.String(rul.id), ruleSourceAttrKey.String(rul.srcID)},
+	}
+
+	appCtx := cache.WithObserver(context.WithValue(ctx.AppContext(), observerCtxKey{}, observer), observer)
+
+	return &observedContext{Context: ctx, appCtx: appCtx}, observer
+}
+
+type observerCtxKey struct{}
+
+type observedContext struct {
+	heimdall.Context
+
+	appCtx context.Context
+}
+
+func (c *observedContext) AppContext() context.Context { return c.appCtx }
+
+// executionObserver records the metrics of a single rule execution and makes the results
+// available to the access context for auditing purposes. As the mechanisms of a pipeline are
+// executed sequentially, it tracks the cache lookups of the currently executed mechanism only.
+type executionObserver struct {
+	metrics    *metrics
+	ruleID     string
+	ruleSource string
+	ruleAttrs  []attribute.KeyValue
+
+	cacheHits   int
+	cacheMisses int
+	skipped     bool
+}
+
+func observerFrom(ctx heimdall.Context) *executionObserver {
+	observer, _ := ctx.AppContext().Value(observerCtxKey{}).(*executionObserver)
+
+	return observer
+}
+
+func (o *executionObserver) CacheLookup(hit bool) {
+	if hit {
+		o.cacheHits++
+	} else {
+		o.cacheMisses++
+	}
+}
+
+func (o *executionObserver) mechanismStarted() time.Time {
+	if o != nil {
+		o.cacheHits = 0
+		o.cacheMisses = 0
+		o.skipped = false
+	}
+
+	return time.Now()
+}
+
+// executionSkipped marks the currently executed mechanism as skipped due to its execution condition.
+func (o *executionObserver) executionSkipped() {
+	if o != nil {
+		o.skipped = true
+	}
+}
+
+func (o *executionObserver) mechanismFinished(
+	ctx context.Context, start time.Time, mechanism interface{ ID() string }, typ, outcome string, err error,
+) {
+	if o == nil {
+		return
+	}
+
+	id := mechanism.ID()
+
+	if o.skipped && outcome == outcomeSuccess {
+		outcome = outcomeSkipped
+	}
+
+	accesscontext.AddMechanism(ctx, accesscontext.MechanismResult{ID: id, Type: typ, Outcome: outcome, Err: err})
+
+	attrs := append(o.outcomeAttributes(outcome, err),
+		mechanismIDAttrKey.String(id),
+		mechanismTypeAttrKey.String(typ),
+	)
+
+	switch {
+	case o.cacheMisses != 0:
+		attrs = append(attrs, cacheAttrKey.String("miss"))
+	case o.cacheHits != 0:
+		attrs = append(attrs, cacheAttrKey.String("hit"))
+	}
+
+	opt := metric.WithAttributes(attrs...)
+
+	o.metrics.mechanismExecutions.Add(ctx, 1, opt)
+	o.metrics.mechanismDuration.Record(ctx, time.Since(start).Seconds(), opt)
+}
+
+func (o *executionObserver) ruleFinished(ctx context.Context, start time.Time, err error) {
+	if o == nil {
+		return
+	}
+
+	accesscontext.SetRule(ctx, o.ruleID, o.ruleSource)
+
+	outcome := outcomeSuccess
+	if err != nil {
+		outcome = outcomeFailure
+	}
+
+	opt := metric.WithAttributes(o.outcomeAttributes(outcome, err)...)
+
+	o.metrics.ruleExecutions.Add(ctx, 1, opt)
+	o.metrics.ruleDuration.Record(ctx, time.Since(start).Seconds(), opt)
+}
+
+func (o *executionObserver) outcomeAttributes(outcome string, err error) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(o.ruleAttrs)+6) //nolint:gomnd
+	attrs = append(attrs, o.ruleAttrs...)
+	attrs = append(attrs, outcomeAttrKey.String(outcome))
+
+	if err != nil {
+		attrs = append(attrs, errorClassAttrKey.String(errorClass(err)))
+	}
+
+	return attrs
+}
+
+// mechanismType returns the type of the given subject handler, like "authorizer" or "finalizer".
+func mechanismType(handler subjectHandler) string {
+	if typed, ok := handler.(interface{ Type() string }); ok {
+		return typed.Type()
+	}
+
+	return "unknown"
+}
+
+// errorClass returns the class of the given error using the same names, which are
+// available in CEL expressions of error handlers.
+func errorClass(err error) string {
+	switch {
+	case errors.Is(err, heimdall.ErrAuthentication):
+		return "authentication_error"
+	case errors.Is(err, heimdall.ErrAuthorization):
+		return "authorization_error"
+	case errors.Is(err, heimdall.ErrCommunication), errors.Is(err, heimdall.ErrCommunicationTimeout):
+		return "communication_error"
+	case errors.Is(err, heimdall.ErrArgument):
+		return "precondition_error"
+	default:
+		return "internal_error"
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/dadrus/heimdall/internal/cache"
+	"github.com/dadrus/heimdall/internal/cache/memory"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	heimdallmocks "github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
+	"github.com/dadrus/heimdall/internal/rules/mocks"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+func TestErrorClass(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		err   error
+		class string
+	}{
+		{err: heimdall.ErrAuthentication, class: "authentication_error"},
+		{err: errorchain.New(heimdall.ErrAuthorization), class: "authorization_error"},
+		{err: heimdall.ErrCommunication, class: "communication_error"},
+		{err: heimdall.ErrCommunicationTimeout, class: "communication_error"},
+		{err: heimdall.ErrArgument, class: "precondition_error"},
+		{err: heimdall.ErrConfiguration, class: "internal_error"},
+		{err: context.Canceled, class: "internal_error"},
+	} {
+		t.Run("case="+tc.err.Error(), func(t *testing.T) {
+			// WHEN
+			class := errorClass(tc.err)
+
+			// THEN
+			assert.Equal(t, tc.class, class)
+		})
+	}
+}
+
+func TestRuleExecutionMetrics(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	reader := metric.NewManualReader()
+
+	mtrcs, err := newMetrics(metric.NewMeterProvider(metric.WithReader(reader)))
+	require.NoError(t, err)
+
+	cch := memory.New()
+	appCtx := cache.WithContext(context.Background(), cch)
+	cch.Set(appCtx, "foo", "bar", time.Minute)
+
+	ctx := heimdallmocks.NewContextMock(t)
+	ctx.EXPECT().AppContext().Return(appCtx)
+
+	sub := &subject.Subject{ID: "foo"}
+
+	auth1 := mocks.NewSubjectCreatorMock(t)
+	auth1.EXPECT().ID().Return("auth1")
+	auth1.EXPECT().Execute(mock.Anything).Return(nil, heimdall.ErrAuthentication)
+	auth1.EXPECT().IsFallbackOnErrorAllowed().Return(true)
+
+	auth2 := mocks.NewSubjectCreatorMock(t)
+	auth2.EXPECT().ID().Return("auth2")
+	auth2.EXPECT().Execute(mock.Anything).RunAndReturn(func(ctx heimdall.Context) (*subject.Subject, error) {
+		cache.Ctx(ctx.AppContext()).Get(ctx.AppContext(), "foo")
+
+		return sub, nil
+	})
+
+	contextualizer := mocks.NewSubjectHandlerMock(t)
+	contextualizer.EXPECT().ID().Return("contextualizer")
+
+	condition := mocks.NewExecutionConditionMock(t)
+	condition.EXPECT().CanExecute(mock.Anything, sub).Return(false, nil)
+
+	authz := mocks.NewSubjectHandlerMock(t)
+	authz.EXPECT().ID().Return("authz")
+	authz.EXPECT().Execute(mock.Anything, sub).RunAndReturn(func(ctx heimdall.Context, _ *subject.Subject) error {
+		cache.Ctx(ctx.AppContext()).Get(ctx.AppContext(), "bar")
+
+		return heimdall.ErrAuthorization
+	})
+	authz.EXPECT().ContinueOnError().Return(false)
+
+	errHandler := mocks.NewErrorHandlerMock(t)
+	errHandler.EXPECT().CanExecute(mock.Anything, heimdall.ErrAuthorization).Return(true)
+	errHandler.EXPECT().Execute(mock.Anything, heimdall.ErrAuthorization).Return(heimdall.ErrAuthorization)
+
+	rul := &ruleImpl{
+		id:    "test-rule",
+		srcID: "test-src",
+		sc:    compositeSubjectCreator{auth1, auth2},
+		sh: compositeSubjectHandler{
+			&conditionalSubjectHandler{h: contextualizer, c: condition, typ: "contextualizer"},
+			&conditionalSubjectHandler{h: authz, c: defaultExecutionCondition{}, typ: "authorizer"},
+		},
+		eh:      compositeErrorHandler{errHandler},
+		metrics: mtrcs,
+	}
+
+	// WHEN
+	_, err = rul.Execute(ctx)
+
+	// THEN
+	require.ErrorIs(t, err, heimdall.ErrAuthorization)
+
+	var rm metricdata.ResourceMetrics
+
+	require.NoError(t, reader.Collect(context.TODO(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m.Data
+	}
+
+	ruleAttrs := []attribute.KeyValue{ruleIDAttrKey.String("test-rule"), ruleSourceAttrKey.String("test-src")}
+
+	ruleExecutions, ok := metrics["rule.executions"].(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, ruleExecutions.DataPoints, 1)
+	assert.Equal(t, int64(1), ruleExecutions.DataPoints[0].Value)
+	assert.Equal(t, attribute.NewSet(append(ruleAttrs,
+		outcomeAttrKey.String(outcomeFailure),
+		errorClassAttrKey.String("authorization_error"),
+	)...), ruleExecutions.DataPoints[0].Attributes)
+
+	ruleDuration, ok := metrics["rule.execution.duration"].(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, ruleDuration.DataPoints, 1)
+	assert.Equal(t, uint64(1), ruleDuration.DataPoints[0].Count)
+
+	mechanismExecutions, ok := metrics["mechanism.executions"].(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, mechanismExecutions.DataPoints, 4)
+
+	var sets []attribute.Set
+	for _, dp := range mechanismExecutions.DataPoints {
+		assert.Equal(t, int64(1), dp.Value)
+
+		sets = append(sets, dp.Attributes)
+	}
+
+	assert.ElementsMatch(t, []attribute.Set{
+		attribute.NewSet(append(ruleAttrs,
+			mechanismIDAttrKey.String("auth1"),
+			mechanismTypeAttrKey.String("authenticator"),
+			outcomeAttrKey.String(outcomeFallback),
+			errorClassAttrKey.String("authentication_error"),
+		)...),
+		attribute.NewSet(append(ruleAttrs,
+			mechanismIDAttrKey.String("auth2"),
+			mechanismTypeAttrKey.String("authenticator"),
+			outcomeAttrKey.String(outcomeSuccess),
+			cacheAttrKey.String("hit"),
+		)...),
+		attribute.NewSet(append(ruleAttrs,
+			mechanismIDAttrKey.String("contextualizer"),
+			mechanismTypeAttrKey.String("contextualizer"),
+			outcomeAttrKey.String(outcomeSkipped),
+		)...),
+		attribute.NewSet(append(ruleAttrs,
+			mechanismIDAttrKey.String("authz"),
+			mechanismTypeAttrKey.String("authorizer"),
+			outcomeAttrKey.String(outcomeFailure),
+			errorClassAttrKey.String("authorization_error"),
+			cacheAttrKey.String("miss"),
+		)...),
+	}, sets)
+
+	mechanismDuration, ok := metrics["mechanism.execution.duration"].(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, mechanismDuration.DataPoints, 4)
+}
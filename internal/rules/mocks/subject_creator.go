@@ -76,6 +76,47 @@ func (_c *SubjectCreatorMock_Execute_Call) RunAndReturn(run func(heimdall.Contex
 	return _c
 }
 
+// ID provides a mock function with given fields:
+func (_m *SubjectCreatorMock) ID() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// SubjectCreatorMock_ID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ID'
+type SubjectCreatorMock_ID_Call struct {
+	*mock.Call
+}
+
+// ID is a helper method to define mock.On call
+func (_e *SubjectCreatorMock_Expecter) ID() *SubjectCreatorMock_ID_Call {
+	return &SubjectCreatorMock_ID_Call{Call: _e.mock.On("ID")}
+}
+
+func (_c *SubjectCreatorMock_ID_Call) Run(run func()) *SubjectCreatorMock_ID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *SubjectCreatorMock_ID_Call) Return(_a0 string) *SubjectCreatorMock_ID_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *SubjectCreatorMock_ID_Call) RunAndReturn(run func() string) *SubjectCreatorMock_ID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // IsFallbackOnErrorAllowed provides a mock function with given fields:
 func (_m *SubjectCreatorMock) IsFallbackOnErrorAllowed() bool {
 	ret := _m.Called()
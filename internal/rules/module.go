@@ -20,11 +20,16 @@ import (
 	"context"
 
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/fx"
 
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/rules/event"
 	"github.com/dadrus/heimdall/internal/rules/provider"
 	"github.com/dadrus/heimdall/internal/rules/rule"
+	"github.com/dadrus/heimdall/internal/rules/upstream"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
 )
 
 const defaultQueueSize = 20
@@ -47,15 +52,39 @@ var Module = fx.Options(
 				},
 			),
 		),
-		NewRuleFactory,
+		fx.Annotate(
+			newUpstreamRegistry,
+			fx.OnStart(func(ctx context.Context, r *upstream.Registry) error { return r.Start(ctx) }),
+			fx.OnStop(func(ctx context.Context, r *upstream.Registry) error { return r.Stop(ctx) }),
+		),
+		newReloadableRuleFactory,
+		func(f *reloadableRuleFactory) rule.Factory { return f },
 		fx.Annotate(
 			newRepository,
 			fx.OnStart(func(ctx context.Context, o *repository) error { return o.Start(ctx) }),
 			fx.OnStop(func(ctx context.Context, o *repository) error { return o.Stop(ctx) }),
 		),
 		func(r *repository) rule.Repository { return r },
+		func(r *repository) rule.ConflictNotifier { return r },
+		func(r *repository) rule.Inspector { return r },
 		newRuleExecutor,
-		NewRuleSetProcessor,
+		newRuleSetProcessor,
+		func(p *ruleSetProcessor) rule.SetProcessor { return p },
 	),
+	fx.Invoke(registerConfigReloader),
 	provider.Module,
 )
+
+func newUpstreamRegistry(
+	conf *config.Configuration,
+	meterProvider metric.MeterProvider,
+	logger zerolog.Logger,
+) (*upstream.Registry, error) {
+	registry, err := upstream.NewRegistry(logger, meterProvider, upstream.WithTLSStores(conf.UpstreamTLS))
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed to create upstream registry").CausedBy(err)
+	}
+
+	return registry, nil
+}
@@ -27,28 +27,46 @@ import (
 	"time"
 
 	"github.com/goccy/go-json"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
 
 	"github.com/dadrus/heimdall/internal/cache"
 	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/keystore"
 	"github.com/dadrus/heimdall/internal/rules/endpoint"
 	"github.com/dadrus/heimdall/internal/x"
 	"github.com/dadrus/heimdall/internal/x/errorchain"
 	"github.com/dadrus/heimdall/internal/x/stringx"
 )
 
+// by intention. Used only during application bootstrap
+//
+//nolint:gochecknoinits
+func init() {
+	cache.RegisterValueType("oauth2/client_credentials/token_info", &TokenInfo{})
+}
+
 type AuthMethod string
 
 const (
-	AuthMethodBasicAuth   AuthMethod = "basic_auth"
-	AuthMethodRequestBody AuthMethod = "request_body"
+	AuthMethodBasicAuth     AuthMethod = "basic_auth"
+	AuthMethodRequestBody   AuthMethod = "request_body"
+	AuthMethodPrivateKeyJWT AuthMethod = "private_key_jwt"
+	AuthMethodTLSClientAuth AuthMethod = "tls_client_auth"
+
+	clientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	clientAssertionTTL  = 1 * time.Minute
 )
 
 type Config struct {
 	TokenURL     string         `mapstructure:"token_url"     validate:"required,url"`
 	ClientID     string         `mapstructure:"client_id"     validate:"required"`
-	ClientSecret string         `mapstructure:"client_secret" validate:"required"`
-	AuthMethod   AuthMethod     `mapstructure:"auth_method"   validate:"omitempty,oneof=basic_auth request_body"`
+	ClientSecret string         `mapstructure:"client_secret" validate:"required_if=AuthMethod basic_auth,required_if=AuthMethod request_body,required_without_all=SigningKey TLS"` //nolint:lll
+	AuthMethod   AuthMethod     `mapstructure:"auth_method"   validate:"omitempty,oneof=basic_auth request_body private_key_jwt tls_client_auth"`                                   //nolint:lll
+	SigningKey   *KeyConfig     `mapstructure:"signing_key"   validate:"required_if=AuthMethod private_key_jwt,excluded_unless=AuthMethod private_key_jwt"`                         //nolint:lll
+	TLS          *KeyConfig     `mapstructure:"tls"           validate:"required_if=AuthMethod tls_client_auth,excluded_unless=AuthMethod tls_client_auth"`                         //nolint:lll
 	Scopes       []string       `mapstructure:"scopes"`
 	TTL          *time.Duration `mapstructure:"cache_ttl"`
 }
@@ -96,13 +114,7 @@ func (c *Config) Token(ctx context.Context) (*TokenInfo, error) {
 }
 
 func (c *Config) calculateCacheKey() string {
-	digest := sha256.New()
-	digest.Write(stringx.ToBytes(c.ClientID))
-	digest.Write(stringx.ToBytes(c.ClientSecret))
-	digest.Write(stringx.ToBytes(c.TokenURL))
-	digest.Write(stringx.ToBytes(strings.Join(c.Scopes, "")))
-
-	return hex.EncodeToString(digest.Sum(nil))
+	return hex.EncodeToString(c.Hash())
 }
 
 func (c *Config) getCacheTTL(resp *TokenInfo) time.Duration {
@@ -148,7 +160,19 @@ func (c *Config) isCacheEnabled() bool {
 }
 
 func (c *Config) fetchToken(ctx context.Context) (*TokenInfo, error) {
+	var transport http.RoundTripper
+
+	if c.AuthMethod == AuthMethodTLSClientAuth {
+		tlsTransport, err := c.TLS.Transport()
+		if err != nil {
+			return nil, err
+		}
+
+		transport = tlsTransport
+	}
+
 	ept := endpoint.Endpoint{
+		Transport:    transport,
 		URL:          c.TokenURL,
 		Method:       http.MethodPost,
 		AuthStrategy: c,
@@ -163,6 +187,12 @@ func (c *Config) fetchToken(ctx context.Context) (*TokenInfo, error) {
 		data.Add("scope", strings.Join(c.Scopes, " "))
 	}
 
+	return RequestToken(ctx, ept, data)
+}
+
+// RequestToken sends the given form data to the token endpoint referenced by ept and returns the
+// issued token. Beyond the client credentials grant it is usable for any other grant type as well.
+func RequestToken(ctx context.Context, ept endpoint.Endpoint, data url.Values) (*TokenInfo, error) {
 	rawData, err := ept.SendRequest(
 		ctx,
 		strings.NewReader(data.Encode()),
@@ -216,32 +246,103 @@ func (c *Config) fetchToken(ctx context.Context) (*TokenInfo, error) {
 }
 
 func (c *Config) Apply(_ context.Context, req *http.Request) error {
-	if c.AuthMethod == AuthMethodRequestBody {
+	switch c.AuthMethod {
+	case AuthMethodRequestBody:
 		// This is not recommended, but there are non-compliant servers out there
 		// which do not support the Basic Auth authentication method required by
 		// the spec. See also https://www.rfc-editor.org/rfc/rfc6749#section-2.3.1
-		data, _ := io.ReadAll(req.Body)
-		values, _ := url.ParseQuery(stringx.ToString(data))
-
-		values.Add("client_id", c.ClientID)
-		values.Add("client_secret", c.ClientSecret)
+		setBodyParameters(req, url.Values{
+			"client_id":     []string{c.ClientID},
+			"client_secret": []string{c.ClientSecret},
+		})
+	case AuthMethodPrivateKeyJWT:
+		// See https://www.rfc-editor.org/rfc/rfc7523#section-2.2
+		assertion, err := c.clientAssertion()
+		if err != nil {
+			return err
+		}
 
-		body := strings.NewReader(values.Encode())
-		req.Body = io.NopCloser(body)
-		req.ContentLength = int64(body.Len())
-	} else {
+		setBodyParameters(req, url.Values{
+			"client_id":             []string{c.ClientID},
+			"client_assertion_type": []string{clientAssertionType},
+			"client_assertion":      []string{assertion},
+		})
+	case AuthMethodTLSClientAuth:
+		// The client is authenticated by the certificate used during the TLS handshake.
+		// See https://www.rfc-editor.org/rfc/rfc8705#section-2
+		setBodyParameters(req, url.Values{"client_id": []string{c.ClientID}})
+	default:
 		req.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
 	}
 
 	return nil
 }
 
+func (c *Config) clientAssertion() (string, error) {
+	entry, err := c.SigningKey.Entry()
+	if err != nil {
+		return "", err
+	}
+
+	alg := entry.JOSEAlgorithm()
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: alg, Key: keystore.JOSESigningKey(entry.PrivateKey)},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", entry.KeyID),
+	)
+	if err != nil {
+		return "", errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed to create client assertion signer").CausedBy(err)
+	}
+
+	now := time.Now().UTC()
+
+	assertion, err := jwt.Signed(signer).Claims(map[string]any{
+		"iss": c.ClientID,
+		"sub": c.ClientID,
+		"aud": c.TokenURL,
+		"jti": uuid.New(),
+		"iat": now.Unix(),
+		"exp": now.Add(clientAssertionTTL).Unix(),
+	}).CompactSerialize()
+	if err != nil {
+		return "", errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed to sign client assertion").CausedBy(err)
+	}
+
+	return assertion, nil
+}
+
 func (c *Config) Hash() []byte {
 	digest := sha256.New()
 	digest.Write(stringx.ToBytes(c.ClientID))
 	digest.Write(stringx.ToBytes(c.ClientSecret))
 	digest.Write(stringx.ToBytes(c.TokenURL))
 	digest.Write(stringx.ToBytes(strings.Join(c.Scopes, "")))
+	digest.Write(stringx.ToBytes(string(c.AuthMethod)))
+
+	for _, key := range []*KeyConfig{c.SigningKey, c.TLS} {
+		if key != nil {
+			digest.Write(stringx.ToBytes(key.KeyStore.Path))
+			digest.Write(stringx.ToBytes(key.KeyStore.TokenLabel))
+			digest.Write(stringx.ToBytes(key.KeyID))
+		}
+	}
 
 	return digest.Sum(nil)
 }
+
+func setBodyParameters(req *http.Request, params url.Values) {
+	data, _ := io.ReadAll(req.Body)
+	values, _ := url.ParseQuery(stringx.ToString(data))
+
+	for key, vals := range params {
+		for _, val := range vals {
+			values.Add(key, val)
+		}
+	}
+
+	body := strings.NewReader(values.Encode())
+	req.Body = io.NopCloser(body)
+	req.ContentLength = int64(body.Len())
+}
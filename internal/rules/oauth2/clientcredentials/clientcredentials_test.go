@@ -18,9 +18,17 @@ package clientcredentials
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/base64"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
@@ -30,11 +38,15 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2/jwt"
 
 	"github.com/dadrus/heimdall/internal/cache"
 	"github.com/dadrus/heimdall/internal/cache/mocks"
+	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/pkix/pemx"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
 )
 
 func TestClientCredentialsToken(t *testing.T) {
@@ -661,3 +673,186 @@ func TestClientCredentialsHash(t *testing.T) {
 	assert.NotEmpty(t, hash2)
 	assert.NotEqual(t, hash1, hash2)
 }
+
+func TestClientCredentialsTokenWithPrivateKeyJWT(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	pemBytes, err := pemx.BuildPEM(pemx.WithECDSAPrivateKey(privKey, pemx.WithHeader("X-Key-ID", "foo")))
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(t.TempDir(), "keystore.pem")
+	require.NoError(t, os.WriteFile(keyFile, pemBytes, 0o600))
+
+	var tokenURL string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.NoError(t, req.ParseForm())
+		assert.Empty(t, req.Header.Get("Authorization"))
+		assert.Equal(t, "bar", req.FormValue("client_id"))
+		assert.Equal(t, "client_credentials", req.FormValue("grant_type"))
+		assert.Equal(t, "urn:ietf:params:oauth:client-assertion-type:jwt-bearer",
+			req.FormValue("client_assertion_type"))
+
+		token, err := jwt.ParseSigned(req.FormValue("client_assertion"))
+		require.NoError(t, err)
+		require.Len(t, token.Headers, 1)
+		assert.Equal(t, "foo", token.Headers[0].KeyID)
+		assert.Equal(t, "ES256", token.Headers[0].Algorithm)
+
+		var claims jwt.Claims
+		require.NoError(t, token.Claims(&privKey.PublicKey, &claims))
+		assert.Equal(t, "bar", claims.Issuer)
+		assert.Equal(t, "bar", claims.Subject)
+		assert.Equal(t, jwt.Audience{tokenURL}, claims.Audience)
+		assert.NotEmpty(t, claims.ID)
+		require.NoError(t, claims.Validate(jwt.Expected{Time: time.Now()}))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err = w.Write([]byte(`{"access_token":"foobar","token_type":"Bearer"}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	tokenURL = srv.URL
+
+	cfg := &Config{
+		TokenURL:   srv.URL,
+		ClientID:   "bar",
+		AuthMethod: AuthMethodPrivateKeyJWT,
+		SigningKey: &KeyConfig{KeyStore: config.KeyStore{Path: keyFile}},
+		TTL: func() *time.Duration {
+			ttl := 0 * time.Second
+
+			return &ttl
+		}(),
+	}
+
+	// WHEN
+	token, err := cfg.Token(context.Background())
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer", token.TokenType)
+	assert.Equal(t, "foobar", token.AccessToken)
+}
+
+func TestClientCredentialsTokenWithTLSClientAuth(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	rootCA, err := testsupport.NewRootCA("Test Root CA", 24*time.Hour)
+	require.NoError(t, err)
+
+	privKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	cert, err := rootCA.IssueCertificate(
+		testsupport.WithSubject(pkix.Name{CommonName: "bar"}),
+		testsupport.WithValidity(time.Now(), 1*time.Hour),
+		testsupport.WithSubjectPubKey(&privKey.PublicKey, x509.ECDSAWithSHA384),
+		testsupport.WithExtendedKeyUsage(x509.ExtKeyUsageClientAuth),
+		testsupport.WithKeyUsage(x509.KeyUsageDigitalSignature))
+	require.NoError(t, err)
+
+	pemBytes, err := pemx.BuildPEM(
+		pemx.WithECDSAPrivateKey(privKey, pemx.WithHeader("X-Key-ID", "foo")),
+		pemx.WithX509Certificate(cert),
+	)
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(t.TempDir(), "keystore.pem")
+	require.NoError(t, os.WriteFile(keyFile, pemBytes, 0o600))
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(rootCA.Certificate)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.NoError(t, req.ParseForm())
+		assert.Empty(t, req.Header.Get("Authorization"))
+		assert.Equal(t, "bar", req.FormValue("client_id"))
+		assert.Empty(t, req.FormValue("client_secret"))
+		require.Len(t, req.TLS.PeerCertificates, 1)
+		assert.Equal(t, "bar", req.TLS.PeerCertificates[0].Subject.CommonName)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err = w.Write([]byte(`{"access_token":"foobar","token_type":"Bearer"}`))
+		assert.NoError(t, err)
+	}))
+	srv.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+	srv.StartTLS()
+
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		uc     string
+		key    *KeyConfig
+		assert func(t *testing.T, err error, token *TokenInfo)
+	}{
+		{
+			uc:  "not existing key store",
+			key: &KeyConfig{KeyStore: config.KeyStore{Path: "/no/such/file"}},
+			assert: func(t *testing.T, err error, _ *TokenInfo) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed loading client credentials key store")
+			},
+		},
+		{
+			uc:  "not existing key id",
+			key: &KeyConfig{KeyStore: config.KeyStore{Path: keyFile}, KeyID: "baz"},
+			assert: func(t *testing.T, err error, _ *TokenInfo) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "failed retrieving key 'baz'")
+			},
+		},
+		{
+			uc:  "successful",
+			key: &KeyConfig{KeyStore: config.KeyStore{Path: keyFile}, KeyID: "foo"},
+			assert: func(t *testing.T, err error, token *TokenInfo) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "Bearer", token.TokenType)
+				assert.Equal(t, "foobar", token.AccessToken)
+			},
+		},
+	} {
+		t.Run(tc.uc, func(t *testing.T) {
+			cfg := &Config{
+				TokenURL:   srv.URL,
+				ClientID:   "bar",
+				AuthMethod: AuthMethodTLSClientAuth,
+				TLS:        tc.key,
+				TTL: func() *time.Duration {
+					ttl := 0 * time.Second
+
+					return &ttl
+				}(),
+			}
+
+			// the server certificate is issued by the test server and is not trusted by default
+			if transport, err := tc.key.Transport(); err == nil {
+				transport.TLSClientConfig.RootCAs = srv.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+			}
+
+			// WHEN
+			token, err := cfg.Token(context.Background())
+
+			// THEN
+			tc.assert(t, err, token)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package clientcredentials
+
+import (
+	"crypto/tls"
+	"net/http"
+	"sync"
+
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/keystore"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+// KeyConfig references a key (and its certificate chain if required) in a key store. It is used
+// to sign client assertions for the private_key_jwt authentication method and to present a client
+// certificate for the tls_client_auth authentication method.
+type KeyConfig struct {
+	KeyStore config.KeyStore `mapstructure:"key_store" validate:"required"`
+	KeyID    string          `mapstructure:"key_id"`
+
+	mutex     sync.Mutex
+	entry     *keystore.Entry
+	transport *http.Transport
+}
+
+// Entry returns the referenced key store entry. The key store is loaded on first use only.
+// If loading fails, the next call tries it again.
+func (k *KeyConfig) Entry() (*keystore.Entry, error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	return k.loadEntry()
+}
+
+// Transport returns an http transport presenting the certificate of the referenced key store
+// entry as client certificate during the TLS handshake.
+func (k *KeyConfig) Transport() (*http.Transport, error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if k.transport != nil {
+		return k.transport, nil
+	}
+
+	entry, err := k.loadEntry()
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := keystore.ToTLSCertificate(entry)
+	if err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"key store entry '%s' cannot be used for tls client authentication", entry.KeyID).
+			CausedBy(err)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	k.transport = transport
+
+	return k.transport, nil
+}
+
+func (k *KeyConfig) loadEntry() (*keystore.Entry, error) {
+	if k.entry != nil {
+		return k.entry, nil
+	}
+
+	ks, err := keystore.NewKeyStore(k.KeyStore)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"failed loading client credentials key store").CausedBy(err)
+	}
+
+	var entry *keystore.Entry
+
+	if len(k.KeyID) != 0 {
+		if entry, err = ks.GetKey(k.KeyID); err != nil {
+			return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+				"failed retrieving key '%s' from client credentials key store", k.KeyID).CausedBy(err)
+		}
+	} else if entries := ks.Entries(); len(entries) != 0 {
+		entry = entries[0]
+	} else {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"client credentials key store is empty")
+	}
+
+	k.entry = entry
+
+	return k.entry, nil
+}
@@ -19,6 +19,8 @@ package clientcredentials
 import (
 	"strings"
 	"time"
+
+	"github.com/goccy/go-json"
 )
 
 type TokenInfo struct {
@@ -31,6 +33,45 @@ type TokenInfo struct {
 	raw map[string]any
 }
 
+type tokenInfoJSON struct {
+	AccessToken  string         `json:"access_token"`
+	RefreshToken string         `json:"refresh_token,omitempty"`
+	TokenType    string         `json:"token_type"`
+	Expiry       time.Time      `json:"expiry"`
+	Scopes       []string       `json:"scopes,omitempty"`
+	Raw          map[string]any `json:"raw,omitempty"`
+}
+
+func (t *TokenInfo) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tokenInfoJSON{
+		AccessToken:  t.AccessToken,
+		RefreshToken: t.RefreshToken,
+		TokenType:    t.TokenType,
+		Expiry:       t.Expiry,
+		Scopes:       t.Scopes,
+		Raw:          t.raw,
+	})
+}
+
+func (t *TokenInfo) UnmarshalJSON(data []byte) error {
+	var aux tokenInfoJSON
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	*t = TokenInfo{
+		AccessToken:  aux.AccessToken,
+		RefreshToken: aux.RefreshToken,
+		TokenType:    aux.TokenType,
+		Expiry:       aux.Expiry,
+		Scopes:       aux.Scopes,
+		raw:          aux.Raw,
+	}
+
+	return nil
+}
+
 func (t *TokenInfo) WithExtra(extra map[string]any) *TokenInfo {
 	t2 := new(TokenInfo)
 	*t2 = *t
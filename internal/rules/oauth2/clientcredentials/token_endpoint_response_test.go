@@ -0,0 +1,54 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package clientcredentials
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenInfoJSONRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	tokenInfo := (&TokenInfo{
+		AccessToken: "foo",
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(5 * time.Minute).Truncate(time.Second),
+		Scopes:      []string{"bar", "baz"},
+	}).WithExtra(map[string]any{"foo": "bar"})
+
+	// WHEN
+	data, err := json.Marshal(tokenInfo)
+	require.NoError(t, err)
+
+	var decoded TokenInfo
+
+	err = json.Unmarshal(data, &decoded)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, tokenInfo.AccessToken, decoded.AccessToken)
+	assert.Equal(t, tokenInfo.TokenType, decoded.TokenType)
+	assert.True(t, tokenInfo.Expiry.Equal(decoded.Expiry))
+	assert.Equal(t, tokenInfo.Scopes, decoded.Scopes)
+	assert.Equal(t, "bar", decoded.Extra("foo"))
+}
@@ -19,6 +19,9 @@ package patternmatcher
 import (
 	"bytes"
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/gobwas/glob"
 )
@@ -26,55 +29,199 @@ import (
 var (
 	ErrUnbalancedPattern    = errors.New("unbalanced pattern")
 	ErrNoGlobPatternDefined = errors.New("no glob pattern defined")
+
+	// namedSectionPattern matches sections of the form <?P<name>pattern>, mirroring the syntax
+	// of named groups in regular expressions. Any other section, like <name> or <localhost:*>,
+	// is a plain glob.
+	namedSectionPattern = regexp.MustCompile(`^\?P<([a-zA-Z_][a-zA-Z0-9_]*)>(.+)$`)
 )
 
 type globMatcher struct {
 	compiled glob.Glob
+	captures *regexp.Regexp
 }
 
 func (m *globMatcher) Match(value string) bool {
 	return m.compiled.Match(value)
 }
 
+func (m *globMatcher) Captures(value string) map[string]string {
+	if m.captures == nil {
+		return nil
+	}
+
+	match := m.captures.FindStringSubmatch(value)
+	if match == nil {
+		return nil
+	}
+
+	captures := make(map[string]string)
+
+	for idx, name := range m.captures.SubexpNames() {
+		if len(name) != 0 {
+			captures[name] = match[idx]
+		}
+	}
+
+	return captures
+}
+
 func newGlobMatcher(pattern string) (*globMatcher, error) {
 	if len(pattern) == 0 {
 		return nil, ErrNoGlobPatternDefined
 	}
 
-	compiled, err := compileGlob(pattern, '<', '>')
+	compiled, captures, err := compileGlob(pattern, '<', '>')
 	if err != nil {
 		return nil, err
 	}
 
-	return &globMatcher{compiled: compiled}, nil
+	return &globMatcher{compiled: compiled, captures: captures}, nil
 }
 
-func compileGlob(pattern string, delimiterStart, delimiterEnd rune) (glob.Glob, error) {
+// compileGlob compiles the given pattern into a glob. If the pattern contains named sections,
+// like <?P<name>pattern>, a regular expression is compiled as well, which is then used
+// to extract the values matched by these sections.
+func compileGlob(pattern string, delimiterStart, delimiterEnd rune) (glob.Glob, *regexp.Regexp, error) {
 	// Check if it is well-formed.
 	idxs, errBraces := delimiterIndices(pattern, delimiterStart, delimiterEnd)
 	if errBraces != nil {
-		return nil, errBraces
+		return nil, nil, errBraces
 	}
 
 	buffer := bytes.NewBufferString("")
+	captures := bytes.NewBufferString("^")
+	named := false
 
 	var end int
 	for ind := 0; ind < len(idxs); ind += 2 {
 		// Set all values we are interested in.
 		raw := pattern[end:idxs[ind]]
 		end = idxs[ind+1]
-		patt := pattern[idxs[ind]+1 : end-1]
+		name, patt := splitSection(pattern[idxs[ind]+1 : end-1])
 
 		buffer.WriteString(glob.QuoteMeta(raw))
 		buffer.WriteString(patt)
+
+		captures.WriteString(regexp.QuoteMeta(raw))
+
+		if len(name) != 0 {
+			named = true
+
+			fmt.Fprintf(captures, "(?P<%s>%s)", name, globToRegex(patt))
+		} else {
+			captures.WriteString(globToRegex(patt))
+		}
 	}
 
 	// Add the remaining.
 	raw := pattern[end:]
 	buffer.WriteString(glob.QuoteMeta(raw))
+	captures.WriteString(regexp.QuoteMeta(raw))
+	captures.WriteString("$")
 
 	// Compile full regexp.
-	return glob.Compile(buffer.String(), '.', '/')
+	compiled, err := glob.Compile(buffer.String(), '.', '/')
+	if err != nil || !named {
+		return compiled, nil, err
+	}
+
+	capturesRegex, err := regexp.Compile(captures.String())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return compiled, capturesRegex, nil
+}
+
+// splitSection returns the name and the glob pattern of a section. A section without a name
+// results in an empty name.
+func splitSection(section string) (string, string) {
+	match := namedSectionPattern.FindStringSubmatch(section)
+	if match == nil {
+		return "", section
+	}
+
+	return match[1], match[2]
+}
+
+// globToRegex translates the given glob pattern into a regular expression, taking '.' and '/'
+// as separators into account.
+func globToRegex(pattern string) string { //nolint:cyclop
+	var (
+		buffer       strings.Builder
+		alternatives int
+	)
+
+	for idx := 0; idx < len(pattern); idx++ {
+		switch char := pattern[idx]; char {
+		case '\\':
+			if idx+1 < len(pattern) {
+				idx++
+				buffer.WriteString(regexp.QuoteMeta(pattern[idx : idx+1]))
+			}
+		case '*':
+			if idx+1 < len(pattern) && pattern[idx+1] == '*' {
+				idx++
+				buffer.WriteString(".*")
+			} else {
+				buffer.WriteString(`[^./]*`)
+			}
+		case '?':
+			buffer.WriteString(`[^./]`)
+		case '[':
+			length := strings.IndexByte(pattern[idx:], ']')
+			if length == -1 {
+				buffer.WriteString(`\[`)
+
+				continue
+			}
+
+			class := pattern[idx+1 : idx+length]
+
+			buffer.WriteByte('[')
+
+			if strings.HasPrefix(class, "!") {
+				buffer.WriteByte('^')
+
+				class = class[1:]
+			}
+
+			for _, r := range class {
+				if r == '-' {
+					buffer.WriteRune(r)
+				} else {
+					buffer.WriteString(regexp.QuoteMeta(string(r)))
+				}
+			}
+
+			buffer.WriteByte(']')
+
+			idx += length
+		case '{':
+			alternatives++
+
+			buffer.WriteString("(?:")
+		case ',':
+			if alternatives > 0 {
+				buffer.WriteByte('|')
+			} else {
+				buffer.WriteByte(',')
+			}
+		case '}':
+			if alternatives > 0 {
+				alternatives--
+
+				buffer.WriteByte(')')
+			} else {
+				buffer.WriteString(`\}`)
+			}
+		default:
+			buffer.WriteString(regexp.QuoteMeta(pattern[idx : idx+1]))
+		}
+	}
+
+	return buffer.String()
 }
 
 // delimiterIndices returns the first level delimiter indices from a string.
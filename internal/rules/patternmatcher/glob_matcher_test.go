@@ -183,3 +183,98 @@ func TestIsMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestGlobMatcherCaptures(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc           string
+		pattern      string
+		matchAgainst string
+		shouldMatch  bool
+		captures     map[string]string
+	}{
+		{
+			uc:           "without named sections",
+			pattern:      `https://example.com/<*>/<**>`,
+			matchAgainst: "https://example.com/foo/bar/baz",
+			shouldMatch:  true,
+		},
+		{
+			uc:           "named section matching a single segment",
+			pattern:      `https://example.com/tenants/<?P<tenant>*>/<**>`,
+			matchAgainst: "https://example.com/tenants/foo/bar/baz",
+			shouldMatch:  true,
+			captures:     map[string]string{"tenant": "foo"},
+		},
+		{
+			uc:           "named section matching a single segment does not match multiple segments",
+			pattern:      `https://example.com/tenants/<?P<tenant>*>`,
+			matchAgainst: "https://example.com/tenants/foo/bar",
+			shouldMatch:  false,
+		},
+		{
+			uc:           "section consisting of a name only is a literal glob",
+			pattern:      `https://example.com/<tenant>`,
+			matchAgainst: "https://example.com/tenant",
+			shouldMatch:  true,
+		},
+		{
+			uc:           "section consisting of a name only does not match any other segment",
+			pattern:      `https://example.com/<tenant>`,
+			matchAgainst: "https://example.com/foo",
+			shouldMatch:  false,
+		},
+		{
+			uc:           "section with a colon is a literal glob",
+			pattern:      `https://<localhost:*>/<api:v1*>`,
+			matchAgainst: "https://localhost:8080/api:v1beta",
+			shouldMatch:  true,
+		},
+		{
+			uc:           "section with a colon does not match other hosts",
+			pattern:      `https://<localhost:*>/foo`,
+			matchAgainst: "https://example.com:8080/foo",
+			shouldMatch:  false,
+		},
+		{
+			uc:           "multiple named sections with patterns",
+			pattern:      `<?P<scheme>http{,s}>://<?P<host>*.example.com>/<?P<version>v[1-3]>/<?P<rest>**>`,
+			matchAgainst: "https://api.example.com/v2/foo/bar",
+			shouldMatch:  true,
+			captures: map[string]string{
+				"scheme":  "https",
+				"host":    "api.example.com",
+				"version": "v2",
+				"rest":    "foo/bar",
+			},
+		},
+		{
+			uc:           "named and unnamed sections",
+			pattern:      `https://<*>.example.com/<?P<id>?[!a-z]?>.<?P<ext>{json,yaml}>`,
+			matchAgainst: "https://api.example.com/a1b.yaml",
+			shouldMatch:  true,
+			captures:     map[string]string{"id": "a1b", "ext": "yaml"},
+		},
+		{
+			uc:           "no match",
+			pattern:      `https://example.com/<?P<id>[a-z]>`,
+			matchAgainst: "https://example.com/1",
+			shouldMatch:  false,
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			matcher, err := newGlobMatcher(tc.pattern)
+			require.NoError(t, err)
+
+			// WHEN
+			matched := matcher.Match(tc.matchAgainst)
+			captures := matcher.Captures(tc.matchAgainst)
+
+			// THEN
+			assert.Equal(t, tc.shouldMatch, matched)
+			assert.Equal(t, tc.captures, captures)
+		})
+	}
+}
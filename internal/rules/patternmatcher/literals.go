@@ -0,0 +1,84 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package patternmatcher
+
+import "strings"
+
+// LiteralPrefix returns the part of the given pattern preceding its first wildcard or
+// regular expression section. Every value matched by the pattern starts with that prefix.
+func LiteralPrefix(pattern string) string {
+	idxs, err := delimiterIndices(pattern, '<', '>')
+	if err != nil || len(idxs) == 0 {
+		return pattern
+	}
+
+	return pattern[:idxs[0]]
+}
+
+// LiteralLength returns the amount of characters of the given pattern, which are matched
+// literally, that is which do not belong to any wildcard or regular expression section.
+// It is used as a measure of how specific a pattern is.
+func LiteralLength(pattern string) int {
+	idxs, err := delimiterIndices(pattern, '<', '>')
+	if err != nil {
+		return len(pattern)
+	}
+
+	length := len(pattern)
+	for ind := 0; ind < len(idxs); ind += 2 {
+		length -= idxs[ind+1] - idxs[ind]
+	}
+
+	return length
+}
+
+// LiteralSuffix returns the part of the given pattern following its last wildcard or
+// regular expression section. Every value matched by the pattern ends with that suffix.
+func LiteralSuffix(pattern string) string {
+	idxs, err := delimiterIndices(pattern, '<', '>')
+	if err != nil || len(idxs) == 0 {
+		return pattern
+	}
+
+	return pattern[idxs[len(idxs)-1]:]
+}
+
+// MayOverlap reports whether there might be a value matched by both given patterns and their
+// matchers. If one of the patterns does not contain any wildcard or regular expression section,
+// the answer is exact. Otherwise, only the literal prefixes and suffixes of the patterns are
+// compared, so that patterns differing in their middle parts are considered overlapping.
+func MayOverlap(firstPattern string, first PatternMatcher, secondPattern string, second PatternMatcher) bool {
+	firstPrefix, secondPrefix := LiteralPrefix(firstPattern), LiteralPrefix(secondPattern)
+	if !strings.HasPrefix(firstPrefix, secondPrefix) && !strings.HasPrefix(secondPrefix, firstPrefix) {
+		return false
+	}
+
+	firstSuffix, secondSuffix := LiteralSuffix(firstPattern), LiteralSuffix(secondPattern)
+	if !strings.HasSuffix(firstSuffix, secondSuffix) && !strings.HasSuffix(secondSuffix, firstSuffix) {
+		return false
+	}
+
+	if firstPrefix == firstPattern {
+		return second.Match(firstPattern)
+	}
+
+	if secondPrefix == secondPattern {
+		return first.Match(secondPattern)
+	}
+
+	return true
+}
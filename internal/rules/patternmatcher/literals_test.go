@@ -0,0 +1,103 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package patternmatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLiterals(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc      string
+		pattern string
+		prefix  string
+		length  int
+	}{
+		{uc: "without wildcards", pattern: "http://foo.bar/baz", prefix: "http://foo.bar/baz", length: 18},
+		{uc: "with wildcard at the end", pattern: "http://foo.bar/<**>", prefix: "http://foo.bar/", length: 15},
+		{uc: "with multiple wildcards", pattern: "http://<*>.bar/<*>.json", prefix: "http://", length: 17},
+		{uc: "with wildcard at the beginning", pattern: "<https|http>://foo.bar/", prefix: "", length: 11},
+		{uc: "with nested delimiters", pattern: "http://foo.bar/<<a|b>>/baz", prefix: "http://foo.bar/", length: 19},
+		{uc: "unbalanced pattern", pattern: "http://foo.bar/<baz", prefix: "http://foo.bar/<baz", length: 19},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// WHEN
+			prefix := LiteralPrefix(tc.pattern)
+			length := LiteralLength(tc.pattern)
+
+			// THEN
+			assert.Equal(t, tc.prefix, prefix)
+			assert.Equal(t, tc.length, length)
+		})
+	}
+}
+
+func TestMayOverlap(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc       string
+		first    string
+		second   string
+		strategy string
+		overlap  bool
+	}{
+		{uc: "same literal patterns", first: "http://foo.bar/baz", second: "http://foo.bar/baz", overlap: true},
+		{uc: "different literal patterns", first: "http://foo.bar/baz", second: "http://foo.bar/qux"},
+		{uc: "literal pattern matched by wildcard", first: "http://foo.bar/baz", second: "http://foo.bar/<**>", overlap: true},
+		{uc: "literal pattern not matched by wildcard", first: "http://foo.bar/baz/qux", second: "http://foo.bar/<*>"},
+		{uc: "wildcards with different prefixes", first: "http://foo.bar/api/<**>", second: "http://foo.bar/web/<**>"},
+		{uc: "wildcards with nested prefixes", first: "http://foo.bar/<**>", second: "http://foo.bar/api/<**>", overlap: true},
+		{uc: "wildcards with different suffixes", first: "http://foo.bar/<**>.json", second: "http://foo.bar/<**>.xml"},
+		{uc: "wildcards in different positions", first: "http://foo.bar/api/<**>", second: "<**>/users", overlap: true},
+		{
+			uc: "literal pattern matched by regex", strategy: "regex",
+			first: "http://foo.bar/v1", second: "http://foo.bar/<v[0-9]+>", overlap: true,
+		},
+		{
+			uc: "literal pattern not matched by regex", strategy: "regex",
+			first: "http://foo.bar/vx", second: "http://foo.bar/<v[0-9]+>",
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			strategy := tc.strategy
+			if len(strategy) == 0 {
+				strategy = "glob"
+			}
+
+			first, err := NewPatternMatcher(strategy, tc.first)
+			require.NoError(t, err)
+
+			second, err := NewPatternMatcher(strategy, tc.second)
+			require.NoError(t, err)
+
+			// WHEN
+			overlap := MayOverlap(tc.first, first, tc.second, second)
+			reversed := MayOverlap(tc.second, second, tc.first, first)
+
+			// THEN
+			assert.Equal(t, tc.overlap, overlap)
+			assert.Equal(t, tc.overlap, reversed)
+		})
+	}
+}
@@ -24,6 +24,9 @@ var ErrUnsupportedPatternMatcher = errors.New("unsupported pattern matcher")
 
 type PatternMatcher interface {
 	Match(value string) bool
+	// Captures returns the values matched by the named sections of the pattern.
+	// It returns nil if the value does not match or the pattern does not define named sections.
+	Captures(value string) map[string]string
 }
 
 func NewPatternMatcher(typ, pattern string) (PatternMatcher, error) {
@@ -18,6 +18,7 @@ package patternmatcher
 
 import (
 	"errors"
+	"strconv"
 
 	"github.com/dlclark/regexp2"
 	"github.com/ory/ladon/compiler"
@@ -48,3 +49,28 @@ func (m *regexpMatcher) Match(matchAgainst string) bool {
 
 	return ok
 }
+
+func (m *regexpMatcher) Captures(value string) map[string]string {
+	// ignoring error as it will be set on timeouts, which basically is the same as match miss
+	match, _ := m.compiled.FindStringMatch(value)
+	if match == nil {
+		return nil
+	}
+
+	var captures map[string]string
+
+	for _, group := range match.Groups() {
+		// unnamed groups are named by their index
+		if _, err := strconv.Atoi(group.Name); err == nil {
+			continue
+		}
+
+		if captures == nil {
+			captures = make(map[string]string)
+		}
+
+		captures[group.Name] = group.String()
+	}
+
+	return captures
+}
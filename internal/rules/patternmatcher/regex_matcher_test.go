@@ -0,0 +1,70 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package patternmatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegexMatcherCaptures(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc           string
+		pattern      string
+		matchAgainst string
+		shouldMatch  bool
+		captures     map[string]string
+	}{
+		{
+			uc:           "without named groups",
+			pattern:      `https://example.com/<[a-z]+>/<(foo|bar)>`,
+			matchAgainst: "https://example.com/baz/foo",
+			shouldMatch:  true,
+		},
+		{
+			uc:           "with named groups",
+			pattern:      `https://example.com/tenants/<(?P<tenant>[^/]+)>/<(?<rest>.*)>`,
+			matchAgainst: "https://example.com/tenants/foo/bar/baz",
+			shouldMatch:  true,
+			captures:     map[string]string{"tenant": "foo", "rest": "bar/baz"},
+		},
+		{
+			uc:           "no match",
+			pattern:      `https://example.com/tenants/<(?P<tenant>[0-9]+)>`,
+			matchAgainst: "https://example.com/tenants/foo",
+			shouldMatch:  false,
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			matcher, err := newRegexMatcher(tc.pattern)
+			require.NoError(t, err)
+
+			// WHEN
+			matched := matcher.Match(tc.matchAgainst)
+			captures := matcher.Captures(tc.matchAgainst)
+
+			// THEN
+			assert.Equal(t, tc.shouldMatch, matched)
+			assert.Equal(t, tc.captures, captures)
+		})
+	}
+}
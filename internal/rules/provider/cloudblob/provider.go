@@ -30,6 +30,7 @@ import (
 	"golang.org/x/exp/maps"
 
 	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/health"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	rule_config "github.com/dadrus/heimdall/internal/rules/config"
 	"github.com/dadrus/heimdall/internal/rules/rule"
@@ -43,13 +44,14 @@ type provider struct {
 	p          rule.SetProcessor
 	l          zerolog.Logger
 	s          gocron.Scheduler
+	ht         *health.LoadTracker
 	cancel     context.CancelFunc
 	states     sync.Map
 	configured bool
 }
 
 func newProvider(
-	conf *config.Configuration, processor rule.SetProcessor, logger zerolog.Logger,
+	conf *config.Configuration, processor rule.SetProcessor, registry *health.Registry, logger zerolog.Logger,
 ) (*provider, error) {
 	rawConf := conf.Providers.CloudBlob
 
@@ -73,7 +75,7 @@ func newProvider(
 			"no buckets configured for cloud_blob rule provider")
 	}
 
-	logger = logger.With().Str("_provider_type", "cloud_blob").Logger()
+	logger = logger.With().Str("_provider_type", ProviderType).Logger()
 
 	ctx, cancel := context.WithCancel(context.Background())
 	ctx = logger.With().Logger().WithContext(ctx)
@@ -100,12 +102,16 @@ func newProvider(
 		configured: true,
 	}
 
+	sources := make([]string, len(providerConf.Buckets))
+
 	for idx, bucket := range providerConf.Buckets {
 		if bucket.URL == nil {
 			return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
 				"missing url for #%d bucket in cloud_blob rule provider configuration", idx)
 		}
 
+		sources[idx] = bucket.ID()
+
 		var definition gocron.JobDefinition
 
 		if providerConf.WatchInterval != nil && *providerConf.WatchInterval > 0 {
@@ -121,6 +127,9 @@ func newProvider(
 		}
 	}
 
+	prov.ht = health.NewLoadTracker("rule_provider:"+ProviderType, sources...)
+	registry.Register(prov.ht)
+
 	logger.Info().Msg("Rule provider configured.")
 
 	return prov, nil
@@ -166,22 +175,37 @@ func (p *provider) watchChanges(ctx context.Context, rsf RuleSetFetcher) error {
 			Str("_endpoint", rsf.ID()).
 			Msg("Failed to fetch rule set")
 
+		p.p.OnFailed(&rule_config.RuleSet{
+			MetaData: rule_config.MetaData{
+				Source:   rsf.ID(),
+				Provider: ProviderType,
+				ModTime:  time.Now(),
+			},
+		}, err)
+
 		if errors.Is(err, heimdall.ErrInternal) || errors.Is(err, heimdall.ErrConfiguration) {
 			return err
 		}
 	}
 
+	fetched := err == nil
 	state := p.getBucketState(rsf.ID())
 
 	// if no rule sets are available and no rule sets were known from the past
 	if len(ruleSets) == 0 && len(state) == 0 {
 		p.l.Debug().Str("_endpoint", rsf.ID()).Msg("No updates received")
 
+		if fetched {
+			p.ht.Loaded(rsf.ID())
+		}
+
 		return nil
 	}
 
 	if err = p.ruleSetsUpdated(ruleSets, state, rsf.ID()); err != nil {
 		p.l.Warn().Err(err).Str("_endpoint", rsf.ID()).Msg("Failed to apply rule set changes")
+	} else if fetched {
+		p.ht.Loaded(rsf.ID())
 	}
 
 	return nil
@@ -199,8 +223,9 @@ func (p *provider) ruleSetsUpdated(ruleSets []*rule_config.RuleSet, state Bucket
 	for _, ID := range removedIDs {
 		conf := &rule_config.RuleSet{
 			MetaData: rule_config.MetaData{
-				Source:  fmt.Sprintf("blob:%s", ID),
-				ModTime: time.Now(),
+				Source:   fmt.Sprintf("blob:%s", ID),
+				Provider: ProviderType,
+				ModTime:  time.Now(),
 			},
 		}
 
@@ -33,6 +33,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/health"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	config2 "github.com/dadrus/heimdall/internal/rules/config"
 	"github.com/dadrus/heimdall/internal/rules/rule/mocks"
@@ -144,7 +145,7 @@ buckets:
 			}
 
 			// WHEN
-			prov, err := newProvider(conf, mocks.NewRuleSetProcessorMock(t), log.Logger)
+			prov, err := newProvider(conf, mocks.NewRuleSetProcessorMock(t), health.NewRegistry(), log.Logger)
 
 			// THEN
 			tc.assert(t, err, prov)
@@ -526,7 +527,7 @@ rules:
 			}
 
 			logs := &strings.Builder{}
-			prov, err := newProvider(conf, mock, zerolog.New(logs))
+			prov, err := newProvider(conf, mock, health.NewRegistry(), zerolog.New(logs))
 			require.NoError(t, err)
 
 			ctx := context.Background()
@@ -131,6 +131,7 @@ func (e *ruleSetEndpoint) readRuleSet(ctx context.Context, bucket *blob.Bucket,
 
 	contents.Hash = attrs.MD5
 	contents.Source = fmt.Sprintf("%s@%s", key, e.ID())
+	contents.Provider = ProviderType
 	contents.ModTime = attrs.ModTime
 
 	return contents, nil
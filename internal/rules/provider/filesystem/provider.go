@@ -32,6 +32,7 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/health"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	config2 "github.com/dadrus/heimdall/internal/rules/config"
 	"github.com/dadrus/heimdall/internal/rules/rule"
@@ -42,13 +43,16 @@ type Provider struct {
 	src            string
 	w              *fsnotify.Watcher
 	p              rule.SetProcessor
+	ht             *health.LoadTracker
 	l              zerolog.Logger
 	states         sync.Map
 	envVarsEnabled bool
 	configured     bool
 }
 
-func NewProvider(conf *config.Configuration, processor rule.SetProcessor, logger zerolog.Logger) (*Provider, error) {
+func NewProvider(
+	conf *config.Configuration, processor rule.SetProcessor, registry *health.Registry, logger zerolog.Logger,
+) (*Provider, error) {
 	rawConf := conf.Providers.FileSystem
 
 	if conf.Providers.FileSystem == nil {
@@ -98,13 +102,17 @@ func NewProvider(conf *config.Configuration, processor rule.SetProcessor, logger
 		}
 	}
 
-	logger = logger.With().Str("_provider_type", "file_system").Logger()
+	tracker := health.NewLoadTracker("rule_provider:"+ProviderType, absPath)
+	registry.Register(tracker)
+
+	logger = logger.With().Str("_provider_type", ProviderType).Logger()
 	logger.Info().Msg("Rule provider configured.")
 
 	return &Provider{
 		src:            absPath,
 		w:              watcher,
 		p:              processor,
+		ht:             tracker,
 		l:              logger,
 		configured:     true,
 		envVarsEnabled: providerConf.EnvVarsEnabled,
@@ -124,6 +132,8 @@ func (p *Provider) Start(_ context.Context) error {
 		return err
 	}
 
+	p.ht.Loaded(p.src)
+
 	if p.w == nil {
 		p.l.Warn().
 			Msg("Watcher for file_system provider is not configured. Updates to rules will have no effects.")
@@ -208,6 +218,14 @@ func (p *Provider) ruleSetCreatedOrUpdated(fileName string) error {
 			return p.ruleSetDeleted(fileName)
 		}
 
+		p.p.OnFailed(&config2.RuleSet{
+			MetaData: config2.MetaData{
+				Source:   fmt.Sprintf("file_system:%s", fileName),
+				Provider: ProviderType,
+				ModTime:  time.Now(),
+			},
+		}, err)
+
 		return err
 	}
 
@@ -243,8 +261,9 @@ func (p *Provider) ruleSetDeleted(fileName string) error {
 
 	conf := &config2.RuleSet{
 		MetaData: config2.MetaData{
-			Source:  fmt.Sprintf("file_system:%s", fileName),
-			ModTime: time.Now(),
+			Source:   fmt.Sprintf("file_system:%s", fileName),
+			Provider: ProviderType,
+			ModTime:  time.Now(),
 		},
 	}
 
@@ -276,6 +295,7 @@ func (p *Provider) loadRuleSet(fileName string) (*config2.RuleSet, error) {
 
 	ruleSet.Hash = md.Sum(nil)
 	ruleSet.Source = fmt.Sprintf("file_system:%s", fileName)
+	ruleSet.Provider = ProviderType
 	ruleSet.ModTime = stat.ModTime()
 
 	return ruleSet, nil
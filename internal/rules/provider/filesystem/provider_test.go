@@ -29,6 +29,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/health"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	config2 "github.com/dadrus/heimdall/internal/rules/config"
 	"github.com/dadrus/heimdall/internal/rules/rule/mocks"
@@ -139,7 +140,7 @@ func TestNewProvider(t *testing.T) {
 			// GIVEN
 			conf := &config.Configuration{Providers: config.RuleProviders{FileSystem: tc.conf}}
 
-			prov, err := NewProvider(conf, nil, log.Logger)
+			prov, err := NewProvider(conf, nil, health.NewRegistry(), log.Logger)
 
 			tc.assert(t, err, prov)
 		})
@@ -178,6 +179,16 @@ func TestProviderLifecycle(t *testing.T) {
 
 				return file.Name()
 			},
+			setupProcessor: func(t *testing.T, processor *mocks.RuleSetProcessorMock) {
+				t.Helper()
+
+				processor.EXPECT().OnFailed(mock.Anything, mock.Anything).
+					Run(func(ruleSet *config2.RuleSet, _ error) {
+						assert.Contains(t, ruleSet.Source, "file_system:")
+						assert.Equal(t, ProviderType, ruleSet.Provider)
+					}).
+					Once()
+			},
 			assert: func(t *testing.T, err error, provider *Provider, processor *mocks.RuleSetProcessorMock) {
 				t.Helper()
 
@@ -480,10 +491,13 @@ rules:
 				require.NoError(t, err)
 			}
 
+			src := setupContents(t, tmpFile, tmpDir)
+
 			// GIVEN
 			prov := &Provider{
-				src:        setupContents(t, tmpFile, tmpDir),
+				src:        src,
 				p:          processor,
+				ht:         health.NewLoadTracker(ProviderType, src),
 				l:          log.Logger,
 				w:          watcher,
 				configured: true,
@@ -498,6 +512,12 @@ rules:
 
 			// THEN
 			tc.assert(t, err, prov, processor)
+
+			if err == nil {
+				require.NoError(t, prov.ht.Check(ctx))
+			} else {
+				require.ErrorIs(t, prov.ht.Check(ctx), health.ErrInitialLoadPending)
+			}
 		})
 	}
 }
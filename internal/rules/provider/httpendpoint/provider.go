@@ -29,6 +29,7 @@ import (
 
 	"github.com/dadrus/heimdall/internal/cache"
 	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/health"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	config2 "github.com/dadrus/heimdall/internal/rules/config"
 	"github.com/dadrus/heimdall/internal/rules/rule"
@@ -40,13 +41,18 @@ type provider struct {
 	p          rule.SetProcessor
 	l          zerolog.Logger
 	s          gocron.Scheduler
+	ht         *health.LoadTracker
 	cancel     context.CancelFunc
 	states     sync.Map
 	configured bool
 }
 
 func newProvider(
-	conf *config.Configuration, cch cache.Cache, processor rule.SetProcessor, logger zerolog.Logger,
+	conf *config.Configuration,
+	cch cache.Cache,
+	processor rule.SetProcessor,
+	registry *health.Registry,
+	logger zerolog.Logger,
 ) (*provider, error) {
 	rawConf := conf.Providers.HTTPEndpoint
 
@@ -70,11 +76,14 @@ func newProvider(
 			"failed validating http_endpoint rule provider config").CausedBy(err)
 	}
 
-	for _, ep := range providerConf.Endpoints {
+	sources := make([]string, len(providerConf.Endpoints))
+	for idx, ep := range providerConf.Endpoints {
 		ep.init()
+
+		sources[idx] = ep.ID()
 	}
 
-	logger = logger.With().Str("_provider_type", "http_endpoint").Logger()
+	logger = logger.With().Str("_provider_type", ProviderType).Logger()
 	ctx, cancel := context.WithCancel(context.Background())
 	ctx = logger.WithContext(cache.WithContext(ctx, cch))
 
@@ -96,6 +105,7 @@ func newProvider(
 		p:          processor,
 		l:          logger,
 		s:          scheduler,
+		ht:         health.NewLoadTracker("rule_provider:"+ProviderType, sources...),
 		cancel:     cancel,
 		configured: true,
 	}
@@ -116,6 +126,8 @@ func newProvider(
 		}
 	}
 
+	registry.Register(prov.ht)
+
 	logger.Info().Msg("Rule provider configured.")
 
 	return prov, nil
@@ -151,6 +163,8 @@ func (p *provider) watchChanges(ctx context.Context, rsf RuleSetFetcher) error {
 		Msg("Retrieving rule set")
 
 	ruleSet, err := rsf.FetchRuleSet(ctx)
+	fetched := err == nil || errors.Is(err, config2.ErrEmptyRuleSet)
+
 	if err != nil {
 		if errors.Is(err, context.Canceled) {
 			p.l.Debug().Msg("Watcher closed")
@@ -162,6 +176,16 @@ func (p *provider) watchChanges(ctx context.Context, rsf RuleSetFetcher) error {
 			Str("_endpoint", rsf.ID()).
 			Msg("Failed to fetch rule set")
 
+		if !errors.Is(err, config2.ErrEmptyRuleSet) {
+			p.p.OnFailed(&config2.RuleSet{
+				MetaData: config2.MetaData{
+					Source:   fmt.Sprintf("http_endpoint:%s", rsf.ID()),
+					Provider: ProviderType,
+					ModTime:  time.Now(),
+				},
+			}, err)
+		}
+
 		if !errors.Is(err, config2.ErrEmptyRuleSet) &&
 			(errors.Is(err, heimdall.ErrInternal) || errors.Is(err, heimdall.ErrConfiguration)) {
 			return err
@@ -169,8 +193,9 @@ func (p *provider) watchChanges(ctx context.Context, rsf RuleSetFetcher) error {
 
 		ruleSet = &config2.RuleSet{
 			MetaData: config2.MetaData{
-				Source:  fmt.Sprintf("http_endpoint:%s", rsf.ID()),
-				ModTime: time.Now(),
+				Source:   fmt.Sprintf("http_endpoint:%s", rsf.ID()),
+				Provider: ProviderType,
+				ModTime:  time.Now(),
 			},
 		}
 	}
@@ -179,6 +204,8 @@ func (p *provider) watchChanges(ctx context.Context, rsf RuleSetFetcher) error {
 		p.l.Warn().Err(err).
 			Str("_src", rsf.ID()).
 			Msg("Failed to apply rule set changes")
+	} else if fetched {
+		p.ht.Loaded(rsf.ID())
 	}
 
 	return nil
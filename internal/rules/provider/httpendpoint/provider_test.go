@@ -34,6 +34,7 @@ import (
 
 	"github.com/dadrus/heimdall/internal/cache/memory"
 	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/health"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	config2 "github.com/dadrus/heimdall/internal/rules/config"
 	"github.com/dadrus/heimdall/internal/rules/rule/mocks"
@@ -167,7 +168,7 @@ endpoints:
 			}
 
 			// WHEN
-			prov, err := newProvider(conf, memory.New(), mocks.NewRuleSetProcessorMock(t), log.Logger)
+			prov, err := newProvider(conf, memory.New(), mocks.NewRuleSetProcessorMock(t), health.NewRegistry(), log.Logger)
 
 			// THEN
 			tc.assert(t, err, prov)
@@ -201,6 +202,7 @@ func TestProviderLifecycle(t *testing.T) {
 		conf           []byte
 		setupProcessor func(t *testing.T, processor *mocks.RuleSetProcessorMock)
 		writeResponse  ResponseWriter
+		notReadyErr    error
 		assert         func(t *testing.T, logs fmt.Stringer, processor *mocks.RuleSetProcessorMock)
 	}{
 		{
@@ -209,11 +211,23 @@ func TestProviderLifecycle(t *testing.T) {
 endpoints:
 - url: ` + srv.URL + `
 `),
+			notReadyErr: health.ErrInitialLoadPending,
 			writeResponse: func(t *testing.T, w http.ResponseWriter) {
 				t.Helper()
 
 				w.WriteHeader(http.StatusBadRequest)
 			},
+			setupProcessor: func(t *testing.T, processor *mocks.RuleSetProcessorMock) {
+				t.Helper()
+
+				processor.EXPECT().OnFailed(mock.Anything, mock.Anything).
+					Run(func(ruleSet *config2.RuleSet, err error) {
+						assert.Equal(t, "http_endpoint:"+srv.URL, ruleSet.Source)
+						assert.Equal(t, ProviderType, ruleSet.Provider)
+						require.ErrorIs(t, err, heimdall.ErrCommunication)
+					}).
+					Once()
+			},
 			assert: func(t *testing.T, logs fmt.Stringer, processor *mocks.RuleSetProcessorMock) {
 				t.Helper()
 
@@ -376,6 +390,8 @@ rules:
 				processor.EXPECT().OnDeleted(mock.Anything).
 					Run(mock2.NewArgumentCaptor[*config2.RuleSet](&processor.Mock, "captor2").Capture).
 					Return(nil).Once()
+
+				processor.EXPECT().OnFailed(mock.Anything, mock.Anything).Once()
 			},
 			assert: func(t *testing.T, logs fmt.Stringer, processor *mocks.RuleSetProcessorMock) {
 				t.Helper()
@@ -600,6 +616,7 @@ rules:
 endpoints:
 - url: ` + srv.URL + `
 `),
+			notReadyErr: health.ErrInitialLoadPending,
 			writeResponse: func(t *testing.T, w http.ResponseWriter) {
 				t.Helper()
 
@@ -710,6 +727,7 @@ rules:
 				t.Helper()
 
 				call := processor.EXPECT().OnCreated(mock.Anything).Return(nil).Once()
+				processor.EXPECT().OnFailed(mock.Anything, mock.Anything).NotBefore(call)
 				processor.EXPECT().OnDeleted(mock.Anything).Return(testsupport.ErrTestPurpose).NotBefore(call)
 			},
 			assert: func(t *testing.T, logs fmt.Stringer, processor *mocks.RuleSetProcessorMock) {
@@ -741,7 +759,7 @@ rules:
 			setupProcessor(t, processor)
 
 			logs := &strings.Builder{}
-			prov, err := newProvider(conf, memory.New(), processor, zerolog.New(logs))
+			prov, err := newProvider(conf, memory.New(), processor, health.NewRegistry(), zerolog.New(logs))
 			require.NoError(t, err)
 
 			ctx := context.Background()
@@ -764,6 +782,12 @@ rules:
 			// THEN
 			require.NoError(t, err)
 			tc.assert(t, logs, processor)
+
+			if tc.notReadyErr != nil {
+				require.ErrorIs(t, prov.ht.Check(ctx), tc.notReadyErr)
+			} else {
+				require.NoError(t, prov.ht.Check(ctx))
+			}
 		})
 	}
 }
@@ -85,6 +85,7 @@ func (e *ruleSetEndpoint) FetchRuleSet(ctx context.Context) (*config.RuleSet, er
 
 	ruleSet.Hash = md.Sum(nil)
 	ruleSet.Source = fmt.Sprintf("http_endpoint:%s", e.ID())
+	ruleSet.Provider = ProviderType
 	ruleSet.ModTime = time.Now()
 
 	return ruleSet, nil
@@ -32,6 +32,7 @@ const (
 	ConditionRuleSetUnloaded         ConditionReason = "RuleSetUnloaded"
 	ConditionRuleSetUnloadingFailed  ConditionReason = "RuleSetUnloadingFailed"
 	ConditionControllerStopped       ConditionReason = "ControllerStopped"
+	ConditionRuleSetConflicting      ConditionReason = "RuleSetConflicting"
 )
 
 // +kubebuilder:object:generate=true
@@ -41,6 +41,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/health"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	config2 "github.com/dadrus/heimdall/internal/rules/config"
 	"github.com/dadrus/heimdall/internal/rules/provider/kubernetes/admissioncontroller"
@@ -53,6 +54,11 @@ import (
 
 type ConfigFactory func() (*rest.Config, error)
 
+var (
+	ErrSyncPending = errors.New("initial synchronization of rule sets not completed")
+	ErrSyncFailed  = errors.New("synchronization of rule sets failed")
+)
+
 type provider struct {
 	p          rule.SetProcessor
 	l          zerolog.Logger
@@ -65,6 +71,16 @@ type provider struct {
 	ac         string
 	id         string
 	store      cache.Store
+
+	mut       sync.RWMutex
+	hasSynced cache.InformerSynced
+	syncErr   error
+
+	// conflicts reported by the repository are applied to the status of the rule sets
+	// asynchronously by a dedicated worker to not block the repository
+	conflictsMut     sync.Mutex
+	pendingConflicts map[string][]rule.Conflict
+	conflictsSignal  chan struct{}
 }
 
 func newProvider(
@@ -73,6 +89,8 @@ func newProvider(
 	k8sCF ConfigFactory,
 	processor rule.SetProcessor,
 	factory rule.Factory,
+	notifier rule.ConflictNotifier,
+	registry *health.Registry,
 ) (*provider, error) {
 	rawConf := conf.Providers.Kubernetes
 
@@ -110,7 +128,7 @@ func newProvider(
 
 	logger.Info().Msg("Rule provider configured.")
 
-	return &provider{
+	prov := &provider{
 		p:          processor,
 		l:          logger,
 		cl:         client,
@@ -118,7 +136,15 @@ func newProvider(
 		adc:        adc,
 		id:         x.IfThenElse(len(instanceID) == 0, "unknown", instanceID),
 		configured: true,
-	}, nil
+
+		pendingConflicts: make(map[string][]rule.Conflict),
+		conflictsSignal:  make(chan struct{}, 1),
+	}
+
+	notifier.Subscribe(prov)
+	registry.Register(prov)
+
+	return prov, nil
 }
 
 func (p *provider) newController(ctx context.Context, namespace string) (cache.Store, cache.Controller) {
@@ -126,8 +152,18 @@ func (p *provider) newController(ctx context.Context, namespace string) (cache.S
 
 	return cache.NewInformer(
 		&cache.ListWatch{
-			ListFunc:  func(opts metav1.ListOptions) (runtime.Object, error) { return repository.List(ctx, opts) },
-			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) { return repository.Watch(ctx, opts) },
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				list, err := repository.List(ctx, opts)
+				p.syncFinished(err)
+
+				return list, err
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				wi, err := repository.Watch(ctx, opts)
+				p.syncFinished(err)
+
+				return wi, err
+			},
 		},
 		&v1alpha3.RuleSet{},
 		0,
@@ -160,9 +196,22 @@ func (p *provider) Start(ctx context.Context) error {
 	// will time out. We need however a fresh context here, which can be
 	// canceled
 	store, controller := p.newController(newCtx, "") //nolint:contextcheck
+
+	p.mut.Lock()
 	p.store = store
+	p.hasSynced = controller.HasSynced
+	p.mut.Unlock()
+
+	p.wg.Add(2) //nolint:gomnd
+
+	go func() {
+		p.l.Debug().Msg("Starting conflicts status update loop")
+
+		p.updateConflictsLoop(newCtx) //nolint:contextcheck
+		p.wg.Done()
 
-	p.wg.Add(1)
+		p.l.Debug().Msg("Conflicts status update loop exited")
+	}()
 
 	go func() {
 		p.l.Debug().Msg("Starting reconciliation loop")
@@ -177,11 +226,19 @@ func (p *provider) Start(ctx context.Context) error {
 }
 
 func (p *provider) Stop(ctx context.Context) error {
-	if !p.configured || p.stopped {
+	if !p.configured {
 		return nil
 	}
 
+	p.mut.Lock()
+	stopped := p.stopped
 	p.stopped = true
+	p.mut.Unlock()
+
+	if stopped {
+		return nil
+	}
+
 	p.l.Info().Msg("Tearing down rule provider.")
 
 	p.cancel()
@@ -205,6 +262,33 @@ func (p *provider) Stop(ctx context.Context) error {
 	}
 }
 
+func (p *provider) Name() string { return "rule_provider:" + ProviderType }
+
+func (p *provider) Check(_ context.Context) error {
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+
+	if p.hasSynced == nil || !p.hasSynced() {
+		return ErrSyncPending
+	}
+
+	if p.syncErr != nil {
+		return fmt.Errorf("%w: %w", ErrSyncFailed, p.syncErr)
+	}
+
+	return nil
+}
+
+func (p *provider) syncFinished(err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	p.mut.Lock()
+	p.syncErr = err
+	p.mut.Unlock()
+}
+
 func (p *provider) filter(obj any) bool {
 	// should never be of a different type. ok if panics
 	rs := obj.(*v1alpha3.RuleSet) // nolint: forcetypeassert
@@ -213,7 +297,7 @@ func (p *provider) filter(obj any) bool {
 }
 
 func (p *provider) addRuleSet(obj any) {
-	if p.stopped {
+	if p.isStopped() {
 		return
 	}
 
@@ -249,7 +333,7 @@ func (p *provider) addRuleSet(obj any) {
 }
 
 func (p *provider) updateRuleSet(oldObj, newObj any) {
-	if p.stopped {
+	if p.isStopped() {
 		return
 	}
 
@@ -292,7 +376,7 @@ func (p *provider) updateRuleSet(oldObj, newObj any) {
 }
 
 func (p *provider) deleteRuleSet(obj any) {
-	if p.stopped {
+	if p.isStopped() {
 		return
 	}
 
@@ -330,8 +414,9 @@ func (p *provider) deleteRuleSet(obj any) {
 func (p *provider) toRuleSetConfiguration(rs *v1alpha3.RuleSet) *config2.RuleSet {
 	return &config2.RuleSet{
 		MetaData: config2.MetaData{
-			Source:  fmt.Sprintf("%s:%s:%s", ProviderType, rs.Namespace, rs.UID),
-			ModTime: rs.CreationTimestamp.Time,
+			Source:   fmt.Sprintf("%s:%s:%s", ProviderType, rs.Namespace, rs.UID),
+			Provider: ProviderType,
+			ModTime:  rs.CreationTimestamp.Time,
 		},
 		Version: p.mapVersion(rs.APIVersion),
 		Name:    rs.Name,
@@ -353,32 +438,144 @@ func (p *provider) updateStatus(
 	usageIncrement int,
 	msg string,
 ) {
-	modRS := rs.DeepCopy()
-	repository := p.cl.RuleSetRepository(modRS.Namespace)
-
 	p.l.Debug().Msg("Updating RuleSet status")
 
-	conditionType := fmt.Sprintf("%s/Reconciliation", p.id)
+	p.patchStatus(ctx, rs, func(modRS *v1alpha3.RuleSet) {
+		conditionType := fmt.Sprintf("%s/Reconciliation", p.id)
+
+		if reason == v1alpha3.ConditionControllerStopped || reason == v1alpha3.ConditionRuleSetUnloaded {
+			meta.RemoveStatusCondition(&modRS.Status.Conditions, conditionType)
+			meta.RemoveStatusCondition(&modRS.Status.Conditions, fmt.Sprintf("%s/Conflicts", p.id))
+		} else {
+			meta.SetStatusCondition(&modRS.Status.Conditions, metav1.Condition{
+				Type:               conditionType,
+				Status:             status,
+				ObservedGeneration: modRS.Generation,
+				Reason:             string(reason),
+				Message:            msg,
+			})
+		}
+
+		modRS.Status.ActiveIn = x.IfThenElse(len(modRS.Status.ActiveIn) == 0, "0/0", modRS.Status.ActiveIn)
+
+		usedBy := strings.Split(modRS.Status.ActiveIn, "/")
+		loadedBy, _ := strconv.Atoi(usedBy[0])
+		matchedBy, _ := strconv.Atoi(usedBy[1])
+
+		modRS.Status.ActiveIn = fmt.Sprintf("%d/%d", loadedBy+usageIncrement, matchedBy+matchIncrement)
+	})
+}
+
+func (p *provider) OnConflictsChanged(srcID string, conflicts []rule.Conflict) {
+	if !strings.HasPrefix(srcID, ProviderType+":") {
+		return
+	}
+
+	if p.isStopped() {
+		return
+	}
+
+	p.conflictsMut.Lock()
+	// only the latest state of the conflicts of a rule set is of interest
+	p.pendingConflicts[srcID] = conflicts
+	p.conflictsMut.Unlock()
+
+	select {
+	case p.conflictsSignal <- struct{}{}:
+	default:
+		// the worker has been signaled already and will pick up this update as well
+	}
+}
+
+func (p *provider) updateConflictsLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.conflictsSignal:
+			p.conflictsMut.Lock()
+			pending := p.pendingConflicts
+			p.pendingConflicts = make(map[string][]rule.Conflict)
+			p.conflictsMut.Unlock()
+
+			for srcID, conflicts := range pending {
+				if ctx.Err() != nil {
+					return
+				}
+
+				p.applyConflicts(ctx, srcID, conflicts)
+			}
+		}
+	}
+}
+
+func (p *provider) applyConflicts(ctx context.Context, srcID string, conflicts []rule.Conflict) {
+	// the source id has the form <provider type>:<namespace>:<uid>
+	uid := srcID[strings.LastIndex(srcID, ":")+1:]
+
+	for _, rs := range p.ruleSets() {
+		if string(rs.UID) == uid {
+			p.updateConflictsStatus(ctx, rs, conflicts)
+
+			return
+		}
+	}
+}
+
+func (p *provider) isStopped() bool {
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+
+	return p.stopped || p.store == nil
+}
+
+func (p *provider) ruleSets() []*v1alpha3.RuleSet {
+	p.mut.RLock()
+	store := p.store
+	p.mut.RUnlock()
+
+	if store == nil {
+		return nil
+	}
+
+	// should never be of a different type. ok if panics
+	// nolint: forcetypeassert
+	return slicex.Map(store.List(), func(s any) *v1alpha3.RuleSet { return s.(*v1alpha3.RuleSet) })
+}
+
+func (p *provider) updateConflictsStatus(ctx context.Context, rs *v1alpha3.RuleSet, conflicts []rule.Conflict) {
+	p.l.Debug().Msg("Updating RuleSet conflicts status")
+
+	p.patchStatus(ctx, rs, func(modRS *v1alpha3.RuleSet) {
+		conditionType := fmt.Sprintf("%s/Conflicts", p.id)
+
+		if len(conflicts) == 0 {
+			meta.RemoveStatusCondition(&modRS.Status.Conditions, conditionType)
+
+			return
+		}
+
+		descriptions := slicex.Map(conflicts, func(conflict rule.Conflict) string {
+			return fmt.Sprintf("rule %s conflicts with rule %s from %s",
+				conflict.RuleID, conflict.ConflictingRuleID, conflict.ConflictingSrcID)
+		})
 
-	if reason == v1alpha3.ConditionControllerStopped || reason == v1alpha3.ConditionRuleSetUnloaded {
-		meta.RemoveStatusCondition(&modRS.Status.Conditions, conditionType)
-	} else {
 		meta.SetStatusCondition(&modRS.Status.Conditions, metav1.Condition{
 			Type:               conditionType,
-			Status:             status,
+			Status:             metav1.ConditionTrue,
 			ObservedGeneration: modRS.Generation,
-			Reason:             string(reason),
-			Message:            msg,
+			Reason:             string(v1alpha3.ConditionRuleSetConflicting),
+			Message: fmt.Sprintf("%s instance detected conflicting rules: %s",
+				p.id, strings.Join(descriptions, "; ")),
 		})
-	}
-
-	modRS.Status.ActiveIn = x.IfThenElse(len(modRS.Status.ActiveIn) == 0, "0/0", modRS.Status.ActiveIn)
+	})
+}
 
-	usedBy := strings.Split(modRS.Status.ActiveIn, "/")
-	loadedBy, _ := strconv.Atoi(usedBy[0])
-	matchedBy, _ := strconv.Atoi(usedBy[1])
+func (p *provider) patchStatus(ctx context.Context, rs *v1alpha3.RuleSet, modify func(modRS *v1alpha3.RuleSet)) {
+	modRS := rs.DeepCopy()
+	repository := p.cl.RuleSetRepository(modRS.Namespace)
 
-	modRS.Status.ActiveIn = fmt.Sprintf("%d/%d", loadedBy+usageIncrement, matchedBy+matchIncrement)
+	modify(modRS)
 
 	_, err := repository.PatchStatus(
 		p.l.WithContext(ctx),
@@ -391,10 +588,14 @@ func (p *provider) updateStatus(
 		return
 	}
 
-	// if there is an error, it is always of the below type
+	// the update conflicts worker uses a context, which is canceled on shutdown. So the error
+	// is not necessarily of the below type
 	var statusErr *errors2.StatusError
+	if !errors.As(err, &statusErr) {
+		p.l.Warn().Err(err).Msgf("Failed updating RuleSet status")
 
-	errors.As(err, &statusErr)
+		return
+	}
 
 	switch statusErr.ErrStatus.Code {
 	case http.StatusNotFound:
@@ -412,7 +613,7 @@ func (p *provider) updateStatus(
 		if rs, err = repository.Get(ctx, rsKey, metav1.GetOptions{}); err != nil {
 			p.l.Warn().Err(err).Msgf("Failed retrieving new RuleSet version for status update")
 		} else {
-			p.updateStatus(ctx, rs, status, reason, matchIncrement, usageIncrement, msg)
+			p.patchStatus(ctx, rs, modify)
 		}
 	default:
 		p.l.Warn().Err(err).Msgf("Failed updating RuleSet status")
@@ -421,8 +622,7 @@ func (p *provider) updateStatus(
 
 func (p *provider) finalize(ctx context.Context) {
 	for _, rs := range slicex.Filter(
-		// nolint: forcetypeassert
-		slicex.Map(p.store.List(), func(s any) *v1alpha3.RuleSet { return s.(*v1alpha3.RuleSet) }),
+		p.ruleSets(),
 		func(set *v1alpha3.RuleSet) bool { return set.Spec.AuthClassName == p.ac },
 	) {
 		p.updateStatus(ctx, rs, metav1.ConditionFalse, v1alpha3.ConditionControllerStopped, -1, -1,
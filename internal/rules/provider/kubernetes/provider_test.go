@@ -39,9 +39,11 @@ import (
 	"k8s.io/client-go/rest"
 
 	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/health"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	config2 "github.com/dadrus/heimdall/internal/rules/config"
 	"github.com/dadrus/heimdall/internal/rules/provider/kubernetes/api/v1alpha3"
+	"github.com/dadrus/heimdall/internal/rules/rule"
 	"github.com/dadrus/heimdall/internal/rules/rule/mocks"
 	"github.com/dadrus/heimdall/internal/x"
 	"github.com/dadrus/heimdall/internal/x/testsupport"
@@ -106,8 +108,12 @@ func TestNewProvider(t *testing.T) {
 			}
 			k8sCF := func() (*rest.Config, error) { return &rest.Config{Host: "http://localhost:80001"}, nil }
 
+			notifier := mocks.NewConflictNotifierMock(t)
+			notifier.EXPECT().Subscribe(mock.Anything).Maybe()
+
 			// WHEN
-			prov, err := newProvider(log.Logger, conf, k8sCF, mocks.NewRuleSetProcessorMock(t), mocks.NewFactoryMock(t))
+			prov, err := newProvider(log.Logger, conf, k8sCF,
+				mocks.NewRuleSetProcessorMock(t), mocks.NewFactoryMock(t), notifier, health.NewRegistry())
 
 			// THEN
 			tc.assert(t, err, prov)
@@ -115,6 +121,33 @@ func TestNewProvider(t *testing.T) {
 	}
 }
 
+func TestProviderPatchStatusWithCanceledContext(t *testing.T) {
+	// not parallel for the same reasons as described in TestNewProvider
+
+	// GIVEN
+	conf := &config.Configuration{
+		Providers: config.RuleProviders{Kubernetes: map[string]any{}},
+	}
+	k8sCF := func() (*rest.Config, error) { return &rest.Config{Host: "http://localhost:80001"}, nil }
+
+	notifier := mocks.NewConflictNotifierMock(t)
+	notifier.EXPECT().Subscribe(mock.Anything).Maybe()
+
+	prov, err := newProvider(log.Logger, conf, k8sCF,
+		mocks.NewRuleSetProcessorMock(t), mocks.NewFactoryMock(t), notifier, health.NewRegistry())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	rs := &v1alpha3.RuleSet{ObjectMeta: metav1.ObjectMeta{Name: "test-rule", Namespace: "foo"}}
+
+	// WHEN & THEN
+	assert.NotPanics(t, func() {
+		prov.patchStatus(ctx, rs, func(modRS *v1alpha3.RuleSet) { modRS.Status.ActiveIn = "1/1" })
+	})
+}
+
 type RuleSetResourceHandler struct {
 	statusUpdates       []*v1alpha3.RuleSetStatus
 	listCallIdx         int
@@ -334,6 +367,7 @@ func TestProviderLifecycle(t *testing.T) {
 		watchEvent     func(rs v1alpha3.RuleSet, callIdx int) (watch.Event, error)
 		updateStatus   func(rs v1alpha3.RuleSet, callIdx int) (*metav1.Status, error)
 		setupProcessor func(t *testing.T, processor *mocks.RuleSetProcessorMock)
+		afterStart     func(t *testing.T, prov *provider)
 		assert         func(t *testing.T, statusList *[]*v1alpha3.RuleSetStatus, processor *mocks.RuleSetProcessorMock)
 	}{
 		{
@@ -386,6 +420,53 @@ func TestProviderLifecycle(t *testing.T) {
 				assert.Equal(t, v1alpha3.ConditionRuleSetActive, v1alpha3.ConditionReason(condition.Reason))
 			},
 		},
+		{
+			uc:   "rule set added and conflicts with rules from other rule sets",
+			conf: []byte("auth_class: bar"),
+			watchEvent: func(rs v1alpha3.RuleSet, _ int) (watch.Event, error) {
+				// status updates are modifications as well. These must be seen by the
+				// provider to base the next status update on the current state
+				return watch.Event{Type: watch.Modified, Object: &rs}, nil
+			},
+			setupProcessor: func(t *testing.T, processor *mocks.RuleSetProcessorMock) {
+				t.Helper()
+
+				processor.EXPECT().OnCreated(mock.Anything).Return(nil).Once()
+			},
+			afterStart: func(t *testing.T, prov *provider) {
+				t.Helper()
+
+				time.Sleep(250 * time.Millisecond)
+
+				// not managed by this provider, is ignored
+				prov.OnConflictsChanged("file_system:/etc/heimdall/rules.yaml", []rule.Conflict{{}})
+
+				prov.OnConflictsChanged("kubernetes:foo:dfb2a2f1-1ad2-4d8c-8456-516fc94abb86", []rule.Conflict{
+					{
+						RuleID:            "test",
+						SrcID:             "kubernetes:foo:dfb2a2f1-1ad2-4d8c-8456-516fc94abb86",
+						ConflictingRuleID: "other",
+						ConflictingSrcID:  "file_system:/etc/heimdall/rules.yaml",
+					},
+				})
+			},
+			assert: func(t *testing.T, statusList *[]*v1alpha3.RuleSetStatus, processor *mocks.RuleSetProcessorMock) {
+				t.Helper()
+
+				time.Sleep(250 * time.Millisecond)
+
+				require.Len(t, *statusList, 2)
+				assert.Equal(t, "1/1", (*statusList)[1].ActiveIn)
+
+				require.Len(t, (*statusList)[1].Conditions, 2)
+				condition := (*statusList)[1].Conditions[1]
+				assert.Equal(t, metav1.ConditionTrue, condition.Status)
+				assert.Contains(t, condition.Type, "/Conflicts")
+				assert.Equal(t, v1alpha3.ConditionRuleSetConflicting, v1alpha3.ConditionReason(condition.Reason))
+				assert.Contains(t, condition.Message,
+					"rule test conflicts with rule other from file_system:/etc/heimdall/rules.yaml")
+			},
+		},
 		{
 			uc:   "adding rule set fails",
 			conf: []byte("auth_class: bar"),
@@ -952,10 +1033,15 @@ func TestProviderLifecycle(t *testing.T) {
 			processor := mocks.NewRuleSetProcessorMock(t)
 			setupProcessor(t, processor)
 
-			prov, err := newProvider(log.Logger, conf, k8sCF, processor, mocks.NewFactoryMock(t))
+			notifier := mocks.NewConflictNotifierMock(t)
+			notifier.EXPECT().Subscribe(mock.Anything).Once()
+
+			prov, err := newProvider(
+				log.Logger, conf, k8sCF, processor, mocks.NewFactoryMock(t), notifier, health.NewRegistry())
 			require.NoError(t, err)
 
 			ctx := context.Background()
+			require.ErrorIs(t, prov.Check(ctx), ErrSyncPending)
 
 			// WHEN
 			err = prov.Start(ctx)
@@ -967,7 +1053,13 @@ func TestProviderLifecycle(t *testing.T) {
 
 			// THEN
 			require.NoError(t, err)
+
+			if tc.afterStart != nil {
+				tc.afterStart(t, prov)
+			}
+
 			tc.assert(t, &handler.statusUpdates, processor)
+			require.NoError(t, prov.Check(ctx))
 		})
 	}
 }
@@ -18,9 +18,13 @@ package rules
 
 import (
 	"bytes"
+	"cmp"
 	"context"
+	"encoding/hex"
 	"net/url"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/rs/zerolog"
 
@@ -29,6 +33,7 @@ import (
 	"github.com/dadrus/heimdall/internal/rules/rule"
 	"github.com/dadrus/heimdall/internal/x"
 	"github.com/dadrus/heimdall/internal/x/errorchain"
+	"github.com/dadrus/heimdall/internal/x/radixtree"
 	"github.com/dadrus/heimdall/internal/x/slicex"
 )
 
@@ -41,9 +46,13 @@ func newRepository(
 		dr: x.IfThenElseExec(ruleFactory.HasDefaultRule(),
 			func() rule.Rule { return ruleFactory.DefaultRule() },
 			func() rule.Rule { return nil }),
-		logger: logger,
-		queue:  queue,
-		quit:   make(chan bool),
+		logger:    logger,
+		index:     radixtree.New[*ruleImpl](compareRules),
+		conflicts: make(map[string][]rule.Conflict),
+		ruleSets:  make(map[string]*ruleSetState),
+		providers: make(map[string]*providerState),
+		queue:     queue,
+		quit:      make(chan bool),
 	}
 }
 
@@ -51,29 +60,130 @@ type repository struct {
 	dr     rule.Rule
 	logger zerolog.Logger
 
-	rules []rule.Rule
-	mutex sync.RWMutex
+	rules     []rule.Rule
+	index     *radixtree.Tree[*ruleImpl]
+	conflicts map[string][]rule.Conflict // accessed by the rule set changes processing goroutine only
+	observers []rule.ConflictObserver
+	ruleSets  map[string]*ruleSetState
+	providers map[string]*providerState
+	mutex     sync.RWMutex
 
 	queue event.RuleSetChangedEventQueue
 	quit  chan bool
+
+	// the rules of a static repository are not used to serve requests. So these are not
+	// activated, which e.g. avoids health checks being started for their upstreams.
+	static bool
 }
 
-func (r *repository) FindRule(requestURL *url.URL) (rule.Rule, error) {
+func (r *repository) FindRule(request *heimdall.Request) (rule.Rule, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
-	for _, rul := range r.rules {
-		if rul.MatchesURL(requestURL) {
+	var methodMismatch rule.Rule
+
+	for _, rul := range r.candidates(&request.URL.URL) {
+		if !rul.MatchesURL(&request.URL.URL) || !rul.matchesConditions(request) {
+			continue
+		}
+
+		if rul.MatchesMethod(request.Method) {
 			return rul, nil
 		}
+
+		if methodMismatch == nil {
+			methodMismatch = rul
+		}
 	}
 
-	if r.dr != nil {
-		return r.dr, nil
+	if methodMismatch == nil && r.dr != nil {
+		if r.dr.MatchesMethod(request.Method) {
+			return r.dr, nil
+		}
+
+		methodMismatch = r.dr
+	}
+
+	if methodMismatch != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrMethodNotAllowed,
+			"rule (id=%s, src=%s) doesn't match %s method",
+			methodMismatch.ID(), methodMismatch.SrcID(), request.Method)
 	}
 
 	return nil, errorchain.NewWithMessagef(heimdall.ErrNoRuleFound,
-		"no applicable rule found for %s", requestURL.String())
+		"no applicable rule found for %s", request.URL.String())
+}
+
+// candidates returns the rules, which URL patterns literal prefix matches the given URL, ordered
+// by their precedence. Since the URL is matched against the pattern of rules configured with
+// allow_encoded_slashes set to no_decode without decoding url-encoded slashes, candidates for
+// that form are looked up as well if the URL path contains url-encoded characters.
+func (r *repository) candidates(requestURL *url.URL) []*ruleImpl {
+	base := requestURL.Scheme + "://" + requestURL.Host
+	candidates := r.index.Find(base + requestURL.Path)
+
+	if len(requestURL.RawPath) != 0 {
+		if path := unescapePathKeepingSlashes(requestURL.RawPath); path != requestURL.Path {
+			for _, rul := range r.index.Find(base + path) {
+				if !slices.Contains(candidates, rul) {
+					candidates = append(candidates, rul)
+				}
+			}
+		}
+	}
+
+	// the tree returns the candidates ordered by the length of their literal prefix already.
+	// Sorting is only required to take the priorities into account, or to order the candidates
+	// retrieved for the partially decoded path.
+	slices.SortStableFunc(candidates, comparePrecedence)
+
+	return candidates
+}
+
+// comparePrecedence defines the order in which the rules are evaluated for a request. The rule
+// with the higher priority wins, followed by the rule with the longer literal URL prefix. Rules
+// having the same literal URL prefix are ordered as defined by compareRules.
+func comparePrecedence(a, b *ruleImpl) int {
+	if res := cmp.Compare(b.priority, a.priority); res != 0 {
+		return res
+	}
+
+	if res := cmp.Compare(len(b.urlPrefix), len(a.urlPrefix)); res != 0 {
+		return res
+	}
+
+	return compareRules(a, b)
+}
+
+// compareRules defines the precedence of rules having the same literal URL prefix. Apart from the
+// priority, the rule with more literal characters in its URL pattern wins, followed by the rule
+// with more matching conditions. Remaining ties are resolved by the id of the rule set and the id
+// of the rule to not depend on the order, the rules have been loaded in.
+func compareRules(a, b *ruleImpl) int {
+	if res := cmp.Compare(b.priority, a.priority); res != 0 {
+		return res
+	}
+
+	if res := cmp.Compare(b.specificity, a.specificity); res != 0 {
+		return res
+	}
+
+	if res := cmp.Compare(len(b.conditions), len(a.conditions)); res != 0 {
+		return res
+	}
+
+	if res := cmp.Compare(a.srcID, b.srcID); res != 0 {
+		return res
+	}
+
+	return cmp.Compare(a.id, b.id)
+}
+
+func (r *repository) Subscribe(observer rule.ConflictObserver) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.observers = append(r.observers, observer)
 }
 
 func (r *repository) Start(_ context.Context) error {
@@ -105,10 +215,17 @@ func (r *repository) watchRuleSetChanges() {
 			switch evt.ChangeType {
 			case event.Create:
 				r.addRuleSet(evt.Source, evt.Rules)
+				r.ruleSetLoaded(evt)
 			case event.Update:
 				r.updateRuleSet(evt.Source, evt.Rules)
+				r.ruleSetLoaded(evt)
 			case event.Remove:
 				r.deleteRuleSet(evt.Source)
+				r.ruleSetLoaded(evt)
+			case event.Failed:
+				r.ruleSetFailed(evt)
+			case event.Reload:
+				r.reloadRules(evt.Rules, evt.DefaultRule)
 			}
 		case <-r.quit:
 			r.logger.Info().Msg("Rule definition loader stopped")
@@ -122,11 +239,17 @@ func (r *repository) addRuleSet(srcID string, rules []rule.Rule) {
 	// create rules
 	r.logger.Info().Str("_src", srcID).Msg("Adding rule set")
 
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
+	snapshot := func() []rule.Rule {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
 
-	// add them
-	r.addRules(rules)
+		// add them
+		r.addRules(rules)
+
+		return slices.Clone(r.rules)
+	}()
+
+	r.notifyConflictsChanged(r.updateConflicts(snapshot))
 }
 
 func (r *repository) updateRuleSet(srcID string, rules []rule.Rule) {
@@ -189,7 +312,7 @@ func (r *repository) updateRuleSet(srcID string, rules []rule.Rule) {
 		return !present
 	})
 
-	func() {
+	snapshot := func() []rule.Rule {
 		r.mutex.Lock()
 		defer r.mutex.Unlock()
 
@@ -201,25 +324,65 @@ func (r *repository) updateRuleSet(srcID string, rules []rule.Rule) {
 
 		// add new rules
 		r.addRules(newRules)
+
+		return slices.Clone(r.rules)
 	}()
+
+	r.notifyConflictsChanged(r.updateConflicts(snapshot))
 }
 
 func (r *repository) deleteRuleSet(srcID string) {
 	r.logger.Info().Str("_src", srcID).Msg("Deleting rule set")
 
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
+	snapshot := func() []rule.Rule {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
 
-	// find all rules for the given src id
-	applicable := slicex.Filter(r.rules, func(r rule.Rule) bool { return r.SrcID() == srcID })
+		// find all rules for the given src id
+		applicable := slicex.Filter(r.rules, func(r rule.Rule) bool { return r.SrcID() == srcID })
+
+		// remove them
+		r.removeRules(applicable)
+
+		return slices.Clone(r.rules)
+	}()
 
-	// remove them
-	r.removeRules(applicable)
+	r.notifyConflictsChanged(r.updateConflicts(snapshot))
+}
+
+// reloadRules replaces all rules and the default rule in one step, so that no request is
+// matched against a mix of rules created from different heimdall configurations.
+func (r *repository) reloadRules(rules []rule.Rule, defaultRule rule.Rule) {
+	r.logger.Info().Msg("Replacing all rules")
+
+	snapshot := func() []rule.Rule {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+
+		for _, rul := range r.rules {
+			r.unindexRule(rul)
+			rul.(*ruleImpl).deactivate() // nolint: forcetypeassert
+		}
+
+		r.rules = nil
+		r.dr = defaultRule
+
+		r.addRules(rules)
+
+		return slices.Clone(r.rules)
+	}()
+
+	r.notifyConflictsChanged(r.updateConflicts(snapshot))
 }
 
 func (r *repository) addRules(rules []rule.Rule) {
 	for _, rul := range rules {
 		r.rules = append(r.rules, rul)
+		r.indexRule(rul)
+
+		if !r.static {
+			rul.(*ruleImpl).activate() // nolint: forcetypeassert
+		}
 
 		r.logger.Debug().Str("_src", rul.SrcID()).Str("_id", rul.ID()).Msg("Rule added")
 	}
@@ -233,6 +396,8 @@ func (r *repository) removeRules(rules []rule.Rule) {
 		for _, tbd := range rules {
 			if rul.ID() == tbd.ID() {
 				idxs = append(idxs, idx)
+				r.unindexRule(rul)
+				rul.(*ruleImpl).deactivate() // nolint: forcetypeassert
 
 				r.logger.Debug().Str("_src", rul.SrcID()).Str("_id", rul.ID()).Msg("Rule removed")
 			}
@@ -267,6 +432,10 @@ func (r *repository) replaceRules(rules []rule.Rule) {
 		for idx, existing := range r.rules {
 			if existing.ID() == updated.ID() {
 				r.rules[idx] = updated
+				r.unindexRule(existing)
+				r.indexRule(updated)
+				updated.(*ruleImpl).activate()    // nolint: forcetypeassert
+				existing.(*ruleImpl).deactivate() // nolint: forcetypeassert
 
 				r.logger.Debug().
 					Str("_src", existing.SrcID()).
@@ -278,3 +447,282 @@ func (r *repository) replaceRules(rules []rule.Rule) {
 		}
 	}
 }
+
+func (r *repository) indexRule(rul rule.Rule) {
+	impl := rul.(*ruleImpl) // nolint: forcetypeassert
+
+	r.index.Add(impl.urlPrefix, impl)
+}
+
+func (r *repository) unindexRule(rul rule.Rule) {
+	impl := rul.(*ruleImpl) // nolint: forcetypeassert
+
+	r.index.Delete(impl.urlPrefix, func(indexed *ruleImpl) bool { return indexed == impl })
+}
+
+// updateConflicts determines the conflicting rules, which are rules from different rule sets having
+// the same priority, at least one HTTP method in common and overlapping matchers, that is there might
+// be a request matched by both. For such rules, the precedence is not decided by priorities, but
+// implicitly by the specificity of the patterns or the ids of the rule sets. It returns the conflicts
+// of the rule sets, which changed compared to the previous invocation.
+//
+// The pairwise comparison is done on a snapshot of the rules without holding the repository lock,
+// so that matching of requests is not blocked while it runs. This is safe, as rules are immutable
+// and the conflicts are only accessed by the goroutine processing the rule set changes.
+func (r *repository) updateConflicts(rules []rule.Rule) map[string][]rule.Conflict {
+	groups := make(map[int][]*ruleImpl)
+
+	for _, rul := range rules {
+		impl := rul.(*ruleImpl) // nolint: forcetypeassert
+
+		groups[impl.priority] = append(groups[impl.priority], impl)
+	}
+
+	conflicts := make(map[string][]rule.Conflict)
+
+	for _, group := range groups {
+		for idx, first := range group {
+			for _, second := range group[idx+1:] {
+				if first.srcID == second.srcID || !first.mayOverlap(second) {
+					continue
+				}
+
+				conflicts[first.srcID] = append(conflicts[first.srcID], rule.Conflict{
+					RuleID: first.id, SrcID: first.srcID, ConflictingRuleID: second.id, ConflictingSrcID: second.srcID,
+				})
+				conflicts[second.srcID] = append(conflicts[second.srcID], rule.Conflict{
+					RuleID: second.id, SrcID: second.srcID, ConflictingRuleID: first.id, ConflictingSrcID: first.srcID,
+				})
+			}
+		}
+	}
+
+	changed := make(map[string][]rule.Conflict)
+
+	for srcID, srcConflicts := range conflicts {
+		slices.SortFunc(srcConflicts, compareConflicts)
+
+		if !slices.Equal(r.conflicts[srcID], srcConflicts) {
+			changed[srcID] = srcConflicts
+		}
+	}
+
+	for srcID := range r.conflicts {
+		if _, present := conflicts[srcID]; !present {
+			changed[srcID] = []rule.Conflict{}
+		}
+	}
+
+	r.conflicts = conflicts
+
+	return changed
+}
+
+func compareConflicts(a, b rule.Conflict) int {
+	if res := cmp.Compare(a.RuleID, b.RuleID); res != 0 {
+		return res
+	}
+
+	if res := cmp.Compare(a.ConflictingSrcID, b.ConflictingSrcID); res != 0 {
+		return res
+	}
+
+	return cmp.Compare(a.ConflictingRuleID, b.ConflictingRuleID)
+}
+
+func (r *repository) notifyConflictsChanged(changed map[string][]rule.Conflict) {
+	if len(changed) == 0 {
+		return
+	}
+
+	for srcID, conflicts := range changed {
+		for _, conflict := range conflicts {
+			// each conflict is reported for both rule sets. Log it only once
+			if conflict.SrcID > conflict.ConflictingSrcID {
+				continue
+			}
+
+			r.logger.Warn().
+				Str("_src", conflict.SrcID).
+				Str("_id", conflict.RuleID).
+				Str("_conflicting_src", conflict.ConflictingSrcID).
+				Str("_conflicting_id", conflict.ConflictingRuleID).
+				Str("_effective_src", min(conflict.SrcID, conflict.ConflictingSrcID)).
+				Msg("Rule conflicts with a rule from another rule set. Set a priority to resolve the conflict")
+		}
+
+		if len(conflicts) == 0 {
+			r.logger.Info().Str("_src", srcID).Msg("Rule conflicts resolved")
+		}
+	}
+
+	r.mutex.RLock()
+	observers := slices.Clone(r.observers)
+	r.mutex.RUnlock()
+
+	for _, observer := range observers {
+		for srcID, conflicts := range changed {
+			observer.OnConflictsChanged(srcID, conflicts)
+		}
+	}
+}
+
+type ruleSetState struct {
+	provider string
+	name     string
+	hash     []byte
+	loadedAt time.Time
+}
+
+type providerState struct {
+	lastSuccess     time.Time
+	lastError       string
+	lastErrorSource string
+	lastErrorAt     time.Time
+}
+
+// ruleSetLoaded records the successful processing of a rule set change for the Inspector.
+func (r *repository) ruleSetLoaded(evt event.RuleSetChanged) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now().UTC()
+
+	if evt.ChangeType == event.Remove {
+		delete(r.ruleSets, evt.Source)
+	} else {
+		r.ruleSets[evt.Source] = &ruleSetState{
+			provider: evt.Provider,
+			name:     evt.Name,
+			hash:     evt.Hash,
+			loadedAt: now,
+		}
+	}
+
+	r.providerState(evt.Provider).lastSuccess = now
+}
+
+// ruleSetFailed records the failure of a provider to retrieve or to process a rule set for the Inspector.
+func (r *repository) ruleSetFailed(evt event.RuleSetChanged) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	state := r.providerState(evt.Provider)
+	state.lastErrorSource = evt.Source
+	state.lastErrorAt = time.Now().UTC()
+
+	if evt.Err != nil {
+		state.lastError = evt.Err.Error()
+	}
+}
+
+func (r *repository) providerState(name string) *providerState {
+	state, ok := r.providers[name]
+	if !ok {
+		state = &providerState{}
+		r.providers[name] = state
+	}
+
+	return state
+}
+
+func (r *repository) RuleSets() []rule.SetInfo {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	infos := make([]rule.SetInfo, 0, len(r.ruleSets))
+
+	for srcID, state := range r.ruleSets {
+		ids := make([]string, 0)
+
+		for _, rul := range r.rules {
+			if rul.SrcID() == srcID {
+				ids = append(ids, rul.ID())
+			}
+		}
+
+		slices.Sort(ids)
+
+		infos = append(infos, rule.SetInfo{
+			Source:   srcID,
+			Provider: state.provider,
+			Name:     state.name,
+			Hash:     hex.EncodeToString(state.hash),
+			LoadedAt: state.loadedAt,
+			Rules:    ids,
+		})
+	}
+
+	slices.SortFunc(infos, func(a, b rule.SetInfo) int { return cmp.Compare(a.Source, b.Source) })
+
+	return infos
+}
+
+func (r *repository) Rules() []rule.Info {
+	return r.ruleInfos(func(_ rule.Rule) bool { return true })
+}
+
+func (r *repository) Rule(id, srcID string) []rule.Info {
+	return r.ruleInfos(func(rul rule.Rule) bool {
+		return rul.ID() == id && (len(srcID) == 0 || rul.SrcID() == srcID)
+	})
+}
+
+func (r *repository) ruleInfos(filter func(rul rule.Rule) bool) []rule.Info {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	infos := make([]rule.Info, 0)
+
+	for _, rul := range r.rules {
+		if filter(rul) {
+			infos = append(infos, rul.(*ruleImpl).info()) // nolint: forcetypeassert
+		}
+	}
+
+	slices.SortFunc(infos, func(a, b rule.Info) int {
+		if res := cmp.Compare(a.Source, b.Source); res != 0 {
+			return res
+		}
+
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return infos
+}
+
+func (r *repository) Providers() []rule.ProviderStatus {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	statuses := make([]rule.ProviderStatus, 0, len(r.providers))
+
+	for name, state := range r.providers {
+		status := rule.ProviderStatus{
+			Name:            name,
+			LastError:       state.lastError,
+			LastErrorSource: state.lastErrorSource,
+		}
+
+		for _, rs := range r.ruleSets {
+			if rs.provider == name {
+				status.RuleSets++
+			}
+		}
+
+		// copies are used to not expose the state to concurrent modifications
+		if lastSuccess := state.lastSuccess; !lastSuccess.IsZero() {
+			status.LastSuccess = &lastSuccess
+		}
+
+		if lastErrorAt := state.lastErrorAt; !lastErrorAt.IsZero() {
+			status.LastErrorAt = &lastErrorAt
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	slices.SortFunc(statuses, func(a, b rule.ProviderStatus) int { return cmp.Compare(a.Name, b.Name) })
+
+	return statuses
+}
@@ -18,6 +18,9 @@ package rules
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"net/url"
 	"testing"
 	"time"
@@ -25,16 +28,37 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/config"
 	"github.com/dadrus/heimdall/internal/rules/event"
+	rulemocks "github.com/dadrus/heimdall/internal/rules/mocks"
 	"github.com/dadrus/heimdall/internal/rules/patternmatcher"
 	"github.com/dadrus/heimdall/internal/rules/rule"
 	"github.com/dadrus/heimdall/internal/rules/rule/mocks"
 	"github.com/dadrus/heimdall/internal/x"
 )
 
+func newTestRule(t testing.TB, id, srcID, pattern string, methods ...string) *ruleImpl {
+	t.Helper()
+
+	matcher, err := patternmatcher.NewPatternMatcher("glob", pattern)
+	require.NoError(t, err)
+
+	return &ruleImpl{
+		id:          id,
+		srcID:       srcID,
+		matcher:     config.Matcher{URL: pattern, Strategy: "glob"},
+		urlMatcher:  matcher,
+		urlPrefix:   patternmatcher.LiteralPrefix(pattern),
+		specificity: patternmatcher.LiteralLength(pattern),
+		matcherHash: []byte(pattern),
+		methods:     x.IfThenElse(len(methods) != 0, methods, []string{http.MethodGet}),
+	}
+}
+
 func TestRepositoryAddAndRemoveRulesFromSameRuleSet(t *testing.T) {
 	t.Parallel()
 
@@ -64,6 +88,7 @@ func TestRepositoryFindRule(t *testing.T) {
 
 	for _, tc := range []struct {
 		uc               string
+		method           string
 		requestURL       *url.URL
 		addRules         func(t *testing.T, repo *repository)
 		configureFactory func(t *testing.T, factory *mocks.FactoryMock)
@@ -91,13 +116,17 @@ func TestRepositoryFindRule(t *testing.T) {
 				t.Helper()
 
 				factory.EXPECT().HasDefaultRule().Return(true)
-				factory.EXPECT().DefaultRule().Return(&ruleImpl{id: "test", isDefault: true})
+				factory.EXPECT().DefaultRule().Return(&ruleImpl{
+					id:        "test",
+					isDefault: true,
+					methods:   []string{http.MethodGet},
+				})
 			},
 			assert: func(t *testing.T, err error, rul rule.Rule) {
 				t.Helper()
 
 				require.NoError(t, err)
-				require.Equal(t, &ruleImpl{id: "test", isDefault: true}, rul)
+				require.Equal(t, &ruleImpl{id: "test", isDefault: true, methods: []string{http.MethodGet}}, rul)
 			},
 		},
 		{
@@ -111,10 +140,11 @@ func TestRepositoryFindRule(t *testing.T) {
 			addRules: func(t *testing.T, repo *repository) {
 				t.Helper()
 
-				repo.rules = append(repo.rules,
+				repo.addRules([]rule.Rule{
 					&ruleImpl{
-						id:    "test1",
-						srcID: "bar",
+						id:      "test1",
+						srcID:   "bar",
+						methods: []string{http.MethodGet},
 						urlMatcher: func() patternmatcher.PatternMatcher {
 							matcher, _ := patternmatcher.NewPatternMatcher("glob",
 								"http://heimdall.test.local/baz")
@@ -123,8 +153,9 @@ func TestRepositoryFindRule(t *testing.T) {
 						}(),
 					},
 					&ruleImpl{
-						id:    "test2",
-						srcID: "baz",
+						id:      "test2",
+						srcID:   "baz",
+						methods: []string{http.MethodGet},
 						urlMatcher: func() patternmatcher.PatternMatcher {
 							matcher, _ := patternmatcher.NewPatternMatcher("glob",
 								"http://foo.bar/baz")
@@ -132,7 +163,7 @@ func TestRepositoryFindRule(t *testing.T) {
 							return matcher
 						}(),
 					},
-				)
+				})
 			},
 			assert: func(t *testing.T, err error, rul rule.Rule) {
 				t.Helper()
@@ -146,6 +177,329 @@ func TestRepositoryFindRule(t *testing.T) {
 				require.Equal(t, "baz", impl.srcID)
 			},
 		},
+		{
+			uc:         "most specific rule wins regardless of the order rules were added in",
+			requestURL: &url.URL{Scheme: "http", Host: "foo.bar", Path: "/baz/qux"},
+			configureFactory: func(t *testing.T, factory *mocks.FactoryMock) {
+				t.Helper()
+
+				factory.EXPECT().HasDefaultRule().Return(false)
+			},
+			addRules: func(t *testing.T, repo *repository) {
+				t.Helper()
+
+				repo.addRules([]rule.Rule{
+					newTestRule(t, "test1", "bar", "http://foo.bar/<**>"),
+					newTestRule(t, "test2", "bar", "http://<*>/baz/qux"),
+					newTestRule(t, "test3", "bar", "http://foo.bar/baz/<**>"),
+				})
+			},
+			assert: func(t *testing.T, err error, rul rule.Rule) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "test3", rul.ID())
+			},
+		},
+		{
+			uc:         "rule with more literal characters wins for same literal prefix",
+			requestURL: &url.URL{Scheme: "http", Host: "foo.bar", Path: "/baz.json"},
+			configureFactory: func(t *testing.T, factory *mocks.FactoryMock) {
+				t.Helper()
+
+				factory.EXPECT().HasDefaultRule().Return(false)
+			},
+			addRules: func(t *testing.T, repo *repository) {
+				t.Helper()
+
+				repo.addRules([]rule.Rule{
+					newTestRule(t, "test1", "bar", "http://foo.bar/<**>"),
+					newTestRule(t, "test2", "bar", "http://foo.bar/<*>.json"),
+				})
+			},
+			assert: func(t *testing.T, err error, rul rule.Rule) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "test2", rul.ID())
+			},
+		},
+		{
+			uc:         "ties are resolved by rule set and rule id",
+			requestURL: &url.URL{Scheme: "http", Host: "foo.bar", Path: "/baz"},
+			configureFactory: func(t *testing.T, factory *mocks.FactoryMock) {
+				t.Helper()
+
+				factory.EXPECT().HasDefaultRule().Return(false)
+			},
+			addRules: func(t *testing.T, repo *repository) {
+				t.Helper()
+
+				repo.addRules([]rule.Rule{
+					newTestRule(t, "test2", "foo", "http://foo.bar/<**>"),
+					newTestRule(t, "test3", "bar", "http://foo.bar/<**>"),
+					newTestRule(t, "test1", "foo", "http://foo.bar/<**>"),
+					newTestRule(t, "test4", "bar", "http://foo.bar/<**>"),
+				})
+			},
+			assert: func(t *testing.T, err error, rul rule.Rule) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "test3", rul.ID())
+				assert.Equal(t, "bar", rul.SrcID())
+			},
+		},
+		{
+			uc:         "rule not decoding encoded slashes matches",
+			requestURL: &url.URL{Scheme: "http", Host: "foo.bar", Path: "/a/b/c", RawPath: "/a%2Fb/c"},
+			configureFactory: func(t *testing.T, factory *mocks.FactoryMock) {
+				t.Helper()
+
+				factory.EXPECT().HasDefaultRule().Return(false)
+			},
+			addRules: func(t *testing.T, repo *repository) {
+				t.Helper()
+
+				rul := newTestRule(t, "test1", "bar", "http://foo.bar/a%2Fb/<**>")
+				rul.encodedSlashesHandling = config.EncodedSlashesNoDecode
+
+				repo.addRules([]rule.Rule{
+					newTestRule(t, "test2", "bar", "http://foo.bar/<**>"),
+					rul,
+				})
+			},
+			assert: func(t *testing.T, err error, rul rule.Rule) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "test1", rul.ID())
+			},
+		},
+		{
+			uc:         "updated rule is found under its new pattern",
+			requestURL: &url.URL{Scheme: "http", Host: "foo.bar", Path: "/qux"},
+			configureFactory: func(t *testing.T, factory *mocks.FactoryMock) {
+				t.Helper()
+
+				factory.EXPECT().HasDefaultRule().Return(false)
+			},
+			addRules: func(t *testing.T, repo *repository) {
+				t.Helper()
+
+				repo.addRules([]rule.Rule{newTestRule(t, "test1", "bar", "http://foo.bar/baz")})
+				repo.replaceRules([]rule.Rule{newTestRule(t, "test1", "bar", "http://foo.bar/qux")})
+			},
+			assert: func(t *testing.T, err error, rul rule.Rule) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "test1", rul.ID())
+			},
+		},
+		{
+			uc:         "rule with satisfied matching conditions wins over rule without conditions",
+			requestURL: &url.URL{Scheme: "http", Host: "foo.bar", Path: "/api/baz", RawQuery: "version=v2"},
+			configureFactory: func(t *testing.T, factory *mocks.FactoryMock) {
+				t.Helper()
+
+				factory.EXPECT().HasDefaultRule().Return(false)
+			},
+			addRules: func(t *testing.T, repo *repository) {
+				t.Helper()
+
+				rul := newTestRule(t, "zzz", "bar", "http://foo.bar/api/<**>")
+				conditions, err := newRequestConditions(config.Matcher{
+					Strategy:    "glob",
+					QueryParams: map[string]string{"version": "v2"},
+				})
+				require.NoError(t, err)
+
+				rul.conditions = conditions
+
+				repo.addRules([]rule.Rule{newTestRule(t, "aaa", "bar", "http://foo.bar/api/<**>"), rul})
+			},
+			assert: func(t *testing.T, err error, rul rule.Rule) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "zzz", rul.ID())
+			},
+		},
+		{
+			uc:         "rule with not satisfied matching conditions is not selected",
+			requestURL: &url.URL{Scheme: "http", Host: "foo.bar", Path: "/api/baz", RawQuery: "version=v1"},
+			configureFactory: func(t *testing.T, factory *mocks.FactoryMock) {
+				t.Helper()
+
+				factory.EXPECT().HasDefaultRule().Return(false)
+			},
+			addRules: func(t *testing.T, repo *repository) {
+				t.Helper()
+
+				rul := newTestRule(t, "zzz", "bar", "http://foo.bar/api/baz")
+				conditions, err := newRequestConditions(config.Matcher{
+					Strategy:    "glob",
+					QueryParams: map[string]string{"version": "v2"},
+				})
+				require.NoError(t, err)
+
+				rul.conditions = conditions
+
+				repo.addRules([]rule.Rule{newTestRule(t, "aaa", "bar", "http://foo.bar/api/<**>"), rul})
+			},
+			assert: func(t *testing.T, err error, rul rule.Rule) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "aaa", rul.ID())
+			},
+		},
+		{
+			uc:         "rule with higher priority wins over more specific rule",
+			requestURL: &url.URL{Scheme: "http", Host: "foo.bar", Path: "/api/baz"},
+			configureFactory: func(t *testing.T, factory *mocks.FactoryMock) {
+				t.Helper()
+
+				factory.EXPECT().HasDefaultRule().Return(false)
+			},
+			addRules: func(t *testing.T, repo *repository) {
+				t.Helper()
+
+				rul := newTestRule(t, "generic", "foo", "http://<**>")
+				rul.priority = 10
+
+				repo.addRules([]rule.Rule{newTestRule(t, "specific", "bar", "http://foo.bar/api/baz"), rul})
+			},
+			assert: func(t *testing.T, err error, rul rule.Rule) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "generic", rul.ID())
+			},
+		},
+		{
+			uc:         "rule with higher priority wins over rule with same pattern",
+			requestURL: &url.URL{Scheme: "http", Host: "foo.bar", Path: "/api/baz"},
+			configureFactory: func(t *testing.T, factory *mocks.FactoryMock) {
+				t.Helper()
+
+				factory.EXPECT().HasDefaultRule().Return(false)
+			},
+			addRules: func(t *testing.T, repo *repository) {
+				t.Helper()
+
+				rul := newTestRule(t, "test2", "foo", "http://foo.bar/api/<**>")
+				rul.priority = 1
+
+				repo.addRules([]rule.Rule{newTestRule(t, "test1", "bar", "http://foo.bar/api/<**>"), rul})
+			},
+			assert: func(t *testing.T, err error, rul rule.Rule) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "test2", rul.ID())
+			},
+		},
+		{
+			uc:         "rules sharing the url pattern are selected by method",
+			method:     http.MethodPost,
+			requestURL: &url.URL{Scheme: "http", Host: "foo.bar", Path: "/api/baz"},
+			configureFactory: func(t *testing.T, factory *mocks.FactoryMock) {
+				t.Helper()
+
+				factory.EXPECT().HasDefaultRule().Return(false)
+			},
+			addRules: func(t *testing.T, repo *repository) {
+				t.Helper()
+
+				repo.addRules([]rule.Rule{
+					newTestRule(t, "read", "bar", "http://foo.bar/api/<**>", http.MethodGet, http.MethodHead),
+					newTestRule(t, "write", "bar", "http://foo.bar/api/<**>", http.MethodPost, http.MethodPut),
+				})
+			},
+			assert: func(t *testing.T, err error, rul rule.Rule) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "write", rul.ID())
+			},
+		},
+		{
+			uc:         "less specific rule matching the method wins over more specific one not matching it",
+			method:     http.MethodGet,
+			requestURL: &url.URL{Scheme: "http", Host: "foo.bar", Path: "/api/baz"},
+			configureFactory: func(t *testing.T, factory *mocks.FactoryMock) {
+				t.Helper()
+
+				factory.EXPECT().HasDefaultRule().Return(false)
+			},
+			addRules: func(t *testing.T, repo *repository) {
+				t.Helper()
+
+				repo.addRules([]rule.Rule{
+					newTestRule(t, "read", "bar", "http://foo.bar/api/<**>", http.MethodGet),
+					newTestRule(t, "write", "bar", "http://foo.bar/api/baz", http.MethodPost),
+				})
+			},
+			assert: func(t *testing.T, err error, rul rule.Rule) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "read", rul.ID())
+			},
+		},
+		{
+			uc:         "rules matching the url, but not the method, with default rule",
+			method:     http.MethodDelete,
+			requestURL: &url.URL{Scheme: "http", Host: "foo.bar", Path: "/api/baz"},
+			configureFactory: func(t *testing.T, factory *mocks.FactoryMock) {
+				t.Helper()
+
+				factory.EXPECT().HasDefaultRule().Return(true)
+				factory.EXPECT().DefaultRule().Return(&ruleImpl{
+					id:        "default",
+					isDefault: true,
+					methods:   []string{http.MethodDelete},
+				})
+			},
+			addRules: func(t *testing.T, repo *repository) {
+				t.Helper()
+
+				repo.addRules([]rule.Rule{
+					newTestRule(t, "read", "bar", "http://foo.bar/api/<**>", http.MethodGet),
+					newTestRule(t, "write", "bar", "http://foo.bar/api/baz", http.MethodPost),
+				})
+			},
+			assert: func(t *testing.T, err error, _ rule.Rule) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrMethodNotAllowed)
+				assert.Contains(t, err.Error(), "write")
+			},
+		},
+		{
+			uc:         "no matching rule with default rule not matching the method",
+			method:     http.MethodPost,
+			requestURL: &url.URL{Scheme: "http", Host: "foo.bar", Path: "/baz"},
+			configureFactory: func(t *testing.T, factory *mocks.FactoryMock) {
+				t.Helper()
+
+				factory.EXPECT().HasDefaultRule().Return(true)
+				factory.EXPECT().DefaultRule().Return(&ruleImpl{
+					id:        "default",
+					isDefault: true,
+					methods:   []string{http.MethodGet},
+				})
+			},
+			assert: func(t *testing.T, err error, _ rule.Rule) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrMethodNotAllowed)
+			},
+		},
 	} {
 		t.Run("case="+tc.uc, func(t *testing.T) {
 			// GIVEN
@@ -161,7 +515,10 @@ func TestRepositoryFindRule(t *testing.T) {
 			addRules(t, repo)
 
 			// WHEN
-			rul, err := repo.FindRule(tc.requestURL)
+			rul, err := repo.FindRule(&heimdall.Request{
+				Method: x.IfThenElse(len(tc.method) != 0, tc.method, http.MethodGet),
+				URL:    &heimdall.URL{URL: *tc.requestURL},
+			})
 
 			// THEN
 			tc.assert(t, err, rul)
@@ -169,6 +526,97 @@ func TestRepositoryFindRule(t *testing.T) {
 	}
 }
 
+func BenchmarkRepositoryFindRule(b *testing.B) {
+	for _, count := range []int{10, 100, 1000, 10000} {
+		b.Run(fmt.Sprintf("rules=%d", count), func(b *testing.B) {
+			repo := newRepository(nil, &ruleFactory{}, zerolog.Nop())
+
+			rules := make([]rule.Rule, count)
+			for idx := range rules {
+				rules[idx] = newTestRule(b, fmt.Sprintf("rule%d", idx), "bench",
+					fmt.Sprintf("http://foo.bar/api/v1/resource%d/<**>", idx))
+			}
+
+			repo.addRules(rules)
+
+			request := &heimdall.Request{
+				Method: http.MethodGet,
+				URL: &heimdall.URL{URL: url.URL{
+					Scheme: "http",
+					Host:   "foo.bar",
+					Path:   fmt.Sprintf("/api/v1/resource%d/baz", count/2),
+				}},
+			}
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.FindRule(request); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestRepositoryConflictDetection(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	observer := mocks.NewConflictObserverMock(t)
+
+	repo := newRepository(nil, &ruleFactory{}, *zerolog.Ctx(context.Background()))
+	repo.Subscribe(observer)
+
+	withPriority := func(rul *ruleImpl, priority int) *ruleImpl {
+		rul.priority = priority
+
+		return rul
+	}
+
+	// WHEN
+	repo.addRuleSet("foo", []rule.Rule{
+		newTestRule(t, "rule1", "foo", "http://foo.bar/<**>", http.MethodGet, http.MethodPost),
+		newTestRule(t, "rule2", "foo", "http://foo.bar/baz", http.MethodGet),
+		newTestRule(t, "rule3", "foo", "http://foo.bar/qux", http.MethodGet),
+	})
+
+	// THEN
+	observer.AssertNotCalled(t, "OnConflictsChanged", mock.Anything, mock.Anything)
+
+	// GIVEN
+	observer.EXPECT().OnConflictsChanged("foo", []rule.Conflict{
+		{RuleID: "rule1", SrcID: "foo", ConflictingRuleID: "rule1", ConflictingSrcID: "bar"},
+		{RuleID: "rule1", SrcID: "foo", ConflictingRuleID: "rule2", ConflictingSrcID: "bar"},
+	}).Once()
+	observer.EXPECT().OnConflictsChanged("bar", []rule.Conflict{
+		{RuleID: "rule1", SrcID: "bar", ConflictingRuleID: "rule1", ConflictingSrcID: "foo"},
+		{RuleID: "rule2", SrcID: "bar", ConflictingRuleID: "rule1", ConflictingSrcID: "foo"},
+	}).Once()
+
+	// WHEN
+	repo.addRuleSet("bar", []rule.Rule{
+		// same pattern and overlapping methods
+		newTestRule(t, "rule1", "bar", "http://foo.bar/<**>", http.MethodPost),
+		// same pattern, but no overlapping methods. Overlaps however with the wildcard of foo's rule1
+		newTestRule(t, "rule2", "bar", "http://foo.bar/baz", http.MethodPost),
+		// same pattern and methods, but different priority
+		withPriority(newTestRule(t, "rule3", "bar", "http://foo.bar/qux", http.MethodGet), 1),
+		// same methods, but not overlapping patterns
+		newTestRule(t, "rule4", "bar", "http://bar.foo/<**>", http.MethodGet),
+	})
+
+	// GIVEN
+	observer.EXPECT().OnConflictsChanged("foo", []rule.Conflict{}).Once()
+	observer.EXPECT().OnConflictsChanged("bar", []rule.Conflict{}).Once()
+
+	// WHEN
+	repo.deleteRuleSet("bar")
+
+	// THEN
+	assert.Empty(t, repo.conflicts)
+}
+
 func TestRepositoryAddAndRemoveRulesFromDifferentRuleSets(t *testing.T) {
 	t.Parallel()
 
@@ -333,6 +781,43 @@ func TestRepositoryRuleSetLifecycleManagement(t *testing.T) {
 				assert.Equal(t, &ruleImpl{id: "rule:foo4", srcID: "test2", hash: []byte{4}}, repo.rules[3])
 			},
 		},
+		{
+			uc: "multiple rule sets created and all rules replaced due to reloaded configuration",
+			events: []event.RuleSetChanged{
+				{
+					Source:     "test1",
+					ChangeType: event.Create,
+					Rules:      []rule.Rule{&ruleImpl{id: "rule:bar", srcID: "test1", urlPrefix: "http://bar"}},
+				},
+				{
+					Source:     "test2",
+					ChangeType: event.Create,
+					Rules:      []rule.Rule{&ruleImpl{id: "rule:foo", srcID: "test2", urlPrefix: "http://foo"}},
+				},
+				{
+					ChangeType: event.Reload,
+					Rules: []rule.Rule{
+						&ruleImpl{id: "rule:bar", srcID: "test1", urlPrefix: "http://bar", hash: []byte{1}},
+						&ruleImpl{id: "rule:foo", srcID: "test2", urlPrefix: "http://foo", hash: []byte{2}},
+					},
+					DefaultRule: &ruleImpl{id: "default", srcID: "config", isDefault: true},
+				},
+			},
+			assert: func(t *testing.T, repo *repository) {
+				t.Helper()
+
+				require.Len(t, repo.rules, 2)
+				assert.Equal(t,
+					&ruleImpl{id: "rule:bar", srcID: "test1", urlPrefix: "http://bar", hash: []byte{1}}, repo.rules[0])
+				assert.Equal(t,
+					&ruleImpl{id: "rule:foo", srcID: "test2", urlPrefix: "http://foo", hash: []byte{2}}, repo.rules[1])
+				assert.Equal(t, &ruleImpl{id: "default", srcID: "config", isDefault: true}, repo.dr)
+
+				candidates := repo.index.Find("http://foo")
+				require.Len(t, candidates, 1)
+				assert.Equal(t, []byte{2}, candidates[0].hash)
+			},
+		},
 	} {
 		t.Run("case="+tc.uc, func(t *testing.T) {
 			// GIVEN
@@ -358,3 +843,126 @@ func TestRepositoryRuleSetLifecycleManagement(t *testing.T) {
 		})
 	}
 }
+
+func TestRepositoryInspection(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	ctx := context.Background()
+	loadedAt := time.Now().UTC()
+
+	queue := make(event.RuleSetChangedEventQueue, 10)
+	defer close(queue)
+
+	repo := newRepository(queue, &ruleFactory{}, log.Logger)
+	require.NoError(t, repo.Start(ctx))
+
+	defer repo.Stop(ctx)
+
+	authenticator := rulemocks.NewSubjectCreatorMock(t)
+	authenticator.EXPECT().ID().Return("jwt")
+
+	handler := rulemocks.NewSubjectHandlerMock(t)
+	handler.EXPECT().ID().Return("allow_all")
+
+	rul := &ruleImpl{
+		id:       "rule:foo",
+		srcID:    "test1",
+		matcher:  config.Matcher{URL: "http://foo.bar/<**>", Strategy: "glob"},
+		methods:  []string{http.MethodGet},
+		priority: 2,
+		hash:     []byte{1, 2},
+		sc:       compositeSubjectCreator{authenticator},
+		sh:       compositeSubjectHandler{handler},
+		loadedAt: loadedAt,
+	}
+
+	// WHEN
+	queue <- event.RuleSetChanged{
+		Source:     "test1",
+		Provider:   "file_system",
+		Name:       "foo",
+		Hash:       []byte{3, 4},
+		ChangeType: event.Create,
+		Rules:      []rule.Rule{rul, &ruleImpl{id: "rule:bar", srcID: "test1"}},
+	}
+	queue <- event.RuleSetChanged{
+		Source:     "test2",
+		Provider:   "file_system",
+		Name:       "bar",
+		ChangeType: event.Create,
+		Rules:      []rule.Rule{&ruleImpl{id: "rule:foo", srcID: "test2"}},
+	}
+	queue <- event.RuleSetChanged{
+		Source:     "test3",
+		Provider:   "http_endpoint",
+		ChangeType: event.Failed,
+		Err:        errors.New("test error"),
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	// THEN
+	ruleSets := repo.RuleSets()
+	require.Len(t, ruleSets, 2)
+	assert.Equal(t, "test1", ruleSets[0].Source)
+	assert.Equal(t, "file_system", ruleSets[0].Provider)
+	assert.Equal(t, "foo", ruleSets[0].Name)
+	assert.Equal(t, "0304", ruleSets[0].Hash)
+	assert.False(t, ruleSets[0].LoadedAt.IsZero())
+	assert.Equal(t, []string{"rule:bar", "rule:foo"}, ruleSets[0].Rules)
+	assert.Equal(t, "test2", ruleSets[1].Source)
+	assert.Equal(t, []string{"rule:foo"}, ruleSets[1].Rules)
+
+	rules := repo.Rules()
+	require.Len(t, rules, 3)
+	assert.Equal(t, "rule:bar", rules[0].ID)
+	assert.Equal(t, "rule:foo", rules[1].ID)
+	assert.Equal(t, "test1", rules[1].Source)
+	assert.Equal(t, "rule:foo", rules[2].ID)
+	assert.Equal(t, "test2", rules[2].Source)
+
+	assert.Len(t, repo.Rule("rule:foo", ""), 2)
+	assert.Empty(t, repo.Rule("rule:baz", ""))
+
+	found := repo.Rule("rule:foo", "test1")
+	require.Len(t, found, 1)
+	assert.Equal(t, rule.Info{
+		ID:       "rule:foo",
+		Source:   "test1",
+		Match:    config.Matcher{URL: "http://foo.bar/<**>", Strategy: "glob"},
+		Methods:  []string{http.MethodGet},
+		Priority: 2,
+		Mechanisms: rule.MechanismsInfo{
+			Authenticators: []string{"jwt"},
+			Handlers:       []string{"allow_all"},
+			Finalizers:     []string{},
+			ErrorHandlers:  []string{},
+		},
+		Hash:     "0102",
+		LoadedAt: loadedAt,
+	}, found[0])
+
+	providers := repo.Providers()
+	require.Len(t, providers, 2)
+	assert.Equal(t, "file_system", providers[0].Name)
+	assert.Equal(t, 2, providers[0].RuleSets)
+	assert.NotNil(t, providers[0].LastSuccess)
+	assert.Empty(t, providers[0].LastError)
+	assert.Nil(t, providers[0].LastErrorAt)
+	assert.Equal(t, "http_endpoint", providers[1].Name)
+	assert.Zero(t, providers[1].RuleSets)
+	assert.Nil(t, providers[1].LastSuccess)
+	assert.Equal(t, "test error", providers[1].LastError)
+	assert.Equal(t, "test3", providers[1].LastErrorSource)
+	assert.NotNil(t, providers[1].LastErrorAt)
+
+	// WHEN
+	queue <- event.RuleSetChanged{Source: "test2", Provider: "file_system", ChangeType: event.Remove}
+
+	time.Sleep(100 * time.Millisecond)
+
+	// THEN
+	require.Len(t, repo.RuleSets(), 1)
+	assert.Equal(t, 1, repo.Providers()[0].RuleSets)
+}
@@ -0,0 +1,222 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/rules/patternmatcher"
+)
+
+var errInvalidIPAddress = errors.New("invalid ip address")
+
+type requestCondition interface {
+	Matches(req *heimdall.Request) bool
+}
+
+type compositeRequestCondition []requestCondition
+
+func (cc compositeRequestCondition) Matches(req *heimdall.Request) bool {
+	for _, condition := range cc {
+		if !condition.Matches(req) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type hostCondition struct {
+	pattern string
+	matcher patternmatcher.PatternMatcher
+}
+
+func (c hostCondition) Matches(req *heimdall.Request) bool {
+	return c.matcher.Match(req.URL.Hostname())
+}
+
+type headerCondition struct {
+	name    string
+	pattern string
+	matcher patternmatcher.PatternMatcher
+}
+
+func (c headerCondition) Matches(req *heimdall.Request) bool {
+	value := req.Header(c.name)
+
+	return len(value) != 0 && c.matcher.Match(value)
+}
+
+type queryParamCondition struct {
+	name    string
+	pattern string
+	matcher patternmatcher.PatternMatcher
+}
+
+func (c queryParamCondition) Matches(req *heimdall.Request) bool {
+	for _, value := range req.URL.Query()[c.name] {
+		if c.matcher.Match(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type clientIPCondition struct {
+	networks []*net.IPNet
+}
+
+func (c clientIPCondition) Matches(req *heimdall.Request) bool {
+	if len(req.ClientIPAddresses) == 0 {
+		return false
+	}
+
+	// the first entry is the address of the client, which initiated the request
+	ip := net.ParseIP(req.ClientIPAddresses[0])
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range c.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mayOverlap reports whether there might be a request satisfying both composite conditions.
+// Conditions defined only by one of them do not restrict the overlap, as the other one accepts
+// any value for them. Query parameter conditions never prevent an overlap, as a query parameter
+// can be present multiple times with different values.
+func (cc compositeRequestCondition) mayOverlap(other compositeRequestCondition) bool {
+	for _, first := range cc {
+		for _, second := range other {
+			if !conditionsMayOverlap(first, second) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func conditionsMayOverlap(first, second requestCondition) bool {
+	switch cond := first.(type) {
+	case hostCondition:
+		if other, ok := second.(hostCondition); ok {
+			return patternmatcher.MayOverlap(cond.pattern, cond.matcher, other.pattern, other.matcher)
+		}
+	case headerCondition:
+		if other, ok := second.(headerCondition); ok && strings.EqualFold(cond.name, other.name) {
+			return patternmatcher.MayOverlap(cond.pattern, cond.matcher, other.pattern, other.matcher)
+		}
+	case clientIPCondition:
+		if other, ok := second.(clientIPCondition); ok {
+			for _, network := range cond.networks {
+				for _, otherNetwork := range other.networks {
+					if network.Contains(otherNetwork.IP) || otherNetwork.Contains(network.IP) {
+						return true
+					}
+				}
+			}
+
+			return false
+		}
+	}
+
+	return true
+}
+
+func newRequestConditions(matcher config.Matcher) (compositeRequestCondition, error) {
+	var conditions compositeRequestCondition
+
+	if len(matcher.Host) != 0 {
+		hm, err := patternmatcher.NewPatternMatcher(matcher.Strategy, matcher.Host)
+		if err != nil {
+			return nil, fmt.Errorf("bad host pattern: %w", err)
+		}
+
+		conditions = append(conditions, hostCondition{pattern: matcher.Host, matcher: hm})
+	}
+
+	for name, pattern := range matcher.Headers {
+		hm, err := patternmatcher.NewPatternMatcher(matcher.Strategy, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("bad pattern for %s header: %w", name, err)
+		}
+
+		conditions = append(conditions, headerCondition{name: name, pattern: pattern, matcher: hm})
+	}
+
+	for name, pattern := range matcher.QueryParams {
+		qm, err := patternmatcher.NewPatternMatcher(matcher.Strategy, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("bad pattern for %s query parameter: %w", name, err)
+		}
+
+		conditions = append(conditions, queryParamCondition{name: name, pattern: pattern, matcher: qm})
+	}
+
+	if len(matcher.ClientIPs) != 0 {
+		networks := make([]*net.IPNet, len(matcher.ClientIPs))
+
+		for idx, value := range matcher.ClientIPs {
+			network, err := parseNetwork(value)
+			if err != nil {
+				return nil, err
+			}
+
+			networks[idx] = network
+		}
+
+		conditions = append(conditions, clientIPCondition{networks: networks})
+	}
+
+	return conditions, nil
+}
+
+func parseNetwork(value string) (*net.IPNet, error) {
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidIPAddress, value)
+		}
+
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			ip = ip.To4()
+			bits = 8 * net.IPv4len
+		}
+
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidIPAddress, err)
+	}
+
+	return network, nil
+}
@@ -0,0 +1,303 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/heimdall/mocks"
+	"github.com/dadrus/heimdall/internal/rules/config"
+)
+
+func TestNewRequestConditions(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc      string
+		matcher config.Matcher
+		assert  func(t *testing.T, err error, conditions compositeRequestCondition)
+	}{
+		{
+			uc:      "without any conditions",
+			matcher: config.Matcher{URL: "http://foo.bar", Strategy: "glob"},
+			assert: func(t *testing.T, err error, conditions compositeRequestCondition) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Empty(t, conditions)
+			},
+		},
+		{
+			uc:      "with bad host pattern",
+			matcher: config.Matcher{Strategy: "glob", Host: "<foo"},
+			assert: func(t *testing.T, err error, _ compositeRequestCondition) {
+				t.Helper()
+
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "bad host pattern")
+			},
+		},
+		{
+			uc:      "with bad header pattern",
+			matcher: config.Matcher{Strategy: "regex", Headers: map[string]string{"X-Foo": "<(foo>"}},
+			assert: func(t *testing.T, err error, _ compositeRequestCondition) {
+				t.Helper()
+
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "X-Foo header")
+			},
+		},
+		{
+			uc:      "with bad query parameter pattern",
+			matcher: config.Matcher{Strategy: "glob", QueryParams: map[string]string{"foo": ""}},
+			assert: func(t *testing.T, err error, _ compositeRequestCondition) {
+				t.Helper()
+
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "foo query parameter")
+			},
+		},
+		{
+			uc:      "with bad client ip",
+			matcher: config.Matcher{Strategy: "glob", ClientIPs: []string{"10.0.0.0/8", "10.1.1"}},
+			assert: func(t *testing.T, err error, _ compositeRequestCondition) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, errInvalidIPAddress)
+			},
+		},
+		{
+			uc:      "with bad client cidr",
+			matcher: config.Matcher{Strategy: "glob", ClientIPs: []string{"10.0.0.0/33"}},
+			assert: func(t *testing.T, err error, _ compositeRequestCondition) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, errInvalidIPAddress)
+			},
+		},
+		{
+			uc: "with all conditions",
+			matcher: config.Matcher{
+				Strategy:    "glob",
+				Host:        "<*>.example.com",
+				Headers:     map[string]string{"X-Foo": "bar", "X-Bar": "<*>"},
+				QueryParams: map[string]string{"foo": "bar"},
+				ClientIPs:   []string{"10.0.0.0/8", "2001:db8::1"},
+			},
+			assert: func(t *testing.T, err error, conditions compositeRequestCondition) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Len(t, conditions, 5)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// WHEN
+			conditions, err := newRequestConditions(tc.matcher)
+
+			// THEN
+			tc.assert(t, err, conditions)
+		})
+	}
+}
+
+func TestRequestConditionsMatches(t *testing.T) {
+	t.Parallel()
+
+	conditions, err := newRequestConditions(config.Matcher{
+		Strategy:    "glob",
+		Host:        "<*>.example.com",
+		Headers:     map[string]string{"X-Api-Version": "<{v2,v3}>"},
+		QueryParams: map[string]string{"tenant": "foo"},
+		ClientIPs:   []string{"10.0.0.0/8", "192.168.1.1"},
+	})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		uc        string
+		url       string
+		header    string
+		clientIPs []string
+		matches   bool
+	}{
+		{
+			uc:        "all conditions are satisfied",
+			url:       "http://api.example.com:8080/foo?tenant=bar&tenant=foo",
+			header:    "v2",
+			clientIPs: []string{"10.1.2.3", "172.16.0.1"},
+			matches:   true,
+		},
+		{
+			uc:        "single client ip address matches",
+			url:       "http://api.example.com/foo?tenant=foo",
+			header:    "v3",
+			clientIPs: []string{"192.168.1.1"},
+			matches:   true,
+		},
+		{
+			uc:        "host does not match",
+			url:       "http://api.foo.example.com/foo?tenant=foo",
+			header:    "v2",
+			clientIPs: []string{"10.1.2.3"},
+		},
+		{
+			uc:        "header does not match",
+			url:       "http://api.example.com/foo?tenant=foo",
+			header:    "v1",
+			clientIPs: []string{"10.1.2.3"},
+		},
+		{
+			uc:        "header is missing",
+			url:       "http://api.example.com/foo?tenant=foo",
+			clientIPs: []string{"10.1.2.3"},
+		},
+		{
+			uc:        "query parameter does not match",
+			url:       "http://api.example.com/foo?tenant=bar",
+			header:    "v2",
+			clientIPs: []string{"10.1.2.3"},
+		},
+		{
+			uc:        "query parameter is missing",
+			url:       "http://api.example.com/foo",
+			header:    "v2",
+			clientIPs: []string{"10.1.2.3"},
+		},
+		{
+			uc:        "client ip address does not match",
+			url:       "http://api.example.com/foo?tenant=foo",
+			header:    "v2",
+			clientIPs: []string{"172.16.0.1", "10.1.2.3"},
+		},
+		{
+			uc:        "client ip address is not parseable",
+			url:       "http://api.example.com/foo?tenant=foo",
+			header:    "v2",
+			clientIPs: []string{"unknown"},
+		},
+		{
+			uc:     "client ip address is unknown",
+			url:    "http://api.example.com/foo?tenant=foo",
+			header: "v2",
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			reqURL, err := url.Parse(tc.url)
+			require.NoError(t, err)
+
+			fnt := mocks.NewRequestFunctionsMock(t)
+			fnt.EXPECT().Header("X-Api-Version").Return(tc.header).Maybe()
+
+			req := &heimdall.Request{RequestFunctions: fnt, URL: &heimdall.URL{URL: *reqURL}, ClientIPAddresses: tc.clientIPs}
+
+			// WHEN
+			matches := conditions.Matches(req)
+
+			// THEN
+			assert.Equal(t, tc.matches, matches)
+		})
+	}
+}
+
+func TestRequestConditionsMayOverlap(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc      string
+		first   config.Matcher
+		second  config.Matcher
+		overlap bool
+	}{
+		{
+			uc:      "without any conditions",
+			overlap: true,
+		},
+		{
+			uc:      "conditions defined by one side only",
+			first:   config.Matcher{Host: "foo.example.com", Headers: map[string]string{"X-Api-Version": "v1"}},
+			overlap: true,
+		},
+		{
+			uc:     "different literal hosts",
+			first:  config.Matcher{Host: "foo.example.com"},
+			second: config.Matcher{Host: "bar.example.com"},
+		},
+		{
+			uc:      "host matched by the other host pattern",
+			first:   config.Matcher{Host: "foo.example.com"},
+			second:  config.Matcher{Host: "<*>.example.com"},
+			overlap: true,
+		},
+		{
+			uc:     "different values of the same header",
+			first:  config.Matcher{Headers: map[string]string{"X-Api-Version": "v1"}},
+			second: config.Matcher{Headers: map[string]string{"x-api-version": "v2"}},
+		},
+		{
+			uc:      "different headers",
+			first:   config.Matcher{Headers: map[string]string{"X-Api-Version": "v1"}},
+			second:  config.Matcher{Headers: map[string]string{"X-Tenant": "foo"}},
+			overlap: true,
+		},
+		{
+			uc:      "different values of the same query parameter",
+			first:   config.Matcher{QueryParams: map[string]string{"version": "v1"}},
+			second:  config.Matcher{QueryParams: map[string]string{"version": "v2"}},
+			overlap: true,
+		},
+		{
+			uc:     "disjoint client networks",
+			first:  config.Matcher{ClientIPs: []string{"10.0.0.0/8"}},
+			second: config.Matcher{ClientIPs: []string{"192.168.0.0/16", "172.16.0.1"}},
+		},
+		{
+			uc:      "nested client networks",
+			first:   config.Matcher{ClientIPs: []string{"10.0.0.0/8"}},
+			second:  config.Matcher{ClientIPs: []string{"10.1.2.3"}},
+			overlap: true,
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			tc.first.Strategy = "glob"
+			tc.second.Strategy = "glob"
+
+			first, err := newRequestConditions(tc.first)
+			require.NoError(t, err)
+
+			second, err := newRequestConditions(tc.second)
+			require.NoError(t, err)
+
+			// WHEN
+			overlap := first.mayOverlap(second)
+			reversed := second.mayOverlap(first)
+
+			// THEN
+			assert.Equal(t, tc.overlap, overlap)
+			assert.Equal(t, tc.overlap, reversed)
+		})
+	}
+}
@@ -17,11 +17,24 @@
 package rule
 
 import (
+	"net/http"
 	"net/url"
+
+	"github.com/dadrus/heimdall/internal/rules/config"
 )
 
 //go:generate mockery --name Backend --structname BackendMock
 
 type Backend interface {
 	URL() *url.URL
+	TLS() *config.BackendTLS
+	// RewriteRequestHeaders applies the configured modifications to the headers
+	// of the request forwarded to the backend.
+	RewriteRequestHeaders(header http.Header)
+	// RewriteResponseHeaders applies the configured modifications to the headers
+	// of the response received from the backend.
+	RewriteResponseHeaders(header http.Header)
+	// Done must be called after the request has been forwarded to the backend
+	// with the error, if any, which happened while communicating with it.
+	Done(err error)
 }
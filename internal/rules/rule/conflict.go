@@ -0,0 +1,41 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rule
+
+// Conflict describes a rule, which matches the same requests as a rule loaded from a different
+// rule set and has the same priority. Which of both rules is used is then only defined by the ids
+// of the rule sets.
+type Conflict struct {
+	RuleID            string
+	SrcID             string
+	ConflictingRuleID string
+	ConflictingSrcID  string
+}
+
+//go:generate mockery --name ConflictObserver --structname ConflictObserverMock
+
+type ConflictObserver interface {
+	// OnConflictsChanged is called whenever the conflicts of the rules from the rule set
+	// with the given id change. An empty conflicts slice means, all conflicts have been resolved.
+	OnConflictsChanged(srcID string, conflicts []Conflict)
+}
+
+//go:generate mockery --name ConflictNotifier --structname ConflictNotifierMock
+
+type ConflictNotifier interface {
+	Subscribe(observer ConflictObserver)
+}
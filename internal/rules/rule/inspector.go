@@ -0,0 +1,75 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rule
+
+import (
+	"time"
+
+	"github.com/dadrus/heimdall/internal/rules/config"
+)
+
+// SetInfo describes a rule set currently loaded.
+type SetInfo struct {
+	Source   string    `json:"source"`
+	Provider string    `json:"provider"`
+	Name     string    `json:"name,omitempty"`
+	Hash     string    `json:"hash,omitempty"`
+	LoadedAt time.Time `json:"loaded_at"`
+	Rules    []string  `json:"rules"`
+}
+
+// MechanismsInfo lists the ids of the mechanisms referenced by a rule.
+type MechanismsInfo struct {
+	Authenticators []string `json:"authenticators"`
+	Handlers       []string `json:"handlers,omitempty"`
+	Finalizers     []string `json:"finalizers,omitempty"`
+	ErrorHandlers  []string `json:"error_handlers,omitempty"`
+}
+
+// Info describes a rule currently loaded.
+type Info struct {
+	ID         string         `json:"id"`
+	Source     string         `json:"source"`
+	Match      config.Matcher `json:"match"`
+	Methods    []string       `json:"methods"`
+	Priority   int            `json:"priority,omitempty"`
+	Mechanisms MechanismsInfo `json:"mechanisms"`
+	Hash       string         `json:"hash"`
+	LoadedAt   time.Time      `json:"loaded_at"`
+}
+
+// ProviderStatus describes the state of a rule provider as observed by the repository.
+type ProviderStatus struct {
+	Name            string     `json:"name"`
+	RuleSets        int        `json:"rule_sets"`
+	LastSuccess     *time.Time `json:"last_success,omitempty"`
+	LastError       string     `json:"last_error,omitempty"`
+	LastErrorSource string     `json:"last_error_source,omitempty"`
+	LastErrorAt     *time.Time `json:"last_error_at,omitempty"`
+}
+
+//go:generate mockery --name Inspector --structname InspectorMock
+
+// Inspector provides read-only access to the state of the rule repository.
+type Inspector interface {
+	RuleSets() []SetInfo
+	Rules() []Info
+	// Rule returns the rules having the given id. Since rule ids are only unique within
+	// a rule set, multiple rules can be returned if srcID is empty.
+	Rule(id, srcID string) []Info
+	Providers() []ProviderStatus
+}
@@ -3,6 +3,9 @@
 package mocks
 
 import (
+	config "github.com/dadrus/heimdall/internal/rules/config"
+	http "net/http"
+
 	mock "github.com/stretchr/testify/mock"
 
 	url "net/url"
@@ -21,6 +24,148 @@ func (_m *BackendMock) EXPECT() *BackendMock_Expecter {
 	return &BackendMock_Expecter{mock: &_m.Mock}
 }
 
+// Done provides a mock function with given fields: err
+func (_m *BackendMock) Done(err error) {
+	_m.Called(err)
+}
+
+// BackendMock_Done_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Done'
+type BackendMock_Done_Call struct {
+	*mock.Call
+}
+
+// Done is a helper method to define mock.On call
+//   - err error
+func (_e *BackendMock_Expecter) Done(err interface{}) *BackendMock_Done_Call {
+	return &BackendMock_Done_Call{Call: _e.mock.On("Done", err)}
+}
+
+func (_c *BackendMock_Done_Call) Run(run func(err error)) *BackendMock_Done_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(error))
+	})
+	return _c
+}
+
+func (_c *BackendMock_Done_Call) Return() *BackendMock_Done_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *BackendMock_Done_Call) RunAndReturn(run func(error)) *BackendMock_Done_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RewriteRequestHeaders provides a mock function with given fields: header
+func (_m *BackendMock) RewriteRequestHeaders(header http.Header) {
+	_m.Called(header)
+}
+
+// BackendMock_RewriteRequestHeaders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RewriteRequestHeaders'
+type BackendMock_RewriteRequestHeaders_Call struct {
+	*mock.Call
+}
+
+// RewriteRequestHeaders is a helper method to define mock.On call
+//   - header http.Header
+func (_e *BackendMock_Expecter) RewriteRequestHeaders(header interface{}) *BackendMock_RewriteRequestHeaders_Call {
+	return &BackendMock_RewriteRequestHeaders_Call{Call: _e.mock.On("RewriteRequestHeaders", header)}
+}
+
+func (_c *BackendMock_RewriteRequestHeaders_Call) Run(run func(header http.Header)) *BackendMock_RewriteRequestHeaders_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(http.Header))
+	})
+	return _c
+}
+
+func (_c *BackendMock_RewriteRequestHeaders_Call) Return() *BackendMock_RewriteRequestHeaders_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *BackendMock_RewriteRequestHeaders_Call) RunAndReturn(run func(http.Header)) *BackendMock_RewriteRequestHeaders_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RewriteResponseHeaders provides a mock function with given fields: header
+func (_m *BackendMock) RewriteResponseHeaders(header http.Header) {
+	_m.Called(header)
+}
+
+// BackendMock_RewriteResponseHeaders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RewriteResponseHeaders'
+type BackendMock_RewriteResponseHeaders_Call struct {
+	*mock.Call
+}
+
+// RewriteResponseHeaders is a helper method to define mock.On call
+//   - header http.Header
+func (_e *BackendMock_Expecter) RewriteResponseHeaders(header interface{}) *BackendMock_RewriteResponseHeaders_Call {
+	return &BackendMock_RewriteResponseHeaders_Call{Call: _e.mock.On("RewriteResponseHeaders", header)}
+}
+
+func (_c *BackendMock_RewriteResponseHeaders_Call) Run(run func(header http.Header)) *BackendMock_RewriteResponseHeaders_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(http.Header))
+	})
+	return _c
+}
+
+func (_c *BackendMock_RewriteResponseHeaders_Call) Return() *BackendMock_RewriteResponseHeaders_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *BackendMock_RewriteResponseHeaders_Call) RunAndReturn(run func(http.Header)) *BackendMock_RewriteResponseHeaders_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TLS provides a mock function with given fields:
+func (_m *BackendMock) TLS() *config.BackendTLS {
+	ret := _m.Called()
+
+	var r0 *config.BackendTLS
+	if rf, ok := ret.Get(0).(func() *config.BackendTLS); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*config.BackendTLS)
+		}
+	}
+
+	return r0
+}
+
+// BackendMock_TLS_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TLS'
+type BackendMock_TLS_Call struct {
+	*mock.Call
+}
+
+// TLS is a helper method to define mock.On call
+func (_e *BackendMock_Expecter) TLS() *BackendMock_TLS_Call {
+	return &BackendMock_TLS_Call{Call: _e.mock.On("TLS")}
+}
+
+func (_c *BackendMock_TLS_Call) Run(run func()) *BackendMock_TLS_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *BackendMock_TLS_Call) Return(_a0 *config.BackendTLS) *BackendMock_TLS_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *BackendMock_TLS_Call) RunAndReturn(run func() *config.BackendTLS) *BackendMock_TLS_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // URL provides a mock function with given fields:
 func (_m *BackendMock) URL() *url.URL {
 	ret := _m.Called()
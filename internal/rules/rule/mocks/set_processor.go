@@ -104,6 +104,40 @@ func (_c *RuleSetProcessorMock_OnDeleted_Call) RunAndReturn(run func(*config.Rul
 	return _c
 }
 
+// OnFailed provides a mock function with given fields: ruleSet, err
+func (_m *RuleSetProcessorMock) OnFailed(ruleSet *config.RuleSet, err error) {
+	_m.Called(ruleSet, err)
+}
+
+// RuleSetProcessorMock_OnFailed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OnFailed'
+type RuleSetProcessorMock_OnFailed_Call struct {
+	*mock.Call
+}
+
+// OnFailed is a helper method to define mock.On call
+//   - ruleSet *config.RuleSet
+//   - err error
+func (_e *RuleSetProcessorMock_Expecter) OnFailed(ruleSet interface{}, err interface{}) *RuleSetProcessorMock_OnFailed_Call {
+	return &RuleSetProcessorMock_OnFailed_Call{Call: _e.mock.On("OnFailed", ruleSet, err)}
+}
+
+func (_c *RuleSetProcessorMock_OnFailed_Call) Run(run func(ruleSet *config.RuleSet, err error)) *RuleSetProcessorMock_OnFailed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*config.RuleSet), args[1].(error))
+	})
+	return _c
+}
+
+func (_c *RuleSetProcessorMock_OnFailed_Call) Return() *RuleSetProcessorMock_OnFailed_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *RuleSetProcessorMock_OnFailed_Call) RunAndReturn(run func(*config.RuleSet, error)) *RuleSetProcessorMock_OnFailed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // OnUpdated provides a mock function with given fields: ruleSet
 func (_m *RuleSetProcessorMock) OnUpdated(ruleSet *config.RuleSet) error {
 	ret := _m.Called(ruleSet)
@@ -24,4 +24,6 @@ type SetProcessor interface {
 	OnCreated(ruleSet *config.RuleSet) error
 	OnUpdated(ruleSet *config.RuleSet) error
 	OnDeleted(ruleSet *config.RuleSet) error
+	// OnFailed is called by the providers if a rule set could not be retrieved or parsed.
+	OnFailed(ruleSet *config.RuleSet, err error)
 }
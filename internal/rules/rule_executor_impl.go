@@ -19,9 +19,9 @@ package rules
 import (
 	"github.com/rs/zerolog"
 
+	"github.com/dadrus/heimdall/internal/accesscontext"
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/rules/rule"
-	"github.com/dadrus/heimdall/internal/x/errorchain"
 )
 
 type ruleExecutor struct {
@@ -35,22 +35,23 @@ func newRuleExecutor(repository rule.Repository) rule.Executor {
 func (e *ruleExecutor) Execute(ctx heimdall.Context) (rule.Backend, error) {
 	req := ctx.Request()
 
+	accesscontext.SetResolvedRequest(ctx.AppContext(), accesscontext.Request{
+		Method: req.Method,
+		Scheme: req.URL.Scheme,
+		Host:   req.URL.Host,
+		Path:   req.URL.Path,
+	})
+
 	//nolint:contextcheck
 	zerolog.Ctx(ctx.AppContext()).Debug().
 		Str("_method", req.Method).
 		Str("_url", req.URL.String()).
 		Msg("Analyzing request")
 
-	rul, err := e.r.FindRule(req.URL)
+	rul, err := e.r.FindRule(req)
 	if err != nil {
 		return nil, err
 	}
 
-	method := ctx.Request().Method
-	if !rul.MatchesMethod(method) {
-		return nil, errorchain.NewWithMessagef(heimdall.ErrMethodNotAllowed,
-			"rule (id=%s, src=%s) doesn't match %s method", rul.ID(), rul.SrcID(), method)
-	}
-
 	return rul.Execute(ctx)
 }
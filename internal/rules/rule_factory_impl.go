@@ -23,9 +23,11 @@ import (
 	"net/http"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/goccy/go-json"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/metric"
 
 	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/heimdall"
@@ -33,6 +35,7 @@ import (
 	"github.com/dadrus/heimdall/internal/rules/mechanisms"
 	"github.com/dadrus/heimdall/internal/rules/patternmatcher"
 	"github.com/dadrus/heimdall/internal/rules/rule"
+	"github.com/dadrus/heimdall/internal/rules/upstream"
 	"github.com/dadrus/heimdall/internal/x"
 	"github.com/dadrus/heimdall/internal/x/errorchain"
 	"github.com/dadrus/heimdall/internal/x/slicex"
@@ -42,11 +45,26 @@ func NewRuleFactory(
 	hf mechanisms.Factory,
 	conf *config.Configuration,
 	mode config.OperationMode,
+	upstreams *upstream.Registry,
+	meterProvider metric.MeterProvider,
 	logger zerolog.Logger,
 ) (rule.Factory, error) {
 	logger.Debug().Msg("Creating rule factory")
 
-	rf := &ruleFactory{hf: hf, hasDefaultRule: false, logger: logger, mode: mode}
+	metrics, err := newMetrics(meterProvider)
+	if err != nil {
+		return nil, errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed to create rule metrics").CausedBy(err)
+	}
+
+	rf := &ruleFactory{
+		hf:             hf,
+		hasDefaultRule: false,
+		logger:         logger,
+		mode:           mode,
+		upstreams:      upstreams,
+		metrics:        metrics,
+	}
 
 	if err := rf.initWithDefaultRule(conf.Default, logger); err != nil {
 		logger.Error().Err(err).Msg("Loading default rule failed")
@@ -63,6 +81,8 @@ type ruleFactory struct {
 	defaultRule    *ruleImpl
 	hasDefaultRule bool
 	mode           config.OperationMode
+	upstreams      *upstream.Registry
+	metrics        *metrics
 }
 
 //nolint:funlen,gocognit,cyclop
@@ -167,6 +187,15 @@ func (f *ruleFactory) CreateRule(version, srcID string, ruleConfig config2.Rule)
 		if err := checkProxyModeApplicability(srcID, ruleConfig); err != nil {
 			return nil, err
 		}
+
+		if ruleConfig.Backend.TLS != nil {
+			// the referenced key and trust stores can only be resolved by the registry
+			if _, err := f.upstreams.TLSConfig(*ruleConfig.Backend.TLS); err != nil {
+				return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+					"bad tls configuration in forward_to in rule ID=%s from %s", ruleConfig.ID, srcID).
+					CausedBy(err)
+			}
+		}
 	}
 
 	matcher, err := patternmatcher.NewPatternMatcher(
@@ -177,6 +206,12 @@ func (f *ruleFactory) CreateRule(version, srcID string, ruleConfig config2.Rule)
 			ruleConfig.RuleMatcher.Strategy, ruleConfig.ID, srcID).CausedBy(err)
 	}
 
+	conditions, err := newRequestConditions(ruleConfig.RuleMatcher)
+	if err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"bad matching conditions defined for rule ID=%s from %s", ruleConfig.ID, srcID).CausedBy(err)
+	}
+
 	authenticators, subHandlers, finalizers, err := f.createExecutePipeline(version, ruleConfig.Execute)
 	if err != nil {
 		return nil, err
@@ -217,6 +252,27 @@ func (f *ruleFactory) CreateRule(version, srcID string, ruleConfig config2.Rule)
 			"failed to create hash for rule ID=%s from %s", ruleConfig.ID, srcID)
 	}
 
+	matcherHash, err := f.createHash(ruleConfig.RuleMatcher)
+	if err != nil {
+		return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"failed to create hash for the matcher of rule ID=%s from %s", ruleConfig.ID, srcID)
+	}
+
+	var (
+		upstreams *upstream.Pool
+		rewriter  *backendRewriter
+	)
+
+	if f.mode == config.ProxyMode {
+		upstreams = f.upstreams.NewPool(ruleConfig.Backend)
+
+		rewriter, err = newBackendRewriter(ruleConfig.Backend.URLRewriter)
+		if err != nil {
+			return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+				"bad rewrite configuration in forward_to in rule ID=%s from %s", ruleConfig.ID, srcID).CausedBy(err)
+		}
+	}
+
 	return &ruleImpl{
 		id: ruleConfig.ID,
 		encodedSlashesHandling: x.IfThenElse(
@@ -224,16 +280,26 @@ func (f *ruleFactory) CreateRule(version, srcID string, ruleConfig config2.Rule)
 			ruleConfig.EncodedSlashesHandling,
 			config2.EncodedSlashesOff,
 		),
-		urlMatcher: matcher,
-		backend:    ruleConfig.Backend,
-		methods:    methods,
-		srcID:      srcID,
-		isDefault:  false,
-		hash:       hash,
-		sc:         authenticators,
-		sh:         subHandlers,
-		fi:         finalizers,
-		eh:         errorHandlers,
+		matcher:     ruleConfig.RuleMatcher,
+		urlMatcher:  matcher,
+		urlPrefix:   patternmatcher.LiteralPrefix(ruleConfig.RuleMatcher.URL),
+		specificity: patternmatcher.LiteralLength(ruleConfig.RuleMatcher.URL),
+		conditions:  conditions,
+		matcherHash: matcherHash,
+		priority:    ruleConfig.Priority,
+		backend:     ruleConfig.Backend,
+		rewriter:    rewriter,
+		upstreams:   upstreams,
+		methods:     methods,
+		srcID:       srcID,
+		isDefault:   false,
+		hash:        hash,
+		sc:          authenticators,
+		sh:          subHandlers,
+		metrics:     f.metrics,
+		fi:          finalizers,
+		eh:          errorHandlers,
+		loadedAt:    time.Now().UTC(),
 	}, nil
 }
 
@@ -244,12 +310,36 @@ func checkProxyModeApplicability(srcID string, ruleConfig config2.Rule) error {
 			ruleConfig.ID, srcID)
 	}
 
-	if len(ruleConfig.Backend.Host) == 0 {
+	if len(ruleConfig.Backend.Host) == 0 && len(ruleConfig.Backend.Targets) == 0 {
 		return errorchain.NewWithMessagef(heimdall.ErrConfiguration,
 			"missing host definition in forward_to in rule ID=%s from %s",
 			ruleConfig.ID, srcID)
 	}
 
+	if len(ruleConfig.Backend.Host) != 0 && len(ruleConfig.Backend.Targets) != 0 {
+		return errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"either host or targets can be defined in forward_to in rule ID=%s from %s, but not both",
+			ruleConfig.ID, srcID)
+	}
+
+	if slices.Contains(ruleConfig.Backend.Targets, "") {
+		return errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"empty target defined in forward_to in rule ID=%s from %s",
+			ruleConfig.ID, srcID)
+	}
+
+	if err := checkLoadBalancing(ruleConfig.Backend.LoadBalancing); err != nil {
+		return errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"bad load_balancing configuration in forward_to in rule ID=%s from %s", ruleConfig.ID, srcID).
+			CausedBy(err)
+	}
+
+	if err := checkHealthCheck(ruleConfig.Backend.HealthCheck); err != nil {
+		return errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"bad health_check configuration in forward_to in rule ID=%s from %s", ruleConfig.ID, srcID).
+			CausedBy(err)
+	}
+
 	urlRewriter := ruleConfig.Backend.URLRewriter
 	if urlRewriter == nil {
 		return nil
@@ -258,7 +348,11 @@ func checkProxyModeApplicability(srcID string, ruleConfig config2.Rule) error {
 	if len(urlRewriter.Scheme) == 0 &&
 		len(urlRewriter.PathPrefixToAdd) == 0 &&
 		len(urlRewriter.PathPrefixToCut) == 0 &&
-		len(urlRewriter.QueryParamsToRemove) == 0 {
+		len(urlRewriter.QueryParamsToRemove) == 0 &&
+		urlRewriter.Path == nil &&
+		len(urlRewriter.QueryParamsToAdd) == 0 &&
+		urlRewriter.RequestHeaders == nil &&
+		urlRewriter.ResponseHeaders == nil {
 		return errorchain.NewWithMessagef(heimdall.ErrConfiguration,
 			"rewrite is defined in forward_to in rule ID=%s from %s, but is empty", ruleConfig.ID, srcID)
 	}
@@ -266,8 +360,53 @@ func checkProxyModeApplicability(srcID string, ruleConfig config2.Rule) error {
 	return nil
 }
 
-func (f *ruleFactory) createHash(ruleConfig config2.Rule) ([]byte, error) {
-	rawRuleConfig, err := json.Marshal(ruleConfig)
+func checkLoadBalancing(conf *config2.LoadBalancing) error {
+	if conf == nil {
+		return nil
+	}
+
+	switch conf.Strategy {
+	case "", config2.LoadBalancingRoundRobin, config2.LoadBalancingLeastConnections:
+		if conf.HashOn != nil {
+			return errorchain.NewWithMessage(heimdall.ErrConfiguration,
+				"hash_on is supported by the consistent_hash strategy only")
+		}
+	case config2.LoadBalancingConsistentHash:
+		if conf.HashOn == nil || (len(conf.HashOn.Header) == 0) == !conf.HashOn.SubjectID {
+			return errorchain.NewWithMessage(heimdall.ErrConfiguration,
+				"consistent_hash strategy requires hash_on to define either header or subject_id")
+		}
+	default:
+		return errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+			"unsupported load balancing strategy %s", conf.Strategy)
+	}
+
+	if phc := conf.PassiveHealthCheck; phc != nil && (phc.MaxFailures < 0 || phc.CoolDown < 0) {
+		return errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"passive_health_check must not contain negative values")
+	}
+
+	return nil
+}
+
+func checkHealthCheck(conf *config2.HealthCheck) error {
+	if conf == nil {
+		return nil
+	}
+
+	if len(conf.Path) != 0 && !strings.HasPrefix(conf.Path, "/") {
+		return errorchain.NewWithMessage(heimdall.ErrConfiguration, "path must start with a slash")
+	}
+
+	if conf.Interval < 0 || conf.Timeout < 0 || conf.HealthyThreshold < 0 || conf.UnhealthyThreshold < 0 {
+		return errorchain.NewWithMessage(heimdall.ErrConfiguration, "health_check must not contain negative values")
+	}
+
+	return nil
+}
+
+func (f *ruleFactory) createHash(conf any) ([]byte, error) {
+	rawRuleConfig, err := json.Marshal(conf)
 	if err != nil {
 		return nil, err
 	}
@@ -356,6 +495,7 @@ func (f *ruleFactory) initWithDefaultRule(ruleConfig *config.DefaultRule, logger
 		methods:                methods,
 		srcID:                  "config",
 		isDefault:              true,
+		metrics:                f.metrics,
 		sc:                     authenticators,
 		sh:                     subHandlers,
 		fi:                     finalizers,
@@ -421,7 +561,7 @@ func createHandler[T subjectHandler](
 		return nil, err
 	}
 
-	return &conditionalSubjectHandler{h: handler, c: condition}, nil
+	return &conditionalSubjectHandler{h: handler, c: condition, typ: handlerType}, nil
 }
 
 func getConfig(conf any) config.MechanismConfig {
@@ -20,11 +20,13 @@ import (
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
 
 	"github.com/dadrus/heimdall/internal/config"
 	"github.com/dadrus/heimdall/internal/heimdall"
@@ -36,6 +38,7 @@ import (
 	mocks7 "github.com/dadrus/heimdall/internal/rules/mechanisms/finalizers/mocks"
 	mocks3 "github.com/dadrus/heimdall/internal/rules/mechanisms/mocks"
 	"github.com/dadrus/heimdall/internal/rules/mocks"
+	"github.com/dadrus/heimdall/internal/rules/upstream"
 	"github.com/dadrus/heimdall/internal/x"
 	"github.com/dadrus/heimdall/internal/x/testsupport"
 )
@@ -512,8 +515,11 @@ func TestRuleFactoryNew(t *testing.T) {
 			handlerFactory := mocks3.NewFactoryMock(t)
 			configureMocks(t, handlerFactory)
 
+			upstreams, err := upstream.NewRegistry(log.Logger, noop.NewMeterProvider())
+			require.NoError(t, err)
+
 			// WHEN
-			factory, err := NewRuleFactory(handlerFactory, tc.config, config.DecisionMode, log.Logger)
+			factory, err := NewRuleFactory(handlerFactory, tc.config, config.DecisionMode, upstreams, noop.NewMeterProvider(), log.Logger)
 
 			// THEN
 			var (
@@ -578,6 +584,173 @@ func TestRuleFactoryCreateRule(t *testing.T) {
 				assert.Contains(t, err.Error(), "missing host")
 			},
 		},
+		{
+			uc:     "in proxy mode, with id and forward_to.host, but bad tls definition",
+			opMode: config.ProxyMode,
+			config: config2.Rule{
+				ID: "foobar",
+				Backend: &config2.Backend{
+					Host: "foo.bar",
+					TLS:  &config2.BackendTLS{TrustStore: "/does/not/exist.pem"},
+				},
+			},
+			assert: func(t *testing.T, err error, rul *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "bad tls configuration")
+			},
+		},
+		{
+			uc:     "in proxy mode, with id and both, forward_to.host and forward_to.targets",
+			opMode: config.ProxyMode,
+			config: config2.Rule{
+				ID:      "foobar",
+				Backend: &config2.Backend{Host: "foo.bar", Targets: []string{"bar.foo"}},
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "but not both")
+			},
+		},
+		{
+			uc:     "in proxy mode, with id and forward_to.targets containing an empty entry",
+			opMode: config.ProxyMode,
+			config: config2.Rule{
+				ID:      "foobar",
+				Backend: &config2.Backend{Targets: []string{"bar.foo", ""}},
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "empty target")
+			},
+		},
+		{
+			uc:     "in proxy mode, with id and forward_to.targets, but unsupported load balancing strategy",
+			opMode: config.ProxyMode,
+			config: config2.Rule{
+				ID:      "foobar",
+				Backend: &config2.Backend{Targets: []string{"bar.foo"}, LoadBalancing: &config2.LoadBalancing{Strategy: "random"}},
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "unsupported load balancing strategy")
+			},
+		},
+		{
+			uc:     "in proxy mode, with id and forward_to.targets, but consistent_hash strategy without hash_on",
+			opMode: config.ProxyMode,
+			config: config2.Rule{
+				ID:      "foobar",
+				Backend: &config2.Backend{Targets: []string{"bar.foo"}, LoadBalancing: &config2.LoadBalancing{Strategy: config2.LoadBalancingConsistentHash}},
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "requires hash_on")
+			},
+		},
+		{
+			uc:     "in proxy mode, with id and forward_to.targets, but hash_on defining header and subject_id",
+			opMode: config.ProxyMode,
+			config: config2.Rule{
+				ID: "foobar",
+				Backend: &config2.Backend{Targets: []string{"bar.foo"}, LoadBalancing: &config2.LoadBalancing{
+					Strategy: config2.LoadBalancingConsistentHash,
+					HashOn:   &config2.HashOn{Header: "X-Foo", SubjectID: true},
+				}},
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "requires hash_on")
+			},
+		},
+		{
+			uc:     "in proxy mode, with id and forward_to.targets, but hash_on for round_robin strategy",
+			opMode: config.ProxyMode,
+			config: config2.Rule{
+				ID:      "foobar",
+				Backend: &config2.Backend{Targets: []string{"bar.foo"}, LoadBalancing: &config2.LoadBalancing{HashOn: &config2.HashOn{SubjectID: true}}},
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "consistent_hash strategy only")
+			},
+		},
+		{
+			uc:     "in proxy mode, with id and forward_to.host, but health_check with relative path",
+			opMode: config.ProxyMode,
+			config: config2.Rule{
+				ID:      "foobar",
+				Backend: &config2.Backend{Host: "foo.bar", HealthCheck: &config2.HealthCheck{Path: "health"}},
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "bad health_check configuration")
+			},
+		},
+		{
+			uc:     "in proxy mode, with id and forward_to.host, but health_check with negative interval",
+			opMode: config.ProxyMode,
+			config: config2.Rule{
+				ID:      "foobar",
+				Backend: &config2.Backend{Host: "foo.bar", HealthCheck: &config2.HealthCheck{Interval: -time.Second}},
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "bad health_check configuration")
+			},
+		},
+		{
+			uc:     "in proxy mode, with id and forward_to.host, but bad rewrite path regex",
+			opMode: config.ProxyMode,
+			config: config2.Rule{
+				ID:          "foobar",
+				RuleMatcher: config2.Matcher{URL: "http://foo.bar", Strategy: "glob"},
+				Backend: &config2.Backend{
+					Host:        "foo.bar",
+					URLRewriter: &config2.URLRewriter{Path: &config2.PathRewriter{Regex: "(foo"}},
+				},
+				Execute: []config.MechanismConfig{{"authenticator": "foo"}},
+				Methods: []string{"FOO"},
+			},
+			configureMocks: func(t *testing.T, mhf *mocks3.FactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateAuthenticator("test", "foo", mock.Anything).Return(&mocks2.AuthenticatorMock{}, nil)
+			},
+			assert: func(t *testing.T, err error, _ *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "bad rewrite configuration")
+			},
+		},
 		{
 			uc:     "in proxy mode, with id and forward_to.host, but empty rewrite definition",
 			opMode: config.ProxyMode,
@@ -618,6 +791,24 @@ func TestRuleFactoryCreateRule(t *testing.T) {
 				assert.Contains(t, err.Error(), "bad URL pattern")
 			},
 		},
+		{
+			uc: "without default rule, with id, but bad matching conditions",
+			config: config2.Rule{
+				ID: "foobar",
+				RuleMatcher: config2.Matcher{
+					URL:       "http://foo.bar",
+					Strategy:  "glob",
+					ClientIPs: []string{"foo"},
+				},
+			},
+			assert: func(t *testing.T, err error, rul *ruleImpl) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Contains(t, err.Error(), "bad matching conditions")
+			},
+		},
 		{
 			uc: "with error while creating execute pipeline",
 			config: config2.Rule{
@@ -856,6 +1047,10 @@ func TestRuleFactoryCreateRule(t *testing.T) {
 				assert.Empty(t, rul.fi)
 				assert.Empty(t, rul.eh)
 				assert.NotNil(t, rul.backend)
+				require.NotNil(t, rul.upstreams)
+				target, err := rul.upstreams.Next("")
+				require.NoError(t, err)
+				assert.Equal(t, "foo.bar", target.Host())
 			},
 		},
 		{
@@ -892,6 +1087,7 @@ func TestRuleFactoryCreateRule(t *testing.T) {
 			uc: "with default rule and with all attributes defined by the rule itself in decision mode",
 			config: config2.Rule{
 				ID:                     "foobar",
+				Priority:               5,
 				RuleMatcher:            config2.Matcher{URL: "http://foo.bar", Strategy: "glob"},
 				EncodedSlashesHandling: config2.EncodedSlashesNoDecode,
 				Execute: []config.MechanismConfig{
@@ -937,6 +1133,8 @@ func TestRuleFactoryCreateRule(t *testing.T) {
 				assert.Equal(t, "foobar", rul.id)
 				assert.Equal(t, config2.EncodedSlashesNoDecode, rul.encodedSlashesHandling)
 				assert.NotNil(t, rul.urlMatcher)
+				assert.NotEmpty(t, rul.matcherHash)
+				assert.Equal(t, 5, rul.priority)
 				assert.ElementsMatch(t, rul.methods, []string{"BAR", "BAZ"})
 
 				// nil checks above mean the responses from the mockHandlerFactory are used
@@ -1219,12 +1417,16 @@ func TestRuleFactoryCreateRule(t *testing.T) {
 			handlerFactory := mocks3.NewFactoryMock(t)
 			configureMocks(t, handlerFactory)
 
+			upstreams, err := upstream.NewRegistry(log.Logger, noop.NewMeterProvider())
+			require.NoError(t, err)
+
 			factory := &ruleFactory{
 				hf:             handlerFactory,
 				defaultRule:    tc.defaultRule,
 				mode:           tc.opMode,
 				logger:         log.Logger,
 				hasDefaultRule: x.IfThenElse(tc.defaultRule != nil, true, false),
+				upstreams:      upstreams,
 			}
 
 			// WHEN
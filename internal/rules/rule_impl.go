@@ -17,24 +17,38 @@
 package rules
 
 import (
+	"bytes"
+	"encoding/hex"
 	"fmt"
+	"net/http"
 	"net/url"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/rs/zerolog"
 
 	"github.com/dadrus/heimdall/internal/heimdall"
 	"github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/rules/mechanisms/subject"
 	"github.com/dadrus/heimdall/internal/rules/patternmatcher"
 	"github.com/dadrus/heimdall/internal/rules/rule"
+	"github.com/dadrus/heimdall/internal/rules/upstream"
 )
 
 type ruleImpl struct {
 	id                     string
 	encodedSlashesHandling config.EncodedSlashesHandling
+	matcher                config.Matcher
 	urlMatcher             patternmatcher.PatternMatcher
+	urlPrefix              string
+	specificity            int
+	conditions             compositeRequestCondition
+	matcherHash            []byte
+	priority               int
 	backend                *config.Backend
+	rewriter               *backendRewriter
+	upstreams              *upstream.Pool
 	methods                []string
 	srcID                  string
 	isDefault              bool
@@ -43,9 +57,26 @@ type ruleImpl struct {
 	sh                     compositeSubjectHandler
 	fi                     compositeSubjectHandler
 	eh                     compositeErrorHandler
+	metrics                *metrics
+	loadedAt               time.Time
 }
 
 func (r *ruleImpl) Execute(ctx heimdall.Context) (rule.Backend, error) {
+	ctx, observer := r.metrics.observe(ctx, r)
+	start := time.Now()
+
+	result, err := r.execute(ctx)
+
+	observer.ruleFinished(ctx.AppContext(), start, err)
+
+	if err != nil {
+		return nil, r.eh.Execute(ctx, err)
+	}
+
+	return result, nil
+}
+
+func (r *ruleImpl) execute(ctx heimdall.Context) (rule.Backend, error) {
 	logger := zerolog.Ctx(ctx.AppContext())
 
 	if r.isDefault {
@@ -54,53 +85,116 @@ func (r *ruleImpl) Execute(ctx heimdall.Context) (rule.Backend, error) {
 		logger.Info().Str("_src", r.srcID).Str("_id", r.id).Msg("Executing rule")
 	}
 
+	if r.urlMatcher != nil {
+		reqURL := ctx.Request().URL
+		if value, ok := r.urlMatchValue(&reqURL.URL); ok {
+			reqURL.Captures = r.urlMatcher.Captures(value)
+		}
+	}
+
 	// authenticators
 	sub, err := r.sc.Execute(ctx)
 	if err != nil {
-		return nil, r.eh.Execute(ctx, err)
+		return nil, err
 	}
 
 	// authorizers & contextualizer
 	if err = r.sh.Execute(ctx, sub); err != nil {
-		return nil, r.eh.Execute(ctx, err)
+		return nil, err
 	}
 
 	// finalizers
 	if err = r.fi.Execute(ctx, sub); err != nil {
-		return nil, r.eh.Execute(ctx, err)
+		return nil, err
 	}
 
-	var upstream rule.Backend
+	var result rule.Backend
 
 	if r.backend != nil {
-		targetURL := *ctx.Request().URL
+		targetURL := ctx.Request().URL.URL
 		if r.encodedSlashesHandling == config.EncodedSlashesOn && len(targetURL.RawPath) != 0 {
 			targetURL.RawPath = ""
 		}
 
-		upstream = &backend{
+		be := &backend{
 			targetURL: r.backend.CreateURL(&targetURL),
+			tls:       r.backend.TLS,
+		}
+
+		if r.rewriter != nil {
+			if err = r.rewriter.rewrite(be, map[string]any{"Request": ctx.Request(), "Subject": sub}); err != nil {
+				return nil, err
+			}
+		}
+
+		if r.upstreams != nil && !upstream.TargetSelectionDisabled(ctx.AppContext()) {
+			target, err := r.upstreams.Next(r.hashKey(ctx, sub))
+			if err != nil {
+				return nil, err
+			}
+
+			be.pool = r.upstreams
+			be.target = target
+			be.targetURL.Host = target.Host()
 		}
+
+		result = be
+	}
+
+	return result, nil
+}
+
+// activate is called by the repository as soon as the rule is used to serve requests.
+func (r *ruleImpl) activate() {
+	if r.upstreams != nil {
+		r.upstreams.Acquire()
+	}
+}
+
+// deactivate is called by the repository if the rule has been removed or replaced.
+func (r *ruleImpl) deactivate() {
+	if r.upstreams != nil {
+		r.upstreams.Release()
 	}
+}
+
+func (r *ruleImpl) hashKey(ctx heimdall.Context, sub *subject.Subject) string {
+	if r.backend.LoadBalancing == nil || r.backend.LoadBalancing.HashOn == nil {
+		return ""
+	}
+
+	hashOn := r.backend.LoadBalancing.HashOn
 
-	return upstream, nil
+	switch {
+	case len(hashOn.Header) != 0:
+		return ctx.Request().Header(hashOn.Header)
+	case hashOn.SubjectID && sub != nil:
+		return sub.ID
+	default:
+		return ""
+	}
 }
 
 func (r *ruleImpl) MatchesURL(requestURL *url.URL) bool {
+	value, ok := r.urlMatchValue(requestURL)
+
+	return ok && r.urlMatcher.Match(value)
+}
+
+// urlMatchValue returns the value the url pattern of the rule is matched against.
+func (r *ruleImpl) urlMatchValue(requestURL *url.URL) (string, bool) {
 	var path string
 
 	switch r.encodedSlashesHandling {
 	case config.EncodedSlashesOff:
 		if strings.Contains(requestURL.RawPath, "%2F") {
-			return false
+			return "", false
 		}
 
 		path = requestURL.Path
 	case config.EncodedSlashesNoDecode:
 		if len(requestURL.RawPath) != 0 {
-			path = strings.ReplaceAll(requestURL.RawPath, "%2F", "$$$escaped-slash$$$")
-			path, _ = url.PathUnescape(path)
-			path = strings.ReplaceAll(path, "$$$escaped-slash$$$", "%2F")
+			path = unescapePathKeepingSlashes(requestURL.RawPath)
 
 			break
 		}
@@ -110,17 +204,92 @@ func (r *ruleImpl) MatchesURL(requestURL *url.URL) bool {
 		path = requestURL.Path
 	}
 
-	return r.urlMatcher.Match(fmt.Sprintf("%s://%s%s", requestURL.Scheme, requestURL.Host, path))
+	return fmt.Sprintf("%s://%s%s", requestURL.Scheme, requestURL.Host, path), true
+}
+
+// unescapePathKeepingSlashes decodes the given raw path except of url-encoded slashes.
+func unescapePathKeepingSlashes(rawPath string) string {
+	path := strings.ReplaceAll(rawPath, "%2F", "$$$escaped-slash$$$")
+	path, _ = url.PathUnescape(path)
+
+	return strings.ReplaceAll(path, "$$$escaped-slash$$$", "%2F")
 }
 
+func (r *ruleImpl) matchesConditions(req *heimdall.Request) bool { return r.conditions.Matches(req) }
+
 func (r *ruleImpl) MatchesMethod(method string) bool { return slices.Contains(r.methods, method) }
 
+// mayOverlap reports whether there might be a request matched by both rules.
+func (r *ruleImpl) mayOverlap(other *ruleImpl) bool {
+	if !slices.ContainsFunc(r.methods, other.MatchesMethod) {
+		return false
+	}
+
+	if bytes.Equal(r.matcherHash, other.matcherHash) {
+		return true
+	}
+
+	if r.urlMatcher == nil || other.urlMatcher == nil {
+		return false
+	}
+
+	return patternmatcher.MayOverlap(r.matcher.URL, r.urlMatcher, other.matcher.URL, other.urlMatcher) &&
+		r.conditions.mayOverlap(other.conditions)
+}
+
 func (r *ruleImpl) ID() string { return r.id }
 
 func (r *ruleImpl) SrcID() string { return r.srcID }
 
+func (r *ruleImpl) info() rule.Info {
+	return rule.Info{
+		ID:       r.id,
+		Source:   r.srcID,
+		Match:    r.matcher,
+		Methods:  r.methods,
+		Priority: r.priority,
+		Mechanisms: rule.MechanismsInfo{
+			Authenticators: mechanismIDs(r.sc),
+			Handlers:       mechanismIDs(r.sh),
+			Finalizers:     mechanismIDs(r.fi),
+			ErrorHandlers:  mechanismIDs(r.eh),
+		},
+		Hash:     hex.EncodeToString(r.hash),
+		LoadedAt: r.loadedAt,
+	}
+}
+
+func mechanismIDs[T any](mechanisms []T) []string {
+	ids := make([]string, 0, len(mechanisms))
+
+	for _, mechanism := range mechanisms {
+		if identifiable, ok := any(mechanism).(interface{ ID() string }); ok {
+			ids = append(ids, identifiable.ID())
+		}
+	}
+
+	return ids
+}
+
 type backend struct {
-	targetURL *url.URL
+	targetURL       *url.URL
+	tls             *config.BackendTLS
+	requestHeaders  *headerModifications
+	responseHeaders *headerModifications
+	pool            *upstream.Pool
+	target          *upstream.Target
 }
 
 func (b *backend) URL() *url.URL { return b.targetURL }
+
+func (b *backend) TLS() *config.BackendTLS { return b.tls }
+
+func (b *backend) RewriteRequestHeaders(header http.Header) { b.requestHeaders.apply(header) }
+
+func (b *backend) RewriteResponseHeaders(header http.Header) { b.responseHeaders.apply(header) }
+
+func (b *backend) Done(err error) {
+	if b.pool != nil {
+		b.pool.Done(b.target, err)
+	}
+}
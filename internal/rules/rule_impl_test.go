@@ -18,11 +18,16 @@ package rules
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
+	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
 
 	"github.com/dadrus/heimdall/internal/heimdall"
 	heimdallmocks "github.com/dadrus/heimdall/internal/heimdall/mocks"
@@ -31,6 +36,7 @@ import (
 	"github.com/dadrus/heimdall/internal/rules/mocks"
 	"github.com/dadrus/heimdall/internal/rules/patternmatcher"
 	"github.com/dadrus/heimdall/internal/rules/rule"
+	"github.com/dadrus/heimdall/internal/rules/upstream"
 	"github.com/dadrus/heimdall/internal/x"
 	"github.com/dadrus/heimdall/internal/x/testsupport"
 )
@@ -387,6 +393,7 @@ func TestRuleExecute(t *testing.T) {
 			uc: "all handler succeed with disallowed urlencoded slashes",
 			backend: &config.Backend{
 				Host: "foo.bar",
+				TLS:  &config.BackendTLS{ServerName: "foo.local"},
 			},
 			configureMocks: func(t *testing.T, ctx *heimdallmocks.ContextMock, authenticator *mocks.SubjectCreatorMock,
 				authorizer *mocks.SubjectHandlerMock, finalizer *mocks.SubjectHandlerMock,
@@ -401,7 +408,7 @@ func TestRuleExecute(t *testing.T) {
 				finalizer.EXPECT().Execute(ctx, sub).Return(nil)
 
 				targetURL, _ := url.Parse("http://foo.local/api/v1/foo%5Bid%5D")
-				ctx.EXPECT().Request().Return(&heimdall.Request{URL: targetURL})
+				ctx.EXPECT().Request().Return(&heimdall.Request{URL: &heimdall.URL{URL: *targetURL}})
 			},
 			assert: func(t *testing.T, err error, backend rule.Backend) {
 				t.Helper()
@@ -410,6 +417,7 @@ func TestRuleExecute(t *testing.T) {
 
 				expectedURL, _ := url.Parse("http://foo.bar/api/v1/foo%5Bid%5D")
 				assert.Equal(t, expectedURL, backend.URL())
+				assert.Equal(t, &config.BackendTLS{ServerName: "foo.local"}, backend.TLS())
 			},
 		},
 		{
@@ -431,7 +439,7 @@ func TestRuleExecute(t *testing.T) {
 				finalizer.EXPECT().Execute(ctx, sub).Return(nil)
 
 				targetURL, _ := url.Parse("http://foo.local/api/v1/foo%5Bid%5D")
-				ctx.EXPECT().Request().Return(&heimdall.Request{URL: targetURL})
+				ctx.EXPECT().Request().Return(&heimdall.Request{URL: &heimdall.URL{URL: *targetURL}})
 			},
 			assert: func(t *testing.T, err error, backend rule.Backend) {
 				t.Helper()
@@ -461,7 +469,7 @@ func TestRuleExecute(t *testing.T) {
 				finalizer.EXPECT().Execute(ctx, sub).Return(nil)
 
 				targetURL, _ := url.Parse("http://foo.local/api%2Fv1/foo%5Bid%5D")
-				ctx.EXPECT().Request().Return(&heimdall.Request{URL: targetURL})
+				ctx.EXPECT().Request().Return(&heimdall.Request{URL: &heimdall.URL{URL: *targetURL}})
 			},
 			assert: func(t *testing.T, err error, backend rule.Backend) {
 				t.Helper()
@@ -491,7 +499,7 @@ func TestRuleExecute(t *testing.T) {
 				finalizer.EXPECT().Execute(ctx, sub).Return(nil)
 
 				targetURL, _ := url.Parse("http://foo.local/api%2Fv1/foo%5Bid%5D")
-				ctx.EXPECT().Request().Return(&heimdall.Request{URL: targetURL})
+				ctx.EXPECT().Request().Return(&heimdall.Request{URL: &heimdall.URL{URL: *targetURL}})
 			},
 			assert: func(t *testing.T, err error, backend rule.Backend) {
 				t.Helper()
@@ -521,7 +529,7 @@ func TestRuleExecute(t *testing.T) {
 				finalizer.EXPECT().Execute(ctx, sub).Return(nil)
 
 				targetURL, _ := url.Parse("http://foo.local/api/v1/foo")
-				ctx.EXPECT().Request().Return(&heimdall.Request{URL: targetURL})
+				ctx.EXPECT().Request().Return(&heimdall.Request{URL: &heimdall.URL{URL: *targetURL}})
 			},
 			assert: func(t *testing.T, err error, backend rule.Backend) {
 				t.Helper()
@@ -562,3 +570,193 @@ func TestRuleExecute(t *testing.T) {
 		})
 	}
 }
+
+func TestRuleExecuteWithLoadBalancedBackend(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	registry, err := upstream.NewRegistry(log.Logger, noop.NewMeterProvider())
+	require.NoError(t, err)
+
+	backendConf := &config.Backend{
+		Targets: []string{"foo:8080", "bar:8080", "baz:8080"},
+		LoadBalancing: &config.LoadBalancing{
+			Strategy: config.LoadBalancingConsistentHash,
+			HashOn:   &config.HashOn{Header: "X-User"},
+		},
+	}
+
+	rul := &ruleImpl{
+		backend:                backendConf,
+		upstreams:              registry.NewPool(backendConf),
+		encodedSlashesHandling: config.EncodedSlashesOff,
+		sc:                     compositeSubjectCreator{},
+		sh:                     compositeSubjectHandler{},
+		fi:                     compositeSubjectHandler{},
+	}
+
+	fnt := heimdallmocks.NewRequestFunctionsMock(t)
+	fnt.EXPECT().Header("X-User").Return("alice")
+
+	requestURL, err := url.Parse("http://foo.local/api/v1/foo")
+	require.NoError(t, err)
+
+	ctx := heimdallmocks.NewContextMock(t)
+	ctx.EXPECT().AppContext().Return(context.Background())
+	ctx.EXPECT().Request().Return(&heimdall.Request{RequestFunctions: fnt, URL: &heimdall.URL{URL: *requestURL}})
+
+	var hosts []string
+
+	for i := 0; i < 3; i++ {
+		// WHEN
+		backend, err := rul.Execute(ctx)
+
+		// THEN
+		require.NoError(t, err)
+		require.NotNil(t, backend)
+
+		assert.Equal(t, "/api/v1/foo", backend.URL().Path)
+		assert.Contains(t, backendConf.Targets, backend.URL().Host)
+
+		hosts = append(hosts, backend.URL().Host)
+
+		backend.Done(nil)
+	}
+
+	assert.Equal(t, hosts[0], hosts[1])
+	assert.Equal(t, hosts[0], hosts[2])
+}
+
+func TestRuleExecuteWithUnhealthyBackend(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	srvURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	registry, err := upstream.NewRegistry(log.Logger, noop.NewMeterProvider())
+	require.NoError(t, err)
+
+	require.NoError(t, registry.Start(context.Background()))
+
+	defer registry.Stop(context.Background()) //nolint:errcheck
+
+	backendConf := &config.Backend{
+		Host:        srvURL.Host,
+		HealthCheck: &config.HealthCheck{Interval: 10 * time.Millisecond, UnhealthyThreshold: 1},
+	}
+
+	rul := &ruleImpl{
+		backend:                backendConf,
+		upstreams:              registry.NewPool(backendConf),
+		encodedSlashesHandling: config.EncodedSlashesOff,
+		sc:                     compositeSubjectCreator{},
+		sh:                     compositeSubjectHandler{},
+		fi:                     compositeSubjectHandler{},
+	}
+
+	rul.activate()
+	defer rul.deactivate()
+
+	require.Eventually(t, func() bool {
+		states := registry.Targets()
+
+		return len(states) == 1 && states[0].Health == upstream.HealthStatusUnhealthy
+	}, time.Second, 5*time.Millisecond)
+
+	requestURL, err := url.Parse("http://foo.local/api/v1/foo")
+	require.NoError(t, err)
+
+	ctx := heimdallmocks.NewContextMock(t)
+	ctx.EXPECT().AppContext().Return(context.Background())
+	ctx.EXPECT().Request().Return(&heimdall.Request{URL: &heimdall.URL{URL: *requestURL}})
+
+	// WHEN
+	backend, err := rul.Execute(ctx)
+
+	// THEN
+	require.Error(t, err)
+	require.ErrorIs(t, err, heimdall.ErrCommunication)
+	assert.Contains(t, err.Error(), "no healthy upstream")
+	assert.Nil(t, backend)
+}
+
+func TestRuleExecuteWithoutTargetSelection(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	registry, err := upstream.NewRegistry(log.Logger, noop.NewMeterProvider())
+	require.NoError(t, err)
+
+	backendConf := &config.Backend{Host: "foo:8080"}
+	pool := registry.NewPool(backendConf)
+
+	rul := &ruleImpl{
+		backend:                backendConf,
+		upstreams:              pool,
+		encodedSlashesHandling: config.EncodedSlashesOff,
+		sc:                     compositeSubjectCreator{},
+		sh:                     compositeSubjectHandler{},
+		fi:                     compositeSubjectHandler{},
+	}
+
+	rul.activate()
+	defer rul.deactivate()
+
+	requestURL, err := url.Parse("http://foo.local/api/v1/foo")
+	require.NoError(t, err)
+
+	ctx := heimdallmocks.NewContextMock(t)
+	ctx.EXPECT().AppContext().Return(upstream.WithoutTargetSelection(context.Background()))
+	ctx.EXPECT().Request().Return(&heimdall.Request{URL: &heimdall.URL{URL: *requestURL}})
+
+	// WHEN
+	backend, err := rul.Execute(ctx)
+
+	// THEN
+	require.NoError(t, err)
+	require.NotNil(t, backend)
+	assert.Equal(t, "http://foo:8080/api/v1/foo", backend.URL().String())
+
+	states := registry.Targets()
+	require.Len(t, states, 1)
+	assert.Equal(t, int64(0), states[0].ActiveRequests)
+}
+
+func TestRuleExecuteSetsURLCaptures(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	matcher, err := patternmatcher.NewPatternMatcher("glob", "http://foo.local/tenants/<?P<tenant>*>/<**>")
+	require.NoError(t, err)
+
+	rul := &ruleImpl{
+		urlMatcher:             matcher,
+		encodedSlashesHandling: config.EncodedSlashesOff,
+		sc:                     compositeSubjectCreator{},
+		sh:                     compositeSubjectHandler{},
+		fi:                     compositeSubjectHandler{},
+	}
+
+	requestURL, err := url.Parse("http://foo.local/tenants/acme/api/v1/foo")
+	require.NoError(t, err)
+
+	req := &heimdall.Request{URL: &heimdall.URL{URL: *requestURL}}
+
+	ctx := heimdallmocks.NewContextMock(t)
+	ctx.EXPECT().AppContext().Return(context.Background())
+	ctx.EXPECT().Request().Return(req)
+
+	// WHEN
+	backend, err := rul.Execute(ctx)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Nil(t, backend)
+	assert.Equal(t, map[string]string{"tenant": "acme"}, req.URL.Captures)
+}
@@ -18,6 +18,7 @@ package rules
 
 import (
 	"errors"
+	"sync"
 
 	"github.com/rs/zerolog"
 
@@ -25,6 +26,7 @@ import (
 	"github.com/dadrus/heimdall/internal/rules/config"
 	"github.com/dadrus/heimdall/internal/rules/event"
 	"github.com/dadrus/heimdall/internal/rules/rule"
+	"github.com/dadrus/heimdall/internal/x"
 	"github.com/dadrus/heimdall/internal/x/errorchain"
 )
 
@@ -34,15 +36,27 @@ type ruleSetProcessor struct {
 	q event.RuleSetChangedEventQueue
 	f rule.Factory
 	l zerolog.Logger
+
+	// ruleSets holds the definitions of the loaded rule sets by their source to be able to
+	// recreate the rules if the heimdall configuration changes.
+	ruleSets map[string]*config.RuleSet
+	mutex    sync.Mutex
 }
 
 func NewRuleSetProcessor(
 	queue event.RuleSetChangedEventQueue, factory rule.Factory, logger zerolog.Logger,
 ) rule.SetProcessor {
+	return newRuleSetProcessor(queue, factory, logger)
+}
+
+func newRuleSetProcessor(
+	queue event.RuleSetChangedEventQueue, factory rule.Factory, logger zerolog.Logger,
+) *ruleSetProcessor {
 	return &ruleSetProcessor{
-		q: queue,
-		f: factory,
-		l: logger,
+		q:        queue,
+		f:        factory,
+		l:        logger,
+		ruleSets: make(map[string]*config.RuleSet),
 	}
 }
 
@@ -50,11 +64,11 @@ func (p *ruleSetProcessor) isVersionSupported(version string) bool {
 	return version == config.CurrentRuleSetVersion
 }
 
-func (p *ruleSetProcessor) loadRules(ruleSet *config.RuleSet) ([]rule.Rule, error) {
+func (p *ruleSetProcessor) loadRules(factory rule.Factory, ruleSet *config.RuleSet) ([]rule.Rule, error) {
 	rules := make([]rule.Rule, len(ruleSet.Rules))
 
 	for idx, rc := range ruleSet.Rules {
-		rul, err := p.f.CreateRule(ruleSet.Version, ruleSet.Source, rc)
+		rul, err := factory.CreateRule(ruleSet.Version, ruleSet.Source, rc)
 		if err != nil {
 			return nil, errorchain.NewWithMessage(heimdall.ErrInternal, "failed loading rule").CausedBy(err)
 		}
@@ -66,61 +80,133 @@ func (p *ruleSetProcessor) loadRules(ruleSet *config.RuleSet) ([]rule.Rule, erro
 }
 
 func (p *ruleSetProcessor) OnCreated(ruleSet *config.RuleSet) error {
-	if !p.isVersionSupported(ruleSet.Version) {
-		return errorchain.NewWithMessage(ErrUnsupportedRuleSetVersion, ruleSet.Version)
-	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
 
-	rules, err := p.loadRules(ruleSet)
+	rules, err := p.process(ruleSet)
 	if err != nil {
 		return err
 	}
 
 	evt := event.RuleSetChanged{
 		Source:     ruleSet.Source,
+		Provider:   ruleSet.Provider,
 		Name:       ruleSet.Name,
+		Hash:       ruleSet.Hash,
 		Rules:      rules,
 		ChangeType: event.Create,
 	}
 
+	p.ruleSets[ruleSet.Source] = ruleSet
 	p.sendEvent(evt)
 
 	return nil
 }
 
 func (p *ruleSetProcessor) OnUpdated(ruleSet *config.RuleSet) error {
-	if !p.isVersionSupported(ruleSet.Version) {
-		return errorchain.NewWithMessage(ErrUnsupportedRuleSetVersion, ruleSet.Version)
-	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
 
-	rules, err := p.loadRules(ruleSet)
+	rules, err := p.process(ruleSet)
 	if err != nil {
 		return err
 	}
 
 	evt := event.RuleSetChanged{
 		Source:     ruleSet.Source,
+		Provider:   ruleSet.Provider,
 		Name:       ruleSet.Name,
+		Hash:       ruleSet.Hash,
 		Rules:      rules,
 		ChangeType: event.Update,
 	}
 
+	p.ruleSets[ruleSet.Source] = ruleSet
 	p.sendEvent(evt)
 
 	return nil
 }
 
 func (p *ruleSetProcessor) OnDeleted(ruleSet *config.RuleSet) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
 	evt := event.RuleSetChanged{
 		Source:     ruleSet.Source,
+		Provider:   ruleSet.Provider,
 		Name:       ruleSet.Name,
 		ChangeType: event.Remove,
 	}
 
+	delete(p.ruleSets, ruleSet.Source)
 	p.sendEvent(evt)
 
 	return nil
 }
 
+func (p *ruleSetProcessor) OnFailed(ruleSet *config.RuleSet, err error) {
+	p.sendEvent(event.RuleSetChanged{
+		Source:     ruleSet.Source,
+		Provider:   ruleSet.Provider,
+		Name:       ruleSet.Name,
+		ChangeType: event.Failed,
+		Err:        err,
+	})
+}
+
+// process creates the rules from the given rule set. Errors are reported to the repository
+// as well to make them available via the management api.
+func (p *ruleSetProcessor) process(ruleSet *config.RuleSet) ([]rule.Rule, error) {
+	var (
+		rules []rule.Rule
+		err   error
+	)
+
+	if !p.isVersionSupported(ruleSet.Version) {
+		err = errorchain.NewWithMessage(ErrUnsupportedRuleSetVersion, ruleSet.Version)
+	} else {
+		rules, err = p.loadRules(p.f, ruleSet)
+	}
+
+	if err != nil {
+		p.OnFailed(ruleSet, err)
+
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// reload recreates the rules of all loaded rule sets using the given factory. Only if all of them
+// could be created, these replace the existing rules together with the default rule of the given
+// factory, which is used for the rule sets processed afterwards. Otherwise, nothing changes.
+func (p *ruleSetProcessor) reload(factory rule.Factory) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var rules []rule.Rule
+
+	for _, ruleSet := range p.ruleSets {
+		ruleSetRules, err := p.loadRules(factory, ruleSet)
+		if err != nil {
+			return errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+				"failed to recreate rules from %s", ruleSet.Source).CausedBy(err)
+		}
+
+		rules = append(rules, ruleSetRules...)
+	}
+
+	p.f = factory
+
+	p.sendEvent(event.RuleSetChanged{
+		Rules:       rules,
+		DefaultRule: x.IfThenElseExec(factory.HasDefaultRule(), factory.DefaultRule, func() rule.Rule { return nil }),
+		ChangeType:  event.Reload,
+	})
+
+	return nil
+}
+
 func (p *ruleSetProcessor) sendEvent(evt event.RuleSetChanged) {
 	p.l.Info().
 		Str("_src", evt.Source).
@@ -26,6 +26,7 @@ import (
 
 	"github.com/dadrus/heimdall/internal/rules/config"
 	"github.com/dadrus/heimdall/internal/rules/event"
+	"github.com/dadrus/heimdall/internal/rules/rule"
 	"github.com/dadrus/heimdall/internal/rules/rule/mocks"
 	"github.com/dadrus/heimdall/internal/x"
 	"github.com/dadrus/heimdall/internal/x/testsupport"
@@ -43,11 +44,16 @@ func TestRuleSetProcessorOnCreated(t *testing.T) {
 		{
 			uc:      "unsupported version",
 			ruleset: &config.RuleSet{Version: "foo"},
-			assert: func(t *testing.T, err error, _ event.RuleSetChangedEventQueue) {
+			assert: func(t *testing.T, err error, queue event.RuleSetChangedEventQueue) {
 				t.Helper()
 
 				require.Error(t, err)
 				require.ErrorIs(t, err, ErrUnsupportedRuleSetVersion)
+				require.Len(t, queue, 1)
+
+				evt := <-queue
+				assert.Equal(t, event.Failed, evt.ChangeType)
+				require.ErrorIs(t, evt.Err, ErrUnsupportedRuleSetVersion)
 			},
 		},
 		{
@@ -65,6 +71,11 @@ func TestRuleSetProcessorOnCreated(t *testing.T) {
 				require.Error(t, err)
 				require.ErrorIs(t, err, testsupport.ErrTestPurpose)
 				assert.Contains(t, err.Error(), "failed loading")
+				require.Len(t, queue, 1)
+
+				evt := <-queue
+				assert.Equal(t, event.Failed, evt.ChangeType)
+				require.ErrorIs(t, evt.Err, testsupport.ErrTestPurpose)
 			},
 		},
 		{
@@ -130,11 +141,16 @@ func TestRuleSetProcessorOnUpdated(t *testing.T) {
 		{
 			uc:      "unsupported version",
 			ruleset: &config.RuleSet{Version: "foo"},
-			assert: func(t *testing.T, err error, _ event.RuleSetChangedEventQueue) {
+			assert: func(t *testing.T, err error, queue event.RuleSetChangedEventQueue) {
 				t.Helper()
 
 				require.Error(t, err)
 				require.ErrorIs(t, err, ErrUnsupportedRuleSetVersion)
+				require.Len(t, queue, 1)
+
+				evt := <-queue
+				assert.Equal(t, event.Failed, evt.ChangeType)
+				require.ErrorIs(t, evt.Err, ErrUnsupportedRuleSetVersion)
 			},
 		},
 		{
@@ -152,6 +168,11 @@ func TestRuleSetProcessorOnUpdated(t *testing.T) {
 				require.Error(t, err)
 				require.ErrorIs(t, err, testsupport.ErrTestPurpose)
 				assert.Contains(t, err.Error(), "failed loading")
+				require.Len(t, queue, 1)
+
+				evt := <-queue
+				assert.Equal(t, event.Failed, evt.ChangeType)
+				require.ErrorIs(t, evt.Err, testsupport.ErrTestPurpose)
 			},
 		},
 		{
@@ -247,3 +268,109 @@ func TestRuleSetProcessorOnDeleted(t *testing.T) {
 		})
 	}
 }
+
+func TestRuleSetProcessorOnFailed(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	queue := make(event.RuleSetChangedEventQueue, 10)
+	processor := NewRuleSetProcessor(queue, mocks.NewFactoryMock(t), log.Logger)
+
+	// WHEN
+	processor.OnFailed(&config.RuleSet{
+		MetaData: config.MetaData{Source: "test", Provider: "file_system"},
+	}, testsupport.ErrTestPurpose)
+
+	// THEN
+	require.Len(t, queue, 1)
+
+	evt := <-queue
+	assert.Equal(t, event.Failed, evt.ChangeType)
+	assert.Equal(t, "test", evt.Source)
+	assert.Equal(t, "file_system", evt.Provider)
+	require.ErrorIs(t, evt.Err, testsupport.ErrTestPurpose)
+}
+
+func TestRuleSetProcessorReload(t *testing.T) {
+	t.Parallel()
+
+	ruleSet := &config.RuleSet{
+		MetaData: config.MetaData{Source: "test"},
+		Version:  config.CurrentRuleSetVersion,
+		Rules:    []config.Rule{{ID: "foo"}},
+	}
+
+	for _, tc := range []struct {
+		uc                  string
+		configureNewFactory func(t *testing.T, mhf *mocks.FactoryMock)
+		assert              func(t *testing.T, err error, queue event.RuleSetChangedEventQueue)
+	}{
+		{
+			uc: "rules cannot be recreated",
+			configureNewFactory: func(t *testing.T, mhf *mocks.FactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateRule(config.CurrentRuleSetVersion, "test", ruleSet.Rules[0]).
+					Return(nil, testsupport.ErrTestPurpose)
+			},
+			assert: func(t *testing.T, err error, queue event.RuleSetChangedEventQueue) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, testsupport.ErrTestPurpose)
+				assert.Contains(t, err.Error(), "test")
+				assert.Empty(t, queue)
+			},
+		},
+		{
+			uc: "rules recreated",
+			configureNewFactory: func(t *testing.T, mhf *mocks.FactoryMock) {
+				t.Helper()
+
+				mhf.EXPECT().CreateRule(config.CurrentRuleSetVersion, "test", ruleSet.Rules[0]).
+					Return(&mocks.RuleMock{}, nil)
+				mhf.EXPECT().HasDefaultRule().Return(true)
+				mhf.EXPECT().DefaultRule().Return(&mocks.RuleMock{})
+			},
+			assert: func(t *testing.T, err error, queue event.RuleSetChangedEventQueue) {
+				t.Helper()
+
+				require.NoError(t, err)
+				require.Len(t, queue, 1)
+
+				evt := <-queue
+				assert.Equal(t, event.Reload, evt.ChangeType)
+				assert.Equal(t, []rule.Rule{&mocks.RuleMock{}}, evt.Rules)
+				assert.Equal(t, &mocks.RuleMock{}, evt.DefaultRule)
+			},
+		},
+	} {
+		t.Run(tc.uc, func(t *testing.T) {
+			// GIVEN
+			queue := make(event.RuleSetChangedEventQueue, 10)
+
+			factory := mocks.NewFactoryMock(t)
+			factory.EXPECT().CreateRule(config.CurrentRuleSetVersion, "test", ruleSet.Rules[0]).
+				Return(&mocks.RuleMock{}, nil).Once()
+
+			newFactory := mocks.NewFactoryMock(t)
+			tc.configureNewFactory(t, newFactory)
+
+			processor := newRuleSetProcessor(queue, factory, log.Logger)
+			require.NoError(t, processor.OnCreated(ruleSet))
+			<-queue
+
+			// WHEN
+			err := processor.reload(newFactory)
+
+			// THEN
+			tc.assert(t, err, queue)
+
+			if err == nil {
+				assert.Equal(t, newFactory, processor.f)
+			} else {
+				assert.Equal(t, factory, processor.f)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/rules/rule"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+// NewStaticRuleExecutor creates a rule executor for the rules from the given rule sets. Unlike
+// the executor used while serving, the rules are not updated at runtime, which makes it suitable
+// for offline usage, like testing of rule sets.
+func NewStaticRuleExecutor(
+	ruleFactory rule.Factory,
+	logger zerolog.Logger,
+	ruleSets ...*config.RuleSet,
+) (rule.Executor, error) {
+	repo := newRepository(nil, ruleFactory, logger)
+	repo.static = true
+	processor := &ruleSetProcessor{f: ruleFactory, l: logger}
+
+	for _, ruleSet := range ruleSets {
+		if !processor.isVersionSupported(ruleSet.Version) {
+			return nil, errorchain.NewWithMessage(ErrUnsupportedRuleSetVersion, ruleSet.Version)
+		}
+
+		rules, err := processor.loadRules(ruleFactory, ruleSet)
+		if err != nil {
+			return nil, errorchain.NewWithMessagef(heimdall.ErrConfiguration,
+				"failed to load rule set %s", ruleSet.Source).CausedBy(err)
+		}
+
+		repo.addRuleSet(ruleSet.Source, rules)
+	}
+
+	return newRuleExecutor(repo), nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/rules/rule/mocks"
+	"github.com/dadrus/heimdall/internal/rules/upstream"
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestNewStaticRuleExecutor(t *testing.T) {
+	t.Parallel()
+
+	registry, err := upstream.NewRegistry(zerolog.Nop(), noop.NewMeterProvider())
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		uc               string
+		ruleSets         []*config.RuleSet
+		configureFactory func(t *testing.T, factory *mocks.FactoryMock)
+		assert           func(t *testing.T, err error, repo *repository)
+	}{
+		{
+			uc:       "unsupported rule set version",
+			ruleSets: []*config.RuleSet{{MetaData: config.MetaData{Source: "test"}, Version: "foo"}},
+			assert: func(t *testing.T, err error, _ *repository) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, ErrUnsupportedRuleSetVersion)
+			},
+		},
+		{
+			uc: "rule creation fails",
+			ruleSets: []*config.RuleSet{
+				{
+					MetaData: config.MetaData{Source: "test"},
+					Version:  config.CurrentRuleSetVersion,
+					Rules:    []config.Rule{{ID: "foo"}},
+				},
+			},
+			configureFactory: func(t *testing.T, factory *mocks.FactoryMock) {
+				t.Helper()
+
+				factory.EXPECT().CreateRule(config.CurrentRuleSetVersion, "test", mock.Anything).
+					Return(nil, testsupport.ErrTestPurpose)
+			},
+			assert: func(t *testing.T, err error, _ *repository) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				require.ErrorIs(t, err, testsupport.ErrTestPurpose)
+				assert.Contains(t, err.Error(), "test")
+			},
+		},
+		{
+			uc: "rules from all rule sets are loaded",
+			ruleSets: []*config.RuleSet{
+				{
+					MetaData: config.MetaData{Source: "test1"},
+					Version:  config.CurrentRuleSetVersion,
+					Rules:    []config.Rule{{ID: "foo"}},
+				},
+				{
+					MetaData: config.MetaData{Source: "test2"},
+					Version:  config.CurrentRuleSetVersion,
+					Rules:    []config.Rule{{ID: "bar"}},
+				},
+			},
+			configureFactory: func(t *testing.T, factory *mocks.FactoryMock) {
+				t.Helper()
+
+				factory.EXPECT().CreateRule(config.CurrentRuleSetVersion, "test1", mock.Anything).
+					Return(newTestRule(t, "foo", "test1", "https://foo.bar/foo"), nil)
+				factory.EXPECT().CreateRule(config.CurrentRuleSetVersion, "test2", mock.Anything).
+					Return(newTestRule(t, "bar", "test2", "https://foo.bar/bar"), nil)
+			},
+			assert: func(t *testing.T, err error, repo *repository) {
+				t.Helper()
+
+				require.NoError(t, err)
+
+				for _, tc := range []struct {
+					url string
+					id  string
+				}{
+					{url: "https://foo.bar/foo", id: "foo"},
+					{url: "https://foo.bar/bar", id: "bar"},
+				} {
+					reqURL, err := url.Parse(tc.url)
+					require.NoError(t, err)
+
+					rul, err := repo.FindRule(&heimdall.Request{
+						Method: http.MethodGet,
+						URL:    &heimdall.URL{URL: *reqURL},
+					})
+					require.NoError(t, err)
+					assert.Equal(t, tc.id, rul.ID())
+				}
+			},
+		},
+		{
+			uc: "rules are not activated",
+			ruleSets: []*config.RuleSet{
+				{
+					MetaData: config.MetaData{Source: "test"},
+					Version:  config.CurrentRuleSetVersion,
+					Rules:    []config.Rule{{ID: "foo"}},
+				},
+			},
+			configureFactory: func(t *testing.T, factory *mocks.FactoryMock) {
+				t.Helper()
+
+				rul := newTestRule(t, "foo", "test", "https://foo.bar/foo")
+				rul.upstreams = registry.NewPool(&config.Backend{
+					Host:        "foo.bar:8080",
+					HealthCheck: &config.HealthCheck{},
+				})
+
+				factory.EXPECT().CreateRule(config.CurrentRuleSetVersion, "test", mock.Anything).Return(rul, nil)
+			},
+			assert: func(t *testing.T, err error, _ *repository) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Empty(t, registry.Targets())
+			},
+		},
+	} {
+		t.Run(tc.uc, func(t *testing.T) {
+			// GIVEN
+			factory := mocks.NewFactoryMock(t)
+			factory.EXPECT().HasDefaultRule().Return(false)
+
+			if tc.configureFactory != nil {
+				tc.configureFactory(t, factory)
+			}
+
+			// WHEN
+			exec, err := NewStaticRuleExecutor(factory, zerolog.Nop(), tc.ruleSets...)
+
+			// THEN
+			var repo *repository
+			if err == nil {
+				repo, _ = exec.(*ruleExecutor).r.(*repository)
+			}
+
+			tc.assert(t, err, repo)
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 //go:generate mockery --name subjectCreator --structname SubjectCreatorMock
 
 type subjectCreator interface {
+	ID() string
 	Execute(ctx heimdall.Context) (*subject.Subject, error)
 	IsFallbackOnErrorAllowed() bool
 }
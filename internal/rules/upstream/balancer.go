@@ -0,0 +1,110 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package upstream
+
+import (
+	"hash/fnv"
+	"sync/atomic"
+
+	"github.com/dadrus/heimdall/internal/rules/config"
+)
+
+type balancer interface {
+	next(targets []*Target, key string) *Target
+}
+
+func newBalancer(strategy config.LoadBalancingStrategy) balancer {
+	switch strategy {
+	case config.LoadBalancingLeastConnections:
+		return &leastConnections{}
+	case config.LoadBalancingConsistentHash:
+		return &consistentHash{fallback: &roundRobin{}}
+	default:
+		return &roundRobin{}
+	}
+}
+
+type roundRobin struct {
+	counter atomic.Uint64
+}
+
+func (b *roundRobin) next(targets []*Target, _ string) *Target {
+	return targets[(b.counter.Add(1)-1)%uint64(len(targets))]
+}
+
+type leastConnections struct {
+	counter atomic.Uint64
+}
+
+func (b *leastConnections) next(targets []*Target, _ string) *Target {
+	// start at a rotating position to distribute requests evenly
+	// between targets having the same amount of active requests
+	start := int((b.counter.Add(1) - 1) % uint64(len(targets)))
+	selected := targets[start]
+
+	for i := 1; i < len(targets); i++ {
+		candidate := targets[(start+i)%len(targets)]
+		if candidate.ActiveRequests() < selected.ActiveRequests() {
+			selected = candidate
+		}
+	}
+
+	return selected
+}
+
+// consistentHash implements rendezvous hashing. That way only the keys of an
+// ejected target are moved to other targets.
+type consistentHash struct {
+	fallback balancer
+}
+
+func (b *consistentHash) next(targets []*Target, key string) *Target {
+	if len(key) == 0 {
+		return b.fallback.next(targets, key)
+	}
+
+	var (
+		selected  *Target
+		bestScore uint64
+	)
+
+	for _, target := range targets {
+		if score := rendezvousScore(key, target.host); selected == nil || score > bestScore {
+			selected = target
+			bestScore = score
+		}
+	}
+
+	return selected
+}
+
+func rendezvousScore(key, host string) uint64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(key))
+	hash.Write([]byte{0})
+	hash.Write([]byte(host))
+
+	// apply the splitmix64 finalizer to improve the distribution of the fnv hash
+	value := hash.Sum64()
+	value ^= value >> 30 //nolint:gomnd
+	value *= 0xbf58476d1ce4e5b9
+	value ^= value >> 27 //nolint:gomnd
+	value *= 0x94d049bb133111eb
+	value ^= value >> 31 //nolint:gomnd
+
+	return value
+}
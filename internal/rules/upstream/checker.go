@@ -0,0 +1,260 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package upstream
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	config2 "github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+const (
+	defaultHealthCheckPath    = "/"
+	defaultExpectedStatus     = http.StatusOK
+	defaultCheckInterval      = 10 * time.Second
+	defaultCheckTimeout       = 2 * time.Second
+	defaultHealthyThreshold   = 2
+	defaultUnhealthyThreshold = 3
+	healthCheckUserAgent      = "heimdall-health-check"
+)
+
+// checkerKey identifies a health checker. Rules configuring the same health check
+// for the same host share a single checker.
+type checkerKey struct {
+	host   string
+	scheme string
+	conf   config.HealthCheck
+	tls    config.BackendTLS
+}
+
+// checker actively probes a target and marks it as unhealthy after the configured
+// amount of consecutive failed probes, respectively healthy again after the configured
+// amount of consecutive successful probes.
+type checker struct {
+	target             *Target
+	client             *http.Client
+	url                string
+	expectedStatus     int
+	interval           time.Duration
+	healthyThreshold   int
+	unhealthyThreshold int
+	logger             zerolog.Logger
+
+	// set if the tls configuration for the probes could not be created.
+	// every probe fails with it in that case.
+	tlsErr error
+
+	// the fields below are guarded by the mutex of the registry
+	refs   int
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// the fields below are accessed by the probing goroutine only
+	healthy   bool
+	successes int
+	failures  int
+}
+
+func newChecker(key checkerKey, target *Target, stores config2.UpstreamTLS, logger zerolog.Logger) *checker {
+	var (
+		tlsConf *tls.Config
+		tlsErr  error
+	)
+
+	if key.tls != (config.BackendTLS{}) {
+		if tlsConf, tlsErr = key.tls.TLSConfig(stores); tlsErr != nil {
+			tlsErr = errorchain.NewWithMessage(heimdall.ErrConfiguration,
+				"failed to create tls configuration for the health check").CausedBy(tlsErr)
+
+			logger.Error().Err(tlsErr).Str("_upstream", key.host).
+				Msg("Health check probes will fail")
+		}
+	}
+
+	conf := key.conf
+	timeout := x.IfThenElse(conf.Timeout > 0, conf.Timeout, defaultCheckTimeout)
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.TLSClientConfig = tlsConf
+	transport.TLSHandshakeTimeout = timeout
+	transport.ResponseHeaderTimeout = timeout
+	transport.IdleConnTimeout = 2 * x.IfThenElse(conf.Interval > 0, conf.Interval, defaultCheckInterval) //nolint:gomnd
+	transport.MaxIdleConnsPerHost = 1
+	probeURL := &url.URL{
+		Scheme: key.scheme,
+		Host:   key.host,
+		Path:   x.IfThenElse(len(conf.Path) != 0, conf.Path, defaultHealthCheckPath),
+	}
+
+	return &checker{
+		target: target,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+			CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		url:                probeURL.String(),
+		expectedStatus:     x.IfThenElse(conf.ExpectedStatus != 0, conf.ExpectedStatus, defaultExpectedStatus),
+		interval:           x.IfThenElse(conf.Interval > 0, conf.Interval, defaultCheckInterval),
+		healthyThreshold:   x.IfThenElse(conf.HealthyThreshold > 0, conf.HealthyThreshold, defaultHealthyThreshold),
+		unhealthyThreshold: x.IfThenElse(conf.UnhealthyThreshold > 0, conf.UnhealthyThreshold, defaultUnhealthyThreshold),
+		logger:             logger,
+		tlsErr:             tlsErr,
+		healthy:            true,
+	}
+}
+
+func (c *checker) start() {
+	if c.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	previous := c.done
+
+	c.cancel = cancel
+	c.done = make(chan struct{})
+
+	go c.run(ctx, previous, c.done)
+}
+
+// stop cancels the probing goroutine and returns a channel, which is closed as soon as that
+// goroutine has exited, or nil if the checker is not running. It does not wait for the goroutine
+// by intention, as an in-flight probe might take up to the configured timeout to finish and
+// the caller typically holds the lock of the registry.
+func (c *checker) stop() <-chan struct{} {
+	if c.cancel == nil {
+		return nil
+	}
+
+	c.cancel()
+	c.cancel = nil
+
+	return c.done
+}
+
+func (c *checker) run(ctx context.Context, previous <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	// the state of the checker is owned by the probing goroutine. So, if the checker has been
+	// restarted, the previous goroutine must have finished before it is touched
+	if previous != nil {
+		<-previous
+	}
+
+	defer c.reset()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.probe(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *checker) reset() {
+	c.client.CloseIdleConnections()
+
+	// a stopped checker must not affect the health state of the target
+	if !c.healthy {
+		c.healthy = true
+		c.target.unhealthyChecks.Add(-1)
+	}
+
+	c.successes = 0
+	c.failures = 0
+}
+
+func (c *checker) probe(ctx context.Context) {
+	if c.tlsErr != nil {
+		c.record(ctx, c.tlsErr, 0)
+
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		c.record(ctx, err, 0)
+
+		return
+	}
+
+	req.Header.Set("User-Agent", healthCheckUserAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		c.record(ctx, err, 0)
+
+		return
+	}
+
+	resp.Body.Close()
+
+	c.record(ctx, nil, resp.StatusCode)
+}
+
+func (c *checker) record(ctx context.Context, err error, status int) {
+	if ctx.Err() != nil {
+		// the checker has been stopped while probing
+		return
+	}
+
+	if err == nil && status == c.expectedStatus {
+		c.failures = 0
+		c.successes++
+
+		if !c.healthy && c.successes >= c.healthyThreshold {
+			c.healthy = true
+			c.target.unhealthyChecks.Add(-1)
+
+			c.logger.Info().Str("_upstream", c.target.host).Msg("Upstream target is healthy again")
+		}
+
+		return
+	}
+
+	c.successes = 0
+	c.failures++
+
+	if c.healthy && c.failures >= c.unhealthyThreshold {
+		c.healthy = false
+		c.target.unhealthyChecks.Add(1)
+
+		c.logger.Warn().Err(err).
+			Str("_upstream", c.target.host).
+			Int("_status", status).
+			Msg("Upstream target is unhealthy")
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package upstream
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/version"
+)
+
+const (
+	instrumentationName = "github.com/dadrus/heimdall/internal/rules/upstream"
+
+	hostAttrKey    = attribute.Key("upstream.host")
+	outcomeAttrKey = attribute.Key("upstream.outcome")
+)
+
+type metrics struct {
+	requests       metric.Int64Counter
+	activeRequests metric.Int64UpDownCounter
+	ejections      metric.Int64Counter
+}
+
+func newMetrics(provider metric.MeterProvider) (*metrics, error) {
+	meter := provider.Meter(instrumentationName, metric.WithInstrumentationVersion(version.Version))
+
+	requests, err := meter.Int64Counter(
+		"upstream.requests",
+		metric.WithDescription("Measures the number of requests forwarded to an upstream target."),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	activeRequests, err := meter.Int64UpDownCounter(
+		"upstream.active_requests",
+		metric.WithDescription("Measures the number of requests to an upstream target currently in-flight."),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ejections, err := meter.Int64Counter(
+		"upstream.ejections",
+		metric.WithDescription("Measures how often an upstream target has been ejected due to consecutive failures."),
+		metric.WithUnit("{ejection}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &metrics{requests: requests, activeRequests: activeRequests, ejections: ejections}, nil
+}
+
+func (m *metrics) requestStarted(ctx context.Context, target *Target) {
+	m.activeRequests.Add(ctx, 1, metric.WithAttributes(hostAttrKey.String(target.host)))
+}
+
+func (m *metrics) requestFinished(ctx context.Context, target *Target, success bool) {
+	m.activeRequests.Add(ctx, -1, metric.WithAttributes(hostAttrKey.String(target.host)))
+	m.requests.Add(ctx, 1, metric.WithAttributes(
+		hostAttrKey.String(target.host),
+		outcomeAttrKey.String(x.IfThenElse(success, "success", "failure")),
+	))
+}
+
+func (m *metrics) targetEjected(ctx context.Context, target *Target) {
+	m.ejections.Add(ctx, 1, metric.WithAttributes(hostAttrKey.String(target.host)))
+}
@@ -0,0 +1,159 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package upstream
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+const (
+	defaultMaxFailures = 3
+	defaultCoolDown    = 30 * time.Second
+)
+
+type noTargetSelectionCtxKey struct{}
+
+// WithoutTargetSelection returns a copy of ctx, which makes the rules skip the selection of
+// an upstream target. It is used for requests, which are never forwarded, like these created
+// to explain how a request would be handled, so that the state of the pools is not affected.
+func WithoutTargetSelection(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noTargetSelectionCtxKey{}, true)
+}
+
+// TargetSelectionDisabled returns true if ctx has been created by WithoutTargetSelection.
+func TargetSelectionDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(noTargetSelectionCtxKey{}).(bool)
+
+	return disabled
+}
+
+// Pool selects one of the configured targets for each request according to
+// the configured load balancing strategy and keeps track of the passive health
+// state of the targets.
+type Pool struct {
+	registry    *Registry
+	checks      []checkerKey
+	tls         *config.BackendTLS
+	targets     []*Target
+	balancer    balancer
+	maxFailures int
+	coolDown    time.Duration
+	metrics     *metrics
+	logger      zerolog.Logger
+}
+
+// Acquire starts the health checks configured for the targets of the pool. It is
+// called when the rule using the pool becomes active.
+func (p *Pool) Acquire() {
+	if p.registry != nil {
+		p.registry.acquire(p)
+	}
+}
+
+// Release stops the health checks configured for the targets of the pool, unless these
+// are still used by other pools. It is called when the rule using the pool is removed.
+func (p *Pool) Release() {
+	if p.registry != nil {
+		p.registry.release(p)
+	}
+}
+
+// Next selects a target for the given key. The key is taken into account by the
+// consistent_hash strategy only. Targets considered unhealthy by the active health
+// checks are never selected. If all healthy targets have been ejected, the selection
+// happens between all healthy targets. If there is no healthy target at all, an error
+// is returned. Each successful call to Next must be followed by a call to Done.
+func (p *Pool) Next(key string) (*Target, error) {
+	now := time.Now()
+
+	healthy := make([]*Target, 0, len(p.targets))
+	candidates := make([]*Target, 0, len(p.targets))
+
+	for _, target := range p.targets {
+		if !target.Healthy() {
+			continue
+		}
+
+		healthy = append(healthy, target)
+
+		if target.available(now) {
+			candidates = append(candidates, target)
+		}
+	}
+
+	if len(healthy) == 0 {
+		return nil, errorchain.NewWithMessage(heimdall.ErrCommunication, "no healthy upstream target available")
+	}
+
+	if len(candidates) == 0 {
+		candidates = healthy
+	}
+
+	target := p.balancer.next(candidates, key)
+	target.activeRequests.Add(1)
+	p.metrics.requestStarted(context.Background(), target)
+
+	return target, nil
+}
+
+// Done releases the target selected by Next and records the outcome of the
+// communication with it.
+func (p *Pool) Done(target *Target, err error) {
+	target.activeRequests.Add(-1)
+	p.metrics.requestFinished(context.Background(), target, err == nil)
+
+	if target.recordResult(err != nil, p.maxFailures, p.coolDown, time.Now()) {
+		p.metrics.targetEjected(context.Background(), target)
+
+		p.logger.Warn().Err(err).
+			Str("_upstream", target.host).
+			Dur("_cool_down", p.coolDown).
+			Msg("Upstream target ejected due to consecutive failures")
+	}
+}
+
+func newPool(targets []*Target, conf *config.LoadBalancing, mtrs *metrics, logger zerolog.Logger) *Pool {
+	pool := &Pool{
+		targets:     targets,
+		balancer:    newBalancer(""),
+		maxFailures: defaultMaxFailures,
+		coolDown:    defaultCoolDown,
+		metrics:     mtrs,
+		logger:      logger,
+	}
+
+	if conf == nil {
+		return pool
+	}
+
+	pool.balancer = newBalancer(conf.Strategy)
+
+	if phc := conf.PassiveHealthCheck; phc != nil {
+		pool.maxFailures = x.IfThenElse(phc.MaxFailures > 0, phc.MaxFailures, defaultMaxFailures)
+		pool.coolDown = x.IfThenElse(phc.CoolDown > 0, phc.CoolDown, defaultCoolDown)
+	}
+
+	return pool
+}
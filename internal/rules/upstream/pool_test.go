@@ -0,0 +1,257 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package upstream
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/dadrus/heimdall/internal/rules/config"
+)
+
+var errTest = errors.New("test error")
+
+func newTestRegistry(t *testing.T) (*Registry, *metric.ManualReader) {
+	t.Helper()
+
+	reader := metric.NewManualReader()
+
+	registry, err := NewRegistry(log.Logger, metric.NewMeterProvider(metric.WithReader(reader)))
+	require.NoError(t, err)
+
+	return registry, reader
+}
+
+func next(t *testing.T, pool *Pool, key string) *Target {
+	t.Helper()
+
+	target, err := pool.Next(key)
+	require.NoError(t, err)
+
+	return target
+}
+
+func selectHosts(t *testing.T, pool *Pool, key string, count int) []string {
+	t.Helper()
+
+	hosts := make([]string, count)
+
+	for idx := range hosts {
+		target := next(t, pool, key)
+		hosts[idx] = target.Host()
+		pool.Done(target, nil)
+	}
+
+	return hosts
+}
+
+func TestPoolNext(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc     string
+		hosts  []string
+		conf   *config.LoadBalancing
+		assert func(t *testing.T, pool *Pool)
+	}{
+		{
+			uc:    "single host",
+			hosts: []string{"foo:8080"},
+			assert: func(t *testing.T, pool *Pool) {
+				t.Helper()
+
+				assert.Equal(t, []string{"foo:8080", "foo:8080"}, selectHosts(t, pool, "", 2))
+			},
+		},
+		{
+			uc:    "round robin is used by default",
+			hosts: []string{"foo:8080", "bar:8080", "baz:8080"},
+			assert: func(t *testing.T, pool *Pool) {
+				t.Helper()
+
+				assert.Equal(t,
+					[]string{"foo:8080", "bar:8080", "baz:8080", "foo:8080"},
+					selectHosts(t, pool, "", 4))
+			},
+		},
+		{
+			uc:    "least connections",
+			hosts: []string{"foo:8080", "bar:8080", "baz:8080"},
+			conf:  &config.LoadBalancing{Strategy: config.LoadBalancingLeastConnections},
+			assert: func(t *testing.T, pool *Pool) {
+				t.Helper()
+
+				first := next(t, pool, "")
+				second := next(t, pool, "")
+				third := next(t, pool, "")
+
+				assert.ElementsMatch(t,
+					[]string{"foo:8080", "bar:8080", "baz:8080"},
+					[]string{first.Host(), second.Host(), third.Host()})
+
+				pool.Done(second, nil)
+
+				// second has now less active requests than the others
+				assert.Equal(t, second, next(t, pool, ""))
+			},
+		},
+		{
+			uc:    "consistent hash",
+			hosts: []string{"foo:8080", "bar:8080", "baz:8080", "zab:8080"},
+			conf: &config.LoadBalancing{
+				Strategy: config.LoadBalancingConsistentHash,
+				HashOn:   &config.HashOn{SubjectID: true},
+			},
+			assert: func(t *testing.T, pool *Pool) {
+				t.Helper()
+
+				for _, key := range []string{"alice", "bob", "carol", "dave"} {
+					hosts := selectHosts(t, pool, key, 3)
+
+					assert.Equal(t, hosts[0], hosts[1])
+					assert.Equal(t, hosts[0], hosts[2])
+				}
+
+				// requests without a key are distributed using round robin
+				assert.Equal(t, []string{"foo:8080", "bar:8080"}, selectHosts(t, pool, "", 2))
+			},
+		},
+		{
+			uc:    "consistent hash moves only keys of ejected targets",
+			hosts: []string{"foo:8080", "bar:8080", "baz:8080", "zab:8080"},
+			conf: &config.LoadBalancing{
+				Strategy:           config.LoadBalancingConsistentHash,
+				HashOn:             &config.HashOn{Header: "X-User"},
+				PassiveHealthCheck: &config.PassiveHealthCheck{MaxFailures: 1, CoolDown: time.Hour},
+			},
+			assert: func(t *testing.T, pool *Pool) {
+				t.Helper()
+
+				keys := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"}
+				before := make(map[string]string, len(keys))
+
+				for _, key := range keys {
+					before[key] = selectHosts(t, pool, key, 1)[0]
+				}
+
+				target := next(t, pool, keys[0])
+				pool.Done(target, errTest)
+
+				for _, key := range keys {
+					host := selectHosts(t, pool, key, 1)[0]
+
+					assert.NotEqual(t, target.Host(), host)
+
+					if before[key] != target.Host() {
+						assert.Equal(t, before[key], host)
+					}
+				}
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			registry, _ := newTestRegistry(t)
+			pool := registry.NewPool(&config.Backend{Targets: tc.hosts, LoadBalancing: tc.conf})
+
+			// WHEN & THEN
+			tc.assert(t, pool)
+		})
+	}
+}
+
+func TestPoolPassiveHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	registry, reader := newTestRegistry(t)
+	pool := registry.NewPool(&config.Backend{
+		Targets: []string{"foo:8080", "bar:8080"},
+		LoadBalancing: &config.LoadBalancing{
+			PassiveHealthCheck: &config.PassiveHealthCheck{MaxFailures: 2, CoolDown: 100 * time.Millisecond},
+		},
+	})
+
+	foo := next(t, pool, "")
+	require.Equal(t, "foo:8080", foo.Host())
+	pool.Done(foo, errTest)
+
+	bar := next(t, pool, "")
+	require.Equal(t, "bar:8080", bar.Host())
+	pool.Done(bar, nil)
+
+	// WHEN
+	foo = next(t, pool, "")
+	pool.Done(foo, errTest)
+
+	// THEN
+	assert.True(t, foo.Ejected())
+	assert.False(t, bar.Ejected())
+	assert.Equal(t, []string{"bar:8080", "bar:8080", "bar:8080"}, selectHosts(t, pool, "", 3))
+
+	// other pools referencing the same host share its state
+	other := registry.NewPool(&config.Backend{Host: "foo:8080"})
+	assert.True(t, next(t, other, "").Ejected())
+
+	// WHEN
+	bar = next(t, pool, "")
+	pool.Done(bar, errTest)
+	bar = next(t, pool, "")
+	pool.Done(bar, errTest)
+
+	// THEN
+	// all targets are ejected, so all are used
+	assert.True(t, bar.Ejected())
+	assert.ElementsMatch(t, []string{"foo:8080", "bar:8080"}, selectHosts(t, pool, "", 2))
+
+	// WHEN
+	time.Sleep(150 * time.Millisecond)
+
+	// THEN
+	assert.False(t, foo.Ejected())
+	assert.False(t, bar.Ejected())
+
+	var rm metricdata.ResourceMetrics
+
+	require.NoError(t, reader.Collect(context.TODO(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m.Data
+	}
+
+	require.Contains(t, metrics, "upstream.requests")
+	require.Contains(t, metrics, "upstream.active_requests")
+	require.Contains(t, metrics, "upstream.ejections")
+
+	ejections, ok := metrics["upstream.ejections"].(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, ejections.DataPoints, 2)
+
+	for _, dp := range ejections.DataPoints {
+		assert.Equal(t, int64(1), dp.Value)
+	}
+}
@@ -0,0 +1,348 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package upstream
+
+import (
+	"cmp"
+	"context"
+	"crypto/tls"
+	"slices"
+	"sync"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/metric"
+
+	config2 "github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/rules/config"
+	"github.com/dadrus/heimdall/internal/x"
+)
+
+// Registry holds the targets of all upstream pools, so that the state of a host,
+// like the number of active requests, or whether it has been ejected, is shared
+// by all rules forwarding requests to it. It also runs the active health checks
+// of the targets referenced by the pools currently in use.
+type Registry struct {
+	mut       sync.Mutex
+	targets   map[string]*Target
+	checkers  map[checkerKey]*checker
+	tlsRefs   map[config.BackendTLS]int
+	tlsStores config2.UpstreamTLS
+	onRelease []func(conf config.BackendTLS)
+	running   bool
+	metrics   *metrics
+	logger    zerolog.Logger
+}
+
+// TargetState describes the state of an upstream target.
+type TargetState struct {
+	Host           string       `json:"host"`
+	Health         HealthStatus `json:"health"`
+	Ejected        bool         `json:"ejected"`
+	ActiveRequests int64        `json:"active_requests"`
+}
+
+type HealthStatus string
+
+const (
+	HealthStatusHealthy   HealthStatus = "healthy"
+	HealthStatusUnhealthy HealthStatus = "unhealthy"
+	HealthStatusUnchecked HealthStatus = "unchecked"
+)
+
+type Option func(r *Registry)
+
+// WithTLSStores sets the key and trust stores the tls settings of the upstreams refer to.
+func WithTLSStores(stores config2.UpstreamTLS) Option {
+	return func(r *Registry) { r.tlsStores = stores }
+}
+
+func NewRegistry(logger zerolog.Logger, provider metric.MeterProvider, opts ...Option) (*Registry, error) {
+	mtrs, err := newMetrics(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := &Registry{
+		targets:  make(map[string]*Target),
+		checkers: make(map[checkerKey]*checker),
+		tlsRefs:  make(map[config.BackendTLS]int),
+		metrics:  mtrs,
+		logger:   logger,
+	}
+
+	for _, opt := range opts {
+		opt(registry)
+	}
+
+	return registry, nil
+}
+
+// Start starts the health checks of the targets referenced by the acquired pools.
+func (r *Registry) Start(_ context.Context) error {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	r.logger.Info().Msg("Starting upstream health checks")
+
+	r.running = true
+
+	for _, chk := range r.checkers {
+		chk.start()
+	}
+
+	return nil
+}
+
+// Stop stops all running health checks.
+func (r *Registry) Stop(_ context.Context) error {
+	stopped := func() []<-chan struct{} {
+		r.mut.Lock()
+		defer r.mut.Unlock()
+
+		r.logger.Info().Msg("Stopping upstream health checks")
+
+		r.running = false
+
+		stopped := make([]<-chan struct{}, 0, len(r.checkers))
+		for _, chk := range r.checkers {
+			stopped = append(stopped, chk.stop())
+		}
+
+		return stopped
+	}()
+
+	// waiting happens without holding the lock to not block the acquisition
+	// and release of pools by in-flight probes
+	waitForCheckers(stopped)
+
+	return nil
+}
+
+// TLSConfig creates the tls configuration for the given tls settings of an upstream resolving
+// the referenced key and trust stores.
+func (r *Registry) TLSConfig(conf config.BackendTLS) (*tls.Config, error) {
+	return conf.TLSConfig(r.tlsStores)
+}
+
+// TLSConfigInUse returns whether the given tls settings are used by any of the acquired pools.
+func (r *Registry) TLSConfigInUse(conf config.BackendTLS) bool {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	return r.tlsRefs[conf] > 0
+}
+
+// OnTLSConfigReleased registers a callback, which is called as soon as the given tls
+// configuration is not used by any of the acquired pools anymore. It allows resources
+// created for that configuration, like http transports, to be released with the rules.
+func (r *Registry) OnTLSConfigReleased(callback func(conf config.BackendTLS)) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	r.onRelease = append(r.onRelease, callback)
+}
+
+// NewPool creates a pool for the hosts and the load balancing and health check settings
+// of the given backend. The health checks are only performed after the pool has been
+// acquired.
+func (r *Registry) NewPool(conf *config.Backend) *Pool {
+	hosts := conf.Hosts()
+	targets := make([]*Target, len(hosts))
+
+	for idx, host := range hosts {
+		targets[idx] = r.target(host)
+	}
+
+	pool := newPool(targets, conf.LoadBalancing, r.metrics, r.logger)
+	pool.registry = r
+
+	if conf.TLS != nil {
+		tlsConf := *conf.TLS
+		pool.tls = &tlsConf
+	}
+
+	if conf.HealthCheck == nil {
+		return pool
+	}
+
+	var tlsConf config.BackendTLS
+	if conf.TLS != nil {
+		tlsConf = *conf.TLS
+	}
+
+	scheme := healthCheckScheme(conf)
+
+	for _, host := range hosts {
+		pool.checks = append(pool.checks, checkerKey{host: host, scheme: scheme, conf: *conf.HealthCheck, tls: tlsConf})
+	}
+
+	return pool
+}
+
+// Targets returns the state of all targets referenced by the acquired pools ordered by their host.
+func (r *Registry) Targets() []TargetState {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	states := make([]TargetState, 0, len(r.targets))
+
+	for _, target := range r.targets {
+		if target.refs == 0 {
+			continue
+		}
+
+		health := HealthStatusUnchecked
+		if target.checks != 0 {
+			health = x.IfThenElse(target.Healthy(), HealthStatusHealthy, HealthStatusUnhealthy)
+		}
+
+		states = append(states, TargetState{
+			Host:           target.host,
+			Health:         health,
+			Ejected:        target.Ejected(),
+			ActiveRequests: target.ActiveRequests(),
+		})
+	}
+
+	slices.SortFunc(states, func(a, b TargetState) int { return cmp.Compare(a.Host, b.Host) })
+
+	return states
+}
+
+func (r *Registry) acquire(pool *Pool) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	for _, target := range pool.targets {
+		target.refs++
+	}
+
+	if pool.tls != nil {
+		r.tlsRefs[*pool.tls]++
+	}
+
+	for _, key := range pool.checks {
+		chk, ok := r.checkers[key]
+		if !ok {
+			target := r.targets[key.host]
+			target.checks++
+
+			chk = newChecker(key, target, r.tlsStores, r.logger)
+			r.checkers[key] = chk
+
+			if r.running {
+				chk.start()
+			}
+		}
+
+		chk.refs++
+	}
+}
+
+func (r *Registry) release(pool *Pool) {
+	conf, released, stopped := r.releasePool(pool)
+
+	// waiting happens without holding the lock to not block the acquisition
+	// and release of other pools by in-flight probes
+	waitForCheckers(stopped)
+
+	if released {
+		r.mut.Lock()
+		callbacks := slices.Clone(r.onRelease)
+		r.mut.Unlock()
+
+		for _, callback := range callbacks {
+			callback(conf)
+		}
+	}
+}
+
+// releasePool releases the resources referenced by the pool. It returns the tls configuration
+// of the pool and true, if that configuration is not referenced by any other pool anymore, as
+// well as the channels of the stopped health checkers to wait for.
+func (r *Registry) releasePool(pool *Pool) (config.BackendTLS, bool, []<-chan struct{}) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	var stopped []<-chan struct{}
+
+	for _, target := range pool.targets {
+		target.refs--
+	}
+
+	for _, key := range pool.checks {
+		chk, ok := r.checkers[key]
+		if !ok {
+			continue
+		}
+
+		chk.refs--
+		if chk.refs == 0 {
+			stopped = append(stopped, chk.stop())
+			chk.target.checks--
+
+			delete(r.checkers, key)
+		}
+	}
+
+	if pool.tls == nil {
+		return config.BackendTLS{}, false, stopped
+	}
+
+	r.tlsRefs[*pool.tls]--
+	if r.tlsRefs[*pool.tls] > 0 {
+		return config.BackendTLS{}, false, stopped
+	}
+
+	delete(r.tlsRefs, *pool.tls)
+
+	return *pool.tls, true, stopped
+}
+
+func waitForCheckers(stopped []<-chan struct{}) {
+	for _, done := range stopped {
+		if done != nil {
+			<-done
+		}
+	}
+}
+
+func (r *Registry) target(host string) *Target {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	target, ok := r.targets[host]
+	if !ok {
+		target = &Target{host: host}
+		r.targets[host] = target
+	}
+
+	return target
+}
+
+func healthCheckScheme(conf *config.Backend) string {
+	switch {
+	case len(conf.HealthCheck.Scheme) != 0:
+		return conf.HealthCheck.Scheme
+	case conf.URLRewriter != nil && len(conf.URLRewriter.Scheme) != 0:
+		return conf.URLRewriter.Scheme
+	case conf.TLS != nil:
+		return "https"
+	default:
+		return "http"
+	}
+}
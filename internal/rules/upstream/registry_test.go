@@ -0,0 +1,349 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package upstream
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/rules/config"
+)
+
+type testUpstream struct {
+	srv    *httptest.Server
+	status atomic.Int32
+	path   atomic.Value
+}
+
+func newTestUpstream(t *testing.T) *testUpstream {
+	t.Helper()
+
+	upstream := &testUpstream{}
+	upstream.status.Store(http.StatusOK)
+	upstream.srv = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		upstream.path.Store(req.URL.Path)
+		rw.WriteHeader(int(upstream.status.Load()))
+	}))
+
+	t.Cleanup(upstream.srv.Close)
+
+	return upstream
+}
+
+func (u *testUpstream) host(t *testing.T) string {
+	t.Helper()
+
+	srvURL, err := url.Parse(u.srv.URL)
+	require.NoError(t, err)
+
+	return srvURL.Host
+}
+
+func TestRegistryActiveHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	foo := newTestUpstream(t)
+	bar := newTestUpstream(t)
+	bar.status.Store(http.StatusServiceUnavailable)
+
+	registry, _ := newTestRegistry(t)
+	require.NoError(t, registry.Start(context.Background()))
+
+	defer registry.Stop(context.Background()) //nolint:errcheck
+
+	pool := registry.NewPool(&config.Backend{
+		Targets: []string{foo.host(t), bar.host(t)},
+		HealthCheck: &config.HealthCheck{
+			Path:               "/health",
+			Interval:           10 * time.Millisecond,
+			HealthyThreshold:   1,
+			UnhealthyThreshold: 1,
+		},
+	})
+
+	// WHEN
+	pool.Acquire()
+
+	// THEN
+	require.Eventually(t, func() bool { return !pool.targets[1].Healthy() }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, "/health", foo.path.Load())
+	assert.Equal(t, []string{foo.host(t), foo.host(t), foo.host(t)}, selectHosts(t, pool, "", 3))
+
+	states := registry.Targets()
+	require.Len(t, states, 2)
+
+	for _, state := range states {
+		expected := HealthStatusHealthy
+		if state.Host == bar.host(t) {
+			expected = HealthStatusUnhealthy
+		}
+
+		assert.Equal(t, expected, state.Health)
+		assert.False(t, state.Ejected)
+	}
+
+	// WHEN
+	foo.status.Store(http.StatusInternalServerError)
+
+	// THEN
+	require.Eventually(t, func() bool { return !pool.targets[0].Healthy() }, time.Second, 5*time.Millisecond)
+
+	_, err := pool.Next("")
+	require.ErrorIs(t, err, heimdall.ErrCommunication)
+
+	// WHEN
+	bar.status.Store(http.StatusOK)
+
+	// THEN
+	require.Eventually(t, func() bool { return pool.targets[1].Healthy() }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, bar.host(t), next(t, pool, "").Host())
+
+	// WHEN
+	pool.Release()
+
+	// THEN
+	assert.Empty(t, registry.Targets())
+	assert.True(t, pool.targets[0].Healthy())
+	assert.Empty(t, registry.checkers)
+}
+
+func TestRegistrySharesHealthChecks(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	foo := newTestUpstream(t)
+
+	registry, _ := newTestRegistry(t)
+	conf := &config.Backend{Host: foo.host(t), HealthCheck: &config.HealthCheck{Interval: time.Hour}}
+
+	first := registry.NewPool(conf)
+	second := registry.NewPool(conf)
+	third := registry.NewPool(&config.Backend{Host: foo.host(t)})
+
+	// WHEN
+	first.Acquire()
+	second.Acquire()
+	third.Acquire()
+
+	// THEN
+	assert.Len(t, registry.checkers, 1)
+	assert.Equal(t, []TargetState{{Host: foo.host(t), Health: HealthStatusHealthy}}, registry.Targets())
+
+	// WHEN
+	first.Release()
+
+	// THEN
+	assert.Len(t, registry.checkers, 1)
+
+	// WHEN
+	second.Release()
+
+	// THEN
+	assert.Empty(t, registry.checkers)
+	assert.Equal(t, []TargetState{{Host: foo.host(t), Health: HealthStatusUnchecked}}, registry.Targets())
+}
+
+func TestRegistryReleaseDoesNotBlockOnInFlightProbe(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	probing := make(chan struct{})
+	unblock := make(chan struct{})
+
+	var once sync.Once
+
+	slow := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		once.Do(func() { close(probing) })
+		<-unblock
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer slow.Close()
+
+	foo := newTestUpstream(t)
+
+	slowURL, err := url.Parse(slow.URL)
+	require.NoError(t, err)
+
+	registry, _ := newTestRegistry(t)
+	require.NoError(t, registry.Start(context.Background()))
+
+	defer registry.Stop(context.Background()) //nolint:errcheck
+
+	first := registry.NewPool(&config.Backend{
+		Host:        slowURL.Host,
+		HealthCheck: &config.HealthCheck{Interval: time.Hour, Timeout: time.Hour},
+	})
+	second := registry.NewPool(&config.Backend{Host: foo.host(t)})
+
+	first.Acquire()
+	<-probing
+
+	released := make(chan struct{})
+
+	// WHEN
+	go func() {
+		first.Release()
+		close(released)
+	}()
+
+	// THEN
+	require.Eventually(t, func() bool {
+		registry.mut.Lock()
+		defer registry.mut.Unlock()
+
+		return len(registry.checkers) == 0
+	}, time.Second, 5*time.Millisecond)
+
+	second.Acquire()
+	second.Release()
+
+	close(unblock)
+
+	select {
+	case <-released:
+	case <-time.After(time.Second):
+		t.Fatal("release did not finish after the probe finished")
+	}
+}
+
+func TestRegistryHealthCheckWithBadTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	foo := newTestUpstream(t)
+
+	registry, _ := newTestRegistry(t)
+	require.NoError(t, registry.Start(context.Background()))
+
+	defer registry.Stop(context.Background()) //nolint:errcheck
+
+	pool := registry.NewPool(&config.Backend{
+		Host: foo.host(t),
+		TLS:  &config.BackendTLS{TrustStore: "not-declared"},
+		HealthCheck: &config.HealthCheck{
+			Interval:           10 * time.Millisecond,
+			UnhealthyThreshold: 1,
+		},
+	})
+
+	// WHEN
+	pool.Acquire()
+
+	// THEN
+	require.Eventually(t, func() bool { return !pool.targets[0].Healthy() }, time.Second, 5*time.Millisecond)
+	assert.Nil(t, foo.path.Load())
+}
+
+func TestRegistryReleasesTLSConfigs(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	foo := newTestUpstream(t)
+
+	var released []config.BackendTLS
+
+	registry, _ := newTestRegistry(t)
+	registry.OnTLSConfigReleased(func(conf config.BackendTLS) { released = append(released, conf) })
+
+	tlsConf := config.BackendTLS{TrustStore: "private-ca"}
+	first := registry.NewPool(&config.Backend{Host: foo.host(t), TLS: &tlsConf})
+	second := registry.NewPool(&config.Backend{Host: foo.host(t), TLS: &tlsConf})
+	third := registry.NewPool(&config.Backend{Host: foo.host(t)})
+
+	// THEN
+	assert.False(t, registry.TLSConfigInUse(tlsConf))
+
+	// WHEN
+	first.Acquire()
+	second.Acquire()
+	third.Acquire()
+
+	// THEN
+	assert.True(t, registry.TLSConfigInUse(tlsConf))
+
+	// WHEN
+	first.Release()
+	third.Release()
+
+	// THEN
+	assert.Empty(t, released)
+	assert.True(t, registry.TLSConfigInUse(tlsConf))
+
+	// WHEN
+	second.Release()
+
+	// THEN
+	assert.Equal(t, []config.BackendTLS{tlsConf}, released)
+	assert.False(t, registry.TLSConfigInUse(tlsConf))
+}
+
+func TestHealthCheckScheme(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc       string
+		conf     *config.Backend
+		expected string
+	}{
+		{
+			uc:       "defaults to http",
+			conf:     &config.Backend{HealthCheck: &config.HealthCheck{}},
+			expected: "http",
+		},
+		{
+			uc:       "https if tls is configured",
+			conf:     &config.Backend{HealthCheck: &config.HealthCheck{}, TLS: &config.BackendTLS{}},
+			expected: "https",
+		},
+		{
+			uc: "scheme from rewrite",
+			conf: &config.Backend{
+				HealthCheck: &config.HealthCheck{},
+				URLRewriter: &config.URLRewriter{Scheme: "https"},
+			},
+			expected: "https",
+		},
+		{
+			uc: "scheme from health check takes precedence",
+			conf: &config.Backend{
+				HealthCheck: &config.HealthCheck{Scheme: "http"},
+				URLRewriter: &config.URLRewriter{Scheme: "https"},
+				TLS:         &config.BackendTLS{},
+			},
+			expected: "http",
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// WHEN
+			scheme := healthCheckScheme(tc.conf)
+
+			// THEN
+			assert.Equal(t, tc.expected, scheme)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package upstream
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Target represents a single upstream host. Its state is shared by all pools
+// referencing the same host.
+type Target struct {
+	host            string
+	activeRequests  atomic.Int64
+	unhealthyChecks atomic.Int32
+
+	// the fields below are guarded by the mutex of the registry
+	refs   int
+	checks int
+
+	mut          sync.Mutex
+	failures     int
+	ejectedUntil time.Time
+}
+
+func (t *Target) Host() string { return t.host }
+
+func (t *Target) ActiveRequests() int64 { return t.activeRequests.Load() }
+
+// Healthy returns false if at least one of the active health checks
+// configured for the target considers it to be unhealthy.
+func (t *Target) Healthy() bool { return t.unhealthyChecks.Load() == 0 }
+
+// Ejected returns true if the target has been ejected due to consecutive failures
+// and the cool down period is not over yet.
+func (t *Target) Ejected() bool { return !t.available(time.Now()) }
+
+func (t *Target) available(now time.Time) bool {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	return !now.Before(t.ejectedUntil)
+}
+
+// recordResult updates the passive health state of the target and returns
+// true if the target has been ejected because of the given failure.
+func (t *Target) recordResult(failed bool, maxFailures int, coolDown time.Duration, now time.Time) bool {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	if !failed {
+		t.failures = 0
+
+		return false
+	}
+
+	t.failures++
+	if t.failures < maxFailures {
+		return false
+	}
+
+	t.failures = 0
+	t.ejectedUntil = now.Add(coolDown)
+
+	return true
+}
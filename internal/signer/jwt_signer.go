@@ -17,12 +17,15 @@
 package signer
 
 import (
+	"context"
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/sha256"
 	"crypto/x509"
+	"errors"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -39,6 +42,8 @@ import (
 	"github.com/dadrus/heimdall/internal/x/stringx"
 )
 
+var ErrNoSigningKey = errors.New("no usable signing key")
+
 func NewJWTSigner(conf *config.Configuration, logger zerolog.Logger) (heimdall.JWTSigner, error) {
 	var (
 		ks  keystore.KeyStore
@@ -60,13 +65,47 @@ func NewJWTSigner(conf *config.Configuration, logger zerolog.Logger) (heimdall.J
 
 		ks, err = keystore.NewKeyStoreFromKey(privateKey)
 	} else {
-		ks, err = keystore.NewKeyStoreFromPEMFile(conf.Signer.KeyStore.Path, conf.Signer.KeyStore.Password)
+		ks, err = keystore.NewKeyStore(conf.Signer.KeyStore)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	logKeyStoreEntries(logger, ks)
+
+	if len(conf.Signer.KeyID) == 0 {
+		logger.Warn().Msg("No key id for signer configured. Taking first entry from the key store")
 	}
 
+	kse, err = signingKey(ks, conf.Signer.KeyID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = validateCertificateChain(kse.CertChain); err != nil {
+		logger.Error().Err(err).Msg("Failed validating certificate")
+
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"configured certificate cannot be used for JWT signing purposes").CausedBy(err)
+	}
+
+	logger.Info().Str("_key_id", kse.KeyID).Msg("Signer configured")
+
+	return &jwtSigner{
+		iss:     conf.Signer.Name,
+		jwk:     kse.JWK(),
+		key:     kse.PrivateKey,
+		chain:   kse.CertChain,
+		ks:      ks,
+		ksConf:  conf.Signer.KeyStore,
+		keyID:   conf.Signer.KeyID,
+		overlap: conf.Signer.KeyRotation.Overlap,
+		l:       logger,
+	}, nil
+}
+
+func logKeyStoreEntries(logger zerolog.Logger, ks keystore.KeyStore) {
 	logger.Info().Msg("Key store contains following entries")
 
 	for _, entry := range ks.Entries() {
@@ -76,67 +115,103 @@ func NewJWTSigner(conf *config.Configuration, logger zerolog.Logger) (heimdall.J
 			Int("_size", entry.KeySize).
 			Msg("Entry info")
 	}
+}
 
-	if len(conf.Signer.KeyID) == 0 {
-		logger.Warn().Msg("No key id for signer configured. Taking first entry from the key store")
-
-		kse, err = ks.Entries()[0], nil
-	} else {
-		kse, err = ks.GetKey(conf.Signer.KeyID)
+// signingKey returns the entry referenced by the given key id, or the first entry
+// of the key store, if no key id is given.
+func signingKey(ks keystore.KeyStore, keyID string) (*keystore.Entry, error) {
+	if len(keyID) != 0 {
+		return ks.GetKey(keyID)
 	}
 
-	if err != nil {
-		return nil, err
+	if len(ks.Entries()) == 0 {
+		return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration, "key store is empty")
 	}
 
-	if len(kse.CertChain) != 0 {
-		if err = pkix.ValidateCertificate(kse.CertChain[0],
-			pkix.WithKeyUsage(x509.KeyUsageDigitalSignature),
-			pkix.WithRootCACertificates([]*x509.Certificate{kse.CertChain[len(kse.CertChain)-1]}),
-			pkix.WithCurrentTime(time.Now()),
-		); err != nil {
-			logger.Error().Err(err).Msg("Failed validating certificate")
+	return ks.Entries()[0], nil
+}
 
-			return nil, errorchain.NewWithMessage(heimdall.ErrConfiguration,
-				"configured certificate cannot be used for JWT signing purposes").CausedBy(err)
-		}
+func validateCertificateChain(chain []*x509.Certificate) error {
+	if len(chain) == 0 {
+		return nil
 	}
 
-	logger.Info().Str("_key_id", kse.KeyID).Msg("Signer configured")
+	return pkix.ValidateCertificate(chain[0],
+		pkix.WithKeyUsage(x509.KeyUsageDigitalSignature),
+		pkix.WithRootCACertificates([]*x509.Certificate{chain[len(chain)-1]}),
+		pkix.WithCurrentTime(time.Now()),
+	)
+}
 
-	return &jwtSigner{
-		iss: conf.Signer.Name,
-		jwk: kse.JWK(),
-		key: kse.PrivateKey,
-		ks:  ks,
-	}, nil
+type retiringKey struct {
+	jwk   jose.JSONWebKey
+	until time.Time
 }
 
+// jwtSigner signs JWTs with the active key. On key store changes, a new signing key is
+// first published as the next key and used for signing only after the configured overlap
+// period. The replaced key is then published as a retiring key for the same period, so that
+// JWTs signed with it can still be verified.
 type jwtSigner struct {
-	iss string
-	jwk jose.JSONWebKey
-	key crypto.Signer
-	ks  keystore.KeyStore
+	iss   string
+	jwk   jose.JSONWebKey
+	key   crypto.Signer
+	chain []*x509.Certificate
+	ks    keystore.KeyStore
+
+	ksConf  config.KeyStore
+	keyID   string
+	overlap time.Duration
+	l       zerolog.Logger
+
+	next       *keystore.Entry
+	activateAt time.Time
+	retiring   []retiringKey
+	mutex      sync.Mutex
+}
+
+func (s *jwtSigner) Name() string { return "signer" }
+
+func (s *jwtSigner) Check(_ context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.rotate(time.Now())
+
+	if s.key == nil || len(s.ks.Entries()) == 0 {
+		return ErrNoSigningKey
+	}
+
+	if err := validateCertificateChain(s.chain); err != nil {
+		return errorchain.NewWithMessage(ErrNoSigningKey, "certificate of the signing key is not valid").
+			CausedBy(err)
+	}
+
+	return nil
 }
 
 func (s *jwtSigner) Hash() []byte {
+	jwk, _ := s.ActiveKey()
+
 	hash := sha256.New()
-	hash.Write(stringx.ToBytes(s.jwk.KeyID))
-	hash.Write(stringx.ToBytes(s.jwk.Algorithm))
+	hash.Write(stringx.ToBytes(jwk.KeyID))
+	hash.Write(stringx.ToBytes(jwk.Algorithm))
 	hash.Write(stringx.ToBytes(s.iss))
 
 	return hash.Sum(nil)
 }
 
 func (s *jwtSigner) Sign(sub string, ttl time.Duration, custClaims map[string]any) (string, error) {
+	jwk, key := s.ActiveKey()
+
 	signerOpts := jose.SignerOptions{}
 	signerOpts.
 		WithType("JWT").
-		WithHeader("kid", s.jwk.KeyID).
-		WithHeader("alg", s.jwk.Algorithm)
+		WithHeader("kid", jwk.KeyID).
+		WithHeader("alg", jwk.Algorithm)
 
 	signer, err := jose.NewSigner(
-		jose.SigningKey{Algorithm: jose.SignatureAlgorithm(s.jwk.Algorithm), Key: s.key},
+		jose.SigningKey{Algorithm: jose.SignatureAlgorithm(jwk.Algorithm), Key: keystore.JOSESigningKey(key)},
 		&signerOpts)
 	if err != nil {
 		return "", errorchain.NewWithMessage(heimdall.ErrInternal, "failed to create JWT signer").CausedBy(err)
@@ -164,12 +239,140 @@ func (s *jwtSigner) Sign(sub string, ttl time.Duration, custClaims map[string]an
 	return rawJwt, nil
 }
 
+// Keys returns the keys to be published in the JWKS. Next to the entries of the key store,
+// these are the active key, the next key and the retiring keys, even if these have already
+// been removed from the key store.
 func (s *jwtSigner) Keys() []jose.JSONWebKey {
-	keys := make([]jose.JSONWebKey, len(s.ks.Entries()))
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.rotate(time.Now())
+
+	known := make(map[string]bool)
+	keys := make([]jose.JSONWebKey, 0, len(s.ks.Entries())+len(s.retiring)+1)
+	add := func(jwk jose.JSONWebKey) {
+		if !known[jwk.KeyID] {
+			known[jwk.KeyID] = true
+
+			keys = append(keys, jwk)
+		}
+	}
+
+	for _, entry := range s.ks.Entries() {
+		add(entry.JWK())
+	}
+
+	if s.key != nil {
+		add(s.jwk)
+	}
 
-	for idx, entry := range s.ks.Entries() {
-		keys[idx] = entry.JWK()
+	if s.next != nil {
+		add(s.next.JWK())
+	}
+
+	for _, rk := range s.retiring {
+		add(rk.jwk)
 	}
 
 	return keys
 }
+
+// ActiveKey returns the JWK and the private key currently used for signing.
+func (s *jwtSigner) ActiveKey() (jose.JSONWebKey, crypto.Signer) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.rotate(time.Now())
+
+	return s.jwk, s.key
+}
+
+// reload loads the key store again and schedules the rotation to the selected signing key if
+// it differs from the active one. On errors, the current keys stay in effect.
+func (s *jwtSigner) reload() error {
+	ks, err := keystore.NewKeyStore(s.ksConf)
+	if err != nil {
+		return err
+	}
+
+	kse, err := signingKey(ks, s.keyID)
+	if err != nil {
+		return err
+	}
+
+	if err = validateCertificateChain(kse.CertChain); err != nil {
+		return errorchain.NewWithMessage(heimdall.ErrConfiguration,
+			"configured certificate cannot be used for JWT signing purposes").CausedBy(err)
+	}
+
+	logKeyStoreEntries(s.l, ks)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+
+	s.ks = ks
+	s.rotate(now)
+	s.schedule(kse, now)
+
+	return nil
+}
+
+// schedule makes the given entry the next signing key. Must be called with the mutex held.
+func (s *jwtSigner) schedule(kse *keystore.Entry, now time.Time) {
+	switch {
+	case kse.KeyID == s.jwk.KeyID:
+		// e.g. a renewed certificate for the same key
+		s.activate(kse)
+
+		if s.next != nil {
+			s.l.Info().Str("_key_id", s.next.KeyID).Msg("Scheduled signing key rotation canceled")
+		}
+
+		s.next = nil
+	case s.next != nil && kse.KeyID == s.next.KeyID:
+		s.next = kse
+	default:
+		s.next = kse
+		s.activateAt = now.Add(s.overlap)
+
+		s.l.Info().
+			Str("_key_id", kse.KeyID).
+			Time("_activation", s.activateAt).
+			Msg("Signing key rotation scheduled")
+
+		s.rotate(now)
+	}
+}
+
+// rotate activates the next key, if its activation time has been reached, and drops
+// the retiring keys, which are not required any more. Must be called with the mutex held.
+func (s *jwtSigner) rotate(now time.Time) {
+	if s.next != nil && !now.Before(s.activateAt) {
+		if s.key != nil {
+			s.retiring = append(s.retiring, retiringKey{jwk: s.jwk, until: s.activateAt.Add(s.overlap)})
+		}
+
+		s.activate(s.next)
+		s.next = nil
+
+		s.l.Info().Str("_key_id", s.jwk.KeyID).Msg("Signing key rotated")
+	}
+
+	retiring := s.retiring[:0]
+
+	for _, rk := range s.retiring {
+		if now.Before(rk.until) {
+			retiring = append(retiring, rk)
+		}
+	}
+
+	s.retiring = retiring
+}
+
+func (s *jwtSigner) activate(kse *keystore.Entry) {
+	s.jwk = kse.JWK()
+	s.key = kse.PrivateKey
+	s.chain = kse.CertChain
+}
@@ -17,6 +17,8 @@
 package signer
 
 import (
+	"context"
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
@@ -370,6 +372,21 @@ func TestJWTSignerSign(t *testing.T) {
 				validateTestJWT(t, rawJWT, signer, subjectID, ttl, claims)
 			},
 		},
+		{
+			uc: "sign with key not directly usable by go-jose",
+			signer: &jwtSigner{
+				iss: "foo",
+				key: opaqueKey{ecdsaPrivKey1},
+				jwk: jose.JSONWebKey{KeyID: "bar", Algorithm: string(jose.ES256)},
+			},
+			claims: map[string]any{"baz": "zab", "bla": "foo"},
+			assert: func(t *testing.T, err error, rawJWT string, signer *jwtSigner, claims map[string]any) {
+				t.Helper()
+
+				require.NoError(t, err)
+				validateTestJWT(t, rawJWT, signer, subjectID, ttl, claims)
+			},
+		},
 		{
 			uc: "sign claims, which contain JWT specific claims",
 			signer: &jwtSigner{
@@ -413,6 +430,11 @@ func TestJWTSignerSign(t *testing.T) {
 	}
 }
 
+// opaqueKey hides the type of the wrapped key, like done by keys residing in a hardware security module.
+type opaqueKey struct {
+	crypto.Signer
+}
+
 func validateTestJWT(t *testing.T, rawJWT string, signer *jwtSigner,
 	subjectID string, ttl time.Duration, customClaims map[string]any,
 ) {
@@ -511,3 +533,221 @@ func TestJwtSignerKeys(t *testing.T) {
 	assert.Equal(t, "PS256", keys[0].Algorithm)
 	assert.Equal(t, "ES256", keys[1].Algorithm)
 }
+
+func TestJWTSignerCheck(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ks, err := keystore.NewKeyStoreFromKey(privKey)
+	require.NoError(t, err)
+
+	expiredCert, err := testsupport.NewCertificateBuilder(
+		testsupport.WithValidity(time.Now().Add(-2*time.Hour), 1*time.Hour),
+		testsupport.WithSerialNumber(big.NewInt(1)),
+		testsupport.WithSubject(pkix.Name{CommonName: "expired cert", Organization: []string{"Test"}}),
+		testsupport.WithSubjectPubKey(&privKey.PublicKey, x509.ECDSAWithSHA256),
+		testsupport.WithKeyUsage(x509.KeyUsageDigitalSignature),
+		testsupport.WithSelfSigned(),
+		testsupport.WithSignaturePrivKey(privKey)).
+		Build()
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		uc     string
+		signer *jwtSigner
+		assert func(t *testing.T, err error)
+	}{
+		{
+			uc:     "signer with usable key",
+			signer: &jwtSigner{key: privKey, ks: ks},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.NoError(t, err)
+			},
+		},
+		{
+			uc:     "signer with expired certificate",
+			signer: &jwtSigner{key: privKey, chain: []*x509.Certificate{expiredCert}, ks: ks},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrNoSigningKey)
+				assert.Contains(t, err.Error(), "certificate")
+			},
+		},
+		{
+			uc:     "signer without key",
+			signer: &jwtSigner{ks: ks},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				require.ErrorIs(t, err, ErrNoSigningKey)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// WHEN
+			err := tc.signer.Check(context.Background())
+
+			// THEN
+			tc.assert(t, err)
+		})
+	}
+}
+
+func TestJWTSignerKeyRotation(t *testing.T) {
+	t.Parallel()
+
+	privKey1, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	privKey2, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	key1 := pemx.WithECDSAPrivateKey(privKey1, pemx.WithHeader("X-Key-ID", "key1"))
+	key2 := pemx.WithECDSAPrivateKey(privKey2, pemx.WithHeader("X-Key-ID", "key2"))
+
+	keyIDs := func(keys []jose.JSONWebKey) []string {
+		ids := make([]string, len(keys))
+		for idx, key := range keys {
+			ids[idx] = key.KeyID
+		}
+
+		return ids
+	}
+
+	signingKeyID := func(t *testing.T, signer *jwtSigner) string {
+		t.Helper()
+
+		rawJWT, err := signer.Sign("foo", time.Minute, nil)
+		require.NoError(t, err)
+
+		token, err := jwt.ParseSigned(rawJWT)
+		require.NoError(t, err)
+
+		return token.Headers[0].KeyID
+	}
+
+	for _, tc := range []struct {
+		uc      string
+		overlap time.Duration
+		update  []pemx.EntryOption
+		assert  func(t *testing.T, err error, signer *jwtSigner)
+	}{
+		{
+			uc:      "new key is published before it is used for signing",
+			overlap: time.Hour,
+			update:  []pemx.EntryOption{key2, key1},
+			assert: func(t *testing.T, err error, signer *jwtSigner) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "key1", signingKeyID(t, signer))
+				assert.ElementsMatch(t, []string{"key1", "key2"}, keyIDs(signer.Keys()))
+
+				// WHEN the overlap period is over
+				signer.activateAt = time.Now().Add(-time.Second)
+
+				// THEN
+				assert.Equal(t, "key2", signingKeyID(t, signer))
+				assert.ElementsMatch(t, []string{"key1", "key2"}, keyIDs(signer.Keys()))
+				require.Len(t, signer.retiring, 1)
+				assert.Equal(t, "key1", signer.retiring[0].jwk.KeyID)
+			},
+		},
+		{
+			uc:      "replaced key is published until the overlap period is over",
+			overlap: time.Hour,
+			update:  []pemx.EntryOption{key2},
+			assert: func(t *testing.T, err error, signer *jwtSigner) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "key1", signingKeyID(t, signer))
+				assert.ElementsMatch(t, []string{"key1", "key2"}, keyIDs(signer.Keys()))
+
+				// WHEN the new key becomes active
+				signer.activateAt = time.Now().Add(-time.Second)
+
+				// THEN
+				assert.Equal(t, "key2", signingKeyID(t, signer))
+				assert.ElementsMatch(t, []string{"key1", "key2"}, keyIDs(signer.Keys()))
+
+				// WHEN the retiring key is not required any more
+				signer.retiring[0].until = time.Now().Add(-time.Second)
+
+				// THEN
+				assert.Equal(t, []string{"key2"}, keyIDs(signer.Keys()))
+			},
+		},
+		{
+			uc:     "new key is used immediately without overlap",
+			update: []pemx.EntryOption{key2},
+			assert: func(t *testing.T, err error, signer *jwtSigner) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "key2", signingKeyID(t, signer))
+				assert.Equal(t, []string{"key2"}, keyIDs(signer.Keys()))
+			},
+		},
+		{
+			uc:      "active key stays the signing key",
+			overlap: time.Hour,
+			update:  []pemx.EntryOption{key1, key2},
+			assert: func(t *testing.T, err error, signer *jwtSigner) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, "key1", signingKeyID(t, signer))
+				assert.Nil(t, signer.next)
+				assert.Equal(t, []string{"key1", "key2"}, keyIDs(signer.Keys()))
+			},
+		},
+		{
+			uc:      "invalid key store keeps the current keys",
+			overlap: time.Hour,
+			assert: func(t *testing.T, err error, signer *jwtSigner) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, heimdall.ErrConfiguration)
+				assert.Equal(t, "key1", signingKeyID(t, signer))
+				assert.Equal(t, []string{"key1"}, keyIDs(signer.Keys()))
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			keyFile := filepath.Join(t.TempDir(), "keys.pem")
+
+			pemBytes, err := pemx.BuildPEM(key1)
+			require.NoError(t, err)
+
+			require.NoError(t, os.WriteFile(keyFile, pemBytes, 0o600))
+
+			signer, err := NewJWTSigner(&config.Configuration{Signer: config.SignerConfig{
+				KeyStore:    config.KeyStore{Path: keyFile},
+				KeyRotation: config.KeyRotation{Overlap: tc.overlap},
+			}}, log.Logger)
+			require.NoError(t, err)
+
+			impl, ok := signer.(*jwtSigner)
+			require.True(t, ok)
+
+			pemBytes, err = pemx.BuildPEM(tc.update...)
+			require.NoError(t, err)
+
+			require.NoError(t, os.WriteFile(keyFile, pemBytes, 0o600))
+
+			// WHEN
+			err = impl.reload()
+
+			// THEN
+			tc.assert(t, err, impl)
+		})
+	}
+}
@@ -16,10 +16,60 @@
 
 package signer
 
-import "go.uber.org/fx"
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/fx"
+
+	"github.com/dadrus/heimdall/internal/config"
+	"github.com/dadrus/heimdall/internal/health"
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/x/filewatcher"
+)
 
 // Module is used on app bootstrap.
 // nolint: gochecknoglobals
 var Module = fx.Options(
 	fx.Provide(NewJWTSigner),
+	fx.Invoke(registerHealthCheck),
+	fx.Invoke(registerKeyStoreWatcher),
 )
+
+func registerHealthCheck(signer heimdall.JWTSigner, registry *health.Registry) {
+	if checker, ok := signer.(health.Checker); ok {
+		registry.Register(checker)
+	}
+}
+
+func registerKeyStoreWatcher(signer heimdall.JWTSigner, logger zerolog.Logger, lifecycle fx.Lifecycle) {
+	impl, ok := signer.(*jwtSigner)
+	if !ok || len(impl.ksConf.Path) == 0 || impl.ksConf.Type == config.KeyStoreTypePKCS11 {
+		return
+	}
+
+	watcher := filewatcher.New(impl.ksConf.Path, func() error {
+		logger.Info().Msg("Reloading key store")
+
+		if err := impl.reload(); err != nil {
+			logger.Error().Err(err).Msg("Failed to reload key store. Keeping the current keys")
+
+			return err
+		}
+
+		return nil
+	}, logger)
+
+	lifecycle.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			if err := watcher.Start(); err != nil {
+				return err
+			}
+
+			logger.Info().Str("_file", impl.ksConf.Path).Msg("Watching key store for changes")
+
+			return nil
+		},
+		OnStop: func(_ context.Context) error { return watcher.Stop() },
+	})
+}
@@ -24,6 +24,7 @@ import (
 
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	"github.com/iancoleman/strcase"
 )
 
 //nolint:cyclop,funlen,gocognit
@@ -48,6 +49,42 @@ func registerTranslations(validate *validator.Validate, trans ut.Translator) err
 				return translation
 			},
 		},
+		{
+			tag:         "required_without_all",
+			translation: "{0} is a required field as long as none of {1} is set",
+			override:    false,
+			customTransFunc: func(ut ut.Translator, fe validator.FieldError) string {
+				params := strings.Fields(fe.Param())
+				for idx, param := range params {
+					params[idx] = strcase.ToSnake(param)
+				}
+
+				translation, err := ut.T(fe.Tag(), fe.Field(), strings.Join(params, ", "))
+				if err != nil {
+					return fe.Error()
+				}
+
+				return translation
+			},
+		},
+		{
+			tag:         "excluded_unless",
+			translation: "{0} can only be set if {1} is {2}",
+			override:    false,
+			customTransFunc: func(ut ut.Translator, fe validator.FieldError) string {
+				params := strings.Fields(fe.Param())
+				if len(params) != 2 { //nolint:gomnd
+					return fe.Error()
+				}
+
+				translation, err := ut.T(fe.Tag(), fe.Field(), strcase.ToSnake(params[0]), params[1])
+				if err != nil {
+					return fe.Error()
+				}
+
+				return translation
+			},
+		},
 		{
 			tag: "gt",
 			customRegisFunc: func(ut ut.Translator) error {
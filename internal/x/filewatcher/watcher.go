@@ -0,0 +1,163 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package filewatcher
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog"
+
+	"github.com/dadrus/heimdall/internal/heimdall"
+	"github.com/dadrus/heimdall/internal/x/errorchain"
+)
+
+// ChangeDelay is the time to wait for further changes to the watched file before notifying about
+// them. That way a partially written file is not taken into account and a change, which results in
+// multiple events is processed only once.
+const ChangeDelay = 250 * time.Millisecond
+
+// Watcher calls the given callback whenever the contents of the watched file change. If the callback
+// fails, it is called again on the next change event, even if the contents did not change since then.
+type Watcher struct {
+	file     string
+	hash     []byte
+	onChange func() error
+	l        zerolog.Logger
+
+	fw   *fsnotify.Watcher
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func New(file string, onChange func() error, logger zerolog.Logger) *Watcher {
+	if absPath, err := filepath.Abs(file); err == nil {
+		file = absPath
+	}
+
+	return &Watcher{file: file, hash: fileHash(file), onChange: onChange, l: logger}
+}
+
+func (w *Watcher) Start() error {
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errorchain.NewWithMessage(heimdall.ErrInternal,
+			"failed to create file watcher").CausedBy(err)
+	}
+
+	// the directory is watched as the file itself is typically replaced and not updated in
+	// place, e.g. by editors, or by kubernetes for mounted config maps and secrets
+	if err = fw.Add(filepath.Dir(w.file)); err != nil {
+		_ = fw.Close()
+
+		return errorchain.NewWithMessagef(heimdall.ErrInternal,
+			"failed to watch %s", w.file).CausedBy(err)
+	}
+
+	w.fw = fw
+	w.done = make(chan struct{})
+
+	w.wg.Add(1)
+
+	go w.watch()
+
+	return nil
+}
+
+func (w *Watcher) Stop() error {
+	if w.done == nil {
+		return nil
+	}
+
+	close(w.done)
+
+	err := w.fw.Close()
+
+	w.wg.Wait()
+
+	return err
+}
+
+func (w *Watcher) watch() {
+	defer w.wg.Done()
+
+	delay := time.NewTimer(ChangeDelay)
+	delay.Stop()
+
+	defer delay.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-delay.C:
+			w.changed()
+		case evt, ok := <-w.fw.Events:
+			if !ok {
+				return
+			}
+
+			if w.affectsFile(evt) {
+				delay.Reset(ChangeDelay)
+			}
+		case err, ok := <-w.fw.Errors:
+			if !ok {
+				return
+			}
+
+			w.l.Warn().Err(err).Str("_file", w.file).Msg("File watcher error")
+		}
+	}
+}
+
+func (w *Watcher) affectsFile(evt fsnotify.Event) bool {
+	if evt.Has(fsnotify.Chmod) && !evt.Has(fsnotify.Write) {
+		return false
+	}
+
+	// kubernetes updates mounted config maps and secrets by swapping the ..data symlink
+	return filepath.Clean(evt.Name) == w.file || filepath.Base(evt.Name) == "..data"
+}
+
+func (w *Watcher) changed() {
+	hash := fileHash(w.file)
+	if hash == nil || bytes.Equal(hash, w.hash) {
+		return
+	}
+
+	if err := w.onChange(); err != nil {
+		return
+	}
+
+	w.hash = hash
+}
+
+func fileHash(file string) []byte {
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+
+	hash := sha256.Sum256(contents)
+
+	return hash[:]
+}
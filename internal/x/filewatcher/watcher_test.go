@@ -0,0 +1,116 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package filewatcher
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/x/testsupport"
+)
+
+func TestWatcher(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	keyFile := filepath.Join(t.TempDir(), "keys.pem")
+	require.NoError(t, os.WriteFile(keyFile, []byte("foo"), 0o600))
+
+	var reloads atomic.Int32
+
+	watcher := New(keyFile, func() error {
+		reloads.Add(1)
+
+		return nil
+	}, log.Logger)
+
+	require.NoError(t, watcher.Start())
+
+	defer func() { require.NoError(t, watcher.Stop()) }()
+
+	// WHEN the contents do not change
+	require.NoError(t, os.WriteFile(keyFile, []byte("foo"), 0o600))
+	time.Sleep(2 * ChangeDelay)
+
+	// THEN
+	assert.Equal(t, int32(0), reloads.Load())
+
+	// WHEN the contents change
+	require.NoError(t, os.WriteFile(keyFile, []byte("bar"), 0o600))
+
+	// THEN
+	assert.Eventually(t, func() bool { return reloads.Load() == 1 }, 2*time.Second, 50*time.Millisecond)
+
+	// WHEN an unrelated file changes
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(keyFile), "other"), []byte("baz"), 0o600))
+	time.Sleep(2 * ChangeDelay)
+
+	// THEN
+	assert.Equal(t, int32(1), reloads.Load())
+}
+
+func TestWatcherWithFailingCallback(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("foo"), 0o600))
+
+	var calls atomic.Int32
+
+	watcher := New(file, func() error {
+		calls.Add(1)
+
+		return testsupport.ErrTestPurpose
+	}, log.Logger)
+
+	require.NoError(t, watcher.Start())
+
+	defer func() { require.NoError(t, watcher.Stop()) }()
+
+	// WHEN the contents change
+	require.NoError(t, os.WriteFile(file, []byte("bar"), 0o600))
+
+	// THEN
+	assert.Eventually(t, func() bool { return calls.Load() == 1 }, 2*time.Second, 50*time.Millisecond)
+
+	// WHEN the same contents are written again
+	require.NoError(t, os.WriteFile(file, []byte("bar"), 0o600))
+
+	// THEN the callback is called again as the previous call failed
+	assert.Eventually(t, func() bool { return calls.Load() == 2 }, 2*time.Second, 50*time.Millisecond)
+}
+
+func TestWatcherStopWithoutStart(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	watcher := New("foo.yaml", func() error { return nil }, log.Logger)
+
+	// WHEN
+	err := watcher.Stop()
+
+	// THEN
+	require.NoError(t, err)
+}
@@ -0,0 +1,117 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package httpx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+var ErrNoStubFound = errors.New("no stub found")
+
+// Stub describes a canned response, which is returned for outbound requests matching the
+// configured method and url instead of sending these requests to the actual server. An empty
+// method matches any method. If the url ends with a "*", it is used as a prefix.
+type Stub struct {
+	Method  string            `json:"method"  yaml:"method"`
+	URL     string            `json:"url"     yaml:"url"`
+	Status  int               `json:"status"  yaml:"status"`
+	Headers map[string]string `json:"headers" yaml:"headers"`
+	Body    string            `json:"body"    yaml:"body"`
+}
+
+func (s Stub) matches(req *http.Request) bool {
+	if len(s.Method) != 0 && !strings.EqualFold(s.Method, req.Method) {
+		return false
+	}
+
+	if prefix, found := strings.CutSuffix(s.URL, "*"); found {
+		return strings.HasPrefix(req.URL.String(), prefix)
+	}
+
+	return s.URL == req.URL.String()
+}
+
+func (s Stub) response(req *http.Request) *http.Response {
+	status := s.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	header := make(http.Header, len(s.Headers))
+	for k, v := range s.Headers {
+		header.Set(k, v)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(s.Body)),
+		ContentLength: int64(len(s.Body)),
+		Request:       req,
+	}
+}
+
+type stubsCtxKey struct{}
+
+type stubs struct {
+	entries []Stub
+	strict  bool
+}
+
+// WithStubs returns a copy of ctx with the given stubs associated. Outbound requests made with
+// that context by clients using a round tripper created with NewStubRoundTripper are answered
+// by the first matching stub. If strict is set, requests not matching any stub fail instead of
+// being sent to the actual server.
+func WithStubs(ctx context.Context, entries []Stub, strict bool) context.Context {
+	return context.WithValue(ctx, stubsCtxKey{}, &stubs{entries: entries, strict: strict})
+}
+
+type stubRoundTripper struct {
+	t http.RoundTripper
+}
+
+func NewStubRoundTripper(rt http.RoundTripper) http.RoundTripper {
+	return &stubRoundTripper{t: rt}
+}
+
+func (t *stubRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	registered, ok := req.Context().Value(stubsCtxKey{}).(*stubs)
+	if !ok {
+		return t.t.RoundTrip(req)
+	}
+
+	for _, stub := range registered.entries {
+		if stub.matches(req) {
+			return stub.response(req), nil
+		}
+	}
+
+	if registered.strict {
+		return nil, fmt.Errorf("%w for %s %s", ErrNoStubFound, req.Method, req.URL)
+	}
+
+	return t.t.RoundTrip(req)
+}
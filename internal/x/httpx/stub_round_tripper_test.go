@@ -0,0 +1,165 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package httpx
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dadrus/heimdall/internal/x"
+	"github.com/dadrus/heimdall/internal/x/httpx/mocks"
+)
+
+func TestStubRoundTripperRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc            string
+		method        string
+		url           string
+		configureCtx  func(ctx context.Context) context.Context
+		configureMock func(t *testing.T, rt *mocks.RoundTripperMock)
+		assert        func(t *testing.T, err error, resp *http.Response)
+	}{
+		{
+			uc:     "no stubs in the context",
+			method: http.MethodGet,
+			url:    "https://foo.bar/baz",
+			configureMock: func(t *testing.T, rt *mocks.RoundTripperMock) {
+				t.Helper()
+
+				rt.EXPECT().RoundTrip(mock.Anything).Return(&http.Response{StatusCode: http.StatusNoContent}, nil)
+			},
+			assert: func(t *testing.T, err error, resp *http.Response) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+			},
+		},
+		{
+			uc:     "exactly matching stub",
+			method: http.MethodPost,
+			url:    "https://foo.bar/baz",
+			configureCtx: func(ctx context.Context) context.Context {
+				return WithStubs(ctx, []Stub{
+					{Method: http.MethodGet, URL: "https://foo.bar/baz", Status: http.StatusForbidden},
+					{
+						Method:  "post",
+						URL:     "https://foo.bar/baz",
+						Status:  http.StatusCreated,
+						Headers: map[string]string{"X-Foo": "bar"},
+						Body:    "hello",
+					},
+				}, true)
+			},
+			assert: func(t *testing.T, err error, resp *http.Response) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusCreated, resp.StatusCode)
+				assert.Equal(t, "bar", resp.Header.Get("X-Foo"))
+
+				data, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, "hello", string(data))
+			},
+		},
+		{
+			uc:     "stub matching by url prefix and any method with default status",
+			method: http.MethodGet,
+			url:    "https://foo.bar/baz?foo=bar",
+			configureCtx: func(ctx context.Context) context.Context {
+				return WithStubs(ctx, []Stub{{URL: "https://foo.bar/*"}}, true)
+			},
+			assert: func(t *testing.T, err error, resp *http.Response) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			uc:     "no matching stub in strict mode",
+			method: http.MethodGet,
+			url:    "https://foo.bar/baz",
+			configureCtx: func(ctx context.Context) context.Context {
+				return WithStubs(ctx, []Stub{{URL: "https://bar.foo/*"}}, true)
+			},
+			assert: func(t *testing.T, err error, _ *http.Response) {
+				t.Helper()
+
+				require.Error(t, err)
+				require.ErrorIs(t, err, ErrNoStubFound)
+				assert.Contains(t, err.Error(), "GET https://foo.bar/baz")
+			},
+		},
+		{
+			uc:     "no matching stub in lenient mode",
+			method: http.MethodGet,
+			url:    "https://foo.bar/baz",
+			configureCtx: func(ctx context.Context) context.Context {
+				return WithStubs(ctx, []Stub{{URL: "https://bar.foo/*"}}, false)
+			},
+			configureMock: func(t *testing.T, rt *mocks.RoundTripperMock) {
+				t.Helper()
+
+				rt.EXPECT().RoundTrip(mock.Anything).Return(&http.Response{StatusCode: http.StatusAccepted}, nil)
+			},
+			assert: func(t *testing.T, err error, resp *http.Response) {
+				t.Helper()
+
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+			},
+		},
+	} {
+		t.Run(tc.uc, func(t *testing.T) {
+			// GIVEN
+			configureCtx := x.IfThenElse(tc.configureCtx != nil,
+				tc.configureCtx,
+				func(ctx context.Context) context.Context { return ctx })
+			configureMock := x.IfThenElse(tc.configureMock != nil,
+				tc.configureMock,
+				func(t *testing.T, _ *mocks.RoundTripperMock) { t.Helper() })
+
+			req, err := http.NewRequestWithContext(
+				configureCtx(context.Background()), tc.method, tc.url, nil)
+			require.NoError(t, err)
+
+			rt := mocks.NewRoundTripperMock(t)
+			configureMock(t, rt)
+
+			srt := NewStubRoundTripper(rt)
+
+			// WHEN
+			resp, err := srt.RoundTrip(req)
+			if resp != nil && resp.Body != nil {
+				defer resp.Body.Close()
+			}
+
+			// THEN
+			tc.assert(t, err, resp)
+		})
+	}
+}
@@ -36,7 +36,7 @@ func NewTraceRoundTripper(rt http.RoundTripper) http.RoundTripper {
 
 func (t *traceRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 	logger := zerolog.Ctx(req.Context())
-	if logger.GetLevel() != zerolog.TraceLevel {
+	if logger.GetLevel() != zerolog.TraceLevel || zerolog.GlobalLevel() != zerolog.TraceLevel {
 		return t.t.RoundTrip(req)
 	}
 
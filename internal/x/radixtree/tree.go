@@ -0,0 +1,189 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package radixtree implements a compressed prefix tree, which allows finding all values
+// stored under keys being a prefix of a given string.
+package radixtree
+
+import (
+	"slices"
+	"strings"
+)
+
+// Tree is a radix tree holding values of type V. Multiple values can be stored under the same key.
+// Such values are kept in the order defined by the comparison function given to New. If no
+// comparison function is given, the insertion order is preserved.
+type Tree[V any] struct {
+	root node[V]
+	cmp  func(a, b V) int
+}
+
+type node[V any] struct {
+	prefix   string
+	values   []V
+	children []*node[V]
+}
+
+func New[V any](cmp func(a, b V) int) *Tree[V] {
+	return &Tree[V]{cmp: cmp}
+}
+
+// Add stores the given value under the given key.
+func (t *Tree[V]) Add(key string, value V) {
+	current := &t.root
+
+	for len(key) != 0 {
+		idx, child := current.child(key[0])
+		if child == nil {
+			current.children = append(current.children, &node[V]{prefix: key})
+			current = current.children[len(current.children)-1]
+
+			break
+		}
+
+		common := commonPrefixLength(key, child.prefix)
+		if common < len(child.prefix) {
+			split := &node[V]{prefix: child.prefix[:common], children: []*node[V]{child}}
+			child.prefix = child.prefix[common:]
+			current.children[idx] = split
+			child = split
+		}
+
+		key = key[common:]
+		current = child
+	}
+
+	current.addValue(value, t.cmp)
+}
+
+// Delete removes all values stored under the given key, for which matches returns true.
+// It returns true if at least one value has been removed.
+func (t *Tree[V]) Delete(key string, matches func(V) bool) bool {
+	return t.root.delete(key, matches)
+}
+
+// Find returns the values stored under all keys, which are a prefix of the given key. The
+// values stored under longer keys are returned first.
+func (t *Tree[V]) Find(key string) []V {
+	var (
+		matched []*node[V]
+		values  []V
+	)
+
+	current := &t.root
+
+	for {
+		if len(current.values) != 0 {
+			matched = append(matched, current)
+		}
+
+		if len(key) == 0 {
+			break
+		}
+
+		_, child := current.child(key[0])
+		if child == nil || !strings.HasPrefix(key, child.prefix) {
+			break
+		}
+
+		key = key[len(child.prefix):]
+		current = child
+	}
+
+	for idx := len(matched) - 1; idx >= 0; idx-- {
+		values = append(values, matched[idx].values...)
+	}
+
+	return values
+}
+
+func (n *node[V]) child(first byte) (int, *node[V]) {
+	for idx, child := range n.children {
+		if child.prefix[0] == first {
+			return idx, child
+		}
+	}
+
+	return -1, nil
+}
+
+func (n *node[V]) addValue(value V, cmp func(a, b V) int) {
+	idx := -1
+	if cmp != nil {
+		idx = slices.IndexFunc(n.values, func(existing V) bool { return cmp(existing, value) > 0 })
+	}
+
+	if idx == -1 {
+		n.values = append(n.values, value)
+	} else {
+		n.values = slices.Insert(n.values, idx, value)
+	}
+}
+
+func (n *node[V]) delete(key string, matches func(V) bool) bool {
+	if len(key) == 0 {
+		remaining := slices.DeleteFunc(n.values, matches)
+		deleted := len(remaining) != len(n.values)
+
+		// avoid memory leaks by not keeping references to the removed values
+		clear(n.values[len(remaining):])
+		n.values = remaining
+
+		return deleted
+	}
+
+	idx, child := n.child(key[0])
+	if child == nil || !strings.HasPrefix(key, child.prefix) {
+		return false
+	}
+
+	if !child.delete(key[len(child.prefix):], matches) {
+		return false
+	}
+
+	n.compact(idx)
+
+	return true
+}
+
+// compact removes the child at the given index if it became empty, or merges it
+// with its only child, if it does not hold any values on its own.
+func (n *node[V]) compact(idx int) {
+	child := n.children[idx]
+
+	switch {
+	case len(child.values) != 0:
+		return
+	case len(child.children) == 0:
+		n.children = slices.Delete(n.children, idx, idx+1)
+	case len(child.children) == 1:
+		merged := child.children[0]
+		merged.prefix = child.prefix + merged.prefix
+		n.children[idx] = merged
+	}
+}
+
+func commonPrefixLength(first, second string) int {
+	length := min(len(first), len(second))
+
+	for idx := 0; idx < length; idx++ {
+		if first[idx] != second[idx] {
+			return idx
+		}
+	}
+
+	return length
+}
@@ -0,0 +1,182 @@
+// Copyright 2023 Dimitrij Drus <dadrus@gmx.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package radixtree
+
+import (
+	"cmp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreeFind(t *testing.T) {
+	t.Parallel()
+
+	tree := New[string](nil)
+	tree.Add("http://foo.bar/", "1")
+	tree.Add("http://foo.bar/baz", "2")
+	tree.Add("http://foo.bar/baz/", "3")
+	tree.Add("http://foo.bar/bar", "4")
+	tree.Add("http://foo.bar/baz", "5")
+	tree.Add("http://", "6")
+	tree.Add("", "7")
+
+	for _, tc := range []struct {
+		uc       string
+		key      string
+		expected []string
+	}{
+		{uc: "exact match with all prefixes", key: "http://foo.bar/baz", expected: []string{"2", "5", "1", "6", "7"}},
+		{uc: "longer key", key: "http://foo.bar/baz/qux", expected: []string{"3", "2", "5", "1", "6", "7"}},
+		{uc: "key diverging within a node prefix", key: "http://foo.baz/", expected: []string{"6", "7"}},
+		{uc: "key shorter than a node prefix", key: "http://foo", expected: []string{"6", "7"}},
+		{uc: "key without matching prefixes", key: "https://foo.bar/baz", expected: []string{"7"}},
+		{uc: "empty key", key: "", expected: []string{"7"}},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// WHEN
+			values := tree.Find(tc.key)
+
+			// THEN
+			assert.Equal(t, tc.expected, values)
+		})
+	}
+}
+
+func TestTreeFindWithValuesOrder(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	tree := New[string](func(a, b string) int { return cmp.Compare(a, b) })
+	tree.Add("foo", "c")
+	tree.Add("foo", "a")
+	tree.Add("foo", "b")
+	tree.Add("fo", "d")
+
+	// WHEN
+	values := tree.Find("foobar")
+
+	// THEN
+	assert.Equal(t, []string{"a", "b", "c", "d"}, values)
+}
+
+func TestTreeDelete(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		uc      string
+		key     string
+		matches func(string) bool
+		deleted bool
+		assert  func(t *testing.T, tree *Tree[string])
+	}{
+		{
+			uc:      "not existing key",
+			key:     "http://foo.bar/qux",
+			matches: func(string) bool { return true },
+			assert: func(t *testing.T, tree *Tree[string]) {
+				t.Helper()
+
+				assert.Equal(t, []string{"3", "2", "1"}, tree.Find("http://foo.bar/baz/qux"))
+			},
+		},
+		{
+			uc:      "not matching value",
+			key:     "http://foo.bar/baz",
+			matches: func(value string) bool { return value == "1" },
+			assert: func(t *testing.T, tree *Tree[string]) {
+				t.Helper()
+
+				assert.Equal(t, []string{"3", "2", "1"}, tree.Find("http://foo.bar/baz/qux"))
+			},
+		},
+		{
+			uc:      "leaf node value",
+			key:     "http://foo.bar/baz/qux",
+			matches: func(value string) bool { return value == "3" },
+			deleted: true,
+			assert: func(t *testing.T, tree *Tree[string]) {
+				t.Helper()
+
+				assert.Equal(t, []string{"2", "1"}, tree.Find("http://foo.bar/baz/qux"))
+				assert.Len(t, tree.root.children[0].children, 1)
+				assert.Empty(t, tree.root.children[0].children[0].children)
+			},
+		},
+		{
+			uc:      "inner node value",
+			key:     "http://foo.bar/baz",
+			matches: func(value string) bool { return value == "2" },
+			deleted: true,
+			assert: func(t *testing.T, tree *Tree[string]) {
+				t.Helper()
+
+				assert.Equal(t, []string{"3", "1"}, tree.Find("http://foo.bar/baz/qux"))
+				assert.Equal(t, "baz/qux", tree.root.children[0].children[0].prefix)
+			},
+		},
+		{
+			uc:      "all values",
+			key:     "http://foo.bar/",
+			matches: func(string) bool { return true },
+			deleted: true,
+			assert: func(t *testing.T, tree *Tree[string]) {
+				t.Helper()
+
+				assert.Equal(t, []string{"3", "2"}, tree.Find("http://foo.bar/baz/qux"))
+				assert.Equal(t, "http://foo.bar/baz", tree.root.children[0].prefix)
+			},
+		},
+	} {
+		t.Run("case="+tc.uc, func(t *testing.T) {
+			// GIVEN
+			tree := New[string](nil)
+			tree.Add("http://foo.bar/", "1")
+			tree.Add("http://foo.bar/baz", "2")
+			tree.Add("http://foo.bar/baz/qux", "3")
+
+			// WHEN
+			deleted := tree.Delete(tc.key, tc.matches)
+
+			// THEN
+			assert.Equal(t, tc.deleted, deleted)
+			tc.assert(t, tree)
+		})
+	}
+}
+
+func TestTreeDeleteAll(t *testing.T) {
+	t.Parallel()
+
+	// GIVEN
+	keys := []string{"foo", "foobar", "foobaz", "bar", "barfoo", ""}
+	tree := New[string](nil)
+
+	for _, key := range keys {
+		tree.Add(key, strings.ToUpper(key))
+	}
+
+	// WHEN
+	for _, key := range keys {
+		assert.True(t, tree.Delete(key, func(string) bool { return true }))
+	}
+
+	// THEN
+	assert.Empty(t, tree.root.children)
+	assert.Empty(t, tree.root.values)
+}
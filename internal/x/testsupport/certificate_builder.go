@@ -150,6 +150,18 @@ func WithURIs(uris []*url.URL) CertificateBuilderOption {
 	}
 }
 
+func WithCRLDistributionPoints(urls []string) CertificateBuilderOption {
+	return func(builder *CertificateBuilder) {
+		builder.tmpl.CRLDistributionPoints = urls
+	}
+}
+
+func WithOCSPServers(urls []string) CertificateBuilderOption {
+	return func(builder *CertificateBuilder) {
+		builder.tmpl.OCSPServer = urls
+	}
+}
+
 func WithGeneratedSubjectKeyID() CertificateBuilderOption {
 	return func(builder *CertificateBuilder) {
 		builder.generateKeyIdentifier = true